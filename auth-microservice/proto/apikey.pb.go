@@ -0,0 +1,540 @@
+// กำหนด version ของ Protocol Buffers ที่ใช้
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v6.31.1
+// source: proto/apikey.proto
+
+package proto
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// ข้อมูล API key (ไม่มี key จริง)
+type APIKey struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=userId,proto3" json:"userId,omitempty"` // เจ้าของ key
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Prefix        string                 `protobuf:"bytes,4,opt,name=prefix,proto3" json:"prefix,omitempty"`        // ส่วนต้นของ key ใช้จำแนก key
+	Scopes        []string               `protobuf:"bytes,5,rep,name=scopes,proto3" json:"scopes,omitempty"`        // เช่น "users:read", "groups:write"
+	RateLimit     int32                  `protobuf:"varint,6,opt,name=rateLimit,proto3" json:"rateLimit,omitempty"` // จำนวน request สูงสุดต่อนาที
+	ExpiresAt     string                 `protobuf:"bytes,7,opt,name=expiresAt,proto3" json:"expiresAt,omitempty"`
+	LastUsedAt    string                 `protobuf:"bytes,8,opt,name=lastUsedAt,proto3" json:"lastUsedAt,omitempty"` // ค่าว่าง = ยังไม่เคยใช้
+	RevokedAt     string                 `protobuf:"bytes,9,opt,name=revokedAt,proto3" json:"revokedAt,omitempty"`   // ค่าว่าง = ยังใช้งานได้
+	CreatedAt     string                 `protobuf:"bytes,10,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *APIKey) Reset() {
+	*x = APIKey{}
+	mi := &file_proto_apikey_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *APIKey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*APIKey) ProtoMessage() {}
+
+func (x *APIKey) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_apikey_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use APIKey.ProtoReflect.Descriptor instead.
+func (*APIKey) Descriptor() ([]byte, []int) {
+	return file_proto_apikey_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *APIKey) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *APIKey) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *APIKey) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *APIKey) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *APIKey) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+func (x *APIKey) GetRateLimit() int32 {
+	if x != nil {
+		return x.RateLimit
+	}
+	return 0
+}
+
+func (x *APIKey) GetExpiresAt() string {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return ""
+}
+
+func (x *APIKey) GetLastUsedAt() string {
+	if x != nil {
+		return x.LastUsedAt
+	}
+	return ""
+}
+
+func (x *APIKey) GetRevokedAt() string {
+	if x != nil {
+		return x.RevokedAt
+	}
+	return ""
+}
+
+func (x *APIKey) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+// ข้อมูลสำหรับสร้าง API key
+type CreateAPIKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Scopes        []string               `protobuf:"bytes,2,rep,name=scopes,proto3" json:"scopes,omitempty"`        // ต้องมีอย่างน้อย 1 scope
+	ExpiresAt     string                 `protobuf:"bytes,3,opt,name=expiresAt,proto3" json:"expiresAt,omitempty"`  // RFC3339 (ค่าว่าง = 90 วัน, สูงสุด 1 ปี)
+	RateLimit     int32                  `protobuf:"varint,4,opt,name=rateLimit,proto3" json:"rateLimit,omitempty"` // ค่าว่าง = 60 request ต่อนาที
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateAPIKeyRequest) Reset() {
+	*x = CreateAPIKeyRequest{}
+	mi := &file_proto_apikey_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateAPIKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateAPIKeyRequest) ProtoMessage() {}
+
+func (x *CreateAPIKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_apikey_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateAPIKeyRequest.ProtoReflect.Descriptor instead.
+func (*CreateAPIKeyRequest) Descriptor() ([]byte, []int) {
+	return file_proto_apikey_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateAPIKeyRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateAPIKeyRequest) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+func (x *CreateAPIKeyRequest) GetExpiresAt() string {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return ""
+}
+
+func (x *CreateAPIKeyRequest) GetRateLimit() int32 {
+	if x != nil {
+		return x.RateLimit
+	}
+	return 0
+}
+
+type CreateAPIKeyReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey        *APIKey                `protobuf:"bytes,1,opt,name=apiKey,proto3" json:"apiKey,omitempty"`
+	Key           string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"` // key จริง แสดงครั้งเดียว ให้เก็บไว้ทันที
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateAPIKeyReply) Reset() {
+	*x = CreateAPIKeyReply{}
+	mi := &file_proto_apikey_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateAPIKeyReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateAPIKeyReply) ProtoMessage() {}
+
+func (x *CreateAPIKeyReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_apikey_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateAPIKeyReply.ProtoReflect.Descriptor instead.
+func (*CreateAPIKeyReply) Descriptor() ([]byte, []int) {
+	return file_proto_apikey_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateAPIKeyReply) GetApiKey() *APIKey {
+	if x != nil {
+		return x.ApiKey
+	}
+	return nil
+}
+
+func (x *CreateAPIKeyReply) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+type ListAPIKeysRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=userId,proto3" json:"userId,omitempty"` // ค่าว่าง = key ของตัวเอง
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAPIKeysRequest) Reset() {
+	*x = ListAPIKeysRequest{}
+	mi := &file_proto_apikey_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAPIKeysRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAPIKeysRequest) ProtoMessage() {}
+
+func (x *ListAPIKeysRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_apikey_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAPIKeysRequest.ProtoReflect.Descriptor instead.
+func (*ListAPIKeysRequest) Descriptor() ([]byte, []int) {
+	return file_proto_apikey_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListAPIKeysRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type ListAPIKeysReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKeys       []*APIKey              `protobuf:"bytes,1,rep,name=apiKeys,proto3" json:"apiKeys,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAPIKeysReply) Reset() {
+	*x = ListAPIKeysReply{}
+	mi := &file_proto_apikey_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAPIKeysReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAPIKeysReply) ProtoMessage() {}
+
+func (x *ListAPIKeysReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_apikey_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAPIKeysReply.ProtoReflect.Descriptor instead.
+func (*ListAPIKeysReply) Descriptor() ([]byte, []int) {
+	return file_proto_apikey_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListAPIKeysReply) GetApiKeys() []*APIKey {
+	if x != nil {
+		return x.ApiKeys
+	}
+	return nil
+}
+
+type RevokeAPIKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeAPIKeyRequest) Reset() {
+	*x = RevokeAPIKeyRequest{}
+	mi := &file_proto_apikey_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeAPIKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeAPIKeyRequest) ProtoMessage() {}
+
+func (x *RevokeAPIKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_apikey_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeAPIKeyRequest.ProtoReflect.Descriptor instead.
+func (*RevokeAPIKeyRequest) Descriptor() ([]byte, []int) {
+	return file_proto_apikey_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *RevokeAPIKeyRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type RevokeAPIKeyReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeAPIKeyReply) Reset() {
+	*x = RevokeAPIKeyReply{}
+	mi := &file_proto_apikey_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeAPIKeyReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeAPIKeyReply) ProtoMessage() {}
+
+func (x *RevokeAPIKeyReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_apikey_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeAPIKeyReply.ProtoReflect.Descriptor instead.
+func (*RevokeAPIKeyReply) Descriptor() ([]byte, []int) {
+	return file_proto_apikey_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *RevokeAPIKeyReply) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+var File_proto_apikey_proto protoreflect.FileDescriptor
+
+const file_proto_apikey_proto_rawDesc = "" +
+	"\n" +
+	"\x12proto/apikey.proto\"\x8c\x02\n" +
+	"\x06APIKey\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06userId\x18\x02 \x01(\tR\x06userId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x16\n" +
+	"\x06prefix\x18\x04 \x01(\tR\x06prefix\x12\x16\n" +
+	"\x06scopes\x18\x05 \x03(\tR\x06scopes\x12\x1c\n" +
+	"\trateLimit\x18\x06 \x01(\x05R\trateLimit\x12\x1c\n" +
+	"\texpiresAt\x18\a \x01(\tR\texpiresAt\x12\x1e\n" +
+	"\n" +
+	"lastUsedAt\x18\b \x01(\tR\n" +
+	"lastUsedAt\x12\x1c\n" +
+	"\trevokedAt\x18\t \x01(\tR\trevokedAt\x12\x1c\n" +
+	"\tcreatedAt\x18\n" +
+	" \x01(\tR\tcreatedAt\"}\n" +
+	"\x13CreateAPIKeyRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06scopes\x18\x02 \x03(\tR\x06scopes\x12\x1c\n" +
+	"\texpiresAt\x18\x03 \x01(\tR\texpiresAt\x12\x1c\n" +
+	"\trateLimit\x18\x04 \x01(\x05R\trateLimit\"F\n" +
+	"\x11CreateAPIKeyReply\x12\x1f\n" +
+	"\x06apiKey\x18\x01 \x01(\v2\a.APIKeyR\x06apiKey\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\tR\x03key\",\n" +
+	"\x12ListAPIKeysRequest\x12\x16\n" +
+	"\x06userId\x18\x01 \x01(\tR\x06userId\"5\n" +
+	"\x10ListAPIKeysReply\x12!\n" +
+	"\aapiKeys\x18\x01 \x03(\v2\a.APIKeyR\aapiKeys\"%\n" +
+	"\x13RevokeAPIKeyRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"-\n" +
+	"\x11RevokeAPIKeyReply\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage2\xc0\x01\n" +
+	"\rAPIKeyService\x12:\n" +
+	"\fCreateAPIKey\x12\x14.CreateAPIKeyRequest\x1a\x12.CreateAPIKeyReply\"\x00\x127\n" +
+	"\vListAPIKeys\x12\x13.ListAPIKeysRequest\x1a\x11.ListAPIKeysReply\"\x00\x12:\n" +
+	"\fRevokeAPIKey\x12\x14.RevokeAPIKeyRequest\x1a\x12.RevokeAPIKeyReply\"\x00B\x19Z\x17auth-microservice/protob\x06proto3"
+
+var (
+	file_proto_apikey_proto_rawDescOnce sync.Once
+	file_proto_apikey_proto_rawDescData []byte
+)
+
+func file_proto_apikey_proto_rawDescGZIP() []byte {
+	file_proto_apikey_proto_rawDescOnce.Do(func() {
+		file_proto_apikey_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_apikey_proto_rawDesc), len(file_proto_apikey_proto_rawDesc)))
+	})
+	return file_proto_apikey_proto_rawDescData
+}
+
+var file_proto_apikey_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_proto_apikey_proto_goTypes = []any{
+	(*APIKey)(nil),              // 0: APIKey
+	(*CreateAPIKeyRequest)(nil), // 1: CreateAPIKeyRequest
+	(*CreateAPIKeyReply)(nil),   // 2: CreateAPIKeyReply
+	(*ListAPIKeysRequest)(nil),  // 3: ListAPIKeysRequest
+	(*ListAPIKeysReply)(nil),    // 4: ListAPIKeysReply
+	(*RevokeAPIKeyRequest)(nil), // 5: RevokeAPIKeyRequest
+	(*RevokeAPIKeyReply)(nil),   // 6: RevokeAPIKeyReply
+}
+var file_proto_apikey_proto_depIdxs = []int32{
+	0, // 0: CreateAPIKeyReply.apiKey:type_name -> APIKey
+	0, // 1: ListAPIKeysReply.apiKeys:type_name -> APIKey
+	1, // 2: APIKeyService.CreateAPIKey:input_type -> CreateAPIKeyRequest
+	3, // 3: APIKeyService.ListAPIKeys:input_type -> ListAPIKeysRequest
+	5, // 4: APIKeyService.RevokeAPIKey:input_type -> RevokeAPIKeyRequest
+	2, // 5: APIKeyService.CreateAPIKey:output_type -> CreateAPIKeyReply
+	4, // 6: APIKeyService.ListAPIKeys:output_type -> ListAPIKeysReply
+	6, // 7: APIKeyService.RevokeAPIKey:output_type -> RevokeAPIKeyReply
+	5, // [5:8] is the sub-list for method output_type
+	2, // [2:5] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_proto_apikey_proto_init() }
+func file_proto_apikey_proto_init() {
+	if File_proto_apikey_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_apikey_proto_rawDesc), len(file_proto_apikey_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_apikey_proto_goTypes,
+		DependencyIndexes: file_proto_apikey_proto_depIdxs,
+		MessageInfos:      file_proto_apikey_proto_msgTypes,
+	}.Build()
+	File_proto_apikey_proto = out.File
+	file_proto_apikey_proto_goTypes = nil
+	file_proto_apikey_proto_depIdxs = nil
+}
@@ -0,0 +1,335 @@
+// กำหนด version ของ Protocol Buffers ที่ใช้
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v6.31.1
+// source: proto/audit.proto
+
+package proto
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// เหตุการณ์หนึ่งรายการใน audit log
+type AuditEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	TenantId      string                 `protobuf:"bytes,2,opt,name=tenantId,proto3" json:"tenantId,omitempty"`
+	Action        string                 `protobuf:"bytes,3,opt,name=action,proto3" json:"action,omitempty"`             // เช่น "user.deleted"
+	ActorEmail    string                 `protobuf:"bytes,4,opt,name=actorEmail,proto3" json:"actorEmail,omitempty"`     // ผู้ที่ทำรายการ
+	SubjectId     string                 `protobuf:"bytes,5,opt,name=subjectId,proto3" json:"subjectId,omitempty"`       // ID ของผู้ใช้ที่ถูกกระทำ
+	SubjectEmail  string                 `protobuf:"bytes,6,opt,name=subjectEmail,proto3" json:"subjectEmail,omitempty"` // อีเมลของผู้ใช้ที่ถูกกระทำ
+	Details       string                 `protobuf:"bytes,7,opt,name=details,proto3" json:"details,omitempty"`           // ข้อมูลเพิ่มเติมแบบ JSON (ว่าง = ไม่มี)
+	CreatedAt     string                 `protobuf:"bytes,8,opt,name=createdAt,proto3" json:"createdAt,omitempty"`       // RFC3339
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuditEvent) Reset() {
+	*x = AuditEvent{}
+	mi := &file_proto_audit_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuditEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuditEvent) ProtoMessage() {}
+
+func (x *AuditEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_audit_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuditEvent.ProtoReflect.Descriptor instead.
+func (*AuditEvent) Descriptor() ([]byte, []int) {
+	return file_proto_audit_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *AuditEvent) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetActorEmail() string {
+	if x != nil {
+		return x.ActorEmail
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetSubjectId() string {
+	if x != nil {
+		return x.SubjectId
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetSubjectEmail() string {
+	if x != nil {
+		return x.SubjectEmail
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetDetails() string {
+	if x != nil {
+		return x.Details
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+type ListAuditEventsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AfterId       string                 `protobuf:"bytes,1,opt,name=afterId,proto3" json:"afterId,omitempty"`     // อ่านต่อหลังเหตุการณ์นี้ (ว่าง = ตั้งแต่ต้น หรือล่าสุดถ้า latest)
+	Action        string                 `protobuf:"bytes,2,opt,name=action,proto3" json:"action,omitempty"`       // กรองตามชื่อเหตุการณ์ (ว่าง = ทั้งหมด)
+	SubjectId     string                 `protobuf:"bytes,3,opt,name=subjectId,proto3" json:"subjectId,omitempty"` // กรองตามผู้ใช้ที่ถูกกระทำ (ว่าง = ทั้งหมด)
+	Limit         int32                  `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`        // จำนวนสูงสุด (ค่าเริ่มต้น 10 สูงสุด 100)
+	Latest        bool                   `protobuf:"varint,5,opt,name=latest,proto3" json:"latest,omitempty"`      // true = เฉพาะ limit รายการล่าสุด
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAuditEventsRequest) Reset() {
+	*x = ListAuditEventsRequest{}
+	mi := &file_proto_audit_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAuditEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAuditEventsRequest) ProtoMessage() {}
+
+func (x *ListAuditEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_audit_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAuditEventsRequest.ProtoReflect.Descriptor instead.
+func (*ListAuditEventsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_audit_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ListAuditEventsRequest) GetAfterId() string {
+	if x != nil {
+		return x.AfterId
+	}
+	return ""
+}
+
+func (x *ListAuditEventsRequest) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *ListAuditEventsRequest) GetSubjectId() string {
+	if x != nil {
+		return x.SubjectId
+	}
+	return ""
+}
+
+func (x *ListAuditEventsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListAuditEventsRequest) GetLatest() bool {
+	if x != nil {
+		return x.Latest
+	}
+	return false
+}
+
+type ListAuditEventsReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Events        []*AuditEvent          `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	LastId        string                 `protobuf:"bytes,2,opt,name=lastId,proto3" json:"lastId,omitempty"` // ID ของเหตุการณ์สุดท้าย (ส่งเป็น afterId ครั้งถัดไป ว่าง = ไม่มีเหตุการณ์)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAuditEventsReply) Reset() {
+	*x = ListAuditEventsReply{}
+	mi := &file_proto_audit_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAuditEventsReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAuditEventsReply) ProtoMessage() {}
+
+func (x *ListAuditEventsReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_audit_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAuditEventsReply.ProtoReflect.Descriptor instead.
+func (*ListAuditEventsReply) Descriptor() ([]byte, []int) {
+	return file_proto_audit_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListAuditEventsReply) GetEvents() []*AuditEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+func (x *ListAuditEventsReply) GetLastId() string {
+	if x != nil {
+		return x.LastId
+	}
+	return ""
+}
+
+var File_proto_audit_proto protoreflect.FileDescriptor
+
+const file_proto_audit_proto_rawDesc = "" +
+	"\n" +
+	"\x11proto/audit.proto\"\xea\x01\n" +
+	"\n" +
+	"AuditEvent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1a\n" +
+	"\btenantId\x18\x02 \x01(\tR\btenantId\x12\x16\n" +
+	"\x06action\x18\x03 \x01(\tR\x06action\x12\x1e\n" +
+	"\n" +
+	"actorEmail\x18\x04 \x01(\tR\n" +
+	"actorEmail\x12\x1c\n" +
+	"\tsubjectId\x18\x05 \x01(\tR\tsubjectId\x12\"\n" +
+	"\fsubjectEmail\x18\x06 \x01(\tR\fsubjectEmail\x12\x18\n" +
+	"\adetails\x18\a \x01(\tR\adetails\x12\x1c\n" +
+	"\tcreatedAt\x18\b \x01(\tR\tcreatedAt\"\x96\x01\n" +
+	"\x16ListAuditEventsRequest\x12\x18\n" +
+	"\aafterId\x18\x01 \x01(\tR\aafterId\x12\x16\n" +
+	"\x06action\x18\x02 \x01(\tR\x06action\x12\x1c\n" +
+	"\tsubjectId\x18\x03 \x01(\tR\tsubjectId\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06latest\x18\x05 \x01(\bR\x06latest\"S\n" +
+	"\x14ListAuditEventsReply\x12#\n" +
+	"\x06events\x18\x01 \x03(\v2\v.AuditEventR\x06events\x12\x16\n" +
+	"\x06lastId\x18\x02 \x01(\tR\x06lastId2S\n" +
+	"\fAuditService\x12C\n" +
+	"\x0fListAuditEvents\x12\x17.ListAuditEventsRequest\x1a\x15.ListAuditEventsReply\"\x00B\x19Z\x17auth-microservice/protob\x06proto3"
+
+var (
+	file_proto_audit_proto_rawDescOnce sync.Once
+	file_proto_audit_proto_rawDescData []byte
+)
+
+func file_proto_audit_proto_rawDescGZIP() []byte {
+	file_proto_audit_proto_rawDescOnce.Do(func() {
+		file_proto_audit_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_audit_proto_rawDesc), len(file_proto_audit_proto_rawDesc)))
+	})
+	return file_proto_audit_proto_rawDescData
+}
+
+var file_proto_audit_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_proto_audit_proto_goTypes = []any{
+	(*AuditEvent)(nil),             // 0: AuditEvent
+	(*ListAuditEventsRequest)(nil), // 1: ListAuditEventsRequest
+	(*ListAuditEventsReply)(nil),   // 2: ListAuditEventsReply
+}
+var file_proto_audit_proto_depIdxs = []int32{
+	0, // 0: ListAuditEventsReply.events:type_name -> AuditEvent
+	1, // 1: AuditService.ListAuditEvents:input_type -> ListAuditEventsRequest
+	2, // 2: AuditService.ListAuditEvents:output_type -> ListAuditEventsReply
+	2, // [2:3] is the sub-list for method output_type
+	1, // [1:2] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_proto_audit_proto_init() }
+func file_proto_audit_proto_init() {
+	if File_proto_audit_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_audit_proto_rawDesc), len(file_proto_audit_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_audit_proto_goTypes,
+		DependencyIndexes: file_proto_audit_proto_depIdxs,
+		MessageInfos:      file_proto_audit_proto_msgTypes,
+	}.Build()
+	File_proto_audit_proto = out.File
+	file_proto_audit_proto_goTypes = nil
+	file_proto_audit_proto_depIdxs = nil
+}
@@ -26,14 +26,15 @@ const (
 // ข้อมูลสำหรับคำขอลงทะเบียนผู้ใช้ใหม่
 type RegisterRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`         // อีเมลผู้ใช้
-	Password      string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`   // รหัสผ่าน
-	Username      string                 `protobuf:"bytes,3,opt,name=username,proto3" json:"username,omitempty"`   // ชื่อผู้ใช้
-	CreatedAt     string                 `protobuf:"bytes,4,opt,name=createdAt,proto3" json:"createdAt,omitempty"` // วันที่สร้างบัญชี (เก็บโดยระบบ)
-	UpdatedAt     string                 `protobuf:"bytes,5,opt,name=updatedAt,proto3" json:"updatedAt,omitempty"` // วันที่อัปเดตล่าสุด (เก็บโดยระบบ)
-	Deleted       bool                   `protobuf:"varint,6,opt,name=deleted,proto3" json:"deleted,omitempty"`    // สถานะลบ (soft delete)
-	DeletedAt     string                 `protobuf:"bytes,7,opt,name=deletedAt,proto3" json:"deletedAt,omitempty"` // วันที่ลบ (soft delete)
-	Role          string                 `protobuf:"bytes,8,opt,name=role,proto3" json:"role,omitempty"`           // บทบาท admin หรือ user
+	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`             // อีเมลผู้ใช้
+	Password      string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`       // รหัสผ่าน
+	Username      string                 `protobuf:"bytes,3,opt,name=username,proto3" json:"username,omitempty"`       // ชื่อผู้ใช้
+	CreatedAt     string                 `protobuf:"bytes,4,opt,name=createdAt,proto3" json:"createdAt,omitempty"`     // วันที่สร้างบัญชี (เก็บโดยระบบ)
+	UpdatedAt     string                 `protobuf:"bytes,5,opt,name=updatedAt,proto3" json:"updatedAt,omitempty"`     // วันที่อัปเดตล่าสุด (เก็บโดยระบบ)
+	Deleted       bool                   `protobuf:"varint,6,opt,name=deleted,proto3" json:"deleted,omitempty"`        // สถานะลบ (soft delete)
+	DeletedAt     string                 `protobuf:"bytes,7,opt,name=deletedAt,proto3" json:"deletedAt,omitempty"`     // วันที่ลบ (soft delete)
+	Role          string                 `protobuf:"bytes,8,opt,name=role,proto3" json:"role,omitempty"`               // ว่างหรือ "user" เท่านั้น (role อื่นกำหนดโดย admin)
+	InviteToken   string                 `protobuf:"bytes,9,opt,name=inviteToken,proto3" json:"inviteToken,omitempty"` // token คำเชิญ (ถ้ามี) สมัครแล้วเข้ากลุ่มตามคำเชิญทันที อีเมลต้องตรงกับคำเชิญ
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -124,6 +125,13 @@ func (x *RegisterRequest) GetRole() string {
 	return ""
 }
 
+func (x *RegisterRequest) GetInviteToken() string {
+	if x != nil {
+		return x.InviteToken
+	}
+	return ""
+}
+
 // ข้อมูลตอบกลับเมื่อสมัครสมาชิกสำเร็จ
 type RegisterReply struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -238,19 +246,197 @@ func (x *LoginRequest) GetPassword() string {
 	return ""
 }
 
-// ข้อมูลตอบกลับเมื่อเข้าสู่ระบบสำเร็จ
-type LoginReply struct {
+// ข้อมูลสำหรับขอรหัสเข้าสู่ระบบทางอีเมล
+type RequestLoginCodeRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`       // อีเมลผู้ใช้
-	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"` // ชื่อผู้ใช้
-	Token         string                 `protobuf:"bytes,3,opt,name=token,proto3" json:"token,omitempty"`       // JWT token สำหรับใช้ยืนยันตัวตนในระบบ
+	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"` // อีเมลผู้ใช้
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestLoginCodeRequest) Reset() {
+	*x = RequestLoginCodeRequest{}
+	mi := &file_proto_auth_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestLoginCodeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestLoginCodeRequest) ProtoMessage() {}
+
+func (x *RequestLoginCodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestLoginCodeRequest.ProtoReflect.Descriptor instead.
+func (*RequestLoginCodeRequest) Descriptor() ([]byte, []int) {
+	return file_proto_auth_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *RequestLoginCodeRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+// reply เหมือนกันไม่ว่าจะมีผู้ใช้ที่มีอีเมลนี้หรือไม่
+type RequestLoginCodeReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	DeviceToken   string                 `protobuf:"bytes,2,opt,name=deviceToken,proto3" json:"deviceToken,omitempty"` // เก็บไว้ในอุปกรณ์ที่ขอ แล้วส่งใน VerifyLoginCode (รหัสและลิงก์ใช้ได้เฉพาะอุปกรณ์นี้)
+	ExpiresIn     int64                  `protobuf:"varint,3,opt,name=expiresIn,proto3" json:"expiresIn,omitempty"`    // อายุของรหัสและลิงก์ (วินาที)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestLoginCodeReply) Reset() {
+	*x = RequestLoginCodeReply{}
+	mi := &file_proto_auth_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestLoginCodeReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestLoginCodeReply) ProtoMessage() {}
+
+func (x *RequestLoginCodeReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestLoginCodeReply.ProtoReflect.Descriptor instead.
+func (*RequestLoginCodeReply) Descriptor() ([]byte, []int) {
+	return file_proto_auth_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *RequestLoginCodeReply) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *RequestLoginCodeReply) GetDeviceToken() string {
+	if x != nil {
+		return x.DeviceToken
+	}
+	return ""
+}
+
+func (x *RequestLoginCodeReply) GetExpiresIn() int64 {
+	if x != nil {
+		return x.ExpiresIn
+	}
+	return 0
+}
+
+// ระบุ email และ code หรือระบุ magicToken จากลิงก์ในอีเมล
+type VerifyLoginCodeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeviceToken   string                 `protobuf:"bytes,1,opt,name=deviceToken,proto3" json:"deviceToken,omitempty"`
+	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	Code          string                 `protobuf:"bytes,3,opt,name=code,proto3" json:"code,omitempty"`             // รหัสตัวเลข 6 หลัก
+	MagicToken    string                 `protobuf:"bytes,4,opt,name=magicToken,proto3" json:"magicToken,omitempty"` // ค่า token ในลิงก์
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
+func (x *VerifyLoginCodeRequest) Reset() {
+	*x = VerifyLoginCodeRequest{}
+	mi := &file_proto_auth_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyLoginCodeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyLoginCodeRequest) ProtoMessage() {}
+
+func (x *VerifyLoginCodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyLoginCodeRequest.ProtoReflect.Descriptor instead.
+func (*VerifyLoginCodeRequest) Descriptor() ([]byte, []int) {
+	return file_proto_auth_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *VerifyLoginCodeRequest) GetDeviceToken() string {
+	if x != nil {
+		return x.DeviceToken
+	}
+	return ""
+}
+
+func (x *VerifyLoginCodeRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *VerifyLoginCodeRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *VerifyLoginCodeRequest) GetMagicToken() string {
+	if x != nil {
+		return x.MagicToken
+	}
+	return ""
+}
+
+// ข้อมูลตอบกลับเมื่อเข้าสู่ระบบสำเร็จ
+type LoginReply struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	Email                string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`                                // อีเมลผู้ใช้
+	Username             string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`                          // ชื่อผู้ใช้
+	Token                string                 `protobuf:"bytes,3,opt,name=token,proto3" json:"token,omitempty"`                                // JWT token สำหรับใช้ยืนยันตัวตนในระบบ
+	PasswordExpired      bool                   `protobuf:"varint,4,opt,name=passwordExpired,proto3" json:"passwordExpired,omitempty"`           // true ถ้ารหัสผ่านมีอายุเกินกำหนด ควรให้ผู้ใช้เปลี่ยนรหัสผ่าน
+	SecondFactorRequired bool                   `protobuf:"varint,5,opt,name=secondFactorRequired,proto3" json:"secondFactorRequired,omitempty"` // ผู้ใช้บังคับใช้ passkey: token เป็นค่าว่าง ให้ยืนยันด้วย BeginPasskeyLogin และ FinishPasskeyLogin
+	SecondFactorToken    string                 `protobuf:"bytes,6,opt,name=secondFactorToken,proto3" json:"secondFactorToken,omitempty"`        // ส่งใน BeginPasskeyLogin (ใช้ได้ภายใน 5 นาที)
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
 func (x *LoginReply) Reset() {
 	*x = LoginReply{}
-	mi := &file_proto_auth_proto_msgTypes[3]
+	mi := &file_proto_auth_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -262,7 +448,7 @@ func (x *LoginReply) String() string {
 func (*LoginReply) ProtoMessage() {}
 
 func (x *LoginReply) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_auth_proto_msgTypes[3]
+	mi := &file_proto_auth_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -275,7 +461,7 @@ func (x *LoginReply) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LoginReply.ProtoReflect.Descriptor instead.
 func (*LoginReply) Descriptor() ([]byte, []int) {
-	return file_proto_auth_proto_rawDescGZIP(), []int{3}
+	return file_proto_auth_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *LoginReply) GetEmail() string {
@@ -299,6 +485,27 @@ func (x *LoginReply) GetToken() string {
 	return ""
 }
 
+func (x *LoginReply) GetPasswordExpired() bool {
+	if x != nil {
+		return x.PasswordExpired
+	}
+	return false
+}
+
+func (x *LoginReply) GetSecondFactorRequired() bool {
+	if x != nil {
+		return x.SecondFactorRequired
+	}
+	return false
+}
+
+func (x *LoginReply) GetSecondFactorToken() string {
+	if x != nil {
+		return x.SecondFactorToken
+	}
+	return ""
+}
+
 // ข้อมูลสำหรับคำขอออกจากระบบ
 type LogoutRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -309,7 +516,7 @@ type LogoutRequest struct {
 
 func (x *LogoutRequest) Reset() {
 	*x = LogoutRequest{}
-	mi := &file_proto_auth_proto_msgTypes[4]
+	mi := &file_proto_auth_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -321,7 +528,7 @@ func (x *LogoutRequest) String() string {
 func (*LogoutRequest) ProtoMessage() {}
 
 func (x *LogoutRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_auth_proto_msgTypes[4]
+	mi := &file_proto_auth_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -334,7 +541,7 @@ func (x *LogoutRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LogoutRequest.ProtoReflect.Descriptor instead.
 func (*LogoutRequest) Descriptor() ([]byte, []int) {
-	return file_proto_auth_proto_rawDescGZIP(), []int{4}
+	return file_proto_auth_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *LogoutRequest) GetToken() string {
@@ -354,7 +561,7 @@ type LogoutReply struct {
 
 func (x *LogoutReply) Reset() {
 	*x = LogoutReply{}
-	mi := &file_proto_auth_proto_msgTypes[5]
+	mi := &file_proto_auth_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -366,7 +573,7 @@ func (x *LogoutReply) String() string {
 func (*LogoutReply) ProtoMessage() {}
 
 func (x *LogoutReply) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_auth_proto_msgTypes[5]
+	mi := &file_proto_auth_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -379,7 +586,7 @@ func (x *LogoutReply) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LogoutReply.ProtoReflect.Descriptor instead.
 func (*LogoutReply) Descriptor() ([]byte, []int) {
-	return file_proto_auth_proto_rawDescGZIP(), []int{5}
+	return file_proto_auth_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *LogoutReply) GetMessage() string {
@@ -389,11 +596,427 @@ func (x *LogoutReply) GetMessage() string {
 	return ""
 }
 
+// ข้อมูลสำหรับคำขอเปลี่ยนรหัสผ่าน
+type ChangePasswordRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	CurrentPassword string                 `protobuf:"bytes,1,opt,name=currentPassword,proto3" json:"currentPassword,omitempty"` // รหัสผ่านปัจจุบัน
+	NewPassword     string                 `protobuf:"bytes,2,opt,name=newPassword,proto3" json:"newPassword,omitempty"`         // รหัสผ่านใหม่
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ChangePasswordRequest) Reset() {
+	*x = ChangePasswordRequest{}
+	mi := &file_proto_auth_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangePasswordRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangePasswordRequest) ProtoMessage() {}
+
+func (x *ChangePasswordRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangePasswordRequest.ProtoReflect.Descriptor instead.
+func (*ChangePasswordRequest) Descriptor() ([]byte, []int) {
+	return file_proto_auth_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ChangePasswordRequest) GetCurrentPassword() string {
+	if x != nil {
+		return x.CurrentPassword
+	}
+	return ""
+}
+
+func (x *ChangePasswordRequest) GetNewPassword() string {
+	if x != nil {
+		return x.NewPassword
+	}
+	return ""
+}
+
+// ข้อมูลตอบกลับเมื่อเปลี่ยนรหัสผ่านสำเร็จ
+type ChangePasswordReply struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Message           string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`                     // ข้อความสถานะ
+	Token             string                 `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`                         // JWT token ใหม่ (token เดิมทั้งหมดถูกยกเลิกแล้ว)
+	PasswordChangedAt string                 `protobuf:"bytes,3,opt,name=passwordChangedAt,proto3" json:"passwordChangedAt,omitempty"` // เวลาที่เปลี่ยนรหัสผ่าน
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ChangePasswordReply) Reset() {
+	*x = ChangePasswordReply{}
+	mi := &file_proto_auth_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangePasswordReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangePasswordReply) ProtoMessage() {}
+
+func (x *ChangePasswordReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangePasswordReply.ProtoReflect.Descriptor instead.
+func (*ChangePasswordReply) Descriptor() ([]byte, []int) {
+	return file_proto_auth_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ChangePasswordReply) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ChangePasswordReply) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *ChangePasswordReply) GetPasswordChangedAt() string {
+	if x != nil {
+		return x.PasswordChangedAt
+	}
+	return ""
+}
+
+// ข้อมูลสำหรับคำขอเปลี่ยนอีเมล
+type RequestEmailChangeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NewEmail      string                 `protobuf:"bytes,1,opt,name=newEmail,proto3" json:"newEmail,omitempty"` // อีเมลใหม่
+	Password      string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"` // รหัสผ่านปัจจุบัน เพื่อยืนยันตัวตน
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestEmailChangeRequest) Reset() {
+	*x = RequestEmailChangeRequest{}
+	mi := &file_proto_auth_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestEmailChangeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestEmailChangeRequest) ProtoMessage() {}
+
+func (x *RequestEmailChangeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestEmailChangeRequest.ProtoReflect.Descriptor instead.
+func (*RequestEmailChangeRequest) Descriptor() ([]byte, []int) {
+	return file_proto_auth_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *RequestEmailChangeRequest) GetNewEmail() string {
+	if x != nil {
+		return x.NewEmail
+	}
+	return ""
+}
+
+func (x *RequestEmailChangeRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+// ข้อมูลตอบกลับเมื่อส่งคำขอเปลี่ยนอีเมลสำเร็จ
+type RequestEmailChangeReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"` // ข้อความสถานะ
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestEmailChangeReply) Reset() {
+	*x = RequestEmailChangeReply{}
+	mi := &file_proto_auth_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestEmailChangeReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestEmailChangeReply) ProtoMessage() {}
+
+func (x *RequestEmailChangeReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestEmailChangeReply.ProtoReflect.Descriptor instead.
+func (*RequestEmailChangeReply) Descriptor() ([]byte, []int) {
+	return file_proto_auth_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *RequestEmailChangeReply) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// ข้อมูลสำหรับยืนยันการเปลี่ยนอีเมล
+type ConfirmEmailChangeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"` // token ยืนยันที่ส่งไปยังอีเมลใหม่
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfirmEmailChangeRequest) Reset() {
+	*x = ConfirmEmailChangeRequest{}
+	mi := &file_proto_auth_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfirmEmailChangeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfirmEmailChangeRequest) ProtoMessage() {}
+
+func (x *ConfirmEmailChangeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfirmEmailChangeRequest.ProtoReflect.Descriptor instead.
+func (*ConfirmEmailChangeRequest) Descriptor() ([]byte, []int) {
+	return file_proto_auth_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ConfirmEmailChangeRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+// ข้อมูลตอบกลับเมื่อเปลี่ยนอีเมลสำเร็จ
+type ConfirmEmailChangeReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"` // ข้อความสถานะ
+	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`     // อีเมลใหม่
+	Token         string                 `protobuf:"bytes,3,opt,name=token,proto3" json:"token,omitempty"`     // JWT token ใหม่ที่ผูกกับอีเมลใหม่
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfirmEmailChangeReply) Reset() {
+	*x = ConfirmEmailChangeReply{}
+	mi := &file_proto_auth_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfirmEmailChangeReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfirmEmailChangeReply) ProtoMessage() {}
+
+func (x *ConfirmEmailChangeReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfirmEmailChangeReply.ProtoReflect.Descriptor instead.
+func (*ConfirmEmailChangeReply) Descriptor() ([]byte, []int) {
+	return file_proto_auth_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ConfirmEmailChangeReply) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ConfirmEmailChangeReply) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *ConfirmEmailChangeReply) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+// ข้อมูลสำหรับย้อนกลับการเปลี่ยนอีเมล
+type RevertEmailChangeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"` // token ย้อนกลับที่ส่งไปยังอีเมลเดิม
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevertEmailChangeRequest) Reset() {
+	*x = RevertEmailChangeRequest{}
+	mi := &file_proto_auth_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevertEmailChangeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevertEmailChangeRequest) ProtoMessage() {}
+
+func (x *RevertEmailChangeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevertEmailChangeRequest.ProtoReflect.Descriptor instead.
+func (*RevertEmailChangeRequest) Descriptor() ([]byte, []int) {
+	return file_proto_auth_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *RevertEmailChangeRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+// ข้อมูลตอบกลับเมื่อย้อนกลับอีเมลสำเร็จ
+type RevertEmailChangeReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"` // ข้อความสถานะ
+	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`     // อีเมลที่กลับมาใช้งาน
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevertEmailChangeReply) Reset() {
+	*x = RevertEmailChangeReply{}
+	mi := &file_proto_auth_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevertEmailChangeReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevertEmailChangeReply) ProtoMessage() {}
+
+func (x *RevertEmailChangeReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevertEmailChangeReply.ProtoReflect.Descriptor instead.
+func (*RevertEmailChangeReply) Descriptor() ([]byte, []int) {
+	return file_proto_auth_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *RevertEmailChangeReply) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *RevertEmailChangeReply) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
 var File_proto_auth_proto protoreflect.FileDescriptor
 
 const file_proto_auth_proto_rawDesc = "" +
 	"\n" +
-	"\x10proto/auth.proto\"\xe7\x01\n" +
+	"\x10proto/auth.proto\"\x89\x02\n" +
 	"\x0fRegisterRequest\x12\x14\n" +
 	"\x05email\x18\x01 \x01(\tR\x05email\x12\x1a\n" +
 	"\bpassword\x18\x02 \x01(\tR\bpassword\x12\x1a\n" +
@@ -402,27 +1025,73 @@ const file_proto_auth_proto_rawDesc = "" +
 	"\tupdatedAt\x18\x05 \x01(\tR\tupdatedAt\x12\x18\n" +
 	"\adeleted\x18\x06 \x01(\bR\adeleted\x12\x1c\n" +
 	"\tdeletedAt\x18\a \x01(\tR\tdeletedAt\x12\x12\n" +
-	"\x04role\x18\b \x01(\tR\x04role\"_\n" +
+	"\x04role\x18\b \x01(\tR\x04role\x12 \n" +
+	"\vinviteToken\x18\t \x01(\tR\vinviteToken\"_\n" +
 	"\rRegisterReply\x12\x14\n" +
 	"\x05email\x18\x01 \x01(\tR\x05email\x12\x1a\n" +
 	"\busername\x18\x02 \x01(\tR\busername\x12\x1c\n" +
 	"\tcreatedAt\x18\x03 \x01(\tR\tcreatedAt\"@\n" +
 	"\fLoginRequest\x12\x14\n" +
 	"\x05email\x18\x01 \x01(\tR\x05email\x12\x1a\n" +
-	"\bpassword\x18\x02 \x01(\tR\bpassword\"T\n" +
+	"\bpassword\x18\x02 \x01(\tR\bpassword\"/\n" +
+	"\x17RequestLoginCodeRequest\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\"q\n" +
+	"\x15RequestLoginCodeReply\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\x12 \n" +
+	"\vdeviceToken\x18\x02 \x01(\tR\vdeviceToken\x12\x1c\n" +
+	"\texpiresIn\x18\x03 \x01(\x03R\texpiresIn\"\x84\x01\n" +
+	"\x16VerifyLoginCodeRequest\x12 \n" +
+	"\vdeviceToken\x18\x01 \x01(\tR\vdeviceToken\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\x12\x12\n" +
+	"\x04code\x18\x03 \x01(\tR\x04code\x12\x1e\n" +
+	"\n" +
+	"magicToken\x18\x04 \x01(\tR\n" +
+	"magicToken\"\xe0\x01\n" +
 	"\n" +
 	"LoginReply\x12\x14\n" +
 	"\x05email\x18\x01 \x01(\tR\x05email\x12\x1a\n" +
 	"\busername\x18\x02 \x01(\tR\busername\x12\x14\n" +
-	"\x05token\x18\x03 \x01(\tR\x05token\"%\n" +
+	"\x05token\x18\x03 \x01(\tR\x05token\x12(\n" +
+	"\x0fpasswordExpired\x18\x04 \x01(\bR\x0fpasswordExpired\x122\n" +
+	"\x14secondFactorRequired\x18\x05 \x01(\bR\x14secondFactorRequired\x12,\n" +
+	"\x11secondFactorToken\x18\x06 \x01(\tR\x11secondFactorToken\"%\n" +
 	"\rLogoutRequest\x12\x14\n" +
 	"\x05token\x18\x01 \x01(\tR\x05token\"'\n" +
 	"\vLogoutReply\x12\x18\n" +
-	"\amessage\x18\x01 \x01(\tR\amessage2\x88\x01\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\"c\n" +
+	"\x15ChangePasswordRequest\x12(\n" +
+	"\x0fcurrentPassword\x18\x01 \x01(\tR\x0fcurrentPassword\x12 \n" +
+	"\vnewPassword\x18\x02 \x01(\tR\vnewPassword\"s\n" +
+	"\x13ChangePasswordReply\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\x12\x14\n" +
+	"\x05token\x18\x02 \x01(\tR\x05token\x12,\n" +
+	"\x11passwordChangedAt\x18\x03 \x01(\tR\x11passwordChangedAt\"S\n" +
+	"\x19RequestEmailChangeRequest\x12\x1a\n" +
+	"\bnewEmail\x18\x01 \x01(\tR\bnewEmail\x12\x1a\n" +
+	"\bpassword\x18\x02 \x01(\tR\bpassword\"3\n" +
+	"\x17RequestEmailChangeReply\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\"1\n" +
+	"\x19ConfirmEmailChangeRequest\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\"_\n" +
+	"\x17ConfirmEmailChangeReply\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\x12\x14\n" +
+	"\x05token\x18\x03 \x01(\tR\x05token\"0\n" +
+	"\x18RevertEmailChangeRequest\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\"H\n" +
+	"\x16RevertEmailChangeReply\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email2\xa8\x04\n" +
 	"\vAuthService\x12,\n" +
 	"\bRegister\x12\x10.RegisterRequest\x1a\x0e.RegisterReply\x12#\n" +
 	"\x05Login\x12\r.LoginRequest\x1a\v.LoginReply\x12&\n" +
-	"\x06Logout\x12\x0e.LogoutRequest\x1a\f.LogoutReplyB\x19Z\x17auth-microservice/protob\x06proto3"
+	"\x06Logout\x12\x0e.LogoutRequest\x1a\f.LogoutReply\x12>\n" +
+	"\x0eChangePassword\x12\x16.ChangePasswordRequest\x1a\x14.ChangePasswordReply\x12J\n" +
+	"\x12RequestEmailChange\x12\x1a.RequestEmailChangeRequest\x1a\x18.RequestEmailChangeReply\x12J\n" +
+	"\x12ConfirmEmailChange\x12\x1a.ConfirmEmailChangeRequest\x1a\x18.ConfirmEmailChangeReply\x12G\n" +
+	"\x11RevertEmailChange\x12\x19.RevertEmailChangeRequest\x1a\x17.RevertEmailChangeReply\x12D\n" +
+	"\x10RequestLoginCode\x12\x18.RequestLoginCodeRequest\x1a\x16.RequestLoginCodeReply\x127\n" +
+	"\x0fVerifyLoginCode\x12\x17.VerifyLoginCodeRequest\x1a\v.LoginReplyB\x19Z\x17auth-microservice/protob\x06proto3"
 
 var (
 	file_proto_auth_proto_rawDescOnce sync.Once
@@ -436,27 +1105,50 @@ func file_proto_auth_proto_rawDescGZIP() []byte {
 	return file_proto_auth_proto_rawDescData
 }
 
-var file_proto_auth_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_proto_auth_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
 var file_proto_auth_proto_goTypes = []any{
-	(*RegisterRequest)(nil), // 0: RegisterRequest
-	(*RegisterReply)(nil),   // 1: RegisterReply
-	(*LoginRequest)(nil),    // 2: LoginRequest
-	(*LoginReply)(nil),      // 3: LoginReply
-	(*LogoutRequest)(nil),   // 4: LogoutRequest
-	(*LogoutReply)(nil),     // 5: LogoutReply
+	(*RegisterRequest)(nil),           // 0: RegisterRequest
+	(*RegisterReply)(nil),             // 1: RegisterReply
+	(*LoginRequest)(nil),              // 2: LoginRequest
+	(*RequestLoginCodeRequest)(nil),   // 3: RequestLoginCodeRequest
+	(*RequestLoginCodeReply)(nil),     // 4: RequestLoginCodeReply
+	(*VerifyLoginCodeRequest)(nil),    // 5: VerifyLoginCodeRequest
+	(*LoginReply)(nil),                // 6: LoginReply
+	(*LogoutRequest)(nil),             // 7: LogoutRequest
+	(*LogoutReply)(nil),               // 8: LogoutReply
+	(*ChangePasswordRequest)(nil),     // 9: ChangePasswordRequest
+	(*ChangePasswordReply)(nil),       // 10: ChangePasswordReply
+	(*RequestEmailChangeRequest)(nil), // 11: RequestEmailChangeRequest
+	(*RequestEmailChangeReply)(nil),   // 12: RequestEmailChangeReply
+	(*ConfirmEmailChangeRequest)(nil), // 13: ConfirmEmailChangeRequest
+	(*ConfirmEmailChangeReply)(nil),   // 14: ConfirmEmailChangeReply
+	(*RevertEmailChangeRequest)(nil),  // 15: RevertEmailChangeRequest
+	(*RevertEmailChangeReply)(nil),    // 16: RevertEmailChangeReply
 }
 var file_proto_auth_proto_depIdxs = []int32{
-	0, // 0: AuthService.Register:input_type -> RegisterRequest
-	2, // 1: AuthService.Login:input_type -> LoginRequest
-	4, // 2: AuthService.Logout:input_type -> LogoutRequest
-	1, // 3: AuthService.Register:output_type -> RegisterReply
-	3, // 4: AuthService.Login:output_type -> LoginReply
-	5, // 5: AuthService.Logout:output_type -> LogoutReply
-	3, // [3:6] is the sub-list for method output_type
-	0, // [0:3] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	0,  // 0: AuthService.Register:input_type -> RegisterRequest
+	2,  // 1: AuthService.Login:input_type -> LoginRequest
+	7,  // 2: AuthService.Logout:input_type -> LogoutRequest
+	9,  // 3: AuthService.ChangePassword:input_type -> ChangePasswordRequest
+	11, // 4: AuthService.RequestEmailChange:input_type -> RequestEmailChangeRequest
+	13, // 5: AuthService.ConfirmEmailChange:input_type -> ConfirmEmailChangeRequest
+	15, // 6: AuthService.RevertEmailChange:input_type -> RevertEmailChangeRequest
+	3,  // 7: AuthService.RequestLoginCode:input_type -> RequestLoginCodeRequest
+	5,  // 8: AuthService.VerifyLoginCode:input_type -> VerifyLoginCodeRequest
+	1,  // 9: AuthService.Register:output_type -> RegisterReply
+	6,  // 10: AuthService.Login:output_type -> LoginReply
+	8,  // 11: AuthService.Logout:output_type -> LogoutReply
+	10, // 12: AuthService.ChangePassword:output_type -> ChangePasswordReply
+	12, // 13: AuthService.RequestEmailChange:output_type -> RequestEmailChangeReply
+	14, // 14: AuthService.ConfirmEmailChange:output_type -> ConfirmEmailChangeReply
+	16, // 15: AuthService.RevertEmailChange:output_type -> RevertEmailChangeReply
+	4,  // 16: AuthService.RequestLoginCode:output_type -> RequestLoginCodeReply
+	6,  // 17: AuthService.VerifyLoginCode:output_type -> LoginReply
+	9,  // [9:18] is the sub-list for method output_type
+	0,  // [0:9] is the sub-list for method input_type
+	0,  // [0:0] is the sub-list for extension type_name
+	0,  // [0:0] is the sub-list for extension extendee
+	0,  // [0:0] is the sub-list for field type_name
 }
 
 func init() { file_proto_auth_proto_init() }
@@ -470,7 +1162,7 @@ func file_proto_auth_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_auth_proto_rawDesc), len(file_proto_auth_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   6,
+			NumMessages:   17,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
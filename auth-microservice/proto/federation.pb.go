@@ -0,0 +1,1991 @@
+// กำหนด version ของ Protocol Buffers ที่ใช้
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v6.31.1
+// source: proto/federation.proto
+
+package proto
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// client secret ไม่ถูกส่งกลับใน reply
+type IdentityProvider struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name           string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	DisplayName    string                 `protobuf:"bytes,3,opt,name=displayName,proto3" json:"displayName,omitempty"`
+	Issuer         string                 `protobuf:"bytes,4,opt,name=issuer,proto3" json:"issuer,omitempty"`
+	ClientId       string                 `protobuf:"bytes,5,opt,name=clientId,proto3" json:"clientId,omitempty"`
+	RedirectUri    string                 `protobuf:"bytes,6,opt,name=redirectUri,proto3" json:"redirectUri,omitempty"`
+	Scopes         []string               `protobuf:"bytes,7,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	AllowSignup    bool                   `protobuf:"varint,8,opt,name=allowSignup,proto3" json:"allowSignup,omitempty"`
+	AllowedDomains []string               `protobuf:"bytes,9,rep,name=allowedDomains,proto3" json:"allowedDomains,omitempty"`
+	CreatedAt      string                 `protobuf:"bytes,10,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+	UpdatedAt      string                 `protobuf:"bytes,11,opt,name=updatedAt,proto3" json:"updatedAt,omitempty"`
+	Protocol       string                 `protobuf:"bytes,12,opt,name=protocol,proto3" json:"protocol,omitempty"` // oidc หรือ saml
+	Saml           *SAMLSettings          `protobuf:"bytes,13,opt,name=saml,proto3" json:"saml,omitempty"`
+	SpEntityId     string                 `protobuf:"bytes,14,opt,name=spEntityId,proto3" json:"spEntityId,omitempty"`       // (SAML) entity ID ของระบบนี้ที่ต้องลงทะเบียนกับ IdP
+	AcsUrl         string                 `protobuf:"bytes,15,opt,name=acsUrl,proto3" json:"acsUrl,omitempty"`               // (SAML) endpoint ที่ IdP ส่ง assertion กลับมา (HTTP-POST)
+	SpMetadataUrl  string                 `protobuf:"bytes,16,opt,name=spMetadataUrl,proto3" json:"spMetadataUrl,omitempty"` // (SAML) metadata ของระบบนี้สำหรับนำเข้าที่ IdP
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *IdentityProvider) Reset() {
+	*x = IdentityProvider{}
+	mi := &file_proto_federation_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IdentityProvider) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IdentityProvider) ProtoMessage() {}
+
+func (x *IdentityProvider) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_federation_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IdentityProvider.ProtoReflect.Descriptor instead.
+func (*IdentityProvider) Descriptor() ([]byte, []int) {
+	return file_proto_federation_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *IdentityProvider) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *IdentityProvider) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *IdentityProvider) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
+
+func (x *IdentityProvider) GetIssuer() string {
+	if x != nil {
+		return x.Issuer
+	}
+	return ""
+}
+
+func (x *IdentityProvider) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+func (x *IdentityProvider) GetRedirectUri() string {
+	if x != nil {
+		return x.RedirectUri
+	}
+	return ""
+}
+
+func (x *IdentityProvider) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+func (x *IdentityProvider) GetAllowSignup() bool {
+	if x != nil {
+		return x.AllowSignup
+	}
+	return false
+}
+
+func (x *IdentityProvider) GetAllowedDomains() []string {
+	if x != nil {
+		return x.AllowedDomains
+	}
+	return nil
+}
+
+func (x *IdentityProvider) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *IdentityProvider) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+func (x *IdentityProvider) GetProtocol() string {
+	if x != nil {
+		return x.Protocol
+	}
+	return ""
+}
+
+func (x *IdentityProvider) GetSaml() *SAMLSettings {
+	if x != nil {
+		return x.Saml
+	}
+	return nil
+}
+
+func (x *IdentityProvider) GetSpEntityId() string {
+	if x != nil {
+		return x.SpEntityId
+	}
+	return ""
+}
+
+func (x *IdentityProvider) GetAcsUrl() string {
+	if x != nil {
+		return x.AcsUrl
+	}
+	return ""
+}
+
+func (x *IdentityProvider) GetSpMetadataUrl() string {
+	if x != nil {
+		return x.SpMetadataUrl
+	}
+	return ""
+}
+
+// การตั้งค่าของ SAML IdP
+type SAMLSettings struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	EntityId          string                 `protobuf:"bytes,1,opt,name=entityId,proto3" json:"entityId,omitempty"`
+	SsoUrl            string                 `protobuf:"bytes,2,opt,name=ssoUrl,proto3" json:"ssoUrl,omitempty"`                         // endpoint ที่รับ AuthnRequest แบบ HTTP-Redirect (https หรือ http://localhost)
+	Certificates      []string               `protobuf:"bytes,3,rep,name=certificates,proto3" json:"certificates,omitempty"`             // certificate (PEM) ที่ IdP ใช้เซ็น assertion
+	EmailAttribute    string                 `protobuf:"bytes,4,opt,name=emailAttribute,proto3" json:"emailAttribute,omitempty"`         // ชื่อ attribute ใน assertion (ค่าว่าง = ชื่อมาตรฐาน เช่น email, mail)
+	NameAttribute     string                 `protobuf:"bytes,5,opt,name=nameAttribute,proto3" json:"nameAttribute,omitempty"`           // ค่าว่าง = displayName, cn
+	UsernameAttribute string                 `protobuf:"bytes,6,opt,name=usernameAttribute,proto3" json:"usernameAttribute,omitempty"`   // ค่าว่าง = uid, username
+	GroupsAttribute   string                 `protobuf:"bytes,7,opt,name=groupsAttribute,proto3" json:"groupsAttribute,omitempty"`       // ค่าว่าง = groups, memberOf
+	RoleMappings      []*RoleMapping         `protobuf:"bytes,8,rep,name=roleMappings,proto3" json:"roleMappings,omitempty"`             // role ตามค่าใน attribute กลุ่ม (ใช้ mapping แรกที่ตรง ว่าง = ไม่กำหนด role จาก assertion)
+	DefaultRole       string                 `protobuf:"bytes,9,opt,name=defaultRole,proto3" json:"defaultRole,omitempty"`               // role ของผู้ใช้ที่ไม่อยู่ในกลุ่มใด (ค่าว่าง = user)
+	AllowIdpInitiated bool                   `protobuf:"varint,10,opt,name=allowIdpInitiated,proto3" json:"allowIdpInitiated,omitempty"` // รับ assertion ที่ IdP ส่งมาเองโดยไม่ได้เริ่มจาก StartFederatedLogin
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *SAMLSettings) Reset() {
+	*x = SAMLSettings{}
+	mi := &file_proto_federation_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SAMLSettings) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SAMLSettings) ProtoMessage() {}
+
+func (x *SAMLSettings) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_federation_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SAMLSettings.ProtoReflect.Descriptor instead.
+func (*SAMLSettings) Descriptor() ([]byte, []int) {
+	return file_proto_federation_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SAMLSettings) GetEntityId() string {
+	if x != nil {
+		return x.EntityId
+	}
+	return ""
+}
+
+func (x *SAMLSettings) GetSsoUrl() string {
+	if x != nil {
+		return x.SsoUrl
+	}
+	return ""
+}
+
+func (x *SAMLSettings) GetCertificates() []string {
+	if x != nil {
+		return x.Certificates
+	}
+	return nil
+}
+
+func (x *SAMLSettings) GetEmailAttribute() string {
+	if x != nil {
+		return x.EmailAttribute
+	}
+	return ""
+}
+
+func (x *SAMLSettings) GetNameAttribute() string {
+	if x != nil {
+		return x.NameAttribute
+	}
+	return ""
+}
+
+func (x *SAMLSettings) GetUsernameAttribute() string {
+	if x != nil {
+		return x.UsernameAttribute
+	}
+	return ""
+}
+
+func (x *SAMLSettings) GetGroupsAttribute() string {
+	if x != nil {
+		return x.GroupsAttribute
+	}
+	return ""
+}
+
+func (x *SAMLSettings) GetRoleMappings() []*RoleMapping {
+	if x != nil {
+		return x.RoleMappings
+	}
+	return nil
+}
+
+func (x *SAMLSettings) GetDefaultRole() string {
+	if x != nil {
+		return x.DefaultRole
+	}
+	return ""
+}
+
+func (x *SAMLSettings) GetAllowIdpInitiated() bool {
+	if x != nil {
+		return x.AllowIdpInitiated
+	}
+	return false
+}
+
+// provider แบบ oidc ระบุ issuer, clientId, clientSecret และ scopes ส่วนแบบ saml ระบุ saml หรือ samlMetadata
+type CreateIdentityProviderRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Name           string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"` // ตัวอักษรพิมพ์เล็ก ตัวเลข และ - (เช่น "corp")
+	DisplayName    string                 `protobuf:"bytes,2,opt,name=displayName,proto3" json:"displayName,omitempty"`
+	Issuer         string                 `protobuf:"bytes,3,opt,name=issuer,proto3" json:"issuer,omitempty"` // https หรือ http://localhost เท่านั้น
+	ClientId       string                 `protobuf:"bytes,4,opt,name=clientId,proto3" json:"clientId,omitempty"`
+	ClientSecret   string                 `protobuf:"bytes,5,opt,name=clientSecret,proto3" json:"clientSecret,omitempty"`     // ค่าว่าง = public client
+	RedirectUri    string                 `protobuf:"bytes,6,opt,name=redirectUri,proto3" json:"redirectUri,omitempty"`       // หน้า callback ที่ลงทะเบียนไว้กับ provider (saml: หน้าที่รับ state และ code)
+	Scopes         []string               `protobuf:"bytes,7,rep,name=scopes,proto3" json:"scopes,omitempty"`                 // ค่าว่าง = openid, email, profile
+	AllowSignup    bool                   `protobuf:"varint,8,opt,name=allowSignup,proto3" json:"allowSignup,omitempty"`      // สร้างผู้ใช้ใหม่เมื่อยังไม่มีผู้ใช้ที่ใช้อีเมลนี้
+	AllowedDomains []string               `protobuf:"bytes,9,rep,name=allowedDomains,proto3" json:"allowedDomains,omitempty"` // โดเมนอีเมลที่สร้างผู้ใช้ใหม่ได้ (ว่าง = ทุกโดเมน)
+	Protocol       string                 `protobuf:"bytes,10,opt,name=protocol,proto3" json:"protocol,omitempty"`            // oidc (ค่าว่าง) หรือ saml
+	Saml           *SAMLSettings          `protobuf:"bytes,11,opt,name=saml,proto3" json:"saml,omitempty"`
+	SamlMetadata   string                 `protobuf:"bytes,12,opt,name=samlMetadata,proto3" json:"samlMetadata,omitempty"` // metadata (XML) ของ IdP ใช้แทน entityId, ssoUrl และ certificates ใน saml
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CreateIdentityProviderRequest) Reset() {
+	*x = CreateIdentityProviderRequest{}
+	mi := &file_proto_federation_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateIdentityProviderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateIdentityProviderRequest) ProtoMessage() {}
+
+func (x *CreateIdentityProviderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_federation_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateIdentityProviderRequest.ProtoReflect.Descriptor instead.
+func (*CreateIdentityProviderRequest) Descriptor() ([]byte, []int) {
+	return file_proto_federation_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateIdentityProviderRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateIdentityProviderRequest) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
+
+func (x *CreateIdentityProviderRequest) GetIssuer() string {
+	if x != nil {
+		return x.Issuer
+	}
+	return ""
+}
+
+func (x *CreateIdentityProviderRequest) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+func (x *CreateIdentityProviderRequest) GetClientSecret() string {
+	if x != nil {
+		return x.ClientSecret
+	}
+	return ""
+}
+
+func (x *CreateIdentityProviderRequest) GetRedirectUri() string {
+	if x != nil {
+		return x.RedirectUri
+	}
+	return ""
+}
+
+func (x *CreateIdentityProviderRequest) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+func (x *CreateIdentityProviderRequest) GetAllowSignup() bool {
+	if x != nil {
+		return x.AllowSignup
+	}
+	return false
+}
+
+func (x *CreateIdentityProviderRequest) GetAllowedDomains() []string {
+	if x != nil {
+		return x.AllowedDomains
+	}
+	return nil
+}
+
+func (x *CreateIdentityProviderRequest) GetProtocol() string {
+	if x != nil {
+		return x.Protocol
+	}
+	return ""
+}
+
+func (x *CreateIdentityProviderRequest) GetSaml() *SAMLSettings {
+	if x != nil {
+		return x.Saml
+	}
+	return nil
+}
+
+func (x *CreateIdentityProviderRequest) GetSamlMetadata() string {
+	if x != nil {
+		return x.SamlMetadata
+	}
+	return ""
+}
+
+type ListIdentityProvidersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListIdentityProvidersRequest) Reset() {
+	*x = ListIdentityProvidersRequest{}
+	mi := &file_proto_federation_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListIdentityProvidersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListIdentityProvidersRequest) ProtoMessage() {}
+
+func (x *ListIdentityProvidersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_federation_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListIdentityProvidersRequest.ProtoReflect.Descriptor instead.
+func (*ListIdentityProvidersRequest) Descriptor() ([]byte, []int) {
+	return file_proto_federation_proto_rawDescGZIP(), []int{3}
+}
+
+type ListIdentityProvidersReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Providers     []*IdentityProvider    `protobuf:"bytes,1,rep,name=providers,proto3" json:"providers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListIdentityProvidersReply) Reset() {
+	*x = ListIdentityProvidersReply{}
+	mi := &file_proto_federation_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListIdentityProvidersReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListIdentityProvidersReply) ProtoMessage() {}
+
+func (x *ListIdentityProvidersReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_federation_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListIdentityProvidersReply.ProtoReflect.Descriptor instead.
+func (*ListIdentityProvidersReply) Descriptor() ([]byte, []int) {
+	return file_proto_federation_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListIdentityProvidersReply) GetProviders() []*IdentityProvider {
+	if x != nil {
+		return x.Providers
+	}
+	return nil
+}
+
+type DeleteIdentityProviderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteIdentityProviderRequest) Reset() {
+	*x = DeleteIdentityProviderRequest{}
+	mi := &file_proto_federation_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteIdentityProviderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteIdentityProviderRequest) ProtoMessage() {}
+
+func (x *DeleteIdentityProviderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_federation_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteIdentityProviderRequest.ProtoReflect.Descriptor instead.
+func (*DeleteIdentityProviderRequest) Descriptor() ([]byte, []int) {
+	return file_proto_federation_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *DeleteIdentityProviderRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type DeleteIdentityProviderReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteIdentityProviderReply) Reset() {
+	*x = DeleteIdentityProviderReply{}
+	mi := &file_proto_federation_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteIdentityProviderReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteIdentityProviderReply) ProtoMessage() {}
+
+func (x *DeleteIdentityProviderReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_federation_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteIdentityProviderReply.ProtoReflect.Descriptor instead.
+func (*DeleteIdentityProviderReply) Descriptor() ([]byte, []int) {
+	return file_proto_federation_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *DeleteIdentityProviderReply) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type StartFederatedLoginRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Provider      string                 `protobuf:"bytes,1,opt,name=provider,proto3" json:"provider,omitempty"` // ชื่อของ provider
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartFederatedLoginRequest) Reset() {
+	*x = StartFederatedLoginRequest{}
+	mi := &file_proto_federation_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartFederatedLoginRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartFederatedLoginRequest) ProtoMessage() {}
+
+func (x *StartFederatedLoginRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_federation_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartFederatedLoginRequest.ProtoReflect.Descriptor instead.
+func (*StartFederatedLoginRequest) Descriptor() ([]byte, []int) {
+	return file_proto_federation_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *StartFederatedLoginRequest) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+type StartFederatedLoginReply struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	AuthorizationUrl string                 `protobuf:"bytes,1,opt,name=authorizationUrl,proto3" json:"authorizationUrl,omitempty"`
+	State            string                 `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"` // ใช้ได้ครั้งเดียวภายใน 10 นาที
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *StartFederatedLoginReply) Reset() {
+	*x = StartFederatedLoginReply{}
+	mi := &file_proto_federation_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartFederatedLoginReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartFederatedLoginReply) ProtoMessage() {}
+
+func (x *StartFederatedLoginReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_federation_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartFederatedLoginReply.ProtoReflect.Descriptor instead.
+func (*StartFederatedLoginReply) Descriptor() ([]byte, []int) {
+	return file_proto_federation_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *StartFederatedLoginReply) GetAuthorizationUrl() string {
+	if x != nil {
+		return x.AuthorizationUrl
+	}
+	return ""
+}
+
+func (x *StartFederatedLoginReply) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+type CompleteFederatedLoginRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	State         string                 `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`
+	Code          string                 `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompleteFederatedLoginRequest) Reset() {
+	*x = CompleteFederatedLoginRequest{}
+	mi := &file_proto_federation_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompleteFederatedLoginRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompleteFederatedLoginRequest) ProtoMessage() {}
+
+func (x *CompleteFederatedLoginRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_federation_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompleteFederatedLoginRequest.ProtoReflect.Descriptor instead.
+func (*CompleteFederatedLoginRequest) Descriptor() ([]byte, []int) {
+	return file_proto_federation_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *CompleteFederatedLoginRequest) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *CompleteFederatedLoginRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+type CompleteFederatedLoginReply struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	Email                string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	Username             string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	Token                string                 `protobuf:"bytes,3,opt,name=token,proto3" json:"token,omitempty"`
+	Created              bool                   `protobuf:"varint,4,opt,name=created,proto3" json:"created,omitempty"`                           // เป็นผู้ใช้ใหม่ที่สร้างจากการเข้าสู่ระบบครั้งนี้
+	SecondFactorRequired bool                   `protobuf:"varint,5,opt,name=secondFactorRequired,proto3" json:"secondFactorRequired,omitempty"` // ผู้ใช้บังคับใช้ passkey: token เป็นค่าว่าง ให้ยืนยันด้วย BeginPasskeyLogin และ FinishPasskeyLogin
+	SecondFactorToken    string                 `protobuf:"bytes,6,opt,name=secondFactorToken,proto3" json:"secondFactorToken,omitempty"`        // ส่งใน BeginPasskeyLogin (ใช้ได้ภายใน 5 นาที)
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *CompleteFederatedLoginReply) Reset() {
+	*x = CompleteFederatedLoginReply{}
+	mi := &file_proto_federation_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompleteFederatedLoginReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompleteFederatedLoginReply) ProtoMessage() {}
+
+func (x *CompleteFederatedLoginReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_federation_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompleteFederatedLoginReply.ProtoReflect.Descriptor instead.
+func (*CompleteFederatedLoginReply) Descriptor() ([]byte, []int) {
+	return file_proto_federation_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CompleteFederatedLoginReply) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *CompleteFederatedLoginReply) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *CompleteFederatedLoginReply) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *CompleteFederatedLoginReply) GetCreated() bool {
+	if x != nil {
+		return x.Created
+	}
+	return false
+}
+
+func (x *CompleteFederatedLoginReply) GetSecondFactorRequired() bool {
+	if x != nil {
+		return x.SecondFactorRequired
+	}
+	return false
+}
+
+func (x *CompleteFederatedLoginReply) GetSecondFactorToken() string {
+	if x != nil {
+		return x.SecondFactorToken
+	}
+	return ""
+}
+
+type LinkedIdentity struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProviderId    string                 `protobuf:"bytes,1,opt,name=providerId,proto3" json:"providerId,omitempty"`
+	Provider      string                 `protobuf:"bytes,2,opt,name=provider,proto3" json:"provider,omitempty"`
+	Subject       string                 `protobuf:"bytes,3,opt,name=subject,proto3" json:"subject,omitempty"`
+	Email         string                 `protobuf:"bytes,4,opt,name=email,proto3" json:"email,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,5,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+	LastLoginAt   string                 `protobuf:"bytes,6,opt,name=lastLoginAt,proto3" json:"lastLoginAt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LinkedIdentity) Reset() {
+	*x = LinkedIdentity{}
+	mi := &file_proto_federation_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LinkedIdentity) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LinkedIdentity) ProtoMessage() {}
+
+func (x *LinkedIdentity) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_federation_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LinkedIdentity.ProtoReflect.Descriptor instead.
+func (*LinkedIdentity) Descriptor() ([]byte, []int) {
+	return file_proto_federation_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *LinkedIdentity) GetProviderId() string {
+	if x != nil {
+		return x.ProviderId
+	}
+	return ""
+}
+
+func (x *LinkedIdentity) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *LinkedIdentity) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *LinkedIdentity) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *LinkedIdentity) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *LinkedIdentity) GetLastLoginAt() string {
+	if x != nil {
+		return x.LastLoginAt
+	}
+	return ""
+}
+
+type LinkIdentityRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	State         string                 `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`
+	Code          string                 `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LinkIdentityRequest) Reset() {
+	*x = LinkIdentityRequest{}
+	mi := &file_proto_federation_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LinkIdentityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LinkIdentityRequest) ProtoMessage() {}
+
+func (x *LinkIdentityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_federation_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LinkIdentityRequest.ProtoReflect.Descriptor instead.
+func (*LinkIdentityRequest) Descriptor() ([]byte, []int) {
+	return file_proto_federation_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *LinkIdentityRequest) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *LinkIdentityRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+type UnlinkIdentityRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Provider      string                 `protobuf:"bytes,1,opt,name=provider,proto3" json:"provider,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnlinkIdentityRequest) Reset() {
+	*x = UnlinkIdentityRequest{}
+	mi := &file_proto_federation_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnlinkIdentityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnlinkIdentityRequest) ProtoMessage() {}
+
+func (x *UnlinkIdentityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_federation_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnlinkIdentityRequest.ProtoReflect.Descriptor instead.
+func (*UnlinkIdentityRequest) Descriptor() ([]byte, []int) {
+	return file_proto_federation_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *UnlinkIdentityRequest) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+type UnlinkIdentityReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnlinkIdentityReply) Reset() {
+	*x = UnlinkIdentityReply{}
+	mi := &file_proto_federation_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnlinkIdentityReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnlinkIdentityReply) ProtoMessage() {}
+
+func (x *UnlinkIdentityReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_federation_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnlinkIdentityReply.ProtoReflect.Descriptor instead.
+func (*UnlinkIdentityReply) Descriptor() ([]byte, []int) {
+	return file_proto_federation_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *UnlinkIdentityReply) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type ListLinkedIdentitiesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLinkedIdentitiesRequest) Reset() {
+	*x = ListLinkedIdentitiesRequest{}
+	mi := &file_proto_federation_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLinkedIdentitiesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLinkedIdentitiesRequest) ProtoMessage() {}
+
+func (x *ListLinkedIdentitiesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_federation_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLinkedIdentitiesRequest.ProtoReflect.Descriptor instead.
+func (*ListLinkedIdentitiesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_federation_proto_rawDescGZIP(), []int{15}
+}
+
+type ListLinkedIdentitiesReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Identities    []*LinkedIdentity      `protobuf:"bytes,1,rep,name=identities,proto3" json:"identities,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLinkedIdentitiesReply) Reset() {
+	*x = ListLinkedIdentitiesReply{}
+	mi := &file_proto_federation_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLinkedIdentitiesReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLinkedIdentitiesReply) ProtoMessage() {}
+
+func (x *ListLinkedIdentitiesReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_federation_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLinkedIdentitiesReply.ProtoReflect.Descriptor instead.
+func (*ListLinkedIdentitiesReply) Descriptor() ([]byte, []int) {
+	return file_proto_federation_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ListLinkedIdentitiesReply) GetIdentities() []*LinkedIdentity {
+	if x != nil {
+		return x.Identities
+	}
+	return nil
+}
+
+// ผู้ใช้ที่อยู่ในกลุ่ม (DN ใน directory หรือค่าใน attribute กลุ่มของ SAML) ได้รับ role นี้
+type RoleMapping struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Group         string                 `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	Role          string                 `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"` // user, tenant_admin หรือ admin (เฉพาะ tenant default)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RoleMapping) Reset() {
+	*x = RoleMapping{}
+	mi := &file_proto_federation_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RoleMapping) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoleMapping) ProtoMessage() {}
+
+func (x *RoleMapping) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_federation_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoleMapping.ProtoReflect.Descriptor instead.
+func (*RoleMapping) Descriptor() ([]byte, []int) {
+	return file_proto_federation_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *RoleMapping) GetGroup() string {
+	if x != nil {
+		return x.Group
+	}
+	return ""
+}
+
+func (x *RoleMapping) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+// รหัสผ่านของ service account ไม่ถูกส่งกลับใน reply
+type Directory struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	Id                   string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name                 string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Url                  string                 `protobuf:"bytes,3,opt,name=url,proto3" json:"url,omitempty"`
+	StartTls             bool                   `protobuf:"varint,4,opt,name=startTls,proto3" json:"startTls,omitempty"`
+	RootCa               string                 `protobuf:"bytes,5,opt,name=rootCa,proto3" json:"rootCa,omitempty"`
+	BindDn               string                 `protobuf:"bytes,6,opt,name=bindDn,proto3" json:"bindDn,omitempty"`
+	UserDnTemplate       string                 `protobuf:"bytes,7,opt,name=userDnTemplate,proto3" json:"userDnTemplate,omitempty"`
+	BaseDn               string                 `protobuf:"bytes,8,opt,name=baseDn,proto3" json:"baseDn,omitempty"`
+	UserFilter           string                 `protobuf:"bytes,9,opt,name=userFilter,proto3" json:"userFilter,omitempty"`
+	UsernameAttribute    string                 `protobuf:"bytes,10,opt,name=usernameAttribute,proto3" json:"usernameAttribute,omitempty"`
+	DisplayNameAttribute string                 `protobuf:"bytes,11,opt,name=displayNameAttribute,proto3" json:"displayNameAttribute,omitempty"`
+	GroupFilter          string                 `protobuf:"bytes,12,opt,name=groupFilter,proto3" json:"groupFilter,omitempty"`
+	RoleMappings         []*RoleMapping         `protobuf:"bytes,13,rep,name=roleMappings,proto3" json:"roleMappings,omitempty"`
+	DefaultRole          string                 `protobuf:"bytes,14,opt,name=defaultRole,proto3" json:"defaultRole,omitempty"`
+	Domains              []string               `protobuf:"bytes,15,rep,name=domains,proto3" json:"domains,omitempty"`
+	AllowLocalFallback   bool                   `protobuf:"varint,16,opt,name=allowLocalFallback,proto3" json:"allowLocalFallback,omitempty"`
+	PoolSize             int32                  `protobuf:"varint,17,opt,name=poolSize,proto3" json:"poolSize,omitempty"`
+	CreatedAt            string                 `protobuf:"bytes,18,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+	UpdatedAt            string                 `protobuf:"bytes,19,opt,name=updatedAt,proto3" json:"updatedAt,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *Directory) Reset() {
+	*x = Directory{}
+	mi := &file_proto_federation_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Directory) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Directory) ProtoMessage() {}
+
+func (x *Directory) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_federation_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Directory.ProtoReflect.Descriptor instead.
+func (*Directory) Descriptor() ([]byte, []int) {
+	return file_proto_federation_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *Directory) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Directory) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Directory) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Directory) GetStartTls() bool {
+	if x != nil {
+		return x.StartTls
+	}
+	return false
+}
+
+func (x *Directory) GetRootCa() string {
+	if x != nil {
+		return x.RootCa
+	}
+	return ""
+}
+
+func (x *Directory) GetBindDn() string {
+	if x != nil {
+		return x.BindDn
+	}
+	return ""
+}
+
+func (x *Directory) GetUserDnTemplate() string {
+	if x != nil {
+		return x.UserDnTemplate
+	}
+	return ""
+}
+
+func (x *Directory) GetBaseDn() string {
+	if x != nil {
+		return x.BaseDn
+	}
+	return ""
+}
+
+func (x *Directory) GetUserFilter() string {
+	if x != nil {
+		return x.UserFilter
+	}
+	return ""
+}
+
+func (x *Directory) GetUsernameAttribute() string {
+	if x != nil {
+		return x.UsernameAttribute
+	}
+	return ""
+}
+
+func (x *Directory) GetDisplayNameAttribute() string {
+	if x != nil {
+		return x.DisplayNameAttribute
+	}
+	return ""
+}
+
+func (x *Directory) GetGroupFilter() string {
+	if x != nil {
+		return x.GroupFilter
+	}
+	return ""
+}
+
+func (x *Directory) GetRoleMappings() []*RoleMapping {
+	if x != nil {
+		return x.RoleMappings
+	}
+	return nil
+}
+
+func (x *Directory) GetDefaultRole() string {
+	if x != nil {
+		return x.DefaultRole
+	}
+	return ""
+}
+
+func (x *Directory) GetDomains() []string {
+	if x != nil {
+		return x.Domains
+	}
+	return nil
+}
+
+func (x *Directory) GetAllowLocalFallback() bool {
+	if x != nil {
+		return x.AllowLocalFallback
+	}
+	return false
+}
+
+func (x *Directory) GetPoolSize() int32 {
+	if x != nil {
+		return x.PoolSize
+	}
+	return 0
+}
+
+func (x *Directory) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *Directory) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+// ระบุ userDnTemplate (bind ด้วย DN ของผู้ใช้) หรือ userFilter (ค้นหา DN ก่อน bind) อย่างใดอย่างหนึ่ง
+// ใน template และ filter ใช้ {email} และ {username} (ส่วนหน้า @ ของอีเมล) ส่วน groupFilter ใช้ {dn}
+type CreateDirectoryRequest struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	Name                 string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Url                  string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"` // ldaps:// หรือ ldap:// พร้อม startTls (ldap:// แบบไม่เข้ารหัสใช้ได้เฉพาะ localhost)
+	StartTls             bool                   `protobuf:"varint,3,opt,name=startTls,proto3" json:"startTls,omitempty"`
+	RootCa               string                 `protobuf:"bytes,4,opt,name=rootCa,proto3" json:"rootCa,omitempty"` // CA (PEM) ของ server (ว่าง = CA ของระบบ)
+	BindDn               string                 `protobuf:"bytes,5,opt,name=bindDn,proto3" json:"bindDn,omitempty"` // service account สำหรับค้นหาผู้ใช้และกลุ่ม (ว่าง = anonymous)
+	BindPassword         string                 `protobuf:"bytes,6,opt,name=bindPassword,proto3" json:"bindPassword,omitempty"`
+	UserDnTemplate       string                 `protobuf:"bytes,7,opt,name=userDnTemplate,proto3" json:"userDnTemplate,omitempty"` // เช่น "uid={username},ou=people,dc=example,dc=com" หรือ "{email}" สำหรับ AD
+	BaseDn               string                 `protobuf:"bytes,8,opt,name=baseDn,proto3" json:"baseDn,omitempty"`
+	UserFilter           string                 `protobuf:"bytes,9,opt,name=userFilter,proto3" json:"userFilter,omitempty"`                      // เช่น "(&(objectClass=person)(mail={email}))"
+	UsernameAttribute    string                 `protobuf:"bytes,10,opt,name=usernameAttribute,proto3" json:"usernameAttribute,omitempty"`       // ค่าว่าง = uid
+	DisplayNameAttribute string                 `protobuf:"bytes,11,opt,name=displayNameAttribute,proto3" json:"displayNameAttribute,omitempty"` // ค่าว่าง = cn
+	GroupFilter          string                 `protobuf:"bytes,12,opt,name=groupFilter,proto3" json:"groupFilter,omitempty"`                   // เช่น "(&(objectClass=groupOfNames)(member={dn}))" ว่าง = ใช้ memberOf
+	RoleMappings         []*RoleMapping         `protobuf:"bytes,13,rep,name=roleMappings,proto3" json:"roleMappings,omitempty"`                 // ใช้ mapping แรกที่ตรง
+	DefaultRole          string                 `protobuf:"bytes,14,opt,name=defaultRole,proto3" json:"defaultRole,omitempty"`                   // role ของผู้ใช้ที่ไม่อยู่ในกลุ่มใด (ค่าว่าง = user)
+	Domains              []string               `protobuf:"bytes,15,rep,name=domains,proto3" json:"domains,omitempty"`                           // โดเมนอีเมลที่เข้าสู่ระบบผ่าน directory นี้ (ว่าง = ทุกอีเมลของ tenant)
+	AllowLocalFallback   bool                   `protobuf:"varint,16,opt,name=allowLocalFallback,proto3" json:"allowLocalFallback,omitempty"`    // ผู้ใช้ที่ไม่พบใน directory เข้าสู่ระบบด้วยรหัสผ่านในระบบนี้ได้ (ต้องใช้ userFilter)
+	PoolSize             int32                  `protobuf:"varint,17,opt,name=poolSize,proto3" json:"poolSize,omitempty"`                        // connection สูงสุด (0 = 5 สูงสุด 50)
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *CreateDirectoryRequest) Reset() {
+	*x = CreateDirectoryRequest{}
+	mi := &file_proto_federation_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateDirectoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateDirectoryRequest) ProtoMessage() {}
+
+func (x *CreateDirectoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_federation_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateDirectoryRequest.ProtoReflect.Descriptor instead.
+func (*CreateDirectoryRequest) Descriptor() ([]byte, []int) {
+	return file_proto_federation_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *CreateDirectoryRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateDirectoryRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *CreateDirectoryRequest) GetStartTls() bool {
+	if x != nil {
+		return x.StartTls
+	}
+	return false
+}
+
+func (x *CreateDirectoryRequest) GetRootCa() string {
+	if x != nil {
+		return x.RootCa
+	}
+	return ""
+}
+
+func (x *CreateDirectoryRequest) GetBindDn() string {
+	if x != nil {
+		return x.BindDn
+	}
+	return ""
+}
+
+func (x *CreateDirectoryRequest) GetBindPassword() string {
+	if x != nil {
+		return x.BindPassword
+	}
+	return ""
+}
+
+func (x *CreateDirectoryRequest) GetUserDnTemplate() string {
+	if x != nil {
+		return x.UserDnTemplate
+	}
+	return ""
+}
+
+func (x *CreateDirectoryRequest) GetBaseDn() string {
+	if x != nil {
+		return x.BaseDn
+	}
+	return ""
+}
+
+func (x *CreateDirectoryRequest) GetUserFilter() string {
+	if x != nil {
+		return x.UserFilter
+	}
+	return ""
+}
+
+func (x *CreateDirectoryRequest) GetUsernameAttribute() string {
+	if x != nil {
+		return x.UsernameAttribute
+	}
+	return ""
+}
+
+func (x *CreateDirectoryRequest) GetDisplayNameAttribute() string {
+	if x != nil {
+		return x.DisplayNameAttribute
+	}
+	return ""
+}
+
+func (x *CreateDirectoryRequest) GetGroupFilter() string {
+	if x != nil {
+		return x.GroupFilter
+	}
+	return ""
+}
+
+func (x *CreateDirectoryRequest) GetRoleMappings() []*RoleMapping {
+	if x != nil {
+		return x.RoleMappings
+	}
+	return nil
+}
+
+func (x *CreateDirectoryRequest) GetDefaultRole() string {
+	if x != nil {
+		return x.DefaultRole
+	}
+	return ""
+}
+
+func (x *CreateDirectoryRequest) GetDomains() []string {
+	if x != nil {
+		return x.Domains
+	}
+	return nil
+}
+
+func (x *CreateDirectoryRequest) GetAllowLocalFallback() bool {
+	if x != nil {
+		return x.AllowLocalFallback
+	}
+	return false
+}
+
+func (x *CreateDirectoryRequest) GetPoolSize() int32 {
+	if x != nil {
+		return x.PoolSize
+	}
+	return 0
+}
+
+type ListDirectoriesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDirectoriesRequest) Reset() {
+	*x = ListDirectoriesRequest{}
+	mi := &file_proto_federation_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDirectoriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDirectoriesRequest) ProtoMessage() {}
+
+func (x *ListDirectoriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_federation_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDirectoriesRequest.ProtoReflect.Descriptor instead.
+func (*ListDirectoriesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_federation_proto_rawDescGZIP(), []int{20}
+}
+
+type ListDirectoriesReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Directories   []*Directory           `protobuf:"bytes,1,rep,name=directories,proto3" json:"directories,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDirectoriesReply) Reset() {
+	*x = ListDirectoriesReply{}
+	mi := &file_proto_federation_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDirectoriesReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDirectoriesReply) ProtoMessage() {}
+
+func (x *ListDirectoriesReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_federation_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDirectoriesReply.ProtoReflect.Descriptor instead.
+func (*ListDirectoriesReply) Descriptor() ([]byte, []int) {
+	return file_proto_federation_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ListDirectoriesReply) GetDirectories() []*Directory {
+	if x != nil {
+		return x.Directories
+	}
+	return nil
+}
+
+type DeleteDirectoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteDirectoryRequest) Reset() {
+	*x = DeleteDirectoryRequest{}
+	mi := &file_proto_federation_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteDirectoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteDirectoryRequest) ProtoMessage() {}
+
+func (x *DeleteDirectoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_federation_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteDirectoryRequest.ProtoReflect.Descriptor instead.
+func (*DeleteDirectoryRequest) Descriptor() ([]byte, []int) {
+	return file_proto_federation_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *DeleteDirectoryRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type DeleteDirectoryReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteDirectoryReply) Reset() {
+	*x = DeleteDirectoryReply{}
+	mi := &file_proto_federation_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteDirectoryReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteDirectoryReply) ProtoMessage() {}
+
+func (x *DeleteDirectoryReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_federation_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteDirectoryReply.ProtoReflect.Descriptor instead.
+func (*DeleteDirectoryReply) Descriptor() ([]byte, []int) {
+	return file_proto_federation_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *DeleteDirectoryReply) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+var File_proto_federation_proto protoreflect.FileDescriptor
+
+const file_proto_federation_proto_rawDesc = "" +
+	"\n" +
+	"\x16proto/federation.proto\"\xe9\x03\n" +
+	"\x10IdentityProvider\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdisplayName\x18\x03 \x01(\tR\vdisplayName\x12\x16\n" +
+	"\x06issuer\x18\x04 \x01(\tR\x06issuer\x12\x1a\n" +
+	"\bclientId\x18\x05 \x01(\tR\bclientId\x12 \n" +
+	"\vredirectUri\x18\x06 \x01(\tR\vredirectUri\x12\x16\n" +
+	"\x06scopes\x18\a \x03(\tR\x06scopes\x12 \n" +
+	"\vallowSignup\x18\b \x01(\bR\vallowSignup\x12&\n" +
+	"\x0eallowedDomains\x18\t \x03(\tR\x0eallowedDomains\x12\x1c\n" +
+	"\tcreatedAt\x18\n" +
+	" \x01(\tR\tcreatedAt\x12\x1c\n" +
+	"\tupdatedAt\x18\v \x01(\tR\tupdatedAt\x12\x1a\n" +
+	"\bprotocol\x18\f \x01(\tR\bprotocol\x12!\n" +
+	"\x04saml\x18\r \x01(\v2\r.SAMLSettingsR\x04saml\x12\x1e\n" +
+	"\n" +
+	"spEntityId\x18\x0e \x01(\tR\n" +
+	"spEntityId\x12\x16\n" +
+	"\x06acsUrl\x18\x0f \x01(\tR\x06acsUrl\x12$\n" +
+	"\rspMetadataUrl\x18\x10 \x01(\tR\rspMetadataUrl\"\x8e\x03\n" +
+	"\fSAMLSettings\x12\x1a\n" +
+	"\bentityId\x18\x01 \x01(\tR\bentityId\x12\x16\n" +
+	"\x06ssoUrl\x18\x02 \x01(\tR\x06ssoUrl\x12\"\n" +
+	"\fcertificates\x18\x03 \x03(\tR\fcertificates\x12&\n" +
+	"\x0eemailAttribute\x18\x04 \x01(\tR\x0eemailAttribute\x12$\n" +
+	"\rnameAttribute\x18\x05 \x01(\tR\rnameAttribute\x12,\n" +
+	"\x11usernameAttribute\x18\x06 \x01(\tR\x11usernameAttribute\x12(\n" +
+	"\x0fgroupsAttribute\x18\a \x01(\tR\x0fgroupsAttribute\x120\n" +
+	"\froleMappings\x18\b \x03(\v2\f.RoleMappingR\froleMappings\x12 \n" +
+	"\vdefaultRole\x18\t \x01(\tR\vdefaultRole\x12,\n" +
+	"\x11allowIdpInitiated\x18\n" +
+	" \x01(\bR\x11allowIdpInitiated\"\x94\x03\n" +
+	"\x1dCreateIdentityProviderRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
+	"\vdisplayName\x18\x02 \x01(\tR\vdisplayName\x12\x16\n" +
+	"\x06issuer\x18\x03 \x01(\tR\x06issuer\x12\x1a\n" +
+	"\bclientId\x18\x04 \x01(\tR\bclientId\x12\"\n" +
+	"\fclientSecret\x18\x05 \x01(\tR\fclientSecret\x12 \n" +
+	"\vredirectUri\x18\x06 \x01(\tR\vredirectUri\x12\x16\n" +
+	"\x06scopes\x18\a \x03(\tR\x06scopes\x12 \n" +
+	"\vallowSignup\x18\b \x01(\bR\vallowSignup\x12&\n" +
+	"\x0eallowedDomains\x18\t \x03(\tR\x0eallowedDomains\x12\x1a\n" +
+	"\bprotocol\x18\n" +
+	" \x01(\tR\bprotocol\x12!\n" +
+	"\x04saml\x18\v \x01(\v2\r.SAMLSettingsR\x04saml\x12\"\n" +
+	"\fsamlMetadata\x18\f \x01(\tR\fsamlMetadata\"\x1e\n" +
+	"\x1cListIdentityProvidersRequest\"M\n" +
+	"\x1aListIdentityProvidersReply\x12/\n" +
+	"\tproviders\x18\x01 \x03(\v2\x11.IdentityProviderR\tproviders\"/\n" +
+	"\x1dDeleteIdentityProviderRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"7\n" +
+	"\x1bDeleteIdentityProviderReply\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\"8\n" +
+	"\x1aStartFederatedLoginRequest\x12\x1a\n" +
+	"\bprovider\x18\x01 \x01(\tR\bprovider\"\\\n" +
+	"\x18StartFederatedLoginReply\x12*\n" +
+	"\x10authorizationUrl\x18\x01 \x01(\tR\x10authorizationUrl\x12\x14\n" +
+	"\x05state\x18\x02 \x01(\tR\x05state\"I\n" +
+	"\x1dCompleteFederatedLoginRequest\x12\x14\n" +
+	"\x05state\x18\x01 \x01(\tR\x05state\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\"\xe1\x01\n" +
+	"\x1bCompleteFederatedLoginReply\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12\x14\n" +
+	"\x05token\x18\x03 \x01(\tR\x05token\x12\x18\n" +
+	"\acreated\x18\x04 \x01(\bR\acreated\x122\n" +
+	"\x14secondFactorRequired\x18\x05 \x01(\bR\x14secondFactorRequired\x12,\n" +
+	"\x11secondFactorToken\x18\x06 \x01(\tR\x11secondFactorToken\"\xbc\x01\n" +
+	"\x0eLinkedIdentity\x12\x1e\n" +
+	"\n" +
+	"providerId\x18\x01 \x01(\tR\n" +
+	"providerId\x12\x1a\n" +
+	"\bprovider\x18\x02 \x01(\tR\bprovider\x12\x18\n" +
+	"\asubject\x18\x03 \x01(\tR\asubject\x12\x14\n" +
+	"\x05email\x18\x04 \x01(\tR\x05email\x12\x1c\n" +
+	"\tcreatedAt\x18\x05 \x01(\tR\tcreatedAt\x12 \n" +
+	"\vlastLoginAt\x18\x06 \x01(\tR\vlastLoginAt\"?\n" +
+	"\x13LinkIdentityRequest\x12\x14\n" +
+	"\x05state\x18\x01 \x01(\tR\x05state\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\"3\n" +
+	"\x15UnlinkIdentityRequest\x12\x1a\n" +
+	"\bprovider\x18\x01 \x01(\tR\bprovider\"/\n" +
+	"\x13UnlinkIdentityReply\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\"\x1d\n" +
+	"\x1bListLinkedIdentitiesRequest\"L\n" +
+	"\x19ListLinkedIdentitiesReply\x12/\n" +
+	"\n" +
+	"identities\x18\x01 \x03(\v2\x0f.LinkedIdentityR\n" +
+	"identities\"7\n" +
+	"\vRoleMapping\x12\x14\n" +
+	"\x05group\x18\x01 \x01(\tR\x05group\x12\x12\n" +
+	"\x04role\x18\x02 \x01(\tR\x04role\"\xe7\x04\n" +
+	"\tDirectory\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x10\n" +
+	"\x03url\x18\x03 \x01(\tR\x03url\x12\x1a\n" +
+	"\bstartTls\x18\x04 \x01(\bR\bstartTls\x12\x16\n" +
+	"\x06rootCa\x18\x05 \x01(\tR\x06rootCa\x12\x16\n" +
+	"\x06bindDn\x18\x06 \x01(\tR\x06bindDn\x12&\n" +
+	"\x0euserDnTemplate\x18\a \x01(\tR\x0euserDnTemplate\x12\x16\n" +
+	"\x06baseDn\x18\b \x01(\tR\x06baseDn\x12\x1e\n" +
+	"\n" +
+	"userFilter\x18\t \x01(\tR\n" +
+	"userFilter\x12,\n" +
+	"\x11usernameAttribute\x18\n" +
+	" \x01(\tR\x11usernameAttribute\x122\n" +
+	"\x14displayNameAttribute\x18\v \x01(\tR\x14displayNameAttribute\x12 \n" +
+	"\vgroupFilter\x18\f \x01(\tR\vgroupFilter\x120\n" +
+	"\froleMappings\x18\r \x03(\v2\f.RoleMappingR\froleMappings\x12 \n" +
+	"\vdefaultRole\x18\x0e \x01(\tR\vdefaultRole\x12\x18\n" +
+	"\adomains\x18\x0f \x03(\tR\adomains\x12.\n" +
+	"\x12allowLocalFallback\x18\x10 \x01(\bR\x12allowLocalFallback\x12\x1a\n" +
+	"\bpoolSize\x18\x11 \x01(\x05R\bpoolSize\x12\x1c\n" +
+	"\tcreatedAt\x18\x12 \x01(\tR\tcreatedAt\x12\x1c\n" +
+	"\tupdatedAt\x18\x13 \x01(\tR\tupdatedAt\"\xcc\x04\n" +
+	"\x16CreateDirectoryRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x12\x1a\n" +
+	"\bstartTls\x18\x03 \x01(\bR\bstartTls\x12\x16\n" +
+	"\x06rootCa\x18\x04 \x01(\tR\x06rootCa\x12\x16\n" +
+	"\x06bindDn\x18\x05 \x01(\tR\x06bindDn\x12\"\n" +
+	"\fbindPassword\x18\x06 \x01(\tR\fbindPassword\x12&\n" +
+	"\x0euserDnTemplate\x18\a \x01(\tR\x0euserDnTemplate\x12\x16\n" +
+	"\x06baseDn\x18\b \x01(\tR\x06baseDn\x12\x1e\n" +
+	"\n" +
+	"userFilter\x18\t \x01(\tR\n" +
+	"userFilter\x12,\n" +
+	"\x11usernameAttribute\x18\n" +
+	" \x01(\tR\x11usernameAttribute\x122\n" +
+	"\x14displayNameAttribute\x18\v \x01(\tR\x14displayNameAttribute\x12 \n" +
+	"\vgroupFilter\x18\f \x01(\tR\vgroupFilter\x120\n" +
+	"\froleMappings\x18\r \x03(\v2\f.RoleMappingR\froleMappings\x12 \n" +
+	"\vdefaultRole\x18\x0e \x01(\tR\vdefaultRole\x12\x18\n" +
+	"\adomains\x18\x0f \x03(\tR\adomains\x12.\n" +
+	"\x12allowLocalFallback\x18\x10 \x01(\bR\x12allowLocalFallback\x12\x1a\n" +
+	"\bpoolSize\x18\x11 \x01(\x05R\bpoolSize\"\x18\n" +
+	"\x16ListDirectoriesRequest\"D\n" +
+	"\x14ListDirectoriesReply\x12,\n" +
+	"\vdirectories\x18\x01 \x03(\v2\n" +
+	".DirectoryR\vdirectories\"(\n" +
+	"\x16DeleteDirectoryRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"0\n" +
+	"\x14DeleteDirectoryReply\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage2\xd1\x06\n" +
+	"\x11FederationService\x12M\n" +
+	"\x16CreateIdentityProvider\x12\x1e.CreateIdentityProviderRequest\x1a\x11.IdentityProvider\"\x00\x12U\n" +
+	"\x15ListIdentityProviders\x12\x1d.ListIdentityProvidersRequest\x1a\x1b.ListIdentityProvidersReply\"\x00\x12X\n" +
+	"\x16DeleteIdentityProvider\x12\x1e.DeleteIdentityProviderRequest\x1a\x1c.DeleteIdentityProviderReply\"\x00\x12O\n" +
+	"\x13StartFederatedLogin\x12\x1b.StartFederatedLoginRequest\x1a\x19.StartFederatedLoginReply\"\x00\x12X\n" +
+	"\x16CompleteFederatedLogin\x12\x1e.CompleteFederatedLoginRequest\x1a\x1c.CompleteFederatedLoginReply\"\x00\x127\n" +
+	"\fLinkIdentity\x12\x14.LinkIdentityRequest\x1a\x0f.LinkedIdentity\"\x00\x12@\n" +
+	"\x0eUnlinkIdentity\x12\x16.UnlinkIdentityRequest\x1a\x14.UnlinkIdentityReply\"\x00\x12R\n" +
+	"\x14ListLinkedIdentities\x12\x1c.ListLinkedIdentitiesRequest\x1a\x1a.ListLinkedIdentitiesReply\"\x00\x128\n" +
+	"\x0fCreateDirectory\x12\x17.CreateDirectoryRequest\x1a\n" +
+	".Directory\"\x00\x12C\n" +
+	"\x0fListDirectories\x12\x17.ListDirectoriesRequest\x1a\x15.ListDirectoriesReply\"\x00\x12C\n" +
+	"\x0fDeleteDirectory\x12\x17.DeleteDirectoryRequest\x1a\x15.DeleteDirectoryReply\"\x00B\x19Z\x17auth-microservice/protob\x06proto3"
+
+var (
+	file_proto_federation_proto_rawDescOnce sync.Once
+	file_proto_federation_proto_rawDescData []byte
+)
+
+func file_proto_federation_proto_rawDescGZIP() []byte {
+	file_proto_federation_proto_rawDescOnce.Do(func() {
+		file_proto_federation_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_federation_proto_rawDesc), len(file_proto_federation_proto_rawDesc)))
+	})
+	return file_proto_federation_proto_rawDescData
+}
+
+var file_proto_federation_proto_msgTypes = make([]protoimpl.MessageInfo, 24)
+var file_proto_federation_proto_goTypes = []any{
+	(*IdentityProvider)(nil),              // 0: IdentityProvider
+	(*SAMLSettings)(nil),                  // 1: SAMLSettings
+	(*CreateIdentityProviderRequest)(nil), // 2: CreateIdentityProviderRequest
+	(*ListIdentityProvidersRequest)(nil),  // 3: ListIdentityProvidersRequest
+	(*ListIdentityProvidersReply)(nil),    // 4: ListIdentityProvidersReply
+	(*DeleteIdentityProviderRequest)(nil), // 5: DeleteIdentityProviderRequest
+	(*DeleteIdentityProviderReply)(nil),   // 6: DeleteIdentityProviderReply
+	(*StartFederatedLoginRequest)(nil),    // 7: StartFederatedLoginRequest
+	(*StartFederatedLoginReply)(nil),      // 8: StartFederatedLoginReply
+	(*CompleteFederatedLoginRequest)(nil), // 9: CompleteFederatedLoginRequest
+	(*CompleteFederatedLoginReply)(nil),   // 10: CompleteFederatedLoginReply
+	(*LinkedIdentity)(nil),                // 11: LinkedIdentity
+	(*LinkIdentityRequest)(nil),           // 12: LinkIdentityRequest
+	(*UnlinkIdentityRequest)(nil),         // 13: UnlinkIdentityRequest
+	(*UnlinkIdentityReply)(nil),           // 14: UnlinkIdentityReply
+	(*ListLinkedIdentitiesRequest)(nil),   // 15: ListLinkedIdentitiesRequest
+	(*ListLinkedIdentitiesReply)(nil),     // 16: ListLinkedIdentitiesReply
+	(*RoleMapping)(nil),                   // 17: RoleMapping
+	(*Directory)(nil),                     // 18: Directory
+	(*CreateDirectoryRequest)(nil),        // 19: CreateDirectoryRequest
+	(*ListDirectoriesRequest)(nil),        // 20: ListDirectoriesRequest
+	(*ListDirectoriesReply)(nil),          // 21: ListDirectoriesReply
+	(*DeleteDirectoryRequest)(nil),        // 22: DeleteDirectoryRequest
+	(*DeleteDirectoryReply)(nil),          // 23: DeleteDirectoryReply
+}
+var file_proto_federation_proto_depIdxs = []int32{
+	1,  // 0: IdentityProvider.saml:type_name -> SAMLSettings
+	17, // 1: SAMLSettings.roleMappings:type_name -> RoleMapping
+	1,  // 2: CreateIdentityProviderRequest.saml:type_name -> SAMLSettings
+	0,  // 3: ListIdentityProvidersReply.providers:type_name -> IdentityProvider
+	11, // 4: ListLinkedIdentitiesReply.identities:type_name -> LinkedIdentity
+	17, // 5: Directory.roleMappings:type_name -> RoleMapping
+	17, // 6: CreateDirectoryRequest.roleMappings:type_name -> RoleMapping
+	18, // 7: ListDirectoriesReply.directories:type_name -> Directory
+	2,  // 8: FederationService.CreateIdentityProvider:input_type -> CreateIdentityProviderRequest
+	3,  // 9: FederationService.ListIdentityProviders:input_type -> ListIdentityProvidersRequest
+	5,  // 10: FederationService.DeleteIdentityProvider:input_type -> DeleteIdentityProviderRequest
+	7,  // 11: FederationService.StartFederatedLogin:input_type -> StartFederatedLoginRequest
+	9,  // 12: FederationService.CompleteFederatedLogin:input_type -> CompleteFederatedLoginRequest
+	12, // 13: FederationService.LinkIdentity:input_type -> LinkIdentityRequest
+	13, // 14: FederationService.UnlinkIdentity:input_type -> UnlinkIdentityRequest
+	15, // 15: FederationService.ListLinkedIdentities:input_type -> ListLinkedIdentitiesRequest
+	19, // 16: FederationService.CreateDirectory:input_type -> CreateDirectoryRequest
+	20, // 17: FederationService.ListDirectories:input_type -> ListDirectoriesRequest
+	22, // 18: FederationService.DeleteDirectory:input_type -> DeleteDirectoryRequest
+	0,  // 19: FederationService.CreateIdentityProvider:output_type -> IdentityProvider
+	4,  // 20: FederationService.ListIdentityProviders:output_type -> ListIdentityProvidersReply
+	6,  // 21: FederationService.DeleteIdentityProvider:output_type -> DeleteIdentityProviderReply
+	8,  // 22: FederationService.StartFederatedLogin:output_type -> StartFederatedLoginReply
+	10, // 23: FederationService.CompleteFederatedLogin:output_type -> CompleteFederatedLoginReply
+	11, // 24: FederationService.LinkIdentity:output_type -> LinkedIdentity
+	14, // 25: FederationService.UnlinkIdentity:output_type -> UnlinkIdentityReply
+	16, // 26: FederationService.ListLinkedIdentities:output_type -> ListLinkedIdentitiesReply
+	18, // 27: FederationService.CreateDirectory:output_type -> Directory
+	21, // 28: FederationService.ListDirectories:output_type -> ListDirectoriesReply
+	23, // 29: FederationService.DeleteDirectory:output_type -> DeleteDirectoryReply
+	19, // [19:30] is the sub-list for method output_type
+	8,  // [8:19] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_proto_federation_proto_init() }
+func file_proto_federation_proto_init() {
+	if File_proto_federation_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_federation_proto_rawDesc), len(file_proto_federation_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   24,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_federation_proto_goTypes,
+		DependencyIndexes: file_proto_federation_proto_depIdxs,
+		MessageInfos:      file_proto_federation_proto_msgTypes,
+	}.Build()
+	File_proto_federation_proto = out.File
+	file_proto_federation_proto_goTypes = nil
+	file_proto_federation_proto_depIdxs = nil
+}
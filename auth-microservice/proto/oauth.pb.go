@@ -0,0 +1,1269 @@
+// กำหนด version ของ Protocol Buffers ที่ใช้
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v6.31.1
+// source: proto/oauth.proto
+
+package proto
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// ข้อมูลสำหรับขอ token
+type TokenRequest struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	GrantType           string                 `protobuf:"bytes,1,opt,name=grantType,proto3" json:"grantType,omitempty"` // "client_credentials", "authorization_code" หรือ "refresh_token"
+	ClientId            string                 `protobuf:"bytes,2,opt,name=clientId,proto3" json:"clientId,omitempty"`
+	ClientSecret        string                 `protobuf:"bytes,3,opt,name=clientSecret,proto3" json:"clientSecret,omitempty"`               // ยืนยันตัวตนด้วย secret (public client ไม่ต้องส่ง)
+	ClientAssertionType string                 `protobuf:"bytes,4,opt,name=clientAssertionType,proto3" json:"clientAssertionType,omitempty"` // "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+	ClientAssertion     string                 `protobuf:"bytes,5,opt,name=clientAssertion,proto3" json:"clientAssertion,omitempty"`         // หรือยืนยันตัวตนด้วย JWT ที่เซ็นด้วย private key ของ service account
+	Scope               string                 `protobuf:"bytes,6,opt,name=scope,proto3" json:"scope,omitempty"`                             // scope ที่ต้องการ คั่นด้วยช่องว่าง (ค่าว่าง = scope ทั้งหมดที่ได้รับ)
+	Code                string                 `protobuf:"bytes,7,opt,name=code,proto3" json:"code,omitempty"`                               // authorization code (authorization_code)
+	RedirectUri         string                 `protobuf:"bytes,8,opt,name=redirectUri,proto3" json:"redirectUri,omitempty"`                 // ต้องตรงกับที่ใช้ตอนขอ code
+	CodeVerifier        string                 `protobuf:"bytes,9,opt,name=codeVerifier,proto3" json:"codeVerifier,omitempty"`               // PKCE code_verifier
+	RefreshToken        string                 `protobuf:"bytes,10,opt,name=refreshToken,proto3" json:"refreshToken,omitempty"`              // refresh token (refresh_token)
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *TokenRequest) Reset() {
+	*x = TokenRequest{}
+	mi := &file_proto_oauth_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TokenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TokenRequest) ProtoMessage() {}
+
+func (x *TokenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oauth_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TokenRequest.ProtoReflect.Descriptor instead.
+func (*TokenRequest) Descriptor() ([]byte, []int) {
+	return file_proto_oauth_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *TokenRequest) GetGrantType() string {
+	if x != nil {
+		return x.GrantType
+	}
+	return ""
+}
+
+func (x *TokenRequest) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+func (x *TokenRequest) GetClientSecret() string {
+	if x != nil {
+		return x.ClientSecret
+	}
+	return ""
+}
+
+func (x *TokenRequest) GetClientAssertionType() string {
+	if x != nil {
+		return x.ClientAssertionType
+	}
+	return ""
+}
+
+func (x *TokenRequest) GetClientAssertion() string {
+	if x != nil {
+		return x.ClientAssertion
+	}
+	return ""
+}
+
+func (x *TokenRequest) GetScope() string {
+	if x != nil {
+		return x.Scope
+	}
+	return ""
+}
+
+func (x *TokenRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *TokenRequest) GetRedirectUri() string {
+	if x != nil {
+		return x.RedirectUri
+	}
+	return ""
+}
+
+func (x *TokenRequest) GetCodeVerifier() string {
+	if x != nil {
+		return x.CodeVerifier
+	}
+	return ""
+}
+
+func (x *TokenRequest) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
+type TokenReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccessToken   string                 `protobuf:"bytes,1,opt,name=accessToken,proto3" json:"accessToken,omitempty"`
+	TokenType     string                 `protobuf:"bytes,2,opt,name=tokenType,proto3" json:"tokenType,omitempty"`       // "Bearer"
+	ExpiresIn     int64                  `protobuf:"varint,3,opt,name=expiresIn,proto3" json:"expiresIn,omitempty"`      // อายุของ token (วินาที)
+	Scope         string                 `protobuf:"bytes,4,opt,name=scope,proto3" json:"scope,omitempty"`               // scope ที่ได้รับ
+	IdToken       string                 `protobuf:"bytes,5,opt,name=idToken,proto3" json:"idToken,omitempty"`           // ID token (เมื่อมี scope openid)
+	RefreshToken  string                 `protobuf:"bytes,6,opt,name=refreshToken,proto3" json:"refreshToken,omitempty"` // refresh token (เมื่อมี scope offline_access) ใช้ได้ครั้งเดียว
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TokenReply) Reset() {
+	*x = TokenReply{}
+	mi := &file_proto_oauth_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TokenReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TokenReply) ProtoMessage() {}
+
+func (x *TokenReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oauth_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TokenReply.ProtoReflect.Descriptor instead.
+func (*TokenReply) Descriptor() ([]byte, []int) {
+	return file_proto_oauth_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *TokenReply) GetAccessToken() string {
+	if x != nil {
+		return x.AccessToken
+	}
+	return ""
+}
+
+func (x *TokenReply) GetTokenType() string {
+	if x != nil {
+		return x.TokenType
+	}
+	return ""
+}
+
+func (x *TokenReply) GetExpiresIn() int64 {
+	if x != nil {
+		return x.ExpiresIn
+	}
+	return 0
+}
+
+func (x *TokenReply) GetScope() string {
+	if x != nil {
+		return x.Scope
+	}
+	return ""
+}
+
+func (x *TokenReply) GetIdToken() string {
+	if x != nil {
+		return x.IdToken
+	}
+	return ""
+}
+
+func (x *TokenReply) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
+// พารามิเตอร์ของ authorization request (ชื่อตาม query string ของ /oauth/authorize)
+type AuthorizeRequest struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	ResponseType        string                 `protobuf:"bytes,1,opt,name=responseType,proto3" json:"responseType,omitempty"` // ต้องเป็น "code"
+	ClientId            string                 `protobuf:"bytes,2,opt,name=clientId,proto3" json:"clientId,omitempty"`
+	RedirectUri         string                 `protobuf:"bytes,3,opt,name=redirectUri,proto3" json:"redirectUri,omitempty"` // ไม่ระบุได้เมื่อ client ลงทะเบียนไว้ URI เดียว
+	Scope               string                 `protobuf:"bytes,4,opt,name=scope,proto3" json:"scope,omitempty"`
+	State               string                 `protobuf:"bytes,5,opt,name=state,proto3" json:"state,omitempty"`
+	Nonce               string                 `protobuf:"bytes,6,opt,name=nonce,proto3" json:"nonce,omitempty"`                             // ใส่ใน ID token
+	CodeChallenge       string                 `protobuf:"bytes,7,opt,name=codeChallenge,proto3" json:"codeChallenge,omitempty"`             // PKCE (จำเป็น)
+	CodeChallengeMethod string                 `protobuf:"bytes,8,opt,name=codeChallengeMethod,proto3" json:"codeChallengeMethod,omitempty"` // ต้องเป็น "S256"
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *AuthorizeRequest) Reset() {
+	*x = AuthorizeRequest{}
+	mi := &file_proto_oauth_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuthorizeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthorizeRequest) ProtoMessage() {}
+
+func (x *AuthorizeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oauth_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthorizeRequest.ProtoReflect.Descriptor instead.
+func (*AuthorizeRequest) Descriptor() ([]byte, []int) {
+	return file_proto_oauth_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *AuthorizeRequest) GetResponseType() string {
+	if x != nil {
+		return x.ResponseType
+	}
+	return ""
+}
+
+func (x *AuthorizeRequest) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+func (x *AuthorizeRequest) GetRedirectUri() string {
+	if x != nil {
+		return x.RedirectUri
+	}
+	return ""
+}
+
+func (x *AuthorizeRequest) GetScope() string {
+	if x != nil {
+		return x.Scope
+	}
+	return ""
+}
+
+func (x *AuthorizeRequest) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *AuthorizeRequest) GetNonce() string {
+	if x != nil {
+		return x.Nonce
+	}
+	return ""
+}
+
+func (x *AuthorizeRequest) GetCodeChallenge() string {
+	if x != nil {
+		return x.CodeChallenge
+	}
+	return ""
+}
+
+func (x *AuthorizeRequest) GetCodeChallengeMethod() string {
+	if x != nil {
+		return x.CodeChallengeMethod
+	}
+	return ""
+}
+
+// ผลของ Authorize: มี redirectTo เมื่อต้องส่ง error กลับไปยัง client ทันที
+// ไม่เช่นนั้นให้ส่งผู้ใช้ไปยังหน้าเข้าสู่ระบบพร้อม requestId
+type AuthorizeReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RequestId     string                 `protobuf:"bytes,1,opt,name=requestId,proto3" json:"requestId,omitempty"`
+	RedirectTo    string                 `protobuf:"bytes,2,opt,name=redirectTo,proto3" json:"redirectTo,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuthorizeReply) Reset() {
+	*x = AuthorizeReply{}
+	mi := &file_proto_oauth_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuthorizeReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthorizeReply) ProtoMessage() {}
+
+func (x *AuthorizeReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oauth_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthorizeReply.ProtoReflect.Descriptor instead.
+func (*AuthorizeReply) Descriptor() ([]byte, []int) {
+	return file_proto_oauth_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *AuthorizeReply) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *AuthorizeReply) GetRedirectTo() string {
+	if x != nil {
+		return x.RedirectTo
+	}
+	return ""
+}
+
+type GetAuthorizationRequestRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RequestId     string                 `protobuf:"bytes,1,opt,name=requestId,proto3" json:"requestId,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAuthorizationRequestRequest) Reset() {
+	*x = GetAuthorizationRequestRequest{}
+	mi := &file_proto_oauth_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAuthorizationRequestRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAuthorizationRequestRequest) ProtoMessage() {}
+
+func (x *GetAuthorizationRequestRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oauth_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAuthorizationRequestRequest.ProtoReflect.Descriptor instead.
+func (*GetAuthorizationRequestRequest) Descriptor() ([]byte, []int) {
+	return file_proto_oauth_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetAuthorizationRequestRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+type AuthorizationRequestInfo struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	RequestId      string                 `protobuf:"bytes,1,opt,name=requestId,proto3" json:"requestId,omitempty"`
+	ClientId       string                 `protobuf:"bytes,2,opt,name=clientId,proto3" json:"clientId,omitempty"`
+	ClientName     string                 `protobuf:"bytes,3,opt,name=clientName,proto3" json:"clientName,omitempty"`
+	Scopes         []string               `protobuf:"bytes,4,rep,name=scopes,proto3" json:"scopes,omitempty"` // scope ที่ client ขอ
+	RedirectUri    string                 `protobuf:"bytes,5,opt,name=redirectUri,proto3" json:"redirectUri,omitempty"`
+	Trusted        bool                   `protobuf:"varint,6,opt,name=trusted,proto3" json:"trusted,omitempty"`               // แอปของระบบเอง ไม่ต้องถามความยินยอม
+	ConsentGranted bool                   `protobuf:"varint,7,opt,name=consentGranted,proto3" json:"consentGranted,omitempty"` // ผู้ใช้ (ตาม token ที่แนบมา) เคยยินยอมครบทุก scope แล้ว
+	ExpiresAt      string                 `protobuf:"bytes,8,opt,name=expiresAt,proto3" json:"expiresAt,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *AuthorizationRequestInfo) Reset() {
+	*x = AuthorizationRequestInfo{}
+	mi := &file_proto_oauth_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuthorizationRequestInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthorizationRequestInfo) ProtoMessage() {}
+
+func (x *AuthorizationRequestInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oauth_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthorizationRequestInfo.ProtoReflect.Descriptor instead.
+func (*AuthorizationRequestInfo) Descriptor() ([]byte, []int) {
+	return file_proto_oauth_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *AuthorizationRequestInfo) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *AuthorizationRequestInfo) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+func (x *AuthorizationRequestInfo) GetClientName() string {
+	if x != nil {
+		return x.ClientName
+	}
+	return ""
+}
+
+func (x *AuthorizationRequestInfo) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+func (x *AuthorizationRequestInfo) GetRedirectUri() string {
+	if x != nil {
+		return x.RedirectUri
+	}
+	return ""
+}
+
+func (x *AuthorizationRequestInfo) GetTrusted() bool {
+	if x != nil {
+		return x.Trusted
+	}
+	return false
+}
+
+func (x *AuthorizationRequestInfo) GetConsentGranted() bool {
+	if x != nil {
+		return x.ConsentGranted
+	}
+	return false
+}
+
+func (x *AuthorizationRequestInfo) GetExpiresAt() string {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return ""
+}
+
+type DecideAuthorizationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RequestId     string                 `protobuf:"bytes,1,opt,name=requestId,proto3" json:"requestId,omitempty"`
+	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"` // ยืนยันตัวตนด้วยอีเมลและรหัสผ่าน (ไม่ระบุ = ใช้ token ใน metadata)
+	Password      string                 `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"`
+	Approve       bool                   `protobuf:"varint,4,opt,name=approve,proto3" json:"approve,omitempty"` // false = ผู้ใช้ปฏิเสธ
+	Scopes        []string               `protobuf:"bytes,5,rep,name=scopes,proto3" json:"scopes,omitempty"`    // scope ที่ผู้ใช้อนุญาต (ค่าว่าง = ทุก scope ที่ขอ)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DecideAuthorizationRequest) Reset() {
+	*x = DecideAuthorizationRequest{}
+	mi := &file_proto_oauth_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DecideAuthorizationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DecideAuthorizationRequest) ProtoMessage() {}
+
+func (x *DecideAuthorizationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oauth_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DecideAuthorizationRequest.ProtoReflect.Descriptor instead.
+func (*DecideAuthorizationRequest) Descriptor() ([]byte, []int) {
+	return file_proto_oauth_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *DecideAuthorizationRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *DecideAuthorizationRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *DecideAuthorizationRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *DecideAuthorizationRequest) GetApprove() bool {
+	if x != nil {
+		return x.Approve
+	}
+	return false
+}
+
+func (x *DecideAuthorizationRequest) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+type DecideAuthorizationReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RedirectTo    string                 `protobuf:"bytes,1,opt,name=redirectTo,proto3" json:"redirectTo,omitempty"` // redirect_uri ของ client พร้อม code/error, state และ iss
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DecideAuthorizationReply) Reset() {
+	*x = DecideAuthorizationReply{}
+	mi := &file_proto_oauth_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DecideAuthorizationReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DecideAuthorizationReply) ProtoMessage() {}
+
+func (x *DecideAuthorizationReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oauth_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DecideAuthorizationReply.ProtoReflect.Descriptor instead.
+func (*DecideAuthorizationReply) Descriptor() ([]byte, []int) {
+	return file_proto_oauth_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *DecideAuthorizationReply) GetRedirectTo() string {
+	if x != nil {
+		return x.RedirectTo
+	}
+	return ""
+}
+
+type UserInfoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserInfoRequest) Reset() {
+	*x = UserInfoRequest{}
+	mi := &file_proto_oauth_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserInfoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserInfoRequest) ProtoMessage() {}
+
+func (x *UserInfoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oauth_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserInfoRequest.ProtoReflect.Descriptor instead.
+func (*UserInfoRequest) Descriptor() ([]byte, []int) {
+	return file_proto_oauth_proto_rawDescGZIP(), []int{8}
+}
+
+// claim มาตรฐานของ OpenID Connect (มีเฉพาะ claim ที่อยู่ใน scope ของ token)
+type UserInfoReply struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Sub               string                 `protobuf:"bytes,1,opt,name=sub,proto3" json:"sub,omitempty"`
+	Email             string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"` // scope email
+	EmailVerified     bool                   `protobuf:"varint,3,opt,name=emailVerified,proto3" json:"emailVerified,omitempty"`
+	Name              string                 `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"` // scope profile
+	PreferredUsername string                 `protobuf:"bytes,5,opt,name=preferredUsername,proto3" json:"preferredUsername,omitempty"`
+	Picture           string                 `protobuf:"bytes,6,opt,name=picture,proto3" json:"picture,omitempty"`
+	Locale            string                 `protobuf:"bytes,7,opt,name=locale,proto3" json:"locale,omitempty"`
+	Zoneinfo          string                 `protobuf:"bytes,8,opt,name=zoneinfo,proto3" json:"zoneinfo,omitempty"`
+	UpdatedAt         int64                  `protobuf:"varint,9,opt,name=updatedAt,proto3" json:"updatedAt,omitempty"`
+	PhoneNumber       string                 `protobuf:"bytes,10,opt,name=phoneNumber,proto3" json:"phoneNumber,omitempty"` // scope phone
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *UserInfoReply) Reset() {
+	*x = UserInfoReply{}
+	mi := &file_proto_oauth_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserInfoReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserInfoReply) ProtoMessage() {}
+
+func (x *UserInfoReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oauth_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserInfoReply.ProtoReflect.Descriptor instead.
+func (*UserInfoReply) Descriptor() ([]byte, []int) {
+	return file_proto_oauth_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *UserInfoReply) GetSub() string {
+	if x != nil {
+		return x.Sub
+	}
+	return ""
+}
+
+func (x *UserInfoReply) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *UserInfoReply) GetEmailVerified() bool {
+	if x != nil {
+		return x.EmailVerified
+	}
+	return false
+}
+
+func (x *UserInfoReply) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UserInfoReply) GetPreferredUsername() string {
+	if x != nil {
+		return x.PreferredUsername
+	}
+	return ""
+}
+
+func (x *UserInfoReply) GetPicture() string {
+	if x != nil {
+		return x.Picture
+	}
+	return ""
+}
+
+func (x *UserInfoReply) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+func (x *UserInfoReply) GetZoneinfo() string {
+	if x != nil {
+		return x.Zoneinfo
+	}
+	return ""
+}
+
+func (x *UserInfoReply) GetUpdatedAt() int64 {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return 0
+}
+
+func (x *UserInfoReply) GetPhoneNumber() string {
+	if x != nil {
+		return x.PhoneNumber
+	}
+	return ""
+}
+
+type RevokeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	TokenTypeHint string                 `protobuf:"bytes,2,opt,name=tokenTypeHint,proto3" json:"tokenTypeHint,omitempty"` // "access_token" หรือ "refresh_token"
+	ClientId      string                 `protobuf:"bytes,3,opt,name=clientId,proto3" json:"clientId,omitempty"`
+	ClientSecret  string                 `protobuf:"bytes,4,opt,name=clientSecret,proto3" json:"clientSecret,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeRequest) Reset() {
+	*x = RevokeRequest{}
+	mi := &file_proto_oauth_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeRequest) ProtoMessage() {}
+
+func (x *RevokeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oauth_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeRequest.ProtoReflect.Descriptor instead.
+func (*RevokeRequest) Descriptor() ([]byte, []int) {
+	return file_proto_oauth_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *RevokeRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *RevokeRequest) GetTokenTypeHint() string {
+	if x != nil {
+		return x.TokenTypeHint
+	}
+	return ""
+}
+
+func (x *RevokeRequest) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+func (x *RevokeRequest) GetClientSecret() string {
+	if x != nil {
+		return x.ClientSecret
+	}
+	return ""
+}
+
+type RevokeReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeReply) Reset() {
+	*x = RevokeReply{}
+	mi := &file_proto_oauth_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeReply) ProtoMessage() {}
+
+func (x *RevokeReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oauth_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeReply.ProtoReflect.Descriptor instead.
+func (*RevokeReply) Descriptor() ([]byte, []int) {
+	return file_proto_oauth_proto_rawDescGZIP(), []int{11}
+}
+
+type IntrospectRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	TokenTypeHint string                 `protobuf:"bytes,2,opt,name=tokenTypeHint,proto3" json:"tokenTypeHint,omitempty"`
+	ClientId      string                 `protobuf:"bytes,3,opt,name=clientId,proto3" json:"clientId,omitempty"`
+	ClientSecret  string                 `protobuf:"bytes,4,opt,name=clientSecret,proto3" json:"clientSecret,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IntrospectRequest) Reset() {
+	*x = IntrospectRequest{}
+	mi := &file_proto_oauth_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IntrospectRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IntrospectRequest) ProtoMessage() {}
+
+func (x *IntrospectRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oauth_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IntrospectRequest.ProtoReflect.Descriptor instead.
+func (*IntrospectRequest) Descriptor() ([]byte, []int) {
+	return file_proto_oauth_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *IntrospectRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *IntrospectRequest) GetTokenTypeHint() string {
+	if x != nil {
+		return x.TokenTypeHint
+	}
+	return ""
+}
+
+func (x *IntrospectRequest) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+func (x *IntrospectRequest) GetClientSecret() string {
+	if x != nil {
+		return x.ClientSecret
+	}
+	return ""
+}
+
+// สถานะของ token (active = false โดยไม่มีข้อมูลอื่นเมื่อ token ใช้ไม่ได้)
+type IntrospectReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Active        bool                   `protobuf:"varint,1,opt,name=active,proto3" json:"active,omitempty"`
+	Scope         string                 `protobuf:"bytes,2,opt,name=scope,proto3" json:"scope,omitempty"`
+	ClientId      string                 `protobuf:"bytes,3,opt,name=clientId,proto3" json:"clientId,omitempty"`
+	Username      string                 `protobuf:"bytes,4,opt,name=username,proto3" json:"username,omitempty"`   // อีเมลของผู้ใช้เจ้าของ token
+	TokenType     string                 `protobuf:"bytes,5,opt,name=tokenType,proto3" json:"tokenType,omitempty"` // "access_token" หรือ "refresh_token"
+	Exp           int64                  `protobuf:"varint,6,opt,name=exp,proto3" json:"exp,omitempty"`
+	Iat           int64                  `protobuf:"varint,7,opt,name=iat,proto3" json:"iat,omitempty"`
+	Sub           string                 `protobuf:"bytes,8,opt,name=sub,proto3" json:"sub,omitempty"`
+	Iss           string                 `protobuf:"bytes,9,opt,name=iss,proto3" json:"iss,omitempty"`
+	TenantId      string                 `protobuf:"bytes,10,opt,name=tenantId,proto3" json:"tenantId,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IntrospectReply) Reset() {
+	*x = IntrospectReply{}
+	mi := &file_proto_oauth_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IntrospectReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IntrospectReply) ProtoMessage() {}
+
+func (x *IntrospectReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oauth_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IntrospectReply.ProtoReflect.Descriptor instead.
+func (*IntrospectReply) Descriptor() ([]byte, []int) {
+	return file_proto_oauth_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *IntrospectReply) GetActive() bool {
+	if x != nil {
+		return x.Active
+	}
+	return false
+}
+
+func (x *IntrospectReply) GetScope() string {
+	if x != nil {
+		return x.Scope
+	}
+	return ""
+}
+
+func (x *IntrospectReply) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+func (x *IntrospectReply) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *IntrospectReply) GetTokenType() string {
+	if x != nil {
+		return x.TokenType
+	}
+	return ""
+}
+
+func (x *IntrospectReply) GetExp() int64 {
+	if x != nil {
+		return x.Exp
+	}
+	return 0
+}
+
+func (x *IntrospectReply) GetIat() int64 {
+	if x != nil {
+		return x.Iat
+	}
+	return 0
+}
+
+func (x *IntrospectReply) GetSub() string {
+	if x != nil {
+		return x.Sub
+	}
+	return ""
+}
+
+func (x *IntrospectReply) GetIss() string {
+	if x != nil {
+		return x.Iss
+	}
+	return ""
+}
+
+func (x *IntrospectReply) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+var File_proto_oauth_proto protoreflect.FileDescriptor
+
+const file_proto_oauth_proto_rawDesc = "" +
+	"\n" +
+	"\x11proto/oauth.proto\"\xdc\x02\n" +
+	"\fTokenRequest\x12\x1c\n" +
+	"\tgrantType\x18\x01 \x01(\tR\tgrantType\x12\x1a\n" +
+	"\bclientId\x18\x02 \x01(\tR\bclientId\x12\"\n" +
+	"\fclientSecret\x18\x03 \x01(\tR\fclientSecret\x120\n" +
+	"\x13clientAssertionType\x18\x04 \x01(\tR\x13clientAssertionType\x12(\n" +
+	"\x0fclientAssertion\x18\x05 \x01(\tR\x0fclientAssertion\x12\x14\n" +
+	"\x05scope\x18\x06 \x01(\tR\x05scope\x12\x12\n" +
+	"\x04code\x18\a \x01(\tR\x04code\x12 \n" +
+	"\vredirectUri\x18\b \x01(\tR\vredirectUri\x12\"\n" +
+	"\fcodeVerifier\x18\t \x01(\tR\fcodeVerifier\x12\"\n" +
+	"\frefreshToken\x18\n" +
+	" \x01(\tR\frefreshToken\"\xbe\x01\n" +
+	"\n" +
+	"TokenReply\x12 \n" +
+	"\vaccessToken\x18\x01 \x01(\tR\vaccessToken\x12\x1c\n" +
+	"\ttokenType\x18\x02 \x01(\tR\ttokenType\x12\x1c\n" +
+	"\texpiresIn\x18\x03 \x01(\x03R\texpiresIn\x12\x14\n" +
+	"\x05scope\x18\x04 \x01(\tR\x05scope\x12\x18\n" +
+	"\aidToken\x18\x05 \x01(\tR\aidToken\x12\"\n" +
+	"\frefreshToken\x18\x06 \x01(\tR\frefreshToken\"\x8e\x02\n" +
+	"\x10AuthorizeRequest\x12\"\n" +
+	"\fresponseType\x18\x01 \x01(\tR\fresponseType\x12\x1a\n" +
+	"\bclientId\x18\x02 \x01(\tR\bclientId\x12 \n" +
+	"\vredirectUri\x18\x03 \x01(\tR\vredirectUri\x12\x14\n" +
+	"\x05scope\x18\x04 \x01(\tR\x05scope\x12\x14\n" +
+	"\x05state\x18\x05 \x01(\tR\x05state\x12\x14\n" +
+	"\x05nonce\x18\x06 \x01(\tR\x05nonce\x12$\n" +
+	"\rcodeChallenge\x18\a \x01(\tR\rcodeChallenge\x120\n" +
+	"\x13codeChallengeMethod\x18\b \x01(\tR\x13codeChallengeMethod\"N\n" +
+	"\x0eAuthorizeReply\x12\x1c\n" +
+	"\trequestId\x18\x01 \x01(\tR\trequestId\x12\x1e\n" +
+	"\n" +
+	"redirectTo\x18\x02 \x01(\tR\n" +
+	"redirectTo\">\n" +
+	"\x1eGetAuthorizationRequestRequest\x12\x1c\n" +
+	"\trequestId\x18\x01 \x01(\tR\trequestId\"\x8e\x02\n" +
+	"\x18AuthorizationRequestInfo\x12\x1c\n" +
+	"\trequestId\x18\x01 \x01(\tR\trequestId\x12\x1a\n" +
+	"\bclientId\x18\x02 \x01(\tR\bclientId\x12\x1e\n" +
+	"\n" +
+	"clientName\x18\x03 \x01(\tR\n" +
+	"clientName\x12\x16\n" +
+	"\x06scopes\x18\x04 \x03(\tR\x06scopes\x12 \n" +
+	"\vredirectUri\x18\x05 \x01(\tR\vredirectUri\x12\x18\n" +
+	"\atrusted\x18\x06 \x01(\bR\atrusted\x12&\n" +
+	"\x0econsentGranted\x18\a \x01(\bR\x0econsentGranted\x12\x1c\n" +
+	"\texpiresAt\x18\b \x01(\tR\texpiresAt\"\x9e\x01\n" +
+	"\x1aDecideAuthorizationRequest\x12\x1c\n" +
+	"\trequestId\x18\x01 \x01(\tR\trequestId\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\x12\x1a\n" +
+	"\bpassword\x18\x03 \x01(\tR\bpassword\x12\x18\n" +
+	"\aapprove\x18\x04 \x01(\bR\aapprove\x12\x16\n" +
+	"\x06scopes\x18\x05 \x03(\tR\x06scopes\":\n" +
+	"\x18DecideAuthorizationReply\x12\x1e\n" +
+	"\n" +
+	"redirectTo\x18\x01 \x01(\tR\n" +
+	"redirectTo\"\x11\n" +
+	"\x0fUserInfoRequest\"\xad\x02\n" +
+	"\rUserInfoReply\x12\x10\n" +
+	"\x03sub\x18\x01 \x01(\tR\x03sub\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\x12$\n" +
+	"\remailVerified\x18\x03 \x01(\bR\remailVerified\x12\x12\n" +
+	"\x04name\x18\x04 \x01(\tR\x04name\x12,\n" +
+	"\x11preferredUsername\x18\x05 \x01(\tR\x11preferredUsername\x12\x18\n" +
+	"\apicture\x18\x06 \x01(\tR\apicture\x12\x16\n" +
+	"\x06locale\x18\a \x01(\tR\x06locale\x12\x1a\n" +
+	"\bzoneinfo\x18\b \x01(\tR\bzoneinfo\x12\x1c\n" +
+	"\tupdatedAt\x18\t \x01(\x03R\tupdatedAt\x12 \n" +
+	"\vphoneNumber\x18\n" +
+	" \x01(\tR\vphoneNumber\"\x8b\x01\n" +
+	"\rRevokeRequest\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12$\n" +
+	"\rtokenTypeHint\x18\x02 \x01(\tR\rtokenTypeHint\x12\x1a\n" +
+	"\bclientId\x18\x03 \x01(\tR\bclientId\x12\"\n" +
+	"\fclientSecret\x18\x04 \x01(\tR\fclientSecret\"\r\n" +
+	"\vRevokeReply\"\x8f\x01\n" +
+	"\x11IntrospectRequest\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12$\n" +
+	"\rtokenTypeHint\x18\x02 \x01(\tR\rtokenTypeHint\x12\x1a\n" +
+	"\bclientId\x18\x03 \x01(\tR\bclientId\x12\"\n" +
+	"\fclientSecret\x18\x04 \x01(\tR\fclientSecret\"\xf9\x01\n" +
+	"\x0fIntrospectReply\x12\x16\n" +
+	"\x06active\x18\x01 \x01(\bR\x06active\x12\x14\n" +
+	"\x05scope\x18\x02 \x01(\tR\x05scope\x12\x1a\n" +
+	"\bclientId\x18\x03 \x01(\tR\bclientId\x12\x1a\n" +
+	"\busername\x18\x04 \x01(\tR\busername\x12\x1c\n" +
+	"\ttokenType\x18\x05 \x01(\tR\ttokenType\x12\x10\n" +
+	"\x03exp\x18\x06 \x01(\x03R\x03exp\x12\x10\n" +
+	"\x03iat\x18\a \x01(\x03R\x03iat\x12\x10\n" +
+	"\x03sub\x18\b \x01(\tR\x03sub\x12\x10\n" +
+	"\x03iss\x18\t \x01(\tR\x03iss\x12\x1a\n" +
+	"\btenantId\x18\n" +
+	" \x01(\tR\btenantId2\xa2\x03\n" +
+	"\fOAuthService\x12%\n" +
+	"\x05Token\x12\r.TokenRequest\x1a\v.TokenReply\"\x00\x121\n" +
+	"\tAuthorize\x12\x11.AuthorizeRequest\x1a\x0f.AuthorizeReply\"\x00\x12W\n" +
+	"\x17GetAuthorizationRequest\x12\x1f.GetAuthorizationRequestRequest\x1a\x19.AuthorizationRequestInfo\"\x00\x12O\n" +
+	"\x13DecideAuthorization\x12\x1b.DecideAuthorizationRequest\x1a\x19.DecideAuthorizationReply\"\x00\x12.\n" +
+	"\bUserInfo\x12\x10.UserInfoRequest\x1a\x0e.UserInfoReply\"\x00\x12(\n" +
+	"\x06Revoke\x12\x0e.RevokeRequest\x1a\f.RevokeReply\"\x00\x124\n" +
+	"\n" +
+	"Introspect\x12\x12.IntrospectRequest\x1a\x10.IntrospectReply\"\x00B\x19Z\x17auth-microservice/protob\x06proto3"
+
+var (
+	file_proto_oauth_proto_rawDescOnce sync.Once
+	file_proto_oauth_proto_rawDescData []byte
+)
+
+func file_proto_oauth_proto_rawDescGZIP() []byte {
+	file_proto_oauth_proto_rawDescOnce.Do(func() {
+		file_proto_oauth_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_oauth_proto_rawDesc), len(file_proto_oauth_proto_rawDesc)))
+	})
+	return file_proto_oauth_proto_rawDescData
+}
+
+var file_proto_oauth_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
+var file_proto_oauth_proto_goTypes = []any{
+	(*TokenRequest)(nil),                   // 0: TokenRequest
+	(*TokenReply)(nil),                     // 1: TokenReply
+	(*AuthorizeRequest)(nil),               // 2: AuthorizeRequest
+	(*AuthorizeReply)(nil),                 // 3: AuthorizeReply
+	(*GetAuthorizationRequestRequest)(nil), // 4: GetAuthorizationRequestRequest
+	(*AuthorizationRequestInfo)(nil),       // 5: AuthorizationRequestInfo
+	(*DecideAuthorizationRequest)(nil),     // 6: DecideAuthorizationRequest
+	(*DecideAuthorizationReply)(nil),       // 7: DecideAuthorizationReply
+	(*UserInfoRequest)(nil),                // 8: UserInfoRequest
+	(*UserInfoReply)(nil),                  // 9: UserInfoReply
+	(*RevokeRequest)(nil),                  // 10: RevokeRequest
+	(*RevokeReply)(nil),                    // 11: RevokeReply
+	(*IntrospectRequest)(nil),              // 12: IntrospectRequest
+	(*IntrospectReply)(nil),                // 13: IntrospectReply
+}
+var file_proto_oauth_proto_depIdxs = []int32{
+	0,  // 0: OAuthService.Token:input_type -> TokenRequest
+	2,  // 1: OAuthService.Authorize:input_type -> AuthorizeRequest
+	4,  // 2: OAuthService.GetAuthorizationRequest:input_type -> GetAuthorizationRequestRequest
+	6,  // 3: OAuthService.DecideAuthorization:input_type -> DecideAuthorizationRequest
+	8,  // 4: OAuthService.UserInfo:input_type -> UserInfoRequest
+	10, // 5: OAuthService.Revoke:input_type -> RevokeRequest
+	12, // 6: OAuthService.Introspect:input_type -> IntrospectRequest
+	1,  // 7: OAuthService.Token:output_type -> TokenReply
+	3,  // 8: OAuthService.Authorize:output_type -> AuthorizeReply
+	5,  // 9: OAuthService.GetAuthorizationRequest:output_type -> AuthorizationRequestInfo
+	7,  // 10: OAuthService.DecideAuthorization:output_type -> DecideAuthorizationReply
+	9,  // 11: OAuthService.UserInfo:output_type -> UserInfoReply
+	11, // 12: OAuthService.Revoke:output_type -> RevokeReply
+	13, // 13: OAuthService.Introspect:output_type -> IntrospectReply
+	7,  // [7:14] is the sub-list for method output_type
+	0,  // [0:7] is the sub-list for method input_type
+	0,  // [0:0] is the sub-list for extension type_name
+	0,  // [0:0] is the sub-list for extension extendee
+	0,  // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_proto_oauth_proto_init() }
+func file_proto_oauth_proto_init() {
+	if File_proto_oauth_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_oauth_proto_rawDesc), len(file_proto_oauth_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   14,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_oauth_proto_goTypes,
+		DependencyIndexes: file_proto_oauth_proto_depIdxs,
+		MessageInfos:      file_proto_oauth_proto_msgTypes,
+	}.Build()
+	File_proto_oauth_proto = out.File
+	file_proto_oauth_proto_goTypes = nil
+	file_proto_oauth_proto_depIdxs = nil
+}
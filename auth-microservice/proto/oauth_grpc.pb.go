@@ -0,0 +1,375 @@
+// กำหนด version ของ Protocol Buffers ที่ใช้
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v6.31.1
+// source: proto/oauth.proto
+
+package proto
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	OAuthService_Token_FullMethodName                   = "/OAuthService/Token"
+	OAuthService_Authorize_FullMethodName               = "/OAuthService/Authorize"
+	OAuthService_GetAuthorizationRequest_FullMethodName = "/OAuthService/GetAuthorizationRequest"
+	OAuthService_DecideAuthorization_FullMethodName     = "/OAuthService/DecideAuthorization"
+	OAuthService_UserInfo_FullMethodName                = "/OAuthService/UserInfo"
+	OAuthService_Revoke_FullMethodName                  = "/OAuthService/Revoke"
+	OAuthService_Introspect_FullMethodName              = "/OAuthService/Introspect"
+)
+
+// OAuthServiceClient is the client API for OAuthService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// บริการ OAuthService เป็น OAuth 2.1 / OpenID Connect provider
+// ใช้ได้ทั้งผ่าน gRPC และ HTTP (/oauth/authorize, /oauth/token, /oauth/userinfo, /oauth/revoke, /oauth/introspect)
+type OAuthServiceClient interface {
+	// แลก credential เป็น token (grant_type client_credentials, authorization_code หรือ refresh_token)
+	Token(ctx context.Context, in *TokenRequest, opts ...grpc.CallOption) (*TokenReply, error)
+	// เริ่ม authorization code flow: ตรวจสอบ request ของ client แล้วเก็บไว้ให้หน้าเข้าสู่ระบบ/ขอความยินยอมใช้ต่อ
+	Authorize(ctx context.Context, in *AuthorizeRequest, opts ...grpc.CallOption) (*AuthorizeReply, error)
+	// ข้อมูลของ authorization request สำหรับแสดงในหน้าขอความยินยอม
+	// แนบ token ของผู้ใช้ใน metadata "authorization" เพื่อดูว่าผู้ใช้เคยยินยอมครบทุก scope แล้วหรือไม่
+	GetAuthorizationRequest(ctx context.Context, in *GetAuthorizationRequestRequest, opts ...grpc.CallOption) (*AuthorizationRequestInfo, error)
+	// ผู้ใช้อนุญาตหรือปฏิเสธ client (ยืนยันตัวตนด้วยอีเมล/รหัสผ่าน หรือ token ของผู้ใช้ใน metadata)
+	// คืน URL ที่ต้อง redirect กลับไปยัง client พร้อม code หรือ error
+	DecideAuthorization(ctx context.Context, in *DecideAuthorizationRequest, opts ...grpc.CallOption) (*DecideAuthorizationReply, error)
+	// ข้อมูลผู้ใช้ตาม scope ของ access token ใน metadata "authorization" (ต้องมี scope openid)
+	UserInfo(ctx context.Context, in *UserInfoRequest, opts ...grpc.CallOption) (*UserInfoReply, error)
+	// ยกเลิก access token หรือ refresh token ของ client (RFC 7009)
+	Revoke(ctx context.Context, in *RevokeRequest, opts ...grpc.CallOption) (*RevokeReply, error)
+	// ตรวจสอบสถานะของ token สำหรับ resource server (RFC 7662) ต้องเป็น confidential client
+	Introspect(ctx context.Context, in *IntrospectRequest, opts ...grpc.CallOption) (*IntrospectReply, error)
+}
+
+type oAuthServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewOAuthServiceClient(cc grpc.ClientConnInterface) OAuthServiceClient {
+	return &oAuthServiceClient{cc}
+}
+
+func (c *oAuthServiceClient) Token(ctx context.Context, in *TokenRequest, opts ...grpc.CallOption) (*TokenReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TokenReply)
+	err := c.cc.Invoke(ctx, OAuthService_Token_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *oAuthServiceClient) Authorize(ctx context.Context, in *AuthorizeRequest, opts ...grpc.CallOption) (*AuthorizeReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AuthorizeReply)
+	err := c.cc.Invoke(ctx, OAuthService_Authorize_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *oAuthServiceClient) GetAuthorizationRequest(ctx context.Context, in *GetAuthorizationRequestRequest, opts ...grpc.CallOption) (*AuthorizationRequestInfo, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AuthorizationRequestInfo)
+	err := c.cc.Invoke(ctx, OAuthService_GetAuthorizationRequest_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *oAuthServiceClient) DecideAuthorization(ctx context.Context, in *DecideAuthorizationRequest, opts ...grpc.CallOption) (*DecideAuthorizationReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DecideAuthorizationReply)
+	err := c.cc.Invoke(ctx, OAuthService_DecideAuthorization_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *oAuthServiceClient) UserInfo(ctx context.Context, in *UserInfoRequest, opts ...grpc.CallOption) (*UserInfoReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UserInfoReply)
+	err := c.cc.Invoke(ctx, OAuthService_UserInfo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *oAuthServiceClient) Revoke(ctx context.Context, in *RevokeRequest, opts ...grpc.CallOption) (*RevokeReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RevokeReply)
+	err := c.cc.Invoke(ctx, OAuthService_Revoke_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *oAuthServiceClient) Introspect(ctx context.Context, in *IntrospectRequest, opts ...grpc.CallOption) (*IntrospectReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(IntrospectReply)
+	err := c.cc.Invoke(ctx, OAuthService_Introspect_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// OAuthServiceServer is the server API for OAuthService service.
+// All implementations must embed UnimplementedOAuthServiceServer
+// for forward compatibility.
+//
+// บริการ OAuthService เป็น OAuth 2.1 / OpenID Connect provider
+// ใช้ได้ทั้งผ่าน gRPC และ HTTP (/oauth/authorize, /oauth/token, /oauth/userinfo, /oauth/revoke, /oauth/introspect)
+type OAuthServiceServer interface {
+	// แลก credential เป็น token (grant_type client_credentials, authorization_code หรือ refresh_token)
+	Token(context.Context, *TokenRequest) (*TokenReply, error)
+	// เริ่ม authorization code flow: ตรวจสอบ request ของ client แล้วเก็บไว้ให้หน้าเข้าสู่ระบบ/ขอความยินยอมใช้ต่อ
+	Authorize(context.Context, *AuthorizeRequest) (*AuthorizeReply, error)
+	// ข้อมูลของ authorization request สำหรับแสดงในหน้าขอความยินยอม
+	// แนบ token ของผู้ใช้ใน metadata "authorization" เพื่อดูว่าผู้ใช้เคยยินยอมครบทุก scope แล้วหรือไม่
+	GetAuthorizationRequest(context.Context, *GetAuthorizationRequestRequest) (*AuthorizationRequestInfo, error)
+	// ผู้ใช้อนุญาตหรือปฏิเสธ client (ยืนยันตัวตนด้วยอีเมล/รหัสผ่าน หรือ token ของผู้ใช้ใน metadata)
+	// คืน URL ที่ต้อง redirect กลับไปยัง client พร้อม code หรือ error
+	DecideAuthorization(context.Context, *DecideAuthorizationRequest) (*DecideAuthorizationReply, error)
+	// ข้อมูลผู้ใช้ตาม scope ของ access token ใน metadata "authorization" (ต้องมี scope openid)
+	UserInfo(context.Context, *UserInfoRequest) (*UserInfoReply, error)
+	// ยกเลิก access token หรือ refresh token ของ client (RFC 7009)
+	Revoke(context.Context, *RevokeRequest) (*RevokeReply, error)
+	// ตรวจสอบสถานะของ token สำหรับ resource server (RFC 7662) ต้องเป็น confidential client
+	Introspect(context.Context, *IntrospectRequest) (*IntrospectReply, error)
+	mustEmbedUnimplementedOAuthServiceServer()
+}
+
+// UnimplementedOAuthServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedOAuthServiceServer struct{}
+
+func (UnimplementedOAuthServiceServer) Token(context.Context, *TokenRequest) (*TokenReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Token not implemented")
+}
+func (UnimplementedOAuthServiceServer) Authorize(context.Context, *AuthorizeRequest) (*AuthorizeReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Authorize not implemented")
+}
+func (UnimplementedOAuthServiceServer) GetAuthorizationRequest(context.Context, *GetAuthorizationRequestRequest) (*AuthorizationRequestInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAuthorizationRequest not implemented")
+}
+func (UnimplementedOAuthServiceServer) DecideAuthorization(context.Context, *DecideAuthorizationRequest) (*DecideAuthorizationReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DecideAuthorization not implemented")
+}
+func (UnimplementedOAuthServiceServer) UserInfo(context.Context, *UserInfoRequest) (*UserInfoReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UserInfo not implemented")
+}
+func (UnimplementedOAuthServiceServer) Revoke(context.Context, *RevokeRequest) (*RevokeReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Revoke not implemented")
+}
+func (UnimplementedOAuthServiceServer) Introspect(context.Context, *IntrospectRequest) (*IntrospectReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Introspect not implemented")
+}
+func (UnimplementedOAuthServiceServer) mustEmbedUnimplementedOAuthServiceServer() {}
+func (UnimplementedOAuthServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeOAuthServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to OAuthServiceServer will
+// result in compilation errors.
+type UnsafeOAuthServiceServer interface {
+	mustEmbedUnimplementedOAuthServiceServer()
+}
+
+func RegisterOAuthServiceServer(s grpc.ServiceRegistrar, srv OAuthServiceServer) {
+	// If the following call pancis, it indicates UnimplementedOAuthServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&OAuthService_ServiceDesc, srv)
+}
+
+func _OAuthService_Token_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OAuthServiceServer).Token(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OAuthService_Token_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OAuthServiceServer).Token(ctx, req.(*TokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OAuthService_Authorize_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuthorizeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OAuthServiceServer).Authorize(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OAuthService_Authorize_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OAuthServiceServer).Authorize(ctx, req.(*AuthorizeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OAuthService_GetAuthorizationRequest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAuthorizationRequestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OAuthServiceServer).GetAuthorizationRequest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OAuthService_GetAuthorizationRequest_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OAuthServiceServer).GetAuthorizationRequest(ctx, req.(*GetAuthorizationRequestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OAuthService_DecideAuthorization_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DecideAuthorizationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OAuthServiceServer).DecideAuthorization(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OAuthService_DecideAuthorization_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OAuthServiceServer).DecideAuthorization(ctx, req.(*DecideAuthorizationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OAuthService_UserInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OAuthServiceServer).UserInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OAuthService_UserInfo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OAuthServiceServer).UserInfo(ctx, req.(*UserInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OAuthService_Revoke_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OAuthServiceServer).Revoke(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OAuthService_Revoke_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OAuthServiceServer).Revoke(ctx, req.(*RevokeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OAuthService_Introspect_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IntrospectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OAuthServiceServer).Introspect(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OAuthService_Introspect_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OAuthServiceServer).Introspect(ctx, req.(*IntrospectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// OAuthService_ServiceDesc is the grpc.ServiceDesc for OAuthService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var OAuthService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "OAuthService",
+	HandlerType: (*OAuthServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Token",
+			Handler:    _OAuthService_Token_Handler,
+		},
+		{
+			MethodName: "Authorize",
+			Handler:    _OAuthService_Authorize_Handler,
+		},
+		{
+			MethodName: "GetAuthorizationRequest",
+			Handler:    _OAuthService_GetAuthorizationRequest_Handler,
+		},
+		{
+			MethodName: "DecideAuthorization",
+			Handler:    _OAuthService_DecideAuthorization_Handler,
+		},
+		{
+			MethodName: "UserInfo",
+			Handler:    _OAuthService_UserInfo_Handler,
+		},
+		{
+			MethodName: "Revoke",
+			Handler:    _OAuthService_Revoke_Handler,
+		},
+		{
+			MethodName: "Introspect",
+			Handler:    _OAuthService_Introspect_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/oauth.proto",
+}
@@ -0,0 +1,1015 @@
+// กำหนด version ของ Protocol Buffers ที่ใช้
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v6.31.1
+// source: proto/oauthclient.proto
+
+package proto
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type OAuthClient struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ClientId      string                 `protobuf:"bytes,2,opt,name=clientId,proto3" json:"clientId,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	RedirectUris  []string               `protobuf:"bytes,4,rep,name=redirectUris,proto3" json:"redirectUris,omitempty"`
+	Scopes        []string               `protobuf:"bytes,5,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	Public        bool                   `protobuf:"varint,6,opt,name=public,proto3" json:"public,omitempty"`   // ไม่มี client secret ต้องใช้ PKCE (SPA, mobile)
+	Trusted       bool                   `protobuf:"varint,7,opt,name=trusted,proto3" json:"trusted,omitempty"` // แอปของระบบเอง ข้ามหน้าขอความยินยอม
+	Secrets       []*ClientSecret        `protobuf:"bytes,8,rep,name=secrets,proto3" json:"secrets,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,9,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+	UpdatedAt     string                 `protobuf:"bytes,10,opt,name=updatedAt,proto3" json:"updatedAt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OAuthClient) Reset() {
+	*x = OAuthClient{}
+	mi := &file_proto_oauthclient_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OAuthClient) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OAuthClient) ProtoMessage() {}
+
+func (x *OAuthClient) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oauthclient_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OAuthClient.ProtoReflect.Descriptor instead.
+func (*OAuthClient) Descriptor() ([]byte, []int) {
+	return file_proto_oauthclient_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *OAuthClient) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *OAuthClient) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+func (x *OAuthClient) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *OAuthClient) GetRedirectUris() []string {
+	if x != nil {
+		return x.RedirectUris
+	}
+	return nil
+}
+
+func (x *OAuthClient) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+func (x *OAuthClient) GetPublic() bool {
+	if x != nil {
+		return x.Public
+	}
+	return false
+}
+
+func (x *OAuthClient) GetTrusted() bool {
+	if x != nil {
+		return x.Trusted
+	}
+	return false
+}
+
+func (x *OAuthClient) GetSecrets() []*ClientSecret {
+	if x != nil {
+		return x.Secrets
+	}
+	return nil
+}
+
+func (x *OAuthClient) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *OAuthClient) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+type CreateOAuthClientRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	RedirectUris  []string               `protobuf:"bytes,2,rep,name=redirectUris,proto3" json:"redirectUris,omitempty"` // https หรือ http://localhost เท่านั้น
+	Scopes        []string               `protobuf:"bytes,3,rep,name=scopes,proto3" json:"scopes,omitempty"`             // openid, profile, email, phone, offline_access และ scope ของ API
+	Public        bool                   `protobuf:"varint,4,opt,name=public,proto3" json:"public,omitempty"`
+	Trusted       bool                   `protobuf:"varint,5,opt,name=trusted,proto3" json:"trusted,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateOAuthClientRequest) Reset() {
+	*x = CreateOAuthClientRequest{}
+	mi := &file_proto_oauthclient_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateOAuthClientRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateOAuthClientRequest) ProtoMessage() {}
+
+func (x *CreateOAuthClientRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oauthclient_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateOAuthClientRequest.ProtoReflect.Descriptor instead.
+func (*CreateOAuthClientRequest) Descriptor() ([]byte, []int) {
+	return file_proto_oauthclient_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateOAuthClientRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateOAuthClientRequest) GetRedirectUris() []string {
+	if x != nil {
+		return x.RedirectUris
+	}
+	return nil
+}
+
+func (x *CreateOAuthClientRequest) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+func (x *CreateOAuthClientRequest) GetPublic() bool {
+	if x != nil {
+		return x.Public
+	}
+	return false
+}
+
+func (x *CreateOAuthClientRequest) GetTrusted() bool {
+	if x != nil {
+		return x.Trusted
+	}
+	return false
+}
+
+type CreateOAuthClientReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Client        *OAuthClient           `protobuf:"bytes,1,opt,name=client,proto3" json:"client,omitempty"`
+	ClientSecret  string                 `protobuf:"bytes,2,opt,name=clientSecret,proto3" json:"clientSecret,omitempty"` // ค่าว่างสำหรับ public client
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateOAuthClientReply) Reset() {
+	*x = CreateOAuthClientReply{}
+	mi := &file_proto_oauthclient_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateOAuthClientReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateOAuthClientReply) ProtoMessage() {}
+
+func (x *CreateOAuthClientReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oauthclient_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateOAuthClientReply.ProtoReflect.Descriptor instead.
+func (*CreateOAuthClientReply) Descriptor() ([]byte, []int) {
+	return file_proto_oauthclient_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateOAuthClientReply) GetClient() *OAuthClient {
+	if x != nil {
+		return x.Client
+	}
+	return nil
+}
+
+func (x *CreateOAuthClientReply) GetClientSecret() string {
+	if x != nil {
+		return x.ClientSecret
+	}
+	return ""
+}
+
+type GetOAuthClientRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOAuthClientRequest) Reset() {
+	*x = GetOAuthClientRequest{}
+	mi := &file_proto_oauthclient_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOAuthClientRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOAuthClientRequest) ProtoMessage() {}
+
+func (x *GetOAuthClientRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oauthclient_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOAuthClientRequest.ProtoReflect.Descriptor instead.
+func (*GetOAuthClientRequest) Descriptor() ([]byte, []int) {
+	return file_proto_oauthclient_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetOAuthClientRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ListOAuthClientsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOAuthClientsRequest) Reset() {
+	*x = ListOAuthClientsRequest{}
+	mi := &file_proto_oauthclient_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOAuthClientsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOAuthClientsRequest) ProtoMessage() {}
+
+func (x *ListOAuthClientsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oauthclient_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOAuthClientsRequest.ProtoReflect.Descriptor instead.
+func (*ListOAuthClientsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_oauthclient_proto_rawDescGZIP(), []int{4}
+}
+
+type ListOAuthClientsReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Clients       []*OAuthClient         `protobuf:"bytes,1,rep,name=clients,proto3" json:"clients,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOAuthClientsReply) Reset() {
+	*x = ListOAuthClientsReply{}
+	mi := &file_proto_oauthclient_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOAuthClientsReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOAuthClientsReply) ProtoMessage() {}
+
+func (x *ListOAuthClientsReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oauthclient_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOAuthClientsReply.ProtoReflect.Descriptor instead.
+func (*ListOAuthClientsReply) Descriptor() ([]byte, []int) {
+	return file_proto_oauthclient_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListOAuthClientsReply) GetClients() []*OAuthClient {
+	if x != nil {
+		return x.Clients
+	}
+	return nil
+}
+
+// ฟิลด์ที่ว่างหรือไม่ได้ส่งจะไม่ถูกแก้ไข (ยกเว้น trusted ที่แก้ทุกครั้ง)
+type UpdateOAuthClientRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	RedirectUris  []string               `protobuf:"bytes,3,rep,name=redirectUris,proto3" json:"redirectUris,omitempty"`
+	Scopes        []string               `protobuf:"bytes,4,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	Trusted       bool                   `protobuf:"varint,5,opt,name=trusted,proto3" json:"trusted,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateOAuthClientRequest) Reset() {
+	*x = UpdateOAuthClientRequest{}
+	mi := &file_proto_oauthclient_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateOAuthClientRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateOAuthClientRequest) ProtoMessage() {}
+
+func (x *UpdateOAuthClientRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oauthclient_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateOAuthClientRequest.ProtoReflect.Descriptor instead.
+func (*UpdateOAuthClientRequest) Descriptor() ([]byte, []int) {
+	return file_proto_oauthclient_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *UpdateOAuthClientRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateOAuthClientRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UpdateOAuthClientRequest) GetRedirectUris() []string {
+	if x != nil {
+		return x.RedirectUris
+	}
+	return nil
+}
+
+func (x *UpdateOAuthClientRequest) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+func (x *UpdateOAuthClientRequest) GetTrusted() bool {
+	if x != nil {
+		return x.Trusted
+	}
+	return false
+}
+
+type DeleteOAuthClientRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteOAuthClientRequest) Reset() {
+	*x = DeleteOAuthClientRequest{}
+	mi := &file_proto_oauthclient_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteOAuthClientRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteOAuthClientRequest) ProtoMessage() {}
+
+func (x *DeleteOAuthClientRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oauthclient_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteOAuthClientRequest.ProtoReflect.Descriptor instead.
+func (*DeleteOAuthClientRequest) Descriptor() ([]byte, []int) {
+	return file_proto_oauthclient_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *DeleteOAuthClientRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type DeleteOAuthClientReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteOAuthClientReply) Reset() {
+	*x = DeleteOAuthClientReply{}
+	mi := &file_proto_oauthclient_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteOAuthClientReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteOAuthClientReply) ProtoMessage() {}
+
+func (x *DeleteOAuthClientReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oauthclient_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteOAuthClientReply.ProtoReflect.Descriptor instead.
+func (*DeleteOAuthClientReply) Descriptor() ([]byte, []int) {
+	return file_proto_oauthclient_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *DeleteOAuthClientReply) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type RotateOAuthClientSecretRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	OverlapSeconds int64                  `protobuf:"varint,2,opt,name=overlapSeconds,proto3" json:"overlapSeconds,omitempty"` // secret เดิมใช้ได้ต่ออีกกี่วินาที (0 = 24 ชั่วโมง สูงสุด 7 วัน)
+	RevokePrevious bool                   `protobuf:"varint,3,opt,name=revokePrevious,proto3" json:"revokePrevious,omitempty"` // ยกเลิก secret เดิมทันที
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *RotateOAuthClientSecretRequest) Reset() {
+	*x = RotateOAuthClientSecretRequest{}
+	mi := &file_proto_oauthclient_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RotateOAuthClientSecretRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotateOAuthClientSecretRequest) ProtoMessage() {}
+
+func (x *RotateOAuthClientSecretRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oauthclient_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotateOAuthClientSecretRequest.ProtoReflect.Descriptor instead.
+func (*RotateOAuthClientSecretRequest) Descriptor() ([]byte, []int) {
+	return file_proto_oauthclient_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *RotateOAuthClientSecretRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RotateOAuthClientSecretRequest) GetOverlapSeconds() int64 {
+	if x != nil {
+		return x.OverlapSeconds
+	}
+	return 0
+}
+
+func (x *RotateOAuthClientSecretRequest) GetRevokePrevious() bool {
+	if x != nil {
+		return x.RevokePrevious
+	}
+	return false
+}
+
+type OAuthConsent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ClientId      string                 `protobuf:"bytes,1,opt,name=clientId,proto3" json:"clientId,omitempty"`
+	ClientName    string                 `protobuf:"bytes,2,opt,name=clientName,proto3" json:"clientName,omitempty"`
+	Scopes        []string               `protobuf:"bytes,3,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,4,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+	UpdatedAt     string                 `protobuf:"bytes,5,opt,name=updatedAt,proto3" json:"updatedAt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OAuthConsent) Reset() {
+	*x = OAuthConsent{}
+	mi := &file_proto_oauthclient_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OAuthConsent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OAuthConsent) ProtoMessage() {}
+
+func (x *OAuthConsent) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oauthclient_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OAuthConsent.ProtoReflect.Descriptor instead.
+func (*OAuthConsent) Descriptor() ([]byte, []int) {
+	return file_proto_oauthclient_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *OAuthConsent) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+func (x *OAuthConsent) GetClientName() string {
+	if x != nil {
+		return x.ClientName
+	}
+	return ""
+}
+
+func (x *OAuthConsent) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+func (x *OAuthConsent) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *OAuthConsent) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+type ListMyConsentsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMyConsentsRequest) Reset() {
+	*x = ListMyConsentsRequest{}
+	mi := &file_proto_oauthclient_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMyConsentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMyConsentsRequest) ProtoMessage() {}
+
+func (x *ListMyConsentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oauthclient_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMyConsentsRequest.ProtoReflect.Descriptor instead.
+func (*ListMyConsentsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_oauthclient_proto_rawDescGZIP(), []int{11}
+}
+
+type ListMyConsentsReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Consents      []*OAuthConsent        `protobuf:"bytes,1,rep,name=consents,proto3" json:"consents,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMyConsentsReply) Reset() {
+	*x = ListMyConsentsReply{}
+	mi := &file_proto_oauthclient_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMyConsentsReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMyConsentsReply) ProtoMessage() {}
+
+func (x *ListMyConsentsReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oauthclient_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMyConsentsReply.ProtoReflect.Descriptor instead.
+func (*ListMyConsentsReply) Descriptor() ([]byte, []int) {
+	return file_proto_oauthclient_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ListMyConsentsReply) GetConsents() []*OAuthConsent {
+	if x != nil {
+		return x.Consents
+	}
+	return nil
+}
+
+type RevokeConsentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ClientId      string                 `protobuf:"bytes,1,opt,name=clientId,proto3" json:"clientId,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeConsentRequest) Reset() {
+	*x = RevokeConsentRequest{}
+	mi := &file_proto_oauthclient_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeConsentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeConsentRequest) ProtoMessage() {}
+
+func (x *RevokeConsentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oauthclient_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeConsentRequest.ProtoReflect.Descriptor instead.
+func (*RevokeConsentRequest) Descriptor() ([]byte, []int) {
+	return file_proto_oauthclient_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *RevokeConsentRequest) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+type RevokeConsentReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeConsentReply) Reset() {
+	*x = RevokeConsentReply{}
+	mi := &file_proto_oauthclient_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeConsentReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeConsentReply) ProtoMessage() {}
+
+func (x *RevokeConsentReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oauthclient_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeConsentReply.ProtoReflect.Descriptor instead.
+func (*RevokeConsentReply) Descriptor() ([]byte, []int) {
+	return file_proto_oauthclient_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *RevokeConsentReply) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+var File_proto_oauthclient_proto protoreflect.FileDescriptor
+
+const file_proto_oauthclient_proto_rawDesc = "" +
+	"\n" +
+	"\x17proto/oauthclient.proto\x1a\x1aproto/serviceaccount.proto\"\xa0\x02\n" +
+	"\vOAuthClient\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1a\n" +
+	"\bclientId\x18\x02 \x01(\tR\bclientId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\"\n" +
+	"\fredirectUris\x18\x04 \x03(\tR\fredirectUris\x12\x16\n" +
+	"\x06scopes\x18\x05 \x03(\tR\x06scopes\x12\x16\n" +
+	"\x06public\x18\x06 \x01(\bR\x06public\x12\x18\n" +
+	"\atrusted\x18\a \x01(\bR\atrusted\x12'\n" +
+	"\asecrets\x18\b \x03(\v2\r.ClientSecretR\asecrets\x12\x1c\n" +
+	"\tcreatedAt\x18\t \x01(\tR\tcreatedAt\x12\x1c\n" +
+	"\tupdatedAt\x18\n" +
+	" \x01(\tR\tupdatedAt\"\x9c\x01\n" +
+	"\x18CreateOAuthClientRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\"\n" +
+	"\fredirectUris\x18\x02 \x03(\tR\fredirectUris\x12\x16\n" +
+	"\x06scopes\x18\x03 \x03(\tR\x06scopes\x12\x16\n" +
+	"\x06public\x18\x04 \x01(\bR\x06public\x12\x18\n" +
+	"\atrusted\x18\x05 \x01(\bR\atrusted\"b\n" +
+	"\x16CreateOAuthClientReply\x12$\n" +
+	"\x06client\x18\x01 \x01(\v2\f.OAuthClientR\x06client\x12\"\n" +
+	"\fclientSecret\x18\x02 \x01(\tR\fclientSecret\"'\n" +
+	"\x15GetOAuthClientRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x19\n" +
+	"\x17ListOAuthClientsRequest\"?\n" +
+	"\x15ListOAuthClientsReply\x12&\n" +
+	"\aclients\x18\x01 \x03(\v2\f.OAuthClientR\aclients\"\x94\x01\n" +
+	"\x18UpdateOAuthClientRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\"\n" +
+	"\fredirectUris\x18\x03 \x03(\tR\fredirectUris\x12\x16\n" +
+	"\x06scopes\x18\x04 \x03(\tR\x06scopes\x12\x18\n" +
+	"\atrusted\x18\x05 \x01(\bR\atrusted\"*\n" +
+	"\x18DeleteOAuthClientRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"2\n" +
+	"\x16DeleteOAuthClientReply\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\"\x80\x01\n" +
+	"\x1eRotateOAuthClientSecretRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12&\n" +
+	"\x0eoverlapSeconds\x18\x02 \x01(\x03R\x0eoverlapSeconds\x12&\n" +
+	"\x0erevokePrevious\x18\x03 \x01(\bR\x0erevokePrevious\"\x9e\x01\n" +
+	"\fOAuthConsent\x12\x1a\n" +
+	"\bclientId\x18\x01 \x01(\tR\bclientId\x12\x1e\n" +
+	"\n" +
+	"clientName\x18\x02 \x01(\tR\n" +
+	"clientName\x12\x16\n" +
+	"\x06scopes\x18\x03 \x03(\tR\x06scopes\x12\x1c\n" +
+	"\tcreatedAt\x18\x04 \x01(\tR\tcreatedAt\x12\x1c\n" +
+	"\tupdatedAt\x18\x05 \x01(\tR\tupdatedAt\"\x17\n" +
+	"\x15ListMyConsentsRequest\"@\n" +
+	"\x13ListMyConsentsReply\x12)\n" +
+	"\bconsents\x18\x01 \x03(\v2\r.OAuthConsentR\bconsents\"2\n" +
+	"\x14RevokeConsentRequest\x12\x1a\n" +
+	"\bclientId\x18\x01 \x01(\tR\bclientId\".\n" +
+	"\x12RevokeConsentReply\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage2\xc4\x04\n" +
+	"\x12OAuthClientService\x12I\n" +
+	"\x11CreateOAuthClient\x12\x19.CreateOAuthClientRequest\x1a\x17.CreateOAuthClientReply\"\x00\x128\n" +
+	"\x0eGetOAuthClient\x12\x16.GetOAuthClientRequest\x1a\f.OAuthClient\"\x00\x12F\n" +
+	"\x10ListOAuthClients\x12\x18.ListOAuthClientsRequest\x1a\x16.ListOAuthClientsReply\"\x00\x12>\n" +
+	"\x11UpdateOAuthClient\x12\x19.UpdateOAuthClientRequest\x1a\f.OAuthClient\"\x00\x12I\n" +
+	"\x11DeleteOAuthClient\x12\x19.DeleteOAuthClientRequest\x1a\x17.DeleteOAuthClientReply\"\x00\x12U\n" +
+	"\x17RotateOAuthClientSecret\x12\x1f.RotateOAuthClientSecretRequest\x1a\x17.CreateOAuthClientReply\"\x00\x12@\n" +
+	"\x0eListMyConsents\x12\x16.ListMyConsentsRequest\x1a\x14.ListMyConsentsReply\"\x00\x12=\n" +
+	"\rRevokeConsent\x12\x15.RevokeConsentRequest\x1a\x13.RevokeConsentReply\"\x00B\x19Z\x17auth-microservice/protob\x06proto3"
+
+var (
+	file_proto_oauthclient_proto_rawDescOnce sync.Once
+	file_proto_oauthclient_proto_rawDescData []byte
+)
+
+func file_proto_oauthclient_proto_rawDescGZIP() []byte {
+	file_proto_oauthclient_proto_rawDescOnce.Do(func() {
+		file_proto_oauthclient_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_oauthclient_proto_rawDesc), len(file_proto_oauthclient_proto_rawDesc)))
+	})
+	return file_proto_oauthclient_proto_rawDescData
+}
+
+var file_proto_oauthclient_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
+var file_proto_oauthclient_proto_goTypes = []any{
+	(*OAuthClient)(nil),                    // 0: OAuthClient
+	(*CreateOAuthClientRequest)(nil),       // 1: CreateOAuthClientRequest
+	(*CreateOAuthClientReply)(nil),         // 2: CreateOAuthClientReply
+	(*GetOAuthClientRequest)(nil),          // 3: GetOAuthClientRequest
+	(*ListOAuthClientsRequest)(nil),        // 4: ListOAuthClientsRequest
+	(*ListOAuthClientsReply)(nil),          // 5: ListOAuthClientsReply
+	(*UpdateOAuthClientRequest)(nil),       // 6: UpdateOAuthClientRequest
+	(*DeleteOAuthClientRequest)(nil),       // 7: DeleteOAuthClientRequest
+	(*DeleteOAuthClientReply)(nil),         // 8: DeleteOAuthClientReply
+	(*RotateOAuthClientSecretRequest)(nil), // 9: RotateOAuthClientSecretRequest
+	(*OAuthConsent)(nil),                   // 10: OAuthConsent
+	(*ListMyConsentsRequest)(nil),          // 11: ListMyConsentsRequest
+	(*ListMyConsentsReply)(nil),            // 12: ListMyConsentsReply
+	(*RevokeConsentRequest)(nil),           // 13: RevokeConsentRequest
+	(*RevokeConsentReply)(nil),             // 14: RevokeConsentReply
+	(*ClientSecret)(nil),                   // 15: ClientSecret
+}
+var file_proto_oauthclient_proto_depIdxs = []int32{
+	15, // 0: OAuthClient.secrets:type_name -> ClientSecret
+	0,  // 1: CreateOAuthClientReply.client:type_name -> OAuthClient
+	0,  // 2: ListOAuthClientsReply.clients:type_name -> OAuthClient
+	10, // 3: ListMyConsentsReply.consents:type_name -> OAuthConsent
+	1,  // 4: OAuthClientService.CreateOAuthClient:input_type -> CreateOAuthClientRequest
+	3,  // 5: OAuthClientService.GetOAuthClient:input_type -> GetOAuthClientRequest
+	4,  // 6: OAuthClientService.ListOAuthClients:input_type -> ListOAuthClientsRequest
+	6,  // 7: OAuthClientService.UpdateOAuthClient:input_type -> UpdateOAuthClientRequest
+	7,  // 8: OAuthClientService.DeleteOAuthClient:input_type -> DeleteOAuthClientRequest
+	9,  // 9: OAuthClientService.RotateOAuthClientSecret:input_type -> RotateOAuthClientSecretRequest
+	11, // 10: OAuthClientService.ListMyConsents:input_type -> ListMyConsentsRequest
+	13, // 11: OAuthClientService.RevokeConsent:input_type -> RevokeConsentRequest
+	2,  // 12: OAuthClientService.CreateOAuthClient:output_type -> CreateOAuthClientReply
+	0,  // 13: OAuthClientService.GetOAuthClient:output_type -> OAuthClient
+	5,  // 14: OAuthClientService.ListOAuthClients:output_type -> ListOAuthClientsReply
+	0,  // 15: OAuthClientService.UpdateOAuthClient:output_type -> OAuthClient
+	8,  // 16: OAuthClientService.DeleteOAuthClient:output_type -> DeleteOAuthClientReply
+	2,  // 17: OAuthClientService.RotateOAuthClientSecret:output_type -> CreateOAuthClientReply
+	12, // 18: OAuthClientService.ListMyConsents:output_type -> ListMyConsentsReply
+	14, // 19: OAuthClientService.RevokeConsent:output_type -> RevokeConsentReply
+	12, // [12:20] is the sub-list for method output_type
+	4,  // [4:12] is the sub-list for method input_type
+	4,  // [4:4] is the sub-list for extension type_name
+	4,  // [4:4] is the sub-list for extension extendee
+	0,  // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_proto_oauthclient_proto_init() }
+func file_proto_oauthclient_proto_init() {
+	if File_proto_oauthclient_proto != nil {
+		return
+	}
+	file_proto_serviceaccount_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_oauthclient_proto_rawDesc), len(file_proto_oauthclient_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   15,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_oauthclient_proto_goTypes,
+		DependencyIndexes: file_proto_oauthclient_proto_depIdxs,
+		MessageInfos:      file_proto_oauthclient_proto_msgTypes,
+	}.Build()
+	File_proto_oauthclient_proto = out.File
+	file_proto_oauthclient_proto_goTypes = nil
+	file_proto_oauthclient_proto_depIdxs = nil
+}
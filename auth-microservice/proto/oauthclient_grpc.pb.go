@@ -0,0 +1,411 @@
+// กำหนด version ของ Protocol Buffers ที่ใช้
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v6.31.1
+// source: proto/oauthclient.proto
+
+package proto
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	OAuthClientService_CreateOAuthClient_FullMethodName       = "/OAuthClientService/CreateOAuthClient"
+	OAuthClientService_GetOAuthClient_FullMethodName          = "/OAuthClientService/GetOAuthClient"
+	OAuthClientService_ListOAuthClients_FullMethodName        = "/OAuthClientService/ListOAuthClients"
+	OAuthClientService_UpdateOAuthClient_FullMethodName       = "/OAuthClientService/UpdateOAuthClient"
+	OAuthClientService_DeleteOAuthClient_FullMethodName       = "/OAuthClientService/DeleteOAuthClient"
+	OAuthClientService_RotateOAuthClientSecret_FullMethodName = "/OAuthClientService/RotateOAuthClientSecret"
+	OAuthClientService_ListMyConsents_FullMethodName          = "/OAuthClientService/ListMyConsents"
+	OAuthClientService_RevokeConsent_FullMethodName           = "/OAuthClientService/RevokeConsent"
+)
+
+// OAuthClientServiceClient is the client API for OAuthClientService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// บริการ OAuthClientService สำหรับลงทะเบียนแอปที่ใช้ระบบนี้เป็น identity provider (ต้องแนบ token ใน metadata "authorization")
+// การจัดการ client ทำได้เฉพาะ admin ส่วนรายการและการยกเลิกความยินยอมเป็นของผู้ใช้แต่ละคน
+type OAuthClientServiceClient interface {
+	// ลงทะเบียน client (confidential client ได้ client secret ที่แสดงครั้งเดียวใน reply นี้)
+	CreateOAuthClient(ctx context.Context, in *CreateOAuthClientRequest, opts ...grpc.CallOption) (*CreateOAuthClientReply, error)
+	// ดูข้อมูล client
+	GetOAuthClient(ctx context.Context, in *GetOAuthClientRequest, opts ...grpc.CallOption) (*OAuthClient, error)
+	// รายการ client ใน tenant
+	ListOAuthClients(ctx context.Context, in *ListOAuthClientsRequest, opts ...grpc.CallOption) (*ListOAuthClientsReply, error)
+	// แก้ไขชื่อ, redirect URI, scope หรือสถานะ trusted
+	UpdateOAuthClient(ctx context.Context, in *UpdateOAuthClientRequest, opts ...grpc.CallOption) (*OAuthClient, error)
+	// ลบ client พร้อมความยินยอมทั้งหมด (refresh token ของ client ใช้ไม่ได้ทันที)
+	DeleteOAuthClient(ctx context.Context, in *DeleteOAuthClientRequest, opts ...grpc.CallOption) (*DeleteOAuthClientReply, error)
+	// สร้าง client secret ใหม่ secret เดิมยังใช้ได้ต่อจนหมดช่วงเวลาซ้อนทับ
+	RotateOAuthClientSecret(ctx context.Context, in *RotateOAuthClientSecretRequest, opts ...grpc.CallOption) (*CreateOAuthClientReply, error)
+	// client ที่ผู้ใช้เคยให้ความยินยอม
+	ListMyConsents(ctx context.Context, in *ListMyConsentsRequest, opts ...grpc.CallOption) (*ListMyConsentsReply, error)
+	// ยกเลิกความยินยอม (refresh token ที่ client ได้จากผู้ใช้ใช้ไม่ได้ทันที)
+	RevokeConsent(ctx context.Context, in *RevokeConsentRequest, opts ...grpc.CallOption) (*RevokeConsentReply, error)
+}
+
+type oAuthClientServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewOAuthClientServiceClient(cc grpc.ClientConnInterface) OAuthClientServiceClient {
+	return &oAuthClientServiceClient{cc}
+}
+
+func (c *oAuthClientServiceClient) CreateOAuthClient(ctx context.Context, in *CreateOAuthClientRequest, opts ...grpc.CallOption) (*CreateOAuthClientReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateOAuthClientReply)
+	err := c.cc.Invoke(ctx, OAuthClientService_CreateOAuthClient_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *oAuthClientServiceClient) GetOAuthClient(ctx context.Context, in *GetOAuthClientRequest, opts ...grpc.CallOption) (*OAuthClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OAuthClient)
+	err := c.cc.Invoke(ctx, OAuthClientService_GetOAuthClient_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *oAuthClientServiceClient) ListOAuthClients(ctx context.Context, in *ListOAuthClientsRequest, opts ...grpc.CallOption) (*ListOAuthClientsReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListOAuthClientsReply)
+	err := c.cc.Invoke(ctx, OAuthClientService_ListOAuthClients_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *oAuthClientServiceClient) UpdateOAuthClient(ctx context.Context, in *UpdateOAuthClientRequest, opts ...grpc.CallOption) (*OAuthClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OAuthClient)
+	err := c.cc.Invoke(ctx, OAuthClientService_UpdateOAuthClient_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *oAuthClientServiceClient) DeleteOAuthClient(ctx context.Context, in *DeleteOAuthClientRequest, opts ...grpc.CallOption) (*DeleteOAuthClientReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteOAuthClientReply)
+	err := c.cc.Invoke(ctx, OAuthClientService_DeleteOAuthClient_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *oAuthClientServiceClient) RotateOAuthClientSecret(ctx context.Context, in *RotateOAuthClientSecretRequest, opts ...grpc.CallOption) (*CreateOAuthClientReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateOAuthClientReply)
+	err := c.cc.Invoke(ctx, OAuthClientService_RotateOAuthClientSecret_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *oAuthClientServiceClient) ListMyConsents(ctx context.Context, in *ListMyConsentsRequest, opts ...grpc.CallOption) (*ListMyConsentsReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListMyConsentsReply)
+	err := c.cc.Invoke(ctx, OAuthClientService_ListMyConsents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *oAuthClientServiceClient) RevokeConsent(ctx context.Context, in *RevokeConsentRequest, opts ...grpc.CallOption) (*RevokeConsentReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RevokeConsentReply)
+	err := c.cc.Invoke(ctx, OAuthClientService_RevokeConsent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// OAuthClientServiceServer is the server API for OAuthClientService service.
+// All implementations must embed UnimplementedOAuthClientServiceServer
+// for forward compatibility.
+//
+// บริการ OAuthClientService สำหรับลงทะเบียนแอปที่ใช้ระบบนี้เป็น identity provider (ต้องแนบ token ใน metadata "authorization")
+// การจัดการ client ทำได้เฉพาะ admin ส่วนรายการและการยกเลิกความยินยอมเป็นของผู้ใช้แต่ละคน
+type OAuthClientServiceServer interface {
+	// ลงทะเบียน client (confidential client ได้ client secret ที่แสดงครั้งเดียวใน reply นี้)
+	CreateOAuthClient(context.Context, *CreateOAuthClientRequest) (*CreateOAuthClientReply, error)
+	// ดูข้อมูล client
+	GetOAuthClient(context.Context, *GetOAuthClientRequest) (*OAuthClient, error)
+	// รายการ client ใน tenant
+	ListOAuthClients(context.Context, *ListOAuthClientsRequest) (*ListOAuthClientsReply, error)
+	// แก้ไขชื่อ, redirect URI, scope หรือสถานะ trusted
+	UpdateOAuthClient(context.Context, *UpdateOAuthClientRequest) (*OAuthClient, error)
+	// ลบ client พร้อมความยินยอมทั้งหมด (refresh token ของ client ใช้ไม่ได้ทันที)
+	DeleteOAuthClient(context.Context, *DeleteOAuthClientRequest) (*DeleteOAuthClientReply, error)
+	// สร้าง client secret ใหม่ secret เดิมยังใช้ได้ต่อจนหมดช่วงเวลาซ้อนทับ
+	RotateOAuthClientSecret(context.Context, *RotateOAuthClientSecretRequest) (*CreateOAuthClientReply, error)
+	// client ที่ผู้ใช้เคยให้ความยินยอม
+	ListMyConsents(context.Context, *ListMyConsentsRequest) (*ListMyConsentsReply, error)
+	// ยกเลิกความยินยอม (refresh token ที่ client ได้จากผู้ใช้ใช้ไม่ได้ทันที)
+	RevokeConsent(context.Context, *RevokeConsentRequest) (*RevokeConsentReply, error)
+	mustEmbedUnimplementedOAuthClientServiceServer()
+}
+
+// UnimplementedOAuthClientServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedOAuthClientServiceServer struct{}
+
+func (UnimplementedOAuthClientServiceServer) CreateOAuthClient(context.Context, *CreateOAuthClientRequest) (*CreateOAuthClientReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateOAuthClient not implemented")
+}
+func (UnimplementedOAuthClientServiceServer) GetOAuthClient(context.Context, *GetOAuthClientRequest) (*OAuthClient, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOAuthClient not implemented")
+}
+func (UnimplementedOAuthClientServiceServer) ListOAuthClients(context.Context, *ListOAuthClientsRequest) (*ListOAuthClientsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListOAuthClients not implemented")
+}
+func (UnimplementedOAuthClientServiceServer) UpdateOAuthClient(context.Context, *UpdateOAuthClientRequest) (*OAuthClient, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateOAuthClient not implemented")
+}
+func (UnimplementedOAuthClientServiceServer) DeleteOAuthClient(context.Context, *DeleteOAuthClientRequest) (*DeleteOAuthClientReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteOAuthClient not implemented")
+}
+func (UnimplementedOAuthClientServiceServer) RotateOAuthClientSecret(context.Context, *RotateOAuthClientSecretRequest) (*CreateOAuthClientReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RotateOAuthClientSecret not implemented")
+}
+func (UnimplementedOAuthClientServiceServer) ListMyConsents(context.Context, *ListMyConsentsRequest) (*ListMyConsentsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMyConsents not implemented")
+}
+func (UnimplementedOAuthClientServiceServer) RevokeConsent(context.Context, *RevokeConsentRequest) (*RevokeConsentReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeConsent not implemented")
+}
+func (UnimplementedOAuthClientServiceServer) mustEmbedUnimplementedOAuthClientServiceServer() {}
+func (UnimplementedOAuthClientServiceServer) testEmbeddedByValue()                            {}
+
+// UnsafeOAuthClientServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to OAuthClientServiceServer will
+// result in compilation errors.
+type UnsafeOAuthClientServiceServer interface {
+	mustEmbedUnimplementedOAuthClientServiceServer()
+}
+
+func RegisterOAuthClientServiceServer(s grpc.ServiceRegistrar, srv OAuthClientServiceServer) {
+	// If the following call pancis, it indicates UnimplementedOAuthClientServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&OAuthClientService_ServiceDesc, srv)
+}
+
+func _OAuthClientService_CreateOAuthClient_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateOAuthClientRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OAuthClientServiceServer).CreateOAuthClient(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OAuthClientService_CreateOAuthClient_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OAuthClientServiceServer).CreateOAuthClient(ctx, req.(*CreateOAuthClientRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OAuthClientService_GetOAuthClient_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOAuthClientRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OAuthClientServiceServer).GetOAuthClient(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OAuthClientService_GetOAuthClient_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OAuthClientServiceServer).GetOAuthClient(ctx, req.(*GetOAuthClientRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OAuthClientService_ListOAuthClients_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListOAuthClientsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OAuthClientServiceServer).ListOAuthClients(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OAuthClientService_ListOAuthClients_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OAuthClientServiceServer).ListOAuthClients(ctx, req.(*ListOAuthClientsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OAuthClientService_UpdateOAuthClient_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateOAuthClientRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OAuthClientServiceServer).UpdateOAuthClient(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OAuthClientService_UpdateOAuthClient_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OAuthClientServiceServer).UpdateOAuthClient(ctx, req.(*UpdateOAuthClientRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OAuthClientService_DeleteOAuthClient_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteOAuthClientRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OAuthClientServiceServer).DeleteOAuthClient(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OAuthClientService_DeleteOAuthClient_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OAuthClientServiceServer).DeleteOAuthClient(ctx, req.(*DeleteOAuthClientRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OAuthClientService_RotateOAuthClientSecret_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RotateOAuthClientSecretRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OAuthClientServiceServer).RotateOAuthClientSecret(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OAuthClientService_RotateOAuthClientSecret_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OAuthClientServiceServer).RotateOAuthClientSecret(ctx, req.(*RotateOAuthClientSecretRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OAuthClientService_ListMyConsents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMyConsentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OAuthClientServiceServer).ListMyConsents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OAuthClientService_ListMyConsents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OAuthClientServiceServer).ListMyConsents(ctx, req.(*ListMyConsentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OAuthClientService_RevokeConsent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeConsentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OAuthClientServiceServer).RevokeConsent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OAuthClientService_RevokeConsent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OAuthClientServiceServer).RevokeConsent(ctx, req.(*RevokeConsentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// OAuthClientService_ServiceDesc is the grpc.ServiceDesc for OAuthClientService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var OAuthClientService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "OAuthClientService",
+	HandlerType: (*OAuthClientServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateOAuthClient",
+			Handler:    _OAuthClientService_CreateOAuthClient_Handler,
+		},
+		{
+			MethodName: "GetOAuthClient",
+			Handler:    _OAuthClientService_GetOAuthClient_Handler,
+		},
+		{
+			MethodName: "ListOAuthClients",
+			Handler:    _OAuthClientService_ListOAuthClients_Handler,
+		},
+		{
+			MethodName: "UpdateOAuthClient",
+			Handler:    _OAuthClientService_UpdateOAuthClient_Handler,
+		},
+		{
+			MethodName: "DeleteOAuthClient",
+			Handler:    _OAuthClientService_DeleteOAuthClient_Handler,
+		},
+		{
+			MethodName: "RotateOAuthClientSecret",
+			Handler:    _OAuthClientService_RotateOAuthClientSecret_Handler,
+		},
+		{
+			MethodName: "ListMyConsents",
+			Handler:    _OAuthClientService_ListMyConsents_Handler,
+		},
+		{
+			MethodName: "RevokeConsent",
+			Handler:    _OAuthClientService_RevokeConsent_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/oauthclient.proto",
+}
@@ -0,0 +1,980 @@
+// กำหนด version ของ Protocol Buffers ที่ใช้
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v6.31.1
+// source: proto/passkey.proto
+
+package proto
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// public key และ credential ID ไม่ถูกส่งกลับใน reply
+type Passkey struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Id                string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name              string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Aaguid            string                 `protobuf:"bytes,3,opt,name=aaguid,proto3" json:"aaguid,omitempty"`                       // รุ่นของ authenticator (ค่าว่างถ้าไม่ทราบ)
+	AttestationFormat string                 `protobuf:"bytes,4,opt,name=attestationFormat,proto3" json:"attestationFormat,omitempty"` // none, packed หรือ fido-u2f
+	Transports        []string               `protobuf:"bytes,5,rep,name=transports,proto3" json:"transports,omitempty"`               // เช่น internal, hybrid, usb
+	BackupEligible    bool                   `protobuf:"varint,6,opt,name=backupEligible,proto3" json:"backupEligible,omitempty"`      // passkey ที่ sync ข้ามอุปกรณ์ได้
+	BackedUp          bool                   `protobuf:"varint,7,opt,name=backedUp,proto3" json:"backedUp,omitempty"`
+	CreatedAt         string                 `protobuf:"bytes,8,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+	LastUsedAt        string                 `protobuf:"bytes,9,opt,name=lastUsedAt,proto3" json:"lastUsedAt,omitempty"` // ค่าว่างถ้ายังไม่เคยใช้เข้าสู่ระบบ
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *Passkey) Reset() {
+	*x = Passkey{}
+	mi := &file_proto_passkey_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Passkey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Passkey) ProtoMessage() {}
+
+func (x *Passkey) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_passkey_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Passkey.ProtoReflect.Descriptor instead.
+func (*Passkey) Descriptor() ([]byte, []int) {
+	return file_proto_passkey_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Passkey) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Passkey) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Passkey) GetAaguid() string {
+	if x != nil {
+		return x.Aaguid
+	}
+	return ""
+}
+
+func (x *Passkey) GetAttestationFormat() string {
+	if x != nil {
+		return x.AttestationFormat
+	}
+	return ""
+}
+
+func (x *Passkey) GetTransports() []string {
+	if x != nil {
+		return x.Transports
+	}
+	return nil
+}
+
+func (x *Passkey) GetBackupEligible() bool {
+	if x != nil {
+		return x.BackupEligible
+	}
+	return false
+}
+
+func (x *Passkey) GetBackedUp() bool {
+	if x != nil {
+		return x.BackedUp
+	}
+	return false
+}
+
+func (x *Passkey) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *Passkey) GetLastUsedAt() string {
+	if x != nil {
+		return x.LastUsedAt
+	}
+	return ""
+}
+
+type BeginPasskeyRegistrationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BeginPasskeyRegistrationRequest) Reset() {
+	*x = BeginPasskeyRegistrationRequest{}
+	mi := &file_proto_passkey_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BeginPasskeyRegistrationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BeginPasskeyRegistrationRequest) ProtoMessage() {}
+
+func (x *BeginPasskeyRegistrationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_passkey_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BeginPasskeyRegistrationRequest.ProtoReflect.Descriptor instead.
+func (*BeginPasskeyRegistrationRequest) Descriptor() ([]byte, []int) {
+	return file_proto_passkey_proto_rawDescGZIP(), []int{1}
+}
+
+type BeginPasskeyRegistrationReply struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	SessionId        string                 `protobuf:"bytes,1,opt,name=sessionId,proto3" json:"sessionId,omitempty"` // ส่งกลับมาใน FinishPasskeyRegistration (ใช้ได้ครั้งเดียว)
+	PublicKeyOptions string                 `protobuf:"bytes,2,opt,name=publicKeyOptions,proto3" json:"publicKeyOptions,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *BeginPasskeyRegistrationReply) Reset() {
+	*x = BeginPasskeyRegistrationReply{}
+	mi := &file_proto_passkey_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BeginPasskeyRegistrationReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BeginPasskeyRegistrationReply) ProtoMessage() {}
+
+func (x *BeginPasskeyRegistrationReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_passkey_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BeginPasskeyRegistrationReply.ProtoReflect.Descriptor instead.
+func (*BeginPasskeyRegistrationReply) Descriptor() ([]byte, []int) {
+	return file_proto_passkey_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *BeginPasskeyRegistrationReply) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *BeginPasskeyRegistrationReply) GetPublicKeyOptions() string {
+	if x != nil {
+		return x.PublicKeyOptions
+	}
+	return ""
+}
+
+type FinishPasskeyRegistrationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=sessionId,proto3" json:"sessionId,omitempty"`
+	Credential    string                 `protobuf:"bytes,2,opt,name=credential,proto3" json:"credential,omitempty"` // ผลของ navigator.credentials.create
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`             // ชื่อที่ผู้ใช้ตั้ง (ค่าว่าง = "Passkey")
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FinishPasskeyRegistrationRequest) Reset() {
+	*x = FinishPasskeyRegistrationRequest{}
+	mi := &file_proto_passkey_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FinishPasskeyRegistrationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FinishPasskeyRegistrationRequest) ProtoMessage() {}
+
+func (x *FinishPasskeyRegistrationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_passkey_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FinishPasskeyRegistrationRequest.ProtoReflect.Descriptor instead.
+func (*FinishPasskeyRegistrationRequest) Descriptor() ([]byte, []int) {
+	return file_proto_passkey_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *FinishPasskeyRegistrationRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *FinishPasskeyRegistrationRequest) GetCredential() string {
+	if x != nil {
+		return x.Credential
+	}
+	return ""
+}
+
+func (x *FinishPasskeyRegistrationRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type ListPasskeysRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPasskeysRequest) Reset() {
+	*x = ListPasskeysRequest{}
+	mi := &file_proto_passkey_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPasskeysRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPasskeysRequest) ProtoMessage() {}
+
+func (x *ListPasskeysRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_passkey_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPasskeysRequest.ProtoReflect.Descriptor instead.
+func (*ListPasskeysRequest) Descriptor() ([]byte, []int) {
+	return file_proto_passkey_proto_rawDescGZIP(), []int{4}
+}
+
+type ListPasskeysReply struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Passkeys        []*Passkey             `protobuf:"bytes,1,rep,name=passkeys,proto3" json:"passkeys,omitempty"`
+	PasskeyRequired bool                   `protobuf:"varint,2,opt,name=passkeyRequired,proto3" json:"passkeyRequired,omitempty"` // ผู้ใช้บังคับใช้ passkey เป็นปัจจัยที่สองหรือไม่
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ListPasskeysReply) Reset() {
+	*x = ListPasskeysReply{}
+	mi := &file_proto_passkey_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPasskeysReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPasskeysReply) ProtoMessage() {}
+
+func (x *ListPasskeysReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_passkey_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPasskeysReply.ProtoReflect.Descriptor instead.
+func (*ListPasskeysReply) Descriptor() ([]byte, []int) {
+	return file_proto_passkey_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListPasskeysReply) GetPasskeys() []*Passkey {
+	if x != nil {
+		return x.Passkeys
+	}
+	return nil
+}
+
+func (x *ListPasskeysReply) GetPasskeyRequired() bool {
+	if x != nil {
+		return x.PasskeyRequired
+	}
+	return false
+}
+
+type RenamePasskeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RenamePasskeyRequest) Reset() {
+	*x = RenamePasskeyRequest{}
+	mi := &file_proto_passkey_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenamePasskeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenamePasskeyRequest) ProtoMessage() {}
+
+func (x *RenamePasskeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_passkey_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenamePasskeyRequest.ProtoReflect.Descriptor instead.
+func (*RenamePasskeyRequest) Descriptor() ([]byte, []int) {
+	return file_proto_passkey_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *RenamePasskeyRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RenamePasskeyRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type DeletePasskeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeletePasskeyRequest) Reset() {
+	*x = DeletePasskeyRequest{}
+	mi := &file_proto_passkey_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeletePasskeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeletePasskeyRequest) ProtoMessage() {}
+
+func (x *DeletePasskeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_passkey_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeletePasskeyRequest.ProtoReflect.Descriptor instead.
+func (*DeletePasskeyRequest) Descriptor() ([]byte, []int) {
+	return file_proto_passkey_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *DeletePasskeyRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type DeletePasskeyReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeletePasskeyReply) Reset() {
+	*x = DeletePasskeyReply{}
+	mi := &file_proto_passkey_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeletePasskeyReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeletePasskeyReply) ProtoMessage() {}
+
+func (x *DeletePasskeyReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_passkey_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeletePasskeyReply.ProtoReflect.Descriptor instead.
+func (*DeletePasskeyReply) Descriptor() ([]byte, []int) {
+	return file_proto_passkey_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *DeletePasskeyReply) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type SetPasskeyRequiredRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Required      bool                   `protobuf:"varint,1,opt,name=required,proto3" json:"required,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetPasskeyRequiredRequest) Reset() {
+	*x = SetPasskeyRequiredRequest{}
+	mi := &file_proto_passkey_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetPasskeyRequiredRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetPasskeyRequiredRequest) ProtoMessage() {}
+
+func (x *SetPasskeyRequiredRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_passkey_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetPasskeyRequiredRequest.ProtoReflect.Descriptor instead.
+func (*SetPasskeyRequiredRequest) Descriptor() ([]byte, []int) {
+	return file_proto_passkey_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *SetPasskeyRequiredRequest) GetRequired() bool {
+	if x != nil {
+		return x.Required
+	}
+	return false
+}
+
+type SetPasskeyRequiredReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Required      bool                   `protobuf:"varint,1,opt,name=required,proto3" json:"required,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetPasskeyRequiredReply) Reset() {
+	*x = SetPasskeyRequiredReply{}
+	mi := &file_proto_passkey_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetPasskeyRequiredReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetPasskeyRequiredReply) ProtoMessage() {}
+
+func (x *SetPasskeyRequiredReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_passkey_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetPasskeyRequiredReply.ProtoReflect.Descriptor instead.
+func (*SetPasskeyRequiredReply) Descriptor() ([]byte, []int) {
+	return file_proto_passkey_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *SetPasskeyRequiredReply) GetRequired() bool {
+	if x != nil {
+		return x.Required
+	}
+	return false
+}
+
+func (x *SetPasskeyRequiredReply) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// ไม่ระบุ email และ secondFactorToken = ให้ผู้ใช้เลือก passkey ที่เก็บไว้ใน authenticator (discoverable credential)
+type BeginPasskeyLoginRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Email             string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`                         // จำกัดเฉพาะ passkey ของผู้ใช้ที่มีอีเมลนี้
+	SecondFactorToken string                 `protobuf:"bytes,2,opt,name=secondFactorToken,proto3" json:"secondFactorToken,omitempty"` // จาก LoginReply เมื่อผู้ใช้บังคับใช้ passkey เป็นปัจจัยที่สอง
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *BeginPasskeyLoginRequest) Reset() {
+	*x = BeginPasskeyLoginRequest{}
+	mi := &file_proto_passkey_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BeginPasskeyLoginRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BeginPasskeyLoginRequest) ProtoMessage() {}
+
+func (x *BeginPasskeyLoginRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_passkey_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BeginPasskeyLoginRequest.ProtoReflect.Descriptor instead.
+func (*BeginPasskeyLoginRequest) Descriptor() ([]byte, []int) {
+	return file_proto_passkey_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *BeginPasskeyLoginRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *BeginPasskeyLoginRequest) GetSecondFactorToken() string {
+	if x != nil {
+		return x.SecondFactorToken
+	}
+	return ""
+}
+
+type BeginPasskeyLoginReply struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	SessionId        string                 `protobuf:"bytes,1,opt,name=sessionId,proto3" json:"sessionId,omitempty"` // ส่งกลับมาใน FinishPasskeyLogin (ใช้ได้ครั้งเดียว)
+	PublicKeyOptions string                 `protobuf:"bytes,2,opt,name=publicKeyOptions,proto3" json:"publicKeyOptions,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *BeginPasskeyLoginReply) Reset() {
+	*x = BeginPasskeyLoginReply{}
+	mi := &file_proto_passkey_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BeginPasskeyLoginReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BeginPasskeyLoginReply) ProtoMessage() {}
+
+func (x *BeginPasskeyLoginReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_passkey_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BeginPasskeyLoginReply.ProtoReflect.Descriptor instead.
+func (*BeginPasskeyLoginReply) Descriptor() ([]byte, []int) {
+	return file_proto_passkey_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *BeginPasskeyLoginReply) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *BeginPasskeyLoginReply) GetPublicKeyOptions() string {
+	if x != nil {
+		return x.PublicKeyOptions
+	}
+	return ""
+}
+
+type FinishPasskeyLoginRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=sessionId,proto3" json:"sessionId,omitempty"`
+	Credential    string                 `protobuf:"bytes,2,opt,name=credential,proto3" json:"credential,omitempty"` // ผลของ navigator.credentials.get
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FinishPasskeyLoginRequest) Reset() {
+	*x = FinishPasskeyLoginRequest{}
+	mi := &file_proto_passkey_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FinishPasskeyLoginRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FinishPasskeyLoginRequest) ProtoMessage() {}
+
+func (x *FinishPasskeyLoginRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_passkey_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FinishPasskeyLoginRequest.ProtoReflect.Descriptor instead.
+func (*FinishPasskeyLoginRequest) Descriptor() ([]byte, []int) {
+	return file_proto_passkey_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *FinishPasskeyLoginRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *FinishPasskeyLoginRequest) GetCredential() string {
+	if x != nil {
+		return x.Credential
+	}
+	return ""
+}
+
+type FinishPasskeyLoginReply struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Email           string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	Username        string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	Token           string                 `protobuf:"bytes,3,opt,name=token,proto3" json:"token,omitempty"`                      // JWT token สำหรับใช้ยืนยันตัวตนในระบบ
+	PasswordExpired bool                   `protobuf:"varint,4,opt,name=passwordExpired,proto3" json:"passwordExpired,omitempty"` // (ปัจจัยที่สอง) รหัสผ่านที่ใช้เข้าสู่ระบบมีอายุเกินกำหนด
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *FinishPasskeyLoginReply) Reset() {
+	*x = FinishPasskeyLoginReply{}
+	mi := &file_proto_passkey_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FinishPasskeyLoginReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FinishPasskeyLoginReply) ProtoMessage() {}
+
+func (x *FinishPasskeyLoginReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_passkey_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FinishPasskeyLoginReply.ProtoReflect.Descriptor instead.
+func (*FinishPasskeyLoginReply) Descriptor() ([]byte, []int) {
+	return file_proto_passkey_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *FinishPasskeyLoginReply) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *FinishPasskeyLoginReply) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *FinishPasskeyLoginReply) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *FinishPasskeyLoginReply) GetPasswordExpired() bool {
+	if x != nil {
+		return x.PasswordExpired
+	}
+	return false
+}
+
+var File_proto_passkey_proto protoreflect.FileDescriptor
+
+const file_proto_passkey_proto_rawDesc = "" +
+	"\n" +
+	"\x13proto/passkey.proto\"\x95\x02\n" +
+	"\aPasskey\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x16\n" +
+	"\x06aaguid\x18\x03 \x01(\tR\x06aaguid\x12,\n" +
+	"\x11attestationFormat\x18\x04 \x01(\tR\x11attestationFormat\x12\x1e\n" +
+	"\n" +
+	"transports\x18\x05 \x03(\tR\n" +
+	"transports\x12&\n" +
+	"\x0ebackupEligible\x18\x06 \x01(\bR\x0ebackupEligible\x12\x1a\n" +
+	"\bbackedUp\x18\a \x01(\bR\bbackedUp\x12\x1c\n" +
+	"\tcreatedAt\x18\b \x01(\tR\tcreatedAt\x12\x1e\n" +
+	"\n" +
+	"lastUsedAt\x18\t \x01(\tR\n" +
+	"lastUsedAt\"!\n" +
+	"\x1fBeginPasskeyRegistrationRequest\"i\n" +
+	"\x1dBeginPasskeyRegistrationReply\x12\x1c\n" +
+	"\tsessionId\x18\x01 \x01(\tR\tsessionId\x12*\n" +
+	"\x10publicKeyOptions\x18\x02 \x01(\tR\x10publicKeyOptions\"t\n" +
+	" FinishPasskeyRegistrationRequest\x12\x1c\n" +
+	"\tsessionId\x18\x01 \x01(\tR\tsessionId\x12\x1e\n" +
+	"\n" +
+	"credential\x18\x02 \x01(\tR\n" +
+	"credential\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\"\x15\n" +
+	"\x13ListPasskeysRequest\"c\n" +
+	"\x11ListPasskeysReply\x12$\n" +
+	"\bpasskeys\x18\x01 \x03(\v2\b.PasskeyR\bpasskeys\x12(\n" +
+	"\x0fpasskeyRequired\x18\x02 \x01(\bR\x0fpasskeyRequired\":\n" +
+	"\x14RenamePasskeyRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"&\n" +
+	"\x14DeletePasskeyRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\".\n" +
+	"\x12DeletePasskeyReply\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\"7\n" +
+	"\x19SetPasskeyRequiredRequest\x12\x1a\n" +
+	"\brequired\x18\x01 \x01(\bR\brequired\"O\n" +
+	"\x17SetPasskeyRequiredReply\x12\x1a\n" +
+	"\brequired\x18\x01 \x01(\bR\brequired\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"^\n" +
+	"\x18BeginPasskeyLoginRequest\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\x12,\n" +
+	"\x11secondFactorToken\x18\x02 \x01(\tR\x11secondFactorToken\"b\n" +
+	"\x16BeginPasskeyLoginReply\x12\x1c\n" +
+	"\tsessionId\x18\x01 \x01(\tR\tsessionId\x12*\n" +
+	"\x10publicKeyOptions\x18\x02 \x01(\tR\x10publicKeyOptions\"Y\n" +
+	"\x19FinishPasskeyLoginRequest\x12\x1c\n" +
+	"\tsessionId\x18\x01 \x01(\tR\tsessionId\x12\x1e\n" +
+	"\n" +
+	"credential\x18\x02 \x01(\tR\n" +
+	"credential\"\x8b\x01\n" +
+	"\x17FinishPasskeyLoginReply\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12\x14\n" +
+	"\x05token\x18\x03 \x01(\tR\x05token\x12(\n" +
+	"\x0fpasswordExpired\x18\x04 \x01(\bR\x0fpasswordExpired2\xd2\x04\n" +
+	"\x0ePasskeyService\x12^\n" +
+	"\x18BeginPasskeyRegistration\x12 .BeginPasskeyRegistrationRequest\x1a\x1e.BeginPasskeyRegistrationReply\"\x00\x12J\n" +
+	"\x19FinishPasskeyRegistration\x12!.FinishPasskeyRegistrationRequest\x1a\b.Passkey\"\x00\x12:\n" +
+	"\fListPasskeys\x12\x14.ListPasskeysRequest\x1a\x12.ListPasskeysReply\"\x00\x122\n" +
+	"\rRenamePasskey\x12\x15.RenamePasskeyRequest\x1a\b.Passkey\"\x00\x12=\n" +
+	"\rDeletePasskey\x12\x15.DeletePasskeyRequest\x1a\x13.DeletePasskeyReply\"\x00\x12L\n" +
+	"\x12SetPasskeyRequired\x12\x1a.SetPasskeyRequiredRequest\x1a\x18.SetPasskeyRequiredReply\"\x00\x12I\n" +
+	"\x11BeginPasskeyLogin\x12\x19.BeginPasskeyLoginRequest\x1a\x17.BeginPasskeyLoginReply\"\x00\x12L\n" +
+	"\x12FinishPasskeyLogin\x12\x1a.FinishPasskeyLoginRequest\x1a\x18.FinishPasskeyLoginReply\"\x00B\x19Z\x17auth-microservice/protob\x06proto3"
+
+var (
+	file_proto_passkey_proto_rawDescOnce sync.Once
+	file_proto_passkey_proto_rawDescData []byte
+)
+
+func file_proto_passkey_proto_rawDescGZIP() []byte {
+	file_proto_passkey_proto_rawDescOnce.Do(func() {
+		file_proto_passkey_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_passkey_proto_rawDesc), len(file_proto_passkey_proto_rawDesc)))
+	})
+	return file_proto_passkey_proto_rawDescData
+}
+
+var file_proto_passkey_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
+var file_proto_passkey_proto_goTypes = []any{
+	(*Passkey)(nil),                          // 0: Passkey
+	(*BeginPasskeyRegistrationRequest)(nil),  // 1: BeginPasskeyRegistrationRequest
+	(*BeginPasskeyRegistrationReply)(nil),    // 2: BeginPasskeyRegistrationReply
+	(*FinishPasskeyRegistrationRequest)(nil), // 3: FinishPasskeyRegistrationRequest
+	(*ListPasskeysRequest)(nil),              // 4: ListPasskeysRequest
+	(*ListPasskeysReply)(nil),                // 5: ListPasskeysReply
+	(*RenamePasskeyRequest)(nil),             // 6: RenamePasskeyRequest
+	(*DeletePasskeyRequest)(nil),             // 7: DeletePasskeyRequest
+	(*DeletePasskeyReply)(nil),               // 8: DeletePasskeyReply
+	(*SetPasskeyRequiredRequest)(nil),        // 9: SetPasskeyRequiredRequest
+	(*SetPasskeyRequiredReply)(nil),          // 10: SetPasskeyRequiredReply
+	(*BeginPasskeyLoginRequest)(nil),         // 11: BeginPasskeyLoginRequest
+	(*BeginPasskeyLoginReply)(nil),           // 12: BeginPasskeyLoginReply
+	(*FinishPasskeyLoginRequest)(nil),        // 13: FinishPasskeyLoginRequest
+	(*FinishPasskeyLoginReply)(nil),          // 14: FinishPasskeyLoginReply
+}
+var file_proto_passkey_proto_depIdxs = []int32{
+	0,  // 0: ListPasskeysReply.passkeys:type_name -> Passkey
+	1,  // 1: PasskeyService.BeginPasskeyRegistration:input_type -> BeginPasskeyRegistrationRequest
+	3,  // 2: PasskeyService.FinishPasskeyRegistration:input_type -> FinishPasskeyRegistrationRequest
+	4,  // 3: PasskeyService.ListPasskeys:input_type -> ListPasskeysRequest
+	6,  // 4: PasskeyService.RenamePasskey:input_type -> RenamePasskeyRequest
+	7,  // 5: PasskeyService.DeletePasskey:input_type -> DeletePasskeyRequest
+	9,  // 6: PasskeyService.SetPasskeyRequired:input_type -> SetPasskeyRequiredRequest
+	11, // 7: PasskeyService.BeginPasskeyLogin:input_type -> BeginPasskeyLoginRequest
+	13, // 8: PasskeyService.FinishPasskeyLogin:input_type -> FinishPasskeyLoginRequest
+	2,  // 9: PasskeyService.BeginPasskeyRegistration:output_type -> BeginPasskeyRegistrationReply
+	0,  // 10: PasskeyService.FinishPasskeyRegistration:output_type -> Passkey
+	5,  // 11: PasskeyService.ListPasskeys:output_type -> ListPasskeysReply
+	0,  // 12: PasskeyService.RenamePasskey:output_type -> Passkey
+	8,  // 13: PasskeyService.DeletePasskey:output_type -> DeletePasskeyReply
+	10, // 14: PasskeyService.SetPasskeyRequired:output_type -> SetPasskeyRequiredReply
+	12, // 15: PasskeyService.BeginPasskeyLogin:output_type -> BeginPasskeyLoginReply
+	14, // 16: PasskeyService.FinishPasskeyLogin:output_type -> FinishPasskeyLoginReply
+	9,  // [9:17] is the sub-list for method output_type
+	1,  // [1:9] is the sub-list for method input_type
+	1,  // [1:1] is the sub-list for extension type_name
+	1,  // [1:1] is the sub-list for extension extendee
+	0,  // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_proto_passkey_proto_init() }
+func file_proto_passkey_proto_init() {
+	if File_proto_passkey_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_passkey_proto_rawDesc), len(file_proto_passkey_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   15,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_passkey_proto_goTypes,
+		DependencyIndexes: file_proto_passkey_proto_depIdxs,
+		MessageInfos:      file_proto_passkey_proto_msgTypes,
+	}.Build()
+	File_proto_passkey_proto = out.File
+	file_proto_passkey_proto_goTypes = nil
+	file_proto_passkey_proto_depIdxs = nil
+}
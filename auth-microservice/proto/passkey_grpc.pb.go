@@ -0,0 +1,415 @@
+// กำหนด version ของ Protocol Buffers ที่ใช้
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v6.31.1
+// source: proto/passkey.proto
+
+package proto
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	PasskeyService_BeginPasskeyRegistration_FullMethodName  = "/PasskeyService/BeginPasskeyRegistration"
+	PasskeyService_FinishPasskeyRegistration_FullMethodName = "/PasskeyService/FinishPasskeyRegistration"
+	PasskeyService_ListPasskeys_FullMethodName              = "/PasskeyService/ListPasskeys"
+	PasskeyService_RenamePasskey_FullMethodName             = "/PasskeyService/RenamePasskey"
+	PasskeyService_DeletePasskey_FullMethodName             = "/PasskeyService/DeletePasskey"
+	PasskeyService_SetPasskeyRequired_FullMethodName        = "/PasskeyService/SetPasskeyRequired"
+	PasskeyService_BeginPasskeyLogin_FullMethodName         = "/PasskeyService/BeginPasskeyLogin"
+	PasskeyService_FinishPasskeyLogin_FullMethodName        = "/PasskeyService/FinishPasskeyLogin"
+)
+
+// PasskeyServiceClient is the client API for PasskeyService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// บริการ PasskeyService สำหรับลงทะเบียน passkey (WebAuthn) และเข้าสู่ระบบโดยไม่ใช้รหัสผ่าน
+// การลงทะเบียนและจัดการ passkey ต้องแนบ token ใน metadata "authorization" ส่วนการเข้าสู่ระบบระบุ tenant ด้วย metadata "x-tenant-id"
+// publicKeyOptions และ credential เป็น JSON ตามรูปแบบของ WebAuthn Level 3
+// (PublicKeyCredential.parseCreationOptionsFromJSON / parseRequestOptionsFromJSON และ credential.toJSON())
+type PasskeyServiceClient interface {
+	// เริ่มลงทะเบียน passkey (ส่ง publicKeyOptions ให้ navigator.credentials.create)
+	BeginPasskeyRegistration(ctx context.Context, in *BeginPasskeyRegistrationRequest, opts ...grpc.CallOption) (*BeginPasskeyRegistrationReply, error)
+	// ตรวจสอบ attestation แล้วบันทึก passkey ของผู้ใช้
+	FinishPasskeyRegistration(ctx context.Context, in *FinishPasskeyRegistrationRequest, opts ...grpc.CallOption) (*Passkey, error)
+	// passkey ของผู้ใช้เจ้าของ token
+	ListPasskeys(ctx context.Context, in *ListPasskeysRequest, opts ...grpc.CallOption) (*ListPasskeysReply, error)
+	// เปลี่ยนชื่อ passkey
+	RenamePasskey(ctx context.Context, in *RenamePasskeyRequest, opts ...grpc.CallOption) (*Passkey, error)
+	// ลบ passkey (ลบ passkey สุดท้ายแล้วจะยกเลิกการใช้ passkey เป็นปัจจัยที่สองด้วย)
+	DeletePasskey(ctx context.Context, in *DeletePasskeyRequest, opts ...grpc.CallOption) (*DeletePasskeyReply, error)
+	// บังคับใช้ passkey เป็นปัจจัยที่สองหลังเข้าสู่ระบบด้วยรหัสผ่าน (ต้องมี passkey อย่างน้อยหนึ่งอัน)
+	SetPasskeyRequired(ctx context.Context, in *SetPasskeyRequiredRequest, opts ...grpc.CallOption) (*SetPasskeyRequiredReply, error)
+	// เริ่มเข้าสู่ระบบด้วย passkey (ส่ง publicKeyOptions ให้ navigator.credentials.get)
+	BeginPasskeyLogin(ctx context.Context, in *BeginPasskeyLoginRequest, opts ...grpc.CallOption) (*BeginPasskeyLoginReply, error)
+	// ตรวจสอบ assertion แล้วออก token
+	FinishPasskeyLogin(ctx context.Context, in *FinishPasskeyLoginRequest, opts ...grpc.CallOption) (*FinishPasskeyLoginReply, error)
+}
+
+type passkeyServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPasskeyServiceClient(cc grpc.ClientConnInterface) PasskeyServiceClient {
+	return &passkeyServiceClient{cc}
+}
+
+func (c *passkeyServiceClient) BeginPasskeyRegistration(ctx context.Context, in *BeginPasskeyRegistrationRequest, opts ...grpc.CallOption) (*BeginPasskeyRegistrationReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BeginPasskeyRegistrationReply)
+	err := c.cc.Invoke(ctx, PasskeyService_BeginPasskeyRegistration_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *passkeyServiceClient) FinishPasskeyRegistration(ctx context.Context, in *FinishPasskeyRegistrationRequest, opts ...grpc.CallOption) (*Passkey, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Passkey)
+	err := c.cc.Invoke(ctx, PasskeyService_FinishPasskeyRegistration_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *passkeyServiceClient) ListPasskeys(ctx context.Context, in *ListPasskeysRequest, opts ...grpc.CallOption) (*ListPasskeysReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListPasskeysReply)
+	err := c.cc.Invoke(ctx, PasskeyService_ListPasskeys_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *passkeyServiceClient) RenamePasskey(ctx context.Context, in *RenamePasskeyRequest, opts ...grpc.CallOption) (*Passkey, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Passkey)
+	err := c.cc.Invoke(ctx, PasskeyService_RenamePasskey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *passkeyServiceClient) DeletePasskey(ctx context.Context, in *DeletePasskeyRequest, opts ...grpc.CallOption) (*DeletePasskeyReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeletePasskeyReply)
+	err := c.cc.Invoke(ctx, PasskeyService_DeletePasskey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *passkeyServiceClient) SetPasskeyRequired(ctx context.Context, in *SetPasskeyRequiredRequest, opts ...grpc.CallOption) (*SetPasskeyRequiredReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetPasskeyRequiredReply)
+	err := c.cc.Invoke(ctx, PasskeyService_SetPasskeyRequired_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *passkeyServiceClient) BeginPasskeyLogin(ctx context.Context, in *BeginPasskeyLoginRequest, opts ...grpc.CallOption) (*BeginPasskeyLoginReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BeginPasskeyLoginReply)
+	err := c.cc.Invoke(ctx, PasskeyService_BeginPasskeyLogin_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *passkeyServiceClient) FinishPasskeyLogin(ctx context.Context, in *FinishPasskeyLoginRequest, opts ...grpc.CallOption) (*FinishPasskeyLoginReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FinishPasskeyLoginReply)
+	err := c.cc.Invoke(ctx, PasskeyService_FinishPasskeyLogin_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PasskeyServiceServer is the server API for PasskeyService service.
+// All implementations must embed UnimplementedPasskeyServiceServer
+// for forward compatibility.
+//
+// บริการ PasskeyService สำหรับลงทะเบียน passkey (WebAuthn) และเข้าสู่ระบบโดยไม่ใช้รหัสผ่าน
+// การลงทะเบียนและจัดการ passkey ต้องแนบ token ใน metadata "authorization" ส่วนการเข้าสู่ระบบระบุ tenant ด้วย metadata "x-tenant-id"
+// publicKeyOptions และ credential เป็น JSON ตามรูปแบบของ WebAuthn Level 3
+// (PublicKeyCredential.parseCreationOptionsFromJSON / parseRequestOptionsFromJSON และ credential.toJSON())
+type PasskeyServiceServer interface {
+	// เริ่มลงทะเบียน passkey (ส่ง publicKeyOptions ให้ navigator.credentials.create)
+	BeginPasskeyRegistration(context.Context, *BeginPasskeyRegistrationRequest) (*BeginPasskeyRegistrationReply, error)
+	// ตรวจสอบ attestation แล้วบันทึก passkey ของผู้ใช้
+	FinishPasskeyRegistration(context.Context, *FinishPasskeyRegistrationRequest) (*Passkey, error)
+	// passkey ของผู้ใช้เจ้าของ token
+	ListPasskeys(context.Context, *ListPasskeysRequest) (*ListPasskeysReply, error)
+	// เปลี่ยนชื่อ passkey
+	RenamePasskey(context.Context, *RenamePasskeyRequest) (*Passkey, error)
+	// ลบ passkey (ลบ passkey สุดท้ายแล้วจะยกเลิกการใช้ passkey เป็นปัจจัยที่สองด้วย)
+	DeletePasskey(context.Context, *DeletePasskeyRequest) (*DeletePasskeyReply, error)
+	// บังคับใช้ passkey เป็นปัจจัยที่สองหลังเข้าสู่ระบบด้วยรหัสผ่าน (ต้องมี passkey อย่างน้อยหนึ่งอัน)
+	SetPasskeyRequired(context.Context, *SetPasskeyRequiredRequest) (*SetPasskeyRequiredReply, error)
+	// เริ่มเข้าสู่ระบบด้วย passkey (ส่ง publicKeyOptions ให้ navigator.credentials.get)
+	BeginPasskeyLogin(context.Context, *BeginPasskeyLoginRequest) (*BeginPasskeyLoginReply, error)
+	// ตรวจสอบ assertion แล้วออก token
+	FinishPasskeyLogin(context.Context, *FinishPasskeyLoginRequest) (*FinishPasskeyLoginReply, error)
+	mustEmbedUnimplementedPasskeyServiceServer()
+}
+
+// UnimplementedPasskeyServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedPasskeyServiceServer struct{}
+
+func (UnimplementedPasskeyServiceServer) BeginPasskeyRegistration(context.Context, *BeginPasskeyRegistrationRequest) (*BeginPasskeyRegistrationReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BeginPasskeyRegistration not implemented")
+}
+func (UnimplementedPasskeyServiceServer) FinishPasskeyRegistration(context.Context, *FinishPasskeyRegistrationRequest) (*Passkey, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FinishPasskeyRegistration not implemented")
+}
+func (UnimplementedPasskeyServiceServer) ListPasskeys(context.Context, *ListPasskeysRequest) (*ListPasskeysReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPasskeys not implemented")
+}
+func (UnimplementedPasskeyServiceServer) RenamePasskey(context.Context, *RenamePasskeyRequest) (*Passkey, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RenamePasskey not implemented")
+}
+func (UnimplementedPasskeyServiceServer) DeletePasskey(context.Context, *DeletePasskeyRequest) (*DeletePasskeyReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeletePasskey not implemented")
+}
+func (UnimplementedPasskeyServiceServer) SetPasskeyRequired(context.Context, *SetPasskeyRequiredRequest) (*SetPasskeyRequiredReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetPasskeyRequired not implemented")
+}
+func (UnimplementedPasskeyServiceServer) BeginPasskeyLogin(context.Context, *BeginPasskeyLoginRequest) (*BeginPasskeyLoginReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BeginPasskeyLogin not implemented")
+}
+func (UnimplementedPasskeyServiceServer) FinishPasskeyLogin(context.Context, *FinishPasskeyLoginRequest) (*FinishPasskeyLoginReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FinishPasskeyLogin not implemented")
+}
+func (UnimplementedPasskeyServiceServer) mustEmbedUnimplementedPasskeyServiceServer() {}
+func (UnimplementedPasskeyServiceServer) testEmbeddedByValue()                        {}
+
+// UnsafePasskeyServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PasskeyServiceServer will
+// result in compilation errors.
+type UnsafePasskeyServiceServer interface {
+	mustEmbedUnimplementedPasskeyServiceServer()
+}
+
+func RegisterPasskeyServiceServer(s grpc.ServiceRegistrar, srv PasskeyServiceServer) {
+	// If the following call pancis, it indicates UnimplementedPasskeyServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&PasskeyService_ServiceDesc, srv)
+}
+
+func _PasskeyService_BeginPasskeyRegistration_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BeginPasskeyRegistrationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PasskeyServiceServer).BeginPasskeyRegistration(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PasskeyService_BeginPasskeyRegistration_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PasskeyServiceServer).BeginPasskeyRegistration(ctx, req.(*BeginPasskeyRegistrationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PasskeyService_FinishPasskeyRegistration_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FinishPasskeyRegistrationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PasskeyServiceServer).FinishPasskeyRegistration(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PasskeyService_FinishPasskeyRegistration_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PasskeyServiceServer).FinishPasskeyRegistration(ctx, req.(*FinishPasskeyRegistrationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PasskeyService_ListPasskeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPasskeysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PasskeyServiceServer).ListPasskeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PasskeyService_ListPasskeys_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PasskeyServiceServer).ListPasskeys(ctx, req.(*ListPasskeysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PasskeyService_RenamePasskey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenamePasskeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PasskeyServiceServer).RenamePasskey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PasskeyService_RenamePasskey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PasskeyServiceServer).RenamePasskey(ctx, req.(*RenamePasskeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PasskeyService_DeletePasskey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeletePasskeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PasskeyServiceServer).DeletePasskey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PasskeyService_DeletePasskey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PasskeyServiceServer).DeletePasskey(ctx, req.(*DeletePasskeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PasskeyService_SetPasskeyRequired_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetPasskeyRequiredRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PasskeyServiceServer).SetPasskeyRequired(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PasskeyService_SetPasskeyRequired_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PasskeyServiceServer).SetPasskeyRequired(ctx, req.(*SetPasskeyRequiredRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PasskeyService_BeginPasskeyLogin_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BeginPasskeyLoginRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PasskeyServiceServer).BeginPasskeyLogin(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PasskeyService_BeginPasskeyLogin_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PasskeyServiceServer).BeginPasskeyLogin(ctx, req.(*BeginPasskeyLoginRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PasskeyService_FinishPasskeyLogin_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FinishPasskeyLoginRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PasskeyServiceServer).FinishPasskeyLogin(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PasskeyService_FinishPasskeyLogin_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PasskeyServiceServer).FinishPasskeyLogin(ctx, req.(*FinishPasskeyLoginRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// PasskeyService_ServiceDesc is the grpc.ServiceDesc for PasskeyService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PasskeyService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "PasskeyService",
+	HandlerType: (*PasskeyServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "BeginPasskeyRegistration",
+			Handler:    _PasskeyService_BeginPasskeyRegistration_Handler,
+		},
+		{
+			MethodName: "FinishPasskeyRegistration",
+			Handler:    _PasskeyService_FinishPasskeyRegistration_Handler,
+		},
+		{
+			MethodName: "ListPasskeys",
+			Handler:    _PasskeyService_ListPasskeys_Handler,
+		},
+		{
+			MethodName: "RenamePasskey",
+			Handler:    _PasskeyService_RenamePasskey_Handler,
+		},
+		{
+			MethodName: "DeletePasskey",
+			Handler:    _PasskeyService_DeletePasskey_Handler,
+		},
+		{
+			MethodName: "SetPasskeyRequired",
+			Handler:    _PasskeyService_SetPasskeyRequired_Handler,
+		},
+		{
+			MethodName: "BeginPasskeyLogin",
+			Handler:    _PasskeyService_BeginPasskeyLogin_Handler,
+		},
+		{
+			MethodName: "FinishPasskeyLogin",
+			Handler:    _PasskeyService_FinishPasskeyLogin_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/passkey.proto",
+}
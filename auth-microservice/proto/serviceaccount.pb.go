@@ -0,0 +1,967 @@
+// กำหนด version ของ Protocol Buffers ที่ใช้
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v6.31.1
+// source: proto/serviceaccount.proto
+
+package proto
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// client secret (ไม่มี secret จริง)
+type ClientSecret struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Prefix        string                 `protobuf:"bytes,2,opt,name=prefix,proto3" json:"prefix,omitempty"` // ส่วนต้นของ secret ใช้จำแนก secret
+	CreatedAt     string                 `protobuf:"bytes,3,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+	ExpiresAt     string                 `protobuf:"bytes,4,opt,name=expiresAt,proto3" json:"expiresAt,omitempty"` // ค่าว่าง = ไม่หมดอายุ (secret ล่าสุด)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClientSecret) Reset() {
+	*x = ClientSecret{}
+	mi := &file_proto_serviceaccount_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClientSecret) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClientSecret) ProtoMessage() {}
+
+func (x *ClientSecret) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_serviceaccount_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClientSecret.ProtoReflect.Descriptor instead.
+func (*ClientSecret) Descriptor() ([]byte, []int) {
+	return file_proto_serviceaccount_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ClientSecret) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ClientSecret) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *ClientSecret) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *ClientSecret) GetExpiresAt() string {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return ""
+}
+
+// public key ของ client
+type ClientPublicKey struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"` // kid ที่ client ต้องใส่ใน header ของ client assertion
+	CreatedAt     string                 `protobuf:"bytes,2,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClientPublicKey) Reset() {
+	*x = ClientPublicKey{}
+	mi := &file_proto_serviceaccount_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClientPublicKey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClientPublicKey) ProtoMessage() {}
+
+func (x *ClientPublicKey) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_serviceaccount_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClientPublicKey.ProtoReflect.Descriptor instead.
+func (*ClientPublicKey) Descriptor() ([]byte, []int) {
+	return file_proto_serviceaccount_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ClientPublicKey) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ClientPublicKey) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+// ข้อมูล service account
+type ServiceAccount struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	ClientId      string                 `protobuf:"bytes,4,opt,name=clientId,proto3" json:"clientId,omitempty"`
+	Role          string                 `protobuf:"bytes,5,opt,name=role,proto3" json:"role,omitempty"`     // "service", "tenant_admin" หรือ "admin"
+	Scopes        []string               `protobuf:"bytes,6,rep,name=scopes,proto3" json:"scopes,omitempty"` // scope สูงสุดที่ขอใน token ได้
+	Secrets       []*ClientSecret        `protobuf:"bytes,7,rep,name=secrets,proto3" json:"secrets,omitempty"`
+	PublicKeys    []*ClientPublicKey     `protobuf:"bytes,8,rep,name=publicKeys,proto3" json:"publicKeys,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,9,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+	UpdatedAt     string                 `protobuf:"bytes,10,opt,name=updatedAt,proto3" json:"updatedAt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ServiceAccount) Reset() {
+	*x = ServiceAccount{}
+	mi := &file_proto_serviceaccount_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ServiceAccount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServiceAccount) ProtoMessage() {}
+
+func (x *ServiceAccount) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_serviceaccount_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServiceAccount.ProtoReflect.Descriptor instead.
+func (*ServiceAccount) Descriptor() ([]byte, []int) {
+	return file_proto_serviceaccount_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ServiceAccount) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ServiceAccount) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ServiceAccount) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *ServiceAccount) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+func (x *ServiceAccount) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *ServiceAccount) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+func (x *ServiceAccount) GetSecrets() []*ClientSecret {
+	if x != nil {
+		return x.Secrets
+	}
+	return nil
+}
+
+func (x *ServiceAccount) GetPublicKeys() []*ClientPublicKey {
+	if x != nil {
+		return x.PublicKeys
+	}
+	return nil
+}
+
+func (x *ServiceAccount) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *ServiceAccount) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+// ข้อมูลสำหรับสร้าง service account
+type CreateServiceAccountRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	Role          string                 `protobuf:"bytes,3,opt,name=role,proto3" json:"role,omitempty"`     // ค่าว่าง = "service"
+	Scopes        []string               `protobuf:"bytes,4,rep,name=scopes,proto3" json:"scopes,omitempty"` // เช่น "users:read", "groups:write"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateServiceAccountRequest) Reset() {
+	*x = CreateServiceAccountRequest{}
+	mi := &file_proto_serviceaccount_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateServiceAccountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateServiceAccountRequest) ProtoMessage() {}
+
+func (x *CreateServiceAccountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_serviceaccount_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateServiceAccountRequest.ProtoReflect.Descriptor instead.
+func (*CreateServiceAccountRequest) Descriptor() ([]byte, []int) {
+	return file_proto_serviceaccount_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CreateServiceAccountRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateServiceAccountRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *CreateServiceAccountRequest) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *CreateServiceAccountRequest) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+type CreateServiceAccountReply struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ServiceAccount *ServiceAccount        `protobuf:"bytes,1,opt,name=serviceAccount,proto3" json:"serviceAccount,omitempty"`
+	ClientSecret   string                 `protobuf:"bytes,2,opt,name=clientSecret,proto3" json:"clientSecret,omitempty"` // secret จริง แสดงครั้งเดียว ให้เก็บไว้ทันที
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CreateServiceAccountReply) Reset() {
+	*x = CreateServiceAccountReply{}
+	mi := &file_proto_serviceaccount_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateServiceAccountReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateServiceAccountReply) ProtoMessage() {}
+
+func (x *CreateServiceAccountReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_serviceaccount_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateServiceAccountReply.ProtoReflect.Descriptor instead.
+func (*CreateServiceAccountReply) Descriptor() ([]byte, []int) {
+	return file_proto_serviceaccount_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CreateServiceAccountReply) GetServiceAccount() *ServiceAccount {
+	if x != nil {
+		return x.ServiceAccount
+	}
+	return nil
+}
+
+func (x *CreateServiceAccountReply) GetClientSecret() string {
+	if x != nil {
+		return x.ClientSecret
+	}
+	return ""
+}
+
+type GetServiceAccountRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetServiceAccountRequest) Reset() {
+	*x = GetServiceAccountRequest{}
+	mi := &file_proto_serviceaccount_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetServiceAccountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetServiceAccountRequest) ProtoMessage() {}
+
+func (x *GetServiceAccountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_serviceaccount_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetServiceAccountRequest.ProtoReflect.Descriptor instead.
+func (*GetServiceAccountRequest) Descriptor() ([]byte, []int) {
+	return file_proto_serviceaccount_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetServiceAccountRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ListServiceAccountsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListServiceAccountsRequest) Reset() {
+	*x = ListServiceAccountsRequest{}
+	mi := &file_proto_serviceaccount_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListServiceAccountsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListServiceAccountsRequest) ProtoMessage() {}
+
+func (x *ListServiceAccountsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_serviceaccount_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListServiceAccountsRequest.ProtoReflect.Descriptor instead.
+func (*ListServiceAccountsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_serviceaccount_proto_rawDescGZIP(), []int{6}
+}
+
+type ListServiceAccountsReply struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ServiceAccounts []*ServiceAccount      `protobuf:"bytes,1,rep,name=serviceAccounts,proto3" json:"serviceAccounts,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ListServiceAccountsReply) Reset() {
+	*x = ListServiceAccountsReply{}
+	mi := &file_proto_serviceaccount_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListServiceAccountsReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListServiceAccountsReply) ProtoMessage() {}
+
+func (x *ListServiceAccountsReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_serviceaccount_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListServiceAccountsReply.ProtoReflect.Descriptor instead.
+func (*ListServiceAccountsReply) Descriptor() ([]byte, []int) {
+	return file_proto_serviceaccount_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListServiceAccountsReply) GetServiceAccounts() []*ServiceAccount {
+	if x != nil {
+		return x.ServiceAccounts
+	}
+	return nil
+}
+
+type DeleteServiceAccountRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteServiceAccountRequest) Reset() {
+	*x = DeleteServiceAccountRequest{}
+	mi := &file_proto_serviceaccount_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteServiceAccountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteServiceAccountRequest) ProtoMessage() {}
+
+func (x *DeleteServiceAccountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_serviceaccount_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteServiceAccountRequest.ProtoReflect.Descriptor instead.
+func (*DeleteServiceAccountRequest) Descriptor() ([]byte, []int) {
+	return file_proto_serviceaccount_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *DeleteServiceAccountRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type DeleteServiceAccountReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteServiceAccountReply) Reset() {
+	*x = DeleteServiceAccountReply{}
+	mi := &file_proto_serviceaccount_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteServiceAccountReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteServiceAccountReply) ProtoMessage() {}
+
+func (x *DeleteServiceAccountReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_serviceaccount_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteServiceAccountReply.ProtoReflect.Descriptor instead.
+func (*DeleteServiceAccountReply) Descriptor() ([]byte, []int) {
+	return file_proto_serviceaccount_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *DeleteServiceAccountReply) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type RotateClientSecretRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	OverlapSeconds int64                  `protobuf:"varint,2,opt,name=overlapSeconds,proto3" json:"overlapSeconds,omitempty"` // secret เดิมใช้ได้ต่ออีกกี่วินาที (ค่าว่าง = 24 ชั่วโมง, สูงสุด 7 วัน)
+	RevokePrevious bool                   `protobuf:"varint,3,opt,name=revokePrevious,proto3" json:"revokePrevious,omitempty"` // true = ยกเลิก secret เดิมทันที (เช่น secret รั่ว)
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *RotateClientSecretRequest) Reset() {
+	*x = RotateClientSecretRequest{}
+	mi := &file_proto_serviceaccount_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RotateClientSecretRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotateClientSecretRequest) ProtoMessage() {}
+
+func (x *RotateClientSecretRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_serviceaccount_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotateClientSecretRequest.ProtoReflect.Descriptor instead.
+func (*RotateClientSecretRequest) Descriptor() ([]byte, []int) {
+	return file_proto_serviceaccount_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *RotateClientSecretRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RotateClientSecretRequest) GetOverlapSeconds() int64 {
+	if x != nil {
+		return x.OverlapSeconds
+	}
+	return 0
+}
+
+func (x *RotateClientSecretRequest) GetRevokePrevious() bool {
+	if x != nil {
+		return x.RevokePrevious
+	}
+	return false
+}
+
+type RotateClientSecretReply struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ServiceAccount *ServiceAccount        `protobuf:"bytes,1,opt,name=serviceAccount,proto3" json:"serviceAccount,omitempty"`
+	ClientSecret   string                 `protobuf:"bytes,2,opt,name=clientSecret,proto3" json:"clientSecret,omitempty"` // secret ใหม่ แสดงครั้งเดียว
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *RotateClientSecretReply) Reset() {
+	*x = RotateClientSecretReply{}
+	mi := &file_proto_serviceaccount_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RotateClientSecretReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotateClientSecretReply) ProtoMessage() {}
+
+func (x *RotateClientSecretReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_serviceaccount_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotateClientSecretReply.ProtoReflect.Descriptor instead.
+func (*RotateClientSecretReply) Descriptor() ([]byte, []int) {
+	return file_proto_serviceaccount_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *RotateClientSecretReply) GetServiceAccount() *ServiceAccount {
+	if x != nil {
+		return x.ServiceAccount
+	}
+	return nil
+}
+
+func (x *RotateClientSecretReply) GetClientSecret() string {
+	if x != nil {
+		return x.ClientSecret
+	}
+	return ""
+}
+
+type AddClientPublicKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	PublicKeyPem  string                 `protobuf:"bytes,2,opt,name=publicKeyPem,proto3" json:"publicKeyPem,omitempty"` // RSA หรือ EC public key ในรูปแบบ PEM
+	KeyId         string                 `protobuf:"bytes,3,opt,name=keyId,proto3" json:"keyId,omitempty"`               // kid (ค่าว่าง = สุ่มให้)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddClientPublicKeyRequest) Reset() {
+	*x = AddClientPublicKeyRequest{}
+	mi := &file_proto_serviceaccount_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddClientPublicKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddClientPublicKeyRequest) ProtoMessage() {}
+
+func (x *AddClientPublicKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_serviceaccount_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddClientPublicKeyRequest.ProtoReflect.Descriptor instead.
+func (*AddClientPublicKeyRequest) Descriptor() ([]byte, []int) {
+	return file_proto_serviceaccount_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *AddClientPublicKeyRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *AddClientPublicKeyRequest) GetPublicKeyPem() string {
+	if x != nil {
+		return x.PublicKeyPem
+	}
+	return ""
+}
+
+func (x *AddClientPublicKeyRequest) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+type RemoveClientPublicKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	KeyId         string                 `protobuf:"bytes,2,opt,name=keyId,proto3" json:"keyId,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveClientPublicKeyRequest) Reset() {
+	*x = RemoveClientPublicKeyRequest{}
+	mi := &file_proto_serviceaccount_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveClientPublicKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveClientPublicKeyRequest) ProtoMessage() {}
+
+func (x *RemoveClientPublicKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_serviceaccount_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveClientPublicKeyRequest.ProtoReflect.Descriptor instead.
+func (*RemoveClientPublicKeyRequest) Descriptor() ([]byte, []int) {
+	return file_proto_serviceaccount_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *RemoveClientPublicKeyRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RemoveClientPublicKeyRequest) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+var File_proto_serviceaccount_proto protoreflect.FileDescriptor
+
+const file_proto_serviceaccount_proto_rawDesc = "" +
+	"\n" +
+	"\x1aproto/serviceaccount.proto\"r\n" +
+	"\fClientSecret\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06prefix\x18\x02 \x01(\tR\x06prefix\x12\x1c\n" +
+	"\tcreatedAt\x18\x03 \x01(\tR\tcreatedAt\x12\x1c\n" +
+	"\texpiresAt\x18\x04 \x01(\tR\texpiresAt\"?\n" +
+	"\x0fClientPublicKey\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1c\n" +
+	"\tcreatedAt\x18\x02 \x01(\tR\tcreatedAt\"\xb5\x02\n" +
+	"\x0eServiceAccount\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x1a\n" +
+	"\bclientId\x18\x04 \x01(\tR\bclientId\x12\x12\n" +
+	"\x04role\x18\x05 \x01(\tR\x04role\x12\x16\n" +
+	"\x06scopes\x18\x06 \x03(\tR\x06scopes\x12'\n" +
+	"\asecrets\x18\a \x03(\v2\r.ClientSecretR\asecrets\x120\n" +
+	"\n" +
+	"publicKeys\x18\b \x03(\v2\x10.ClientPublicKeyR\n" +
+	"publicKeys\x12\x1c\n" +
+	"\tcreatedAt\x18\t \x01(\tR\tcreatedAt\x12\x1c\n" +
+	"\tupdatedAt\x18\n" +
+	" \x01(\tR\tupdatedAt\"\x7f\n" +
+	"\x1bCreateServiceAccountRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x12\n" +
+	"\x04role\x18\x03 \x01(\tR\x04role\x12\x16\n" +
+	"\x06scopes\x18\x04 \x03(\tR\x06scopes\"x\n" +
+	"\x19CreateServiceAccountReply\x127\n" +
+	"\x0eserviceAccount\x18\x01 \x01(\v2\x0f.ServiceAccountR\x0eserviceAccount\x12\"\n" +
+	"\fclientSecret\x18\x02 \x01(\tR\fclientSecret\"*\n" +
+	"\x18GetServiceAccountRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x1c\n" +
+	"\x1aListServiceAccountsRequest\"U\n" +
+	"\x18ListServiceAccountsReply\x129\n" +
+	"\x0fserviceAccounts\x18\x01 \x03(\v2\x0f.ServiceAccountR\x0fserviceAccounts\"-\n" +
+	"\x1bDeleteServiceAccountRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"5\n" +
+	"\x19DeleteServiceAccountReply\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\"{\n" +
+	"\x19RotateClientSecretRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12&\n" +
+	"\x0eoverlapSeconds\x18\x02 \x01(\x03R\x0eoverlapSeconds\x12&\n" +
+	"\x0erevokePrevious\x18\x03 \x01(\bR\x0erevokePrevious\"v\n" +
+	"\x17RotateClientSecretReply\x127\n" +
+	"\x0eserviceAccount\x18\x01 \x01(\v2\x0f.ServiceAccountR\x0eserviceAccount\x12\"\n" +
+	"\fclientSecret\x18\x02 \x01(\tR\fclientSecret\"e\n" +
+	"\x19AddClientPublicKeyRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\"\n" +
+	"\fpublicKeyPem\x18\x02 \x01(\tR\fpublicKeyPem\x12\x14\n" +
+	"\x05keyId\x18\x03 \x01(\tR\x05keyId\"D\n" +
+	"\x1cRemoveClientPublicKeyRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05keyId\x18\x02 \x01(\tR\x05keyId2\xb1\x04\n" +
+	"\x15ServiceAccountService\x12R\n" +
+	"\x14CreateServiceAccount\x12\x1c.CreateServiceAccountRequest\x1a\x1a.CreateServiceAccountReply\"\x00\x12A\n" +
+	"\x11GetServiceAccount\x12\x19.GetServiceAccountRequest\x1a\x0f.ServiceAccount\"\x00\x12O\n" +
+	"\x13ListServiceAccounts\x12\x1b.ListServiceAccountsRequest\x1a\x19.ListServiceAccountsReply\"\x00\x12R\n" +
+	"\x14DeleteServiceAccount\x12\x1c.DeleteServiceAccountRequest\x1a\x1a.DeleteServiceAccountReply\"\x00\x12L\n" +
+	"\x12RotateClientSecret\x12\x1a.RotateClientSecretRequest\x1a\x18.RotateClientSecretReply\"\x00\x12C\n" +
+	"\x12AddClientPublicKey\x12\x1a.AddClientPublicKeyRequest\x1a\x0f.ServiceAccount\"\x00\x12I\n" +
+	"\x15RemoveClientPublicKey\x12\x1d.RemoveClientPublicKeyRequest\x1a\x0f.ServiceAccount\"\x00B\x19Z\x17auth-microservice/protob\x06proto3"
+
+var (
+	file_proto_serviceaccount_proto_rawDescOnce sync.Once
+	file_proto_serviceaccount_proto_rawDescData []byte
+)
+
+func file_proto_serviceaccount_proto_rawDescGZIP() []byte {
+	file_proto_serviceaccount_proto_rawDescOnce.Do(func() {
+		file_proto_serviceaccount_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_serviceaccount_proto_rawDesc), len(file_proto_serviceaccount_proto_rawDesc)))
+	})
+	return file_proto_serviceaccount_proto_rawDescData
+}
+
+var file_proto_serviceaccount_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
+var file_proto_serviceaccount_proto_goTypes = []any{
+	(*ClientSecret)(nil),                 // 0: ClientSecret
+	(*ClientPublicKey)(nil),              // 1: ClientPublicKey
+	(*ServiceAccount)(nil),               // 2: ServiceAccount
+	(*CreateServiceAccountRequest)(nil),  // 3: CreateServiceAccountRequest
+	(*CreateServiceAccountReply)(nil),    // 4: CreateServiceAccountReply
+	(*GetServiceAccountRequest)(nil),     // 5: GetServiceAccountRequest
+	(*ListServiceAccountsRequest)(nil),   // 6: ListServiceAccountsRequest
+	(*ListServiceAccountsReply)(nil),     // 7: ListServiceAccountsReply
+	(*DeleteServiceAccountRequest)(nil),  // 8: DeleteServiceAccountRequest
+	(*DeleteServiceAccountReply)(nil),    // 9: DeleteServiceAccountReply
+	(*RotateClientSecretRequest)(nil),    // 10: RotateClientSecretRequest
+	(*RotateClientSecretReply)(nil),      // 11: RotateClientSecretReply
+	(*AddClientPublicKeyRequest)(nil),    // 12: AddClientPublicKeyRequest
+	(*RemoveClientPublicKeyRequest)(nil), // 13: RemoveClientPublicKeyRequest
+}
+var file_proto_serviceaccount_proto_depIdxs = []int32{
+	0,  // 0: ServiceAccount.secrets:type_name -> ClientSecret
+	1,  // 1: ServiceAccount.publicKeys:type_name -> ClientPublicKey
+	2,  // 2: CreateServiceAccountReply.serviceAccount:type_name -> ServiceAccount
+	2,  // 3: ListServiceAccountsReply.serviceAccounts:type_name -> ServiceAccount
+	2,  // 4: RotateClientSecretReply.serviceAccount:type_name -> ServiceAccount
+	3,  // 5: ServiceAccountService.CreateServiceAccount:input_type -> CreateServiceAccountRequest
+	5,  // 6: ServiceAccountService.GetServiceAccount:input_type -> GetServiceAccountRequest
+	6,  // 7: ServiceAccountService.ListServiceAccounts:input_type -> ListServiceAccountsRequest
+	8,  // 8: ServiceAccountService.DeleteServiceAccount:input_type -> DeleteServiceAccountRequest
+	10, // 9: ServiceAccountService.RotateClientSecret:input_type -> RotateClientSecretRequest
+	12, // 10: ServiceAccountService.AddClientPublicKey:input_type -> AddClientPublicKeyRequest
+	13, // 11: ServiceAccountService.RemoveClientPublicKey:input_type -> RemoveClientPublicKeyRequest
+	4,  // 12: ServiceAccountService.CreateServiceAccount:output_type -> CreateServiceAccountReply
+	2,  // 13: ServiceAccountService.GetServiceAccount:output_type -> ServiceAccount
+	7,  // 14: ServiceAccountService.ListServiceAccounts:output_type -> ListServiceAccountsReply
+	9,  // 15: ServiceAccountService.DeleteServiceAccount:output_type -> DeleteServiceAccountReply
+	11, // 16: ServiceAccountService.RotateClientSecret:output_type -> RotateClientSecretReply
+	2,  // 17: ServiceAccountService.AddClientPublicKey:output_type -> ServiceAccount
+	2,  // 18: ServiceAccountService.RemoveClientPublicKey:output_type -> ServiceAccount
+	12, // [12:19] is the sub-list for method output_type
+	5,  // [5:12] is the sub-list for method input_type
+	5,  // [5:5] is the sub-list for extension type_name
+	5,  // [5:5] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_proto_serviceaccount_proto_init() }
+func file_proto_serviceaccount_proto_init() {
+	if File_proto_serviceaccount_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_serviceaccount_proto_rawDesc), len(file_proto_serviceaccount_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   14,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_serviceaccount_proto_goTypes,
+		DependencyIndexes: file_proto_serviceaccount_proto_depIdxs,
+		MessageInfos:      file_proto_serviceaccount_proto_msgTypes,
+	}.Build()
+	File_proto_serviceaccount_proto = out.File
+	file_proto_serviceaccount_proto_goTypes = nil
+	file_proto_serviceaccount_proto_depIdxs = nil
+}
@@ -0,0 +1,865 @@
+// กำหนด version ของ Protocol Buffers ที่ใช้
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v6.31.1
+// source: proto/tenant.proto
+
+package proto
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// นโยบายรหัสผ่านของ tenant
+type PasswordPolicy struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MinLength     int32                  `protobuf:"varint,1,opt,name=minLength,proto3" json:"minLength,omitempty"`         // ความยาวขั้นต่ำ (6-72)
+	RequireUpper  bool                   `protobuf:"varint,2,opt,name=requireUpper,proto3" json:"requireUpper,omitempty"`   // ต้องมีตัวพิมพ์ใหญ่
+	RequireLower  bool                   `protobuf:"varint,3,opt,name=requireLower,proto3" json:"requireLower,omitempty"`   // ต้องมีตัวพิมพ์เล็ก
+	RequireNumber bool                   `protobuf:"varint,4,opt,name=requireNumber,proto3" json:"requireNumber,omitempty"` // ต้องมีตัวเลข
+	RequireSymbol bool                   `protobuf:"varint,5,opt,name=requireSymbol,proto3" json:"requireSymbol,omitempty"` // ต้องมีอักขระพิเศษ
+	HistorySize   int32                  `protobuf:"varint,6,opt,name=historySize,proto3" json:"historySize,omitempty"`     // จำนวนรหัสผ่านเก่าที่ห้ามใช้ซ้ำ (0-24)
+	MaxAgeDays    int32                  `protobuf:"varint,7,opt,name=maxAgeDays,proto3" json:"maxAgeDays,omitempty"`       // อายุสูงสุดของรหัสผ่าน (0 = ไม่หมดอายุ)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PasswordPolicy) Reset() {
+	*x = PasswordPolicy{}
+	mi := &file_proto_tenant_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PasswordPolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PasswordPolicy) ProtoMessage() {}
+
+func (x *PasswordPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tenant_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PasswordPolicy.ProtoReflect.Descriptor instead.
+func (*PasswordPolicy) Descriptor() ([]byte, []int) {
+	return file_proto_tenant_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *PasswordPolicy) GetMinLength() int32 {
+	if x != nil {
+		return x.MinLength
+	}
+	return 0
+}
+
+func (x *PasswordPolicy) GetRequireUpper() bool {
+	if x != nil {
+		return x.RequireUpper
+	}
+	return false
+}
+
+func (x *PasswordPolicy) GetRequireLower() bool {
+	if x != nil {
+		return x.RequireLower
+	}
+	return false
+}
+
+func (x *PasswordPolicy) GetRequireNumber() bool {
+	if x != nil {
+		return x.RequireNumber
+	}
+	return false
+}
+
+func (x *PasswordPolicy) GetRequireSymbol() bool {
+	if x != nil {
+		return x.RequireSymbol
+	}
+	return false
+}
+
+func (x *PasswordPolicy) GetHistorySize() int32 {
+	if x != nil {
+		return x.HistorySize
+	}
+	return 0
+}
+
+func (x *PasswordPolicy) GetMaxAgeDays() int32 {
+	if x != nil {
+		return x.MaxAgeDays
+	}
+	return 0
+}
+
+// ข้อมูลของ signing key (ไม่รวม secret)
+type SigningKeyInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"` // kid ใน header ของ token
+	CreatedAt     string                 `protobuf:"bytes,2,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+	Active        bool                   `protobuf:"varint,3,opt,name=active,proto3" json:"active,omitempty"` // ใช้เซ็น token ใหม่อยู่หรือไม่
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SigningKeyInfo) Reset() {
+	*x = SigningKeyInfo{}
+	mi := &file_proto_tenant_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SigningKeyInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SigningKeyInfo) ProtoMessage() {}
+
+func (x *SigningKeyInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tenant_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SigningKeyInfo.ProtoReflect.Descriptor instead.
+func (*SigningKeyInfo) Descriptor() ([]byte, []int) {
+	return file_proto_tenant_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SigningKeyInfo) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *SigningKeyInfo) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *SigningKeyInfo) GetActive() bool {
+	if x != nil {
+		return x.Active
+	}
+	return false
+}
+
+// ข้อมูล tenant
+type Tenant struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	Id                    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name                  string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Status                string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"` // "active" หรือ "suspended"
+	PasswordPolicy        *PasswordPolicy        `protobuf:"bytes,4,opt,name=passwordPolicy,proto3" json:"passwordPolicy,omitempty"`
+	AccessTokenTtlSeconds int64                  `protobuf:"varint,5,opt,name=accessTokenTtlSeconds,proto3" json:"accessTokenTtlSeconds,omitempty"` // อายุของ JWT
+	SessionTtlSeconds     int64                  `protobuf:"varint,6,opt,name=sessionTtlSeconds,proto3" json:"sessionTtlSeconds,omitempty"`         // อายุของ session
+	SigningKeys           []*SigningKeyInfo      `protobuf:"bytes,7,rep,name=signingKeys,proto3" json:"signingKeys,omitempty"`
+	CreatedAt             string                 `protobuf:"bytes,8,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+	UpdatedAt             string                 `protobuf:"bytes,9,opt,name=updatedAt,proto3" json:"updatedAt,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *Tenant) Reset() {
+	*x = Tenant{}
+	mi := &file_proto_tenant_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Tenant) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Tenant) ProtoMessage() {}
+
+func (x *Tenant) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tenant_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Tenant.ProtoReflect.Descriptor instead.
+func (*Tenant) Descriptor() ([]byte, []int) {
+	return file_proto_tenant_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Tenant) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Tenant) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Tenant) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Tenant) GetPasswordPolicy() *PasswordPolicy {
+	if x != nil {
+		return x.PasswordPolicy
+	}
+	return nil
+}
+
+func (x *Tenant) GetAccessTokenTtlSeconds() int64 {
+	if x != nil {
+		return x.AccessTokenTtlSeconds
+	}
+	return 0
+}
+
+func (x *Tenant) GetSessionTtlSeconds() int64 {
+	if x != nil {
+		return x.SessionTtlSeconds
+	}
+	return 0
+}
+
+func (x *Tenant) GetSigningKeys() []*SigningKeyInfo {
+	if x != nil {
+		return x.SigningKeys
+	}
+	return nil
+}
+
+func (x *Tenant) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *Tenant) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+// ข้อมูลสำหรับคำขอสร้าง tenant (ไม่ระบุการตั้งค่า = ใช้ค่าเริ่มต้น)
+type CreateTenantRequest struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	Id                    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"` // ตัวพิมพ์เล็ก ตัวเลข หรือ - เช่น "shop"
+	Name                  string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	PasswordPolicy        *PasswordPolicy        `protobuf:"bytes,3,opt,name=passwordPolicy,proto3" json:"passwordPolicy,omitempty"`
+	AccessTokenTtlSeconds int64                  `protobuf:"varint,4,opt,name=accessTokenTtlSeconds,proto3" json:"accessTokenTtlSeconds,omitempty"`
+	SessionTtlSeconds     int64                  `protobuf:"varint,5,opt,name=sessionTtlSeconds,proto3" json:"sessionTtlSeconds,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *CreateTenantRequest) Reset() {
+	*x = CreateTenantRequest{}
+	mi := &file_proto_tenant_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTenantRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTenantRequest) ProtoMessage() {}
+
+func (x *CreateTenantRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tenant_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTenantRequest.ProtoReflect.Descriptor instead.
+func (*CreateTenantRequest) Descriptor() ([]byte, []int) {
+	return file_proto_tenant_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CreateTenantRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *CreateTenantRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateTenantRequest) GetPasswordPolicy() *PasswordPolicy {
+	if x != nil {
+		return x.PasswordPolicy
+	}
+	return nil
+}
+
+func (x *CreateTenantRequest) GetAccessTokenTtlSeconds() int64 {
+	if x != nil {
+		return x.AccessTokenTtlSeconds
+	}
+	return 0
+}
+
+func (x *CreateTenantRequest) GetSessionTtlSeconds() int64 {
+	if x != nil {
+		return x.SessionTtlSeconds
+	}
+	return 0
+}
+
+// ข้อมูลสำหรับคำขอดู tenant
+type GetTenantRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"` // ว่าง = tenant ของผู้เรียก
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTenantRequest) Reset() {
+	*x = GetTenantRequest{}
+	mi := &file_proto_tenant_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTenantRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTenantRequest) ProtoMessage() {}
+
+func (x *GetTenantRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tenant_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTenantRequest.ProtoReflect.Descriptor instead.
+func (*GetTenantRequest) Descriptor() ([]byte, []int) {
+	return file_proto_tenant_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetTenantRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// ข้อมูลสำหรับคำขอรายการ tenant
+type ListTenantsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTenantsRequest) Reset() {
+	*x = ListTenantsRequest{}
+	mi := &file_proto_tenant_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTenantsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTenantsRequest) ProtoMessage() {}
+
+func (x *ListTenantsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tenant_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTenantsRequest.ProtoReflect.Descriptor instead.
+func (*ListTenantsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_tenant_proto_rawDescGZIP(), []int{5}
+}
+
+// รายการ tenant
+type ListTenantsReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tenants       []*Tenant              `protobuf:"bytes,1,rep,name=tenants,proto3" json:"tenants,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTenantsReply) Reset() {
+	*x = ListTenantsReply{}
+	mi := &file_proto_tenant_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTenantsReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTenantsReply) ProtoMessage() {}
+
+func (x *ListTenantsReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tenant_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTenantsReply.ProtoReflect.Descriptor instead.
+func (*ListTenantsReply) Descriptor() ([]byte, []int) {
+	return file_proto_tenant_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListTenantsReply) GetTenants() []*Tenant {
+	if x != nil {
+		return x.Tenants
+	}
+	return nil
+}
+
+// ข้อมูลสำหรับคำขอแก้ไข tenant
+type UpdateTenantRequest struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	Id                    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"` // ว่าง = tenant ของผู้เรียก
+	Name                  string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	PasswordPolicy        *PasswordPolicy        `protobuf:"bytes,3,opt,name=passwordPolicy,proto3" json:"passwordPolicy,omitempty"`
+	AccessTokenTtlSeconds int64                  `protobuf:"varint,4,opt,name=accessTokenTtlSeconds,proto3" json:"accessTokenTtlSeconds,omitempty"`
+	SessionTtlSeconds     int64                  `protobuf:"varint,5,opt,name=sessionTtlSeconds,proto3" json:"sessionTtlSeconds,omitempty"`
+	// field ที่ต้องการแก้ไข: "name", "passwordPolicy", "accessTokenTtlSeconds", "sessionTtlSeconds"
+	UpdateMask    *fieldmaskpb.FieldMask `protobuf:"bytes,6,opt,name=updateMask,proto3" json:"updateMask,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateTenantRequest) Reset() {
+	*x = UpdateTenantRequest{}
+	mi := &file_proto_tenant_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateTenantRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateTenantRequest) ProtoMessage() {}
+
+func (x *UpdateTenantRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tenant_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateTenantRequest.ProtoReflect.Descriptor instead.
+func (*UpdateTenantRequest) Descriptor() ([]byte, []int) {
+	return file_proto_tenant_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *UpdateTenantRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateTenantRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UpdateTenantRequest) GetPasswordPolicy() *PasswordPolicy {
+	if x != nil {
+		return x.PasswordPolicy
+	}
+	return nil
+}
+
+func (x *UpdateTenantRequest) GetAccessTokenTtlSeconds() int64 {
+	if x != nil {
+		return x.AccessTokenTtlSeconds
+	}
+	return 0
+}
+
+func (x *UpdateTenantRequest) GetSessionTtlSeconds() int64 {
+	if x != nil {
+		return x.SessionTtlSeconds
+	}
+	return 0
+}
+
+func (x *UpdateTenantRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+// ข้อมูลสำหรับคำขอระงับ tenant
+type SuspendTenantRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"` // เหตุผล (บันทึกใน audit log)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SuspendTenantRequest) Reset() {
+	*x = SuspendTenantRequest{}
+	mi := &file_proto_tenant_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SuspendTenantRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SuspendTenantRequest) ProtoMessage() {}
+
+func (x *SuspendTenantRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tenant_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SuspendTenantRequest.ProtoReflect.Descriptor instead.
+func (*SuspendTenantRequest) Descriptor() ([]byte, []int) {
+	return file_proto_tenant_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SuspendTenantRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *SuspendTenantRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+// ข้อมูลสำหรับคำขอเปิดใช้งาน tenant
+type ActivateTenantRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ActivateTenantRequest) Reset() {
+	*x = ActivateTenantRequest{}
+	mi := &file_proto_tenant_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ActivateTenantRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ActivateTenantRequest) ProtoMessage() {}
+
+func (x *ActivateTenantRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tenant_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ActivateTenantRequest.ProtoReflect.Descriptor instead.
+func (*ActivateTenantRequest) Descriptor() ([]byte, []int) {
+	return file_proto_tenant_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ActivateTenantRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// ข้อมูลสำหรับคำขอสร้าง signing key ใหม่
+type RotateSigningKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"` // ว่าง = tenant ของผู้เรียก
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RotateSigningKeyRequest) Reset() {
+	*x = RotateSigningKeyRequest{}
+	mi := &file_proto_tenant_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RotateSigningKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotateSigningKeyRequest) ProtoMessage() {}
+
+func (x *RotateSigningKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tenant_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotateSigningKeyRequest.ProtoReflect.Descriptor instead.
+func (*RotateSigningKeyRequest) Descriptor() ([]byte, []int) {
+	return file_proto_tenant_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *RotateSigningKeyRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+var File_proto_tenant_proto protoreflect.FileDescriptor
+
+const file_proto_tenant_proto_rawDesc = "" +
+	"\n" +
+	"\x12proto/tenant.proto\x1a google/protobuf/field_mask.proto\"\x84\x02\n" +
+	"\x0ePasswordPolicy\x12\x1c\n" +
+	"\tminLength\x18\x01 \x01(\x05R\tminLength\x12\"\n" +
+	"\frequireUpper\x18\x02 \x01(\bR\frequireUpper\x12\"\n" +
+	"\frequireLower\x18\x03 \x01(\bR\frequireLower\x12$\n" +
+	"\rrequireNumber\x18\x04 \x01(\bR\rrequireNumber\x12$\n" +
+	"\rrequireSymbol\x18\x05 \x01(\bR\rrequireSymbol\x12 \n" +
+	"\vhistorySize\x18\x06 \x01(\x05R\vhistorySize\x12\x1e\n" +
+	"\n" +
+	"maxAgeDays\x18\a \x01(\x05R\n" +
+	"maxAgeDays\"V\n" +
+	"\x0eSigningKeyInfo\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1c\n" +
+	"\tcreatedAt\x18\x02 \x01(\tR\tcreatedAt\x12\x16\n" +
+	"\x06active\x18\x03 \x01(\bR\x06active\"\xd0\x02\n" +
+	"\x06Tenant\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x127\n" +
+	"\x0epasswordPolicy\x18\x04 \x01(\v2\x0f.PasswordPolicyR\x0epasswordPolicy\x124\n" +
+	"\x15accessTokenTtlSeconds\x18\x05 \x01(\x03R\x15accessTokenTtlSeconds\x12,\n" +
+	"\x11sessionTtlSeconds\x18\x06 \x01(\x03R\x11sessionTtlSeconds\x121\n" +
+	"\vsigningKeys\x18\a \x03(\v2\x0f.SigningKeyInfoR\vsigningKeys\x12\x1c\n" +
+	"\tcreatedAt\x18\b \x01(\tR\tcreatedAt\x12\x1c\n" +
+	"\tupdatedAt\x18\t \x01(\tR\tupdatedAt\"\xd6\x01\n" +
+	"\x13CreateTenantRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x127\n" +
+	"\x0epasswordPolicy\x18\x03 \x01(\v2\x0f.PasswordPolicyR\x0epasswordPolicy\x124\n" +
+	"\x15accessTokenTtlSeconds\x18\x04 \x01(\x03R\x15accessTokenTtlSeconds\x12,\n" +
+	"\x11sessionTtlSeconds\x18\x05 \x01(\x03R\x11sessionTtlSeconds\"\"\n" +
+	"\x10GetTenantRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x14\n" +
+	"\x12ListTenantsRequest\"5\n" +
+	"\x10ListTenantsReply\x12!\n" +
+	"\atenants\x18\x01 \x03(\v2\a.TenantR\atenants\"\x92\x02\n" +
+	"\x13UpdateTenantRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x127\n" +
+	"\x0epasswordPolicy\x18\x03 \x01(\v2\x0f.PasswordPolicyR\x0epasswordPolicy\x124\n" +
+	"\x15accessTokenTtlSeconds\x18\x04 \x01(\x03R\x15accessTokenTtlSeconds\x12,\n" +
+	"\x11sessionTtlSeconds\x18\x05 \x01(\x03R\x11sessionTtlSeconds\x12:\n" +
+	"\n" +
+	"updateMask\x18\x06 \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
+	"updateMask\">\n" +
+	"\x14SuspendTenantRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"'\n" +
+	"\x15ActivateTenantRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\")\n" +
+	"\x17RotateSigningKeyRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id2\xf6\x02\n" +
+	"\rTenantService\x12/\n" +
+	"\fCreateTenant\x12\x14.CreateTenantRequest\x1a\a.Tenant\"\x00\x12)\n" +
+	"\tGetTenant\x12\x11.GetTenantRequest\x1a\a.Tenant\"\x00\x127\n" +
+	"\vListTenants\x12\x13.ListTenantsRequest\x1a\x11.ListTenantsReply\"\x00\x12/\n" +
+	"\fUpdateTenant\x12\x14.UpdateTenantRequest\x1a\a.Tenant\"\x00\x121\n" +
+	"\rSuspendTenant\x12\x15.SuspendTenantRequest\x1a\a.Tenant\"\x00\x123\n" +
+	"\x0eActivateTenant\x12\x16.ActivateTenantRequest\x1a\a.Tenant\"\x00\x127\n" +
+	"\x10RotateSigningKey\x12\x18.RotateSigningKeyRequest\x1a\a.Tenant\"\x00B\x19Z\x17auth-microservice/protob\x06proto3"
+
+var (
+	file_proto_tenant_proto_rawDescOnce sync.Once
+	file_proto_tenant_proto_rawDescData []byte
+)
+
+func file_proto_tenant_proto_rawDescGZIP() []byte {
+	file_proto_tenant_proto_rawDescOnce.Do(func() {
+		file_proto_tenant_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_tenant_proto_rawDesc), len(file_proto_tenant_proto_rawDesc)))
+	})
+	return file_proto_tenant_proto_rawDescData
+}
+
+var file_proto_tenant_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_proto_tenant_proto_goTypes = []any{
+	(*PasswordPolicy)(nil),          // 0: PasswordPolicy
+	(*SigningKeyInfo)(nil),          // 1: SigningKeyInfo
+	(*Tenant)(nil),                  // 2: Tenant
+	(*CreateTenantRequest)(nil),     // 3: CreateTenantRequest
+	(*GetTenantRequest)(nil),        // 4: GetTenantRequest
+	(*ListTenantsRequest)(nil),      // 5: ListTenantsRequest
+	(*ListTenantsReply)(nil),        // 6: ListTenantsReply
+	(*UpdateTenantRequest)(nil),     // 7: UpdateTenantRequest
+	(*SuspendTenantRequest)(nil),    // 8: SuspendTenantRequest
+	(*ActivateTenantRequest)(nil),   // 9: ActivateTenantRequest
+	(*RotateSigningKeyRequest)(nil), // 10: RotateSigningKeyRequest
+	(*fieldmaskpb.FieldMask)(nil),   // 11: google.protobuf.FieldMask
+}
+var file_proto_tenant_proto_depIdxs = []int32{
+	0,  // 0: Tenant.passwordPolicy:type_name -> PasswordPolicy
+	1,  // 1: Tenant.signingKeys:type_name -> SigningKeyInfo
+	0,  // 2: CreateTenantRequest.passwordPolicy:type_name -> PasswordPolicy
+	2,  // 3: ListTenantsReply.tenants:type_name -> Tenant
+	0,  // 4: UpdateTenantRequest.passwordPolicy:type_name -> PasswordPolicy
+	11, // 5: UpdateTenantRequest.updateMask:type_name -> google.protobuf.FieldMask
+	3,  // 6: TenantService.CreateTenant:input_type -> CreateTenantRequest
+	4,  // 7: TenantService.GetTenant:input_type -> GetTenantRequest
+	5,  // 8: TenantService.ListTenants:input_type -> ListTenantsRequest
+	7,  // 9: TenantService.UpdateTenant:input_type -> UpdateTenantRequest
+	8,  // 10: TenantService.SuspendTenant:input_type -> SuspendTenantRequest
+	9,  // 11: TenantService.ActivateTenant:input_type -> ActivateTenantRequest
+	10, // 12: TenantService.RotateSigningKey:input_type -> RotateSigningKeyRequest
+	2,  // 13: TenantService.CreateTenant:output_type -> Tenant
+	2,  // 14: TenantService.GetTenant:output_type -> Tenant
+	6,  // 15: TenantService.ListTenants:output_type -> ListTenantsReply
+	2,  // 16: TenantService.UpdateTenant:output_type -> Tenant
+	2,  // 17: TenantService.SuspendTenant:output_type -> Tenant
+	2,  // 18: TenantService.ActivateTenant:output_type -> Tenant
+	2,  // 19: TenantService.RotateSigningKey:output_type -> Tenant
+	13, // [13:20] is the sub-list for method output_type
+	6,  // [6:13] is the sub-list for method input_type
+	6,  // [6:6] is the sub-list for extension type_name
+	6,  // [6:6] is the sub-list for extension extendee
+	0,  // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_proto_tenant_proto_init() }
+func file_proto_tenant_proto_init() {
+	if File_proto_tenant_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_tenant_proto_rawDesc), len(file_proto_tenant_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   11,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_tenant_proto_goTypes,
+		DependencyIndexes: file_proto_tenant_proto_depIdxs,
+		MessageInfos:      file_proto_tenant_proto_msgTypes,
+	}.Build()
+	File_proto_tenant_proto = out.File
+	file_proto_tenant_proto_goTypes = nil
+	file_proto_tenant_proto_depIdxs = nil
+}
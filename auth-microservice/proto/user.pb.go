@@ -11,6 +11,8 @@ package proto
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
+	wrapperspb "google.golang.org/protobuf/types/known/wrapperspb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -71,11 +73,20 @@ func (x *UserIdRequest) GetId() string {
 // ข้อมูลตอบกลับเมื่อดึงผู้ใช้ตาม ID สำเร็จ
 type UserIdReply struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`               // ID ของผู้ใช้
-	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`         // อีเมลของผู้ใช้
-	Username      string                 `protobuf:"bytes,3,opt,name=username,proto3" json:"username,omitempty"`   // ชื่อผู้ใช้
-	CreatedAt     string                 `protobuf:"bytes,4,opt,name=createdAt,proto3" json:"createdAt,omitempty"` // วันที่สร้างบัญชี
-	UpdatedAt     string                 `protobuf:"bytes,5,opt,name=updatedAt,proto3" json:"updatedAt,omitempty"` // วันที่อัปเดตข้อมูลล่าสุด
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`                                                                                        // ID ของผู้ใช้
+	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`                                                                                  // อีเมลของผู้ใช้
+	Username      string                 `protobuf:"bytes,3,opt,name=username,proto3" json:"username,omitempty"`                                                                            // ชื่อผู้ใช้
+	CreatedAt     string                 `protobuf:"bytes,4,opt,name=createdAt,proto3" json:"createdAt,omitempty"`                                                                          // วันที่สร้างบัญชี
+	UpdatedAt     string                 `protobuf:"bytes,5,opt,name=updatedAt,proto3" json:"updatedAt,omitempty"`                                                                          // วันที่อัปเดตข้อมูลล่าสุด
+	Role          string                 `protobuf:"bytes,6,opt,name=role,proto3" json:"role,omitempty"`                                                                                    // บทบาทของผู้ใช้ admin, user
+	DisplayName   string                 `protobuf:"bytes,7,opt,name=displayName,proto3" json:"displayName,omitempty"`                                                                      // ชื่อที่แสดง
+	AvatarUrl     string                 `protobuf:"bytes,8,opt,name=avatarUrl,proto3" json:"avatarUrl,omitempty"`                                                                          // URL รูปโปรไฟล์
+	Locale        string                 `protobuf:"bytes,9,opt,name=locale,proto3" json:"locale,omitempty"`                                                                                // ภาษา/ภูมิภาค เช่น "th-TH"
+	Timezone      string                 `protobuf:"bytes,10,opt,name=timezone,proto3" json:"timezone,omitempty"`                                                                           // เขตเวลา เช่น "Asia/Bangkok"
+	Phone         string                 `protobuf:"bytes,11,opt,name=phone,proto3" json:"phone,omitempty"`                                                                                 // เบอร์โทรศัพท์ (รูปแบบ E.164)
+	Metadata      map[string]string      `protobuf:"bytes,12,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // ข้อมูลเพิ่มเติมตาม schema ที่ admin กำหนด
+	EmailVerified bool                   `protobuf:"varint,13,opt,name=emailVerified,proto3" json:"emailVerified,omitempty"`                                                                // ยืนยันความเป็นเจ้าของอีเมลแล้วหรือไม่
+	TenantId      string                 `protobuf:"bytes,14,opt,name=tenantId,proto3" json:"tenantId,omitempty"`                                                                           // tenant ที่ผู้ใช้สังกัด
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -145,11 +156,83 @@ func (x *UserIdReply) GetUpdatedAt() string {
 	return ""
 }
 
+func (x *UserIdReply) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *UserIdReply) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
+
+func (x *UserIdReply) GetAvatarUrl() string {
+	if x != nil {
+		return x.AvatarUrl
+	}
+	return ""
+}
+
+func (x *UserIdReply) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+func (x *UserIdReply) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+func (x *UserIdReply) GetPhone() string {
+	if x != nil {
+		return x.Phone
+	}
+	return ""
+}
+
+func (x *UserIdReply) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *UserIdReply) GetEmailVerified() bool {
+	if x != nil {
+		return x.EmailVerified
+	}
+	return false
+}
+
+func (x *UserIdReply) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
 // ข้อมูลสำหรับคำขออัปเดตผู้ใช้
 type UpdateUserRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`             // ID ของผู้ใช้ที่ต้องการอัปเดต
-	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"` // ชื่อผู้ใช้ใหม่
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`                                                                                       // ID ของผู้ใช้ที่ต้องการอัปเดต
+	Username    string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`                                                                           // ชื่อผู้ใช้ใหม่
+	DisplayName string                 `protobuf:"bytes,3,opt,name=displayName,proto3" json:"displayName,omitempty"`                                                                     // ชื่อที่แสดง
+	AvatarUrl   string                 `protobuf:"bytes,4,opt,name=avatarUrl,proto3" json:"avatarUrl,omitempty"`                                                                         // URL รูปโปรไฟล์
+	Locale      string                 `protobuf:"bytes,5,opt,name=locale,proto3" json:"locale,omitempty"`                                                                               // ภาษา/ภูมิภาค
+	Timezone    string                 `protobuf:"bytes,6,opt,name=timezone,proto3" json:"timezone,omitempty"`                                                                           // เขตเวลา
+	Phone       string                 `protobuf:"bytes,7,opt,name=phone,proto3" json:"phone,omitempty"`                                                                                 // เบอร์โทรศัพท์
+	Metadata    map[string]string      `protobuf:"bytes,8,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // ข้อมูลเพิ่มเติม
+	// field ที่ต้องการอัปเดต เช่น "displayName", "metadata" หรือ "metadata.<key>"
+	// ถ้าไม่ระบุ จะอัปเดตเฉพาะ field ที่ส่งค่ามา (ไม่ว่าง)
+	UpdateMask    *fieldmaskpb.FieldMask `protobuf:"bytes,9,opt,name=updateMask,proto3" json:"updateMask,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -198,6 +281,55 @@ func (x *UpdateUserRequest) GetUsername() string {
 	return ""
 }
 
+func (x *UpdateUserRequest) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
+
+func (x *UpdateUserRequest) GetAvatarUrl() string {
+	if x != nil {
+		return x.AvatarUrl
+	}
+	return ""
+}
+
+func (x *UpdateUserRequest) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+func (x *UpdateUserRequest) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+func (x *UpdateUserRequest) GetPhone() string {
+	if x != nil {
+		return x.Phone
+	}
+	return ""
+}
+
+func (x *UpdateUserRequest) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *UpdateUserRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
 // ข้อมูลตอบกลับเมื่ออัปเดตผู้ใช้สำเร็จ
 type UpdateUserReply struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -333,33 +465,28 @@ func (x *DeleteUserReply) GetMessage() string {
 	return ""
 }
 
-// ข้อมูลสำหรับคำขอรายการผู้ใช้ (พร้อมตัวกรองและ pagination)
-type ListUsersRequest struct {
+// ข้อมูลสำหรับคำขอกู้คืนผู้ใช้
+type RestoreUserRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`    // ชื่อผู้ใช้ (username) สำหรับกรอง (ค้นหาแบบใกล้เคียง)
-	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`  // อีเมลสำหรับกรอง (ค้นหาแบบใกล้เคียง)
-	Page          int32                  `protobuf:"varint,3,opt,name=page,proto3" json:"page,omitempty"`   // หมายเลขหน้าที่ต้องการดู (เริ่มต้นที่ 1)
-	Limit         int32                  `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"` // จำนวนรายการต่อหน้า
-	Role          string                 `protobuf:"bytes,5,opt,name=role,proto3" json:"role,omitempty"`    // กรองตามบทบาทผู้ใช้ เช่น "admin" หรือ "user"
-	Token         string                 `protobuf:"bytes,6,opt,name=token,proto3" json:"token,omitempty"`  // JWT token สำหรับตรวจสอบสิทธิ์ (authorization)
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"` // ID ของผู้ใช้ที่ต้องการกู้คืน
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListUsersRequest) Reset() {
-	*x = ListUsersRequest{}
+func (x *RestoreUserRequest) Reset() {
+	*x = RestoreUserRequest{}
 	mi := &file_proto_user_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListUsersRequest) String() string {
+func (x *RestoreUserRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListUsersRequest) ProtoMessage() {}
+func (*RestoreUserRequest) ProtoMessage() {}
 
-func (x *ListUsersRequest) ProtoReflect() protoreflect.Message {
+func (x *RestoreUserRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_user_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -371,77 +498,86 @@ func (x *ListUsersRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListUsersRequest.ProtoReflect.Descriptor instead.
-func (*ListUsersRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use RestoreUserRequest.ProtoReflect.Descriptor instead.
+func (*RestoreUserRequest) Descriptor() ([]byte, []int) {
 	return file_proto_user_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *ListUsersRequest) GetName() string {
+func (x *RestoreUserRequest) GetId() string {
 	if x != nil {
-		return x.Name
+		return x.Id
 	}
 	return ""
 }
 
-func (x *ListUsersRequest) GetEmail() string {
-	if x != nil {
-		return x.Email
-	}
-	return ""
+// ข้อมูลตอบกลับเมื่อกู้คืนผู้ใช้สำเร็จ
+type RestoreUserReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"` // ข้อความสถานะ
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListUsersRequest) GetPage() int32 {
-	if x != nil {
-		return x.Page
-	}
-	return 0
+func (x *RestoreUserReply) Reset() {
+	*x = RestoreUserReply{}
+	mi := &file_proto_user_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListUsersRequest) GetLimit() int32 {
-	if x != nil {
-		return x.Limit
-	}
-	return 0
+func (x *RestoreUserReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *ListUsersRequest) GetRole() string {
+func (*RestoreUserReply) ProtoMessage() {}
+
+func (x *RestoreUserReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_proto_msgTypes[7]
 	if x != nil {
-		return x.Role
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *ListUsersRequest) GetToken() string {
+// Deprecated: Use RestoreUserReply.ProtoReflect.Descriptor instead.
+func (*RestoreUserReply) Descriptor() ([]byte, []int) {
+	return file_proto_user_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *RestoreUserReply) GetMessage() string {
 	if x != nil {
-		return x.Token
+		return x.Message
 	}
 	return ""
 }
 
-// ข้อมูลตอบกลับรายการผู้ใช้ พร้อมจำนวนรวมทั้งหมด
-type ListUsersReply struct {
+// ข้อมูลสำหรับคำขอลบผู้ใช้ถาวร
+type PurgeUserRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Users         []*UserItem            `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`  // รายการผู้ใช้ (array)
-	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"` // จำนวนผู้ใช้ทั้งหมดที่ตรงกับเงื่อนไข
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"` // ID ของผู้ใช้ที่ต้องการลบถาวร
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListUsersReply) Reset() {
-	*x = ListUsersReply{}
-	mi := &file_proto_user_proto_msgTypes[7]
+func (x *PurgeUserRequest) Reset() {
+	*x = PurgeUserRequest{}
+	mi := &file_proto_user_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListUsersReply) String() string {
+func (x *PurgeUserRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListUsersReply) ProtoMessage() {}
+func (*PurgeUserRequest) ProtoMessage() {}
 
-func (x *ListUsersReply) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_user_proto_msgTypes[7]
+func (x *PurgeUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -452,52 +588,99 @@ func (x *ListUsersReply) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListUsersReply.ProtoReflect.Descriptor instead.
-func (*ListUsersReply) Descriptor() ([]byte, []int) {
-	return file_proto_user_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use PurgeUserRequest.ProtoReflect.Descriptor instead.
+func (*PurgeUserRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *ListUsersReply) GetUsers() []*UserItem {
+func (x *PurgeUserRequest) GetId() string {
 	if x != nil {
-		return x.Users
+		return x.Id
 	}
-	return nil
+	return ""
 }
 
-func (x *ListUsersReply) GetTotal() int32 {
+// ข้อมูลตอบกลับเมื่อลบผู้ใช้ถาวรสำเร็จ
+type PurgeUserReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"` // ข้อความสถานะ
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PurgeUserReply) Reset() {
+	*x = PurgeUserReply{}
+	mi := &file_proto_user_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PurgeUserReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PurgeUserReply) ProtoMessage() {}
+
+func (x *PurgeUserReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_proto_msgTypes[9]
 	if x != nil {
-		return x.Total
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
 }
 
-// โครงสร้างข้อมูลผู้ใช้แต่ละรายการใน ListUsersReply
-type UserItem struct {
+// Deprecated: Use PurgeUserReply.ProtoReflect.Descriptor instead.
+func (*PurgeUserReply) Descriptor() ([]byte, []int) {
+	return file_proto_user_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *PurgeUserReply) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// ข้อมูลสำหรับคำขอรายการผู้ใช้ (พร้อมตัวกรองและ pagination)
+type ListUsersRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`               // ID ของผู้ใช้
-	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`         // อีเมลของผู้ใช้
-	Username      string                 `protobuf:"bytes,3,opt,name=username,proto3" json:"username,omitempty"`   // ชื่อผู้ใช้
-	CreatedAt     string                 `protobuf:"bytes,4,opt,name=createdAt,proto3" json:"createdAt,omitempty"` // วันที่สร้างบัญชี
-	Role          string                 `protobuf:"bytes,5,opt,name=role,proto3" json:"role,omitempty"`           // บทบาทของผู้ใช้ admin, user
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`                    // ชื่อผู้ใช้ (username) สำหรับกรอง (ไม่สนตัวพิมพ์เล็ก/ใหญ่ ตาม matchMode)
+	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`                  // อีเมลสำหรับกรอง (ไม่สนตัวพิมพ์เล็ก/ใหญ่ ตาม matchMode)
+	Page          int32                  `protobuf:"varint,3,opt,name=page,proto3" json:"page,omitempty"`                   // หมายเลขหน้าที่ต้องการดู (เริ่มต้นที่ 1) แบบเดิม แนะนำให้ใช้ pageToken แทน
+	Limit         int32                  `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`                 // จำนวนรายการต่อหน้า (สูงสุด 100)
+	Role          string                 `protobuf:"bytes,5,opt,name=role,proto3" json:"role,omitempty"`                    // กรองตามบทบาทผู้ใช้ เช่น "admin" หรือ "user"
+	Token         string                 `protobuf:"bytes,6,opt,name=token,proto3" json:"token,omitempty"`                  // JWT token สำหรับตรวจสอบสิทธิ์ (authorization)
+	PageToken     string                 `protobuf:"bytes,7,opt,name=pageToken,proto3" json:"pageToken,omitempty"`          // cursor จาก nextPageToken ของหน้าก่อนหน้า (ว่าง = หน้าแรก)
+	OrderBy       string                 `protobuf:"bytes,8,opt,name=orderBy,proto3" json:"orderBy,omitempty"`              // การเรียงลำดับ เช่น "createdAt desc", "username" (ค่าเริ่มต้น "createdAt")
+	IncludeTotal  bool                   `protobuf:"varint,9,opt,name=includeTotal,proto3" json:"includeTotal,omitempty"`   // ต้องการจำนวนทั้งหมดหรือไม่ (นับเพิ่ม ทำให้ช้าลง)
+	MatchMode     string                 `protobuf:"bytes,10,opt,name=matchMode,proto3" json:"matchMode,omitempty"`         // รูปแบบการค้นหา name/email: "prefix" (ค่าเริ่มต้น), "exact" หรือ "contains"
+	CreatedAfter  string                 `protobuf:"bytes,11,opt,name=createdAfter,proto3" json:"createdAfter,omitempty"`   // กรองผู้ใช้ที่สร้างตั้งแต่เวลานี้ (RFC3339)
+	CreatedBefore string                 `protobuf:"bytes,12,opt,name=createdBefore,proto3" json:"createdBefore,omitempty"` // กรองผู้ใช้ที่สร้างก่อนเวลานี้ (RFC3339)
+	Verified      *wrapperspb.BoolValue  `protobuf:"bytes,13,opt,name=verified,proto3" json:"verified,omitempty"`           // กรองตามสถานะยืนยันอีเมล (ไม่ระบุ = ทั้งหมด)
+	Deleted       string                 `protobuf:"bytes,14,opt,name=deleted,proto3" json:"deleted,omitempty"`             // "exclude" (ค่าเริ่มต้น), "include" หรือ "only"
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UserItem) Reset() {
-	*x = UserItem{}
-	mi := &file_proto_user_proto_msgTypes[8]
+func (x *ListUsersRequest) Reset() {
+	*x = ListUsersRequest{}
+	mi := &file_proto_user_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UserItem) String() string {
+func (x *ListUsersRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UserItem) ProtoMessage() {}
+func (*ListUsersRequest) ProtoMessage() {}
 
-func (x *UserItem) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_user_proto_msgTypes[8]
+func (x *ListUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -508,91 +691,1636 @@ func (x *UserItem) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UserItem.ProtoReflect.Descriptor instead.
-func (*UserItem) Descriptor() ([]byte, []int) {
-	return file_proto_user_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use ListUsersRequest.ProtoReflect.Descriptor instead.
+func (*ListUsersRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *UserItem) GetId() string {
+func (x *ListUsersRequest) GetName() string {
 	if x != nil {
-		return x.Id
+		return x.Name
 	}
 	return ""
 }
 
-func (x *UserItem) GetEmail() string {
+func (x *ListUsersRequest) GetEmail() string {
 	if x != nil {
 		return x.Email
 	}
 	return ""
 }
 
-func (x *UserItem) GetUsername() string {
+func (x *ListUsersRequest) GetPage() int32 {
 	if x != nil {
-		return x.Username
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListUsersRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListUsersRequest) GetRole() string {
+	if x != nil {
+		return x.Role
 	}
 	return ""
 }
 
-func (x *UserItem) GetCreatedAt() string {
+func (x *ListUsersRequest) GetToken() string {
 	if x != nil {
-		return x.CreatedAt
+		return x.Token
 	}
 	return ""
 }
 
-func (x *UserItem) GetRole() string {
+func (x *ListUsersRequest) GetPageToken() string {
 	if x != nil {
-		return x.Role
+		return x.PageToken
 	}
 	return ""
 }
 
-var File_proto_user_proto protoreflect.FileDescriptor
+func (x *ListUsersRequest) GetOrderBy() string {
+	if x != nil {
+		return x.OrderBy
+	}
+	return ""
+}
 
-const file_proto_user_proto_rawDesc = "" +
-	"\n" +
-	"\x10proto/user.proto\"\x1f\n" +
-	"\rUserIdRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"\x8b\x01\n" +
-	"\vUserIdReply\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
-	"\x05email\x18\x02 \x01(\tR\x05email\x12\x1a\n" +
-	"\busername\x18\x03 \x01(\tR\busername\x12\x1c\n" +
-	"\tcreatedAt\x18\x04 \x01(\tR\tcreatedAt\x12\x1c\n" +
-	"\tupdatedAt\x18\x05 \x01(\tR\tupdatedAt\"?\n" +
-	"\x11UpdateUserRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1a\n" +
-	"\busername\x18\x02 \x01(\tR\busername\"+\n" +
-	"\x0fUpdateUserReply\x12\x18\n" +
-	"\amessage\x18\x01 \x01(\tR\amessage\"#\n" +
-	"\x11DeleteUserRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"+\n" +
-	"\x0fDeleteUserReply\x12\x18\n" +
-	"\amessage\x18\x01 \x01(\tR\amessage\"\x90\x01\n" +
-	"\x10ListUsersRequest\x12\x12\n" +
-	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
-	"\x05email\x18\x02 \x01(\tR\x05email\x12\x12\n" +
+func (x *ListUsersRequest) GetIncludeTotal() bool {
+	if x != nil {
+		return x.IncludeTotal
+	}
+	return false
+}
+
+func (x *ListUsersRequest) GetMatchMode() string {
+	if x != nil {
+		return x.MatchMode
+	}
+	return ""
+}
+
+func (x *ListUsersRequest) GetCreatedAfter() string {
+	if x != nil {
+		return x.CreatedAfter
+	}
+	return ""
+}
+
+func (x *ListUsersRequest) GetCreatedBefore() string {
+	if x != nil {
+		return x.CreatedBefore
+	}
+	return ""
+}
+
+func (x *ListUsersRequest) GetVerified() *wrapperspb.BoolValue {
+	if x != nil {
+		return x.Verified
+	}
+	return nil
+}
+
+func (x *ListUsersRequest) GetDeleted() string {
+	if x != nil {
+		return x.Deleted
+	}
+	return ""
+}
+
+// ข้อมูลตอบกลับรายการผู้ใช้ พร้อมจำนวนรวมทั้งหมด
+type ListUsersReply struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Users           []*UserItem            `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`                      // รายการผู้ใช้ (array)
+	Total           int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`                     // จำนวนผู้ใช้ทั้งหมดที่ตรงกับเงื่อนไข (เมื่อ includeTotal หรือใช้ page)
+	NextPageToken   string                 `protobuf:"bytes,3,opt,name=nextPageToken,proto3" json:"nextPageToken,omitempty"`      // cursor สำหรับหน้าถัดไป (ว่าง = หน้าสุดท้าย)
+	TotalIsEstimate bool                   `protobuf:"varint,4,opt,name=totalIsEstimate,proto3" json:"totalIsEstimate,omitempty"` // true ถ้า total เป็นค่าประมาณ (มีผู้ใช้ที่ตรงเงื่อนไขอย่างน้อย total คน ส่ง includeTotal เพื่อได้ค่าที่แน่นอน)
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ListUsersReply) Reset() {
+	*x = ListUsersReply{}
+	mi := &file_proto_user_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUsersReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUsersReply) ProtoMessage() {}
+
+func (x *ListUsersReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUsersReply.ProtoReflect.Descriptor instead.
+func (*ListUsersReply) Descriptor() ([]byte, []int) {
+	return file_proto_user_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListUsersReply) GetUsers() []*UserItem {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+func (x *ListUsersReply) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *ListUsersReply) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *ListUsersReply) GetTotalIsEstimate() bool {
+	if x != nil {
+		return x.TotalIsEstimate
+	}
+	return false
+}
+
+// โครงสร้างข้อมูลผู้ใช้แต่ละรายการใน ListUsersReply
+type UserItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`                                                                                        // ID ของผู้ใช้
+	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`                                                                                  // อีเมลของผู้ใช้
+	Username      string                 `protobuf:"bytes,3,opt,name=username,proto3" json:"username,omitempty"`                                                                            // ชื่อผู้ใช้
+	CreatedAt     string                 `protobuf:"bytes,4,opt,name=createdAt,proto3" json:"createdAt,omitempty"`                                                                          // วันที่สร้างบัญชี
+	Role          string                 `protobuf:"bytes,5,opt,name=role,proto3" json:"role,omitempty"`                                                                                    // บทบาทของผู้ใช้ admin, user
+	UpdatedAt     string                 `protobuf:"bytes,6,opt,name=updatedAt,proto3" json:"updatedAt,omitempty"`                                                                          // วันที่อัปเดตข้อมูลล่าสุด
+	DisplayName   string                 `protobuf:"bytes,7,opt,name=displayName,proto3" json:"displayName,omitempty"`                                                                      // ชื่อที่แสดง
+	AvatarUrl     string                 `protobuf:"bytes,8,opt,name=avatarUrl,proto3" json:"avatarUrl,omitempty"`                                                                          // URL รูปโปรไฟล์
+	Locale        string                 `protobuf:"bytes,9,opt,name=locale,proto3" json:"locale,omitempty"`                                                                                // ภาษา/ภูมิภาค
+	Timezone      string                 `protobuf:"bytes,10,opt,name=timezone,proto3" json:"timezone,omitempty"`                                                                           // เขตเวลา
+	Phone         string                 `protobuf:"bytes,11,opt,name=phone,proto3" json:"phone,omitempty"`                                                                                 // เบอร์โทรศัพท์
+	Metadata      map[string]string      `protobuf:"bytes,12,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // ข้อมูลเพิ่มเติม
+	EmailVerified bool                   `protobuf:"varint,13,opt,name=emailVerified,proto3" json:"emailVerified,omitempty"`                                                                // ยืนยันความเป็นเจ้าของอีเมลแล้วหรือไม่
+	TenantId      string                 `protobuf:"bytes,14,opt,name=tenantId,proto3" json:"tenantId,omitempty"`                                                                           // tenant ที่ผู้ใช้สังกัด
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserItem) Reset() {
+	*x = UserItem{}
+	mi := &file_proto_user_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserItem) ProtoMessage() {}
+
+func (x *UserItem) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserItem.ProtoReflect.Descriptor instead.
+func (*UserItem) Descriptor() ([]byte, []int) {
+	return file_proto_user_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *UserItem) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UserItem) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *UserItem) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *UserItem) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *UserItem) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *UserItem) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+func (x *UserItem) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
+
+func (x *UserItem) GetAvatarUrl() string {
+	if x != nil {
+		return x.AvatarUrl
+	}
+	return ""
+}
+
+func (x *UserItem) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+func (x *UserItem) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+func (x *UserItem) GetPhone() string {
+	if x != nil {
+		return x.Phone
+	}
+	return ""
+}
+
+func (x *UserItem) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *UserItem) GetEmailVerified() bool {
+	if x != nil {
+		return x.EmailVerified
+	}
+	return false
+}
+
+func (x *UserItem) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+// ข้อมูลสำหรับคำขอดึง schema ของ metadata
+type GetProfileSchemaRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProfileSchemaRequest) Reset() {
+	*x = GetProfileSchemaRequest{}
+	mi := &file_proto_user_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProfileSchemaRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProfileSchemaRequest) ProtoMessage() {}
+
+func (x *GetProfileSchemaRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProfileSchemaRequest.ProtoReflect.Descriptor instead.
+func (*GetProfileSchemaRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_proto_rawDescGZIP(), []int{13}
+}
+
+// schema ของ metadata ในโปรไฟล์ผู้ใช้
+type ProfileSchema struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Attributes    []*ProfileAttribute    `protobuf:"bytes,1,rep,name=attributes,proto3" json:"attributes,omitempty"` // รายการ key ที่อนุญาต
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProfileSchema) Reset() {
+	*x = ProfileSchema{}
+	mi := &file_proto_user_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProfileSchema) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProfileSchema) ProtoMessage() {}
+
+func (x *ProfileSchema) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProfileSchema.ProtoReflect.Descriptor instead.
+func (*ProfileSchema) Descriptor() ([]byte, []int) {
+	return file_proto_user_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ProfileSchema) GetAttributes() []*ProfileAttribute {
+	if x != nil {
+		return x.Attributes
+	}
+	return nil
+}
+
+// นิยามของ key หนึ่งตัวใน metadata
+type ProfileAttribute struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`                     // ชื่อ key (a-z, 0-9, _)
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`                   // ชนิดข้อมูล: "string", "number" หรือ "boolean"
+	Required      bool                   `protobuf:"varint,3,opt,name=required,proto3" json:"required,omitempty"`          // ต้องมีค่าหรือไม่
+	MaxLength     int32                  `protobuf:"varint,4,opt,name=maxLength,proto3" json:"maxLength,omitempty"`        // ความยาวสูงสุด (0 = ไม่จำกัด)
+	AllowedValues []string               `protobuf:"bytes,5,rep,name=allowedValues,proto3" json:"allowedValues,omitempty"` // ค่าที่อนุญาต (ว่าง = ไม่จำกัด)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProfileAttribute) Reset() {
+	*x = ProfileAttribute{}
+	mi := &file_proto_user_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProfileAttribute) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProfileAttribute) ProtoMessage() {}
+
+func (x *ProfileAttribute) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProfileAttribute.ProtoReflect.Descriptor instead.
+func (*ProfileAttribute) Descriptor() ([]byte, []int) {
+	return file_proto_user_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ProfileAttribute) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *ProfileAttribute) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *ProfileAttribute) GetRequired() bool {
+	if x != nil {
+		return x.Required
+	}
+	return false
+}
+
+func (x *ProfileAttribute) GetMaxLength() int32 {
+	if x != nil {
+		return x.MaxLength
+	}
+	return 0
+}
+
+func (x *ProfileAttribute) GetAllowedValues() []string {
+	if x != nil {
+		return x.AllowedValues
+	}
+	return nil
+}
+
+// ข้อมูลสำหรับคำขอส่งออกข้อมูลของตัวเอง (ใช้ token ใน metadata "authorization")
+type ExportMyDataRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportMyDataRequest) Reset() {
+	*x = ExportMyDataRequest{}
+	mi := &file_proto_user_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportMyDataRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportMyDataRequest) ProtoMessage() {}
+
+func (x *ExportMyDataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportMyDataRequest.ProtoReflect.Descriptor instead.
+func (*ExportMyDataRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_proto_rawDescGZIP(), []int{16}
+}
+
+// ข้อมูลสำหรับคำขอส่งออกข้อมูลของผู้ใช้ตาม ID
+type ExportUserDataRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"` // ID ของผู้ใช้
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportUserDataRequest) Reset() {
+	*x = ExportUserDataRequest{}
+	mi := &file_proto_user_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportUserDataRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportUserDataRequest) ProtoMessage() {}
+
+func (x *ExportUserDataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportUserDataRequest.ProtoReflect.Descriptor instead.
+func (*ExportUserDataRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ExportUserDataRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// ข้อมูลไฟล์ส่งออกแต่ละส่วน (นำ data ของทุก chunk มาต่อกันจะได้ไฟล์ zip)
+type DataExportChunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Filename      string                 `protobuf:"bytes,1,opt,name=filename,proto3" json:"filename,omitempty"` // ชื่อไฟล์ (ส่งมาใน chunk แรก)
+	Data          []byte                 `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`         // ข้อมูลไฟล์บางส่วน
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DataExportChunk) Reset() {
+	*x = DataExportChunk{}
+	mi := &file_proto_user_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DataExportChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DataExportChunk) ProtoMessage() {}
+
+func (x *DataExportChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DataExportChunk.ProtoReflect.Descriptor instead.
+func (*DataExportChunk) Descriptor() ([]byte, []int) {
+	return file_proto_user_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *DataExportChunk) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *DataExportChunk) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+// ข้อมูลสำหรับคำขอติดตามการเปลี่ยนแปลงของผู้ใช้
+type WatchUsersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Types         []string               `protobuf:"bytes,1,rep,name=types,proto3" json:"types,omitempty"`             // ประเภทที่ต้องการ: "created", "updated", "deleted", "restored", "purged" (ว่าง = ทั้งหมด)
+	UserIds       []string               `protobuf:"bytes,2,rep,name=userIds,proto3" json:"userIds,omitempty"`         // เฉพาะผู้ใช้ตาม ID (ว่าง = ทุกคนใน tenant สูงสุด 100 รายการ)
+	ResumeToken   string                 `protobuf:"bytes,3,opt,name=resumeToken,proto3" json:"resumeToken,omitempty"` // resumeToken ของ event ล่าสุดที่ได้รับ เพื่อรับต่อโดยไม่พลาด (ว่าง = เฉพาะที่เกิดหลังจากนี้)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchUsersRequest) Reset() {
+	*x = WatchUsersRequest{}
+	mi := &file_proto_user_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchUsersRequest) ProtoMessage() {}
+
+func (x *WatchUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchUsersRequest.ProtoReflect.Descriptor instead.
+func (*WatchUsersRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *WatchUsersRequest) GetTypes() []string {
+	if x != nil {
+		return x.Types
+	}
+	return nil
+}
+
+func (x *WatchUsersRequest) GetUserIds() []string {
+	if x != nil {
+		return x.UserIds
+	}
+	return nil
+}
+
+func (x *WatchUsersRequest) GetResumeToken() string {
+	if x != nil {
+		return x.ResumeToken
+	}
+	return ""
+}
+
+// การเปลี่ยนแปลงของผู้ใช้หนึ่งรายการ
+type UserChangeEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ResumeToken   string                 `protobuf:"bytes,1,opt,name=resumeToken,proto3" json:"resumeToken,omitempty"`     // ส่งใน WatchUsersRequest เมื่อเชื่อมต่อใหม่
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`                   // "created", "updated", "deleted" (soft delete), "restored" หรือ "purged" (ลบถาวร)
+	UserId        string                 `protobuf:"bytes,3,opt,name=userId,proto3" json:"userId,omitempty"`               // ID ของผู้ใช้
+	TenantId      string                 `protobuf:"bytes,4,opt,name=tenantId,proto3" json:"tenantId,omitempty"`           // tenant ของผู้ใช้
+	OccurredAt    string                 `protobuf:"bytes,5,opt,name=occurredAt,proto3" json:"occurredAt,omitempty"`       // เวลาที่เปลี่ยน (RFC3339)
+	UpdatedFields []string               `protobuf:"bytes,6,rep,name=updatedFields,proto3" json:"updatedFields,omitempty"` // field ที่เปลี่ยน (เฉพาะ "updated")
+	User          *UserItem              `protobuf:"bytes,7,opt,name=user,proto3" json:"user,omitempty"`                   // ข้อมูลล่าสุดของผู้ใช้ ณ เวลาที่ส่ง (ไม่มีเมื่อ "purged")
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserChangeEvent) Reset() {
+	*x = UserChangeEvent{}
+	mi := &file_proto_user_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserChangeEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserChangeEvent) ProtoMessage() {}
+
+func (x *UserChangeEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserChangeEvent.ProtoReflect.Descriptor instead.
+func (*UserChangeEvent) Descriptor() ([]byte, []int) {
+	return file_proto_user_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *UserChangeEvent) GetResumeToken() string {
+	if x != nil {
+		return x.ResumeToken
+	}
+	return ""
+}
+
+func (x *UserChangeEvent) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *UserChangeEvent) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UserChangeEvent) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *UserChangeEvent) GetOccurredAt() string {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return ""
+}
+
+func (x *UserChangeEvent) GetUpdatedFields() []string {
+	if x != nil {
+		return x.UpdatedFields
+	}
+	return nil
+}
+
+func (x *UserChangeEvent) GetUser() *UserItem {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+// ข้อมูลไฟล์นำเข้าแต่ละส่วน (format, dryRun และ importId อ่านจาก message แรกเท่านั้น)
+// แถวมี field: email, username, password หรือ passwordHash (bcrypt/argon2), role, displayName,
+// avatarUrl, locale, timezone, phone, emailVerified และ metadata (CSV ใช้คอลัมน์ "metadata.<key>")
+type ImportUsersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Format        string                 `protobuf:"bytes,1,opt,name=format,proto3" json:"format,omitempty"`     // "csv" (บรรทัดแรกเป็นชื่อคอลัมน์) หรือ "jsonl" (หนึ่ง JSON object ต่อบรรทัด)
+	DryRun        bool                   `protobuf:"varint,2,opt,name=dryRun,proto3" json:"dryRun,omitempty"`    // ตรวจสอบอย่างเดียว ไม่บันทึกผู้ใช้
+	ImportId      string                 `protobuf:"bytes,3,opt,name=importId,proto3" json:"importId,omitempty"` // ID ของการนำเข้า ส่ง ID เดิมพร้อมไฟล์เดิมเพื่อทำต่อจากแถวที่บันทึกแล้ว (ว่าง = นำเข้าใหม่)
+	Data          []byte                 `protobuf:"bytes,4,opt,name=data,proto3" json:"data,omitempty"`         // ข้อมูลไฟล์บางส่วน (นำ data ของทุก message มาต่อกันจะได้ไฟล์)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportUsersRequest) Reset() {
+	*x = ImportUsersRequest{}
+	mi := &file_proto_user_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportUsersRequest) ProtoMessage() {}
+
+func (x *ImportUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportUsersRequest.ProtoReflect.Descriptor instead.
+func (*ImportUsersRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ImportUsersRequest) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *ImportUsersRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+func (x *ImportUsersRequest) GetImportId() string {
+	if x != nil {
+		return x.ImportId
+	}
+	return ""
+}
+
+func (x *ImportUsersRequest) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+// ผลการนำเข้า (รวมแถวที่ทำไปแล้วก่อนทำต่อด้วย importId เดิม)
+type ImportUsersReply struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ImportId        string                 `protobuf:"bytes,1,opt,name=importId,proto3" json:"importId,omitempty"`                // ID ของการนำเข้า
+	TotalRows       int32                  `protobuf:"varint,2,opt,name=totalRows,proto3" json:"totalRows,omitempty"`             // จำนวนแถวข้อมูลทั้งหมดในไฟล์
+	Imported        int32                  `protobuf:"varint,3,opt,name=imported,proto3" json:"imported,omitempty"`               // จำนวนผู้ใช้ที่นำเข้าสำเร็จ (dry run = ผ่านการตรวจสอบ)
+	Skipped         int32                  `protobuf:"varint,4,opt,name=skipped,proto3" json:"skipped,omitempty"`                 // แถวที่มีผู้ใช้อยู่แล้วเมื่อทำต่อ (อาจถูกนำเข้าไปแล้วก่อน stream ขาด)
+	Failed          int32                  `protobuf:"varint,5,opt,name=failed,proto3" json:"failed,omitempty"`                   // จำนวนแถวที่ไม่ผ่านการตรวจสอบหรือซ้ำ
+	Errors          []*ImportRowError      `protobuf:"bytes,6,rep,name=errors,proto3" json:"errors,omitempty"`                    // error ของแต่ละแถว (สูงสุด 1000 รายการ)
+	ErrorsTruncated bool                   `protobuf:"varint,7,opt,name=errorsTruncated,proto3" json:"errorsTruncated,omitempty"` // true ถ้ามี error มากกว่าที่ส่งกลับ
+	DryRun          bool                   `protobuf:"varint,8,opt,name=dryRun,proto3" json:"dryRun,omitempty"`                   // เป็นการตรวจสอบอย่างเดียวหรือไม่
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ImportUsersReply) Reset() {
+	*x = ImportUsersReply{}
+	mi := &file_proto_user_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportUsersReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportUsersReply) ProtoMessage() {}
+
+func (x *ImportUsersReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportUsersReply.ProtoReflect.Descriptor instead.
+func (*ImportUsersReply) Descriptor() ([]byte, []int) {
+	return file_proto_user_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ImportUsersReply) GetImportId() string {
+	if x != nil {
+		return x.ImportId
+	}
+	return ""
+}
+
+func (x *ImportUsersReply) GetTotalRows() int32 {
+	if x != nil {
+		return x.TotalRows
+	}
+	return 0
+}
+
+func (x *ImportUsersReply) GetImported() int32 {
+	if x != nil {
+		return x.Imported
+	}
+	return 0
+}
+
+func (x *ImportUsersReply) GetSkipped() int32 {
+	if x != nil {
+		return x.Skipped
+	}
+	return 0
+}
+
+func (x *ImportUsersReply) GetFailed() int32 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+func (x *ImportUsersReply) GetErrors() []*ImportRowError {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+func (x *ImportUsersReply) GetErrorsTruncated() bool {
+	if x != nil {
+		return x.ErrorsTruncated
+	}
+	return false
+}
+
+func (x *ImportUsersReply) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+// error ของแถวหนึ่งในไฟล์นำเข้า
+type ImportRowError struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Row           int32                  `protobuf:"varint,1,opt,name=row,proto3" json:"row,omitempty"`        // ลำดับแถวข้อมูล (เริ่มที่ 1 ไม่นับบรรทัดชื่อคอลัมน์ของ CSV)
+	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`     // อีเมลในแถวนั้น (ถ้ามี)
+	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"` // สาเหตุ
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportRowError) Reset() {
+	*x = ImportRowError{}
+	mi := &file_proto_user_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportRowError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportRowError) ProtoMessage() {}
+
+func (x *ImportRowError) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportRowError.ProtoReflect.Descriptor instead.
+func (*ImportRowError) Descriptor() ([]byte, []int) {
+	return file_proto_user_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ImportRowError) GetRow() int32 {
+	if x != nil {
+		return x.Row
+	}
+	return 0
+}
+
+func (x *ImportRowError) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *ImportRowError) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// ข้อมูลสำหรับคำขอส่งออกรายชื่อผู้ใช้
+type ExportUsersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Format        string                 `protobuf:"bytes,1,opt,name=format,proto3" json:"format,omitempty"`           // "csv" (ค่าเริ่มต้น) หรือ "jsonl"
+	Role          string                 `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"`               // กรองตามบทบาท (ว่าง = ทั้งหมด)
+	Deleted       string                 `protobuf:"bytes,3,opt,name=deleted,proto3" json:"deleted,omitempty"`         // "exclude" (ค่าเริ่มต้น), "include" หรือ "only"
+	ResumeToken   string                 `protobuf:"bytes,4,opt,name=resumeToken,proto3" json:"resumeToken,omitempty"` // resumeToken ของ chunk ล่าสุดที่ได้รับ เพื่อส่งออกต่อ (ว่าง = เริ่มใหม่)
+	DryRun        bool                   `protobuf:"varint,5,opt,name=dryRun,proto3" json:"dryRun,omitempty"`          // นับจำนวนผู้ใช้ที่จะส่งออกอย่างเดียว (ส่ง chunk เดียวที่มี total)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportUsersRequest) Reset() {
+	*x = ExportUsersRequest{}
+	mi := &file_proto_user_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportUsersRequest) ProtoMessage() {}
+
+func (x *ExportUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportUsersRequest.ProtoReflect.Descriptor instead.
+func (*ExportUsersRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ExportUsersRequest) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *ExportUsersRequest) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *ExportUsersRequest) GetDeleted() string {
+	if x != nil {
+		return x.Deleted
+	}
+	return ""
+}
+
+func (x *ExportUsersRequest) GetResumeToken() string {
+	if x != nil {
+		return x.ResumeToken
+	}
+	return ""
+}
+
+func (x *ExportUsersRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+// ข้อมูลส่งออกแต่ละส่วน (นำ data ของทุก chunk มาต่อกันจะได้ไฟล์)
+type ExportUsersChunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`               // แถวข้อมูล (chunk แรกของ CSV มีบรรทัดชื่อคอลัมน์)
+	Rows          int32                  `protobuf:"varint,2,opt,name=rows,proto3" json:"rows,omitempty"`              // จำนวนแถวใน chunk นี้
+	ResumeToken   string                 `protobuf:"bytes,3,opt,name=resumeToken,proto3" json:"resumeToken,omitempty"` // ส่งใน ExportUsersRequest เพื่อส่งออกต่อหลัง chunk นี้ (ว่าง = chunk สุดท้าย)
+	Total         int32                  `protobuf:"varint,4,opt,name=total,proto3" json:"total,omitempty"`            // จำนวนผู้ใช้ที่ตรงกับเงื่อนไข (เฉพาะ dry run)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportUsersChunk) Reset() {
+	*x = ExportUsersChunk{}
+	mi := &file_proto_user_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportUsersChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportUsersChunk) ProtoMessage() {}
+
+func (x *ExportUsersChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportUsersChunk.ProtoReflect.Descriptor instead.
+func (*ExportUsersChunk) Descriptor() ([]byte, []int) {
+	return file_proto_user_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ExportUsersChunk) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *ExportUsersChunk) GetRows() int32 {
+	if x != nil {
+		return x.Rows
+	}
+	return 0
+}
+
+func (x *ExportUsersChunk) GetResumeToken() string {
+	if x != nil {
+		return x.ResumeToken
+	}
+	return ""
+}
+
+func (x *ExportUsersChunk) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+// ข้อมูลสำหรับคำขอกำหนด role ของผู้ใช้
+type SetUserRoleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`     // ID ของผู้ใช้
+	Role          string                 `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"` // "user", "tenant_admin" หรือ "admin" (admin ได้เฉพาะ tenant default โดย admin ของระบบ)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetUserRoleRequest) Reset() {
+	*x = SetUserRoleRequest{}
+	mi := &file_proto_user_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetUserRoleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetUserRoleRequest) ProtoMessage() {}
+
+func (x *SetUserRoleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetUserRoleRequest.ProtoReflect.Descriptor instead.
+func (*SetUserRoleRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *SetUserRoleRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *SetUserRoleRequest) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+type SetUserRoleReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"` // ข้อความสถานะ
+	User          *UserItem              `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`       // ข้อมูลผู้ใช้หลังเปลี่ยน role
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetUserRoleReply) Reset() {
+	*x = SetUserRoleReply{}
+	mi := &file_proto_user_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetUserRoleReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetUserRoleReply) ProtoMessage() {}
+
+func (x *SetUserRoleReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetUserRoleReply.ProtoReflect.Descriptor instead.
+func (*SetUserRoleReply) Descriptor() ([]byte, []int) {
+	return file_proto_user_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *SetUserRoleReply) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *SetUserRoleReply) GetUser() *UserItem {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+// ข้อมูลสำหรับคำขอยกเลิก session ของผู้ใช้
+type RevokeUserSessionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"` // ID ของผู้ใช้
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeUserSessionsRequest) Reset() {
+	*x = RevokeUserSessionsRequest{}
+	mi := &file_proto_user_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeUserSessionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeUserSessionsRequest) ProtoMessage() {}
+
+func (x *RevokeUserSessionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeUserSessionsRequest.ProtoReflect.Descriptor instead.
+func (*RevokeUserSessionsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *RevokeUserSessionsRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type RevokeUserSessionsReply struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Message         string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`                  // ข้อความสถานะ
+	RevokedConsents int32                  `protobuf:"varint,2,opt,name=revokedConsents,proto3" json:"revokedConsents,omitempty"` // จำนวนความยินยอมของ OAuth client ที่ถูกยกเลิก (refresh token ใช้ไม่ได้อีก)
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *RevokeUserSessionsReply) Reset() {
+	*x = RevokeUserSessionsReply{}
+	mi := &file_proto_user_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeUserSessionsReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeUserSessionsReply) ProtoMessage() {}
+
+func (x *RevokeUserSessionsReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeUserSessionsReply.ProtoReflect.Descriptor instead.
+func (*RevokeUserSessionsReply) Descriptor() ([]byte, []int) {
+	return file_proto_user_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *RevokeUserSessionsReply) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *RevokeUserSessionsReply) GetRevokedConsents() int32 {
+	if x != nil {
+		return x.RevokedConsents
+	}
+	return 0
+}
+
+// ข้อมูลสำหรับคำขอปลดล็อกผู้ใช้
+type UnlockUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"` // ID ของผู้ใช้
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnlockUserRequest) Reset() {
+	*x = UnlockUserRequest{}
+	mi := &file_proto_user_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnlockUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnlockUserRequest) ProtoMessage() {}
+
+func (x *UnlockUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnlockUserRequest.ProtoReflect.Descriptor instead.
+func (*UnlockUserRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *UnlockUserRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type UnlockUserReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"` // ข้อความสถานะ
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnlockUserReply) Reset() {
+	*x = UnlockUserReply{}
+	mi := &file_proto_user_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnlockUserReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnlockUserReply) ProtoMessage() {}
+
+func (x *UnlockUserReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnlockUserReply.ProtoReflect.Descriptor instead.
+func (*UnlockUserReply) Descriptor() ([]byte, []int) {
+	return file_proto_user_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *UnlockUserReply) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+var File_proto_user_proto protoreflect.FileDescriptor
+
+const file_proto_user_proto_rawDesc = "" +
+	"\n" +
+	"\x10proto/user.proto\x1a google/protobuf/field_mask.proto\x1a\x1egoogle/protobuf/wrappers.proto\"\x1f\n" +
+	"\rUserIdRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\xe0\x03\n" +
+	"\vUserIdReply\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\x12\x1a\n" +
+	"\busername\x18\x03 \x01(\tR\busername\x12\x1c\n" +
+	"\tcreatedAt\x18\x04 \x01(\tR\tcreatedAt\x12\x1c\n" +
+	"\tupdatedAt\x18\x05 \x01(\tR\tupdatedAt\x12\x12\n" +
+	"\x04role\x18\x06 \x01(\tR\x04role\x12 \n" +
+	"\vdisplayName\x18\a \x01(\tR\vdisplayName\x12\x1c\n" +
+	"\tavatarUrl\x18\b \x01(\tR\tavatarUrl\x12\x16\n" +
+	"\x06locale\x18\t \x01(\tR\x06locale\x12\x1a\n" +
+	"\btimezone\x18\n" +
+	" \x01(\tR\btimezone\x12\x14\n" +
+	"\x05phone\x18\v \x01(\tR\x05phone\x126\n" +
+	"\bmetadata\x18\f \x03(\v2\x1a.UserIdReply.MetadataEntryR\bmetadata\x12$\n" +
+	"\remailVerified\x18\r \x01(\bR\remailVerified\x12\x1a\n" +
+	"\btenantId\x18\x0e \x01(\tR\btenantId\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x80\x03\n" +
+	"\x11UpdateUserRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12 \n" +
+	"\vdisplayName\x18\x03 \x01(\tR\vdisplayName\x12\x1c\n" +
+	"\tavatarUrl\x18\x04 \x01(\tR\tavatarUrl\x12\x16\n" +
+	"\x06locale\x18\x05 \x01(\tR\x06locale\x12\x1a\n" +
+	"\btimezone\x18\x06 \x01(\tR\btimezone\x12\x14\n" +
+	"\x05phone\x18\a \x01(\tR\x05phone\x12<\n" +
+	"\bmetadata\x18\b \x03(\v2 .UpdateUserRequest.MetadataEntryR\bmetadata\x12:\n" +
+	"\n" +
+	"updateMask\x18\t \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
+	"updateMask\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"+\n" +
+	"\x0fUpdateUserReply\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\"#\n" +
+	"\x11DeleteUserRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"+\n" +
+	"\x0fDeleteUserReply\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\"$\n" +
+	"\x12RestoreUserRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\",\n" +
+	"\x10RestoreUserReply\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\"\"\n" +
+	"\x10PurgeUserRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"*\n" +
+	"\x0ePurgeUserReply\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\"\xa6\x03\n" +
+	"\x10ListUsersRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\x12\x12\n" +
 	"\x04page\x18\x03 \x01(\x05R\x04page\x12\x14\n" +
 	"\x05limit\x18\x04 \x01(\x05R\x05limit\x12\x12\n" +
 	"\x04role\x18\x05 \x01(\tR\x04role\x12\x14\n" +
-	"\x05token\x18\x06 \x01(\tR\x05token\"G\n" +
+	"\x05token\x18\x06 \x01(\tR\x05token\x12\x1c\n" +
+	"\tpageToken\x18\a \x01(\tR\tpageToken\x12\x18\n" +
+	"\aorderBy\x18\b \x01(\tR\aorderBy\x12\"\n" +
+	"\fincludeTotal\x18\t \x01(\bR\fincludeTotal\x12\x1c\n" +
+	"\tmatchMode\x18\n" +
+	" \x01(\tR\tmatchMode\x12\"\n" +
+	"\fcreatedAfter\x18\v \x01(\tR\fcreatedAfter\x12$\n" +
+	"\rcreatedBefore\x18\f \x01(\tR\rcreatedBefore\x126\n" +
+	"\bverified\x18\r \x01(\v2\x1a.google.protobuf.BoolValueR\bverified\x12\x18\n" +
+	"\adeleted\x18\x0e \x01(\tR\adeleted\"\x97\x01\n" +
 	"\x0eListUsersReply\x12\x1f\n" +
 	"\x05users\x18\x01 \x03(\v2\t.UserItemR\x05users\x12\x14\n" +
-	"\x05total\x18\x02 \x01(\x05R\x05total\"~\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\x12$\n" +
+	"\rnextPageToken\x18\x03 \x01(\tR\rnextPageToken\x12(\n" +
+	"\x0ftotalIsEstimate\x18\x04 \x01(\bR\x0ftotalIsEstimate\"\xda\x03\n" +
 	"\bUserItem\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
 	"\x05email\x18\x02 \x01(\tR\x05email\x12\x1a\n" +
 	"\busername\x18\x03 \x01(\tR\busername\x12\x1c\n" +
 	"\tcreatedAt\x18\x04 \x01(\tR\tcreatedAt\x12\x12\n" +
-	"\x04role\x18\x05 \x01(\tR\x04role2\xdb\x01\n" +
+	"\x04role\x18\x05 \x01(\tR\x04role\x12\x1c\n" +
+	"\tupdatedAt\x18\x06 \x01(\tR\tupdatedAt\x12 \n" +
+	"\vdisplayName\x18\a \x01(\tR\vdisplayName\x12\x1c\n" +
+	"\tavatarUrl\x18\b \x01(\tR\tavatarUrl\x12\x16\n" +
+	"\x06locale\x18\t \x01(\tR\x06locale\x12\x1a\n" +
+	"\btimezone\x18\n" +
+	" \x01(\tR\btimezone\x12\x14\n" +
+	"\x05phone\x18\v \x01(\tR\x05phone\x123\n" +
+	"\bmetadata\x18\f \x03(\v2\x17.UserItem.MetadataEntryR\bmetadata\x12$\n" +
+	"\remailVerified\x18\r \x01(\bR\remailVerified\x12\x1a\n" +
+	"\btenantId\x18\x0e \x01(\tR\btenantId\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x19\n" +
+	"\x17GetProfileSchemaRequest\"B\n" +
+	"\rProfileSchema\x121\n" +
+	"\n" +
+	"attributes\x18\x01 \x03(\v2\x11.ProfileAttributeR\n" +
+	"attributes\"\x98\x01\n" +
+	"\x10ProfileAttribute\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x1a\n" +
+	"\brequired\x18\x03 \x01(\bR\brequired\x12\x1c\n" +
+	"\tmaxLength\x18\x04 \x01(\x05R\tmaxLength\x12$\n" +
+	"\rallowedValues\x18\x05 \x03(\tR\rallowedValues\"\x15\n" +
+	"\x13ExportMyDataRequest\"'\n" +
+	"\x15ExportUserDataRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"A\n" +
+	"\x0fDataExportChunk\x12\x1a\n" +
+	"\bfilename\x18\x01 \x01(\tR\bfilename\x12\x12\n" +
+	"\x04data\x18\x02 \x01(\fR\x04data\"e\n" +
+	"\x11WatchUsersRequest\x12\x14\n" +
+	"\x05types\x18\x01 \x03(\tR\x05types\x12\x18\n" +
+	"\auserIds\x18\x02 \x03(\tR\auserIds\x12 \n" +
+	"\vresumeToken\x18\x03 \x01(\tR\vresumeToken\"\xe0\x01\n" +
+	"\x0fUserChangeEvent\x12 \n" +
+	"\vresumeToken\x18\x01 \x01(\tR\vresumeToken\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x16\n" +
+	"\x06userId\x18\x03 \x01(\tR\x06userId\x12\x1a\n" +
+	"\btenantId\x18\x04 \x01(\tR\btenantId\x12\x1e\n" +
+	"\n" +
+	"occurredAt\x18\x05 \x01(\tR\n" +
+	"occurredAt\x12$\n" +
+	"\rupdatedFields\x18\x06 \x03(\tR\rupdatedFields\x12\x1d\n" +
+	"\x04user\x18\a \x01(\v2\t.UserItemR\x04user\"t\n" +
+	"\x12ImportUsersRequest\x12\x16\n" +
+	"\x06format\x18\x01 \x01(\tR\x06format\x12\x16\n" +
+	"\x06dryRun\x18\x02 \x01(\bR\x06dryRun\x12\x1a\n" +
+	"\bimportId\x18\x03 \x01(\tR\bimportId\x12\x12\n" +
+	"\x04data\x18\x04 \x01(\fR\x04data\"\x85\x02\n" +
+	"\x10ImportUsersReply\x12\x1a\n" +
+	"\bimportId\x18\x01 \x01(\tR\bimportId\x12\x1c\n" +
+	"\ttotalRows\x18\x02 \x01(\x05R\ttotalRows\x12\x1a\n" +
+	"\bimported\x18\x03 \x01(\x05R\bimported\x12\x18\n" +
+	"\askipped\x18\x04 \x01(\x05R\askipped\x12\x16\n" +
+	"\x06failed\x18\x05 \x01(\x05R\x06failed\x12'\n" +
+	"\x06errors\x18\x06 \x03(\v2\x0f.ImportRowErrorR\x06errors\x12(\n" +
+	"\x0ferrorsTruncated\x18\a \x01(\bR\x0ferrorsTruncated\x12\x16\n" +
+	"\x06dryRun\x18\b \x01(\bR\x06dryRun\"R\n" +
+	"\x0eImportRowError\x12\x10\n" +
+	"\x03row\x18\x01 \x01(\x05R\x03row\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\"\x94\x01\n" +
+	"\x12ExportUsersRequest\x12\x16\n" +
+	"\x06format\x18\x01 \x01(\tR\x06format\x12\x12\n" +
+	"\x04role\x18\x02 \x01(\tR\x04role\x12\x18\n" +
+	"\adeleted\x18\x03 \x01(\tR\adeleted\x12 \n" +
+	"\vresumeToken\x18\x04 \x01(\tR\vresumeToken\x12\x16\n" +
+	"\x06dryRun\x18\x05 \x01(\bR\x06dryRun\"r\n" +
+	"\x10ExportUsersChunk\x12\x12\n" +
+	"\x04data\x18\x01 \x01(\fR\x04data\x12\x12\n" +
+	"\x04rows\x18\x02 \x01(\x05R\x04rows\x12 \n" +
+	"\vresumeToken\x18\x03 \x01(\tR\vresumeToken\x12\x14\n" +
+	"\x05total\x18\x04 \x01(\x05R\x05total\"8\n" +
+	"\x12SetUserRoleRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04role\x18\x02 \x01(\tR\x04role\"K\n" +
+	"\x10SetUserRoleReply\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\x12\x1d\n" +
+	"\x04user\x18\x02 \x01(\v2\t.UserItemR\x04user\"+\n" +
+	"\x19RevokeUserSessionsRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"]\n" +
+	"\x17RevokeUserSessionsReply\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\x12(\n" +
+	"\x0frevokedConsents\x18\x02 \x01(\x05R\x0frevokedConsents\"#\n" +
+	"\x11UnlockUserRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"+\n" +
+	"\x0fUnlockUserReply\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage2\xa7\a\n" +
 	"\vUserService\x12-\n" +
 	"\vGetUserById\x12\x0e.UserIdRequest\x1a\f.UserIdReply\"\x00\x124\n" +
 	"\n" +
 	"UpdateUser\x12\x12.UpdateUserRequest\x1a\x10.UpdateUserReply\"\x00\x124\n" +
 	"\n" +
-	"DeleteUser\x12\x12.DeleteUserRequest\x1a\x10.DeleteUserReply\"\x00\x121\n" +
-	"\tListUsers\x12\x11.ListUsersRequest\x1a\x0f.ListUsersReply\"\x00B\x19Z\x17auth-microservice/protob\x06proto3"
+	"DeleteUser\x12\x12.DeleteUserRequest\x1a\x10.DeleteUserReply\"\x00\x127\n" +
+	"\vRestoreUser\x12\x13.RestoreUserRequest\x1a\x11.RestoreUserReply\"\x00\x121\n" +
+	"\tPurgeUser\x12\x11.PurgeUserRequest\x1a\x0f.PurgeUserReply\"\x00\x121\n" +
+	"\tListUsers\x12\x11.ListUsersRequest\x1a\x0f.ListUsersReply\"\x00\x12>\n" +
+	"\x10GetProfileSchema\x12\x18.GetProfileSchemaRequest\x1a\x0e.ProfileSchema\"\x00\x127\n" +
+	"\x13UpdateProfileSchema\x12\x0e.ProfileSchema\x1a\x0e.ProfileSchema\"\x00\x12:\n" +
+	"\fExportMyData\x12\x14.ExportMyDataRequest\x1a\x10.DataExportChunk\"\x000\x01\x12>\n" +
+	"\x0eExportUserData\x12\x16.ExportUserDataRequest\x1a\x10.DataExportChunk\"\x000\x01\x126\n" +
+	"\n" +
+	"WatchUsers\x12\x12.WatchUsersRequest\x1a\x10.UserChangeEvent\"\x000\x01\x127\n" +
+	"\vSetUserRole\x12\x13.SetUserRoleRequest\x1a\x11.SetUserRoleReply\"\x00\x12L\n" +
+	"\x12RevokeUserSessions\x12\x1a.RevokeUserSessionsRequest\x1a\x18.RevokeUserSessionsReply\"\x00\x124\n" +
+	"\n" +
+	"UnlockUser\x12\x12.UnlockUserRequest\x1a\x10.UnlockUserReply\"\x00\x129\n" +
+	"\vImportUsers\x12\x13.ImportUsersRequest\x1a\x11.ImportUsersReply\"\x00(\x01\x129\n" +
+	"\vExportUsers\x12\x13.ExportUsersRequest\x1a\x11.ExportUsersChunk\"\x000\x01B\x19Z\x17auth-microservice/protob\x06proto3"
 
 var (
 	file_proto_user_proto_rawDescOnce sync.Once
@@ -606,33 +2334,94 @@ func file_proto_user_proto_rawDescGZIP() []byte {
 	return file_proto_user_proto_rawDescData
 }
 
-var file_proto_user_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_proto_user_proto_msgTypes = make([]protoimpl.MessageInfo, 35)
 var file_proto_user_proto_goTypes = []any{
-	(*UserIdRequest)(nil),     // 0: UserIdRequest
-	(*UserIdReply)(nil),       // 1: UserIdReply
-	(*UpdateUserRequest)(nil), // 2: UpdateUserRequest
-	(*UpdateUserReply)(nil),   // 3: UpdateUserReply
-	(*DeleteUserRequest)(nil), // 4: DeleteUserRequest
-	(*DeleteUserReply)(nil),   // 5: DeleteUserReply
-	(*ListUsersRequest)(nil),  // 6: ListUsersRequest
-	(*ListUsersReply)(nil),    // 7: ListUsersReply
-	(*UserItem)(nil),          // 8: UserItem
+	(*UserIdRequest)(nil),             // 0: UserIdRequest
+	(*UserIdReply)(nil),               // 1: UserIdReply
+	(*UpdateUserRequest)(nil),         // 2: UpdateUserRequest
+	(*UpdateUserReply)(nil),           // 3: UpdateUserReply
+	(*DeleteUserRequest)(nil),         // 4: DeleteUserRequest
+	(*DeleteUserReply)(nil),           // 5: DeleteUserReply
+	(*RestoreUserRequest)(nil),        // 6: RestoreUserRequest
+	(*RestoreUserReply)(nil),          // 7: RestoreUserReply
+	(*PurgeUserRequest)(nil),          // 8: PurgeUserRequest
+	(*PurgeUserReply)(nil),            // 9: PurgeUserReply
+	(*ListUsersRequest)(nil),          // 10: ListUsersRequest
+	(*ListUsersReply)(nil),            // 11: ListUsersReply
+	(*UserItem)(nil),                  // 12: UserItem
+	(*GetProfileSchemaRequest)(nil),   // 13: GetProfileSchemaRequest
+	(*ProfileSchema)(nil),             // 14: ProfileSchema
+	(*ProfileAttribute)(nil),          // 15: ProfileAttribute
+	(*ExportMyDataRequest)(nil),       // 16: ExportMyDataRequest
+	(*ExportUserDataRequest)(nil),     // 17: ExportUserDataRequest
+	(*DataExportChunk)(nil),           // 18: DataExportChunk
+	(*WatchUsersRequest)(nil),         // 19: WatchUsersRequest
+	(*UserChangeEvent)(nil),           // 20: UserChangeEvent
+	(*ImportUsersRequest)(nil),        // 21: ImportUsersRequest
+	(*ImportUsersReply)(nil),          // 22: ImportUsersReply
+	(*ImportRowError)(nil),            // 23: ImportRowError
+	(*ExportUsersRequest)(nil),        // 24: ExportUsersRequest
+	(*ExportUsersChunk)(nil),          // 25: ExportUsersChunk
+	(*SetUserRoleRequest)(nil),        // 26: SetUserRoleRequest
+	(*SetUserRoleReply)(nil),          // 27: SetUserRoleReply
+	(*RevokeUserSessionsRequest)(nil), // 28: RevokeUserSessionsRequest
+	(*RevokeUserSessionsReply)(nil),   // 29: RevokeUserSessionsReply
+	(*UnlockUserRequest)(nil),         // 30: UnlockUserRequest
+	(*UnlockUserReply)(nil),           // 31: UnlockUserReply
+	nil,                               // 32: UserIdReply.MetadataEntry
+	nil,                               // 33: UpdateUserRequest.MetadataEntry
+	nil,                               // 34: UserItem.MetadataEntry
+	(*fieldmaskpb.FieldMask)(nil),     // 35: google.protobuf.FieldMask
+	(*wrapperspb.BoolValue)(nil),      // 36: google.protobuf.BoolValue
 }
 var file_proto_user_proto_depIdxs = []int32{
-	8, // 0: ListUsersReply.users:type_name -> UserItem
-	0, // 1: UserService.GetUserById:input_type -> UserIdRequest
-	2, // 2: UserService.UpdateUser:input_type -> UpdateUserRequest
-	4, // 3: UserService.DeleteUser:input_type -> DeleteUserRequest
-	6, // 4: UserService.ListUsers:input_type -> ListUsersRequest
-	1, // 5: UserService.GetUserById:output_type -> UserIdReply
-	3, // 6: UserService.UpdateUser:output_type -> UpdateUserReply
-	5, // 7: UserService.DeleteUser:output_type -> DeleteUserReply
-	7, // 8: UserService.ListUsers:output_type -> ListUsersReply
-	5, // [5:9] is the sub-list for method output_type
-	1, // [1:5] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	32, // 0: UserIdReply.metadata:type_name -> UserIdReply.MetadataEntry
+	33, // 1: UpdateUserRequest.metadata:type_name -> UpdateUserRequest.MetadataEntry
+	35, // 2: UpdateUserRequest.updateMask:type_name -> google.protobuf.FieldMask
+	36, // 3: ListUsersRequest.verified:type_name -> google.protobuf.BoolValue
+	12, // 4: ListUsersReply.users:type_name -> UserItem
+	34, // 5: UserItem.metadata:type_name -> UserItem.MetadataEntry
+	15, // 6: ProfileSchema.attributes:type_name -> ProfileAttribute
+	12, // 7: UserChangeEvent.user:type_name -> UserItem
+	23, // 8: ImportUsersReply.errors:type_name -> ImportRowError
+	12, // 9: SetUserRoleReply.user:type_name -> UserItem
+	0,  // 10: UserService.GetUserById:input_type -> UserIdRequest
+	2,  // 11: UserService.UpdateUser:input_type -> UpdateUserRequest
+	4,  // 12: UserService.DeleteUser:input_type -> DeleteUserRequest
+	6,  // 13: UserService.RestoreUser:input_type -> RestoreUserRequest
+	8,  // 14: UserService.PurgeUser:input_type -> PurgeUserRequest
+	10, // 15: UserService.ListUsers:input_type -> ListUsersRequest
+	13, // 16: UserService.GetProfileSchema:input_type -> GetProfileSchemaRequest
+	14, // 17: UserService.UpdateProfileSchema:input_type -> ProfileSchema
+	16, // 18: UserService.ExportMyData:input_type -> ExportMyDataRequest
+	17, // 19: UserService.ExportUserData:input_type -> ExportUserDataRequest
+	19, // 20: UserService.WatchUsers:input_type -> WatchUsersRequest
+	26, // 21: UserService.SetUserRole:input_type -> SetUserRoleRequest
+	28, // 22: UserService.RevokeUserSessions:input_type -> RevokeUserSessionsRequest
+	30, // 23: UserService.UnlockUser:input_type -> UnlockUserRequest
+	21, // 24: UserService.ImportUsers:input_type -> ImportUsersRequest
+	24, // 25: UserService.ExportUsers:input_type -> ExportUsersRequest
+	1,  // 26: UserService.GetUserById:output_type -> UserIdReply
+	3,  // 27: UserService.UpdateUser:output_type -> UpdateUserReply
+	5,  // 28: UserService.DeleteUser:output_type -> DeleteUserReply
+	7,  // 29: UserService.RestoreUser:output_type -> RestoreUserReply
+	9,  // 30: UserService.PurgeUser:output_type -> PurgeUserReply
+	11, // 31: UserService.ListUsers:output_type -> ListUsersReply
+	14, // 32: UserService.GetProfileSchema:output_type -> ProfileSchema
+	14, // 33: UserService.UpdateProfileSchema:output_type -> ProfileSchema
+	18, // 34: UserService.ExportMyData:output_type -> DataExportChunk
+	18, // 35: UserService.ExportUserData:output_type -> DataExportChunk
+	20, // 36: UserService.WatchUsers:output_type -> UserChangeEvent
+	27, // 37: UserService.SetUserRole:output_type -> SetUserRoleReply
+	29, // 38: UserService.RevokeUserSessions:output_type -> RevokeUserSessionsReply
+	31, // 39: UserService.UnlockUser:output_type -> UnlockUserReply
+	22, // 40: UserService.ImportUsers:output_type -> ImportUsersReply
+	25, // 41: UserService.ExportUsers:output_type -> ExportUsersChunk
+	26, // [26:42] is the sub-list for method output_type
+	10, // [10:26] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
 }
 
 func init() { file_proto_user_proto_init() }
@@ -646,7 +2435,7 @@ func file_proto_user_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_user_proto_rawDesc), len(file_proto_user_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   9,
+			NumMessages:   35,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -21,10 +21,22 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	UserService_GetUserById_FullMethodName = "/UserService/GetUserById"
-	UserService_UpdateUser_FullMethodName  = "/UserService/UpdateUser"
-	UserService_DeleteUser_FullMethodName  = "/UserService/DeleteUser"
-	UserService_ListUsers_FullMethodName   = "/UserService/ListUsers"
+	UserService_GetUserById_FullMethodName         = "/UserService/GetUserById"
+	UserService_UpdateUser_FullMethodName          = "/UserService/UpdateUser"
+	UserService_DeleteUser_FullMethodName          = "/UserService/DeleteUser"
+	UserService_RestoreUser_FullMethodName         = "/UserService/RestoreUser"
+	UserService_PurgeUser_FullMethodName           = "/UserService/PurgeUser"
+	UserService_ListUsers_FullMethodName           = "/UserService/ListUsers"
+	UserService_GetProfileSchema_FullMethodName    = "/UserService/GetProfileSchema"
+	UserService_UpdateProfileSchema_FullMethodName = "/UserService/UpdateProfileSchema"
+	UserService_ExportMyData_FullMethodName        = "/UserService/ExportMyData"
+	UserService_ExportUserData_FullMethodName      = "/UserService/ExportUserData"
+	UserService_WatchUsers_FullMethodName          = "/UserService/WatchUsers"
+	UserService_SetUserRole_FullMethodName         = "/UserService/SetUserRole"
+	UserService_RevokeUserSessions_FullMethodName  = "/UserService/RevokeUserSessions"
+	UserService_UnlockUser_FullMethodName          = "/UserService/UnlockUser"
+	UserService_ImportUsers_FullMethodName         = "/UserService/ImportUsers"
+	UserService_ExportUsers_FullMethodName         = "/UserService/ExportUsers"
 )
 
 // UserServiceClient is the client API for UserService service.
@@ -33,14 +45,38 @@ const (
 //
 // บริการ UserService สำหรับจัดการข้อมูลผู้ใช้
 type UserServiceClient interface {
-	// ดึงข้อมูลผู้ใช้ตาม ID
+	// ดึงข้อมูลผู้ใช้ตาม ID (เฉพาะตัวเองหรือ admin ของ tenant)
 	GetUserById(ctx context.Context, in *UserIdRequest, opts ...grpc.CallOption) (*UserIdReply, error)
-	// อัปเดตข้อมูลผู้ใช้
+	// อัปเดตข้อมูลผู้ใช้ (เลือก field ที่ต้องการแก้ด้วย updateMask) เฉพาะตัวเองหรือ admin ของ tenant
 	UpdateUser(ctx context.Context, in *UpdateUserRequest, opts ...grpc.CallOption) (*UpdateUserReply, error)
-	// ลบผู้ใช้ (soft delete)
+	// ลบผู้ใช้ (soft delete) และยกเลิก token ทั้งหมดของผู้ใช้ เฉพาะตัวเองหรือ admin ของ tenant
 	DeleteUser(ctx context.Context, in *DeleteUserRequest, opts ...grpc.CallOption) (*DeleteUserReply, error)
+	// กู้คืนผู้ใช้ที่ถูก soft delete (เฉพาะ admin)
+	RestoreUser(ctx context.Context, in *RestoreUserRequest, opts ...grpc.CallOption) (*RestoreUserReply, error)
+	// ลบผู้ใช้ถาวรพร้อม session, token และข้อมูลส่วนบุคคลใน audit log (เฉพาะ admin)
+	PurgeUser(ctx context.Context, in *PurgeUserRequest, opts ...grpc.CallOption) (*PurgeUserReply, error)
 	// ดึงรายการผู้ใช้พร้อม pagination และกรองข้อมูล
 	ListUsers(ctx context.Context, in *ListUsersRequest, opts ...grpc.CallOption) (*ListUsersReply, error)
+	// ดึง schema ของ metadata ในโปรไฟล์ผู้ใช้
+	GetProfileSchema(ctx context.Context, in *GetProfileSchemaRequest, opts ...grpc.CallOption) (*ProfileSchema, error)
+	// กำหนด schema ของ metadata ในโปรไฟล์ผู้ใช้ (เฉพาะ admin)
+	UpdateProfileSchema(ctx context.Context, in *ProfileSchema, opts ...grpc.CallOption) (*ProfileSchema, error)
+	// ส่งออกข้อมูลส่วนบุคคลของผู้ใช้ที่เข้าสู่ระบบอยู่ เป็นไฟล์ zip (ส่งกลับทีละ chunk)
+	ExportMyData(ctx context.Context, in *ExportMyDataRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[DataExportChunk], error)
+	// ส่งออกข้อมูลส่วนบุคคลของผู้ใช้ตาม ID เป็นไฟล์ zip (เฉพาะ admin)
+	ExportUserData(ctx context.Context, in *ExportUserDataRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[DataExportChunk], error)
+	// ติดตามการเปลี่ยนแปลงของผู้ใช้ใน tenant ต่อเนื่องจนกว่าจะยกเลิก (เฉพาะ admin) ไม่มี hash รหัสผ่านหรือข้อมูลลับ
+	WatchUsers(ctx context.Context, in *WatchUsersRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[UserChangeEvent], error)
+	// กำหนด role ของผู้ใช้ (เฉพาะ admin) แล้วยกเลิก token เดิมของผู้ใช้เพื่อให้ role ใหม่มีผลทันที
+	SetUserRole(ctx context.Context, in *SetUserRoleRequest, opts ...grpc.CallOption) (*SetUserRoleReply, error)
+	// ยกเลิก session และ token ทั้งหมดของผู้ใช้ รวมถึง refresh token ของ OAuth client (เฉพาะ admin)
+	RevokeUserSessions(ctx context.Context, in *RevokeUserSessionsRequest, opts ...grpc.CallOption) (*RevokeUserSessionsReply, error)
+	// ล้างตัวนับการเข้าสู่ระบบผิดพลาดของผู้ใช้ เพื่อให้เข้าสู่ระบบได้ทันที (เฉพาะ admin)
+	UnlockUser(ctx context.Context, in *UnlockUserRequest, opts ...grpc.CallOption) (*UnlockUserReply, error)
+	// นำเข้าผู้ใช้จากไฟล์ CSV หรือ JSONL ที่ส่งมาทีละส่วน (เฉพาะ admin) ตรวจสอบทีละแถวและคืน error ของแต่ละแถว
+	ImportUsers(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ImportUsersRequest, ImportUsersReply], error)
+	// ส่งออกรายชื่อผู้ใช้ใน tenant เป็น CSV หรือ JSONL ทีละส่วน (เฉพาะ admin) ไม่มี hash รหัสผ่าน
+	ExportUsers(ctx context.Context, in *ExportUsersRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportUsersChunk], error)
 }
 
 type userServiceClient struct {
@@ -81,6 +117,26 @@ func (c *userServiceClient) DeleteUser(ctx context.Context, in *DeleteUserReques
 	return out, nil
 }
 
+func (c *userServiceClient) RestoreUser(ctx context.Context, in *RestoreUserRequest, opts ...grpc.CallOption) (*RestoreUserReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RestoreUserReply)
+	err := c.cc.Invoke(ctx, UserService_RestoreUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) PurgeUser(ctx context.Context, in *PurgeUserRequest, opts ...grpc.CallOption) (*PurgeUserReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PurgeUserReply)
+	err := c.cc.Invoke(ctx, UserService_PurgeUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *userServiceClient) ListUsers(ctx context.Context, in *ListUsersRequest, opts ...grpc.CallOption) (*ListUsersReply, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(ListUsersReply)
@@ -91,20 +147,183 @@ func (c *userServiceClient) ListUsers(ctx context.Context, in *ListUsersRequest,
 	return out, nil
 }
 
+func (c *userServiceClient) GetProfileSchema(ctx context.Context, in *GetProfileSchemaRequest, opts ...grpc.CallOption) (*ProfileSchema, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ProfileSchema)
+	err := c.cc.Invoke(ctx, UserService_GetProfileSchema_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) UpdateProfileSchema(ctx context.Context, in *ProfileSchema, opts ...grpc.CallOption) (*ProfileSchema, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ProfileSchema)
+	err := c.cc.Invoke(ctx, UserService_UpdateProfileSchema_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) ExportMyData(ctx context.Context, in *ExportMyDataRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[DataExportChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &UserService_ServiceDesc.Streams[0], UserService_ExportMyData_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ExportMyDataRequest, DataExportChunk]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type UserService_ExportMyDataClient = grpc.ServerStreamingClient[DataExportChunk]
+
+func (c *userServiceClient) ExportUserData(ctx context.Context, in *ExportUserDataRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[DataExportChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &UserService_ServiceDesc.Streams[1], UserService_ExportUserData_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ExportUserDataRequest, DataExportChunk]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type UserService_ExportUserDataClient = grpc.ServerStreamingClient[DataExportChunk]
+
+func (c *userServiceClient) WatchUsers(ctx context.Context, in *WatchUsersRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[UserChangeEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &UserService_ServiceDesc.Streams[2], UserService_WatchUsers_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchUsersRequest, UserChangeEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type UserService_WatchUsersClient = grpc.ServerStreamingClient[UserChangeEvent]
+
+func (c *userServiceClient) SetUserRole(ctx context.Context, in *SetUserRoleRequest, opts ...grpc.CallOption) (*SetUserRoleReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetUserRoleReply)
+	err := c.cc.Invoke(ctx, UserService_SetUserRole_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) RevokeUserSessions(ctx context.Context, in *RevokeUserSessionsRequest, opts ...grpc.CallOption) (*RevokeUserSessionsReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RevokeUserSessionsReply)
+	err := c.cc.Invoke(ctx, UserService_RevokeUserSessions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) UnlockUser(ctx context.Context, in *UnlockUserRequest, opts ...grpc.CallOption) (*UnlockUserReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UnlockUserReply)
+	err := c.cc.Invoke(ctx, UserService_UnlockUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) ImportUsers(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ImportUsersRequest, ImportUsersReply], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &UserService_ServiceDesc.Streams[3], UserService_ImportUsers_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ImportUsersRequest, ImportUsersReply]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type UserService_ImportUsersClient = grpc.ClientStreamingClient[ImportUsersRequest, ImportUsersReply]
+
+func (c *userServiceClient) ExportUsers(ctx context.Context, in *ExportUsersRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportUsersChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &UserService_ServiceDesc.Streams[4], UserService_ExportUsers_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ExportUsersRequest, ExportUsersChunk]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type UserService_ExportUsersClient = grpc.ServerStreamingClient[ExportUsersChunk]
+
 // UserServiceServer is the server API for UserService service.
 // All implementations must embed UnimplementedUserServiceServer
 // for forward compatibility.
 //
 // บริการ UserService สำหรับจัดการข้อมูลผู้ใช้
 type UserServiceServer interface {
-	// ดึงข้อมูลผู้ใช้ตาม ID
+	// ดึงข้อมูลผู้ใช้ตาม ID (เฉพาะตัวเองหรือ admin ของ tenant)
 	GetUserById(context.Context, *UserIdRequest) (*UserIdReply, error)
-	// อัปเดตข้อมูลผู้ใช้
+	// อัปเดตข้อมูลผู้ใช้ (เลือก field ที่ต้องการแก้ด้วย updateMask) เฉพาะตัวเองหรือ admin ของ tenant
 	UpdateUser(context.Context, *UpdateUserRequest) (*UpdateUserReply, error)
-	// ลบผู้ใช้ (soft delete)
+	// ลบผู้ใช้ (soft delete) และยกเลิก token ทั้งหมดของผู้ใช้ เฉพาะตัวเองหรือ admin ของ tenant
 	DeleteUser(context.Context, *DeleteUserRequest) (*DeleteUserReply, error)
+	// กู้คืนผู้ใช้ที่ถูก soft delete (เฉพาะ admin)
+	RestoreUser(context.Context, *RestoreUserRequest) (*RestoreUserReply, error)
+	// ลบผู้ใช้ถาวรพร้อม session, token และข้อมูลส่วนบุคคลใน audit log (เฉพาะ admin)
+	PurgeUser(context.Context, *PurgeUserRequest) (*PurgeUserReply, error)
 	// ดึงรายการผู้ใช้พร้อม pagination และกรองข้อมูล
 	ListUsers(context.Context, *ListUsersRequest) (*ListUsersReply, error)
+	// ดึง schema ของ metadata ในโปรไฟล์ผู้ใช้
+	GetProfileSchema(context.Context, *GetProfileSchemaRequest) (*ProfileSchema, error)
+	// กำหนด schema ของ metadata ในโปรไฟล์ผู้ใช้ (เฉพาะ admin)
+	UpdateProfileSchema(context.Context, *ProfileSchema) (*ProfileSchema, error)
+	// ส่งออกข้อมูลส่วนบุคคลของผู้ใช้ที่เข้าสู่ระบบอยู่ เป็นไฟล์ zip (ส่งกลับทีละ chunk)
+	ExportMyData(*ExportMyDataRequest, grpc.ServerStreamingServer[DataExportChunk]) error
+	// ส่งออกข้อมูลส่วนบุคคลของผู้ใช้ตาม ID เป็นไฟล์ zip (เฉพาะ admin)
+	ExportUserData(*ExportUserDataRequest, grpc.ServerStreamingServer[DataExportChunk]) error
+	// ติดตามการเปลี่ยนแปลงของผู้ใช้ใน tenant ต่อเนื่องจนกว่าจะยกเลิก (เฉพาะ admin) ไม่มี hash รหัสผ่านหรือข้อมูลลับ
+	WatchUsers(*WatchUsersRequest, grpc.ServerStreamingServer[UserChangeEvent]) error
+	// กำหนด role ของผู้ใช้ (เฉพาะ admin) แล้วยกเลิก token เดิมของผู้ใช้เพื่อให้ role ใหม่มีผลทันที
+	SetUserRole(context.Context, *SetUserRoleRequest) (*SetUserRoleReply, error)
+	// ยกเลิก session และ token ทั้งหมดของผู้ใช้ รวมถึง refresh token ของ OAuth client (เฉพาะ admin)
+	RevokeUserSessions(context.Context, *RevokeUserSessionsRequest) (*RevokeUserSessionsReply, error)
+	// ล้างตัวนับการเข้าสู่ระบบผิดพลาดของผู้ใช้ เพื่อให้เข้าสู่ระบบได้ทันที (เฉพาะ admin)
+	UnlockUser(context.Context, *UnlockUserRequest) (*UnlockUserReply, error)
+	// นำเข้าผู้ใช้จากไฟล์ CSV หรือ JSONL ที่ส่งมาทีละส่วน (เฉพาะ admin) ตรวจสอบทีละแถวและคืน error ของแต่ละแถว
+	ImportUsers(grpc.ClientStreamingServer[ImportUsersRequest, ImportUsersReply]) error
+	// ส่งออกรายชื่อผู้ใช้ใน tenant เป็น CSV หรือ JSONL ทีละส่วน (เฉพาะ admin) ไม่มี hash รหัสผ่าน
+	ExportUsers(*ExportUsersRequest, grpc.ServerStreamingServer[ExportUsersChunk]) error
 	mustEmbedUnimplementedUserServiceServer()
 }
 
@@ -124,9 +343,45 @@ func (UnimplementedUserServiceServer) UpdateUser(context.Context, *UpdateUserReq
 func (UnimplementedUserServiceServer) DeleteUser(context.Context, *DeleteUserRequest) (*DeleteUserReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteUser not implemented")
 }
+func (UnimplementedUserServiceServer) RestoreUser(context.Context, *RestoreUserRequest) (*RestoreUserReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreUser not implemented")
+}
+func (UnimplementedUserServiceServer) PurgeUser(context.Context, *PurgeUserRequest) (*PurgeUserReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PurgeUser not implemented")
+}
 func (UnimplementedUserServiceServer) ListUsers(context.Context, *ListUsersRequest) (*ListUsersReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListUsers not implemented")
 }
+func (UnimplementedUserServiceServer) GetProfileSchema(context.Context, *GetProfileSchemaRequest) (*ProfileSchema, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProfileSchema not implemented")
+}
+func (UnimplementedUserServiceServer) UpdateProfileSchema(context.Context, *ProfileSchema) (*ProfileSchema, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateProfileSchema not implemented")
+}
+func (UnimplementedUserServiceServer) ExportMyData(*ExportMyDataRequest, grpc.ServerStreamingServer[DataExportChunk]) error {
+	return status.Errorf(codes.Unimplemented, "method ExportMyData not implemented")
+}
+func (UnimplementedUserServiceServer) ExportUserData(*ExportUserDataRequest, grpc.ServerStreamingServer[DataExportChunk]) error {
+	return status.Errorf(codes.Unimplemented, "method ExportUserData not implemented")
+}
+func (UnimplementedUserServiceServer) WatchUsers(*WatchUsersRequest, grpc.ServerStreamingServer[UserChangeEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchUsers not implemented")
+}
+func (UnimplementedUserServiceServer) SetUserRole(context.Context, *SetUserRoleRequest) (*SetUserRoleReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetUserRole not implemented")
+}
+func (UnimplementedUserServiceServer) RevokeUserSessions(context.Context, *RevokeUserSessionsRequest) (*RevokeUserSessionsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeUserSessions not implemented")
+}
+func (UnimplementedUserServiceServer) UnlockUser(context.Context, *UnlockUserRequest) (*UnlockUserReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnlockUser not implemented")
+}
+func (UnimplementedUserServiceServer) ImportUsers(grpc.ClientStreamingServer[ImportUsersRequest, ImportUsersReply]) error {
+	return status.Errorf(codes.Unimplemented, "method ImportUsers not implemented")
+}
+func (UnimplementedUserServiceServer) ExportUsers(*ExportUsersRequest, grpc.ServerStreamingServer[ExportUsersChunk]) error {
+	return status.Errorf(codes.Unimplemented, "method ExportUsers not implemented")
+}
 func (UnimplementedUserServiceServer) mustEmbedUnimplementedUserServiceServer() {}
 func (UnimplementedUserServiceServer) testEmbeddedByValue()                     {}
 
@@ -202,6 +457,42 @@ func _UserService_DeleteUser_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _UserService_RestoreUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).RestoreUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_RestoreUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).RestoreUser(ctx, req.(*RestoreUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_PurgeUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PurgeUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).PurgeUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_PurgeUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).PurgeUser(ctx, req.(*PurgeUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _UserService_ListUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ListUsersRequest)
 	if err := dec(in); err != nil {
@@ -220,6 +511,147 @@ func _UserService_ListUsers_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _UserService_GetProfileSchema_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProfileSchemaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).GetProfileSchema(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_GetProfileSchema_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).GetProfileSchema(ctx, req.(*GetProfileSchemaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_UpdateProfileSchema_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProfileSchema)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).UpdateProfileSchema(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_UpdateProfileSchema_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).UpdateProfileSchema(ctx, req.(*ProfileSchema))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_ExportMyData_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportMyDataRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(UserServiceServer).ExportMyData(m, &grpc.GenericServerStream[ExportMyDataRequest, DataExportChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type UserService_ExportMyDataServer = grpc.ServerStreamingServer[DataExportChunk]
+
+func _UserService_ExportUserData_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportUserDataRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(UserServiceServer).ExportUserData(m, &grpc.GenericServerStream[ExportUserDataRequest, DataExportChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type UserService_ExportUserDataServer = grpc.ServerStreamingServer[DataExportChunk]
+
+func _UserService_WatchUsers_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchUsersRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(UserServiceServer).WatchUsers(m, &grpc.GenericServerStream[WatchUsersRequest, UserChangeEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type UserService_WatchUsersServer = grpc.ServerStreamingServer[UserChangeEvent]
+
+func _UserService_SetUserRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetUserRoleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).SetUserRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_SetUserRole_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).SetUserRole(ctx, req.(*SetUserRoleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_RevokeUserSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeUserSessionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).RevokeUserSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_RevokeUserSessions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).RevokeUserSessions(ctx, req.(*RevokeUserSessionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_UnlockUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnlockUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).UnlockUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_UnlockUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).UnlockUser(ctx, req.(*UnlockUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_ImportUsers_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(UserServiceServer).ImportUsers(&grpc.GenericServerStream[ImportUsersRequest, ImportUsersReply]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type UserService_ImportUsersServer = grpc.ClientStreamingServer[ImportUsersRequest, ImportUsersReply]
+
+func _UserService_ExportUsers_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportUsersRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(UserServiceServer).ExportUsers(m, &grpc.GenericServerStream[ExportUsersRequest, ExportUsersChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type UserService_ExportUsersServer = grpc.ServerStreamingServer[ExportUsersChunk]
+
 // UserService_ServiceDesc is the grpc.ServiceDesc for UserService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -239,11 +671,65 @@ var UserService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteUser",
 			Handler:    _UserService_DeleteUser_Handler,
 		},
+		{
+			MethodName: "RestoreUser",
+			Handler:    _UserService_RestoreUser_Handler,
+		},
+		{
+			MethodName: "PurgeUser",
+			Handler:    _UserService_PurgeUser_Handler,
+		},
 		{
 			MethodName: "ListUsers",
 			Handler:    _UserService_ListUsers_Handler,
 		},
+		{
+			MethodName: "GetProfileSchema",
+			Handler:    _UserService_GetProfileSchema_Handler,
+		},
+		{
+			MethodName: "UpdateProfileSchema",
+			Handler:    _UserService_UpdateProfileSchema_Handler,
+		},
+		{
+			MethodName: "SetUserRole",
+			Handler:    _UserService_SetUserRole_Handler,
+		},
+		{
+			MethodName: "RevokeUserSessions",
+			Handler:    _UserService_RevokeUserSessions_Handler,
+		},
+		{
+			MethodName: "UnlockUser",
+			Handler:    _UserService_UnlockUser_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ExportMyData",
+			Handler:       _UserService_ExportMyData_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ExportUserData",
+			Handler:       _UserService_ExportUserData_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchUsers",
+			Handler:       _UserService_WatchUsers_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ImportUsers",
+			Handler:       _UserService_ImportUsers_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "ExportUsers",
+			Handler:       _UserService_ExportUsers_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/user.proto",
 }
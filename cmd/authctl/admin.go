@@ -0,0 +1,188 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"text/tabwriter"
+	"time"
+
+	pb "auth-microservice/auth-microservice/proto"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const seedChunkRows = 1000 // จำนวนแถวต่อ message ของ ImportUsers ตอนสร้างข้อมูลทดสอบ
+
+func (c *client) keysCommand(args []string) error {
+	if len(args) == 0 || args[0] != "rotate" {
+		return errors.New("usage: authctl keys rotate [-id tenant]")
+	}
+	fs := flag.NewFlagSet("keys rotate", flag.ContinueOnError)
+	id := fs.String("id", "", "tenant whose key is rotated (default: the caller's tenant)")
+	if _, err := parseFlags(fs, args[1:]); err != nil {
+		return err
+	}
+
+	ctx, cancel := c.context()
+	defer cancel()
+	tenant, err := c.tenant.RotateSigningKey(ctx, &pb.RotateSigningKeyRequest{Id: *id})
+	if err != nil {
+		return err
+	}
+	if c.jsonOutput() {
+		return c.printJSON(tenant, true)
+	}
+	fmt.Fprintf(c.out, "signing keys of tenant %s\n", tenant.GetId())
+	w := c.table("KID", "CREATED", "ACTIVE")
+	for _, key := range tenant.GetSigningKeys() {
+		row(w, key.GetId(), key.GetCreatedAt(), key.GetActive())
+	}
+	return w.Flush()
+}
+
+func (c *client) auditCommand(args []string) error {
+	if len(args) == 0 || args[0] != "tail" {
+		return errors.New("usage: authctl audit tail [-n 20] [-f] [-action a] [-subject id]")
+	}
+	fs := flag.NewFlagSet("audit tail", flag.ContinueOnError)
+	n := fs.Int("n", 20, "number of recent events to show (max 100)")
+	follow := fs.Bool("f", false, "keep polling for new events")
+	interval := fs.Duration("interval", 2*time.Second, "polling interval with -f")
+	in := &pb.ListAuditEventsRequest{Latest: true}
+	fs.StringVar(&in.Action, "action", "", `only this action, e.g. "user.deleted"`)
+	fs.StringVar(&in.SubjectId, "subject", "", "only events about this user ID")
+	if _, err := parseFlags(fs, args[1:]); err != nil {
+		return err
+	}
+	in.Limit = int32(*n)
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt)
+	defer signal.Stop(stop)
+
+	header := false
+	for {
+		ctx, cancel := c.context()
+		reply, err := c.audit.ListAuditEvents(ctx, in)
+		cancel()
+		if err != nil {
+			return err
+		}
+
+		if c.jsonOutput() {
+			// หนึ่งเหตุการณ์ต่อบรรทัด
+			for _, ev := range reply.GetEvents() {
+				if err := c.printJSON(ev, false); err != nil {
+					return err
+				}
+			}
+		} else if len(reply.GetEvents()) > 0 || !header {
+			// แสดงหัวตารางครั้งเดียว เหตุการณ์ถัดไปต่อท้ายโดยไม่มีหัวตาราง
+			var w *tabwriter.Writer
+			if header {
+				w = c.table()
+			} else {
+				w = c.table("TIME", "ACTION", "ACTOR", "SUBJECT", "DETAILS")
+			}
+			header = true
+			for _, ev := range reply.GetEvents() {
+				subject := ev.GetSubjectEmail()
+				if subject == "" {
+					subject = ev.GetSubjectId()
+				}
+				row(w, ev.GetCreatedAt(), ev.GetAction(), ev.GetActorEmail(), subject, ev.GetDetails())
+			}
+			if err := w.Flush(); err != nil {
+				return err
+			}
+		}
+
+		if !*follow {
+			return nil
+		}
+		// ครั้งถัดไปอ่านต่อจากเหตุการณ์ล่าสุดที่แสดงแล้ว
+		in.Latest = false
+		in.AfterId = reply.GetLastId()
+		in.Limit = 100
+		if len(reply.GetEvents()) == 100 {
+			continue
+		}
+		select {
+		case <-stop:
+			return nil
+		case <-time.After(*interval):
+		}
+	}
+}
+
+// สร้างผู้ใช้จำนวนมากสำหรับทดสอบ load ผ่าน ImportUsers (ทุกคนใช้รหัสผ่านเดียวกัน hash ครั้งเดียว)
+func (c *client) seedCommand(args []string) error {
+	fs := flag.NewFlagSet("seed", flag.ContinueOnError)
+	count := fs.Int("count", 1000, "number of users to create")
+	prefix := fs.String("prefix", "bulkuser", "email and username prefix")
+	domain := fs.String("domain", "example.com", "email domain")
+	password := fs.String("password", "Password123!", "password of every seeded user")
+	role := fs.String("role", "user", "role of every seeded user")
+	if _, err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *count < 1 {
+		return errors.New("-count must be at least 1")
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(*password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	next := 0
+	var genErr error
+	reply, err := c.importUsers(func() []byte {
+		if next >= *count || genErr != nil {
+			return nil
+		}
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		end := next + seedChunkRows
+		if end > *count {
+			end = *count
+		}
+		for ; next < end; next++ {
+			name := fmt.Sprintf("%s%06d", *prefix, next)
+			if genErr = enc.Encode(map[string]interface{}{
+				"email":        name + "@" + *domain,
+				"username":     name,
+				"passwordHash": string(hash),
+				"role":         *role,
+			}); genErr != nil {
+				return nil
+			}
+		}
+		return buf.Bytes()
+	})
+	if genErr != nil {
+		return genErr
+	}
+	if err != nil {
+		return err
+	}
+
+	if c.jsonOutput() {
+		return c.printJSON(reply, true)
+	}
+	fmt.Fprintf(c.out, "seeded %d of %d users in %s (failed: %d)\n",
+		reply.GetImported(), reply.GetTotalRows(), time.Since(start).Round(time.Millisecond), reply.GetFailed())
+	for _, rowErr := range reply.GetErrors() {
+		fmt.Fprintf(c.out, "  row %d %s: %s\n", rowErr.GetRow(), rowErr.GetEmail(), rowErr.GetMessage())
+	}
+	if reply.GetErrorsTruncated() {
+		fmt.Fprintln(c.out, "  ...")
+	}
+	return nil
+}
@@ -0,0 +1,309 @@
+// authctl คือเครื่องมือ command line สำหรับผู้ดูแลระบบ เรียก auth-microservice ผ่าน gRPC ด้วยสิทธิ์ admin
+//
+//	export AUTHCTL_TOKEN=$(authctl -email admin@example.com -password ... login)
+//	authctl [flags] users list|find|create|update|disable|restore|purge|role|revoke-sessions|unlock ...
+//	authctl [flags] keys rotate [-id tenant]
+//	authctl [flags] audit tail [-n 20] [-f]
+//	authctl [flags] seed [-count 1000]
+//	authctl migrate <up|down [steps]|status>
+//	authctl create-admin -email e -username u -password p
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	pb "auth-microservice/auth-microservice/proto"
+	"auth-microservice/internal/auth"
+	"auth-microservice/internal/server"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const usage = `usage: authctl [flags] <command> [args]
+
+commands:
+  login                         log in with -email and -password and print the token for AUTHCTL_TOKEN
+  users list [-email e] [-name n] [-role r] [-deleted exclude|include|only] [-limit n] [-all]
+  users find <id|email>
+  users create -email e -password p [-username u] [-role r] [-display-name n]
+  users update <id> [-username u] [-display-name n] [-avatar-url u] [-locale l] [-timezone z] [-phone p] [-meta key=value]...
+  users disable <id>            soft delete and revoke the user's token
+  users restore <id>
+  users purge -yes <id>         permanently delete the user and their personal data
+  users role <id> <user|tenant_admin|admin>
+  users revoke-sessions <id>    revoke the active token and OAuth refresh tokens
+  users unlock <id>             clear failed login attempts
+  keys rotate [-id tenant]      rotate the tenant's JWT signing key
+  audit tail [-n 20] [-f] [-action a] [-subject id]
+  seed [-count 1000] [-prefix bulkuser] [-password p]
+  migrate <up|down [steps]|status>   run database migrations locally (uses the server's environment)
+  create-admin -email e -username u -password p
+                                create the first platform admin locally, or promote an existing user
+
+flags:
+`
+
+// ตัวเลือกที่ใช้ร่วมกันทุกคำสั่ง
+type options struct {
+	addr     string
+	token    string
+	apiKey   string
+	email    string
+	password string
+	tenant   string
+	output   string
+	useTLS   bool
+	timeout  time.Duration
+}
+
+// การเชื่อมต่อกับ server พร้อม metadata ของ admin
+type client struct {
+	opts   options
+	conn   *grpc.ClientConn
+	md     metadata.MD
+	out    io.Writer
+	users  pb.UserServiceClient
+	audit  pb.AuditServiceClient
+	tenant pb.TenantServiceClient
+}
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		if st, ok := status.FromError(err); ok {
+			fmt.Fprintf(os.Stderr, "authctl: %s: %s\n", st.Code(), st.Message())
+		} else {
+			fmt.Fprintf(os.Stderr, "authctl: %v\n", err)
+		}
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	var opts options
+	fs := flag.NewFlagSet("authctl", flag.ContinueOnError)
+	fs.StringVar(&opts.addr, "addr", getEnv("AUTHCTL_ADDR", "localhost:50051"), "gRPC address of the server (AUTHCTL_ADDR)")
+	fs.StringVar(&opts.token, "token", os.Getenv("AUTHCTL_TOKEN"), "admin JWT (AUTHCTL_TOKEN)")
+	fs.StringVar(&opts.apiKey, "api-key", os.Getenv("AUTHCTL_API_KEY"), "admin API key, used instead of a token (AUTHCTL_API_KEY)")
+	fs.StringVar(&opts.email, "email", os.Getenv("AUTHCTL_EMAIL"), "admin email, used to log in when no token is given (AUTHCTL_EMAIL)")
+	fs.StringVar(&opts.password, "password", os.Getenv("AUTHCTL_PASSWORD"), "admin password (AUTHCTL_PASSWORD)")
+	fs.StringVar(&opts.tenant, "tenant", os.Getenv("AUTHCTL_TENANT"), "tenant to operate on (AUTHCTL_TENANT)")
+	fs.StringVar(&opts.output, "o", "table", "output format: table or json")
+	fs.BoolVar(&opts.useTLS, "tls", false, "connect with TLS")
+	fs.DurationVar(&opts.timeout, "timeout", 30*time.Second, "timeout of each request")
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), usage)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if opts.output != "table" && opts.output != "json" {
+		return fmt.Errorf("unknown output format %q", opts.output)
+	}
+
+	args = fs.Args()
+	if len(args) == 0 {
+		fs.Usage()
+		return errors.New("missing command")
+	}
+
+	// migration และการสร้าง admin คนแรกรันกับฐานข้อมูลโดยตรง ไม่ผ่าน gRPC
+	switch args[0] {
+	case "migrate":
+		return server.RunMigrations(args[1:])
+	case "create-admin":
+		return server.RunCreateAdmin(args[1:])
+	}
+
+	c, err := dial(opts)
+	if err != nil {
+		return err
+	}
+	defer c.conn.Close()
+
+	if args[0] == "login" {
+		token, err := c.login()
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(c.out, token)
+		return err
+	}
+	if err := c.authorize(); err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "users":
+		return c.usersCommand(args[1:])
+	case "keys":
+		return c.keysCommand(args[1:])
+	case "audit":
+		return c.auditCommand(args[1:])
+	case "seed":
+		return c.seedCommand(args[1:])
+	}
+	return fmt.Errorf("unknown command %q", args[0])
+}
+
+// เชื่อมต่อ server (ยังไม่แนบสิทธิ์ของ admin)
+func dial(opts options) (*client, error) {
+	creds := insecure.NewCredentials()
+	if opts.useTLS {
+		creds = credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12})
+	}
+	conn, err := grpc.NewClient(opts.addr, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, err
+	}
+	c := &client{
+		opts:   opts,
+		conn:   conn,
+		md:     metadata.MD{},
+		out:    os.Stdout,
+		users:  pb.NewUserServiceClient(conn),
+		audit:  pb.NewAuditServiceClient(conn),
+		tenant: pb.NewTenantServiceClient(conn),
+	}
+	if opts.tenant != "" {
+		c.md.Set(auth.TenantMetadataKey, opts.tenant)
+	}
+	return c, nil
+}
+
+// แนบ token หรือ API key ของ admin กับทุก request
+// ถ้าไม่ได้ระบุจะเข้าสู่ระบบด้วยอีเมลและรหัสผ่าน (การเข้าสู่ระบบนับรวมใน rate limit ถ้าเรียกบ่อยควรใช้คำสั่ง login แล้วตั้ง AUTHCTL_TOKEN)
+func (c *client) authorize() error {
+	switch {
+	case c.opts.token != "":
+		c.md.Set("authorization", "Bearer "+c.opts.token)
+	case c.opts.apiKey != "":
+		c.md.Set("authorization", "ApiKey "+c.opts.apiKey)
+	default:
+		token, err := c.login()
+		if err != nil {
+			return err
+		}
+		c.md.Set("authorization", "Bearer "+token)
+	}
+	return nil
+}
+
+func (c *client) login() (string, error) {
+	if c.opts.email == "" || c.opts.password == "" {
+		return "", errors.New("set -token, -api-key or -email and -password (AUTHCTL_TOKEN, AUTHCTL_API_KEY, AUTHCTL_EMAIL, AUTHCTL_PASSWORD)")
+	}
+	ctx, cancel := c.context()
+	defer cancel()
+	reply, err := pb.NewAuthServiceClient(c.conn).Login(ctx, &pb.LoginRequest{Email: c.opts.email, Password: c.opts.password})
+	if err != nil {
+		return "", err
+	}
+	if reply.GetSecondFactorRequired() {
+		return "", errors.New("this account requires a passkey, use -api-key or a token from another client")
+	}
+	return reply.GetToken(), nil
+}
+
+// context ของหนึ่ง request พร้อม metadata ของ admin
+func (c *client) context() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.opts.timeout)
+	return metadata.NewOutgoingContext(ctx, c.md), cancel
+}
+
+// context สำหรับ stream ที่อาจใช้เวลานาน (ยกเลิกได้ด้วย cancel เท่านั้น)
+func (c *client) streamContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	return metadata.NewOutgoingContext(ctx, c.md), cancel
+}
+
+func (c *client) jsonOutput() bool {
+	return c.opts.output == "json"
+}
+
+// พิมพ์ message เป็น JSON (indent = false จะได้หนึ่งบรรทัด ใช้กับผลลัพธ์ที่ส่งต่อเนื่อง)
+func (c *client) printJSON(m proto.Message, indent bool) error {
+	opts := protojson.MarshalOptions{EmitUnpopulated: true}
+	if indent {
+		opts.Multiline = true
+		opts.Indent = "  "
+	}
+	data, err := opts.Marshal(m)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(c.out, string(data))
+	return err
+}
+
+// พิมพ์ข้อความสถานะจาก server หรือ reply ทั้งหมดเมื่อใช้ -o json
+func (c *client) printMessage(m proto.Message, message string) error {
+	if c.jsonOutput() {
+		return c.printJSON(m, true)
+	}
+	_, err := fmt.Fprintln(c.out, message)
+	return err
+}
+
+// ตารางที่จัดคอลัมน์ด้วย tab (ไม่ระบุ header = ไม่มีหัวตาราง)
+func (c *client) table(header ...string) *tabwriter.Writer {
+	w := tabwriter.NewWriter(c.out, 0, 4, 2, ' ', 0)
+	if len(header) > 0 {
+		values := make([]interface{}, len(header))
+		for i, h := range header {
+			values[i] = h
+		}
+		row(w, values...)
+	}
+	return w
+}
+
+func row(w io.Writer, values ...interface{}) {
+	for i, v := range values {
+		if i > 0 {
+			fmt.Fprint(w, "\t")
+		}
+		fmt.Fprint(w, v)
+	}
+	fmt.Fprintln(w)
+}
+
+// parse flag ของคำสั่งย่อย โดยยอมให้ argument ตามตำแหน่งอยู่ก่อนหรือหลัง flag
+func parseFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+func getEnv(key string, fallback string) string {
+	if value, ok := os.LookupEnv(key); ok {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,421 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	pb "auth-microservice/auth-microservice/proto"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+func (c *client) usersCommand(args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: authctl users <list|find|create|update|disable|restore|purge|role|revoke-sessions|unlock>")
+	}
+	switch args[0] {
+	case "list":
+		return c.listUsers(args[1:])
+	case "find":
+		return c.findUser(args[1:])
+	case "create":
+		return c.createUser(args[1:])
+	case "update":
+		return c.updateUser(args[1:])
+	case "disable":
+		return c.disableUser(args[1:])
+	case "restore":
+		return c.restoreUser(args[1:])
+	case "purge":
+		return c.purgeUser(args[1:])
+	case "role":
+		return c.setUserRole(args[1:])
+	case "revoke-sessions":
+		return c.revokeUserSessions(args[1:])
+	case "unlock":
+		return c.unlockUser(args[1:])
+	}
+	return fmt.Errorf("unknown users command %q", args[0])
+}
+
+func (c *client) listUsers(args []string) error {
+	fs := flag.NewFlagSet("users list", flag.ContinueOnError)
+	in := &pb.ListUsersRequest{}
+	fs.StringVar(&in.Email, "email", "", "filter by email prefix")
+	fs.StringVar(&in.Name, "name", "", "filter by username prefix")
+	fs.StringVar(&in.Role, "role", "", "filter by role")
+	fs.StringVar(&in.Deleted, "deleted", "", "exclude (default), include or only")
+	fs.StringVar(&in.OrderBy, "order-by", "", `e.g. "createdAt desc"`)
+	fs.StringVar(&in.PageToken, "page-token", "", "continue from a previous page")
+	limit := fs.Int("limit", 20, "users per page (max 100)")
+	all := fs.Bool("all", false, "follow nextPageToken until the last page")
+	if _, err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	in.Limit = int32(*limit)
+	in.IncludeTotal = !*all
+
+	var w *tabwriter.Writer
+	for {
+		ctx, cancel := c.context()
+		reply, err := c.users.ListUsers(ctx, in)
+		cancel()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case c.jsonOutput() && !*all:
+			return c.printJSON(reply, true)
+		case c.jsonOutput():
+			// ทุกหน้า: หนึ่งผู้ใช้ต่อบรรทัด
+			for _, user := range reply.GetUsers() {
+				if err := c.printJSON(user, false); err != nil {
+					return err
+				}
+			}
+		default:
+			if w == nil {
+				w = c.table("ID", "EMAIL", "USERNAME", "ROLE", "VERIFIED", "CREATED")
+			}
+			for _, user := range reply.GetUsers() {
+				row(w, user.GetId(), user.GetEmail(), user.GetUsername(), user.GetRole(), user.GetEmailVerified(), user.GetCreatedAt())
+			}
+		}
+
+		if !*all || reply.GetNextPageToken() == "" {
+			if w != nil {
+				if err := w.Flush(); err != nil {
+					return err
+				}
+				if !*all {
+					fmt.Fprintf(c.out, "\ntotal: %d", reply.GetTotal())
+					if reply.GetNextPageToken() != "" {
+						fmt.Fprintf(c.out, "  next page: -page-token %s", reply.GetNextPageToken())
+					}
+					fmt.Fprintln(c.out)
+				}
+			}
+			return nil
+		}
+		in.PageToken = reply.GetNextPageToken()
+	}
+}
+
+// ค้นหาผู้ใช้ตาม ID หรืออีเมล (อีเมลรวมผู้ใช้ที่ถูกลบแล้ว)
+func (c *client) findUser(args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: authctl users find <id|email>")
+	}
+	user, err := c.lookupUser(args[0])
+	if err != nil {
+		return err
+	}
+	return c.printUser(user)
+}
+
+func (c *client) lookupUser(key string) (*pb.UserItem, error) {
+	ctx, cancel := c.context()
+	defer cancel()
+
+	if _, err := primitive.ObjectIDFromHex(key); err == nil {
+		reply, err := c.users.GetUserById(ctx, &pb.UserIdRequest{Id: key})
+		if err != nil {
+			return nil, err
+		}
+		return &pb.UserItem{
+			Id:            reply.GetId(),
+			Email:         reply.GetEmail(),
+			Username:      reply.GetUsername(),
+			CreatedAt:     reply.GetCreatedAt(),
+			Role:          reply.GetRole(),
+			UpdatedAt:     reply.GetUpdatedAt(),
+			DisplayName:   reply.GetDisplayName(),
+			AvatarUrl:     reply.GetAvatarUrl(),
+			Locale:        reply.GetLocale(),
+			Timezone:      reply.GetTimezone(),
+			Phone:         reply.GetPhone(),
+			Metadata:      reply.GetMetadata(),
+			EmailVerified: reply.GetEmailVerified(),
+			TenantId:      reply.GetTenantId(),
+		}, nil
+	}
+
+	reply, err := c.users.ListUsers(ctx, &pb.ListUsersRequest{Email: key, MatchMode: "exact", Deleted: "include", Limit: 1})
+	if err != nil {
+		return nil, err
+	}
+	if len(reply.GetUsers()) == 0 {
+		return nil, fmt.Errorf("user %s not found", key)
+	}
+	return reply.GetUsers()[0], nil
+}
+
+// พิมพ์ข้อมูลผู้ใช้หนึ่งคนแบบ key: value
+func (c *client) printUser(user *pb.UserItem) error {
+	if c.jsonOutput() {
+		return c.printJSON(user, true)
+	}
+	w := c.table("FIELD", "VALUE")
+	row(w, "id", user.GetId())
+	row(w, "tenantId", user.GetTenantId())
+	row(w, "email", user.GetEmail())
+	row(w, "username", user.GetUsername())
+	row(w, "role", user.GetRole())
+	row(w, "emailVerified", user.GetEmailVerified())
+	row(w, "displayName", user.GetDisplayName())
+	row(w, "avatarUrl", user.GetAvatarUrl())
+	row(w, "locale", user.GetLocale())
+	row(w, "timezone", user.GetTimezone())
+	row(w, "phone", user.GetPhone())
+	for key, value := range user.GetMetadata() {
+		row(w, "metadata."+key, value)
+	}
+	row(w, "createdAt", user.GetCreatedAt())
+	row(w, "updatedAt", user.GetUpdatedAt())
+	return w.Flush()
+}
+
+// สร้างผู้ใช้ผ่าน ImportUsers (ตรวจสอบข้อมูลและบันทึก audit log เหมือนการนำเข้า)
+func (c *client) createUser(args []string) error {
+	fs := flag.NewFlagSet("users create", flag.ContinueOnError)
+	email := fs.String("email", "", "email (required)")
+	password := fs.String("password", "", "password (required)")
+	username := fs.String("username", "", "username (default: part of the email before @)")
+	role := fs.String("role", "user", "user, tenant_admin or admin")
+	displayName := fs.String("display-name", "", "display name")
+	verified := fs.Bool("verified", false, "mark the email as verified")
+	if _, err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *email == "" || *password == "" {
+		return errors.New("usage: authctl users create -email e -password p [-username u] [-role r]")
+	}
+	if *username == "" {
+		*username, _, _ = strings.Cut(*email, "@")
+	}
+
+	line, err := json.Marshal(map[string]interface{}{
+		"email":         *email,
+		"username":      *username,
+		"password":      *password,
+		"role":          *role,
+		"displayName":   *displayName,
+		"emailVerified": *verified,
+	})
+	if err != nil {
+		return err
+	}
+	data := append(line, '\n')
+	reply, err := c.importUsers(func() []byte {
+		chunk := data
+		data = nil
+		return chunk
+	})
+	if err != nil {
+		return err
+	}
+	if len(reply.GetErrors()) > 0 {
+		return errors.New(reply.GetErrors()[0].GetMessage())
+	}
+
+	user, err := c.lookupUser(*email)
+	if err != nil {
+		return err
+	}
+	return c.printUser(user)
+}
+
+// ส่งข้อมูล JSONL ทีละส่วนผ่าน ImportUsers (next คืน nil เมื่อหมดข้อมูล)
+func (c *client) importUsers(next func() []byte) (*pb.ImportUsersReply, error) {
+	ctx, cancel := c.streamContext()
+	defer cancel()
+	stream, err := c.users.ImportUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for data := next(); data != nil; data = next() {
+		if err := stream.Send(&pb.ImportUsersRequest{Format: "jsonl", Data: data}); err != nil {
+			break // error จริงได้จาก CloseAndRecv
+		}
+	}
+	return stream.CloseAndRecv()
+}
+
+// ค่าของ -meta key=value ที่ระบุได้หลายครั้ง
+type metadataFlag map[string]string
+
+func (m metadataFlag) String() string {
+	return fmt.Sprint(map[string]string(m))
+}
+
+func (m metadataFlag) Set(value string) error {
+	key, val, ok := strings.Cut(value, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("expected key=value, got %q", value)
+	}
+	m[key] = val
+	return nil
+}
+
+// อัปเดตเฉพาะ field ที่ระบุ flag (ระบุค่าว่างเพื่อล้าง field ได้)
+func (c *client) updateUser(args []string) error {
+	fs := flag.NewFlagSet("users update", flag.ContinueOnError)
+	in := &pb.UpdateUserRequest{}
+	meta := metadataFlag{}
+	fs.StringVar(&in.Username, "username", "", "username")
+	fs.StringVar(&in.DisplayName, "display-name", "", "display name")
+	fs.StringVar(&in.AvatarUrl, "avatar-url", "", "avatar URL")
+	fs.StringVar(&in.Locale, "locale", "", "locale, e.g. th-TH")
+	fs.StringVar(&in.Timezone, "timezone", "", "timezone, e.g. Asia/Bangkok")
+	fs.StringVar(&in.Phone, "phone", "", "phone number in E.164 format")
+	fs.Var(meta, "meta", "metadata key=value (repeatable, empty value removes the key)")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return errors.New("usage: authctl users update <id> [-display-name n] [-meta key=value]...")
+	}
+	in.Id = positional[0]
+
+	fieldNames := map[string]string{
+		"username":     "username",
+		"display-name": "displayName",
+		"avatar-url":   "avatarUrl",
+		"locale":       "locale",
+		"timezone":     "timezone",
+		"phone":        "phone",
+	}
+	mask := &fieldmaskpb.FieldMask{}
+	fs.Visit(func(f *flag.Flag) {
+		if name, ok := fieldNames[f.Name]; ok {
+			mask.Paths = append(mask.Paths, name)
+		}
+	})
+	for key := range meta {
+		mask.Paths = append(mask.Paths, "metadata."+key)
+	}
+	if len(mask.Paths) == 0 {
+		return errors.New("nothing to update")
+	}
+	in.Metadata = meta
+	in.UpdateMask = mask
+
+	ctx, cancel := c.context()
+	defer cancel()
+	reply, err := c.users.UpdateUser(ctx, in)
+	if err != nil {
+		return err
+	}
+	return c.printMessage(reply, reply.GetMessage())
+}
+
+// คำสั่งที่รับ ID ของผู้ใช้อย่างเดียว
+func singleID(name string, args []string) (string, error) {
+	if len(args) != 1 {
+		return "", fmt.Errorf("usage: authctl users %s <id>", name)
+	}
+	return args[0], nil
+}
+
+func (c *client) disableUser(args []string) error {
+	id, err := singleID("disable", args)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := c.context()
+	defer cancel()
+	reply, err := c.users.DeleteUser(ctx, &pb.DeleteUserRequest{Id: id})
+	if err != nil {
+		return err
+	}
+	return c.printMessage(reply, reply.GetMessage())
+}
+
+func (c *client) restoreUser(args []string) error {
+	id, err := singleID("restore", args)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := c.context()
+	defer cancel()
+	reply, err := c.users.RestoreUser(ctx, &pb.RestoreUserRequest{Id: id})
+	if err != nil {
+		return err
+	}
+	return c.printMessage(reply, reply.GetMessage())
+}
+
+func (c *client) purgeUser(args []string) error {
+	fs := flag.NewFlagSet("users purge", flag.ContinueOnError)
+	yes := fs.Bool("yes", false, "confirm permanent deletion")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	id, err := singleID("purge -yes", positional)
+	if err != nil {
+		return err
+	}
+	if !*yes {
+		return errors.New("purge cannot be undone, add -yes to confirm")
+	}
+	ctx, cancel := c.context()
+	defer cancel()
+	reply, err := c.users.PurgeUser(ctx, &pb.PurgeUserRequest{Id: id})
+	if err != nil {
+		return err
+	}
+	return c.printMessage(reply, reply.GetMessage())
+}
+
+func (c *client) setUserRole(args []string) error {
+	if len(args) != 2 {
+		return errors.New("usage: authctl users role <id> <user|tenant_admin|admin>")
+	}
+	ctx, cancel := c.context()
+	defer cancel()
+	reply, err := c.users.SetUserRole(ctx, &pb.SetUserRoleRequest{Id: args[0], Role: args[1]})
+	if err != nil {
+		return err
+	}
+	if c.jsonOutput() {
+		return c.printJSON(reply, true)
+	}
+	fmt.Fprintln(c.out, reply.GetMessage())
+	return c.printUser(reply.GetUser())
+}
+
+func (c *client) revokeUserSessions(args []string) error {
+	id, err := singleID("revoke-sessions", args)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := c.context()
+	defer cancel()
+	reply, err := c.users.RevokeUserSessions(ctx, &pb.RevokeUserSessionsRequest{Id: id})
+	if err != nil {
+		return err
+	}
+	return c.printMessage(reply, fmt.Sprintf("%s (OAuth consents revoked: %d)", reply.GetMessage(), reply.GetRevokedConsents()))
+}
+
+func (c *client) unlockUser(args []string) error {
+	id, err := singleID("unlock", args)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := c.context()
+	defer cancel()
+	reply, err := c.users.UnlockUser(ctx, &pb.UnlockUserRequest{Id: id})
+	if err != nil {
+		return err
+	}
+	return c.printMessage(reply, reply.GetMessage())
+}
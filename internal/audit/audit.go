@@ -0,0 +1,59 @@
+package audit
+
+import (
+	"context"
+	"log"
+	"time"
+
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/store"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Sink รับเหตุการณ์ที่บันทึกลง audit log แล้วไปประมวลผลต่อ (เช่น ส่งไปยัง webhook ของ tenant)
+type Sink interface {
+	Publish(ctx context.Context, ev models.AuditEvent)
+}
+
+// Logger บันทึกเหตุการณ์สำคัญลง audit log ของ backend ที่เลือก
+type Logger struct {
+	Store store.AuditStore
+	Sinks []Sink // ได้รับทุกเหตุการณ์ที่บันทึกสำเร็จ
+}
+
+// สร้างอินสแตนซ์ของ Logger
+func NewLogger(auditStore store.AuditStore) *Logger {
+	return &Logger{Store: auditStore}
+}
+
+// บันทึกเหตุการณ์ ถ้าบันทึกไม่สำเร็จจะแค่ log ไว้ ไม่ทำให้ request ล้มเหลว
+func (l *Logger) Record(ctx context.Context, ev models.AuditEvent) {
+	if ev.CreatedAt.IsZero() {
+		ev.CreatedAt = time.Now()
+	}
+	if ev.TenantID == "" {
+		ev.TenantID = models.DefaultTenantID
+	}
+	// กำหนด ID ก่อนบันทึก เพื่อให้ sink อ้างถึงเหตุการณ์เดียวกันได้
+	if ev.ID.IsZero() {
+		ev.ID = primitive.NewObjectID()
+	}
+	if err := l.Store.InsertEvent(ctx, ev); err != nil {
+		log.Printf("Could not record audit event %s: %v", ev.Action, err)
+		return
+	}
+	for _, sink := range l.Sinks {
+		sink.Publish(ctx, ev)
+	}
+}
+
+// ลบข้อมูลส่วนบุคคล (PII) ของผู้ใช้ออกจาก audit log แต่ยังเก็บเหตุการณ์ไว้
+func (l *Logger) RedactSubject(ctx context.Context, tenantID string, subjectID string, email string) error {
+	return l.Store.RedactSubject(ctx, tenantID, subjectID, email)
+}
+
+// ดึงเหตุการณ์ทั้งหมดที่เกี่ยวกับผู้ใช้ (ตาม ID หรืออีเมลใน tenant) เรียงจากเก่าไปใหม่
+func (l *Logger) FindBySubject(ctx context.Context, tenantID string, subjectID string, email string) ([]models.AuditEvent, error) {
+	return l.Store.FindBySubject(ctx, tenantID, subjectID, email)
+}
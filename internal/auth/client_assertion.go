@@ -0,0 +1,72 @@
+package auth
+
+import (
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ชนิดของ client assertion ตาม RFC 7523 (client ยืนยันตัวตนด้วย JWT ที่เซ็นด้วย private key ของตัวเอง)
+const ClientAssertionType = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+
+// แปลง public key รูปแบบ PEM (RSA หรือ EC)
+func ParsePublicKeyPEM(data string) (interface{}, error) {
+	if key, err := jwt.ParseRSAPublicKeyFromPEM([]byte(data)); err == nil {
+		return key, nil
+	}
+	if key, err := jwt.ParseECPublicKeyFromPEM([]byte(data)); err == nil {
+		return key, nil
+	}
+	return nil, errors.New("public key ต้องเป็น RSA หรือ EC ในรูปแบบ PEM")
+}
+
+// ดึง client ID (iss) ของ client assertion โดยไม่ตรวจสอบลายเซ็น ใช้เพื่อค้นหา public key ของ client เท่านั้น
+func ClientAssertionIssuer(assertion string) (string, error) {
+	claims := jwt.MapClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(assertion, claims); err != nil {
+		return "", err
+	}
+	return claims.GetIssuer()
+}
+
+// ตรวจสอบ client assertion: ลายเซ็นต้องถูกต้องตาม key ที่ keys คืนให้ (ตาม kid ใน header),
+// iss และ sub ต้องเป็น clientID, aud ต้องมีค่าใดค่าหนึ่งใน audiences และต้องมี exp กับ jti
+// คืน jti และเวลาหมดอายุเพื่อใช้กันการนำ assertion กลับมาใช้ซ้ำ
+func VerifyClientAssertion(assertion string, clientID string, audiences []string, keys func(keyID string) (interface{}, error)) (string, time.Time, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(assertion, claims, func(token *jwt.Token) (interface{}, error) {
+		keyID, _ := token.Header["kid"].(string)
+		return keys(keyID)
+	},
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}),
+		jwt.WithIssuer(clientID),
+		jwt.WithSubject(clientID),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	aud, _ := claims.GetAudience()
+	if !containsAny(aud, audiences) {
+		return "", time.Time{}, errors.New("aud ของ client assertion ไม่ถูกต้อง")
+	}
+	jti, _ := claims["jti"].(string)
+	if jti == "" {
+		return "", time.Time{}, errors.New("client assertion ต้องมี jti")
+	}
+	exp, _ := claims.GetExpirationTime()
+	return jti, exp.Time, nil
+}
+
+func containsAny(values []string, wanted []string) bool {
+	for _, v := range values {
+		for _, w := range wanted {
+			if v == w {
+				return true
+			}
+		}
+	}
+	return false
+}
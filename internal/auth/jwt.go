@@ -7,30 +7,96 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
-// secret key สำหรับใช้เซ็นและตรวจสอบ JWT token
-var JwtSecret = []byte("secret-key") // ใช้ตัวนี้ที่เดียวพอ
+// ชื่อ claim ที่เก็บ ID ของ tenant
+const TenantClaim = "tid"
 
-// สร้าง JWT token
-func GenerateJWT(email string, role string) (string, error) {
+// ชื่อ claim ที่เก็บกลุ่มของผู้ใช้ (ไม่มี claim นี้ = ต้องดึงจาก GroupService เพราะกลุ่มเยอะเกินไป)
+const GroupsClaim = "groups"
+
+// ชื่อ claim ของ token ที่ออกให้ client ของ OAuth2 (service account หรือแอปที่ผู้ใช้อนุญาต)
+const (
+	ClientIDClaim = "client_id" // client ID ของ service account หรือ OAuth client
+	ScopeClaim    = "scope"     // scope ที่ได้รับ คั่นด้วยช่องว่าง
+)
+
+// กลุ่มของผู้ใช้ใน token
+type GroupClaim struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Role string `json:"role"`
+}
+
+// ค้นหา secret ของ tenant ตาม kid ใน header ของ token ("" = token ที่ไม่มี kid)
+type KeyLookup func(tenantID string, keyID string) ([]byte, error)
+
+// สร้าง JWT token ของผู้ใช้ใน tenant โดยเซ็นด้วย key ที่กำหนด (keyID ว่าง = ไม่ใส่ kid)
+// groups เป็น nil = ไม่ใส่ claim กลุ่ม
+// jti ทำให้ token ที่ออกในวินาทีเดียวกันไม่ซ้ำกับ token เดิมที่เพิ่งถูกบล็อกตอนเข้าสู่ระบบใหม่
+func GenerateJWT(email string, role string, tenantID string, jti string, keyID string, secret []byte, ttl time.Duration, groups []GroupClaim) (string, error) {
 
 	// สร้าง claims สำหรับใส่ข้อมูลใน token
 	claims := jwt.MapClaims{
-		"email": email,
-		"role":  role,
-		"exp":   time.Now().Add(time.Minute * 5).Unix(),
+		"email":     email,
+		"role":      role,
+		TenantClaim: tenantID,
+		"jti":       jti,
+		"exp":       time.Now().Add(ttl).Unix(),
 	}
+	if groups != nil {
+		claims[GroupsClaim] = groups
+	}
+
+	return signToken(claims, keyID, secret)
+}
 
+// สร้าง JWT token ของ service account ใน tenant โดยเซ็นด้วย key เดียวกับ token ของผู้ใช้
+// token ไม่มี claim email แต่มี client_id และ scope (คั่นด้วยช่องว่าง)
+func GenerateClientJWT(clientID string, role string, tenantID string, scope string, keyID string, secret []byte, ttl time.Duration) (string, error) {
+	claims := jwt.MapClaims{
+		"sub":         clientID,
+		ClientIDClaim: clientID,
+		"role":        role,
+		TenantClaim:   tenantID,
+		ScopeClaim:    scope,
+		"exp":         time.Now().Add(ttl).Unix(),
+	}
+	return signToken(claims, keyID, secret)
+}
+
+// สร้าง access token ที่ผู้ใช้อนุญาตให้ client ใช้แทน (OAuth2 authorization code flow)
+// มีข้อมูลผู้ใช้เหมือน token จาก Login แต่เรียก RPC ได้เฉพาะที่อยู่ใน scope
+// jti ทำให้ token ที่ออกในวินาทีเดียวกันไม่ซ้ำกัน (ยกเลิกทีละ token ได้)
+func GenerateDelegatedJWT(userID string, email string, role string, tenantID string, clientID string, scope string, jti string, keyID string, secret []byte, ttl time.Duration) (string, error) {
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"sub":         userID,
+		"email":       email,
+		"role":        role,
+		TenantClaim:   tenantID,
+		ClientIDClaim: clientID,
+		ScopeClaim:    scope,
+		"jti":         jti,
+		"iat":         now.Unix(),
+		"exp":         now.Add(ttl).Unix(),
+	}
+	return signToken(claims, keyID, secret)
+}
+
+// เซ็น claims ด้วย HS256 (keyID ว่าง = ไม่ใส่ kid)
+func signToken(claims jwt.MapClaims, keyID string, secret []byte) (string, error) {
 	//// สร้าง token ใหม่โดยใช้ HS256
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(JwtSecret)
+	if keyID != "" {
+		token.Header["kid"] = keyID
+	}
+	return token.SignedString(secret)
 }
 
-// ตรวจสอบและดึงเวลาหมดอายุของ token
+// ดึงเวลาหมดอายุของ token โดยไม่ตรวจสอบลายเซ็น
+// ใช้กับ token ที่ผ่านการตรวจสอบหรือถูกเก็บไว้โดย service แล้วเท่านั้น
 func GetTokenExpiration(tokenString string) (time.Time, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		return JwtSecret, nil
-	})
-	if err != nil || !token.Valid {
+	token, _, err := jwt.NewParser().ParseUnverified(tokenString, jwt.MapClaims{})
+	if err != nil {
 		return time.Time{}, err
 	}
 
@@ -45,18 +111,33 @@ func GetTokenExpiration(tokenString string) (time.Time, error) {
 }
 
 // แปลง token string เป็น claims map[string]interface{} เพื่อดึงข้อมูลใน token
-func ParseToken(tokenStr string) (map[string]interface{}, error) {
-	// แปลง token string และตรวจสอบความถูกต้อง
+// key สำหรับตรวจสอบลายเซ็นเลือกจาก tenant ใน claims และ kid ใน header
+// token เดิมที่ไม่มี tenant ถือว่าเป็นของ defaultTenant
+func ParseToken(tokenStr string, defaultTenant string, keys KeyLookup) (map[string]interface{}, error) {
+	// แปลง token string และตรวจสอบความถูกต้อง (รับเฉพาะ HS256)
 	token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
-		return JwtSecret, nil
-	})
+		claims, ok := token.Claims.(jwt.MapClaims)
+		if !ok {
+			return nil, errors.New("ไม่สามารถแปลง claims เป็น map ได้")
+		}
+		tenantID, _ := claims[TenantClaim].(string)
+		if tenantID == "" {
+			tenantID = defaultTenant
+		}
+		keyID, _ := token.Header["kid"].(string)
+		return keys(tenantID, keyID)
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
 	if err != nil || !token.Valid {
 		return nil, err
 	}
 
 	// แปลง claims เป็น map และคืนค่า
-	if claims, ok := token.Claims.(jwt.MapClaims); ok {
-		return claims, nil
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, errors.New("ไม่สามารถแปลง claims เป็น map ได้")
+	}
+	if tenantID, _ := claims[TenantClaim].(string); tenantID == "" {
+		claims[TenantClaim] = defaultTenant
 	}
-	return nil, errors.New("ไม่สามารถแปลง claims เป็น map ได้")
+	return claims, nil
 }
@@ -0,0 +1,61 @@
+package auth
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// ดึง JWT token จาก metadata "authorization: Bearer <token>" ของ gRPC request
+func TokenFromContext(ctx context.Context) (string, error) {
+	// ดึง metadata จาก context
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", status.Error(codes.Unauthenticated, "missing metadata")
+	}
+
+	// ดึงค่า authorization header
+	authHeaders := md["authorization"]
+	if len(authHeaders) == 0 {
+		return "", status.Error(codes.Unauthenticated, "authorization token is not supplied")
+	}
+
+	// ดึง token จาก "Bearer <token>"
+	tokenStr := strings.TrimPrefix(authHeaders[0], "Bearer ")
+	if tokenStr == "" {
+		return "", status.Error(codes.Unauthenticated, "authorization token is empty")
+	}
+
+	return tokenStr, nil
+}
+
+// ดึง API key จาก metadata "authorization: ApiKey <key>" (false ถ้า request ไม่ได้ใช้ API key)
+func APIKeyFromContext(ctx context.Context) (string, bool) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", false
+	}
+	authHeaders := md["authorization"]
+	if len(authHeaders) == 0 || !strings.HasPrefix(authHeaders[0], "ApiKey ") {
+		return "", false
+	}
+	return strings.TrimPrefix(authHeaders[0], "ApiKey "), true
+}
+
+// ชื่อ metadata ที่ client ใช้ระบุ tenant เช่น "x-tenant-id: shop"
+const TenantMetadataKey = "x-tenant-id"
+
+// ดึง ID ของ tenant ที่ client ระบุใน metadata ("" ถ้าไม่ได้ระบุ)
+func TenantFromContext(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if values := md.Get(TenantMetadataKey); len(values) > 0 {
+		return strings.TrimSpace(values[0])
+	}
+	return ""
+}
@@ -0,0 +1,128 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"math"
+	"math/big"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// สร้าง RSA private key สำหรับเซ็น ID token คืนเป็น PKCS#8 PEM
+func GenerateOIDCKey() (string, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return "", err
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return "", err
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})), nil
+}
+
+// แปลง RSA private key รูปแบบ PEM
+func ParseOIDCKey(data string) (*rsa.PrivateKey, error) {
+	key, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(data))
+	if err != nil {
+		return nil, errors.New("signing key ของ OpenID Connect ต้องเป็น RSA private key ในรูปแบบ PEM")
+	}
+	return key, nil
+}
+
+// เซ็น ID token ด้วย RS256 (relying party ตรวจสอบด้วย public key จาก JWKS)
+func SignIDToken(claims map[string]interface{}, keyID string, key *rsa.PrivateKey) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims(claims))
+	token.Header["kid"] = keyID
+	return token.SignedString(key)
+}
+
+// public key ในรูปแบบ JWK (RFC 7517) สำหรับ endpoint JWKS
+func PublicJWK(keyID string, key *rsa.PublicKey) map[string]interface{} {
+	return map[string]interface{}{
+		"kty": "RSA",
+		"use": "sig",
+		"alg": jwt.SigningMethodRS256.Alg(),
+		"kid": keyID,
+		"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+// ค่า at_hash ของ ID token: ครึ่งซ้ายของ SHA-256 ของ access token (OpenID Connect Core ข้อ 3.1.3.6)
+func AccessTokenHash(accessToken string) string {
+	sum := sha256.Sum256([]byte(accessToken))
+	return base64.RawURLEncoding.EncodeToString(sum[:len(sum)/2])
+}
+
+// แปลง public key ในรูปแบบ JWK (RSA หรือ EC) จาก JWKS ของ provider ภายนอก
+func ParsePublicJWK(jwk map[string]interface{}) (interface{}, error) {
+	field := func(name string) (*big.Int, error) {
+		value, _ := jwk[name].(string)
+		data, err := base64.RawURLEncoding.DecodeString(value)
+		if err != nil || len(data) == 0 {
+			return nil, errors.New("JWK ไม่มีค่า " + name)
+		}
+		return new(big.Int).SetBytes(data), nil
+	}
+
+	switch jwk["kty"] {
+	case "RSA":
+		n, err := field("n")
+		if err != nil {
+			return nil, err
+		}
+		e, err := field("e")
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() > math.MaxInt32 {
+			return nil, errors.New("JWK มีค่า e ไม่ถูกต้อง")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk["crv"] {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, errors.New("ไม่รองรับ curve ของ JWK")
+		}
+		x, err := field("x")
+		if err != nil {
+			return nil, err
+		}
+		y, err := field("y")
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("JWK มีจุดที่ไม่อยู่บน curve")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+	return nil, errors.New("ไม่รองรับชนิดของ JWK")
+}
+
+// code_challenge แบบ S256 ของ code_verifier (RFC 7636)
+func PKCEChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// ตรวจสอบ code_verifier ของ PKCE กับ code_challenge แบบ S256
+func VerifyPKCE(verifier string, challenge string) bool {
+	return PKCEChallenge(verifier) == challenge
+}
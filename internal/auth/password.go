@@ -0,0 +1,115 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ขอบเขตของพารามิเตอร์ argon2 ที่ยอมรับ (กัน hash ที่ใช้หน่วยความจำหรือเวลามากเกินไปตอนเข้าสู่ระบบ)
+const (
+	maxArgon2Memory  = 256 * 1024 // KiB
+	maxArgon2Time    = 10
+	minArgon2SaltLen = 8
+	minArgon2KeyLen  = 16
+	maxArgon2KeyLen  = 64
+)
+
+var (
+	// รหัสผ่านไม่ตรงกับ hash
+	ErrPasswordMismatch = errors.New("auth: password does not match")
+	// hash ไม่ใช่ bcrypt หรือ argon2 ในรูปแบบที่รองรับ
+	ErrUnsupportedHash = errors.New("auth: unsupported password hash")
+)
+
+// ตรวจรหัสผ่านกับ hash ที่เก็บไว้ คืน nil ถ้าตรงกัน
+// รองรับ bcrypt ที่ service นี้สร้าง และ argon2id / argon2i ในรูปแบบ PHC
+// ($argon2id$v=19$m=65536,t=3,p=4$<salt>$<hash> แบบ base64 ไม่มี padding) ที่นำเข้าจากระบบเดิม
+func ComparePassword(hash string, password string) error {
+	if strings.HasPrefix(hash, "$argon2") {
+		params, err := parseArgon2Hash(hash)
+		if err != nil {
+			return err
+		}
+		if subtle.ConstantTimeCompare(params.derive(password), params.key) != 1 {
+			return ErrPasswordMismatch
+		}
+		return nil
+	}
+	if err := CheckPasswordHash(hash); err != nil {
+		return err
+	}
+	if bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) != nil {
+		return ErrPasswordMismatch
+	}
+	return nil
+}
+
+// ตรวจรูปแบบของ hash รหัสผ่าน (ไม่ตรวจรหัสผ่าน)
+func CheckPasswordHash(hash string) error {
+	if strings.HasPrefix(hash, "$argon2") {
+		_, err := parseArgon2Hash(hash)
+		return err
+	}
+	// bcrypt: $2a$, $2b$ หรือ $2y$ ตามด้วย cost และ salt+hash 53 ตัวอักษร
+	if len(hash) != 60 || !(strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$")) {
+		return ErrUnsupportedHash
+	}
+	if _, err := bcrypt.Cost([]byte(hash)); err != nil {
+		return ErrUnsupportedHash
+	}
+	return nil
+}
+
+// พารามิเตอร์ของ hash argon2
+type argon2Params struct {
+	variant string // "argon2id" หรือ "argon2i"
+	memory  uint32
+	time    uint32
+	threads uint8
+	salt    []byte
+	key     []byte
+}
+
+func (p argon2Params) derive(password string) []byte {
+	if p.variant == "argon2i" {
+		return argon2.Key([]byte(password), p.salt, p.time, p.memory, p.threads, uint32(len(p.key)))
+	}
+	return argon2.IDKey([]byte(password), p.salt, p.time, p.memory, p.threads, uint32(len(p.key)))
+}
+
+func parseArgon2Hash(hash string) (argon2Params, error) {
+	var p argon2Params
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[0] != "" || (parts[1] != "argon2id" && parts[1] != "argon2i") {
+		return p, ErrUnsupportedHash
+	}
+	p.variant = parts[1]
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return p, ErrUnsupportedHash
+	}
+	var threads uint32
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.memory, &p.time, &threads); err != nil {
+		return p, ErrUnsupportedHash
+	}
+	if p.memory == 0 || p.memory > maxArgon2Memory || p.time == 0 || p.time > maxArgon2Time || threads == 0 || threads > 255 {
+		return p, ErrUnsupportedHash
+	}
+	p.threads = uint8(threads)
+
+	var err error
+	if p.salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil || len(p.salt) < minArgon2SaltLen {
+		return p, ErrUnsupportedHash
+	}
+	if p.key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil || len(p.key) < minArgon2KeyLen || len(p.key) > maxArgon2KeyLen {
+		return p, ErrUnsupportedHash
+	}
+	return p, nil
+}
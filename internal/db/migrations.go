@@ -0,0 +1,492 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"auth-microservice/internal/migrate"
+	models "auth-microservice/internal/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	duplicateReportLimit = 20 // จำนวนค่าที่ซ้ำกันสูงสุดที่แสดงใน error ของ migration
+	duplicateReportIDs   = 5  // จำนวน ID สูงสุดที่แสดงต่อหนึ่งค่า
+)
+
+// collation แบบไม่สนตัวพิมพ์เล็ก/ใหญ่ ต้องตรงกับ collation ของ index จึงจะใช้ index ได้
+var CaseInsensitive = &options.Collation{Locale: "en", Strength: 2}
+
+// รายการ migration ทั้งหมดของฐานข้อมูล (เพิ่มเวอร์ชันใหม่ต่อท้ายเสมอ ห้ามแก้เวอร์ชันที่ปล่อยไปแล้ว)
+func Migrations(db *mongo.Database) []migrate.Migration {
+	return []migrate.Migration{
+		{
+			Version: 1,
+			Name:    "users_unique_email_username",
+			Up: func(ctx context.Context) error {
+				users := db.Collection("users")
+				// index แบบไม่ unique ที่เคยสร้างตอน startup ใช้ชื่อเดียวกัน ต้องลบก่อน
+				// ข้อมูลเดิมอาจมีอีเมลหรือ username ซ้ำกัน (ต่างกันแค่ตัวพิมพ์) ต้องให้ผู้ดูแลแก้ก่อน ไม่เช่นนั้นสร้าง unique index ไม่ได้
+				for _, field := range []string{"email", "username"} {
+					if err := checkDuplicates(ctx, users, field); err != nil {
+						return err
+					}
+				}
+				for _, name := range []string{"email_1", "username_1"} {
+					if err := dropIndexIfExists(ctx, users, name); err != nil {
+						return err
+					}
+				}
+				unique := options.Index().SetUnique(true).SetCollation(CaseInsensitive)
+				_, err := users.Indexes().CreateMany(ctx, []mongo.IndexModel{
+					{Keys: bson.D{{Key: "email", Value: 1}}, Options: unique},
+					{Keys: bson.D{{Key: "username", Value: 1}}, Options: unique},
+				})
+				return err
+			},
+			Down: func(ctx context.Context) error {
+				users := db.Collection("users")
+				for _, name := range []string{"email_1", "username_1"} {
+					if err := dropIndexIfExists(ctx, users, name); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			Version: 2,
+			Name:    "blacklisted_tokens_ttl",
+			Up: func(ctx context.Context) error {
+				// MongoDB ลบ token ที่หมดอายุแล้วออกจาก blacklist ให้อัตโนมัติ
+				_, err := db.Collection("blacklisted_tokens").Indexes().CreateMany(ctx, []mongo.IndexModel{
+					{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+					{Keys: bson.D{{Key: "token", Value: 1}}},
+				})
+				return err
+			},
+			Down: func(ctx context.Context) error {
+				blacklist := db.Collection("blacklisted_tokens")
+				for _, name := range []string{"expires_at_1", "token_1"} {
+					if err := dropIndexIfExists(ctx, blacklist, name); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			Version: 3,
+			Name:    "query_indexes",
+			Up: func(ctx context.Context) error {
+				_, err := db.Collection("users").Indexes().CreateMany(ctx, []mongo.IndexModel{
+					{Keys: bson.D{{Key: "role", Value: 1}, {Key: "createdAt", Value: 1}}},
+					{Keys: bson.D{{Key: "deleted", Value: 1}, {Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}}},
+					{Keys: bson.D{{Key: "deleted", Value: 1}, {Key: "deletedAt", Value: 1}}},
+				})
+				if err != nil {
+					return err
+				}
+				_, err = db.Collection("audit_logs").Indexes().CreateMany(ctx, []mongo.IndexModel{
+					{Keys: bson.D{{Key: "subjectId", Value: 1}, {Key: "createdAt", Value: 1}}},
+					{Keys: bson.D{{Key: "subjectEmail", Value: 1}}},
+					{Keys: bson.D{{Key: "actorEmail", Value: 1}}},
+				})
+				return err
+			},
+			Down: func(ctx context.Context) error {
+				for col, names := range map[string][]string{
+					"users":      {"role_1_createdAt_1", "deleted_1_createdAt_1__id_1", "deleted_1_deletedAt_1"},
+					"audit_logs": {"subjectId_1_createdAt_1", "subjectEmail_1", "actorEmail_1"},
+				} {
+					for _, name := range names {
+						if err := dropIndexIfExists(ctx, db.Collection(col), name); err != nil {
+							return err
+						}
+					}
+				}
+				return nil
+			},
+		},
+		{
+			Version: 4,
+			Name:    "tenant_scoped_users",
+			Up: func(ctx context.Context) error {
+				// ข้อมูลเดิมทั้งหมดเป็นของ tenant default
+				for _, col := range []string{"users", "audit_logs"} {
+					_, err := db.Collection(col).UpdateMany(ctx,
+						bson.M{"tenantId": bson.M{"$exists": false}},
+						bson.M{"$set": bson.M{"tenantId": models.DefaultTenantID}})
+					if err != nil {
+						return err
+					}
+				}
+
+				// อีเมลและ username ไม่ซ้ำกันภายใน tenant แทนทั้งระบบ
+				users := db.Collection("users")
+				for _, field := range []string{"email", "username"} {
+					if err := checkDuplicates(ctx, users, "tenantId", field); err != nil {
+						return err
+					}
+				}
+				for _, name := range []string{"email_1", "username_1"} {
+					if err := dropIndexIfExists(ctx, users, name); err != nil {
+						return err
+					}
+				}
+				unique := options.Index().SetUnique(true).SetCollation(CaseInsensitive)
+				_, err := users.Indexes().CreateMany(ctx, []mongo.IndexModel{
+					{Keys: bson.D{{Key: "tenantId", Value: 1}, {Key: "email", Value: 1}}, Options: unique},
+					{Keys: bson.D{{Key: "tenantId", Value: 1}, {Key: "username", Value: 1}}, Options: unique},
+					{Keys: bson.D{{Key: "tenantId", Value: 1}, {Key: "deleted", Value: 1}, {Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}}},
+				})
+				if err != nil {
+					return err
+				}
+				_, err = db.Collection("audit_logs").Indexes().CreateOne(ctx, mongo.IndexModel{
+					Keys: bson.D{{Key: "tenantId", Value: 1}, {Key: "subjectEmail", Value: 1}},
+				})
+				return err
+			},
+			Down: func(ctx context.Context) error {
+				// ย้อนกลับได้เฉพาะเมื่ออีเมลและ username ยังไม่ซ้ำกันข้าม tenant
+				users := db.Collection("users")
+				for _, field := range []string{"email", "username"} {
+					if err := checkDuplicates(ctx, users, field); err != nil {
+						return err
+					}
+				}
+				for _, name := range []string{"tenantId_1_email_1", "tenantId_1_username_1", "tenantId_1_deleted_1_createdAt_1__id_1"} {
+					if err := dropIndexIfExists(ctx, users, name); err != nil {
+						return err
+					}
+				}
+				if err := dropIndexIfExists(ctx, db.Collection("audit_logs"), "tenantId_1_subjectEmail_1"); err != nil {
+					return err
+				}
+				unique := options.Index().SetUnique(true).SetCollation(CaseInsensitive)
+				_, err := users.Indexes().CreateMany(ctx, []mongo.IndexModel{
+					{Keys: bson.D{{Key: "email", Value: 1}}, Options: unique},
+					{Keys: bson.D{{Key: "username", Value: 1}}, Options: unique},
+				})
+				return err
+			},
+		},
+		{
+			Version: 5,
+			Name:    "groups",
+			Up: func(ctx context.Context) error {
+				// ชื่อกลุ่มไม่ซ้ำกันภายใน tenant
+				_, err := db.Collection("groups").Indexes().CreateOne(ctx, mongo.IndexModel{
+					Keys:    bson.D{{Key: "tenantId", Value: 1}, {Key: "name", Value: 1}},
+					Options: options.Index().SetUnique(true).SetCollation(CaseInsensitive),
+				})
+				if err != nil {
+					return err
+				}
+				// สมาชิกของกลุ่มเก็บเป็น array groups ใน document ของผู้ใช้
+				_, err = db.Collection("users").Indexes().CreateOne(ctx, mongo.IndexModel{
+					Keys: bson.D{{Key: "tenantId", Value: 1}, {Key: "groups.groupId", Value: 1}},
+				})
+				return err
+			},
+			Down: func(ctx context.Context) error {
+				if err := dropIndexIfExists(ctx, db.Collection("users"), "tenantId_1_groups.groupId_1"); err != nil {
+					return err
+				}
+				return db.Collection("groups").Drop(ctx)
+			},
+		},
+		{
+			Version: 6,
+			Name:    "api_keys",
+			Up: func(ctx context.Context) error {
+				// ค้นหา key ตอนยืนยันตัวตนด้วย hash
+				_, err := db.Collection("api_keys").Indexes().CreateMany(ctx, []mongo.IndexModel{
+					{Keys: bson.D{{Key: "hash", Value: 1}}, Options: options.Index().SetUnique(true)},
+					{Keys: bson.D{{Key: "tenantId", Value: 1}, {Key: "userId", Value: 1}, {Key: "createdAt", Value: -1}}},
+				})
+				return err
+			},
+			Down: func(ctx context.Context) error {
+				return db.Collection("api_keys").Drop(ctx)
+			},
+		},
+		{
+			Version: 7,
+			Name:    "service_accounts",
+			Up: func(ctx context.Context) error {
+				// client ID ไม่ซ้ำกันทั้งระบบ ส่วนชื่อไม่ซ้ำกันภายใน tenant
+				_, err := db.Collection("service_accounts").Indexes().CreateMany(ctx, []mongo.IndexModel{
+					{Keys: bson.D{{Key: "clientId", Value: 1}}, Options: options.Index().SetUnique(true)},
+					{Keys: bson.D{{Key: "tenantId", Value: 1}, {Key: "name", Value: 1}}, Options: options.Index().SetUnique(true).SetCollation(CaseInsensitive)},
+				})
+				return err
+			},
+			Down: func(ctx context.Context) error {
+				return db.Collection("service_accounts").Drop(ctx)
+			},
+		},
+		{
+			Version: 8,
+			Name:    "oauth_clients",
+			Up: func(ctx context.Context) error {
+				// client ID ไม่ซ้ำกันทั้งระบบ ชื่อไม่ซ้ำกันภายใน tenant และความยินยอมมีได้หนึ่งรายการต่อผู้ใช้ต่อ client
+				_, err := db.Collection("oauth_clients").Indexes().CreateMany(ctx, []mongo.IndexModel{
+					{Keys: bson.D{{Key: "clientId", Value: 1}}, Options: options.Index().SetUnique(true)},
+					{Keys: bson.D{{Key: "tenantId", Value: 1}, {Key: "name", Value: 1}}, Options: options.Index().SetUnique(true).SetCollation(CaseInsensitive)},
+				})
+				if err != nil {
+					return err
+				}
+				_, err = db.Collection("oauth_consents").Indexes().CreateOne(ctx, mongo.IndexModel{
+					Keys:    bson.D{{Key: "tenantId", Value: 1}, {Key: "userId", Value: 1}, {Key: "clientId", Value: 1}},
+					Options: options.Index().SetUnique(true),
+				})
+				return err
+			},
+			Down: func(ctx context.Context) error {
+				if err := db.Collection("oauth_consents").Drop(ctx); err != nil {
+					return err
+				}
+				return db.Collection("oauth_clients").Drop(ctx)
+			},
+		},
+		{
+			Version: 9,
+			Name:    "identity_providers",
+			Up: func(ctx context.Context) error {
+				// ชื่อ provider ไม่ซ้ำกันภายใน tenant, บัญชีภายนอกหนึ่งบัญชีผูกกับผู้ใช้ได้คนเดียว
+				// และผู้ใช้ผูกได้หนึ่งบัญชีต่อ provider
+				_, err := db.Collection("identity_providers").Indexes().CreateOne(ctx, mongo.IndexModel{
+					Keys:    bson.D{{Key: "tenantId", Value: 1}, {Key: "name", Value: 1}},
+					Options: options.Index().SetUnique(true).SetCollation(CaseInsensitive),
+				})
+				if err != nil {
+					return err
+				}
+				_, err = db.Collection("linked_identities").Indexes().CreateMany(ctx, []mongo.IndexModel{
+					{Keys: bson.D{{Key: "providerId", Value: 1}, {Key: "subject", Value: 1}}, Options: options.Index().SetUnique(true)},
+					{Keys: bson.D{{Key: "tenantId", Value: 1}, {Key: "userId", Value: 1}, {Key: "providerId", Value: 1}}, Options: options.Index().SetUnique(true)},
+				})
+				return err
+			},
+			Down: func(ctx context.Context) error {
+				if err := db.Collection("linked_identities").Drop(ctx); err != nil {
+					return err
+				}
+				return db.Collection("identity_providers").Drop(ctx)
+			},
+		},
+		{
+			Version: 10,
+			Name:    "directories",
+			Up: func(ctx context.Context) error {
+				// ชื่อ directory ไม่ซ้ำกันภายใน tenant
+				_, err := db.Collection("directories").Indexes().CreateOne(ctx, mongo.IndexModel{
+					Keys:    bson.D{{Key: "tenantId", Value: 1}, {Key: "name", Value: 1}},
+					Options: options.Index().SetUnique(true).SetCollation(CaseInsensitive),
+				})
+				return err
+			},
+			Down: func(ctx context.Context) error {
+				return db.Collection("directories").Drop(ctx)
+			},
+		},
+		{
+			Version: 11,
+			Name:    "passkeys",
+			Up: func(ctx context.Context) error {
+				// credential หนึ่งตัวลงทะเบียนได้ครั้งเดียวใน tenant และค้นหา passkey ของผู้ใช้
+				_, err := db.Collection("passkeys").Indexes().CreateMany(ctx, []mongo.IndexModel{
+					{Keys: bson.D{{Key: "tenantId", Value: 1}, {Key: "credentialId", Value: 1}}, Options: options.Index().SetUnique(true)},
+					{Keys: bson.D{{Key: "tenantId", Value: 1}, {Key: "userId", Value: 1}}},
+				})
+				return err
+			},
+			Down: func(ctx context.Context) error {
+				return db.Collection("passkeys").Drop(ctx)
+			},
+		},
+		{
+			Version: 12,
+			Name:    "webhooks",
+			Up: func(ctx context.Context) error {
+				if _, err := db.Collection("webhooks").Indexes().CreateOne(ctx, mongo.IndexModel{
+					Keys: bson.D{{Key: "tenantId", Value: 1}, {Key: "createdAt", Value: 1}},
+				}); err != nil {
+					return err
+				}
+				// worker ค้นหารายการที่ถึงเวลาส่ง และ admin ดูประวัติการส่งของ tenant
+				_, err := db.Collection("webhook_deliveries").Indexes().CreateMany(ctx, []mongo.IndexModel{
+					{Keys: bson.D{{Key: "status", Value: 1}, {Key: "nextAttemptAt", Value: 1}}},
+					{Keys: bson.D{{Key: "tenantId", Value: 1}, {Key: "_id", Value: -1}}},
+					{Keys: bson.D{{Key: "webhookId", Value: 1}}},
+				})
+				return err
+			},
+			Down: func(ctx context.Context) error {
+				if err := db.Collection("webhook_deliveries").Drop(ctx); err != nil {
+					return err
+				}
+				return db.Collection("webhooks").Drop(ctx)
+			},
+		},
+		{
+			Version: 13,
+			Name:    "outbox",
+			Up: func(ctx context.Context) error {
+				// relay ค้นหา event ที่ยังไม่เผยแพร่และถึงเวลาแล้ว
+				_, err := db.Collection("outbox").Indexes().CreateOne(ctx, mongo.IndexModel{
+					Keys: bson.D{{Key: "publishedAt", Value: 1}, {Key: "nextAttemptAt", Value: 1}},
+				})
+				return err
+			},
+			Down: func(ctx context.Context) error {
+				return db.Collection("outbox").Drop(ctx)
+			},
+		},
+		{
+			Version: 14,
+			Name:    "outbox tenant index",
+			Up: func(ctx context.Context) error {
+				// WatchUsers อ่าน event ของ tenant ตามลำดับ
+				_, err := db.Collection("outbox").Indexes().CreateOne(ctx, mongo.IndexModel{
+					Keys: bson.D{{Key: "tenantId", Value: 1}, {Key: "_id", Value: 1}},
+				})
+				return err
+			},
+			Down: func(ctx context.Context) error {
+				return dropIndexIfExists(ctx, db.Collection("outbox"), "tenantId_1__id_1")
+			},
+		},
+		{
+			Version: 15,
+			Name:    "users_change_stream_pre_images",
+			Up: func(ctx context.Context) error {
+				// change stream ของ WatchUsers ใช้ pre-image หา tenant ของผู้ใช้ที่ถูกลบถาวร (MongoDB 6.0 ขึ้นไป)
+				return collModIfSupported(ctx, db, bson.D{
+					{Key: "collMod", Value: "users"},
+					{Key: "changeStreamPreAndPostImages", Value: bson.M{"enabled": true}},
+				})
+			},
+			Down: func(ctx context.Context) error {
+				return collModIfSupported(ctx, db, bson.D{
+					{Key: "collMod", Value: "users"},
+					{Key: "changeStreamPreAndPostImages", Value: bson.M{"enabled": false}},
+				})
+			},
+		},
+		{
+			Version: 16,
+			Name:    "audit tenant index",
+			Up: func(ctx context.Context) error {
+				// ListAuditEvents อ่านเหตุการณ์ของ tenant ตามลำดับ
+				_, err := db.Collection("audit_logs").Indexes().CreateOne(ctx, mongo.IndexModel{
+					Keys: bson.D{{Key: "tenantId", Value: 1}, {Key: "_id", Value: 1}},
+				})
+				return err
+			},
+			Down: func(ctx context.Context) error {
+				return dropIndexIfExists(ctx, db.Collection("audit_logs"), "tenantId_1__id_1")
+			},
+		},
+	}
+}
+
+// สร้าง Migrator สำหรับฐานข้อมูลของ service
+func NewMigrator(client *mongo.Client) *migrate.Migrator {
+	database := client.Database(DBName)
+	return migrate.NewMigrator(migrate.NewMongoJournal(database), Migrations(database))
+}
+
+// หาค่าที่ซ้ำกันของ fields (เทียบแบบไม่สนตัวพิมพ์เหมือน unique index) แล้วคืน error ที่บอกค่าและ ID ของผู้ใช้ที่ชนกัน
+func checkDuplicates(ctx context.Context, col *mongo.Collection, fields ...string) error {
+	key := bson.D{}
+	for _, field := range fields {
+		key = append(key, bson.E{Key: field, Value: "$" + field})
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: key},
+			{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}},
+			{Key: "ids", Value: bson.D{{Key: "$push", Value: "$_id"}}},
+		}}},
+		{{Key: "$match", Value: bson.D{{Key: "count", Value: bson.D{{Key: "$gt", Value: 1}}}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "count", Value: -1}}}},
+		{{Key: "$limit", Value: duplicateReportLimit}},
+		{{Key: "$project", Value: bson.D{
+			{Key: "count", Value: 1},
+			{Key: "ids", Value: bson.D{{Key: "$slice", Value: bson.A{"$ids", duplicateReportIDs}}}},
+		}}},
+	}
+	opts := options.Aggregate().SetCollation(CaseInsensitive).SetAllowDiskUse(true)
+	cursor, err := col.Aggregate(ctx, pipeline, opts)
+	if err != nil {
+		return err
+	}
+	var groups []struct {
+		Key   bson.M        `bson:"_id"`
+		Count int           `bson:"count"`
+		IDs   []interface{} `bson:"ids"`
+	}
+	if err := cursor.All(ctx, &groups); err != nil {
+		return err
+	}
+	if len(groups) == 0 {
+		return nil
+	}
+
+	lines := make([]string, 0, len(groups))
+	for _, g := range groups {
+		values := make([]string, 0, len(fields))
+		for _, field := range fields {
+			values = append(values, fmt.Sprintf("%s=%v", field, g.Key[field]))
+		}
+		ids := make([]string, 0, len(g.IDs))
+		for _, id := range g.IDs {
+			if oid, ok := id.(primitive.ObjectID); ok {
+				ids = append(ids, oid.Hex())
+			} else {
+				ids = append(ids, fmt.Sprint(id))
+			}
+		}
+		if g.Count > len(ids) {
+			ids = append(ids, "...")
+		}
+		lines = append(lines, fmt.Sprintf("%s: %d documents (%s)", strings.Join(values, " "), g.Count, strings.Join(ids, ", ")))
+	}
+	more := ""
+	if len(groups) == duplicateReportLimit {
+		more = fmt.Sprintf("\n  (showing the first %d conflicts)", duplicateReportLimit)
+	}
+	return fmt.Errorf("cannot create a unique index on %s(%s): values must be unique ignoring case; "+
+		"change or delete the conflicting documents below and run the migration again:\n  %s%s",
+		col.Name(), strings.Join(fields, ", "), strings.Join(lines, "\n  "), more)
+}
+
+// ลบ index ถ้ามีอยู่ (ไม่ error ถ้าไม่มี index หรือ collection)
+func dropIndexIfExists(ctx context.Context, col *mongo.Collection, name string) error {
+	_, err := col.Indexes().DropOne(ctx, name)
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) && (cmdErr.Code == 26 || cmdErr.Code == 27) { // NamespaceNotFound, IndexNotFound
+		return nil
+	}
+	return err
+}
+
+// รันคำสั่ง collMod (ข้ามถ้า MongoDB รุ่นนี้ไม่รู้จัก option)
+func collModIfSupported(ctx context.Context, db *mongo.Database, cmd bson.D) error {
+	err := db.RunCommand(ctx, cmd).Err()
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) && (cmdErr.Code == 72 || cmdErr.Code == 40415) { // InvalidOptions, unknown field
+		return nil
+	}
+	return err
+}
@@ -5,40 +5,93 @@ import (
 	"log"
 	"time"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 const (
-	MongoURI = "mongodb://localhost:27017" // URI สำหรับเชื่อมต่อ MongoDB
+	MongoURI = "mongodb://localhost:27017" // URI เริ่มต้นสำหรับเชื่อมต่อ MongoDB
 	DBName   = "authManagement"            // ชื่อฐานข้อมูลที่จะใช้
 )
 
+// รวม collection ทั้งหมดที่ service ต่าง ๆ ใช้งาน
+type Collections struct {
+	Tenants   *mongo.Collection // tenant และการตั้งค่าของแต่ละ tenant
+	Users     *mongo.Collection // ข้อมูลผู้ใช้ (รวมการเป็นสมาชิกกลุ่ม)
+	Groups    *mongo.Collection // กลุ่มของผู้ใช้
+	APIKeys   *mongo.Collection // API key ของผู้ใช้ (เก็บเฉพาะ hash)
+	Clients   *mongo.Collection // service account สำหรับ OAuth2 client_credentials
+	OAuthApps *mongo.Collection // client ของ OAuth/OpenID Connect (authorization code flow)
+	Consents  *mongo.Collection // ความยินยอมของผู้ใช้ที่ให้กับ client
+	IdPs      *mongo.Collection // identity provider ภายนอกของแต่ละ tenant
+	Links     *mongo.Collection // บัญชีภายนอกที่ผู้ใช้ผูกไว้
+	Dirs      *mongo.Collection // LDAP / Active Directory ของแต่ละ tenant
+	Passkeys  *mongo.Collection // passkey (WebAuthn credential) ของผู้ใช้
+	Webhooks  *mongo.Collection // webhook ของแต่ละ tenant
+	HookQueue *mongo.Collection // คิวและประวัติการส่งเหตุการณ์ไปยัง webhook
+	Outbox    *mongo.Collection // domain event ที่รอเผยแพร่ (transactional outbox)
+	Blacklist *mongo.Collection // token ที่ถูก blacklist
+	AuditLogs *mongo.Collection // บันทึกเหตุการณ์ (audit log)
+	Settings  *mongo.Collection // การตั้งค่าของระบบ เช่น schema ของโปรไฟล์ผู้ใช้
+}
+
 // ฟังก์ชัน InitMongo ใช้สำหรับเชื่อมต่อกับ MongoDB และส่งคืน client กับ collection ที่ต้องการ
-func InitMongo() (*mongo.Client, *mongo.Collection, *mongo.Collection, error) {
+func InitMongo(uri string) (*mongo.Client, *Collections, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel() // เพื่อให้ยกเลิก context เมื่อฟังก์ชันนี้ทำงานเสร็จ
 
-	// สร้าง client เชื่อมต่อ MongoDB โดยใช้ URI ที่กำหนดไว้
-	client, err := mongo.Connect(ctx, options.Client().ApplyURI(MongoURI))
+	// สร้าง client เชื่อมต่อ MongoDB โดยใช้ URI ที่กำหนด (ค่าว่าง = MongoURI)
+	if uri == "" {
+		uri = MongoURI
+	}
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, nil, err
 	}
 
 	// ตรวจสอบว่าการเชื่อมต่อยังใช้ได้โดยการ ping
 	if err := client.Ping(ctx, nil); err != nil {
-		return nil, nil, nil, err
+		return nil, nil, err
 	}
 
 	log.Println("Connected to MongoDB")
 
 	// เลือกฐานข้อมูลตามชื่อ DBName
-	db := client.Database(DBName)
+	return client, NewCollections(client.Database(DBName)), nil
+}
 
-	// เลือก collection
-	usersCollection := db.Collection("users")
-	blacklistCollection := db.Collection("blacklisted_tokens")
+// เลือก collection ทั้งหมดจากฐานข้อมูลที่กำหนด (ชุดทดสอบใช้ฐานข้อมูลแยกของตัวเอง)
+func NewCollections(db *mongo.Database) *Collections {
+	return &Collections{
+		Tenants:   db.Collection("tenants"),
+		Users:     db.Collection("users"),
+		Groups:    db.Collection("groups"),
+		APIKeys:   db.Collection("api_keys"),
+		Clients:   db.Collection("service_accounts"),
+		OAuthApps: db.Collection("oauth_clients"),
+		Consents:  db.Collection("oauth_consents"),
+		IdPs:      db.Collection("identity_providers"),
+		Links:     db.Collection("linked_identities"),
+		Dirs:      db.Collection("directories"),
+		Passkeys:  db.Collection("passkeys"),
+		Webhooks:  db.Collection("webhooks"),
+		HookQueue: db.Collection("webhook_deliveries"),
+		Outbox:    db.Collection("outbox"),
+		Blacklist: db.Collection("blacklisted_tokens"),
+		AuditLogs: db.Collection("audit_logs"),
+		Settings:  db.Collection("settings"),
+	}
+}
 
-	// ส่งคืนค่าที่กำหนด
-	return client, usersCollection, blacklistCollection, nil
+// ตรวจว่า MongoDB รองรับ transaction หรือไม่ (replica set หรือ sharded cluster) ด้วยคำสั่ง hello
+func SupportsTransactions(ctx context.Context, client *mongo.Client) (bool, error) {
+	var hello struct {
+		SetName string `bson:"setName"`
+		Msg     string `bson:"msg"`
+	}
+	if err := client.Database("admin").RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello); err != nil {
+		return false, err
+	}
+	return hello.SetName != "" || hello.Msg == "isdbgrid", nil
 }
@@ -0,0 +1,50 @@
+// Package directory ตรวจสอบรหัสผ่านของผู้ใช้กับ directory ขององค์กร (LDAP / Active Directory)
+// เพื่อไม่ต้องเก็บ hash รหัสผ่านของพนักงานไว้ในระบบนี้
+package directory
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ข้อมูลผู้ใช้ใน directory หลังตรวจสอบรหัสผ่านแล้ว
+type Entry struct {
+	DN          string
+	Username    string
+	DisplayName string
+	Groups      []string // DN ของกลุ่มที่ผู้ใช้เป็นสมาชิก
+}
+
+// การตั้งค่าการเชื่อมต่อและการค้นหาผู้ใช้
+// ใน UserDNTemplate และ UserFilter ใช้ {email} และ {username} (ส่วนหน้า @ ของอีเมล) ส่วน GroupFilter ใช้ {dn}
+type Config struct {
+	URL                  string // ldap:// หรือ ldaps://
+	StartTLS             bool
+	RootCA               string // CA (PEM) ของ server (ว่าง = CA ของระบบ)
+	BindDN               string // service account สำหรับค้นหาผู้ใช้และกลุ่ม (ว่าง = anonymous)
+	BindPassword         string
+	UserDNTemplate       string // bind ด้วย DN ของผู้ใช้โดยตรง
+	BaseDN               string
+	UserFilter           string // ค้นหา DN ของผู้ใช้ก่อน bind (ใช้แทน UserDNTemplate)
+	UsernameAttribute    string
+	DisplayNameAttribute string
+	GroupFilter          string // ว่าง = ใช้ attribute memberOf ของผู้ใช้
+	PoolSize             int
+	Timeout              time.Duration // เวลารอ server แต่ละครั้ง (0 = 10 วินาที)
+}
+
+// Directory คือที่เก็บบัญชีผู้ใช้ภายนอกที่ตรวจสอบรหัสผ่านได้
+type Directory interface {
+	// ตรวจสอบอีเมลและรหัสผ่าน คืน ErrInvalidCredentials หรือ ErrUserNotFound ถ้าไม่ผ่าน
+	Authenticate(ctx context.Context, email string, password string) (*Entry, error)
+	// ปิด connection ทั้งหมด
+	Close()
+}
+
+var (
+	// รหัสผ่านไม่ถูกต้อง (หรือไม่พบผู้ใช้เมื่อ bind ด้วย DN ของผู้ใช้โดยตรงซึ่งแยกสองกรณีไม่ได้)
+	ErrInvalidCredentials = errors.New("directory: invalid credentials")
+	// ไม่พบผู้ใช้จากการค้นหาด้วย UserFilter
+	ErrUserNotFound = errors.New("directory: user not found")
+)
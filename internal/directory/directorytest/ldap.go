@@ -0,0 +1,403 @@
+// Package directorytest มี LDAP server ที่รันใน process สำหรับทดสอบการเข้าสู่ระบบผ่าน directory
+package directorytest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+)
+
+// OID ของ StartTLS extended operation (RFC 4511)
+const startTLSOID = "1.3.6.1.4.1.1466.20037"
+
+// Entry คือข้อมูลหนึ่งรายการใน directory (Password ว่าง = bind ด้วย DN นี้ไม่ได้)
+type Entry struct {
+	DN         string
+	Password   string
+	Attributes map[string][]string
+}
+
+// Server คือ LDAP server ขนาดเล็กบน 127.0.0.1 ที่รองรับ simple bind, search, unbind และ StartTLS
+// filter ที่รองรับคือ and, or, not, equality (ไม่สนตัวพิมพ์) และ present
+// bind ด้วย DN หรือด้วยค่า userPrincipalName ได้เหมือน Active Directory
+type Server struct {
+	URL    string // ldap://127.0.0.1:port
+	RootCA string // certificate (PEM) ของ server สำหรับ StartTLS
+	// ปฏิเสธ bind ด้วยรหัสผ่านบน connection ที่ยังไม่เข้ารหัส (ต้อง StartTLS ก่อน)
+	RequireTLS bool
+
+	listener  net.Listener
+	tlsConfig *tls.Config
+
+	mu          sync.Mutex
+	entries     []Entry
+	connections int
+	conns       map[net.Conn]struct{}
+}
+
+// เริ่ม server ที่ไม่มีข้อมูล (ปิดเมื่อจบการทดสอบ)
+func NewServer(t *testing.T) *Server {
+	t.Helper()
+	cert, rootCA, err := selfSignedCert()
+	if err != nil {
+		t.Fatalf("generate LDAP server certificate: %v", err)
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	s := &Server{
+		URL:       "ldap://" + listener.Addr().String(),
+		RootCA:    rootCA,
+		listener:  listener,
+		tlsConfig: &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12},
+		conns:     map[net.Conn]struct{}{},
+	}
+	go s.serve()
+	t.Cleanup(s.Close)
+	return s
+}
+
+// เพิ่มรายการใน directory
+func (s *Server) Add(entry Entry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, entry)
+}
+
+// แทนค่าของ attribute ในรายการ (เช่น ย้ายผู้ใช้ออกจากกลุ่มด้วย memberOf)
+func (s *Server) SetAttribute(dn string, name string, values ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.entries {
+		if equalDN(s.entries[i].DN, dn) {
+			if s.entries[i].Attributes == nil {
+				s.entries[i].Attributes = map[string][]string{}
+			}
+			s.entries[i].Attributes[name] = values
+		}
+	}
+}
+
+// จำนวน connection ที่ client เปิดมาทั้งหมด
+func (s *Server) Connections() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.connections
+}
+
+// ตัด connection ที่เปิดอยู่ทั้งหมด (เหมือน server restart) แต่ยังรับ connection ใหม่
+func (s *Server) DropConnections() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for conn := range s.conns {
+		conn.Close()
+	}
+}
+
+// ปิด server และ connection ทั้งหมด
+func (s *Server) Close() {
+	s.listener.Close()
+	s.DropConnections()
+}
+
+func (s *Server) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.connections++
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+		go s.handle(conn)
+	}
+}
+
+// สถานะของแต่ละ connection
+type session struct {
+	conn net.Conn
+	tls  bool
+}
+
+func (s *Server) handle(conn net.Conn) {
+	sess := &session{conn: conn}
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		sess.conn.Close()
+	}()
+
+	for {
+		packet, err := ber.ReadPacket(sess.conn)
+		if err != nil || len(packet.Children) < 2 {
+			return
+		}
+		messageID := packet.Children[0].Value
+		op := packet.Children[1]
+		switch op.Tag {
+		case ldap.ApplicationBindRequest:
+			code := s.bind(sess, op)
+			s.write(sess, messageID, result(ldap.ApplicationBindResponse, code))
+		case ldap.ApplicationUnbindRequest:
+			return
+		case ldap.ApplicationSearchRequest:
+			s.search(sess, messageID, op)
+		case ldap.ApplicationExtendedRequest:
+			if len(op.Children) == 0 || string(op.Children[0].Data.Bytes()) != startTLSOID || sess.tls {
+				s.write(sess, messageID, result(ldap.ApplicationExtendedResponse, ldap.LDAPResultProtocolError))
+				continue
+			}
+			response := result(ldap.ApplicationExtendedResponse, ldap.LDAPResultSuccess)
+			response.AppendChild(ber.NewString(ber.ClassContext, ber.TypePrimitive, 10, startTLSOID, "responseName"))
+			s.write(sess, messageID, response)
+			tlsConn := tls.Server(sess.conn, s.tlsConfig)
+			if err := tlsConn.Handshake(); err != nil {
+				return
+			}
+			sess.conn, sess.tls = tlsConn, true
+		default:
+			return
+		}
+	}
+}
+
+// simple bind: รหัสผ่านว่างคือ anonymous bind (RFC 4513) ซึ่งสำเร็จเสมอ
+func (s *Server) bind(sess *session, op *ber.Packet) uint16 {
+	if len(op.Children) < 3 || op.Children[2].Tag != 0 {
+		return ldap.LDAPResultAuthMethodNotSupported
+	}
+	name, _ := op.Children[1].Value.(string)
+	password := string(op.Children[2].Data.Bytes())
+	if password == "" {
+		return ldap.LDAPResultSuccess
+	}
+	if s.RequireTLS && !sess.tls {
+		return ldap.LDAPResultConfidentialityRequired
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, e := range s.entries {
+		if !equalDN(e.DN, name) && !hasValue(e, "userPrincipalName", name) {
+			continue
+		}
+		if e.Password == "" || e.Password != password {
+			break
+		}
+		return ldap.LDAPResultSuccess
+	}
+	return ldap.LDAPResultInvalidCredentials
+}
+
+func (s *Server) search(sess *session, messageID interface{}, op *ber.Packet) {
+	if len(op.Children) < 8 {
+		s.write(sess, messageID, result(ldap.ApplicationSearchResultDone, ldap.LDAPResultProtocolError))
+		return
+	}
+	base, _ := op.Children[0].Value.(string)
+	scope, _ := op.Children[1].Value.(int64)
+	sizeLimit, _ := op.Children[3].Value.(int64)
+	filter := op.Children[6]
+	var attributes []string
+	for _, a := range op.Children[7].Children {
+		if name, ok := a.Value.(string); ok {
+			attributes = append(attributes, name)
+		}
+	}
+
+	s.mu.Lock()
+	var matched []Entry
+	for _, e := range s.entries {
+		if inScope(e.DN, base, int(scope)) && matches(e, filter) {
+			matched = append(matched, e)
+		}
+	}
+	s.mu.Unlock()
+
+	code := uint16(ldap.LDAPResultSuccess)
+	if sizeLimit > 0 && int64(len(matched)) > sizeLimit {
+		matched, code = matched[:sizeLimit], ldap.LDAPResultSizeLimitExceeded
+	}
+	for _, e := range matched {
+		s.write(sess, messageID, searchEntry(e, attributes))
+	}
+	s.write(sess, messageID, result(ldap.ApplicationSearchResultDone, code))
+}
+
+func (s *Server) write(sess *session, messageID interface{}, op *ber.Packet) {
+	packet := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+	packet.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, messageID, "MessageID"))
+	packet.AppendChild(op)
+	sess.conn.Write(packet.Bytes())
+}
+
+// LDAPResult (resultCode, matchedDN, diagnosticMessage) ใน response ตามชนิดที่กำหนด
+func result(tag ber.Tag, code uint16) *ber.Packet {
+	p := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "Response")
+	p.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, int64(code), "resultCode"))
+	p.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "matchedDN"))
+	p.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, ldap.LDAPResultCodeMap[code], "diagnosticMessage"))
+	return p
+}
+
+// SearchResultEntry ที่มีเฉพาะ attribute ที่ขอ (ไม่ระบุ = ทั้งหมด)
+func searchEntry(e Entry, attributes []string) *ber.Packet {
+	p := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldap.ApplicationSearchResultEntry, nil, "Search Result Entry")
+	p.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, e.DN, "objectName"))
+	list := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "attributes")
+	for name, values := range e.Attributes {
+		if !wanted(name, attributes) {
+			continue
+		}
+		attr := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "attribute")
+		attr.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, name, "type"))
+		vals := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "vals")
+		for _, v := range values {
+			vals.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, v, "value"))
+		}
+		attr.AppendChild(vals)
+		list.AppendChild(attr)
+	}
+	p.AppendChild(list)
+	return p
+}
+
+func wanted(name string, attributes []string) bool {
+	if len(attributes) == 0 {
+		return true
+	}
+	for _, a := range attributes {
+		if a == "*" || strings.EqualFold(a, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// dn อยู่ใต้ base ตาม scope (0 = base object, 1 = single level, 2 = whole subtree)
+func inScope(dn string, base string, scope int) bool {
+	entry, err := ldap.ParseDN(dn)
+	if err != nil {
+		return false
+	}
+	baseDN, err := ldap.ParseDN(base)
+	if err != nil {
+		return false
+	}
+	switch scope {
+	case ldap.ScopeBaseObject:
+		return entry.EqualFold(baseDN)
+	case ldap.ScopeSingleLevel:
+		return len(entry.RDNs) == len(baseDN.RDNs)+1 && baseDN.AncestorOfFold(entry)
+	default:
+		return entry.EqualFold(baseDN) || baseDN.AncestorOfFold(entry)
+	}
+}
+
+// ตรวจ filter แบบ BER กับรายการ (filter ที่ไม่รองรับถือว่าไม่ตรง)
+func matches(e Entry, filter *ber.Packet) bool {
+	switch filter.Tag {
+	case ldap.FilterAnd:
+		for _, child := range filter.Children {
+			if !matches(e, child) {
+				return false
+			}
+		}
+		return true
+	case ldap.FilterOr:
+		for _, child := range filter.Children {
+			if matches(e, child) {
+				return true
+			}
+		}
+		return false
+	case ldap.FilterNot:
+		return len(filter.Children) == 1 && !matches(e, filter.Children[0])
+	case ldap.FilterEqualityMatch:
+		if len(filter.Children) != 2 {
+			return false
+		}
+		name, _ := filter.Children[0].Value.(string)
+		value, _ := filter.Children[1].Value.(string)
+		return hasValue(e, name, value)
+	case ldap.FilterPresent:
+		name := string(filter.Data.Bytes())
+		if strings.EqualFold(name, "objectClass") {
+			return true
+		}
+		_, ok := attribute(e, name)
+		return ok
+	}
+	return false
+}
+
+// รายการมี attribute นี้ที่มีค่าตรงกัน (ไม่สนตัวพิมพ์ ค่าที่เป็น DN เทียบแบบ DN)
+func hasValue(e Entry, name string, value string) bool {
+	values, _ := attribute(e, name)
+	for _, v := range values {
+		if strings.EqualFold(v, value) || (strings.Contains(v, "=") && equalDN(v, value)) {
+			return true
+		}
+	}
+	return false
+}
+
+func attribute(e Entry, name string) ([]string, bool) {
+	for n, values := range e.Attributes {
+		if strings.EqualFold(n, name) {
+			return values, true
+		}
+	}
+	return nil, false
+}
+
+func equalDN(a string, b string) bool {
+	dnA, errA := ldap.ParseDN(a)
+	dnB, errB := ldap.ParseDN(b)
+	if errA != nil || errB != nil || a == "" || b == "" {
+		return false
+	}
+	return dnA.EqualFold(dnB)
+}
+
+// certificate แบบ self-signed สำหรับ 127.0.0.1 และ localhost คืนทั้ง certificate สำหรับ server และ PEM สำหรับ client
+func selfSignedCert() (tls.Certificate, string, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, "", err
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "directorytest"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1)},
+		DNSNames:              []string{"localhost"},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, "", err
+	}
+	cert := tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+	return cert, string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})), nil
+}
@@ -0,0 +1,216 @@
+package directory
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+const (
+	defaultPoolSize = 5
+	defaultTimeout  = 10 * time.Second
+)
+
+// LDAP ตรวจสอบรหัสผ่านด้วยการ bind กับ LDAP / Active Directory
+// แบบ bind ด้วย DN ของผู้ใช้ (UserDNTemplate) หรือค้นหา DN ด้วย service account ก่อนแล้วจึง bind (UserFilter)
+type LDAP struct {
+	Config Config
+
+	tlsConfig *tls.Config
+	pool      *pool
+}
+
+// สร้างอินสแตนซ์ของ LDAP (ยังไม่เชื่อมต่อจนกว่าจะมีการเข้าสู่ระบบ)
+func NewLDAP(cfg Config) (*LDAP, error) {
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("directory: URL ไม่ถูกต้อง: %w", err)
+	}
+	tlsConfig := &tls.Config{ServerName: u.Hostname(), MinVersion: tls.VersionTLS12}
+	if cfg.RootCA != "" {
+		roots := x509.NewCertPool()
+		if !roots.AppendCertsFromPEM([]byte(cfg.RootCA)) {
+			return nil, errors.New("directory: CA ไม่ใช่ certificate แบบ PEM")
+		}
+		tlsConfig.RootCAs = roots
+	}
+	if cfg.PoolSize <= 0 {
+		cfg.PoolSize = defaultPoolSize
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+
+	d := &LDAP{Config: cfg, tlsConfig: tlsConfig}
+	d.pool = newPool(cfg.PoolSize, d.dial)
+	return d, nil
+}
+
+func (d *LDAP) Authenticate(ctx context.Context, email string, password string) (*Entry, error) {
+	// LDAP ถือว่า bind ด้วยรหัสผ่านว่างเป็น unauthenticated bind ซึ่งสำเร็จเสมอ
+	if password == "" {
+		return nil, ErrInvalidCredentials
+	}
+	conn, err := d.pool.get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("directory: เชื่อมต่อ server ไม่ได้: %w", err)
+	}
+	entry, err := d.authenticate(conn, email, password)
+	d.pool.put(conn, ldap.IsErrorWithCode(err, ldap.ErrorNetwork))
+	return entry, err
+}
+
+func (d *LDAP) Close() {
+	d.pool.close()
+}
+
+func (d *LDAP) authenticate(conn *ldap.Conn, email string, password string) (*Entry, error) {
+	username, _, _ := strings.Cut(email, "@")
+	attributes := []string{"memberOf"}
+	for _, attr := range []string{d.Config.UsernameAttribute, d.Config.DisplayNameAttribute} {
+		if attr != "" {
+			attributes = append(attributes, attr)
+		}
+	}
+
+	var user *ldap.Entry
+	if d.Config.UserFilter != "" {
+		// ค้นหา DN ของผู้ใช้ด้วย service account แล้วจึง bind ด้วยรหัสผ่านของผู้ใช้
+		if err := d.bindService(conn); err != nil {
+			return nil, err
+		}
+		filter := expand(d.Config.UserFilter, ldap.EscapeFilter, map[string]string{"email": email, "username": username})
+		res, err := conn.Search(ldap.NewSearchRequest(d.Config.BaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases,
+			2, int(d.Config.Timeout/time.Second), false, filter, attributes, nil))
+		if err != nil && !ldap.IsErrorWithCode(err, ldap.LDAPResultSizeLimitExceeded) {
+			return nil, err
+		}
+		switch {
+		case res == nil || len(res.Entries) == 0:
+			return nil, ErrUserNotFound
+		case len(res.Entries) > 1:
+			return nil, fmt.Errorf("directory: พบผู้ใช้มากกว่า 1 รายการสำหรับ %s", email)
+		}
+		user = res.Entries[0]
+		if err := d.bind(conn, user.DN, password); err != nil {
+			return nil, err
+		}
+	} else {
+		dn := expand(d.Config.UserDNTemplate, ldap.EscapeDN, map[string]string{"email": email, "username": username})
+		if err := d.bind(conn, dn, password); err != nil {
+			return nil, err
+		}
+		// อ่าน entry ของตัวเองหลัง bind สำเร็จ (AD ที่ bind ด้วย UPN ต้องค้นหาจาก userPrincipalName)
+		base, scope, filter := dn, ldap.ScopeBaseObject, "(objectClass=*)"
+		if _, err := ldap.ParseDN(dn); err != nil || !strings.Contains(dn, "=") {
+			base, scope, filter = d.Config.BaseDN, ldap.ScopeWholeSubtree, "(userPrincipalName="+ldap.EscapeFilter(dn)+")"
+		}
+		res, err := conn.Search(ldap.NewSearchRequest(base, scope, ldap.NeverDerefAliases,
+			1, int(d.Config.Timeout/time.Second), false, filter, attributes, nil))
+		if err != nil || len(res.Entries) == 0 {
+			return nil, fmt.Errorf("directory: อ่านข้อมูลผู้ใช้ %s ไม่ได้: %v", dn, err)
+		}
+		user = res.Entries[0]
+	}
+
+	entry := &Entry{
+		DN:       user.DN,
+		Username: user.GetAttributeValue(d.Config.UsernameAttribute),
+		Groups:   user.GetAttributeValues("memberOf"),
+	}
+	if d.Config.DisplayNameAttribute != "" {
+		entry.DisplayName = user.GetAttributeValue(d.Config.DisplayNameAttribute)
+	}
+	if d.Config.GroupFilter != "" {
+		groups, err := d.searchGroups(conn, user.DN)
+		if err != nil {
+			return nil, err
+		}
+		entry.Groups = groups
+	}
+	return entry, nil
+}
+
+// ค้นหากลุ่มที่มีผู้ใช้เป็นสมาชิก (ด้วย service account ถ้ามี เพราะผู้ใช้ทั่วไปอาจอ่านกลุ่มไม่ได้)
+func (d *LDAP) searchGroups(conn *ldap.Conn, userDN string) ([]string, error) {
+	if d.Config.BindDN != "" {
+		if err := d.bindService(conn); err != nil {
+			return nil, err
+		}
+	}
+	filter := expand(d.Config.GroupFilter, ldap.EscapeFilter, map[string]string{"dn": userDN})
+	res, err := conn.Search(ldap.NewSearchRequest(d.Config.BaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases,
+		0, int(d.Config.Timeout/time.Second), false, filter, []string{"dn"}, nil))
+	if err != nil {
+		return nil, err
+	}
+	groups := make([]string, 0, len(res.Entries))
+	for _, group := range res.Entries {
+		groups = append(groups, group.DN)
+	}
+	return groups, nil
+}
+
+// bind ด้วย service account (หรือ anonymous ถ้าไม่ได้ตั้งค่า)
+func (d *LDAP) bindService(conn *ldap.Conn) error {
+	if d.Config.BindDN == "" {
+		return conn.UnauthenticatedBind("")
+	}
+	if err := conn.Bind(d.Config.BindDN, d.Config.BindPassword); err != nil {
+		return fmt.Errorf("directory: bind ด้วย service account ไม่สำเร็จ: %w", err)
+	}
+	return nil
+}
+
+// bind ด้วยรหัสผ่านของผู้ใช้
+func (d *LDAP) bind(conn *ldap.Conn, dn string, password string) error {
+	err := conn.Bind(dn, password)
+	if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+		return ErrInvalidCredentials
+	}
+	return err
+}
+
+// เปิด connection ใหม่ (ขอ StartTLS ถ้าตั้งค่าไว้)
+func (d *LDAP) dial() (*ldap.Conn, error) {
+	conn, err := ldap.DialURL(d.Config.URL,
+		ldap.DialWithDialer(&net.Dialer{Timeout: d.Config.Timeout}),
+		ldap.DialWithTLSConfig(d.tlsConfig))
+	if err != nil {
+		return nil, err
+	}
+	conn.SetTimeout(d.Config.Timeout)
+	if d.Config.StartTLS {
+		if err := conn.StartTLS(d.tlsConfig); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("directory: StartTLS ไม่สำเร็จ: %w", err)
+		}
+	}
+	return conn, nil
+}
+
+// แทนที่ {name} ใน template ด้วยค่าที่ escape แล้ว
+func expand(template string, escape func(string) string, values map[string]string) string {
+	for name, value := range values {
+		template = strings.ReplaceAll(template, "{"+name+"}", escape(value))
+	}
+	return template
+}
+
+// เปรียบเทียบ DN สองค่าโดยไม่สนตัวพิมพ์และช่องว่างระหว่าง RDN (ถ้า parse ไม่ได้ให้เทียบเป็นข้อความ)
+func EqualDN(a string, b string) bool {
+	dnA, errA := ldap.ParseDN(a)
+	dnB, errB := ldap.ParseDN(b)
+	if errA != nil || errB != nil {
+		return strings.EqualFold(a, b)
+	}
+	return dnA.EqualFold(dnB)
+}
@@ -0,0 +1,268 @@
+package directory_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"auth-microservice/internal/directory"
+	"auth-microservice/internal/directory/directorytest"
+)
+
+const (
+	adminsGroup = "cn=admins,ou=groups,dc=corp,dc=example"
+	staffGroup  = "cn=staff,ou=groups,dc=corp,dc=example"
+)
+
+// directory ขององค์กรที่มี service account, ผู้ใช้สองคน และกลุ่มแบบ groupOfNames
+func newServer(t *testing.T) *directorytest.Server {
+	t.Helper()
+	s := directorytest.NewServer(t)
+	s.Add(directorytest.Entry{
+		DN:       "cn=svc,dc=corp,dc=example",
+		Password: "svc-secret",
+	})
+	s.Add(directorytest.Entry{
+		DN:       "uid=alice,ou=people,dc=corp,dc=example",
+		Password: "alice-pw",
+		Attributes: map[string][]string{
+			"objectClass":       {"person"},
+			"uid":               {"alice"},
+			"cn":                {"Alice Smith"},
+			"mail":              {"alice@corp.example.com"},
+			"userPrincipalName": {"alice@corp.example.com"},
+			"memberOf":          {adminsGroup},
+		},
+	})
+	s.Add(directorytest.Entry{
+		DN:       "uid=bob,ou=people,dc=corp,dc=example",
+		Password: "bob-pw",
+		Attributes: map[string][]string{
+			"objectClass": {"person"},
+			"uid":         {"bob"},
+			"cn":          {"Bob Jones"},
+			"mail":        {"bob@corp.example.com"},
+		},
+	})
+	s.Add(directorytest.Entry{
+		DN:         adminsGroup,
+		Attributes: map[string][]string{"objectClass": {"groupOfNames"}, "member": {"uid=alice,ou=people,dc=corp,dc=example"}},
+	})
+	s.Add(directorytest.Entry{
+		DN: staffGroup,
+		Attributes: map[string][]string{"objectClass": {"groupOfNames"}, "member": {
+			"uid=alice,ou=people,dc=corp,dc=example",
+			"uid=bob,ou=people,dc=corp,dc=example",
+		}},
+	})
+	return s
+}
+
+func newLDAP(t *testing.T, cfg directory.Config) *directory.LDAP {
+	t.Helper()
+	d, err := directory.NewLDAP(cfg)
+	if err != nil {
+		t.Fatalf("NewLDAP: %v", err)
+	}
+	t.Cleanup(d.Close)
+	return d
+}
+
+func bindAsUserConfig(s *directorytest.Server) directory.Config {
+	return directory.Config{
+		URL:                  s.URL,
+		UserDNTemplate:       "uid={username},ou=people,dc=corp,dc=example",
+		UsernameAttribute:    "uid",
+		DisplayNameAttribute: "cn",
+	}
+}
+
+func searchConfig(s *directorytest.Server) directory.Config {
+	return directory.Config{
+		URL:                  s.URL,
+		BindDN:               "cn=svc,dc=corp,dc=example",
+		BindPassword:         "svc-secret",
+		BaseDN:               "dc=corp,dc=example",
+		UserFilter:           "(&(objectClass=person)(mail={email}))",
+		UsernameAttribute:    "uid",
+		DisplayNameAttribute: "cn",
+		GroupFilter:          "(&(objectClass=groupOfNames)(member={dn}))",
+	}
+}
+
+func TestLDAPBindAsUser(t *testing.T) {
+	d := newLDAP(t, bindAsUserConfig(newServer(t)))
+
+	entry, err := d.Authenticate(context.Background(), "alice@corp.example.com", "alice-pw")
+	if err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+	if entry.DN != "uid=alice,ou=people,dc=corp,dc=example" || entry.Username != "alice" || entry.DisplayName != "Alice Smith" ||
+		len(entry.Groups) != 1 || entry.Groups[0] != adminsGroup {
+		t.Fatalf("Authenticate = %+v", entry)
+	}
+
+	// bind ด้วย DN ของผู้ใช้โดยตรงแยกไม่ได้ว่ารหัสผ่านผิดหรือไม่มีผู้ใช้
+	for _, tt := range []struct{ email, password string }{
+		{"alice@corp.example.com", "wrong"},
+		{"alice@corp.example.com", ""},
+		{"nobody@corp.example.com", "alice-pw"},
+	} {
+		if _, err := d.Authenticate(context.Background(), tt.email, tt.password); !errors.Is(err, directory.ErrInvalidCredentials) {
+			t.Errorf("Authenticate(%q, %q) = %v, want ErrInvalidCredentials", tt.email, tt.password, err)
+		}
+	}
+}
+
+func TestLDAPSearchThenBind(t *testing.T) {
+	s := newServer(t)
+	d := newLDAP(t, searchConfig(s))
+
+	entry, err := d.Authenticate(context.Background(), "bob@corp.example.com", "bob-pw")
+	if err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+	if entry.DN != "uid=bob,ou=people,dc=corp,dc=example" || entry.Username != "bob" ||
+		len(entry.Groups) != 1 || entry.Groups[0] != staffGroup {
+		t.Fatalf("Authenticate = %+v, want bob in the staff group", entry)
+	}
+
+	if _, err := d.Authenticate(context.Background(), "bob@corp.example.com", "alice-pw"); !errors.Is(err, directory.ErrInvalidCredentials) {
+		t.Fatalf("Authenticate with a wrong password = %v, want ErrInvalidCredentials", err)
+	}
+	if _, err := d.Authenticate(context.Background(), "carol@corp.example.com", "x"); !errors.Is(err, directory.ErrUserNotFound) {
+		t.Fatalf("Authenticate of an unknown email = %v, want ErrUserNotFound", err)
+	}
+	// อักขระพิเศษในอีเมลถูก escape จึงใช้แก้ filter ไม่ได้
+	if _, err := d.Authenticate(context.Background(), "*)(uid=bob", "bob-pw"); !errors.Is(err, directory.ErrUserNotFound) {
+		t.Fatalf("Authenticate with a filter injection = %v, want ErrUserNotFound", err)
+	}
+
+	// อีเมลเดียวกันสองรายการต้องไม่ bind กับรายการใดรายการหนึ่ง
+	s.Add(directorytest.Entry{
+		DN:         "uid=bob2,ou=people,dc=corp,dc=example",
+		Password:   "bob-pw",
+		Attributes: map[string][]string{"objectClass": {"person"}, "mail": {"bob@corp.example.com"}},
+	})
+	_, err = d.Authenticate(context.Background(), "bob@corp.example.com", "bob-pw")
+	if err == nil || errors.Is(err, directory.ErrInvalidCredentials) || errors.Is(err, directory.ErrUserNotFound) {
+		t.Fatalf("Authenticate of an ambiguous email = %v, want an error", err)
+	}
+}
+
+func TestLDAPWrongServiceAccount(t *testing.T) {
+	cfg := searchConfig(newServer(t))
+	cfg.BindPassword = "wrong"
+	d := newLDAP(t, cfg)
+
+	// รหัสผ่านของ service account ผิดเป็นปัญหาการตั้งค่า ไม่ใช่รหัสผ่านของผู้ใช้ผิด
+	_, err := d.Authenticate(context.Background(), "bob@corp.example.com", "bob-pw")
+	if err == nil || errors.Is(err, directory.ErrInvalidCredentials) {
+		t.Fatalf("Authenticate with a wrong service account = %v, want a configuration error", err)
+	}
+}
+
+func TestLDAPActiveDirectoryUPN(t *testing.T) {
+	d := newLDAP(t, directory.Config{
+		URL:               newServer(t).URL,
+		UserDNTemplate:    "{email}",
+		BaseDN:            "dc=corp,dc=example",
+		UsernameAttribute: "uid",
+	})
+
+	// bind ด้วย userPrincipalName แล้วค้นหา entry ของตัวเองจาก UPN
+	entry, err := d.Authenticate(context.Background(), "alice@corp.example.com", "alice-pw")
+	if err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+	if entry.DN != "uid=alice,ou=people,dc=corp,dc=example" || entry.Username != "alice" {
+		t.Fatalf("Authenticate = %+v", entry)
+	}
+}
+
+func TestLDAPStartTLS(t *testing.T) {
+	s := newServer(t)
+	s.RequireTLS = true
+
+	// server ไม่ยอมรับรหัสผ่านบน connection ที่ไม่เข้ารหัส
+	plain := newLDAP(t, bindAsUserConfig(s))
+	if _, err := plain.Authenticate(context.Background(), "alice@corp.example.com", "alice-pw"); err == nil || errors.Is(err, directory.ErrInvalidCredentials) {
+		t.Fatalf("Authenticate without StartTLS = %v, want a confidentiality error", err)
+	}
+
+	cfg := bindAsUserConfig(s)
+	cfg.StartTLS = true
+	cfg.RootCA = s.RootCA
+	if _, err := newLDAP(t, cfg).Authenticate(context.Background(), "alice@corp.example.com", "alice-pw"); err != nil {
+		t.Fatalf("Authenticate with StartTLS: %v", err)
+	}
+
+	// certificate ของ server ต้องออกโดย CA ที่เชื่อถือ
+	cfg.RootCA = ""
+	if _, err := newLDAP(t, cfg).Authenticate(context.Background(), "alice@corp.example.com", "alice-pw"); err == nil {
+		t.Fatal("Authenticate with StartTLS to an untrusted certificate succeeded")
+	}
+}
+
+func TestLDAPRejectsInvalidRootCA(t *testing.T) {
+	cfg := bindAsUserConfig(newServer(t))
+	cfg.RootCA = "not a certificate"
+	if _, err := directory.NewLDAP(cfg); err == nil {
+		t.Fatal("NewLDAP with an invalid CA succeeded")
+	}
+}
+
+func TestLDAPPoolReusesConnections(t *testing.T) {
+	s := newServer(t)
+	cfg := searchConfig(s)
+	cfg.PoolSize = 2
+	d := newLDAP(t, cfg)
+
+	for i := 0; i < 3; i++ {
+		if _, err := d.Authenticate(context.Background(), "bob@corp.example.com", "bob-pw"); err != nil {
+			t.Fatalf("Authenticate #%d: %v", i, err)
+		}
+	}
+	if n := s.Connections(); n != 1 {
+		t.Fatalf("sequential logins opened %d connections, want 1", n)
+	}
+
+	// เข้าสู่ระบบพร้อมกันเปิด connection ได้ไม่เกินขนาดของ pool
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := d.Authenticate(context.Background(), "alice@corp.example.com", "alice-pw")
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("concurrent Authenticate: %v", err)
+		}
+	}
+	if n := s.Connections(); n > 2 {
+		t.Fatalf("concurrent logins opened %d connections, want at most 2", n)
+	}
+}
+
+func TestEqualDN(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"cn=Admins,ou=Groups,dc=corp,dc=example", "CN=admins, OU=groups, DC=corp, DC=example", true},
+		{"cn=admins,ou=groups,dc=corp,dc=example", "cn=staff,ou=groups,dc=corp,dc=example", false},
+		{"not a dn", "NOT A DN", true},
+	}
+	for _, tt := range tests {
+		if got := directory.EqualDN(tt.a, tt.b); got != tt.want {
+			t.Errorf("EqualDN(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
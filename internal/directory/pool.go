@@ -0,0 +1,83 @@
+package directory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+const idleTimeout = 5 * time.Minute // ปิด connection ที่ไม่ได้ใช้นานเกินนี้ (server มักตัดเองอยู่แล้ว)
+
+type idleConn struct {
+	conn     *ldap.Conn
+	returned time.Time
+}
+
+// pool ของ connection ไปยัง server เดียว จำกัดจำนวน connection ที่เปิดพร้อมกัน
+type pool struct {
+	dial  func() (*ldap.Conn, error)
+	slots chan struct{} // จำนวน connection ที่ใช้งานหรือเปิดค้างไว้ได้
+
+	mu     sync.Mutex
+	idle   []idleConn
+	closed bool
+}
+
+func newPool(size int, dial func() (*ldap.Conn, error)) *pool {
+	return &pool{dial: dial, slots: make(chan struct{}, size)}
+}
+
+// ยืม connection (ใช้ตัวที่ว่างอยู่ก่อน) รอจนมีที่ว่างถ้าใช้ครบตามขนาดของ pool แล้ว
+func (p *pool) get(ctx context.Context) (*ldap.Conn, error) {
+	select {
+	case p.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	p.mu.Lock()
+	for len(p.idle) > 0 {
+		c := p.idle[len(p.idle)-1]
+		p.idle = p.idle[:len(p.idle)-1]
+		if c.conn.IsClosing() || time.Since(c.returned) > idleTimeout {
+			c.conn.Close()
+			continue
+		}
+		p.mu.Unlock()
+		return c.conn, nil
+	}
+	p.mu.Unlock()
+
+	conn, err := p.dial()
+	if err != nil {
+		<-p.slots
+		return nil, err
+	}
+	return conn, nil
+}
+
+// คืน connection เข้า pool (broken = ปิดทิ้ง เช่น เมื่อเกิด network error)
+func (p *pool) put(conn *ldap.Conn, broken bool) {
+	defer func() { <-p.slots }()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if broken || p.closed || conn.IsClosing() {
+		conn.Close()
+		return
+	}
+	p.idle = append(p.idle, idleConn{conn: conn, returned: time.Now()})
+}
+
+// ปิด connection ที่ว่างอยู่ทั้งหมด ส่วนที่ถูกยืมอยู่จะถูกปิดตอนคืน
+func (p *pool) close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.closed = true
+	for _, c := range p.idle {
+		c.conn.Close()
+	}
+	p.idle = nil
+}
@@ -0,0 +1,62 @@
+// Package events เผยแพร่ domain event จาก transactional outbox ไปยัง message broker (Redis Streams, NATS หรือ bus ภายใน process)
+// การส่งเป็นแบบอย่างน้อยหนึ่งครั้ง (at-least-once) ผู้รับใช้ Message.ID กันการประมวลผลซ้ำ
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	models "auth-microservice/internal/model"
+)
+
+// Message คือ event ที่ส่งให้ผู้รับ
+type Message struct {
+	ID          string          `json:"id"`   // ID สำหรับกันการประมวลผลซ้ำ (ID ของ outbox event)
+	Type        string          `json:"type"` // เช่น "user.registered"
+	TenantID    string          `json:"tenantId"`
+	AggregateID string          `json:"aggregateId,omitempty"` // ID ของข้อมูลที่เปลี่ยน เช่น ID ผู้ใช้
+	CreatedAt   time.Time       `json:"createdAt"`
+	Data        json.RawMessage `json:"data"`
+}
+
+// Publisher ส่ง event ไปยัง broker คืน error ถ้าไม่แน่ใจว่า broker ได้รับแล้ว (relay จะส่งซ้ำ)
+type Publisher interface {
+	Publish(ctx context.Context, msg Message) error
+	Close() error
+}
+
+// แปลง outbox event เป็น Message
+func FromOutbox(ev models.OutboxEvent) Message {
+	return Message{
+		ID:          ev.ID.Hex(),
+		Type:        ev.Type,
+		TenantID:    ev.TenantID,
+		AggregateID: ev.AggregateID,
+		CreatedAt:   ev.CreatedAt.UTC(),
+		Data:        json.RawMessage(ev.Payload),
+	}
+}
+
+// Fanout ส่ง event ไปยัง Publisher ทุกตัวตามลำดับ และคืน error แรกที่พบ
+// (relay จะส่งซ้ำให้ทุกตัว ผู้รับแต่ละตัวตัด ID ที่ซ้ำเอง)
+type Fanout []Publisher
+
+func (f Fanout) Publish(ctx context.Context, msg Message) error {
+	for _, p := range f {
+		if err := p.Publish(ctx, msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f Fanout) Close() error {
+	var first error
+	for _, p := range f {
+		if err := p.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
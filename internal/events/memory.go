@@ -0,0 +1,93 @@
+package events
+
+import (
+	"context"
+	"sync"
+)
+
+// จำนวน ID ล่าสุดที่ MemoryBus จำไว้เพื่อกันการส่งซ้ำ
+const memoryDedupSize = 10000
+
+// MemoryBus ส่ง event ให้ผู้รับภายใน process เดียวกัน (ไม่ต้องมี broker ใช้สำหรับ development หรือ service ที่รันเครื่องเดียว)
+// event ที่ ID ซ้ำกับที่เคยส่งแล้วจะไม่ถูกส่งอีก
+type MemoryBus struct {
+	mu          sync.Mutex
+	subscribers map[int]*subscriber
+	nextID      int
+	seen        map[string]bool
+	seenOrder   []string // ID ตามลำดับที่ส่ง ใช้ลบ ID เก่าออกจาก seen
+}
+
+type subscriber struct {
+	ch   chan Message
+	done chan struct{}
+}
+
+// สร้างอินสแตนซ์ของ MemoryBus
+func NewMemoryBus() *MemoryBus {
+	return &MemoryBus{
+		subscribers: map[int]*subscriber{},
+		seen:        map[string]bool{},
+	}
+}
+
+// ส่ง event ให้ผู้รับทุกรายที่ subscribe อยู่ (รอจนผู้รับทุกรายรับแล้ว หรือ ctx ถูกยกเลิก)
+func (b *MemoryBus) Publish(ctx context.Context, msg Message) error {
+	b.mu.Lock()
+	if b.seen[msg.ID] {
+		b.mu.Unlock()
+		return nil
+	}
+	subs := make([]*subscriber, 0, len(b.subscribers))
+	for _, sub := range b.subscribers {
+		subs = append(subs, sub)
+	}
+	b.mu.Unlock()
+
+	for _, sub := range subs {
+		select {
+		case sub.ch <- msg:
+		case <-sub.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.seen[msg.ID] {
+		b.seen[msg.ID] = true
+		b.seenOrder = append(b.seenOrder, msg.ID)
+		if len(b.seenOrder) > memoryDedupSize {
+			delete(b.seen, b.seenOrder[0])
+			b.seenOrder = b.seenOrder[1:]
+		}
+	}
+	return nil
+}
+
+// รับ event ที่ส่งหลังจากนี้ผ่าน channel (buffer = ขนาดคิวของผู้รับ) เรียก cancel เมื่อเลิกรับ
+func (b *MemoryBus) Subscribe(buffer int) (<-chan Message, func()) {
+	sub := &subscriber{ch: make(chan Message, buffer), done: make(chan struct{})}
+
+	b.mu.Lock()
+	id := b.nextID
+	b.nextID++
+	b.subscribers[id] = sub
+	b.mu.Unlock()
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers, id)
+			b.mu.Unlock()
+			close(sub.done)
+		})
+	}
+	return sub.ch, cancel
+}
+
+func (b *MemoryBus) Close() error {
+	return nil
+}
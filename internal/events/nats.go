@@ -0,0 +1,270 @@
+package events
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// เวลารอคำตอบจาก NATS server ต่อคำสั่ง
+const DefaultNATSTimeout = 5 * time.Second
+
+// NATSPublisher ส่ง event ไปยัง NATS JetStream ที่ subject "<SubjectPrefix>.<type>" (เช่น auth.events.user.registered)
+// พร้อม header Nats-Msg-Id เพื่อให้ JetStream ตัด event ซ้ำภายใน duplicate window ของ stream
+// และรอ ack จาก stream ก่อนถือว่าส่งสำเร็จ (ต้องมี stream ที่รับ subject นี้ ไม่เช่นนั้นจะได้ error "no responders")
+// ใช้ protocol ของ NATS แบบข้อความโดยตรง รองรับ nats://[user:password@]host:port โดยไม่มี TLS
+type NATSPublisher struct {
+	URL           string
+	SubjectPrefix string
+	Timeout       time.Duration
+
+	mu     sync.Mutex
+	conn   net.Conn
+	reader *bufio.Reader
+	inbox  string // subject สำหรับรับ ack ของ JetStream
+	seq    int
+}
+
+// สร้างอินสแตนซ์ของ NATSPublisher (เชื่อมต่อเมื่อส่ง event แรก และเชื่อมต่อใหม่หลังเกิด error)
+func NewNATSPublisher(rawURL string, subjectPrefix string) *NATSPublisher {
+	return &NATSPublisher{URL: rawURL, SubjectPrefix: subjectPrefix, Timeout: DefaultNATSTimeout}
+}
+
+// ack ที่ JetStream ตอบกลับหลังเก็บ event
+type natsPubAck struct {
+	Stream    string `json:"stream"`
+	Sequence  uint64 `json:"seq"`
+	Duplicate bool   `json:"duplicate"`
+	Error     *struct {
+		Code        int    `json:"code"`
+		Description string `json:"description"`
+	} `json:"error"`
+}
+
+func (p *NATSPublisher) Publish(ctx context.Context, msg Message) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.conn == nil {
+		if err := p.connect(ctx); err != nil {
+			return err
+		}
+	}
+	if err := p.publish(ctx, p.SubjectPrefix+"."+msg.Type, msg.ID, data); err != nil {
+		// ไม่รู้สถานะของ connection แล้ว เชื่อมต่อใหม่ในครั้งถัดไป
+		p.closeConn()
+		return err
+	}
+	return nil
+}
+
+func (p *NATSPublisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.closeConn()
+	return nil
+}
+
+func (p *NATSPublisher) connect(ctx context.Context) error {
+	u, err := url.Parse(p.URL)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("nats: invalid URL %q", p.URL)
+	}
+	if u.Scheme != "nats" {
+		return fmt.Errorf("nats: unsupported scheme %q", u.Scheme)
+	}
+	host := u.Host
+	if u.Port() == "" {
+		host = net.JoinHostPort(u.Hostname(), "4222")
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", host)
+	if err != nil {
+		return err
+	}
+	p.conn = conn
+	p.reader = bufio.NewReader(conn)
+	p.setDeadline(ctx)
+
+	// server ส่ง INFO มาก่อนเสมอ
+	line, err := p.readLine()
+	if err != nil {
+		p.closeConn()
+		return err
+	}
+	if !strings.HasPrefix(line, "INFO ") {
+		p.closeConn()
+		return fmt.Errorf("nats: unexpected greeting %q", line)
+	}
+	var info struct {
+		Headers     bool `json:"headers"`
+		TLSRequired bool `json:"tls_required"`
+	}
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "INFO ")), &info); err != nil {
+		p.closeConn()
+		return fmt.Errorf("nats: invalid INFO: %w", err)
+	}
+	if info.TLSRequired || !info.Headers {
+		p.closeConn()
+		return errors.New("nats: server requires TLS or does not support headers")
+	}
+
+	options := map[string]interface{}{
+		"verbose":       false,
+		"pedantic":      false,
+		"headers":       true,
+		"no_responders": true,
+		"protocol":      1,
+		"name":          "auth-microservice",
+		"lang":          "go",
+		"version":       "1.0",
+	}
+	if u.User != nil {
+		options["user"] = u.User.Username()
+		options["pass"], _ = u.User.Password()
+	}
+	connectJSON, _ := json.Marshal(options)
+
+	suffix := make([]byte, 8)
+	rand.Read(suffix)
+	p.inbox = "_INBOX." + hex.EncodeToString(suffix)
+	p.seq = 0
+
+	if _, err := fmt.Fprintf(conn, "CONNECT %s\r\nSUB %s.* 1\r\nPING\r\n", connectJSON, p.inbox); err != nil {
+		p.closeConn()
+		return err
+	}
+	// PONG แรกยืนยันว่า CONNECT (รวมถึงการยืนยันตัวตน) สำเร็จ
+	for {
+		line, err := p.readLine()
+		if err != nil {
+			p.closeConn()
+			return err
+		}
+		switch {
+		case line == "PONG":
+			return nil
+		case strings.HasPrefix(line, "-ERR"):
+			p.closeConn()
+			return fmt.Errorf("nats: %s", strings.TrimSpace(strings.TrimPrefix(line, "-ERR")))
+		}
+	}
+}
+
+// ส่ง HPUB พร้อม reply subject แล้วรอ ack ของ JetStream
+func (p *NATSPublisher) publish(ctx context.Context, subject string, msgID string, data []byte) error {
+	p.setDeadline(ctx)
+	p.seq++
+	reply := p.inbox + "." + strconv.Itoa(p.seq)
+	header := "NATS/1.0\r\nNats-Msg-Id: " + msgID + "\r\n\r\n"
+	if _, err := fmt.Fprintf(p.conn, "HPUB %s %s %d %d\r\n%s%s\r\n", subject, reply, len(header), len(header)+len(data), header, data); err != nil {
+		return err
+	}
+
+	for {
+		line, err := p.readLine()
+		if err != nil {
+			return err
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "PING":
+			if _, err := io.WriteString(p.conn, "PONG\r\n"); err != nil {
+				return err
+			}
+		case "-ERR":
+			return fmt.Errorf("nats: %s", strings.TrimSpace(strings.TrimPrefix(line, "-ERR")))
+		case "MSG", "HMSG":
+			subj, headers, body, err := p.readMessage(fields)
+			if err != nil {
+				return err
+			}
+			if subj != reply {
+				continue // ack ของ event ก่อนหน้าที่หมดเวลารอไปแล้ว
+			}
+			return parsePubAck(headers, body)
+		}
+	}
+}
+
+// อ่าน payload ของ MSG <subject> <sid> [reply] <size> หรือ HMSG <subject> <sid> [reply] <hdr size> <total size>
+func (p *NATSPublisher) readMessage(fields []string) (string, string, []byte, error) {
+	headerSize := 0
+	sizeField := fields[len(fields)-1]
+	if fields[0] == "HMSG" && len(fields) >= 5 {
+		var err error
+		if headerSize, err = strconv.Atoi(fields[len(fields)-2]); err != nil {
+			return "", "", nil, fmt.Errorf("nats: invalid HMSG %q", strings.Join(fields, " "))
+		}
+	}
+	total, err := strconv.Atoi(sizeField)
+	if err != nil || len(fields) < 4 || headerSize > total {
+		return "", "", nil, fmt.Errorf("nats: invalid %s %q", fields[0], strings.Join(fields, " "))
+	}
+	buf := make([]byte, total+2) // รวม \r\n ท้าย payload
+	if _, err := io.ReadFull(p.reader, buf); err != nil {
+		return "", "", nil, err
+	}
+	return fields[1], string(buf[:headerSize]), buf[headerSize:total], nil
+}
+
+// ตรวจ ack: header "NATS/1.0 503" = ไม่มี stream รับ subject นี้
+func parsePubAck(headers string, body []byte) error {
+	if status := strings.Fields(strings.SplitN(headers, "\r\n", 2)[0]); len(status) >= 2 && status[1] == "503" {
+		return errors.New("nats: no responders (no JetStream stream bound to subject)")
+	}
+	var ack natsPubAck
+	if err := json.Unmarshal(body, &ack); err != nil {
+		return fmt.Errorf("nats: invalid publish ack: %w", err)
+	}
+	if ack.Error != nil {
+		return fmt.Errorf("nats: publish rejected (%d): %s", ack.Error.Code, ack.Error.Description)
+	}
+	if ack.Stream == "" {
+		return errors.New("nats: publish ack without stream")
+	}
+	return nil
+}
+
+func (p *NATSPublisher) readLine() (string, error) {
+	line, err := p.reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func (p *NATSPublisher) setDeadline(ctx context.Context) {
+	deadline := time.Now().Add(p.Timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	p.conn.SetDeadline(deadline)
+}
+
+func (p *NATSPublisher) closeConn() {
+	if p.conn != nil {
+		p.conn.Close()
+		p.conn = nil
+		p.reader = nil
+	}
+}
@@ -0,0 +1,350 @@
+package events
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// NATS server ปลอมที่รองรับเฉพาะคำสั่งที่ NATSPublisher ใช้ (INFO, CONNECT, SUB, PING/PONG และ HPUB)
+type fakeNATS struct {
+	URL      string
+	Info     map[string]interface{} // INFO ที่ส่งเมื่อเชื่อมต่อ
+	User     string                 // "" = ไม่ตรวจ user และ password ใน CONNECT
+	Password string
+	// ตอบ HPUB (nil = ack จาก stream "AUTH") เขียนคำสั่งลง conn หรือปิด conn ได้โดยตรง
+	Reply func(conn net.Conn, pub natsPub)
+
+	listener  net.Listener
+	mu        sync.Mutex
+	connects  []map[string]interface{} // options ของ CONNECT แต่ละครั้ง
+	published []natsPub
+	pongs     int // PONG ที่ client ตอบ PING ของ server
+}
+
+// HPUB ที่ server ได้รับ
+type natsPub struct {
+	Subject string
+	Reply   string
+	MsgID   string
+	Data    []byte
+}
+
+// เริ่ม server ปลอม configure (ถ้ามี) ตั้งค่าได้ก่อนเริ่มรับการเชื่อมต่อ หลังจากนั้นเปลี่ยน Reply ด้วย setReply
+func newFakeNATS(t *testing.T, configure func(s *fakeNATS)) *fakeNATS {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	s := &fakeNATS{
+		URL:      "nats://" + listener.Addr().String(),
+		Info:     map[string]interface{}{"server_id": "fake", "version": "2.10.0", "headers": true, "max_payload": 1 << 20},
+		listener: listener,
+	}
+	t.Cleanup(func() { listener.Close() })
+	if configure != nil {
+		configure(s)
+	}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *fakeNATS) serve(conn net.Conn) {
+	defer conn.Close()
+	info, _ := json.Marshal(s.Info)
+	fmt.Fprintf(conn, "INFO %s\r\n", info)
+	reader := bufio.NewReader(conn)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "CONNECT":
+			var options map[string]interface{}
+			json.Unmarshal([]byte(strings.TrimPrefix(line, "CONNECT ")), &options)
+			s.mu.Lock()
+			s.connects = append(s.connects, options)
+			s.mu.Unlock()
+			if s.User != "" && (options["user"] != s.User || options["pass"] != s.Password) {
+				io.WriteString(conn, "-ERR 'Authorization Violation'\r\n")
+				return
+			}
+		case "PING":
+			io.WriteString(conn, "PONG\r\n")
+		case "PONG":
+			s.mu.Lock()
+			s.pongs++
+			s.mu.Unlock()
+		case "HPUB":
+			// HPUB <subject> <reply> <header size> <total size>
+			headerSize, _ := strconv.Atoi(fields[3])
+			total, _ := strconv.Atoi(fields[4])
+			buf := make([]byte, total+2)
+			if _, err := io.ReadFull(reader, buf); err != nil {
+				return
+			}
+			pub := natsPub{Subject: fields[1], Reply: fields[2], Data: buf[headerSize:total]}
+			for _, header := range strings.Split(string(buf[:headerSize]), "\r\n") {
+				if strings.HasPrefix(header, "Nats-Msg-Id: ") {
+					pub.MsgID = strings.TrimPrefix(header, "Nats-Msg-Id: ")
+				}
+			}
+			s.mu.Lock()
+			s.published = append(s.published, pub)
+			seq := len(s.published)
+			reply := s.Reply
+			s.mu.Unlock()
+			if reply == nil {
+				writeAck(conn, pub.Reply, fmt.Sprintf(`{"stream":"AUTH","seq":%d}`, seq))
+				continue
+			}
+			reply(conn, pub)
+		}
+	}
+}
+
+func (s *fakeNATS) setReply(reply func(conn net.Conn, pub natsPub)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Reply = reply
+}
+
+func (s *fakeNATS) stats() (connects []map[string]interface{}, published []natsPub, pongs int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append(connects, s.connects...), append(published, s.published...), s.pongs
+}
+
+// ack ของ JetStream ส่งเป็น MSG ไปยัง reply subject
+func writeAck(conn net.Conn, subject string, body string) {
+	fmt.Fprintf(conn, "MSG %s 1 %d\r\n%s\r\n", subject, len(body), body)
+}
+
+func testMessage(id string) Message {
+	return Message{
+		ID:        id,
+		Type:      "user.registered",
+		TenantID:  "default",
+		CreatedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Data:      json.RawMessage(`{"email":"alice@example.com"}`),
+	}
+}
+
+func TestNATSPublisherPublishesToJetStream(t *testing.T) {
+	server := newFakeNATS(t, func(s *fakeNATS) { s.User, s.Password = "relay", "s3cret" })
+	p := NewNATSPublisher(strings.Replace(server.URL, "nats://", "nats://relay:s3cret@", 1), "auth.events")
+	defer p.Close()
+
+	for _, id := range []string{"evt-1", "evt-2"} {
+		if err := p.Publish(context.Background(), testMessage(id)); err != nil {
+			t.Fatalf("Publish %s: %v", id, err)
+		}
+	}
+
+	connects, published, _ := server.stats()
+	if len(connects) != 1 {
+		t.Fatalf("CONNECT sent %d times, want 1 (connection is reused)", len(connects))
+	}
+	options := connects[0]
+	if options["user"] != "relay" || options["pass"] != "s3cret" || options["headers"] != true ||
+		options["no_responders"] != true || options["verbose"] != false {
+		t.Fatalf("CONNECT options = %v", options)
+	}
+	if len(published) != 2 {
+		t.Fatalf("published %d messages, want 2", len(published))
+	}
+	pub := published[0]
+	if pub.Subject != "auth.events.user.registered" || pub.MsgID != "evt-1" || !strings.HasPrefix(pub.Reply, "_INBOX.") {
+		t.Fatalf("HPUB = %+v", pub)
+	}
+	var got Message
+	if err := json.Unmarshal(pub.Data, &got); err != nil || got.ID != "evt-1" || got.TenantID != "default" || string(got.Data) != `{"email":"alice@example.com"}` {
+		t.Fatalf("payload = %s, %v", pub.Data, err)
+	}
+	if published[1].Reply == pub.Reply {
+		t.Fatalf("both publishes used reply subject %s", pub.Reply)
+	}
+}
+
+func TestNATSPublisherConnectErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(s *fakeNATS) string // คืน URL ที่ใช้เชื่อมต่อ
+		want  string
+	}{
+		{"wrong password", func(s *fakeNATS) string {
+			s.User, s.Password = "relay", "s3cret"
+			return strings.Replace(s.URL, "nats://", "nats://relay:wrong@", 1)
+		}, "Authorization Violation"},
+		{"server without headers", func(s *fakeNATS) string {
+			s.Info["headers"] = false
+			return s.URL
+		}, "does not support headers"},
+		{"server requires TLS", func(s *fakeNATS) string {
+			s.Info["tls_required"] = true
+			return s.URL
+		}, "requires TLS"},
+		{"unsupported scheme", func(s *fakeNATS) string {
+			return strings.Replace(s.URL, "nats://", "tls://", 1)
+		}, "unsupported scheme"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var rawURL string
+			server := newFakeNATS(t, func(s *fakeNATS) { rawURL = tt.setup(s) })
+			p := NewNATSPublisher(rawURL, "auth.events")
+			defer p.Close()
+			if err := p.Publish(context.Background(), testMessage("evt-1")); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Publish = %v, want an error containing %q", err, tt.want)
+			}
+			if _, published, _ := server.stats(); len(published) != 0 {
+				t.Fatalf("published %d messages after a failed handshake", len(published))
+			}
+		})
+	}
+}
+
+func TestNATSPublisherAcks(t *testing.T) {
+	tests := []struct {
+		name    string
+		reply   func(conn net.Conn, pub natsPub)
+		wantErr string // "" = สำเร็จ
+	}{
+		{"ack", func(conn net.Conn, pub natsPub) {
+			writeAck(conn, pub.Reply, `{"stream":"AUTH","seq":1}`)
+		}, ""},
+		{"duplicate ack", func(conn net.Conn, pub natsPub) {
+			writeAck(conn, pub.Reply, `{"stream":"AUTH","seq":1,"duplicate":true}`)
+		}, ""},
+		{"ack after ping", func(conn net.Conn, pub natsPub) {
+			io.WriteString(conn, "PING\r\n")
+			writeAck(conn, pub.Reply, `{"stream":"AUTH","seq":1}`)
+		}, ""},
+		{"stale ack before ack", func(conn net.Conn, pub natsPub) {
+			writeAck(conn, pub.Reply+"0", `{"stream":"AUTH","seq":1}`)
+			writeAck(conn, pub.Reply, `{"stream":"AUTH","seq":2}`)
+		}, ""},
+		{"ack with headers", func(conn net.Conn, pub natsPub) {
+			header, body := "NATS/1.0\r\n\r\n", `{"stream":"AUTH","seq":1}`
+			fmt.Fprintf(conn, "HMSG %s 1 %d %d\r\n%s%s\r\n", pub.Reply, len(header), len(header)+len(body), header, body)
+		}, ""},
+		{"no responders", func(conn net.Conn, pub natsPub) {
+			header := "NATS/1.0 503\r\n\r\n"
+			fmt.Fprintf(conn, "HMSG %s 1 %d %d\r\n%s\r\n", pub.Reply, len(header), len(header), header)
+		}, "no responders"},
+		{"server error", func(conn net.Conn, pub natsPub) {
+			io.WriteString(conn, "-ERR 'Permissions Violation for Publish to auth.events.user.registered'\r\n")
+		}, "Permissions Violation"},
+		{"stream error", func(conn net.Conn, pub natsPub) {
+			writeAck(conn, pub.Reply, `{"error":{"code":503,"description":"stream is offline"}}`)
+		}, "stream is offline"},
+		{"ack without stream", func(conn net.Conn, pub natsPub) {
+			writeAck(conn, pub.Reply, `{}`)
+		}, "without stream"},
+		{"invalid ack", func(conn net.Conn, pub natsPub) {
+			writeAck(conn, pub.Reply, `+OK`)
+		}, "invalid publish ack"},
+		{"no ack", func(conn net.Conn, pub natsPub) {}, "timeout"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newFakeNATS(t, func(s *fakeNATS) { s.Reply = tt.reply })
+			p := NewNATSPublisher(server.URL, "auth.events")
+			p.Timeout = 200 * time.Millisecond
+			defer p.Close()
+
+			err := p.Publish(context.Background(), testMessage("evt-1"))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Publish: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Publish = %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNATSPublisherAnswersPing(t *testing.T) {
+	server := newFakeNATS(t, nil)
+	// ack หลังได้ PONG เท่านั้น เหมือน server ที่ตัด client ที่ไม่ตอบ PING
+	// (รอใน goroutine แยกเพราะ Reply รันใน goroutine ที่อ่าน PONG)
+	server.setReply(func(conn net.Conn, pub natsPub) {
+		io.WriteString(conn, "PING\r\n")
+		go func() {
+			for i := 0; i < 100; i++ {
+				if _, _, pongs := server.stats(); pongs > 0 {
+					writeAck(conn, pub.Reply, `{"stream":"AUTH","seq":1}`)
+					return
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+		}()
+	})
+	p := NewNATSPublisher(server.URL, "auth.events")
+	defer p.Close()
+	if err := p.Publish(context.Background(), testMessage("evt-1")); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if _, _, pongs := server.stats(); pongs != 1 {
+		t.Fatalf("client answered %d PINGs, want 1", pongs)
+	}
+}
+
+func TestNATSPublisherReconnects(t *testing.T) {
+	// server ปิด connection ก่อนตอบ ack ของ event แรก
+	server := newFakeNATS(t, func(s *fakeNATS) { s.Reply = func(conn net.Conn, pub natsPub) { conn.Close() } })
+	p := NewNATSPublisher(server.URL, "auth.events")
+	defer p.Close()
+
+	if err := p.Publish(context.Background(), testMessage("evt-1")); err == nil {
+		t.Fatal("Publish on a closed connection succeeded")
+	}
+	server.setReply(nil)
+	if err := p.Publish(context.Background(), testMessage("evt-1")); err != nil {
+		t.Fatalf("Publish after the connection dropped: %v", err)
+	}
+	// -ERR ทำให้ไม่รู้สถานะของ connection จึงเชื่อมต่อใหม่เช่นกัน
+	server.setReply(func(conn net.Conn, pub natsPub) { io.WriteString(conn, "-ERR 'Maximum Payload Violation'\r\n") })
+	if err := p.Publish(context.Background(), testMessage("evt-2")); err == nil {
+		t.Fatal("Publish after -ERR succeeded")
+	}
+	server.setReply(nil)
+	if err := p.Publish(context.Background(), testMessage("evt-2")); err != nil {
+		t.Fatalf("Publish after -ERR: %v", err)
+	}
+
+	connects, published, _ := server.stats()
+	if len(connects) != 3 {
+		t.Fatalf("CONNECT sent %d times, want 3", len(connects))
+	}
+	// event เดิมส่งซ้ำด้วย Nats-Msg-Id เดิม ให้ JetStream ตัดตัวซ้ำ
+	if len(published) != 4 || published[0].MsgID != "evt-1" || published[1].MsgID != "evt-1" || published[3].MsgID != "evt-2" {
+		t.Fatalf("published = %+v", published)
+	}
+}
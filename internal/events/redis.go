@@ -0,0 +1,63 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ค่าเริ่มต้นของ RedisStreamPublisher
+const (
+	DefaultStreamMaxLen = 100000         // จำนวน entry ที่เก็บใน stream โดยประมาณ
+	DefaultDedupWindow  = 24 * time.Hour // ระยะเวลาที่จำ ID ที่ส่งแล้ว
+)
+
+// เพิ่ม entry เฉพาะเมื่อยังไม่เคยส่ง ID นี้ภายในช่วงเวลาที่จำไว้ (ทำใน script เดียวจึงไม่มีช่องว่างระหว่างสองคำสั่ง)
+var xaddOnce = redis.NewScript(`
+if redis.call('SET', KEYS[2], '1', 'NX', 'PX', ARGV[1]) then
+  return redis.call('XADD', KEYS[1], 'MAXLEN', '~', ARGV[2], '*', 'id', ARGV[3], 'type', ARGV[4], 'message', ARGV[5])
+end
+return false
+`)
+
+// RedisStreamPublisher เพิ่ม event ลง Redis Stream (field: id, type, message = Message แบบ JSON)
+// ผู้รับอ่านด้วย XREADGROUP ได้ และ ID เดิมที่ relay ส่งซ้ำภายใน DedupWindow จะไม่ถูกเพิ่มอีก
+type RedisStreamPublisher struct {
+	Redis       *redis.Client
+	Stream      string
+	MaxLen      int64
+	DedupWindow time.Duration
+}
+
+// สร้างอินสแตนซ์ของ RedisStreamPublisher
+func NewRedisStreamPublisher(rdb *redis.Client, stream string) *RedisStreamPublisher {
+	return &RedisStreamPublisher{
+		Redis:       rdb,
+		Stream:      stream,
+		MaxLen:      DefaultStreamMaxLen,
+		DedupWindow: DefaultDedupWindow,
+	}
+}
+
+func (p *RedisStreamPublisher) Publish(ctx context.Context, msg Message) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	keys := []string{p.Stream, p.Stream + ":published:" + msg.ID}
+	err = xaddOnce.Run(ctx, p.Redis, keys,
+		p.DedupWindow.Milliseconds(), strconv.FormatInt(p.MaxLen, 10), msg.ID, msg.Type, string(data)).Err()
+	if errors.Is(err, redis.Nil) {
+		// เคยส่ง ID นี้แล้ว
+		return nil
+	}
+	return err
+}
+
+func (p *RedisStreamPublisher) Close() error {
+	return p.Redis.Close()
+}
@@ -0,0 +1,121 @@
+package events
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"auth-microservice/internal/store"
+)
+
+// ค่าเริ่มต้นของ Relay
+const (
+	DefaultRelayBatchSize  = 100
+	DefaultRelayBackoff    = 5 * time.Second // ระยะรอก่อนลองใหม่ครั้งแรก (เพิ่มเป็นสองเท่าทุกครั้ง)
+	DefaultRelayMaxWait    = 10 * time.Minute
+	DefaultRelayLease      = time.Minute        // relay อื่นจะไม่ได้ event ที่จองไว้จนกว่า lease จะหมด
+	DefaultOutboxRetention = 7 * 24 * time.Hour // เก็บ event ที่เผยแพร่แล้วไว้ก่อนลบ
+)
+
+// Relay อ่าน event ที่ยังไม่เผยแพร่จาก outbox ส่งผ่าน Publisher ตามลำดับ แล้วบันทึกว่าเผยแพร่แล้ว
+// ถ้า process หยุดหลังส่งแต่ก่อนบันทึก event จะถูกส่งซ้ำ (at-least-once) ผู้รับใช้ Message.ID กันการประมวลผลซ้ำ
+type Relay struct {
+	Store       store.OutboxStore
+	Publisher   Publisher
+	BatchSize   int
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	Lease       time.Duration
+	Retention   time.Duration
+
+	lastPurge time.Time
+}
+
+// สร้างอินสแตนซ์ของ Relay ด้วยค่าเริ่มต้น
+func NewRelay(outbox store.OutboxStore, publisher Publisher) *Relay {
+	return &Relay{
+		Store:       outbox,
+		Publisher:   publisher,
+		BatchSize:   DefaultRelayBatchSize,
+		BaseBackoff: DefaultRelayBackoff,
+		MaxBackoff:  DefaultRelayMaxWait,
+		Lease:       DefaultRelayLease,
+		Retention:   DefaultOutboxRetention,
+	}
+}
+
+// รันการเผยแพร่เป็นรอบ ๆ จนกว่า ctx จะถูกยกเลิก
+func (r *Relay) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := r.PublishPending(ctx); err != nil {
+			log.Printf("Outbox relay failed: %v", err)
+		}
+		if time.Since(r.lastPurge) > time.Hour {
+			r.lastPurge = time.Now()
+			if _, err := r.Store.PurgePublishedOutbox(ctx, time.Now().Add(-r.Retention)); err != nil {
+				log.Printf("Could not purge outbox: %v", err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// เผยแพร่ event ที่ถึงเวลาแล้วทั้งหมด คืนจำนวนที่เผยแพร่สำเร็จ
+func (r *Relay) PublishPending(ctx context.Context) (int, error) {
+	published := 0
+	for ctx.Err() == nil {
+		now := time.Now()
+		claimed, err := r.Store.ClaimOutboxEvents(ctx, now, now.Add(r.Lease), r.BatchSize)
+		if err != nil {
+			return published, err
+		}
+		for i, ev := range claimed {
+			if err := r.Publisher.Publish(ctx, FromOutbox(ev)); err != nil {
+				// หยุดชุดนี้และเลื่อน event ที่เหลือไปพร้อมกัน เพื่อรักษาลำดับและไม่รอ broker ที่ล่มซ้ำทีละรายการ
+				attempts := ev.Attempts + 1
+				retryAt := time.Now().Add(r.backoff(attempts))
+				r.markFailed(ctx, ev.ID.Hex(), attempts, retryAt, err.Error())
+				for _, rest := range claimed[i+1:] {
+					r.markFailed(ctx, rest.ID.Hex(), rest.Attempts, retryAt, rest.LastError)
+				}
+				return published, err
+			}
+			if err := r.Store.MarkOutboxPublished(ctx, ev.ID.Hex(), time.Now()); err != nil {
+				// event จะถูกส่งซ้ำหลัง lease หมด
+				log.Printf("Could not mark outbox event %s as published: %v", ev.ID.Hex(), err)
+				continue
+			}
+			published++
+		}
+		if len(claimed) < r.BatchSize {
+			break
+		}
+	}
+	return published, ctx.Err()
+}
+
+func (r *Relay) markFailed(ctx context.Context, id string, attempts int, retryAt time.Time, lastError string) {
+	if err := r.Store.MarkOutboxFailed(ctx, id, attempts, retryAt, lastError); err != nil {
+		log.Printf("Could not record outbox failure %s: %v", id, err)
+	}
+}
+
+// ระยะรอก่อนลองใหม่หลังส่งไม่สำเร็จครั้งที่ attempts (ไม่มี dead letter ลองต่อไปเรื่อย ๆ ทุก MaxBackoff)
+func (r *Relay) backoff(attempts int) time.Duration {
+	wait := r.BaseBackoff
+	for i := 1; i < attempts && wait < r.MaxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > r.MaxBackoff {
+		wait = r.MaxBackoff
+	}
+	return wait
+}
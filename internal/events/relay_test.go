@@ -0,0 +1,175 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/store"
+	"auth-microservice/internal/store/sqlstore"
+)
+
+// outbox บน SQLite ในไดเรกทอรีชั่วคราว
+func newTestOutbox(t *testing.T) store.OutboxStore {
+	t.Helper()
+	ctx := context.Background()
+	params := url.Values{}
+	params.Add("_pragma", "busy_timeout(5000)")
+	sqlStore, err := sqlstore.Open(ctx, sqlstore.SQLite, "file:"+filepath.Join(t.TempDir(), "auth.db")+"?"+params.Encode())
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if _, err := sqlStore.Migrator().Up(ctx); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	stores := sqlStore.Stores()
+	t.Cleanup(func() { stores.Close(context.Background()) })
+	return stores.Outbox
+}
+
+// บันทึก event "token.revoked" n รายการลง outbox
+func addEvents(t *testing.T, outbox store.OutboxStore, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		now := time.Now()
+		ev := &models.OutboxEvent{
+			TenantID:      "default",
+			Type:          "token.revoked",
+			Payload:       fmt.Sprintf(`{"n":%d}`, i),
+			CreatedAt:     now,
+			NextAttemptAt: now,
+		}
+		if err := outbox.AddTokenWithEvent(context.Background(), fmt.Sprintf("token-%d", i), now.Add(time.Hour), ev); err != nil {
+			t.Fatalf("AddTokenWithEvent: %v", err)
+		}
+	}
+}
+
+func listEvents(t *testing.T, outbox store.OutboxStore) []models.OutboxEvent {
+	t.Helper()
+	events, err := outbox.ListOutboxEvents(context.Background(), store.OutboxListQuery{TenantID: "default", Limit: 100})
+	if err != nil {
+		t.Fatalf("ListOutboxEvents: %v", err)
+	}
+	return events
+}
+
+// Publisher ที่ส่งต่อให้ MemoryBus และล้มเหลว fail ครั้งแรก
+type flakyPublisher struct {
+	*MemoryBus
+	mu   sync.Mutex
+	fail int
+}
+
+func (p *flakyPublisher) Publish(ctx context.Context, msg Message) error {
+	p.mu.Lock()
+	if p.fail > 0 {
+		p.fail--
+		p.mu.Unlock()
+		return errors.New("broker unavailable")
+	}
+	p.mu.Unlock()
+	return p.MemoryBus.Publish(ctx, msg)
+}
+
+// อ่าน ID ของ event ที่ผู้รับได้จนครบ n รายการ
+func receive(t *testing.T, ch <-chan Message, n int) []string {
+	t.Helper()
+	var ids []string
+	for len(ids) < n {
+		select {
+		case msg := <-ch:
+			ids = append(ids, msg.ID)
+		case <-time.After(time.Second):
+			t.Fatalf("received %d events, want %d", len(ids), n)
+		}
+	}
+	return ids
+}
+
+func TestRelayPublishesPendingEvents(t *testing.T) {
+	outbox := newTestOutbox(t)
+	addEvents(t, outbox, 5)
+	bus := NewMemoryBus()
+	ch, cancel := bus.Subscribe(10)
+	defer cancel()
+
+	relay := NewRelay(outbox, bus)
+	relay.BatchSize = 2 // หลายชุดต่อรอบ
+	published, err := relay.PublishPending(context.Background())
+	if err != nil || published != 5 {
+		t.Fatalf("PublishPending = %d, %v, want 5", published, err)
+	}
+
+	events := listEvents(t, outbox)
+	ids := receive(t, ch, 5)
+	for i, ev := range events {
+		if ev.PublishedAt == nil {
+			t.Errorf("event %s is not marked as published", ev.ID.Hex())
+		}
+		if ids[i] != ev.ID.Hex() {
+			t.Errorf("event %d delivered as %s, want %s (outbox order)", i, ids[i], ev.ID.Hex())
+		}
+	}
+
+	if published, err := relay.PublishPending(context.Background()); err != nil || published != 0 {
+		t.Fatalf("second PublishPending = %d, %v, want nothing left", published, err)
+	}
+}
+
+func TestRelayRetriesAfterPublishError(t *testing.T) {
+	outbox := newTestOutbox(t)
+	addEvents(t, outbox, 2)
+	publisher := &flakyPublisher{MemoryBus: NewMemoryBus(), fail: 1}
+	ch, cancel := publisher.Subscribe(10)
+	defer cancel()
+
+	relay := NewRelay(outbox, publisher)
+	relay.BaseBackoff = 300 * time.Millisecond
+	published, err := relay.PublishPending(context.Background())
+	if err == nil || published != 0 {
+		t.Fatalf("PublishPending with the broker down = %d, %v, want an error", published, err)
+	}
+	events := listEvents(t, outbox)
+	first, second := events[0], events[1]
+	if first.PublishedAt != nil || first.Attempts != 1 || first.LastError != "broker unavailable" || !first.NextAttemptAt.After(time.Now()) {
+		t.Fatalf("failed event = %+v, want attempt 1 scheduled for later", first)
+	}
+	// event ที่ยังไม่ได้ส่งเลื่อนไปพร้อมกันโดยไม่นับเป็นความพยายาม
+	if second.PublishedAt != nil || second.Attempts != 0 || !second.NextAttemptAt.Equal(first.NextAttemptAt) {
+		t.Fatalf("pending event = %+v, want it rescheduled with the failed one", second)
+	}
+
+	// ยังไม่ถึงเวลาลองใหม่
+	if published, err := relay.PublishPending(context.Background()); err != nil || published != 0 {
+		t.Fatalf("PublishPending before the backoff = %d, %v, want nothing", published, err)
+	}
+	time.Sleep(time.Until(first.NextAttemptAt) + 50*time.Millisecond)
+	if published, err := relay.PublishPending(context.Background()); err != nil || published != 2 {
+		t.Fatalf("PublishPending after the backoff = %d, %v, want 2", published, err)
+	}
+	if ids := receive(t, ch, 2); ids[0] != first.ID.Hex() || ids[1] != second.ID.Hex() {
+		t.Fatalf("delivered %v, want the events in outbox order", ids)
+	}
+	for _, ev := range listEvents(t, outbox) {
+		if ev.PublishedAt == nil {
+			t.Fatalf("event %s is not marked as published after the retry", ev.ID.Hex())
+		}
+	}
+}
+
+func TestRelayBackoff(t *testing.T) {
+	r := &Relay{BaseBackoff: 5 * time.Second, MaxBackoff: time.Minute}
+	want := []time.Duration{5 * time.Second, 10 * time.Second, 20 * time.Second, 40 * time.Second, time.Minute, time.Minute}
+	for i, w := range want {
+		if got := r.backoff(i + 1); got != w {
+			t.Errorf("backoff(%d) = %v, want %v", i+1, got, w)
+		}
+	}
+}
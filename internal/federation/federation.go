@@ -0,0 +1,40 @@
+// Package federation เชื่อมต่อกับ identity provider ภายนอกเพื่อให้ผู้ใช้เข้าสู่ระบบด้วยบัญชีขององค์กร
+package federation
+
+import (
+	"context"
+	"errors"
+)
+
+// ข้อมูลผู้ใช้ที่ได้จาก identity provider หลังตรวจสอบ ID token หรือ SAML assertion แล้ว
+type Identity struct {
+	Subject           string // sub (ไม่ซ้ำกันภายใน provider เดียว)
+	Email             string
+	EmailVerified     bool
+	Name              string
+	PreferredUsername string
+	Picture           string
+	Groups            []string // กลุ่มของผู้ใช้ตาม provider (เฉพาะ provider ที่ส่งมา เช่น attribute กลุ่มของ SAML)
+}
+
+// การตั้งค่าของ client ที่ลงทะเบียนไว้กับ identity provider
+type Config struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string // ค่าว่าง = public client (ใช้ PKCE อย่างเดียว)
+	RedirectURI  string
+	Scopes       []string
+	AllowLocal   bool // ยอมให้เชื่อมต่อ issuer บน localhost และที่อยู่ภายใน (สำหรับ dev เท่านั้น)
+}
+
+// Connector คือ identity provider ภายนอกที่ยืนยันตัวตนผู้ใช้ด้วย authorization code flow + PKCE
+// เปลี่ยน implementation ได้ (เช่น provider ที่ไม่รองรับ OpenID Connect)
+type Connector interface {
+	// URL ของ provider ที่ต้องส่งผู้ใช้ไปเข้าสู่ระบบ
+	AuthCodeURL(ctx context.Context, state string, nonce string, codeChallenge string) (string, error)
+	// แลก code เป็นข้อมูลผู้ใช้ (ตรวจสอบลายเซ็น, iss, aud, อายุ และ nonce ของ ID token แล้ว)
+	Exchange(ctx context.Context, code string, codeVerifier string, nonce string) (*Identity, error)
+}
+
+// provider ปฏิเสธการแลก code หรือ ID token / SAML assertion ไม่ผ่านการตรวจสอบ
+var ErrInvalidGrant = errors.New("federation: invalid grant")
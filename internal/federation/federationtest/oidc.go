@@ -0,0 +1,211 @@
+// Package federationtest มี identity provider ปลอมที่รันใน process สำหรับทดสอบการเข้าสู่ระบบผ่าน provider ภายนอก
+package federationtest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"auth-microservice/internal/auth"
+)
+
+// OIDCIssuer คือ OpenID Connect provider ปลอมบน httptest มี discovery document, JWKS,
+// authorization endpoint (อนุญาตทันทีในนามของผู้ใช้ที่ตั้งด้วย SetUser) และ token endpoint ที่ตรวจ client secret และ PKCE
+type OIDCIssuer struct {
+	Server       *httptest.Server
+	URL          string // issuer (ไม่มี / ท้าย)
+	ClientID     string
+	ClientSecret string
+	Key          *rsa.PrivateKey // key ที่เผยแพร่ใน JWKS
+	KeyID        string
+	// แก้ claims ของ ID token ก่อนเซ็น (nil = ไม่แก้) ใช้ทดสอบ ID token ที่ไม่ถูกต้อง
+	TamperClaims func(claims map[string]interface{})
+	// key ที่ใช้เซ็น ID token (nil = Key) ใช้ทดสอบลายเซ็นที่ไม่ตรงกับ JWKS
+	SignWith *rsa.PrivateKey
+	// แก้ discovery document ก่อนส่ง (nil = ไม่แก้) ใช้ทดสอบ endpoint ที่ไม่ถูกต้อง
+	TamperDiscovery func(doc map[string]interface{})
+
+	mu    sync.Mutex
+	user  map[string]interface{} // claims ของผู้ใช้ที่เข้าสู่ระบบอยู่ที่ provider (sub, email, email_verified, ...)
+	codes map[string]issuedCode
+}
+
+// code ที่ออกไปแล้วพร้อมข้อมูลจาก authorization request
+type issuedCode struct {
+	redirectURI string
+	challenge   string
+	nonce       string
+	user        map[string]interface{}
+}
+
+// เริ่ม provider ปลอมพร้อม RSA key ใหม่ (ปิดเมื่อจบการทดสอบ)
+func NewOIDCIssuer(t *testing.T, clientID string, clientSecret string) *OIDCIssuer {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate issuer key: %v", err)
+	}
+	i := &OIDCIssuer{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		Key:          key,
+		KeyID:        "test-key",
+		codes:        map[string]issuedCode{},
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", i.handleDiscovery)
+	mux.HandleFunc("GET /jwks", i.handleJWKS)
+	mux.HandleFunc("GET /authorize", i.handleAuthorize)
+	mux.HandleFunc("POST /token", i.handleToken)
+	i.Server = httptest.NewServer(mux)
+	i.URL = i.Server.URL
+	t.Cleanup(i.Server.Close)
+	return i
+}
+
+// ตั้งผู้ใช้ที่เข้าสู่ระบบอยู่ที่ provider
+func (i *OIDCIssuer) SetUser(claims map[string]interface{}) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.user = claims
+}
+
+// จำลองผู้ใช้เปิด URL จาก AuthCodeURL: provider redirect กลับพร้อม code และ state แล้วคืน code
+func (i *OIDCIssuer) Authorize(t *testing.T, authURL string, wantState string) string {
+	t.Helper()
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	resp, err := client.Get(authURL)
+	if err != nil {
+		t.Fatalf("open authorization URL: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusFound {
+		t.Fatalf("authorization endpoint answered %d, want a redirect", resp.StatusCode)
+	}
+	callback, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		t.Fatalf("callback URL: %v", err)
+	}
+	if got := callback.Query().Get("state"); got != wantState {
+		t.Fatalf("callback state = %q, want %q", got, wantState)
+	}
+	return callback.Query().Get("code")
+}
+
+func (i *OIDCIssuer) handleDiscovery(w http.ResponseWriter, r *http.Request) {
+	doc := map[string]interface{}{
+		"issuer":                 i.URL,
+		"authorization_endpoint": i.URL + "/authorize",
+		"token_endpoint":         i.URL + "/token",
+		"jwks_uri":               i.URL + "/jwks",
+	}
+	if i.TamperDiscovery != nil {
+		i.TamperDiscovery(doc)
+	}
+	writeJSON(w, http.StatusOK, doc)
+}
+
+func (i *OIDCIssuer) handleJWKS(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"keys": []interface{}{auth.PublicJWK(i.KeyID, &i.Key.PublicKey)},
+	})
+}
+
+func (i *OIDCIssuer) handleAuthorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if q.Get("response_type") != "code" || q.Get("client_id") != i.ClientID ||
+		q.Get("code_challenge_method") != "S256" || q.Get("code_challenge") == "" {
+		http.Error(w, "invalid authorization request", http.StatusBadRequest)
+		return
+	}
+	redirect, err := url.Parse(q.Get("redirect_uri"))
+	if err != nil || redirect.Host == "" {
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+
+	code := randomString()
+	i.mu.Lock()
+	i.codes[code] = issuedCode{redirectURI: q.Get("redirect_uri"), challenge: q.Get("code_challenge"), nonce: q.Get("nonce"), user: i.user}
+	i.mu.Unlock()
+
+	params := redirect.Query()
+	params.Set("code", code)
+	params.Set("state", q.Get("state"))
+	redirect.RawQuery = params.Encode()
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+func (i *OIDCIssuer) handleToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]interface{}{"error": "invalid_request"})
+		return
+	}
+	clientID, secret, _ := r.BasicAuth()
+	clientID, _ = url.QueryUnescape(clientID)
+	secret, _ = url.QueryUnescape(secret)
+	if clientID != i.ClientID || secret != i.ClientSecret {
+		writeJSON(w, http.StatusUnauthorized, map[string]interface{}{"error": "invalid_client"})
+		return
+	}
+
+	// code ใช้ได้ครั้งเดียว
+	i.mu.Lock()
+	issued, ok := i.codes[r.PostForm.Get("code")]
+	delete(i.codes, r.PostForm.Get("code"))
+	i.mu.Unlock()
+	if !ok || r.PostForm.Get("grant_type") != "authorization_code" || r.PostForm.Get("redirect_uri") != issued.redirectURI ||
+		!auth.VerifyPKCE(r.PostForm.Get("code_verifier"), issued.challenge) {
+		writeJSON(w, http.StatusBadRequest, map[string]interface{}{"error": "invalid_grant"})
+		return
+	}
+
+	now := time.Now()
+	claims := map[string]interface{}{
+		"iss":   i.URL,
+		"aud":   i.ClientID,
+		"iat":   now.Unix(),
+		"exp":   now.Add(5 * time.Minute).Unix(),
+		"nonce": issued.nonce,
+	}
+	for name, value := range issued.user {
+		claims[name] = value
+	}
+	if i.TamperClaims != nil {
+		i.TamperClaims(claims)
+	}
+	signWith := i.SignWith
+	if signWith == nil {
+		signWith = i.Key
+	}
+	idToken, err := auth.SignIDToken(claims, i.KeyID, signWith)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]interface{}{"error": "server_error"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": randomString(),
+		"token_type":   "Bearer",
+		"expires_in":   300,
+		"id_token":     idToken,
+	})
+}
+
+func randomString() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func writeJSON(w http.ResponseWriter, code int, body map[string]interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,295 @@
+package federationtest
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"io"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"auth-microservice/internal/federation"
+
+	"github.com/beevik/etree"
+	dsig "github.com/russellhaering/goxmldsig"
+)
+
+const (
+	samlProtocolNS = "urn:oasis:names:tc:SAML:2.0:protocol"
+	samlAssertNS   = "urn:oasis:names:tc:SAML:2.0:assertion"
+	samlMetadataNS = "urn:oasis:names:tc:SAML:2.0:metadata"
+)
+
+// SAMLIdP คือ SAML 2.0 IdP ปลอมที่มี key pair สร้างใหม่ทุกครั้ง ใช้สร้าง metadata และ Response ที่เซ็นแล้ว
+// (ไม่มี server เพราะ IdP ส่ง Response ผ่าน browser ของผู้ใช้ ทดสอบได้ด้วยการส่ง Response ไปที่ ACS โดยตรง)
+type SAMLIdP struct {
+	EntityID    string
+	SSOURL      string
+	Key         *rsa.PrivateKey
+	Certificate *x509.Certificate
+}
+
+// สร้าง IdP พร้อม RSA key และ certificate แบบ self-signed ใหม่
+func NewSAMLIdP(t *testing.T, entityID string, ssoURL string) *SAMLIdP {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate IdP key: %v", err)
+	}
+	cert, err := federation.SAMLCertificate(key, entityID)
+	if err != nil {
+		t.Fatalf("generate IdP certificate: %v", err)
+	}
+	return &SAMLIdP{EntityID: entityID, SSOURL: ssoURL, Key: key, Certificate: cert}
+}
+
+// certificate ของ IdP แบบ PEM
+func (idp *SAMLIdP) CertificatePEM() string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: idp.Certificate.Raw}))
+}
+
+// IdP metadata (EntityDescriptor ที่มี IDPSSODescriptor)
+func (idp *SAMLIdP) Metadata() string {
+	doc := etree.NewDocument()
+	entity := doc.CreateElement("md:EntityDescriptor")
+	entity.CreateAttr("xmlns:md", samlMetadataNS)
+	entity.CreateAttr("xmlns:ds", "http://www.w3.org/2000/09/xmldsig#")
+	entity.CreateAttr("entityID", idp.EntityID)
+	descriptor := entity.CreateElement("md:IDPSSODescriptor")
+	descriptor.CreateAttr("protocolSupportEnumeration", samlProtocolNS)
+	key := descriptor.CreateElement("md:KeyDescriptor")
+	key.CreateAttr("use", "signing")
+	key.CreateElement("ds:KeyInfo").CreateElement("ds:X509Data").CreateElement("ds:X509Certificate").
+		SetText(base64.StdEncoding.EncodeToString(idp.Certificate.Raw))
+	for _, binding := range []string{"urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST", "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect"} {
+		sso := descriptor.CreateElement("md:SingleSignOnService")
+		sso.CreateAttr("Binding", binding)
+		sso.CreateAttr("Location", idp.SSOURL)
+	}
+	s, _ := doc.WriteToString()
+	return s
+}
+
+// เนื้อหาของ assertion ที่ IdP ออกให้ (ค่าว่างของ NotBefore/NotOnOrAfter = ตอนนี้ ± 5 นาที)
+type SAMLAssertion struct {
+	ID           string // ว่าง = สุ่ม
+	Issuer       string // ว่าง = EntityID ของ IdP
+	NameID       string
+	NameIDFormat string // ว่าง = persistent
+	Audience     string
+	Recipient    string // ACS ของ SP
+	InResponseTo string // ว่าง = IdP-initiated
+	Destination  string // Destination ของ Response (ว่าง = ไม่ระบุ)
+	NotBefore    time.Time
+	NotOnOrAfter time.Time
+	Attributes   map[string][]string
+	StatusCode   string // ว่าง = Success
+}
+
+// วิธีเซ็นของ Response
+type SAMLSigning int
+
+const (
+	SignAssertion SAMLSigning = iota // เซ็นเฉพาะ assertion (แบบที่ IdP ส่วนใหญ่ใช้)
+	SignResponse                     // เซ็นทั้ง Response
+	SignNothing                      // ไม่เซ็น
+)
+
+// Response ที่มี assertion เดียว เข้ารหัสแบบ base64 เหมือนค่า SAMLResponse ที่ IdP POST มาที่ ACS
+// signWith เป็น nil = เซ็นด้วย key ของ IdP
+func (idp *SAMLIdP) Response(t *testing.T, a SAMLAssertion, signing SAMLSigning, signWith *rsa.PrivateKey) string {
+	t.Helper()
+	return base64.StdEncoding.EncodeToString(idp.ResponseXML(t, a, signing, signWith))
+}
+
+// Response แบบ XML (ก่อนเข้ารหัส base64) สำหรับทดสอบการแก้ไขหลังเซ็น
+func (idp *SAMLIdP) ResponseXML(t *testing.T, a SAMLAssertion, signing SAMLSigning, signWith *rsa.PrivateKey) []byte {
+	t.Helper()
+	if signWith == nil {
+		signWith = idp.Key
+	}
+	signer, err := dsig.NewSigningContext(signWith, [][]byte{idp.Certificate.Raw})
+	if err != nil {
+		t.Fatalf("signing context: %v", err)
+	}
+	signer.Canonicalizer = dsig.MakeC14N10ExclusiveCanonicalizerWithPrefixList("")
+
+	now := time.Now().UTC()
+	if a.ID == "" {
+		a.ID = "_" + randomString()
+	}
+	if a.Issuer == "" {
+		a.Issuer = idp.EntityID
+	}
+	if a.NameIDFormat == "" {
+		a.NameIDFormat = federation.SAMLNameIDPersistent
+	}
+	if a.NotBefore.IsZero() {
+		a.NotBefore = now.Add(-5 * time.Minute)
+	}
+	if a.NotOnOrAfter.IsZero() {
+		a.NotOnOrAfter = now.Add(5 * time.Minute)
+	}
+	if a.StatusCode == "" {
+		a.StatusCode = "urn:oasis:names:tc:SAML:2.0:status:Success"
+	}
+
+	assertion := etree.NewElement("saml:Assertion")
+	assertion.CreateAttr("xmlns:saml", samlAssertNS)
+	assertion.CreateAttr("ID", a.ID)
+	assertion.CreateAttr("Version", "2.0")
+	assertion.CreateAttr("IssueInstant", now.Format(time.RFC3339))
+	assertion.CreateElement("saml:Issuer").SetText(a.Issuer)
+	subject := assertion.CreateElement("saml:Subject")
+	nameID := subject.CreateElement("saml:NameID")
+	nameID.CreateAttr("Format", a.NameIDFormat)
+	nameID.SetText(a.NameID)
+	confirmation := subject.CreateElement("saml:SubjectConfirmation")
+	confirmation.CreateAttr("Method", "urn:oasis:names:tc:SAML:2.0:cm:bearer")
+	data := confirmation.CreateElement("saml:SubjectConfirmationData")
+	data.CreateAttr("Recipient", a.Recipient)
+	data.CreateAttr("NotOnOrAfter", a.NotOnOrAfter.Format(time.RFC3339))
+	if a.InResponseTo != "" {
+		data.CreateAttr("InResponseTo", a.InResponseTo)
+	}
+	conditions := assertion.CreateElement("saml:Conditions")
+	conditions.CreateAttr("NotBefore", a.NotBefore.Format(time.RFC3339))
+	conditions.CreateAttr("NotOnOrAfter", a.NotOnOrAfter.Format(time.RFC3339))
+	conditions.CreateElement("saml:AudienceRestriction").CreateElement("saml:Audience").SetText(a.Audience)
+	authn := assertion.CreateElement("saml:AuthnStatement")
+	authn.CreateAttr("AuthnInstant", now.Format(time.RFC3339))
+	if len(a.Attributes) > 0 {
+		statement := assertion.CreateElement("saml:AttributeStatement")
+		for name, values := range a.Attributes {
+			attr := statement.CreateElement("saml:Attribute")
+			attr.CreateAttr("Name", name)
+			for _, v := range values {
+				attr.CreateElement("saml:AttributeValue").SetText(v)
+			}
+		}
+	}
+	if signing == SignAssertion {
+		if assertion, err = signer.SignEnveloped(assertion); err != nil {
+			t.Fatalf("sign assertion: %v", err)
+		}
+	}
+
+	response := etree.NewElement("samlp:Response")
+	response.CreateAttr("xmlns:samlp", samlProtocolNS)
+	response.CreateAttr("xmlns:saml", samlAssertNS)
+	response.CreateAttr("ID", "_"+randomString())
+	response.CreateAttr("Version", "2.0")
+	response.CreateAttr("IssueInstant", now.Format(time.RFC3339))
+	if a.Destination != "" {
+		response.CreateAttr("Destination", a.Destination)
+	}
+	if a.InResponseTo != "" {
+		response.CreateAttr("InResponseTo", a.InResponseTo)
+	}
+	response.CreateElement("saml:Issuer").SetText(a.Issuer)
+	response.CreateElement("samlp:Status").CreateElement("samlp:StatusCode").CreateAttr("Value", a.StatusCode)
+	response.AddChild(assertion)
+	if signing == SignResponse {
+		if response, err = signer.SignEnveloped(response); err != nil {
+			t.Fatalf("sign response: %v", err)
+		}
+	}
+
+	doc := etree.NewDocument()
+	doc.SetRoot(response)
+	raw, err := doc.WriteToBytes()
+	if err != nil {
+		t.Fatalf("write response: %v", err)
+	}
+	return raw
+}
+
+// AuthnRequest ที่ SP ส่งมาใน URL แบบ HTTP-Redirect binding
+type SAMLAuthnRequest struct {
+	ID          string
+	Issuer      string
+	ACSURL      string
+	Destination string
+	RelayState  string
+}
+
+// อ่าน AuthnRequest จาก URL ที่ SP ส่งผู้ใช้มา และตรวจลายเซ็นของ query string ด้วย certificate ของ SP
+func (idp *SAMLIdP) ParseAuthnRequest(t *testing.T, authURL string, spCertificate *x509.Certificate) SAMLAuthnRequest {
+	t.Helper()
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatalf("AuthnRequest URL: %v", err)
+	}
+	if got := u.Scheme + "://" + u.Host + u.Path; got != idp.SSOURL {
+		t.Fatalf("AuthnRequest sent to %s, want %s", got, idp.SSOURL)
+	}
+
+	// ลายเซ็นครอบคลุม SAMLRequest, RelayState และ SigAlg ตามค่าที่ส่งมา (ยังไม่ decode) ตามลำดับนี้
+	raw := map[string]string{}
+	for _, param := range strings.Split(u.RawQuery, "&") {
+		name, value, _ := strings.Cut(param, "=")
+		raw[name] = value
+	}
+	var signedParams []string
+	for _, name := range []string{"SAMLRequest", "RelayState", "SigAlg"} {
+		if value, ok := raw[name]; ok {
+			signedParams = append(signedParams, name+"="+value)
+		}
+	}
+	signed := strings.Join(signedParams, "&")
+	sig, err := url.QueryUnescape(raw["Signature"])
+	if err != nil || sig == "" {
+		t.Fatal("AuthnRequest is not signed")
+	}
+	sigBytes, err := base64.StdEncoding.DecodeString(sig)
+	if err != nil {
+		t.Fatalf("Signature: %v", err)
+	}
+	digest := sha256.Sum256([]byte(signed))
+	pub, ok := spCertificate.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		t.Fatal("SP certificate does not hold an RSA key")
+	}
+	if err := rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], sigBytes); err != nil {
+		t.Fatalf("AuthnRequest signature: %v", err)
+	}
+
+	q := u.Query()
+	if q.Get("SigAlg") != "http://www.w3.org/2001/04/xmldsig-more#rsa-sha256" {
+		t.Fatalf("SigAlg = %q", q.Get("SigAlg"))
+	}
+	deflated, err := base64.StdEncoding.DecodeString(q.Get("SAMLRequest"))
+	if err != nil {
+		t.Fatalf("SAMLRequest: %v", err)
+	}
+	request, err := io.ReadAll(flate.NewReader(bytes.NewReader(deflated)))
+	if err != nil {
+		t.Fatalf("inflate SAMLRequest: %v", err)
+	}
+	doc := etree.NewDocument()
+	if err := doc.ReadFromBytes(request); err != nil {
+		t.Fatalf("SAMLRequest XML: %v", err)
+	}
+	root := doc.Root()
+	if root == nil || root.Tag != "AuthnRequest" || root.NamespaceURI() != samlProtocolNS {
+		t.Fatalf("SAMLRequest is not an AuthnRequest: %s", request)
+	}
+	req := SAMLAuthnRequest{
+		ID:          root.SelectAttrValue("ID", ""),
+		ACSURL:      root.SelectAttrValue("AssertionConsumerServiceURL", ""),
+		Destination: root.SelectAttrValue("Destination", ""),
+		RelayState:  q.Get("RelayState"),
+	}
+	if issuer := root.SelectElement("Issuer"); issuer != nil {
+		req.Issuer = issuer.Text()
+	}
+	return req
+}
@@ -0,0 +1,316 @@
+package federation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"auth-microservice/internal/auth"
+	"auth-microservice/internal/netguard"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	metadataTTL     = time.Hour        // อ่าน discovery document และ JWKS ใหม่ทุกชั่วโมง
+	keyRefreshDelay = time.Minute      // kid ที่ไม่รู้จัก (provider เพิ่งเปลี่ยน key) อ่าน JWKS ใหม่ได้ไม่เกินนาทีละครั้ง
+	clockSkew       = time.Minute      // เผื่อเวลาของ provider ไม่ตรงกับเครื่องนี้
+	maxResponseSize = 1 << 20          // ขนาด response สูงสุดจาก provider
+	requestTimeout  = 10 * time.Second // เวลารอ provider แต่ละครั้ง
+	maxRedirects    = 5                // จำนวน redirect สูงสุดต่อ request
+)
+
+// ส่วนของ discovery document (/.well-known/openid-configuration) ที่ใช้
+type providerMetadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// OIDCConnector เชื่อมต่อกับ OpenID Connect provider ใดก็ได้ตาม discovery document ของ issuer
+type OIDCConnector struct {
+	Config     Config
+	HTTPClient *http.Client
+
+	mu         sync.Mutex
+	metadata   *providerMetadata
+	metadataAt time.Time
+	keys       map[string]interface{} // public key ตาม kid
+	keysAt     time.Time
+}
+
+// สร้างอินสแตนซ์ของ OIDCConnector (อ่าน discovery document ตอนใช้งานครั้งแรก)
+// เชื่อมต่อได้เฉพาะที่อยู่สาธารณะ (ตรวจทุกครั้งที่เชื่อมต่อ รวมถึงหลัง redirect) เว้นแต่ตั้ง cfg.AllowLocal
+func NewOIDCConnector(cfg Config) *OIDCConnector {
+	client := netguard.NewClient(requestTimeout, cfg.AllowLocal)
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) >= maxRedirects {
+			return errors.New("redirect หลายครั้งเกินไป")
+		}
+		if req.URL.Scheme != "https" && !cfg.AllowLocal {
+			return fmt.Errorf("redirect ไปยัง %s ที่ไม่ใช่ https", req.URL.Redacted())
+		}
+		return nil
+	}
+	return &OIDCConnector{
+		Config:     cfg,
+		HTTPClient: client,
+	}
+}
+
+func (c *OIDCConnector) AuthCodeURL(ctx context.Context, state string, nonce string, codeChallenge string) (string, error) {
+	meta, err := c.providerMetadata(ctx)
+	if err != nil {
+		return "", err
+	}
+	u, err := url.Parse(meta.AuthorizationEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("federation: authorization_endpoint ไม่ถูกต้อง: %w", err)
+	}
+	q := u.Query()
+	q.Set("response_type", "code")
+	q.Set("client_id", c.Config.ClientID)
+	q.Set("redirect_uri", c.Config.RedirectURI)
+	q.Set("scope", strings.Join(c.Config.Scopes, " "))
+	q.Set("state", state)
+	q.Set("nonce", nonce)
+	q.Set("code_challenge", codeChallenge)
+	q.Set("code_challenge_method", "S256")
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+func (c *OIDCConnector) Exchange(ctx context.Context, code string, codeVerifier string, nonce string) (*Identity, error) {
+	meta, err := c.providerMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {c.Config.RedirectURI},
+		"code_verifier": {codeVerifier},
+		"client_id":     {c.Config.ClientID},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, meta.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if c.Config.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(c.Config.ClientID), url.QueryEscape(c.Config.ClientSecret))
+	}
+
+	var reply struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	status, err := c.doJSON(req, &reply)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK || reply.IDToken == "" {
+		if reply.Error == "invalid_grant" {
+			return nil, ErrInvalidGrant
+		}
+		return nil, fmt.Errorf("federation: token endpoint ตอบ %d %s %s", status, reply.Error, reply.ErrorDescription)
+	}
+	return c.verifyIDToken(ctx, reply.IDToken, nonce)
+}
+
+// ตรวจสอบ ID token ตาม OpenID Connect Core ข้อ 3.1.3.7
+func (c *OIDCConnector) verifyIDToken(ctx context.Context, idToken string, nonce string) (*Identity, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(idToken, claims, func(token *jwt.Token) (interface{}, error) {
+		keyID, _ := token.Header["kid"].(string)
+		return c.publicKey(ctx, keyID)
+	},
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}),
+		jwt.WithIssuer(c.Config.Issuer),
+		jwt.WithAudience(c.Config.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(clockSkew),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidGrant, err)
+	}
+	if got, _ := claims["nonce"].(string); got != nonce {
+		return nil, fmt.Errorf("%w: nonce ไม่ตรงกัน", ErrInvalidGrant)
+	}
+	if azp, ok := claims["azp"].(string); ok && azp != c.Config.ClientID {
+		return nil, fmt.Errorf("%w: azp ไม่ตรงกับ client", ErrInvalidGrant)
+	}
+
+	identity := &Identity{}
+	identity.Subject, _ = claims["sub"].(string)
+	if identity.Subject == "" {
+		return nil, fmt.Errorf("%w: ID token ไม่มี sub", ErrInvalidGrant)
+	}
+	identity.Email, _ = claims["email"].(string)
+	identity.Name, _ = claims["name"].(string)
+	identity.PreferredUsername, _ = claims["preferred_username"].(string)
+	identity.Picture, _ = claims["picture"].(string)
+	// บาง provider ส่ง email_verified เป็นข้อความ
+	switch verified := claims["email_verified"].(type) {
+	case bool:
+		identity.EmailVerified = verified
+	case string:
+		identity.EmailVerified = verified == "true"
+	}
+	return identity, nil
+}
+
+// public key ตาม kid (ID token ที่ไม่ระบุ kid ใช้ได้เมื่อ provider มี key เดียว)
+func (c *OIDCConnector) publicKey(ctx context.Context, keyID string) (interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	lookup := func() interface{} {
+		if keyID == "" && len(c.keys) == 1 {
+			for _, key := range c.keys {
+				return key
+			}
+		}
+		return c.keys[keyID]
+	}
+	if key := lookup(); key != nil && time.Since(c.keysAt) < metadataTTL {
+		return key, nil
+	}
+	if time.Since(c.keysAt) < keyRefreshDelay {
+		return nil, errors.New("ไม่รู้จัก kid ของ ID token")
+	}
+	if err := c.loadKeys(ctx); err != nil {
+		return nil, err
+	}
+	if key := lookup(); key != nil {
+		return key, nil
+	}
+	return nil, errors.New("ไม่รู้จัก kid ของ ID token")
+}
+
+// อ่าน JWKS ของ provider (เรียกขณะถือ c.mu)
+func (c *OIDCConnector) loadKeys(ctx context.Context) error {
+	meta, err := c.loadMetadata(ctx)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, meta.JWKSURI, nil)
+	if err != nil {
+		return err
+	}
+	var jwks struct {
+		Keys []map[string]interface{} `json:"keys"`
+	}
+	status, err := c.doJSON(req, &jwks)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK {
+		return fmt.Errorf("federation: jwks_uri ตอบ %d", status)
+	}
+
+	keys := map[string]interface{}{}
+	for _, jwk := range jwks.Keys {
+		if use, _ := jwk["use"].(string); use != "" && use != "sig" {
+			continue
+		}
+		key, err := auth.ParsePublicJWK(jwk)
+		if err != nil {
+			continue // ข้าม key ชนิดที่ไม่รองรับ
+		}
+		keyID, _ := jwk["kid"].(string)
+		keys[keyID] = key
+	}
+	c.keys = keys
+	c.keysAt = time.Now()
+	return nil
+}
+
+func (c *OIDCConnector) providerMetadata(ctx context.Context) (*providerMetadata, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.loadMetadata(ctx)
+}
+
+// อ่าน discovery document ของ issuer (เรียกขณะถือ c.mu) issuer ในเอกสารต้องตรงกับที่ตั้งค่าไว้
+func (c *OIDCConnector) loadMetadata(ctx context.Context) (*providerMetadata, error) {
+	if c.metadata != nil && time.Since(c.metadataAt) < metadataTTL {
+		return c.metadata, nil
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(c.Config.Issuer, "/")+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, err
+	}
+	var meta providerMetadata
+	status, err := c.doJSON(req, &meta)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("federation: discovery document ตอบ %d", status)
+	}
+	if meta.Issuer != c.Config.Issuer {
+		return nil, fmt.Errorf("federation: issuer ของ discovery document (%s) ไม่ตรงกับที่ตั้งค่าไว้", meta.Issuer)
+	}
+	if meta.AuthorizationEndpoint == "" || meta.TokenEndpoint == "" || meta.JWKSURI == "" {
+		return nil, errors.New("federation: discovery document ไม่มี endpoint ที่จำเป็น")
+	}
+	if err := c.checkEndpoint("authorization_endpoint", meta.AuthorizationEndpoint, false); err != nil {
+		return nil, err
+	}
+	// service นี้เรียก token_endpoint และ jwks_uri เอง จึงต้องอยู่บน host เดียวกับ issuer
+	if err := c.checkEndpoint("token_endpoint", meta.TokenEndpoint, true); err != nil {
+		return nil, err
+	}
+	if err := c.checkEndpoint("jwks_uri", meta.JWKSURI, true); err != nil {
+		return nil, err
+	}
+	c.metadata = &meta
+	c.metadataAt = time.Now()
+	return c.metadata, nil
+}
+
+// endpoint ใน discovery document ต้องเป็น https (http ได้เมื่อ AllowLocal) และถ้า sameHost ต้องอยู่บน host ของ issuer
+func (c *OIDCConnector) checkEndpoint(name string, endpoint string, sameHost bool) error {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" || (u.Scheme != "https" && !(c.Config.AllowLocal && u.Scheme == "http")) {
+		return fmt.Errorf("federation: %s ของ discovery document ต้องเป็น URL แบบ https", name)
+	}
+	if !sameHost {
+		return nil
+	}
+	issuer, err := url.Parse(c.Config.Issuer)
+	if err != nil || !strings.EqualFold(u.Host, issuer.Host) {
+		return fmt.Errorf("federation: %s ของ discovery document (%s) ไม่อยู่บน host ของ issuer", name, u.Host)
+	}
+	return nil
+}
+
+// ส่ง request แล้วอ่าน response เป็น JSON (อ่านได้ไม่เกิน maxResponseSize)
+func (c *OIDCConnector) doJSON(req *http.Request, v interface{}) (int, error) {
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("federation: เชื่อมต่อ provider ไม่ได้: %w", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return 0, err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return resp.StatusCode, fmt.Errorf("federation: response ของ %s ไม่ใช่ JSON", req.URL.Path)
+	}
+	return resp.StatusCode, nil
+}
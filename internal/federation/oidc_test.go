@@ -0,0 +1,183 @@
+package federation_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"auth-microservice/internal/auth"
+	"auth-microservice/internal/federation"
+	"auth-microservice/internal/federation/federationtest"
+	"auth-microservice/internal/netguard"
+)
+
+const testRedirectURI = "http://localhost:8080/federation/callback"
+
+func newConnector(issuer *federationtest.OIDCIssuer) *federation.OIDCConnector {
+	return federation.NewOIDCConnector(federation.Config{
+		Issuer:       issuer.URL,
+		ClientID:     issuer.ClientID,
+		ClientSecret: issuer.ClientSecret,
+		RedirectURI:  testRedirectURI,
+		Scopes:       []string{"openid", "email", "profile"},
+		AllowLocal:   true, // provider ปลอมอยู่บน 127.0.0.1
+	})
+}
+
+// เริ่มเข้าสู่ระบบกับ provider จนได้ code (state "state-1", nonce "nonce-1")
+func authorize(t *testing.T, issuer *federationtest.OIDCIssuer, c *federation.OIDCConnector, verifier string) string {
+	t.Helper()
+	authURL, err := c.AuthCodeURL(context.Background(), "state-1", "nonce-1", auth.PKCEChallenge(verifier))
+	if err != nil {
+		t.Fatalf("AuthCodeURL: %v", err)
+	}
+	u, _ := url.Parse(authURL)
+	if u.Query().Get("redirect_uri") != testRedirectURI || u.Query().Get("scope") != "openid email profile" {
+		t.Fatalf("AuthCodeURL = %s", authURL)
+	}
+	return issuer.Authorize(t, authURL, "state-1")
+}
+
+func TestOIDCConnectorExchange(t *testing.T) {
+	issuer := federationtest.NewOIDCIssuer(t, "relying-party", "rp-secret")
+	issuer.SetUser(map[string]interface{}{
+		"sub":                "idp-user-1",
+		"email":              "carol@corp.example.com",
+		"email_verified":     "true",
+		"name":               "Carol",
+		"preferred_username": "carol",
+	})
+	c := newConnector(issuer)
+	verifier := "verifier-0123456789-0123456789-0123456789"
+
+	code := authorize(t, issuer, c, verifier)
+	identity, err := c.Exchange(context.Background(), code, verifier, "nonce-1")
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+	want := federation.Identity{Subject: "idp-user-1", Email: "carol@corp.example.com", EmailVerified: true, Name: "Carol", PreferredUsername: "carol"}
+	if identity.Subject != want.Subject || identity.Email != want.Email || identity.EmailVerified != want.EmailVerified ||
+		identity.Name != want.Name || identity.PreferredUsername != want.PreferredUsername {
+		t.Fatalf("Exchange = %+v, want %+v", identity, want)
+	}
+
+	// provider ไม่ยอมให้ใช้ code ซ้ำ
+	if _, err := c.Exchange(context.Background(), code, verifier, "nonce-1"); !errors.Is(err, federation.ErrInvalidGrant) {
+		t.Fatalf("Exchange of a used code = %v, want ErrInvalidGrant", err)
+	}
+}
+
+func TestOIDCConnectorRejectsInvalidIDTokens(t *testing.T) {
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		nonce    string // nonce ที่ connector คาดไว้
+		verifier string // verifier ที่ส่งตอนแลก code
+		tamper   func(claims map[string]interface{})
+		signWith *rsa.PrivateKey
+	}{
+		{name: "wrong nonce", nonce: "nonce-2"},
+		{name: "wrong PKCE verifier", verifier: "another-verifier-0123456789-0123456789"},
+		{name: "other audience", tamper: func(c map[string]interface{}) { c["aud"] = "someone-else" }},
+		{name: "other issuer", tamper: func(c map[string]interface{}) { c["iss"] = "https://evil.example.com" }},
+		{name: "expired", tamper: func(c map[string]interface{}) { c["exp"] = time.Now().Add(-time.Hour).Unix() }},
+		{name: "no expiry", tamper: func(c map[string]interface{}) { delete(c, "exp") }},
+		{name: "other authorized party", tamper: func(c map[string]interface{}) { c["azp"] = "someone-else" }},
+		{name: "no subject", tamper: func(c map[string]interface{}) { delete(c, "sub") }},
+		{name: "signed by a key not in the JWKS", signWith: otherKey},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issuer := federationtest.NewOIDCIssuer(t, "relying-party", "rp-secret")
+			issuer.SetUser(map[string]interface{}{"sub": "idp-user-1", "email": "carol@corp.example.com"})
+			issuer.TamperClaims = tt.tamper
+			issuer.SignWith = tt.signWith
+			c := newConnector(issuer)
+
+			verifier := "verifier-0123456789-0123456789-0123456789"
+			code := authorize(t, issuer, c, verifier)
+			if tt.verifier != "" {
+				verifier = tt.verifier
+			}
+			nonce := "nonce-1"
+			if tt.nonce != "" {
+				nonce = tt.nonce
+			}
+			identity, err := c.Exchange(context.Background(), code, verifier, nonce)
+			if !errors.Is(err, federation.ErrInvalidGrant) {
+				t.Fatalf("Exchange = %+v, %v, want ErrInvalidGrant", identity, err)
+			}
+		})
+	}
+}
+
+func TestOIDCConnectorRejectsWrongClientSecret(t *testing.T) {
+	issuer := federationtest.NewOIDCIssuer(t, "relying-party", "rp-secret")
+	issuer.SetUser(map[string]interface{}{"sub": "idp-user-1"})
+	c := newConnector(issuer)
+	c.Config.ClientSecret = "wrong"
+
+	verifier := "verifier-0123456789-0123456789-0123456789"
+	code := authorize(t, issuer, c, verifier)
+	_, err := c.Exchange(context.Background(), code, verifier, "nonce-1")
+	if err == nil || errors.Is(err, federation.ErrInvalidGrant) {
+		t.Fatalf("Exchange with a wrong client secret = %v, want a provider error", err)
+	}
+}
+
+func TestOIDCConnectorRejectsMismatchedDiscoveryIssuer(t *testing.T) {
+	issuer := federationtest.NewOIDCIssuer(t, "relying-party", "rp-secret")
+	c := newConnector(issuer)
+	// server เดียวกันแต่ issuer ในเอกสารคือ 127.0.0.1 ไม่ใช่ localhost
+	c.Config.Issuer = strings.Replace(issuer.URL, "127.0.0.1", "localhost", 1)
+	_, err := c.AuthCodeURL(context.Background(), "s", "n", "c")
+	if err == nil || !strings.Contains(err.Error(), "issuer") {
+		t.Fatalf("AuthCodeURL with a discovery document of another issuer = %v, want an issuer mismatch", err)
+	}
+}
+
+func TestOIDCConnectorRejectsPrivateAddressIssuer(t *testing.T) {
+	issuer := federationtest.NewOIDCIssuer(t, "relying-party", "rp-secret")
+	c := newConnector(issuer)
+	c.Config.AllowLocal = false
+	c = federation.NewOIDCConnector(c.Config)
+	if _, err := c.AuthCodeURL(context.Background(), "s", "n", "c"); !errors.Is(err, netguard.ErrForbiddenAddress) {
+		t.Fatalf("AuthCodeURL with an issuer on 127.0.0.1 = %v, want ErrForbiddenAddress", err)
+	}
+}
+
+func TestOIDCConnectorRejectsDiscoveryEndpoints(t *testing.T) {
+	tests := []struct {
+		name     string
+		endpoint string
+		value    func(issuer string) string
+	}{
+		{"token endpoint on another host", "token_endpoint", func(string) string { return "http://169.254.169.254/token" }},
+		{"jwks on another host", "jwks_uri", func(issuer string) string {
+			return strings.Replace(issuer, "127.0.0.1", "localhost", 1) + "/jwks"
+		}},
+		{"token endpoint not http(s)", "token_endpoint", func(issuer string) string {
+			return strings.Replace(issuer, "http://", "file://", 1) + "/token"
+		}},
+		{"authorization endpoint without host", "authorization_endpoint", func(string) string { return "/authorize" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issuer := federationtest.NewOIDCIssuer(t, "relying-party", "rp-secret")
+			issuer.TamperDiscovery = func(doc map[string]interface{}) { doc[tt.endpoint] = tt.value(issuer.URL) }
+			c := newConnector(issuer)
+			_, err := c.AuthCodeURL(context.Background(), "s", "n", "c")
+			if err == nil || !strings.Contains(err.Error(), tt.endpoint) {
+				t.Fatalf("AuthCodeURL with %s = %v, want an error about %s", tt.name, err, tt.endpoint)
+			}
+		})
+	}
+}
@@ -0,0 +1,338 @@
+package federation
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/beevik/etree"
+	dsig "github.com/russellhaering/goxmldsig"
+)
+
+const (
+	samlProtocolNS    = "urn:oasis:names:tc:SAML:2.0:protocol"
+	samlAssertionNS   = "urn:oasis:names:tc:SAML:2.0:assertion"
+	samlBindingPOST   = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST"
+	samlStatusSuccess = "urn:oasis:names:tc:SAML:2.0:status:Success"
+	samlBearer        = "urn:oasis:names:tc:SAML:2.0:cm:bearer"
+	samlSigAlgSHA256  = "http://www.w3.org/2001/04/xmldsig-more#rsa-sha256"
+
+	SAMLNameIDEmail      = "urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress"
+	SAMLNameIDPersistent = "urn:oasis:names:tc:SAML:2.0:nameid-format:persistent"
+	samlNameIDTransient  = "urn:oasis:names:tc:SAML:2.0:nameid-format:transient"
+
+	maxSAMLResponseSize = 256 << 10 // ขนาด SAMLResponse สูงสุด (หลัง decode base64)
+)
+
+// ชื่อ attribute มาตรฐานที่ IdP นิยมใช้ (ชื่อแบบ LDAP, OID และ claim ของ AD FS / Entra ID)
+var (
+	defaultSAMLEmailAttributes = []string{"email", "mail", "urn:oid:0.9.2342.19200300.100.1.3",
+		"http://schemas.xmlsoap.org/ws/2005/05/identity/claims/emailaddress"}
+	defaultSAMLNameAttributes = []string{"displayName", "urn:oid:2.16.840.1.113730.3.1.241", "cn",
+		"http://schemas.microsoft.com/identity/claims/displayname"}
+	defaultSAMLUsernameAttributes = []string{"uid", "username", "urn:oid:0.9.2342.19200300.100.1.1"}
+	defaultSAMLGroupsAttributes   = []string{"groups", "memberOf", "urn:oid:1.3.6.1.4.1.5923.1.5.1.1",
+		"http://schemas.microsoft.com/ws/2008/06/identity/claims/groups"}
+)
+
+// การตั้งค่าของระบบนี้ในฐานะ SAML service provider (SP) และ IdP ที่เชื่อถือ
+type SAMLConfig struct {
+	EntityID    string            // entity ID ของระบบนี้ (SP)
+	ACSURL      string            // endpoint ที่ IdP ส่ง Response กลับมา (HTTP-POST binding)
+	Key         *rsa.PrivateKey   // key ที่ใช้เซ็น AuthnRequest
+	Certificate *x509.Certificate // certificate ของ Key ที่เผยแพร่ใน SP metadata
+
+	IdPEntityID     string
+	IdPSSOURL       string // endpoint ที่รับ AuthnRequest (HTTP-Redirect binding)
+	IdPCertificates []*x509.Certificate
+
+	// ชื่อ attribute ใน assertion (ว่าง = ชื่อมาตรฐานที่พบบ่อย)
+	EmailAttribute    string
+	NameAttribute     string
+	UsernameAttribute string
+	GroupsAttribute   string
+}
+
+// assertion ที่ผ่านการตรวจสอบแล้ว
+type SAMLAssertion struct {
+	ID        string
+	Identity  Identity
+	ExpiresAt time.Time // หลังเวลานี้ assertion ใช้ไม่ได้แล้ว (ใช้กำหนดอายุของการกันใช้ซ้ำ)
+}
+
+// SAMLServiceProvider สร้าง AuthnRequest และตรวจสอบ Response จาก SAML 2.0 IdP
+type SAMLServiceProvider struct {
+	Config SAMLConfig
+	Now    func() time.Time // เวลาปัจจุบัน (เปลี่ยนได้ตอนทดสอบ)
+}
+
+// สร้างอินสแตนซ์ของ SAMLServiceProvider
+func NewSAMLServiceProvider(cfg SAMLConfig) *SAMLServiceProvider {
+	return &SAMLServiceProvider{Config: cfg, Now: time.Now}
+}
+
+// URL ของ IdP ที่ส่งผู้ใช้ไปพร้อม AuthnRequest ที่เซ็นแล้ว (HTTP-Redirect binding)
+// requestID ต้องขึ้นต้นด้วยตัวอักษรหรือ _ และ IdP จะส่งกลับมาใน InResponseTo
+func (sp *SAMLServiceProvider) AuthnRequestURL(requestID string, relayState string) (string, error) {
+	doc := etree.NewDocument()
+	req := doc.CreateElement("samlp:AuthnRequest")
+	req.CreateAttr("xmlns:samlp", samlProtocolNS)
+	req.CreateAttr("xmlns:saml", samlAssertionNS)
+	req.CreateAttr("ID", requestID)
+	req.CreateAttr("Version", "2.0")
+	req.CreateAttr("IssueInstant", sp.Now().UTC().Format(time.RFC3339))
+	req.CreateAttr("Destination", sp.Config.IdPSSOURL)
+	req.CreateAttr("AssertionConsumerServiceURL", sp.Config.ACSURL)
+	req.CreateAttr("ProtocolBinding", samlBindingPOST)
+	req.CreateElement("saml:Issuer").SetText(sp.Config.EntityID)
+	policy := req.CreateElement("samlp:NameIDPolicy")
+	policy.CreateAttr("AllowCreate", "true")
+	raw, err := doc.WriteToBytes()
+	if err != nil {
+		return "", err
+	}
+
+	var deflated bytes.Buffer
+	w, _ := flate.NewWriter(&deflated, flate.BestCompression)
+	w.Write(raw)
+	w.Close()
+
+	// ลายเซ็นครอบคลุม query string ตามลำดับที่กำหนดใน SAML bindings ข้อ 3.4.4.1
+	query := "SAMLRequest=" + url.QueryEscape(base64.StdEncoding.EncodeToString(deflated.Bytes()))
+	if relayState != "" {
+		query += "&RelayState=" + url.QueryEscape(relayState)
+	}
+	query += "&SigAlg=" + url.QueryEscape(samlSigAlgSHA256)
+	digest := sha256.Sum256([]byte(query))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, sp.Config.Key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+	query += "&Signature=" + url.QueryEscape(base64.StdEncoding.EncodeToString(signature))
+
+	separator := "?"
+	if strings.Contains(sp.Config.IdPSSOURL, "?") {
+		separator = "&"
+	}
+	return sp.Config.IdPSSOURL + separator + query, nil
+}
+
+// ตรวจสอบ SAMLResponse (base64) ที่ IdP POST มาที่ ACS
+// requestID คือ ID ของ AuthnRequest ที่ส่งไป (ว่าง = IdP-initiated ซึ่ง assertion ต้องไม่มี InResponseTo)
+// ตรวจสอบลายเซ็น, Issuer, Audience, Recipient, ช่วงเวลา และ InResponseTo แล้วอ่านเฉพาะส่วนที่ถูกเซ็น
+func (sp *SAMLServiceProvider) ParseResponse(encoded string, requestID string) (*SAMLAssertion, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(encoded), ""))
+	if err != nil || len(raw) == 0 {
+		return nil, invalidAssertion("SAMLResponse ไม่ใช่ base64")
+	}
+	if len(raw) > maxSAMLResponseSize {
+		return nil, invalidAssertion("SAMLResponse มีขนาดใหญ่เกินไป")
+	}
+	doc := etree.NewDocument()
+	if err := doc.ReadFromBytes(raw); err != nil {
+		return nil, invalidAssertion("SAMLResponse ไม่ใช่ XML ที่ถูกต้อง")
+	}
+	root := doc.Root()
+	if root == nil || root.Tag != "Response" || root.NamespaceURI() != samlProtocolNS {
+		return nil, invalidAssertion("ไม่ใช่ SAML Response")
+	}
+
+	now := sp.Now()
+	validator := dsig.NewDefaultValidationContext(&dsig.MemoryX509CertificateStore{Roots: sp.Config.IdPCertificates})
+	validator.Clock = dsig.NewFakeClockAt(now)
+
+	code := root.FindElement("./Status/StatusCode")
+	if code == nil || code.SelectAttrValue("Value", "") != samlStatusSuccess {
+		return nil, invalidAssertion("IdP ไม่อนุญาตให้เข้าสู่ระบบ")
+	}
+	if dest := root.SelectAttrValue("Destination", ""); dest != "" && dest != sp.Config.ACSURL {
+		return nil, invalidAssertion("Destination ไม่ตรงกับ ACS")
+	}
+	if len(root.SelectElements("EncryptedAssertion")) > 0 {
+		return nil, invalidAssertion("ยังไม่รองรับ assertion ที่เข้ารหัส")
+	}
+
+	// อ่านเฉพาะ assertion จาก element ที่ตรวจสอบลายเซ็นแล้ว (กัน XML signature wrapping)
+	var assertion *etree.Element
+	if hasSignature(root) {
+		validated, err := validator.Validate(root)
+		if err != nil {
+			return nil, invalidAssertion("ลายเซ็นของ Response ไม่ถูกต้อง: " + err.Error())
+		}
+		assertions := validated.SelectElements("Assertion")
+		if len(assertions) != 1 {
+			return nil, invalidAssertion("Response ต้องมี assertion เดียว")
+		}
+		assertion = assertions[0]
+	} else {
+		assertions := root.SelectElements("Assertion")
+		if len(assertions) != 1 {
+			return nil, invalidAssertion("Response ต้องมี assertion เดียว")
+		}
+		if !hasSignature(assertions[0]) {
+			return nil, invalidAssertion("assertion ไม่ได้ถูกเซ็น")
+		}
+		if assertion, err = validator.Validate(assertions[0]); err != nil {
+			return nil, invalidAssertion("ลายเซ็นของ assertion ไม่ถูกต้อง: " + err.Error())
+		}
+	}
+	return sp.verifyAssertion(assertion, requestID, now)
+}
+
+// ตรวจสอบเนื้อหาของ assertion ที่ผ่านการตรวจลายเซ็นแล้ว
+func (sp *SAMLServiceProvider) verifyAssertion(assertion *etree.Element, requestID string, now time.Time) (*SAMLAssertion, error) {
+	if childText(assertion, "Issuer") != sp.Config.IdPEntityID {
+		return nil, invalidAssertion("Issuer ไม่ตรงกับ IdP")
+	}
+	id := assertion.SelectAttrValue("ID", "")
+	if id == "" {
+		return nil, invalidAssertion("assertion ไม่มี ID")
+	}
+
+	subject := assertion.SelectElement("Subject")
+	if subject == nil || subject.SelectElement("NameID") == nil {
+		return nil, invalidAssertion("assertion ไม่มี NameID")
+	}
+	nameID := strings.TrimSpace(subject.SelectElement("NameID").Text())
+	nameIDFormat := subject.SelectElement("NameID").SelectAttrValue("Format", "")
+	if nameID == "" || nameIDFormat == samlNameIDTransient {
+		return nil, invalidAssertion("NameID ต้องเป็นค่าคงที่ของผู้ใช้ (ไม่ใช่ transient)")
+	}
+
+	// bearer assertion ต้องส่งมาที่ ACS นี้ ยังไม่หมดอายุ และตอบ AuthnRequest ที่ส่งไป (ถ้ามี)
+	var expiresAt time.Time
+	for _, sc := range subject.SelectElements("SubjectConfirmation") {
+		data := sc.SelectElement("SubjectConfirmationData")
+		if sc.SelectAttrValue("Method", "") != samlBearer || data == nil {
+			continue
+		}
+		notOnOrAfter, err := parseSAMLTime(data.SelectAttrValue("NotOnOrAfter", ""))
+		if err != nil || !now.Before(notOnOrAfter.Add(clockSkew)) {
+			continue
+		}
+		if data.SelectAttrValue("Recipient", "") != sp.Config.ACSURL || data.SelectAttrValue("InResponseTo", "") != requestID {
+			continue
+		}
+		expiresAt = notOnOrAfter
+		break
+	}
+	if expiresAt.IsZero() {
+		return nil, invalidAssertion("SubjectConfirmation ไม่ถูกต้อง หมดอายุ หรือไม่ได้ตอบ AuthnRequest นี้")
+	}
+
+	conditions := assertion.SelectElement("Conditions")
+	if conditions == nil {
+		return nil, invalidAssertion("assertion ไม่มี Conditions")
+	}
+	if v := conditions.SelectAttrValue("NotBefore", ""); v != "" {
+		notBefore, err := parseSAMLTime(v)
+		if err != nil || now.Add(clockSkew).Before(notBefore) {
+			return nil, invalidAssertion("assertion ยังไม่ถึงเวลาใช้งาน")
+		}
+	}
+	if v := conditions.SelectAttrValue("NotOnOrAfter", ""); v != "" {
+		notOnOrAfter, err := parseSAMLTime(v)
+		if err != nil || !now.Before(notOnOrAfter.Add(clockSkew)) {
+			return nil, invalidAssertion("assertion หมดอายุแล้ว")
+		}
+		if notOnOrAfter.Before(expiresAt) {
+			expiresAt = notOnOrAfter
+		}
+	}
+	// ทุก AudienceRestriction ต้องมี entity ID ของระบบนี้
+	restrictions := conditions.SelectElements("AudienceRestriction")
+	if len(restrictions) == 0 {
+		return nil, invalidAssertion("assertion ไม่ได้ระบุ Audience")
+	}
+	for _, restriction := range restrictions {
+		found := false
+		for _, audience := range restriction.SelectElements("Audience") {
+			if strings.TrimSpace(audience.Text()) == sp.Config.EntityID {
+				found = true
+			}
+		}
+		if !found {
+			return nil, invalidAssertion("Audience ไม่ตรงกับระบบนี้")
+		}
+	}
+
+	attributes := map[string][]string{}
+	for _, statement := range assertion.SelectElements("AttributeStatement") {
+		for _, attr := range statement.SelectElements("Attribute") {
+			var values []string
+			for _, value := range attr.SelectElements("AttributeValue") {
+				if v := strings.TrimSpace(value.Text()); v != "" {
+					values = append(values, v)
+				}
+			}
+			for _, name := range []string{attr.SelectAttrValue("Name", ""), attr.SelectAttrValue("FriendlyName", "")} {
+				if name != "" {
+					attributes[name] = append(attributes[name], values...)
+				}
+			}
+		}
+	}
+
+	// IdP ขององค์กรเป็นผู้รับรองอีเมลใน assertion ที่เซ็นแล้ว
+	identity := Identity{
+		Subject:           nameID,
+		Email:             firstAttribute(attributes, sp.Config.EmailAttribute, defaultSAMLEmailAttributes),
+		Name:              firstAttribute(attributes, sp.Config.NameAttribute, defaultSAMLNameAttributes),
+		PreferredUsername: firstAttribute(attributes, sp.Config.UsernameAttribute, defaultSAMLUsernameAttributes),
+		Groups:            allAttributes(attributes, sp.Config.GroupsAttribute, defaultSAMLGroupsAttributes),
+	}
+	if identity.Email == "" && (nameIDFormat == SAMLNameIDEmail || strings.Contains(nameID, "@")) {
+		identity.Email = nameID
+	}
+	identity.EmailVerified = identity.Email != ""
+	return &SAMLAssertion{ID: id, Identity: identity, ExpiresAt: expiresAt}, nil
+}
+
+func invalidAssertion(reason string) error {
+	return fmt.Errorf("%w: %s", ErrInvalidGrant, reason)
+}
+
+// element ลูกโดยตรงที่มีลายเซ็น (enveloped signature)
+func hasSignature(el *etree.Element) bool {
+	return len(el.SelectElements("Signature")) > 0
+}
+
+func childText(el *etree.Element, tag string) string {
+	if child := el.SelectElement(tag); child != nil {
+		return strings.TrimSpace(child.Text())
+	}
+	return ""
+}
+
+func parseSAMLTime(v string) (time.Time, error) {
+	return time.Parse(time.RFC3339Nano, v)
+}
+
+// ค่าแรกของ attribute ที่ระบุ หรือของชื่อมาตรฐานชื่อแรกที่มีค่า
+func firstAttribute(attributes map[string][]string, name string, defaults []string) string {
+	if values := allAttributes(attributes, name, defaults); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func allAttributes(attributes map[string][]string, name string, defaults []string) []string {
+	if name != "" {
+		return attributes[name]
+	}
+	for _, candidate := range defaults {
+		if values := attributes[candidate]; len(values) > 0 {
+			return values
+		}
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package federation
+
+import (
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/beevik/etree"
+)
+
+const (
+	samlMetadataNS        = "urn:oasis:names:tc:SAML:2.0:metadata"
+	samlBindingRedirect   = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect"
+	xmlDSigNS             = "http://www.w3.org/2000/09/xmldsig#"
+	maxSAMLMetadataSize   = 1 << 20
+	samlCertificateExpiry = 100 * 365 * 24 * time.Hour
+)
+
+// ข้อมูลของ IdP ที่อ่านจาก metadata
+type SAMLIdPMetadata struct {
+	EntityID     string
+	SSOURL       string   // SingleSignOnService แบบ HTTP-Redirect
+	Certificates []string // certificate (PEM) สำหรับตรวจลายเซ็น
+}
+
+// SP metadata (EntityDescriptor) สำหรับลงทะเบียนระบบนี้กับ IdP
+func (sp *SAMLServiceProvider) Metadata() ([]byte, error) {
+	doc := etree.NewDocument()
+	doc.CreateProcInst("xml", `version="1.0" encoding="UTF-8"`)
+	entity := doc.CreateElement("md:EntityDescriptor")
+	entity.CreateAttr("xmlns:md", samlMetadataNS)
+	entity.CreateAttr("xmlns:ds", xmlDSigNS)
+	entity.CreateAttr("entityID", sp.Config.EntityID)
+
+	descriptor := entity.CreateElement("md:SPSSODescriptor")
+	descriptor.CreateAttr("AuthnRequestsSigned", "true")
+	descriptor.CreateAttr("WantAssertionsSigned", "true")
+	descriptor.CreateAttr("protocolSupportEnumeration", samlProtocolNS)
+
+	key := descriptor.CreateElement("md:KeyDescriptor")
+	key.CreateAttr("use", "signing")
+	key.CreateElement("ds:KeyInfo").CreateElement("ds:X509Data").CreateElement("ds:X509Certificate").
+		SetText(base64.StdEncoding.EncodeToString(sp.Config.Certificate.Raw))
+
+	for _, format := range []string{SAMLNameIDPersistent, SAMLNameIDEmail} {
+		descriptor.CreateElement("md:NameIDFormat").SetText(format)
+	}
+	acs := descriptor.CreateElement("md:AssertionConsumerService")
+	acs.CreateAttr("Binding", samlBindingPOST)
+	acs.CreateAttr("Location", sp.Config.ACSURL)
+	acs.CreateAttr("index", "0")
+	acs.CreateAttr("isDefault", "true")
+
+	doc.Indent(2)
+	return doc.WriteToBytes()
+}
+
+// อ่าน entity ID, SSO endpoint และ certificate จาก IdP metadata (EntityDescriptor ที่มี IDPSSODescriptor)
+func ParseSAMLIdPMetadata(data []byte) (*SAMLIdPMetadata, error) {
+	if len(data) > maxSAMLMetadataSize {
+		return nil, errors.New("federation: metadata มีขนาดใหญ่เกินไป")
+	}
+	doc := etree.NewDocument()
+	if err := doc.ReadFromBytes(data); err != nil {
+		return nil, errors.New("federation: metadata ไม่ใช่ XML ที่ถูกต้อง")
+	}
+	root := doc.Root()
+	if root == nil || root.NamespaceURI() != samlMetadataNS {
+		return nil, errors.New("federation: ไม่ใช่ SAML metadata")
+	}
+
+	// EntitiesDescriptor ใช้ entity แรกที่เป็น IdP
+	entities := []*etree.Element{root}
+	if root.Tag == "EntitiesDescriptor" {
+		entities = root.SelectElements("EntityDescriptor")
+	}
+	for _, entity := range entities {
+		idp := entity.SelectElement("IDPSSODescriptor")
+		if entity.Tag != "EntityDescriptor" || idp == nil {
+			continue
+		}
+		meta := &SAMLIdPMetadata{EntityID: entity.SelectAttrValue("entityID", "")}
+		for _, sso := range idp.SelectElements("SingleSignOnService") {
+			if sso.SelectAttrValue("Binding", "") == samlBindingRedirect {
+				meta.SSOURL = sso.SelectAttrValue("Location", "")
+				break
+			}
+		}
+		for _, key := range idp.SelectElements("KeyDescriptor") {
+			if use := key.SelectAttrValue("use", ""); use != "" && use != "signing" {
+				continue
+			}
+			for _, cert := range key.FindElements("./KeyInfo/X509Data/X509Certificate") {
+				der, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(cert.Text()), ""))
+				if err != nil {
+					return nil, errors.New("federation: certificate ใน metadata ไม่ถูกต้อง")
+				}
+				meta.Certificates = append(meta.Certificates, string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})))
+			}
+		}
+		return meta, nil
+	}
+	return nil, errors.New("federation: metadata ไม่มี IDPSSODescriptor")
+}
+
+// แปลง certificate แบบ PEM (หนึ่งหรือหลายใบ) ของ IdP
+func ParseSAMLCertificates(pems []string) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for _, data := range pems {
+		rest := []byte(data)
+		for {
+			var block *pem.Block
+			block, rest = pem.Decode(rest)
+			if block == nil {
+				break
+			}
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil, err
+			}
+			certs = append(certs, cert)
+		}
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("federation: ไม่พบ certificate แบบ PEM")
+	}
+	return certs, nil
+}
+
+// certificate แบบ self-signed ของ key ที่ใช้เซ็น AuthnRequest (IdP ใช้แค่ public key ใน certificate)
+// สร้างจาก key อย่างเดียวจึงได้ certificate เดิมทุกครั้งและทุก instance
+func SAMLCertificate(key *rsa.PrivateKey, commonName string) (*x509.Certificate, error) {
+	fingerprint := sha256.Sum256(x509.MarshalPKCS1PublicKey(&key.PublicKey))
+	notBefore := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	template := &x509.Certificate{
+		SerialNumber:          new(big.Int).SetBytes(fingerprint[:16]),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             notBefore,
+		NotAfter:              notBefore.Add(samlCertificateExpiry),
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(nil, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, err
+	}
+	return x509.ParseCertificate(der)
+}
@@ -0,0 +1,243 @@
+package federation_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"auth-microservice/internal/federation"
+	"auth-microservice/internal/federation/federationtest"
+)
+
+const (
+	testSPEntityID = "http://localhost:8080/saml/default/corp/metadata"
+	testACSURL     = "http://localhost:8080/saml/default/corp/acs"
+)
+
+func newSAMLServiceProvider(t *testing.T, idp *federationtest.SAMLIdP) *federation.SAMLServiceProvider {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := federation.SAMLCertificate(key, "http://localhost:8080")
+	if err != nil {
+		t.Fatalf("SAMLCertificate: %v", err)
+	}
+	return federation.NewSAMLServiceProvider(federation.SAMLConfig{
+		EntityID:        testSPEntityID,
+		ACSURL:          testACSURL,
+		Key:             key,
+		Certificate:     cert,
+		IdPEntityID:     idp.EntityID,
+		IdPSSOURL:       idp.SSOURL,
+		IdPCertificates: []*x509.Certificate{idp.Certificate},
+	})
+}
+
+// assertion ที่ถูกต้องสำหรับ SP ในการทดสอบ (ตอบ AuthnRequest "id-1")
+func validAssertion() federationtest.SAMLAssertion {
+	return federationtest.SAMLAssertion{
+		NameID:       "idp-user-1",
+		Audience:     testSPEntityID,
+		Recipient:    testACSURL,
+		InResponseTo: "id-1",
+		Destination:  testACSURL,
+		Attributes: map[string][]string{
+			"mail":        {"carol@corp.example.com"},
+			"displayName": {"Carol"},
+			"uid":         {"carol"},
+			"groups":      {"engineering", "admins"},
+		},
+	}
+}
+
+func TestSAMLAuthnRequestIsSigned(t *testing.T) {
+	idp := federationtest.NewSAMLIdP(t, "https://idp.example.com", "https://idp.example.com/sso?tenant=corp")
+	sp := newSAMLServiceProvider(t, idp)
+
+	authURL, err := sp.AuthnRequestURL("id-1", "relay-state-1")
+	if err != nil {
+		t.Fatalf("AuthnRequestURL: %v", err)
+	}
+	// query string เดิมของ SSO URL ยังอยู่
+	if !strings.HasPrefix(authURL, "https://idp.example.com/sso?tenant=corp&SAMLRequest=") {
+		t.Fatalf("AuthnRequestURL = %s", authURL)
+	}
+	idp.SSOURL = "https://idp.example.com/sso"
+	req := idp.ParseAuthnRequest(t, authURL, sp.Config.Certificate)
+	if req.ID != "id-1" || req.Issuer != testSPEntityID || req.ACSURL != testACSURL || req.RelayState != "relay-state-1" {
+		t.Fatalf("AuthnRequest = %+v", req)
+	}
+}
+
+func TestSAMLParseResponse(t *testing.T) {
+	idp := federationtest.NewSAMLIdP(t, "https://idp.example.com", "https://idp.example.com/sso")
+	sp := newSAMLServiceProvider(t, idp)
+
+	for _, signing := range []federationtest.SAMLSigning{federationtest.SignAssertion, federationtest.SignResponse} {
+		a := validAssertion()
+		a.ID = "_assertion-1"
+		assertion, err := sp.ParseResponse(idp.Response(t, a, signing, nil), "id-1")
+		if err != nil {
+			t.Fatalf("ParseResponse (signing %d): %v", signing, err)
+		}
+		got := assertion.Identity
+		if assertion.ID != "_assertion-1" || got.Subject != "idp-user-1" || got.Email != "carol@corp.example.com" || !got.EmailVerified ||
+			got.Name != "Carol" || got.PreferredUsername != "carol" || len(got.Groups) != 2 {
+			t.Fatalf("ParseResponse (signing %d) = %+v", signing, assertion)
+		}
+		if assertion.ExpiresAt.Before(time.Now()) {
+			t.Fatalf("ExpiresAt = %v, want a time in the future", assertion.ExpiresAt)
+		}
+	}
+}
+
+func TestSAMLParseResponseIdPInitiated(t *testing.T) {
+	idp := federationtest.NewSAMLIdP(t, "https://idp.example.com", "https://idp.example.com/sso")
+	sp := newSAMLServiceProvider(t, idp)
+
+	a := validAssertion()
+	a.InResponseTo = ""
+	a.NameIDFormat = federation.SAMLNameIDEmail
+	a.NameID = "dave@corp.example.com"
+	a.Attributes = nil
+	assertion, err := sp.ParseResponse(idp.Response(t, a, federationtest.SignAssertion, nil), "")
+	if err != nil {
+		t.Fatalf("ParseResponse: %v", err)
+	}
+	// ไม่มี attribute อีเมล ใช้ NameID แบบอีเมลแทน
+	if assertion.Identity.Email != "dave@corp.example.com" {
+		t.Fatalf("Email = %q, want the NameID", assertion.Identity.Email)
+	}
+
+	// Response ที่ตอบ AuthnRequest ใช้แบบ IdP-initiated ไม่ได้
+	if _, err := sp.ParseResponse(idp.Response(t, validAssertion(), federationtest.SignAssertion, nil), ""); !errors.Is(err, federation.ErrInvalidGrant) {
+		t.Fatalf("ParseResponse of a solicited response without a request = %v, want ErrInvalidGrant", err)
+	}
+}
+
+func TestSAMLParseResponseRejects(t *testing.T) {
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name      string
+		modify    func(a *federationtest.SAMLAssertion)
+		signing   federationtest.SAMLSigning
+		signWith  *rsa.PrivateKey
+		requestID string // ว่าง = "id-1"
+	}{
+		{name: "unsigned", signing: federationtest.SignNothing},
+		{name: "signed by another key", signWith: otherKey},
+		{name: "other audience", modify: func(a *federationtest.SAMLAssertion) { a.Audience = "https://other-sp.example.com" }},
+		{name: "other recipient", modify: func(a *federationtest.SAMLAssertion) { a.Recipient = "https://other-sp.example.com/acs" }},
+		{name: "other destination", modify: func(a *federationtest.SAMLAssertion) { a.Destination = "https://other-sp.example.com/acs" }},
+		{name: "other issuer", modify: func(a *federationtest.SAMLAssertion) { a.Issuer = "https://evil.example.com" }},
+		{name: "answers another request", requestID: "id-2"},
+		{name: "expired", modify: func(a *federationtest.SAMLAssertion) {
+			a.NotBefore, a.NotOnOrAfter = time.Now().Add(-time.Hour), time.Now().Add(-10*time.Minute)
+		}},
+		{name: "not yet valid", modify: func(a *federationtest.SAMLAssertion) {
+			a.NotBefore, a.NotOnOrAfter = time.Now().Add(10*time.Minute), time.Now().Add(time.Hour)
+		}},
+		{name: "transient NameID", modify: func(a *federationtest.SAMLAssertion) {
+			a.NameIDFormat = "urn:oasis:names:tc:SAML:2.0:nameid-format:transient"
+		}},
+		{name: "failed status", modify: func(a *federationtest.SAMLAssertion) {
+			a.StatusCode = "urn:oasis:names:tc:SAML:2.0:status:AuthnFailed"
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idp := federationtest.NewSAMLIdP(t, "https://idp.example.com", "https://idp.example.com/sso")
+			sp := newSAMLServiceProvider(t, idp)
+			a := validAssertion()
+			if tt.modify != nil {
+				tt.modify(&a)
+			}
+			requestID := tt.requestID
+			if requestID == "" {
+				requestID = "id-1"
+			}
+			assertion, err := sp.ParseResponse(idp.Response(t, a, tt.signing, tt.signWith), requestID)
+			if !errors.Is(err, federation.ErrInvalidGrant) {
+				t.Fatalf("ParseResponse = %+v, %v, want ErrInvalidGrant", assertion, err)
+			}
+		})
+	}
+}
+
+func TestSAMLParseResponseRejectsTampering(t *testing.T) {
+	idp := federationtest.NewSAMLIdP(t, "https://idp.example.com", "https://idp.example.com/sso")
+	sp := newSAMLServiceProvider(t, idp)
+
+	// แก้อีเมลหลังเซ็น
+	raw := idp.ResponseXML(t, validAssertion(), federationtest.SignAssertion, nil)
+	tampered := bytes.Replace(raw, []byte("carol@corp.example.com"), []byte("admin@corp.example.com"), 1)
+	if _, err := sp.ParseResponse(base64.StdEncoding.EncodeToString(tampered), "id-1"); !errors.Is(err, federation.ErrInvalidGrant) {
+		t.Fatalf("ParseResponse of a tampered assertion = %v, want ErrInvalidGrant", err)
+	}
+
+	// signature wrapping: เพิ่ม assertion ที่ไม่ได้เซ็นไว้ก่อน assertion ที่เซ็นแล้ว
+	evil := validAssertion()
+	evil.NameID = "admin"
+	unsigned := idp.ResponseXML(t, evil, federationtest.SignNothing, nil)
+	start := bytes.Index(unsigned, []byte("<saml:Assertion"))
+	end := bytes.Index(unsigned, []byte("</saml:Assertion>")) + len("</saml:Assertion>")
+	signedStart := bytes.Index(raw, []byte("<saml:Assertion"))
+	wrapped := append(append(append([]byte{}, raw[:signedStart]...), unsigned[start:end]...), raw[signedStart:]...)
+	if _, err := sp.ParseResponse(base64.StdEncoding.EncodeToString(wrapped), "id-1"); !errors.Is(err, federation.ErrInvalidGrant) {
+		t.Fatalf("ParseResponse of a wrapped assertion = %v, want ErrInvalidGrant", err)
+	}
+
+	if _, err := sp.ParseResponse("not base64!", "id-1"); !errors.Is(err, federation.ErrInvalidGrant) {
+		t.Fatalf("ParseResponse of garbage = %v, want ErrInvalidGrant", err)
+	}
+}
+
+func TestSAMLMetadata(t *testing.T) {
+	idp := federationtest.NewSAMLIdP(t, "https://idp.example.com", "https://idp.example.com/sso")
+
+	meta, err := federation.ParseSAMLIdPMetadata([]byte(idp.Metadata()))
+	if err != nil {
+		t.Fatalf("ParseSAMLIdPMetadata: %v", err)
+	}
+	if meta.EntityID != idp.EntityID || meta.SSOURL != idp.SSOURL || len(meta.Certificates) != 1 || meta.Certificates[0] != idp.CertificatePEM() {
+		t.Fatalf("ParseSAMLIdPMetadata = %+v", meta)
+	}
+	if _, err := federation.ParseSAMLIdPMetadata([]byte(`<md:EntityDescriptor xmlns:md="urn:oasis:names:tc:SAML:2.0:metadata" entityID="sp"><md:SPSSODescriptor/></md:EntityDescriptor>`)); err == nil {
+		t.Fatal("ParseSAMLIdPMetadata of SP metadata succeeded")
+	}
+
+	sp := newSAMLServiceProvider(t, idp)
+	spMeta, err := sp.Metadata()
+	if err != nil {
+		t.Fatalf("Metadata: %v", err)
+	}
+	for _, want := range []string{`entityID="` + testSPEntityID + `"`, `Location="` + testACSURL + `"`, `AuthnRequestsSigned="true"`,
+		base64.StdEncoding.EncodeToString(sp.Config.Certificate.Raw)} {
+		if !strings.Contains(string(spMeta), want) {
+			t.Errorf("SP metadata does not contain %s", want)
+		}
+	}
+}
+
+func TestSAMLCertificateIsStable(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err1 := federation.SAMLCertificate(key, "sp")
+	b, err2 := federation.SAMLCertificate(key, "sp")
+	if err1 != nil || err2 != nil || !bytes.Equal(a.Raw, b.Raw) {
+		t.Fatalf("SAMLCertificate is not deterministic: %v, %v", err1, err2)
+	}
+}
@@ -0,0 +1,114 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ชื่อ collection/ตารางที่ใช้บันทึก migration ที่รันไปแล้ว
+const CollectionName = "schema_migrations"
+
+// Migration หนึ่งเวอร์ชัน ต้องมีทั้ง Up และ Down
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(ctx context.Context) error
+	Down    func(ctx context.Context) error
+}
+
+// สถานะของ migration แต่ละเวอร์ชัน
+type Status struct {
+	Version   int
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// Journal บันทึกว่า migration เวอร์ชันใดรันไปแล้ว (แต่ละ backend มี implementation ของตัวเอง)
+type Journal interface {
+	// เวลาที่รันของแต่ละเวอร์ชันที่รันไปแล้ว (key = version)
+	Applied(ctx context.Context) (map[int]time.Time, error)
+	Record(ctx context.Context, version int, name string, appliedAt time.Time) error
+	Remove(ctx context.Context, version int) error
+}
+
+// Migrator รัน migration ตามลำดับเวอร์ชัน และบันทึกผลลง Journal
+type Migrator struct {
+	Journal    Journal
+	Migrations []Migration
+}
+
+// สร้างอินสแตนซ์ของ Migrator (เรียง migration ตามเวอร์ชัน)
+func NewMigrator(journal Journal, migrations []Migration) *Migrator {
+	sorted := append([]Migration(nil), migrations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+	return &Migrator{Journal: journal, Migrations: sorted}
+}
+
+// รัน migration ที่ยังไม่เคยรันทั้งหมด แล้วคืนรายการที่รันในครั้งนี้
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	applied, err := m.Journal.Applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var ran []Migration
+	for _, mig := range m.Migrations {
+		if _, ok := applied[mig.Version]; ok {
+			continue
+		}
+		if err := mig.Up(ctx); err != nil {
+			return ran, fmt.Errorf("migration %d (%s) failed: %w", mig.Version, mig.Name, err)
+		}
+		if err := m.Journal.Record(ctx, mig.Version, mig.Name, time.Now()); err != nil {
+			return ran, err
+		}
+		ran = append(ran, mig)
+	}
+	return ran, nil
+}
+
+// ย้อน migration ล่าสุดที่รันไปแล้วจำนวน steps เวอร์ชัน
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	applied, err := m.Journal.Applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var reverted []Migration
+	for i := len(m.Migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+		mig := m.Migrations[i]
+		if _, ok := applied[mig.Version]; !ok {
+			continue
+		}
+		if err := mig.Down(ctx); err != nil {
+			return reverted, fmt.Errorf("rollback %d (%s) failed: %w", mig.Version, mig.Name, err)
+		}
+		if err := m.Journal.Remove(ctx, mig.Version); err != nil {
+			return reverted, err
+		}
+		reverted = append(reverted, mig)
+	}
+	return reverted, nil
+}
+
+// สถานะของ migration ทุกเวอร์ชัน
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := m.Journal.Applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(m.Migrations))
+	for _, mig := range m.Migrations {
+		st := Status{Version: mig.Version, Name: mig.Name}
+		if appliedAt, ok := applied[mig.Version]; ok {
+			st.Applied = true
+			st.AppliedAt = appliedAt
+		}
+		statuses = append(statuses, st)
+	}
+	return statuses, nil
+}
@@ -0,0 +1,171 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// Journal ในหน่วยความจำ
+type memoryJournal struct {
+	applied map[int]time.Time
+}
+
+func (j *memoryJournal) Applied(ctx context.Context) (map[int]time.Time, error) {
+	applied := make(map[int]time.Time, len(j.applied))
+	for v, at := range j.applied {
+		applied[v] = at
+	}
+	return applied, nil
+}
+
+func (j *memoryJournal) Record(ctx context.Context, version int, name string, appliedAt time.Time) error {
+	j.applied[version] = appliedAt
+	return nil
+}
+
+func (j *memoryJournal) Remove(ctx context.Context, version int) error {
+	delete(j.applied, version)
+	return nil
+}
+
+// migration ทดสอบที่บันทึกลำดับการรันไว้ใน log (เวอร์ชันใน fail จะล้มเหลว)
+func testMigrations(log *[]string, fail map[int]bool, versions ...int) []Migration {
+	var migrations []Migration
+	for _, v := range versions {
+		v := v
+		step := func(dir string) func(ctx context.Context) error {
+			return func(ctx context.Context) error {
+				if fail[v] {
+					return errors.New("boom")
+				}
+				*log = append(*log, fmt.Sprintf("%s %d", dir, v))
+				return nil
+			}
+		}
+		migrations = append(migrations, Migration{Version: v, Name: fmt.Sprintf("m%d", v), Up: step("up"), Down: step("down")})
+	}
+	return migrations
+}
+
+func versions(migrations []Migration) []int {
+	out := []int{}
+	for _, m := range migrations {
+		out = append(out, m.Version)
+	}
+	return out
+}
+
+func TestMigratorUp(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		applied []int
+		fail    map[int]bool
+		wantRan []int
+		wantLog []string
+		wantErr bool
+	}{
+		{"fresh database runs in version order", nil, nil, []int{1, 2, 3, 10}, []string{"up 1", "up 2", "up 3", "up 10"}, false},
+		{"skips applied versions", []int{1, 2}, nil, []int{3, 10}, []string{"up 3", "up 10"}, false},
+		{"fills a gap", []int{1, 3}, nil, []int{2, 10}, []string{"up 2", "up 10"}, false},
+		{"nothing pending", []int{1, 2, 3, 10}, nil, []int{}, nil, false},
+		{"stops at the first failure", nil, map[int]bool{3: true}, []int{1, 2}, []string{"up 1", "up 2"}, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var log []string
+			journal := &memoryJournal{applied: map[int]time.Time{}}
+			for _, v := range tc.applied {
+				journal.applied[v] = time.Now()
+			}
+			// ลำดับที่ส่งเข้าไม่เรียง NewMigrator ต้องเรียงตามเวอร์ชัน
+			m := NewMigrator(journal, testMigrations(&log, tc.fail, 10, 2, 1, 3))
+
+			ran, err := m.Up(context.Background())
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Up error = %v, want error %v", err, tc.wantErr)
+			}
+			if !reflect.DeepEqual(versions(ran), tc.wantRan) || !reflect.DeepEqual(log, tc.wantLog) {
+				t.Fatalf("Up ran %v (log %v), want %v (log %v)", versions(ran), log, tc.wantRan, tc.wantLog)
+			}
+			for _, v := range tc.wantRan {
+				if _, ok := journal.applied[v]; !ok {
+					t.Fatalf("version %d was not recorded", v)
+				}
+			}
+			if tc.wantErr {
+				if _, ok := journal.applied[3]; ok {
+					t.Fatal("the failed migration was recorded")
+				}
+			}
+		})
+	}
+}
+
+func TestMigratorDown(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		applied      []int
+		steps        int
+		fail         map[int]bool
+		wantReverted []int
+		wantLeft     []int
+		wantErr      bool
+	}{
+		{"latest first", []int{1, 2, 3, 10}, 1, nil, []int{10}, []int{1, 2, 3}, false},
+		{"several steps in reverse order", []int{1, 2, 3, 10}, 3, nil, []int{10, 3, 2}, []int{1}, false},
+		{"skips versions that are not applied", []int{1, 3}, 2, nil, []int{3, 1}, []int{}, false},
+		{"more steps than applied", []int{1, 2}, 5, nil, []int{2, 1}, []int{}, false},
+		{"nothing applied", nil, 1, nil, []int{}, []int{}, false},
+		{"stops at the first failure", []int{1, 2, 3, 10}, 3, map[int]bool{3: true}, []int{10}, []int{1, 2, 3}, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var log []string
+			journal := &memoryJournal{applied: map[int]time.Time{}}
+			for _, v := range tc.applied {
+				journal.applied[v] = time.Now()
+			}
+			m := NewMigrator(journal, testMigrations(&log, tc.fail, 3, 1, 10, 2))
+
+			reverted, err := m.Down(context.Background(), tc.steps)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Down error = %v, want error %v", err, tc.wantErr)
+			}
+			if !reflect.DeepEqual(versions(reverted), tc.wantReverted) {
+				t.Fatalf("Down reverted %v, want %v", versions(reverted), tc.wantReverted)
+			}
+			statuses, _ := m.Status(context.Background())
+			left := []int{}
+			for _, st := range statuses {
+				if st.Applied {
+					left = append(left, st.Version)
+				}
+			}
+			if !reflect.DeepEqual(left, tc.wantLeft) {
+				t.Fatalf("applied after Down = %v, want %v", left, tc.wantLeft)
+			}
+		})
+	}
+}
+
+func TestMigratorStatus(t *testing.T) {
+	appliedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	journal := &memoryJournal{applied: map[int]time.Time{2: appliedAt, 99: appliedAt}} // 99 ไม่มีใน migration แล้ว
+	var log []string
+	m := NewMigrator(journal, testMigrations(&log, nil, 3, 1, 2))
+
+	statuses, err := m.Status(context.Background())
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	want := []Status{
+		{Version: 1, Name: "m1"},
+		{Version: 2, Name: "m2", Applied: true, AppliedAt: appliedAt},
+		{Version: 3, Name: "m3"},
+	}
+	if !reflect.DeepEqual(statuses, want) {
+		t.Fatalf("Status = %+v, want %+v", statuses, want)
+	}
+}
@@ -0,0 +1,54 @@
+package migrate
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// record ที่เก็บใน schema_migrations
+type appliedMigration struct {
+	Version   int       `bson:"_id"`
+	Name      string    `bson:"name"`
+	AppliedAt time.Time `bson:"appliedAt"`
+}
+
+// MongoJournal เก็บ migration ที่รันแล้วใน collection schema_migrations
+type MongoJournal struct {
+	Collection *mongo.Collection
+}
+
+// สร้าง Journal บนฐานข้อมูล MongoDB
+func NewMongoJournal(db *mongo.Database) *MongoJournal {
+	return &MongoJournal{Collection: db.Collection(CollectionName)}
+}
+
+func (j *MongoJournal) Applied(ctx context.Context) (map[int]time.Time, error) {
+	cursor, err := j.Collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	var records []appliedMigration
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int]time.Time, len(records))
+	for _, r := range records {
+		applied[r.Version] = r.AppliedAt
+	}
+	return applied, nil
+}
+
+func (j *MongoJournal) Record(ctx context.Context, version int, name string, appliedAt time.Time) error {
+	_, err := j.Collection.InsertOne(ctx, appliedMigration{Version: version, Name: name, AppliedAt: appliedAt})
+	return err
+}
+
+func (j *MongoJournal) Remove(ctx context.Context, version int) error {
+	_, err := j.Collection.DeleteOne(ctx, bson.M{"_id": version})
+	return err
+}
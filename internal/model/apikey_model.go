@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// scope ของ API key กำหนดว่า key เรียก RPC ใดได้บ้าง (สิทธิ์ตาม role ของเจ้าของ key ยังบังคับใช้เหมือนเดิม)
+const (
+	ScopeUsersRead    = "users:read"
+	ScopeUsersWrite   = "users:write"
+	ScopeGroupsRead   = "groups:read"
+	ScopeGroupsWrite  = "groups:write"
+	ScopeTenantsRead  = "tenants:read"
+	ScopeTenantsWrite = "tenants:write"
+)
+
+// API key สำหรับ script และ CI ที่เรียก service แทนผู้ใช้เจ้าของ key
+// เก็บเฉพาะ hash ของ key ส่วน key จริงแสดงครั้งเดียวตอนสร้าง
+type APIKey struct {
+	ID         primitive.ObjectID `bson:"_id"`
+	TenantID   string             `bson:"tenantId"`
+	UserID     primitive.ObjectID `bson:"userId"` // เจ้าของ key
+	Name       string             `bson:"name"`
+	Prefix     string             `bson:"prefix"` // ส่วนต้นของ key ใช้แสดงให้ผู้ใช้จำแนก key
+	Hash       string             `bson:"hash"`   // SHA-256 ของ key ทั้งหมด
+	Scopes     []string           `bson:"scopes"`
+	RateLimit  int                `bson:"rateLimit"` // จำนวน request สูงสุดต่อนาที
+	ExpiresAt  time.Time          `bson:"expiresAt"`
+	LastUsedAt *time.Time         `bson:"lastUsedAt,omitempty"`
+	RevokedAt  *time.Time         `bson:"revokedAt,omitempty"`
+	CreatedAt  time.Time          `bson:"createdAt"`
+}
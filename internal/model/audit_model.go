@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// เหตุการณ์ที่บันทึกลง audit log
+type AuditEvent struct {
+	ID           primitive.ObjectID     `bson:"_id,omitempty"`
+	TenantID     string                 `bson:"tenantId"`          // tenant ของผู้ใช้ที่ถูกกระทำ
+	Action       string                 `bson:"action"`            // ชื่อเหตุการณ์ เช่น "user.deleted"
+	ActorEmail   string                 `bson:"actorEmail"`        // ผู้ที่ทำรายการ
+	SubjectID    string                 `bson:"subjectId"`         // ID ของผู้ใช้ที่ถูกกระทำ
+	SubjectEmail string                 `bson:"subjectEmail"`      // อีเมลของผู้ใช้ที่ถูกกระทำ
+	Details      map[string]interface{} `bson:"details,omitempty"` // ข้อมูลเพิ่มเติม
+	CreatedAt    time.Time              `bson:"createdAt"`
+}
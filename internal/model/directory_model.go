@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Directory คือ LDAP / Active Directory ที่ใช้ตรวจสอบรหัสผ่านของผู้ใช้ใน tenant แทนรหัสผ่านที่เก็บในระบบนี้
+// ผู้ใช้ที่อีเมลอยู่ในโดเมนของ directory เข้าสู่ระบบผ่าน directory เสมอ และถูกสร้างในระบบนี้ตอนเข้าสู่ระบบครั้งแรก
+type Directory struct {
+	ID       primitive.ObjectID `bson:"_id"`
+	TenantID string             `bson:"tenantId"`
+	Name     string             `bson:"name"` // ไม่ซ้ำกันภายใน tenant
+	URL      string             `bson:"url"`  // ldap://host:389 หรือ ldaps://host:636
+	StartTLS bool               `bson:"startTls"`
+	RootCA   string             `bson:"rootCa,omitempty"` // CA (PEM) ของ server (ว่าง = CA ของระบบ)
+
+	// service account สำหรับค้นหาผู้ใช้ (ว่าง = ค้นหาแบบ anonymous)
+	BindDN       string `bson:"bindDn,omitempty"`
+	BindPassword string `bson:"bindPassword,omitempty"`
+	// bind ด้วย DN ของผู้ใช้โดยตรง เช่น "uid={username},ou=people,dc=example,dc=com" หรือ "{email}" สำหรับ AD
+	UserDNTemplate string `bson:"userDnTemplate,omitempty"`
+	// ค้นหา DN ของผู้ใช้ก่อน bind เช่น "(&(objectClass=person)(mail={email}))" (ใช้แทน UserDNTemplate)
+	BaseDN     string `bson:"baseDn"`
+	UserFilter string `bson:"userFilter,omitempty"`
+
+	UsernameAttribute    string `bson:"usernameAttribute"`    // เช่น uid หรือ sAMAccountName
+	DisplayNameAttribute string `bson:"displayNameAttribute"` // เช่น cn หรือ displayName
+	// ค้นหากลุ่มของผู้ใช้ เช่น "(&(objectClass=groupOfNames)(member={dn}))" (ว่าง = ใช้ attribute memberOf ของผู้ใช้)
+	GroupFilter string `bson:"groupFilter,omitempty"`
+
+	// role ตามกลุ่มใน directory (ใช้ mapping แรกที่ตรง) ผู้ใช้ที่ไม่อยู่ในกลุ่มใดได้ DefaultRole
+	RoleMappings []RoleMapping `bson:"roleMappings"`
+	DefaultRole  string        `bson:"defaultRole"`
+	// โดเมนอีเมลที่เข้าสู่ระบบผ่าน directory นี้ (ว่าง = ทุกอีเมลของ tenant)
+	Domains []string `bson:"domains"`
+	// ผู้ใช้ที่ไม่พบใน directory เข้าสู่ระบบด้วยรหัสผ่านในระบบนี้ได้ (เฉพาะแบบค้นหา DN ก่อน bind)
+	AllowLocalFallback bool      `bson:"allowLocalFallback"`
+	PoolSize           int       `bson:"poolSize"` // จำนวน connection สูงสุดต่อ instance
+	CreatedAt          time.Time `bson:"createdAt"`
+	UpdatedAt          time.Time `bson:"updatedAt"`
+}
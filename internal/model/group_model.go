@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// role ของสมาชิกภายในกลุ่ม
+const (
+	GroupRoleOwner   = "owner"   // จัดการสมาชิกและเชิญผู้ใช้เข้ากลุ่มได้
+	GroupRoleManager = "manager" // จัดการสมาชิกและเชิญผู้ใช้เข้ากลุ่มได้ ยกเว้น owner
+	GroupRoleMember  = "member"
+)
+
+// กลุ่มของผู้ใช้ (เช่น ทีม) ภายใน tenant ชื่อกลุ่มไม่ซ้ำกันใน tenant เดียวกัน
+type Group struct {
+	ID          primitive.ObjectID `bson:"_id"`
+	TenantID    string             `bson:"tenantId"`
+	Name        string             `bson:"name"`
+	Description string             `bson:"description,omitempty"`
+	CreatedAt   time.Time          `bson:"createdAt"`
+	UpdatedAt   time.Time          `bson:"updatedAt"`
+}
+
+// การเป็นสมาชิกกลุ่มของผู้ใช้ (MongoDB เก็บเป็น array "groups" ใน document ของผู้ใช้)
+type GroupMembership struct {
+	GroupID  primitive.ObjectID `bson:"groupId"`
+	Role     string             `bson:"role"`
+	JoinedAt time.Time          `bson:"joinedAt"`
+}
+
+// สมาชิกของกลุ่มพร้อมข้อมูลผู้ใช้
+type GroupMember struct {
+	UserID   primitive.ObjectID
+	Email    string
+	Username string
+	Role     string
+	JoinedAt time.Time
+}
+
+// กลุ่มที่ผู้ใช้เป็นสมาชิกพร้อม role ในกลุ่มนั้น
+type UserGroup struct {
+	Group    Group
+	Role     string
+	JoinedAt time.Time
+}
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// protocol ของ identity provider
+const (
+	ProtocolOIDC = "oidc"
+	ProtocolSAML = "saml"
+)
+
+// IdentityProvider คือ identity provider ภายนอก (เช่น IdP ขององค์กร) แบบ OpenID Connect หรือ SAML 2.0
+// ที่ผู้ใช้ของ tenant ใช้เข้าสู่ระบบได้
+type IdentityProvider struct {
+	ID           primitive.ObjectID `bson:"_id"`
+	TenantID     string             `bson:"tenantId"`
+	Name         string             `bson:"name"`        // ชื่อสั้นที่ใช้อ้างถึงตอนเข้าสู่ระบบ (เช่น "corp") ไม่ซ้ำกันภายใน tenant
+	DisplayName  string             `bson:"displayName"` // ชื่อที่แสดงบนปุ่ม "เข้าสู่ระบบด้วย ..."
+	Protocol     string             `bson:"protocol"`    // ProtocolOIDC (ค่าว่างของ provider เดิมถือเป็น OIDC) หรือ ProtocolSAML
+	Issuer       string             `bson:"issuer"`      // ใช้หา /.well-known/openid-configuration และตรวจ iss ของ ID token
+	ClientID     string             `bson:"clientId"`
+	ClientSecret string             `bson:"clientSecret"` // secret ที่ IdP ออกให้ (ต้องเก็บค่าจริงเพื่อใช้แลก code)
+	// หน้า callback ของแอป (OIDC: ลงทะเบียนไว้กับ IdP, SAML: ระบบส่งผู้ใช้มาพร้อม state และ code หลังตรวจสอบ assertion)
+	RedirectURI string   `bson:"redirectUri"`
+	Scopes      []string `bson:"scopes"`
+	// การตั้งค่าของ SAML IdP (เฉพาะ ProtocolSAML)
+	SAML *SAMLSettings `bson:"saml,omitempty"`
+	// สร้างผู้ใช้ใหม่ให้อัตโนมัติเมื่อยังไม่มีผู้ใช้ที่ใช้อีเมลนี้ (false = เข้าสู่ระบบได้เฉพาะผู้ใช้ที่มีอยู่แล้ว)
+	AllowSignup bool `bson:"allowSignup"`
+	// โดเมนอีเมลที่สร้างผู้ใช้ใหม่ได้ (ว่าง = ทุกโดเมน)
+	AllowedDomains []string  `bson:"allowedDomains"`
+	CreatedAt      time.Time `bson:"createdAt"`
+	UpdatedAt      time.Time `bson:"updatedAt"`
+}
+
+// บัญชีภายนอกที่ผูกกับผู้ใช้ (ผู้ใช้หนึ่งคนผูกได้หนึ่งบัญชีต่อ provider)
+type LinkedIdentity struct {
+	TenantID    string             `bson:"tenantId"`
+	UserID      primitive.ObjectID `bson:"userId"`
+	ProviderID  primitive.ObjectID `bson:"providerId"`
+	Subject     string             `bson:"subject"` // sub ใน ID token ของ provider
+	Email       string             `bson:"email"`   // อีเมลตาม provider ตอนผูกบัญชี
+	CreatedAt   time.Time          `bson:"createdAt"`
+	LastLoginAt *time.Time         `bson:"lastLoginAt,omitempty"`
+}
+
+// การตั้งค่าของ SAML 2.0 IdP และการแปลง attribute ใน assertion เป็นข้อมูลผู้ใช้
+type SAMLSettings struct {
+	EntityID     string   `bson:"entityId" json:"entityId"`         // Issuer ของ assertion
+	SSOURL       string   `bson:"ssoUrl" json:"ssoUrl"`             // endpoint ที่รับ AuthnRequest (HTTP-Redirect binding)
+	Certificates []string `bson:"certificates" json:"certificates"` // certificate (PEM) ที่ IdP ใช้เซ็น assertion
+
+	// ชื่อ attribute ใน assertion (ว่าง = ชื่อมาตรฐานที่พบบ่อย เช่น email, mail)
+	EmailAttribute    string `bson:"emailAttribute,omitempty" json:"emailAttribute,omitempty"`
+	NameAttribute     string `bson:"nameAttribute,omitempty" json:"nameAttribute,omitempty"`
+	UsernameAttribute string `bson:"usernameAttribute,omitempty" json:"usernameAttribute,omitempty"`
+	GroupsAttribute   string `bson:"groupsAttribute,omitempty" json:"groupsAttribute,omitempty"`
+
+	// role ตามค่าใน GroupsAttribute (ใช้ mapping แรกที่ตรง ไม่ตรงเลย = DefaultRole)
+	// ว่าง = ไม่กำหนด role จาก assertion
+	RoleMappings []RoleMapping `bson:"roleMappings,omitempty" json:"roleMappings,omitempty"`
+	DefaultRole  string        `bson:"defaultRole,omitempty" json:"defaultRole,omitempty"`
+	// รับ assertion ที่ IdP ส่งมาเองโดยไม่มี AuthnRequest จากระบบนี้
+	AllowIdPInitiated bool `bson:"allowIdpInitiated" json:"allowIdpInitiated"`
+}
+
+// กลุ่มกับ role ที่ผู้ใช้ในกลุ่มได้รับ (ใช้กับกลุ่มใน directory และ attribute กลุ่มของ SAML)
+type RoleMapping struct {
+	Group string `bson:"group" json:"group"`
+	Role  string `bson:"role" json:"role"`
+}
@@ -0,0 +1,58 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// scope มาตรฐานของ OpenID Connect (ใช้ร่วมกับ scope ของ API เช่น users:read)
+const (
+	ScopeOpenID        = "openid"
+	ScopeProfile       = "profile"
+	ScopeEmail         = "email"
+	ScopePhone         = "phone"
+	ScopeOfflineAccess = "offline_access" // ขอ refresh token
+)
+
+// OAuthClient คือแอปที่ขอสิทธิ์แทนผู้ใช้ด้วย authorization code flow (ต้องใช้ PKCE เสมอ)
+type OAuthClient struct {
+	ID           primitive.ObjectID `bson:"_id"`
+	TenantID     string             `bson:"tenantId"` // ผู้ใช้ที่เข้าสู่ระบบผ่าน client ต้องอยู่ใน tenant นี้
+	ClientID     string             `bson:"clientId"` // ไม่ซ้ำกันทั้งระบบ
+	Name         string             `bson:"name"`     // ชื่อที่แสดงในหน้าขอความยินยอม
+	RedirectURIs []string           `bson:"redirectUris"`
+	Scopes       []string           `bson:"scopes"`  // scope สูงสุดที่ client ขอได้
+	Public       bool               `bson:"public"`  // client ที่เก็บ secret ไม่ได้ (SPA, mobile) ไม่มี client secret
+	Trusted      bool               `bson:"trusted"` // แอปของระบบเอง ข้ามหน้าขอความยินยอม
+	// secret ที่ยังใช้ได้ ตัวแรกคือ secret ล่าสุด (ว่างเสมอสำหรับ public client)
+	Secrets   []ClientSecret `bson:"secrets"`
+	CreatedAt time.Time      `bson:"createdAt"`
+	UpdatedAt time.Time      `bson:"updatedAt"`
+}
+
+// ความยินยอมของผู้ใช้ที่ให้ client เข้าถึงข้อมูลตาม scope (หนึ่งรายการต่อผู้ใช้ต่อ client)
+// refresh token ผูกกับ GrantID ลบความยินยอมแล้ว refresh token ทั้งหมดของ client นั้นใช้ไม่ได้ทันที
+type OAuthConsent struct {
+	TenantID  string             `bson:"tenantId"`
+	UserID    primitive.ObjectID `bson:"userId"`
+	ClientID  string             `bson:"clientId"`
+	Scopes    []string           `bson:"scopes"`
+	GrantID   string             `bson:"grantId"`
+	CreatedAt time.Time          `bson:"createdAt"`
+	UpdatedAt time.Time          `bson:"updatedAt"`
+}
+
+// private key (RSA) ที่ใช้เซ็น ID token ของ OpenID Connect
+type OIDCSigningKey struct {
+	ID         string    `bson:"id" json:"id"`                 // kid ใน header ของ ID token และใน JWKS
+	PrivateKey string    `bson:"privateKey" json:"privateKey"` // PKCS#8 PEM
+	CreatedAt  time.Time `bson:"createdAt" json:"createdAt"`
+}
+
+// ชุด signing key ของ OpenID Connect เก็บเป็น document เดียวใน collection settings
+type OIDCKeySet struct {
+	ID        string           `bson:"_id"`  // "oidc_signing_keys"
+	Keys      []OIDCSigningKey `bson:"keys"` // ตัวแรกคือ key ที่ใช้เซ็นในปัจจุบัน
+	UpdatedAt time.Time        `bson:"updatedAt"`
+}
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// OutboxEvent คือ domain event ที่บันทึกใน transaction เดียวกับการเปลี่ยนแปลงข้อมูล
+// relay อ่านรายการที่ยังไม่เผยแพร่ไปส่งผ่าน Publisher (อย่างน้อยหนึ่งครั้ง โดยใช้ ID กันการประมวลผลซ้ำ)
+type OutboxEvent struct {
+	ID            primitive.ObjectID `bson:"_id"`
+	TenantID      string             `bson:"tenantId"`
+	Type          string             `bson:"type"`        // เช่น "user.registered", "token.revoked"
+	AggregateID   string             `bson:"aggregateId"` // ID ของข้อมูลที่เปลี่ยน เช่น ID ผู้ใช้
+	Payload       string             `bson:"payload"`     // ข้อมูลของ event (JSON)
+	CreatedAt     time.Time          `bson:"createdAt"`
+	PublishedAt   *time.Time         `bson:"publishedAt,omitempty"`
+	Attempts      int                `bson:"attempts"`
+	NextAttemptAt time.Time          `bson:"nextAttemptAt"` // เวลาที่เผยแพร่ครั้งถัดไปได้ (ระหว่างเผยแพร่คือเวลาหมด lease)
+	LastError     string             `bson:"lastError"`
+}
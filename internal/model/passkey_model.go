@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Passkey คือ WebAuthn credential ที่ผู้ใช้ลงทะเบียนไว้ ใช้เข้าสู่ระบบแทนรหัสผ่านหรือยืนยันตัวตนขั้นที่สอง
+// ผู้ใช้หนึ่งคนมีได้หลาย passkey (หนึ่งตัวต่อ authenticator)
+type Passkey struct {
+	ID                primitive.ObjectID `bson:"_id"`
+	TenantID          string             `bson:"tenantId"`
+	UserID            primitive.ObjectID `bson:"userId"`
+	Name              string             `bson:"name"`         // ชื่อที่ผู้ใช้ตั้ง (เช่น "iPhone", "YubiKey")
+	CredentialID      string             `bson:"credentialId"` // credential ID แบบ base64url (ไม่ซ้ำกันภายใน tenant)
+	PublicKey         []byte             `bson:"publicKey"`    // COSE_Key ที่ใช้ตรวจลายเซ็นของ assertion
+	Algorithm         int64              `bson:"algorithm"`
+	SignCount         uint32             `bson:"signCount"` // ค่าล่าสุดของ sign counter (0 = authenticator ไม่มี counter)
+	AAGUID            string             `bson:"aaguid"`    // รุ่นของ authenticator (UUID)
+	AttestationFormat string             `bson:"attestationFormat"`
+	Transports        []string           `bson:"transports"`
+	BackupEligible    bool               `bson:"backupEligible"` // passkey ที่ sync ข้ามอุปกรณ์ได้
+	BackedUp          bool               `bson:"backedUp"`
+	CreatedAt         time.Time          `bson:"createdAt"`
+	LastUsedAt        *time.Time         `bson:"lastUsedAt,omitempty"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// role ของ service account ที่ไม่ได้เป็น admin (สิทธิ์ถูกจำกัดด้วย scope ของ token)
+const ServiceAccountRole = "service"
+
+// client secret ของ service account (เก็บเฉพาะ hash)
+type ClientSecret struct {
+	ID        string     `bson:"id" json:"id"`
+	Hash      string     `bson:"hash" json:"hash"`     // SHA-256 ของ secret
+	Prefix    string     `bson:"prefix" json:"prefix"` // ส่วนต้นของ secret ใช้แสดงผล
+	CreatedAt time.Time  `bson:"createdAt" json:"createdAt"`
+	ExpiresAt *time.Time `bson:"expiresAt,omitempty" json:"expiresAt,omitempty"` // secret เดิมหลัง rotate ใช้ได้ถึงเวลานี้ (nil = ไม่หมดอายุ)
+}
+
+// public key ที่ใช้ตรวจสอบ client assertion (JWT ที่ client เซ็นด้วย private key ของตัวเอง)
+type ClientPublicKey struct {
+	ID        string    `bson:"id" json:"id"`   // kid ใน header ของ client assertion
+	PEM       string    `bson:"pem" json:"pem"` // RSA หรือ EC public key ในรูปแบบ PEM
+	CreatedAt time.Time `bson:"createdAt" json:"createdAt"`
+}
+
+// ServiceAccount คือตัวตนของ backend service (ไม่มีรหัสผ่าน) ขอ token ด้วย OAuth2 client_credentials
+type ServiceAccount struct {
+	ID          primitive.ObjectID `bson:"_id"`
+	TenantID    string             `bson:"tenantId"`
+	Name        string             `bson:"name"`
+	Description string             `bson:"description,omitempty"`
+	ClientID    string             `bson:"clientId"` // ไม่ซ้ำกันทั้งระบบ
+	Role        string             `bson:"role"`     // "service", "tenant_admin" หรือ "admin" (เฉพาะ tenant default)
+	Scopes      []string           `bson:"scopes"`   // scope สูงสุดที่ขอใน token ได้
+	// secret ที่ยังใช้ได้ ตัวแรกคือ secret ล่าสุด
+	Secrets    []ClientSecret    `bson:"secrets"`
+	PublicKeys []ClientPublicKey `bson:"publicKeys"`
+	CreatedAt  time.Time         `bson:"createdAt"`
+	UpdatedAt  time.Time         `bson:"updatedAt"`
+}
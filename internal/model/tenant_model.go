@@ -0,0 +1,70 @@
+package models
+
+import "time"
+
+// tenant เริ่มต้นของข้อมูลเดิมก่อนรองรับหลาย tenant และของ request ที่ไม่ระบุ tenant
+const DefaultTenantID = "default"
+
+// สถานะของ tenant
+const (
+	TenantActive    = "active"
+	TenantSuspended = "suspended" // ผู้ใช้ของ tenant นี้เข้าสู่ระบบหรือใช้ token ไม่ได้
+)
+
+// นโยบายรหัสผ่านของ tenant
+type PasswordPolicy struct {
+	MinLength     int           `bson:"minLength" json:"minLength"`
+	RequireUpper  bool          `bson:"requireUpper" json:"requireUpper"`
+	RequireLower  bool          `bson:"requireLower" json:"requireLower"`
+	RequireNumber bool          `bson:"requireNumber" json:"requireNumber"`
+	RequireSymbol bool          `bson:"requireSymbol" json:"requireSymbol"`
+	HistorySize   int           `bson:"historySize" json:"historySize"` // จำนวนรหัสผ่านเก่าที่ห้ามใช้ซ้ำ
+	MaxAge        time.Duration `bson:"maxAge" json:"maxAge"`           // อายุสูงสุดของรหัสผ่าน (0 = ไม่หมดอายุ)
+}
+
+// key สำหรับเซ็น JWT ของ tenant (ระบุด้วย kid ใน header ของ token)
+// key ว่างจากข้อมูลเก่าที่เคยแทน secret ร่วมของระบบถูกตัดออกตอนเริ่ม server (ดู service.EnsureSigningKeys)
+type SigningKey struct {
+	ID        string    `bson:"id" json:"id"`
+	Secret    []byte    `bson:"secret" json:"secret"`
+	CreatedAt time.Time `bson:"createdAt" json:"createdAt"`
+}
+
+// Tenant คือกลุ่มผู้ใช้ที่แยกขาดจากกัน (เช่น แต่ละผลิตภัณฑ์) พร้อมการตั้งค่าของตัวเอง
+type Tenant struct {
+	ID             string         `bson:"_id"`
+	Name           string         `bson:"name"`
+	Status         string         `bson:"status"`
+	PasswordPolicy PasswordPolicy `bson:"passwordPolicy"`
+	AccessTokenTTL time.Duration  `bson:"accessTokenTtl"` // อายุของ JWT
+	SessionTTL     time.Duration  `bson:"sessionTtl"`     // อายุของ active token ที่เก็บไว้
+	// key ที่ใช้ตรวจสอบ token ได้ ตัวแรกคือ key ที่ใช้เซ็น token ใหม่
+	// tenant ที่ไม่มี key เลยเซ็นและตรวจสอบ token ไม่ได้ (ไม่มี secret ร่วมของระบบ)
+	SigningKeys []SigningKey `bson:"signingKeys"`
+	CreatedAt   time.Time    `bson:"createdAt"`
+	UpdatedAt   time.Time    `bson:"updatedAt"`
+}
+
+// นโยบายรหัสผ่านเริ่มต้น (ตรงกับกฎเดิมก่อนรองรับหลาย tenant)
+func DefaultPasswordPolicy() PasswordPolicy {
+	return PasswordPolicy{
+		MinLength:     6,
+		RequireUpper:  true,
+		RequireLower:  true,
+		RequireNumber: true,
+		HistorySize:   5,
+		MaxAge:        90 * 24 * time.Hour,
+	}
+}
+
+// การตั้งค่าของ tenant default เมื่อยังไม่เคยบันทึกไว้
+func DefaultTenant() *Tenant {
+	return &Tenant{
+		ID:             DefaultTenantID,
+		Name:           "Default",
+		Status:         TenantActive,
+		PasswordPolicy: DefaultPasswordPolicy(),
+		AccessTokenTTL: 5 * time.Minute,
+		SessionTTL:     24 * time.Hour,
+	}
+}
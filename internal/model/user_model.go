@@ -6,10 +6,43 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-var User struct {
-	ID        primitive.ObjectID `bson:"_id"`
-	Email     string             `bson:"email"`
-	Username  string             `bson:"username"`
-	CreatedAt time.Time          `bson:"createdAt"`
-	UpdatedAt time.Time          `bson:"updatedAt"`
+// ข้อมูลผู้ใช้ใน collection users
+// hash รหัสผ่านมีไว้ใช้ภายใน service เท่านั้น ห้ามส่งกลับไปยัง client
+type User struct {
+	ID                primitive.ObjectID `bson:"_id"`
+	TenantID          string             `bson:"tenantId"` // อีเมลและ username ไม่ซ้ำกันภายใน tenant เดียวกัน
+	Email             string             `bson:"email"`
+	Username          string             `bson:"username"`
+	Password          string             `bson:"password"`
+	PasswordHistory   []string           `bson:"passwordHistory"`
+	PasswordChangedAt *time.Time         `bson:"passwordChangedAt,omitempty"`
+	Role              string             `bson:"role"`
+	EmailVerified     bool               `bson:"emailVerified"`
+	DisplayName       string             `bson:"displayName,omitempty"`
+	AvatarURL         string             `bson:"avatarUrl,omitempty"`
+	Locale            string             `bson:"locale,omitempty"`
+	Timezone          string             `bson:"timezone,omitempty"`
+	Phone             string             `bson:"phone,omitempty"`
+	Metadata          map[string]string  `bson:"metadata,omitempty"` // ข้อมูลเพิ่มเติมตาม ProfileSchema
+	PasskeyRequired   bool               `bson:"passkeyRequired"`    // เข้าสู่ระบบด้วยรหัสผ่านแล้วต้องยืนยันด้วย passkey อีกขั้น
+	Deleted           bool               `bson:"deleted"`
+	DeletedAt         *time.Time         `bson:"deletedAt"`
+	CreatedAt         time.Time          `bson:"createdAt"`
+	UpdatedAt         time.Time          `bson:"updatedAt"`
+}
+
+// นิยามของ key หนึ่งตัวใน metadata ของผู้ใช้
+type ProfileAttribute struct {
+	Key           string   `bson:"key"`
+	Type          string   `bson:"type"` // "string", "number" หรือ "boolean"
+	Required      bool     `bson:"required"`
+	MaxLength     int      `bson:"maxLength"`
+	AllowedValues []string `bson:"allowedValues,omitempty"`
+}
+
+// schema ของ metadata ที่ admin กำหนด เก็บเป็น document เดียวใน collection settings
+type ProfileSchema struct {
+	ID         string             `bson:"_id"` // "profile_schema"
+	Attributes []ProfileAttribute `bson:"attributes"`
+	UpdatedAt  time.Time          `bson:"updatedAt"`
 }
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// สถานะของการส่ง webhook
+const (
+	DeliveryPending   = "pending"   // รอส่ง (ครั้งแรกหรือรอ retry)
+	DeliverySucceeded = "succeeded" // ปลายทางตอบ 2xx แล้ว
+	DeliveryDead      = "dead"      // ส่งไม่สำเร็จครบจำนวนครั้งแล้ว (dead letter)
+)
+
+// Webhook ปลายทางที่ได้รับเหตุการณ์ของผู้ใช้ใน tenant ผ่าน HTTP POST
+type Webhook struct {
+	ID          primitive.ObjectID `bson:"_id"`
+	TenantID    string             `bson:"tenantId"`
+	URL         string             `bson:"url"`
+	Secret      string             `bson:"secret"` // ใช้ลงลายเซ็น HMAC-SHA256 ของ payload
+	Events      []string           `bson:"events"` // ชื่อเหตุการณ์ที่ต้องการ เช่น "user.deleted", "user.*" หรือ "*"
+	Description string             `bson:"description"`
+	CreatedAt   time.Time          `bson:"createdAt"`
+}
+
+// WebhookDelivery การส่งเหตุการณ์หนึ่งไปยัง webhook หนึ่ง (คิวที่เก็บในฐานข้อมูล)
+type WebhookDelivery struct {
+	ID             primitive.ObjectID `bson:"_id"`
+	TenantID       string             `bson:"tenantId"`
+	WebhookID      primitive.ObjectID `bson:"webhookId"`
+	EventID        string             `bson:"eventId"` // ID ของเหตุการณ์ ปลายทางใช้กันการประมวลผลซ้ำ
+	EventType      string             `bson:"eventType"`
+	Payload        string             `bson:"payload"` // body ที่ส่ง (JSON)
+	Status         string             `bson:"status"`
+	Attempts       int                `bson:"attempts"`
+	NextAttemptAt  time.Time          `bson:"nextAttemptAt"`  // เวลาที่ส่งครั้งถัดไปได้ (ระหว่างส่งคือเวลาหมด lease)
+	LastError      string             `bson:"lastError"`      // สาเหตุที่ส่งไม่สำเร็จครั้งล่าสุด
+	ResponseStatus int                `bson:"responseStatus"` // HTTP status ล่าสุดจากปลายทาง (0 = ไม่ได้รับคำตอบ)
+	CreatedAt      time.Time          `bson:"createdAt"`
+	DeliveredAt    *time.Time         `bson:"deliveredAt,omitempty"`
+}
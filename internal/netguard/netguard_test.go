@@ -0,0 +1,63 @@
+package netguard
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestIsPublicIP(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:2800:220:1:248:1893:25c8:1946", true},
+		{"127.0.0.1", false},
+		{"10.0.0.1", false},
+		{"172.16.5.4", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"100.64.0.1", false},
+		{"0.0.0.0", false},
+		{"::1", false},
+		{"fd00::1", false},
+		{"fe80::1", false},
+		{"::ffff:127.0.0.1", false},
+		{"64:ff9b::a00:1", false},
+	}
+	for _, tt := range tests {
+		if got := IsPublicIP(net.ParseIP(tt.ip)); got != tt.want {
+			t.Errorf("IsPublicIP(%s) = %v, want %v", tt.ip, got, tt.want)
+		}
+	}
+}
+
+func TestPublicOnly(t *testing.T) {
+	for _, address := range []string{"127.0.0.1:443", "10.0.0.1:443", "169.254.169.254:80", "[::1]:443", "[fd00::1]:443"} {
+		if err := PublicOnly("tcp", address, nil); !errors.Is(err, ErrForbiddenAddress) {
+			t.Errorf("PublicOnly(%s) = %v, want ErrForbiddenAddress", address, err)
+		}
+	}
+	if err := PublicOnly("tcp", "93.184.216.34:443", nil); err != nil {
+		t.Errorf("PublicOnly of a public address: %v", err)
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	// server บน loopback เชื่อมต่อไม่ได้ เว้นแต่จะเปิด allowLocal (เครื่อง dev)
+	if _, err := NewClient(time.Second, false).Get(server.URL); !errors.Is(err, ErrForbiddenAddress) {
+		t.Fatalf("Get of a loopback address = %v, want ErrForbiddenAddress", err)
+	}
+	resp, err := NewClient(time.Second, true).Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get with allowLocal: %v", err)
+	}
+	resp.Body.Close()
+}
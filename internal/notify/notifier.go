@@ -0,0 +1,31 @@
+package notify
+
+import (
+	"context"
+	"log"
+)
+
+// ข้อความที่จะส่งถึงผู้ใช้ (เช่น อีเมลยืนยัน)
+type Message struct {
+	To      string // ที่อยู่ผู้รับ
+	Subject string // หัวข้อ
+	Body    string // เนื้อหา
+}
+
+// Notifier คือช่องทางส่งข้อความถึงผู้ใช้ เปลี่ยน implementation ได้ (เช่น SMTP, ผู้ให้บริการอีเมล)
+type Notifier interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// LogNotifier พิมพ์ข้อความลง log แทนการส่งจริง ใช้สำหรับ development
+type LogNotifier struct{}
+
+// สร้างอินสแตนซ์ของ LogNotifier
+func NewLogNotifier() *LogNotifier {
+	return &LogNotifier{}
+}
+
+func (n *LogNotifier) Send(ctx context.Context, msg Message) error {
+	log.Printf("[notify] to=%s subject=%q\n%s", msg.To, msg.Subject, msg.Body)
+	return nil
+}
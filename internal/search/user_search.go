@@ -0,0 +1,93 @@
+package search
+
+import (
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ความยาวสูงสุดของคำค้นหา
+const maxTermLength = 100
+
+// รูปแบบการจับคู่คำค้นหา
+type MatchMode string
+
+const (
+	MatchPrefix   MatchMode = "prefix"   // ขึ้นต้นด้วยคำค้นหา (ใช้ index ได้)
+	MatchExact    MatchMode = "exact"    // ตรงทั้งคำ (ใช้ index ได้)
+	MatchContains MatchMode = "contains" // มีคำค้นหาอยู่ภายใน (ต้อง scan)
+)
+
+// การกรองผู้ใช้ที่ถูก soft delete
+type DeletedFilter string
+
+const (
+	DeletedExclude DeletedFilter = "exclude" // ไม่รวมผู้ใช้ที่ถูกลบ (ค่าเริ่มต้น)
+	DeletedInclude DeletedFilter = "include" // รวมทั้งหมด
+	DeletedOnly    DeletedFilter = "only"    // เฉพาะผู้ใช้ที่ถูกลบ
+)
+
+// เงื่อนไขค้นหาผู้ใช้
+type UserQuery struct {
+	TenantID      string // "" = ทุก tenant
+	Username      string
+	Email         string
+	Mode          MatchMode
+	Role          string
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	Verified      *bool
+	Deleted       DeletedFilter
+}
+
+// แปลง string จาก request เป็น MatchMode
+func ParseMatchMode(mode string) (MatchMode, error) {
+	switch MatchMode(mode) {
+	case "":
+		return MatchPrefix, nil
+	case MatchPrefix, MatchExact, MatchContains:
+		return MatchMode(mode), nil
+	}
+	return "", status.Error(codes.InvalidArgument, "matchMode ต้องเป็น prefix, exact หรือ contains")
+}
+
+// แปลง string จาก request เป็น DeletedFilter
+func ParseDeletedFilter(deleted string) (DeletedFilter, error) {
+	switch DeletedFilter(deleted) {
+	case "":
+		return DeletedExclude, nil
+	case DeletedExclude, DeletedInclude, DeletedOnly:
+		return DeletedFilter(deleted), nil
+	}
+	return "", status.Error(codes.InvalidArgument, "deleted ต้องเป็น exclude, include หรือ only")
+}
+
+// แปลงช่วงเวลาในรูปแบบ RFC3339 (ค่าว่าง = ไม่กำหนด)
+func ParseTime(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "รูปแบบเวลา %q ไม่ถูกต้อง (ต้องเป็น RFC3339)", value)
+	}
+	return &t, nil
+}
+
+// ตรวจสอบความยาวของคำค้นหา (ข้อความจากผู้ใช้ไม่ถูกตีความเป็น regex หรือ pattern ในทุก backend)
+func (q UserQuery) Validate() error {
+	for _, term := range []string{q.Username, q.Email} {
+		if len(term) > maxTermLength {
+			return status.Errorf(codes.InvalidArgument, "คำค้นหาต้องมีความยาวไม่เกิน %d ตัวอักษร", maxTermLength)
+		}
+	}
+	return nil
+}
+
+// ไม่มีเงื่อนไขค้นหาอื่นนอกจากค่าเริ่มต้น (ไม่รวมผู้ใช้ที่ถูกลบ) ใช้ตัดสินว่านับแบบประมาณได้หรือไม่
+func (q UserQuery) IsDefault() bool {
+	return q.TenantID == "" && q.Username == "" && q.Email == "" && q.Role == "" &&
+		q.CreatedAfter == nil && q.CreatedBefore == nil && q.Verified == nil &&
+		(q.Deleted == "" || q.Deleted == DeletedExclude)
+}
@@ -0,0 +1,53 @@
+package server
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"auth-microservice/internal/audit"
+	"auth-microservice/internal/config"
+	"auth-microservice/internal/service"
+)
+
+// รันคำสั่ง create-admin: สร้าง admin ของระบบคนแรก (การสมัครสมาชิกผ่าน gRPC ได้รับ role user เท่านั้น)
+func RunCreateAdmin(args []string) error {
+	fs := flag.NewFlagSet("create-admin", flag.ContinueOnError)
+	email := fs.String("email", "", "admin email (required)")
+	username := fs.String("username", "", "admin username (required for a new user)")
+	password := fs.String("password", "", "admin password (required for a new user)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *email == "" {
+		return fmt.Errorf("usage: create-admin -email e [-username u -password p]")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	stores, migrator, err := openStorage(cfg)
+	if err != nil {
+		return err
+	}
+	defer stores.Close(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	if _, err := migrator.Up(ctx); err != nil {
+		return err
+	}
+
+	created, err := service.BootstrapAdmin(ctx, stores, audit.NewLogger(stores.Audit), *email, *username, *password)
+	if err != nil {
+		return err
+	}
+	if created {
+		fmt.Printf("created platform admin %s\n", *email)
+	} else {
+		fmt.Printf("%s is a platform admin\n", *email)
+	}
+	return nil
+}
@@ -4,94 +4,142 @@ import (
 	"context"
 	"log"
 	"net"
+	"net/http"
+	"time"
 
-	"auth-microservice/internal/db"
+	"auth-microservice/internal/audit"
+	"auth-microservice/internal/config"
+	"auth-microservice/internal/events"
+	"auth-microservice/internal/notify"
 	"auth-microservice/internal/service"
+	"auth-microservice/internal/webauthn"
+	"auth-microservice/internal/webhook"
 
 	pb "auth-microservice/auth-microservice/proto"
 
-	"github.com/redis/go-redis/v9"
 	"google.golang.org/grpc"
 )
 
-const grpcPort = ":50051" // พอร์ตที่ gRPC server จะรับการเชื่อมต่อ
+const (
+	deletedUserRetention = 30 * 24 * time.Hour // ระยะเวลาเก็บผู้ใช้ที่ถูก soft delete ก่อนลบถาวร
+	retentionJobInterval = time.Hour           // ความถี่ในการรันงานลบผู้ใช้ที่หมดระยะเก็บรักษา
+	webhookPollInterval  = 5 * time.Second     // ความถี่ในการตรวจคิวการส่ง webhook
+	outboxRelayInterval  = time.Second         // ความถี่ในการเผยแพร่ domain event จาก outbox
+)
 
 func RunGRPCServer() error {
-	//  ===== เชื่อมต่อ MongoDB  =====
-	client, userCollection, blacklistCollection, err := db.InitMongo()
+	// ===== อ่านการตั้งค่า (เลือก backend ด้วย STORAGE_BACKEND) =====
+	cfg, err := config.Load()
 	if err != nil {
 		return err
 	}
-	defer client.Disconnect(context.Background()) // ปิดการเชื่อมต่อเมื่อ server หยุดทำงาน
 
-	// ===== เชื่อมต่อกับ Redis =====
-	rdb := redis.NewClient(&redis.Options{
-		Addr:     "localhost:6379",
-		Password: "",
-		DB:       0,
-	})
+	//  ===== เชื่อมต่อฐานข้อมูล (MongoDB + Redis, PostgreSQL หรือ SQLite) =====
+	stores, migrator, err := openStorage(cfg)
+	if err != nil {
+		return err
+	}
+	defer stores.Close(context.Background()) // ปิดการเชื่อมต่อเมื่อ server หยุดทำงาน
 
-	// ===== กำหนดพอร์ต gRPC listener  =====
-	lis, err := net.Listen("tcp", grpcPort)
+	// รัน migration ที่ยังไม่ได้รัน (สร้างตาราง/unique index, TTL index และ index สำหรับค้นหา)
+	ran, err := migrator.Up(context.Background())
+	if err != nil {
+		return err
+	}
+	for _, m := range ran {
+		log.Printf("Applied migration %d: %s", m.Version, m.Name)
+	}
+
+	// สร้าง signing key ให้ tenant ที่ยังไม่มี (รวมถึง tenant default ครั้งแรก) token ทุกตัวเซ็นด้วย key ของ tenant เท่านั้น
+	generated, err := service.EnsureSigningKeys(context.Background(), stores.Tenants)
 	if err != nil {
 		return err
 	}
+	for _, id := range generated {
+		log.Printf("Generated signing key for tenant %s", id)
+	}
 
-	// ===== สร้าง gRPC Server =====
-	grpcServer := grpc.NewServer()
+	// ===== กำหนดพอร์ต gRPC listener  =====
+	lis, err := net.Listen("tcp", cfg.GRPCPort)
+	if err != nil {
+		return err
+	}
 
 	//===== สร้าง service instances และ inject dependencies =====
-	authService := service.NewAuthService(userCollection, blacklistCollection, rdb)
-	userService := service.NewUserService(userCollection)
+	auditLogger := audit.NewLogger(stores.Audit)
+	webhooks := webhook.NewDispatcher(stores.Webhooks, cfg.WebhookAllowLocal)
+	auditLogger.Sinks = append(auditLogger.Sinks, webhooks) // เหตุการณ์ที่บันทึกแล้วเข้าคิวของ webhook ที่สนใจ
+	apiKeyService := service.NewAPIKeyService(stores, auditLogger)
+
+	// ===== สร้าง gRPC Server (ตรวจสอบ "authorization: ApiKey <key>" ก่อนเข้าทุก service) =====
+	grpcServer := grpc.NewServer(
+		grpc.UnaryInterceptor(apiKeyService.UnaryInterceptor),
+		grpc.StreamInterceptor(apiKeyService.StreamInterceptor),
+	)
+
+	notifier := notify.NewLogNotifier()
+	authService := service.NewAuthService(stores, notifier, auditLogger)
+	bus := events.NewMemoryBus() // domain event ภายใน process ที่ relay เผยแพร่จาก outbox
+	userService := service.NewUserService(stores, bus, auditLogger)
+	tenantService := service.NewTenantService(stores, auditLogger)
+	groupService := service.NewGroupService(stores, notifier, auditLogger)
+	serviceAccountService := service.NewServiceAccountService(stores, auditLogger)
+	oauthService := service.NewOAuthService(stores, cfg.Issuer, auditLogger)
+	oauthClientService := service.NewOAuthClientService(stores, auditLogger)
+	federationService := service.NewFederationService(stores, cfg.Issuer, cfg.FederationAllowLocal, auditLogger)
+	passkeyService := service.NewPasskeyService(stores, webauthn.Config{
+		RPID:        cfg.WebAuthnRPID,
+		RPName:      cfg.WebAuthnRPName,
+		Origins:     cfg.WebAuthnOrigins,
+		Attestation: cfg.WebAuthnAttestation,
+	}, auditLogger)
+	webhookService := service.NewWebhookService(stores, cfg.WebhookAllowLocal, auditLogger)
+	auditService := service.NewAuditService(stores)
+
+	// ===== งานเบื้องหลัง: ลบผู้ใช้ที่ถูก soft delete เกินระยะเก็บรักษา และส่งเหตุการณ์ไปยัง webhook =====
+	jobCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	go userService.RunRetentionJob(jobCtx, retentionJobInterval, deletedUserRetention)
+	go webhooks.Run(jobCtx, webhookPollInterval)
+
+	// ===== เผยแพร่ domain event จาก outbox (EVENT_PUBLISHER) =====
+	publisher := newPublisher(cfg, bus)
+	defer publisher.Close()
+	go events.NewRelay(stores.Outbox, publisher).Run(jobCtx, outboxRelayInterval)
+	log.Printf("Publishing outbox events via %s", cfg.EventPublisher)
 
 	// ===== Register gRPC service =====
 	pb.RegisterAuthServiceServer(grpcServer, authService)
 	pb.RegisterUserServiceServer(grpcServer, userService)
-	log.Printf("gRPC server listening on %s", grpcPort)
+	pb.RegisterTenantServiceServer(grpcServer, tenantService)
+	pb.RegisterGroupServiceServer(grpcServer, groupService)
+	pb.RegisterAPIKeyServiceServer(grpcServer, apiKeyService)
+	pb.RegisterServiceAccountServiceServer(grpcServer, serviceAccountService)
+	pb.RegisterOAuthServiceServer(grpcServer, oauthService)
+	pb.RegisterOAuthClientServiceServer(grpcServer, oauthClientService)
+	pb.RegisterFederationServiceServer(grpcServer, federationService)
+	pb.RegisterPasskeyServiceServer(grpcServer, passkeyService)
+	pb.RegisterWebhookServiceServer(grpcServer, webhookService)
+	pb.RegisterAuditServiceServer(grpcServer, auditService)
+
+	// ===== HTTP server สำหรับ endpoint ของ OAuth 2.1 / OpenID Connect (client ที่ไม่ใช้ gRPC) =====
+	httpLis, err := net.Listen("tcp", cfg.HTTPPort)
+	if err != nil {
+		return err
+	}
+	httpServer := &http.Server{
+		Handler:           newHTTPHandler(oauthService, federationService, cfg.LoginURL),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	defer httpServer.Close()
+	go func() {
+		if err := httpServer.Serve(httpLis); err != nil && err != http.ErrServerClosed {
+			log.Printf("HTTP server failed: %v", err)
+		}
+	}()
+	log.Printf("HTTP server listening on %s", cfg.HTTPPort)
+	log.Printf("gRPC server listening on %s (storage: %s)", cfg.GRPCPort, cfg.StorageBackend)
 
 	// เริ่มรัน gRPC
 	return grpcServer.Serve(lis)
 }
-
-// แก้จาก `error` เป็น `(*service.AuthService, error)`
-// func RunGRPCServer() (*service.AuthService, error) {
-// 	// ===== เชื่อมต่อ MongoDB =====
-// 	_, userCollection, blacklistCollection, err := db.InitMongo()
-// 	if err != nil {
-// 		return nil, err
-// 	}
-// 	// ไม่ defer Disconnect ที่นี่ เพราะเราจะ return service ไปใช้งานต่อ
-
-// 	// ===== เชื่อมต่อกับ Redis =====
-// 	rdb := redis.NewClient(&redis.Options{
-// 		Addr:     "localhost:6379",
-// 		Password: "",
-// 		DB:       0,
-// 	})
-
-// 	// ===== สร้าง service instances =====
-// 	authService := service.NewAuthService(userCollection, blacklistCollection, rdb)
-// 	userService := service.NewUserService(userCollection)
-
-// 	// ===== Register gRPC =====
-// 	lis, err := net.Listen("tcp", grpcPort)
-// 	if err != nil {
-// 		return nil, err
-// 	}
-
-// 	grpcServer := grpc.NewServer()
-// 	pb.RegisterAuthServiceServer(grpcServer, authService)
-// 	pb.RegisterUserServiceServer(grpcServer, userService)
-
-// 	log.Printf("gRPC server listening on %s", grpcPort)
-
-// 	// รัน server ใน goroutine เพื่อไม่บล็อก
-// 	go func() {
-// 		if err := grpcServer.Serve(lis); err != nil {
-// 			log.Fatalf("gRPC server failed: %v", err)
-// 		}
-// 	}()
-
-// 	// return authService เพื่อให้ main.go เรียกใช้ได้
-// 	return authService, nil
-// }
@@ -0,0 +1,441 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+
+	pb "auth-microservice/auth-microservice/proto"
+	"auth-microservice/internal/service"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// สร้าง HTTP handler สำหรับ endpoint มาตรฐานของ OAuth 2.1 / OpenID Connect และ SAML 2.0 service provider
+// loginURL คือหน้าเข้าสู่ระบบ/ขอความยินยอมที่ /oauth/authorize ส่งผู้ใช้ไป
+func newHTTPHandler(oauth *service.OAuthService, federation *service.FederationService, loginURL string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/oauth/authorize", func(w http.ResponseWriter, r *http.Request) {
+		handleAuthorize(w, r, oauth, loginURL)
+	})
+	mux.HandleFunc("/oauth/consent", func(w http.ResponseWriter, r *http.Request) {
+		handleConsent(w, r, oauth)
+	})
+	mux.HandleFunc("/oauth/token", func(w http.ResponseWriter, r *http.Request) {
+		handleToken(w, r, oauth)
+	})
+	mux.HandleFunc("/oauth/userinfo", func(w http.ResponseWriter, r *http.Request) {
+		handleUserInfo(w, r, oauth)
+	})
+	mux.HandleFunc("/oauth/revoke", func(w http.ResponseWriter, r *http.Request) {
+		handleRevoke(w, r, oauth)
+	})
+	mux.HandleFunc("/oauth/introspect", func(w http.ResponseWriter, r *http.Request) {
+		handleIntrospect(w, r, oauth)
+	})
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, oauth.Discovery())
+	})
+	mux.HandleFunc("/.well-known/jwks.json", func(w http.ResponseWriter, r *http.Request) {
+		jwks, err := oauth.JWKS(r.Context())
+		if err != nil {
+			writeOAuthError(w, http.StatusInternalServerError, "server_error", "ไม่สามารถโหลด signing key ได้")
+			return
+		}
+		writeJSON(w, http.StatusOK, jwks)
+	})
+	mux.HandleFunc("GET /saml/{tenant}/{provider}/metadata", func(w http.ResponseWriter, r *http.Request) {
+		handleSAMLMetadata(w, r, federation)
+	})
+	mux.HandleFunc("POST /saml/{tenant}/{provider}/acs", func(w http.ResponseWriter, r *http.Request) {
+		handleSAMLACS(w, r, federation)
+	})
+	return mux
+}
+
+// SP metadata สำหรับลงทะเบียนระบบนี้กับ SAML IdP ของ tenant
+func handleSAMLMetadata(w http.ResponseWriter, r *http.Request, federation *service.FederationService) {
+	metadata, err := federation.SAMLMetadata(r.Context(), r.PathValue("tenant"), r.PathValue("provider"))
+	if err != nil {
+		writeStatusError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/samlmetadata+xml")
+	w.WriteHeader(http.StatusOK)
+	w.Write(metadata)
+}
+
+// assertion consumer service (HTTP-POST binding): ตรวจสอบ SAMLResponse แล้วส่งผู้ใช้กลับไปยังแอปพร้อม state และ code
+func handleSAMLACS(w http.ResponseWriter, r *http.Request, federation *service.FederationService) {
+	if err := r.ParseForm(); err != nil {
+		writeOAuthError(w, http.StatusBadRequest, "invalid_request", "form ไม่ถูกต้อง")
+		return
+	}
+	redirectURL, err := federation.ConsumeSAMLResponse(r.Context(), r.PathValue("tenant"), r.PathValue("provider"),
+		r.PostForm.Get("SAMLResponse"), r.PostForm.Get("RelayState"))
+	if err != nil {
+		writeStatusError(w, err)
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	http.Redirect(w, r, redirectURL, http.StatusSeeOther)
+}
+
+// authorization endpoint: ตรวจสอบ request แล้วส่งผู้ใช้ไปหน้าเข้าสู่ระบบพร้อม request_id
+// หรือ redirect กลับไปยัง client ทันทีเมื่อ request ไม่ถูกต้อง
+func handleAuthorize(w http.ResponseWriter, r *http.Request, oauth *service.OAuthService, loginURL string) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		w.Header().Set("Allow", "GET, POST")
+		writeOAuthError(w, http.StatusMethodNotAllowed, "invalid_request", "authorization endpoint รองรับเฉพาะ GET และ POST")
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		writeOAuthError(w, http.StatusBadRequest, "invalid_request", "request ไม่ถูกต้อง")
+		return
+	}
+
+	reply, err := oauth.Authorize(r.Context(), &pb.AuthorizeRequest{
+		ResponseType:        r.Form.Get("response_type"),
+		ClientId:            r.Form.Get("client_id"),
+		RedirectUri:         r.Form.Get("redirect_uri"),
+		Scope:               r.Form.Get("scope"),
+		State:               r.Form.Get("state"),
+		Nonce:               r.Form.Get("nonce"),
+		CodeChallenge:       r.Form.Get("code_challenge"),
+		CodeChallengeMethod: r.Form.Get("code_challenge_method"),
+	})
+	if err != nil {
+		writeStatusError(w, err)
+		return
+	}
+	if reply.GetRedirectTo() != "" {
+		http.Redirect(w, r, reply.GetRedirectTo(), http.StatusFound)
+		return
+	}
+
+	login, err := url.Parse(loginURL)
+	if err != nil {
+		writeOAuthError(w, http.StatusInternalServerError, "server_error", "OAUTH_LOGIN_URL ไม่ถูกต้อง")
+		return
+	}
+	q := login.Query()
+	q.Set("request_id", reply.GetRequestId())
+	login.RawQuery = q.Encode()
+	http.Redirect(w, r, login.String(), http.StatusFound)
+}
+
+// backend ของหน้าขอความยินยอม
+// GET ?request_id=... คืนข้อมูลของ request, POST (JSON หรือ form) บันทึกการตัดสินใจของผู้ใช้แล้วคืน redirect_to
+func handleConsent(w http.ResponseWriter, r *http.Request, oauth *service.OAuthService) {
+	ctx := bearerContext(r)
+	switch r.Method {
+	case http.MethodGet:
+		info, err := oauth.GetAuthorizationRequest(ctx, &pb.GetAuthorizationRequestRequest{RequestId: r.URL.Query().Get("request_id")})
+		if err != nil {
+			writeStatusError(w, err)
+			return
+		}
+		writeOAuthJSON(w, http.StatusOK, map[string]interface{}{
+			"request_id":      info.GetRequestId(),
+			"client_id":       info.GetClientId(),
+			"client_name":     info.GetClientName(),
+			"scopes":          info.GetScopes(),
+			"redirect_uri":    info.GetRedirectUri(),
+			"trusted":         info.GetTrusted(),
+			"consent_granted": info.GetConsentGranted(),
+			"expires_at":      info.GetExpiresAt(),
+		})
+	case http.MethodPost:
+		var body struct {
+			RequestID string   `json:"request_id"`
+			Email     string   `json:"email"`
+			Password  string   `json:"password"`
+			Approve   bool     `json:"approve"`
+			Scopes    []string `json:"scopes"`
+		}
+		if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				writeOAuthError(w, http.StatusBadRequest, "invalid_request", "JSON ไม่ถูกต้อง")
+				return
+			}
+		} else {
+			if err := r.ParseForm(); err != nil {
+				writeOAuthError(w, http.StatusBadRequest, "invalid_request", "form ไม่ถูกต้อง")
+				return
+			}
+			body.RequestID = r.PostForm.Get("request_id")
+			body.Email = r.PostForm.Get("email")
+			body.Password = r.PostForm.Get("password")
+			body.Approve = r.PostForm.Get("approve") == "true"
+			body.Scopes = strings.Fields(r.PostForm.Get("scope"))
+		}
+
+		reply, err := oauth.DecideAuthorization(ctx, &pb.DecideAuthorizationRequest{
+			RequestId: body.RequestID,
+			Email:     body.Email,
+			Password:  body.Password,
+			Approve:   body.Approve,
+			Scopes:    body.Scopes,
+		})
+		if err != nil {
+			writeStatusError(w, err)
+			return
+		}
+		writeOAuthJSON(w, http.StatusOK, map[string]interface{}{"redirect_to": reply.GetRedirectTo()})
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		writeOAuthError(w, http.StatusMethodNotAllowed, "invalid_request", "consent endpoint รองรับเฉพาะ GET และ POST")
+	}
+}
+
+// token endpoint ตาม RFC 6749: รับ application/x-www-form-urlencoded
+// client ส่ง client_id/client_secret ผ่าน HTTP Basic หรือใน form ก็ได้
+func handleToken(w http.ResponseWriter, r *http.Request, oauth *service.OAuthService) {
+	if !parseOAuthForm(w, r, "token") {
+		return
+	}
+	clientID, clientSecret, basic, ok := clientCredentials(w, r)
+	if !ok {
+		return
+	}
+
+	reply, err := oauth.Token(r.Context(), &pb.TokenRequest{
+		GrantType:           r.PostForm.Get("grant_type"),
+		ClientId:            clientID,
+		ClientSecret:        clientSecret,
+		ClientAssertionType: r.PostForm.Get("client_assertion_type"),
+		ClientAssertion:     r.PostForm.Get("client_assertion"),
+		Scope:               r.PostForm.Get("scope"),
+		Code:                r.PostForm.Get("code"),
+		RedirectUri:         r.PostForm.Get("redirect_uri"),
+		CodeVerifier:        r.PostForm.Get("code_verifier"),
+		RefreshToken:        r.PostForm.Get("refresh_token"),
+	})
+	if err != nil {
+		writeTokenError(w, err, basic)
+		return
+	}
+
+	body := map[string]interface{}{
+		"access_token": reply.GetAccessToken(),
+		"token_type":   reply.GetTokenType(),
+		"expires_in":   reply.GetExpiresIn(),
+		"scope":        reply.GetScope(),
+	}
+	if reply.GetIdToken() != "" {
+		body["id_token"] = reply.GetIdToken()
+	}
+	if reply.GetRefreshToken() != "" {
+		body["refresh_token"] = reply.GetRefreshToken()
+	}
+	writeOAuthJSON(w, http.StatusOK, body)
+}
+
+// userinfo endpoint (OpenID Connect Core ข้อ 5.3) ใช้ access token ใน header Authorization: Bearer
+func handleUserInfo(w http.ResponseWriter, r *http.Request, oauth *service.OAuthService) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		w.Header().Set("Allow", "GET, POST")
+		writeOAuthError(w, http.StatusMethodNotAllowed, "invalid_request", "userinfo endpoint รองรับเฉพาะ GET และ POST")
+		return
+	}
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="oauth"`)
+		writeOAuthError(w, http.StatusUnauthorized, "invalid_token", "ต้องแนบ access token")
+		return
+	}
+
+	reply, err := oauth.UserInfo(bearerContext(r), &pb.UserInfoRequest{})
+	if err != nil {
+		st, _ := status.FromError(err)
+		switch st.Code() {
+		case codes.Unauthenticated:
+			w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			writeOAuthError(w, http.StatusUnauthorized, "invalid_token", st.Message())
+		case codes.PermissionDenied:
+			w.Header().Set("WWW-Authenticate", `Bearer error="insufficient_scope", scope="openid"`)
+			writeOAuthError(w, http.StatusForbidden, "insufficient_scope", st.Message())
+		default:
+			writeOAuthError(w, http.StatusInternalServerError, "server_error", st.Message())
+		}
+		return
+	}
+	writeOAuthJSON(w, http.StatusOK, service.UserInfoClaims(reply))
+}
+
+// revocation endpoint (RFC 7009) ตอบ 200 แม้ token ไม่ถูกต้องหรือถูกยกเลิกไปแล้ว
+func handleRevoke(w http.ResponseWriter, r *http.Request, oauth *service.OAuthService) {
+	if !parseOAuthForm(w, r, "revocation") {
+		return
+	}
+	clientID, clientSecret, basic, ok := clientCredentials(w, r)
+	if !ok {
+		return
+	}
+
+	_, err := oauth.Revoke(r.Context(), &pb.RevokeRequest{
+		Token:         r.PostForm.Get("token"),
+		TokenTypeHint: r.PostForm.Get("token_type_hint"),
+		ClientId:      clientID,
+		ClientSecret:  clientSecret,
+	})
+	if err != nil {
+		writeTokenError(w, err, basic)
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+}
+
+// introspection endpoint (RFC 7662) สำหรับ resource server ที่เป็น confidential client
+func handleIntrospect(w http.ResponseWriter, r *http.Request, oauth *service.OAuthService) {
+	if !parseOAuthForm(w, r, "introspection") {
+		return
+	}
+	clientID, clientSecret, basic, ok := clientCredentials(w, r)
+	if !ok {
+		return
+	}
+
+	reply, err := oauth.Introspect(r.Context(), &pb.IntrospectRequest{
+		Token:         r.PostForm.Get("token"),
+		TokenTypeHint: r.PostForm.Get("token_type_hint"),
+		ClientId:      clientID,
+		ClientSecret:  clientSecret,
+	})
+	if err != nil {
+		writeTokenError(w, err, basic)
+		return
+	}
+	if !reply.GetActive() {
+		writeOAuthJSON(w, http.StatusOK, map[string]interface{}{"active": false})
+		return
+	}
+
+	body := map[string]interface{}{
+		"active":     true,
+		"token_type": reply.GetTokenType(),
+		"exp":        reply.GetExp(),
+		"iss":        reply.GetIss(),
+		"tid":        reply.GetTenantId(),
+	}
+	optional := map[string]string{
+		"scope":     reply.GetScope(),
+		"client_id": reply.GetClientId(),
+		"username":  reply.GetUsername(),
+		"sub":       reply.GetSub(),
+	}
+	for name, value := range optional {
+		if value != "" {
+			body[name] = value
+		}
+	}
+	if reply.GetIat() > 0 {
+		body["iat"] = reply.GetIat()
+	}
+	writeOAuthJSON(w, http.StatusOK, body)
+}
+
+// endpoint ที่รับ form ผ่าน POST เท่านั้น (token, revocation, introspection)
+func parseOAuthForm(w http.ResponseWriter, r *http.Request, endpoint string) bool {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeOAuthError(w, http.StatusMethodNotAllowed, "invalid_request", endpoint+" endpoint รองรับเฉพาะ POST")
+		return false
+	}
+	if err := r.ParseForm(); err != nil {
+		writeOAuthError(w, http.StatusBadRequest, "invalid_request", "form ไม่ถูกต้อง")
+		return false
+	}
+	return true
+}
+
+// client_id/client_secret จาก HTTP Basic (ถ้ามี) หรือจาก form
+func clientCredentials(w http.ResponseWriter, r *http.Request) (clientID string, clientSecret string, basic bool, ok bool) {
+	user, pass, basic := r.BasicAuth()
+	if !basic {
+		return r.PostForm.Get("client_id"), r.PostForm.Get("client_secret"), false, true
+	}
+	// ค่าใน Basic ถูก form-encode ก่อน (RFC 6749 ข้อ 2.3.1)
+	var err1, err2 error
+	clientID, err1 = url.QueryUnescape(user)
+	clientSecret, err2 = url.QueryUnescape(pass)
+	if err1 != nil || err2 != nil {
+		writeOAuthError(w, http.StatusBadRequest, "invalid_request", "Authorization header ไม่ถูกต้อง")
+		return "", "", true, false
+	}
+	return clientID, clientSecret, true, true
+}
+
+// ส่งต่อ header Authorization ให้ service อ่านได้เหมือน metadata ของ gRPC
+func bearerContext(r *http.Request) context.Context {
+	header := r.Header.Get("Authorization")
+	if header == "" {
+		return r.Context()
+	}
+	return metadata.NewIncomingContext(r.Context(), metadata.Pairs("authorization", header))
+}
+
+// แปลง error ของ token, revocation และ introspection endpoint เป็น error ตาม RFC 6749 ข้อ 5.2
+func writeTokenError(w http.ResponseWriter, err error, basic bool) {
+	st, _ := status.FromError(err)
+	switch st.Code() {
+	case codes.InvalidArgument:
+		writeOAuthError(w, http.StatusBadRequest, "invalid_request", st.Message())
+	case codes.Unimplemented:
+		writeOAuthError(w, http.StatusBadRequest, "unsupported_grant_type", st.Message())
+	case codes.Unauthenticated:
+		if basic {
+			w.Header().Set("WWW-Authenticate", `Basic realm="oauth"`)
+		}
+		writeOAuthError(w, http.StatusUnauthorized, "invalid_client", st.Message())
+	case codes.FailedPrecondition:
+		writeOAuthError(w, http.StatusBadRequest, "invalid_grant", st.Message())
+	case codes.PermissionDenied:
+		writeOAuthError(w, http.StatusBadRequest, "invalid_scope", st.Message())
+	default:
+		writeOAuthError(w, http.StatusInternalServerError, "server_error", st.Message())
+	}
+}
+
+// แปลง error ของ authorization และ consent endpoint เป็น HTTP status
+func writeStatusError(w http.ResponseWriter, err error) {
+	st, _ := status.FromError(err)
+	switch st.Code() {
+	case codes.InvalidArgument, codes.FailedPrecondition:
+		writeOAuthError(w, http.StatusBadRequest, "invalid_request", st.Message())
+	case codes.NotFound:
+		writeOAuthError(w, http.StatusNotFound, "invalid_request", st.Message())
+	case codes.Unauthenticated:
+		writeOAuthError(w, http.StatusUnauthorized, "login_required", st.Message())
+	case codes.PermissionDenied:
+		writeOAuthError(w, http.StatusForbidden, "access_denied", st.Message())
+	case codes.ResourceExhausted:
+		writeOAuthError(w, http.StatusTooManyRequests, "temporarily_unavailable", st.Message())
+	default:
+		writeOAuthError(w, http.StatusInternalServerError, "server_error", st.Message())
+	}
+}
+
+func writeOAuthError(w http.ResponseWriter, code int, errCode string, description string) {
+	writeOAuthJSON(w, code, map[string]interface{}{
+		"error":             errCode,
+		"error_description": description,
+	})
+}
+
+// response ที่มี token หรือข้อมูลผู้ใช้ห้าม cache (RFC 6749 ข้อ 5.1)
+func writeOAuthJSON(w http.ResponseWriter, code int, body map[string]interface{}) {
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Pragma", "no-cache")
+	writeJSON(w, code, body)
+}
+
+func writeJSON(w http.ResponseWriter, code int, body map[string]interface{}) {
+	w.Header().Set("Content-Type", "application/json;charset=UTF-8")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,415 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	pb "auth-microservice/auth-microservice/proto"
+	"auth-microservice/internal/audit"
+	"auth-microservice/internal/auth"
+	"auth-microservice/internal/federation/federationtest"
+	"auth-microservice/internal/notify"
+	"auth-microservice/internal/service"
+	"auth-microservice/internal/store"
+	"auth-microservice/internal/store/sqlstore"
+
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	testRedirectURI = "http://localhost:8080/callback"
+	testLoginURL    = "http://localhost:8080/login"
+	testPassword    = "Secret123!"
+)
+
+// provider ที่รันบน httptest พร้อม client ของ OAuth ที่ลงทะเบียนไว้และผู้ใช้หนึ่งคน
+type oauthProvider struct {
+	server       *httptest.Server
+	http         *http.Client
+	clientID     string
+	clientSecret string
+	email        string
+	federation   *service.FederationService
+	auth         *service.AuthService
+	adminCtx     context.Context
+}
+
+// สร้าง service ทั้งหมดบน SQLite ในไดเรกทอรีชั่วคราว แล้วเปิด HTTP endpoint ของ OAuth ด้วย httptest
+func newOAuthProvider(t *testing.T) *oauthProvider {
+	t.Helper()
+	ctx := context.Background()
+	stores := openTestStores(t)
+	auditLogger := audit.NewLogger(stores.Audit)
+
+	server := httptest.NewServer(nil)
+	t.Cleanup(server.Close)
+	oauthService := service.NewOAuthService(stores, server.URL, auditLogger)
+	federationService := service.NewFederationService(stores, server.URL, true, auditLogger)
+	server.Config.Handler = newHTTPHandler(oauthService, federationService, testLoginURL)
+
+	if _, err := service.BootstrapAdmin(ctx, stores, auditLogger, "admin@example.com", "admin", testPassword); err != nil {
+		t.Fatalf("BootstrapAdmin: %v", err)
+	}
+	authService := service.NewAuthService(stores, notify.NewLogNotifier(), auditLogger)
+	login, err := authService.Login(ctx, &pb.LoginRequest{Email: "admin@example.com", Password: testPassword})
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if _, err := authService.Register(ctx, &pb.RegisterRequest{Email: "alice@example.com", Username: "alice", Password: testPassword}); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+
+	adminCtx := metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer "+login.GetToken()))
+	created, err := service.NewOAuthClientService(stores, auditLogger).CreateOAuthClient(adminCtx, &pb.CreateOAuthClientRequest{
+		Name:         "Example App",
+		RedirectUris: []string{testRedirectURI},
+		Scopes:       []string{"openid", "profile", "email", "offline_access"},
+	})
+	if err != nil {
+		t.Fatalf("CreateOAuthClient: %v", err)
+	}
+
+	return &oauthProvider{
+		server: server,
+		// redirect ต้องกลับมาที่ test ไม่ใช่ตามไปยังหน้าเข้าสู่ระบบหรือ redirect_uri
+		http: &http.Client{
+			Timeout:       10 * time.Second,
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		},
+		clientID:     created.GetClient().GetClientId(),
+		clientSecret: created.GetClientSecret(),
+		email:        "alice@example.com",
+		federation:   federationService,
+		auth:         authService,
+		adminCtx:     adminCtx,
+	}
+}
+
+func openTestStores(t *testing.T) *store.Stores {
+	t.Helper()
+	ctx := context.Background()
+	sqlStore, err := sqlstore.Open(ctx, sqlstore.SQLite, sqliteDSN(filepath.Join(t.TempDir(), "auth.db")))
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if _, err := sqlStore.Migrator().Up(ctx); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	stores := sqlStore.Stores()
+	t.Cleanup(func() { stores.Close(context.Background()) })
+	if _, err := service.EnsureSigningKeys(ctx, stores.Tenants); err != nil {
+		t.Fatalf("EnsureSigningKeys: %v", err)
+	}
+	return stores
+}
+
+func pkcePair() (verifier string, challenge string) {
+	verifier = strings.Repeat("v", 50)
+	sum := sha256.Sum256([]byte(verifier))
+	return verifier, base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// ส่ง request แล้วถอด JSON ของ response ตรวจ status ตามที่คาดไว้
+func (p *oauthProvider) do(t *testing.T, req *http.Request, wantStatus int) map[string]interface{} {
+	t.Helper()
+	resp, err := p.http.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", req.Method, req.URL.Path, err)
+	}
+	defer resp.Body.Close()
+	body := map[string]interface{}{}
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") {
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			t.Fatalf("%s %s: decode: %v", req.Method, req.URL.Path, err)
+		}
+	}
+	if resp.StatusCode != wantStatus {
+		t.Fatalf("%s %s: status %d, want %d (%v)", req.Method, req.URL.Path, resp.StatusCode, wantStatus, body)
+	}
+	if loc := resp.Header.Get("Location"); loc != "" {
+		body["Location"] = loc
+	}
+	return body
+}
+
+func (p *oauthProvider) get(t *testing.T, path string, wantStatus int) map[string]interface{} {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodGet, p.server.URL+path, nil)
+	return p.do(t, req, wantStatus)
+}
+
+// POST form ไปยัง endpoint ของ client (token, revoke, introspect) ด้วย client_secret_basic
+func (p *oauthProvider) postForm(t *testing.T, path string, form url.Values, wantStatus int) map[string]interface{} {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodPost, p.server.URL+path, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(url.QueryEscape(p.clientID), url.QueryEscape(p.clientSecret))
+	return p.do(t, req, wantStatus)
+}
+
+// เริ่ม authorization code flow จนได้ code ผ่านหน้าเข้าสู่ระบบและขอความยินยอม
+func (p *oauthProvider) authorize(t *testing.T, challenge string, scope string) string {
+	t.Helper()
+	q := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.clientID},
+		"redirect_uri":          {testRedirectURI},
+		"scope":                 {scope},
+		"state":                 {"state-123"},
+		"nonce":                 {"nonce-456"},
+		"code_challenge":        {challenge},
+		"code_challenge_method": {"S256"},
+	}
+	body := p.get(t, "/oauth/authorize?"+q.Encode(), http.StatusFound)
+	login, err := url.Parse(body["Location"].(string))
+	if err != nil || !strings.HasPrefix(login.String(), testLoginURL) {
+		t.Fatalf("authorize redirected to %v, want the login page", body["Location"])
+	}
+	requestID := login.Query().Get("request_id")
+
+	info := p.get(t, "/oauth/consent?request_id="+url.QueryEscape(requestID), http.StatusOK)
+	if info["client_name"] != "Example App" || info["redirect_uri"] != testRedirectURI {
+		t.Fatalf("consent info = %v", info)
+	}
+
+	decision, _ := json.Marshal(map[string]interface{}{"request_id": requestID, "email": p.email, "password": testPassword, "approve": true})
+	req, _ := http.NewRequest(http.MethodPost, p.server.URL+"/oauth/consent", strings.NewReader(string(decision)))
+	req.Header.Set("Content-Type", "application/json")
+	reply := p.do(t, req, http.StatusOK)
+	redirect, err := url.Parse(reply["redirect_to"].(string))
+	if err != nil || !strings.HasPrefix(redirect.String(), testRedirectURI) {
+		t.Fatalf("consent redirect_to = %v", reply["redirect_to"])
+	}
+	if redirect.Query().Get("state") != "state-123" || redirect.Query().Get("iss") != p.server.URL {
+		t.Fatalf("redirect_to %s must carry state and iss", redirect)
+	}
+	code := redirect.Query().Get("code")
+	if code == "" {
+		t.Fatalf("redirect_to %s has no code", redirect)
+	}
+	return code
+}
+
+func (p *oauthProvider) exchange(t *testing.T, code string, verifier string, wantStatus int) map[string]interface{} {
+	t.Helper()
+	return p.postForm(t, "/oauth/token", url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {testRedirectURI},
+		"code_verifier": {verifier},
+	}, wantStatus)
+}
+
+// ตรวจลายเซ็นของ ID token ด้วย JWKS ที่ provider เผยแพร่
+func (p *oauthProvider) verifyIDToken(t *testing.T, idToken string) jwt.MapClaims {
+	t.Helper()
+	discovery := p.get(t, "/.well-known/openid-configuration", http.StatusOK)
+	jwksURL, _ := url.Parse(discovery["jwks_uri"].(string))
+	jwks := p.get(t, jwksURL.Path, http.StatusOK)
+
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(idToken, claims, func(token *jwt.Token) (interface{}, error) {
+		for _, k := range jwks["keys"].([]interface{}) {
+			jwk := k.(map[string]interface{})
+			if jwk["kid"] == token.Header["kid"] {
+				return auth.ParsePublicJWK(jwk)
+			}
+		}
+		return nil, jwt.ErrTokenUnverifiable
+	}, jwt.WithValidMethods([]string{"RS256"}), jwt.WithIssuer(p.server.URL), jwt.WithAudience(p.clientID))
+	if err != nil {
+		t.Fatalf("ID token does not verify against the JWKS: %v", err)
+	}
+	return claims
+}
+
+func TestOAuthAuthorizationCodeFlow(t *testing.T) {
+	p := newOAuthProvider(t)
+
+	discovery := p.get(t, "/.well-known/openid-configuration", http.StatusOK)
+	if discovery["issuer"] != p.server.URL || discovery["token_endpoint"] != p.server.URL+"/oauth/token" {
+		t.Fatalf("discovery = %v", discovery)
+	}
+
+	verifier, challenge := pkcePair()
+	code := p.authorize(t, challenge, "openid profile email offline_access")
+	tokens := p.exchange(t, code, verifier, http.StatusOK)
+	accessToken, _ := tokens["access_token"].(string)
+	refreshToken, _ := tokens["refresh_token"].(string)
+	if accessToken == "" || refreshToken == "" || tokens["token_type"] != "Bearer" {
+		t.Fatalf("token response = %v", tokens)
+	}
+
+	claims := p.verifyIDToken(t, tokens["id_token"].(string))
+	if claims["nonce"] != "nonce-456" || claims["email"] != p.email {
+		t.Fatalf("ID token claims = %v", claims)
+	}
+
+	// code ใช้ได้ครั้งเดียว
+	if body := p.exchange(t, code, verifier, http.StatusBadRequest); body["error"] != "invalid_grant" {
+		t.Fatalf("second exchange = %v, want invalid_grant", body)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, p.server.URL+"/oauth/userinfo", nil)
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	userinfo := p.do(t, req, http.StatusOK)
+	if userinfo["sub"] != claims["sub"] || userinfo["email"] != p.email || userinfo["preferred_username"] != "alice" {
+		t.Fatalf("userinfo = %v", userinfo)
+	}
+
+	introspection := p.postForm(t, "/oauth/introspect", url.Values{"token": {accessToken}}, http.StatusOK)
+	if introspection["active"] != true || introspection["client_id"] != p.clientID || introspection["sub"] != claims["sub"] {
+		t.Fatalf("introspection = %v", introspection)
+	}
+
+	// refresh token หมุนทุกครั้ง ตัวเดิมใช้ซ้ำไม่ได้
+	refreshed := p.postForm(t, "/oauth/token", url.Values{"grant_type": {"refresh_token"}, "refresh_token": {refreshToken}}, http.StatusOK)
+	if refreshed["refresh_token"] == refreshToken || refreshed["access_token"] == "" {
+		t.Fatalf("refresh response = %v", refreshed)
+	}
+	reuse := p.postForm(t, "/oauth/token", url.Values{"grant_type": {"refresh_token"}, "refresh_token": {refreshToken}}, http.StatusBadRequest)
+	if reuse["error"] != "invalid_grant" {
+		t.Fatalf("refresh token reuse = %v, want invalid_grant", reuse)
+	}
+
+	newAccess := refreshed["access_token"].(string)
+	p.postForm(t, "/oauth/revoke", url.Values{"token": {newAccess}, "token_type_hint": {"access_token"}}, http.StatusOK)
+	if body := p.postForm(t, "/oauth/introspect", url.Values{"token": {newAccess}}, http.StatusOK); body["active"] != false {
+		t.Fatalf("introspection after revoke = %v, want inactive", body)
+	}
+}
+
+func TestOAuthRefreshTokenRevokedByPasswordChange(t *testing.T) {
+	p := newOAuthProvider(t)
+	verifier, challenge := pkcePair()
+	tokens := p.exchange(t, p.authorize(t, challenge, "openid offline_access"), verifier, http.StatusOK)
+	refreshToken, _ := tokens["refresh_token"].(string)
+	if refreshToken == "" {
+		t.Fatalf("token response = %v", tokens)
+	}
+
+	ctx := context.Background()
+	login, err := p.auth.Login(ctx, &pb.LoginRequest{Email: p.email, Password: testPassword})
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	userCtx := metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer "+login.GetToken()))
+	if _, err := p.auth.ChangePassword(userCtx, &pb.ChangePasswordRequest{CurrentPassword: testPassword, NewPassword: "Changed456!"}); err != nil {
+		t.Fatalf("ChangePassword: %v", err)
+	}
+
+	body := p.postForm(t, "/oauth/token", url.Values{"grant_type": {"refresh_token"}, "refresh_token": {refreshToken}}, http.StatusBadRequest)
+	if body["error"] != "invalid_grant" {
+		t.Fatalf("refresh after a password change = %v, want invalid_grant", body)
+	}
+}
+
+func TestOAuthRejectsWrongPKCEVerifier(t *testing.T) {
+	p := newOAuthProvider(t)
+	_, challenge := pkcePair()
+	code := p.authorize(t, challenge, "openid")
+	body := p.exchange(t, code, strings.Repeat("x", 50), http.StatusBadRequest)
+	if body["error"] != "invalid_grant" {
+		t.Fatalf("exchange with a wrong verifier = %v, want invalid_grant", body)
+	}
+}
+
+func TestOAuthAuthorizeErrors(t *testing.T) {
+	p := newOAuthProvider(t)
+	_, challenge := pkcePair()
+
+	// redirect_uri ที่ไม่ได้ลงทะเบียนต้องไม่ถูก redirect ไป (กัน open redirect)
+	q := url.Values{
+		"response_type": {"code"}, "client_id": {p.clientID}, "redirect_uri": {"https://evil.example.com/cb"},
+		"scope": {"openid"}, "code_challenge": {challenge}, "code_challenge_method": {"S256"},
+	}
+	if body := p.get(t, "/oauth/authorize?"+q.Encode(), http.StatusBadRequest); body["error"] != "invalid_request" {
+		t.Fatalf("authorize with unknown redirect_uri = %v", body)
+	}
+
+	// error อื่นกลับไปยัง client ผ่าน redirect_uri
+	q.Set("redirect_uri", testRedirectURI)
+	q.Del("code_challenge")
+	q.Set("state", "s1")
+	body := p.get(t, "/oauth/authorize?"+q.Encode(), http.StatusFound)
+	redirect, _ := url.Parse(body["Location"].(string))
+	if redirect.Query().Get("error") != "invalid_request" || redirect.Query().Get("state") != "s1" {
+		t.Fatalf("authorize without PKCE redirected to %s", redirect)
+	}
+
+	// scope ที่ไม่ได้ลงทะเบียนให้ client
+	q.Set("code_challenge", challenge)
+	q.Set("scope", "openid admin")
+	body = p.get(t, "/oauth/authorize?"+q.Encode(), http.StatusFound)
+	redirect, _ = url.Parse(body["Location"].(string))
+	if redirect.Query().Get("error") != "invalid_scope" {
+		t.Fatalf("authorize with a scope the client may not request redirected to %s", redirect)
+	}
+}
+
+func TestSAMLEndpoints(t *testing.T) {
+	p := newOAuthProvider(t)
+	idp := federationtest.NewSAMLIdP(t, "https://idp.corp.example.com", "http://127.0.0.1:9999/sso")
+	_, err := p.federation.CreateIdentityProvider(p.adminCtx, &pb.CreateIdentityProviderRequest{
+		Name:         "corp",
+		Protocol:     "saml",
+		RedirectUri:  testRedirectURI,
+		AllowSignup:  true,
+		SamlMetadata: idp.Metadata(),
+		Saml:         &pb.SAMLSettings{AllowIdpInitiated: true},
+	})
+	if err != nil {
+		t.Fatalf("CreateIdentityProvider: %v", err)
+	}
+	acsURL := p.server.URL + "/saml/default/corp/acs"
+
+	resp, err := p.http.Get(p.server.URL + "/saml/default/corp/metadata")
+	if err != nil {
+		t.Fatalf("GET metadata: %v", err)
+	}
+	metadata, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "application/samlmetadata+xml" ||
+		!strings.Contains(string(metadata), `Location="`+acsURL+`"`) {
+		t.Fatalf("GET metadata = %d %s\n%s", resp.StatusCode, resp.Header.Get("Content-Type"), metadata)
+	}
+
+	// IdP POST Response (IdP-initiated) มาที่ ACS แล้วผู้ใช้ถูกส่งกลับไปยังแอปพร้อม state และ code
+	response := idp.Response(t, federationtest.SAMLAssertion{
+		NameID:      "idp-1",
+		Audience:    p.server.URL + "/saml/default/corp/metadata",
+		Recipient:   acsURL,
+		Destination: acsURL,
+		Attributes:  map[string][]string{"email": {"olivia@corp.example.com"}},
+	}, federationtest.SignAssertion, nil)
+	form := url.Values{"SAMLResponse": {response}}
+	body := p.postForm(t, "/saml/default/corp/acs", form, http.StatusSeeOther)
+	redirect, _ := url.Parse(body["Location"].(string))
+	if !strings.HasPrefix(redirect.String(), testRedirectURI+"?") || redirect.Query().Get("state") == "" || redirect.Query().Get("code") == "" {
+		t.Fatalf("ACS redirected to %s", redirect)
+	}
+	reply, err := p.federation.CompleteFederatedLogin(context.Background(), &pb.CompleteFederatedLoginRequest{
+		State: redirect.Query().Get("state"),
+		Code:  redirect.Query().Get("code"),
+	})
+	if err != nil || reply.GetEmail() != "olivia@corp.example.com" || reply.GetToken() == "" {
+		t.Fatalf("CompleteFederatedLogin = %+v, %v", reply, err)
+	}
+
+	// assertion เดิมใช้ซ้ำไม่ได้
+	if body := p.postForm(t, "/saml/default/corp/acs", form, http.StatusUnauthorized); body["error"] != "login_required" {
+		t.Fatalf("ACS with a replayed assertion = %v", body)
+	}
+	p.get(t, "/saml/default/other/metadata", http.StatusNotFound)
+}
@@ -0,0 +1,68 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"auth-microservice/internal/config"
+)
+
+// รันคำสั่ง migration: up, down [steps] หรือ status
+func RunMigrations(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: migrate <up|down [steps]|status>")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	stores, migrator, err := openStorage(cfg)
+	if err != nil {
+		return err
+	}
+	defer stores.Close(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	switch args[0] {
+	case "up":
+		ran, err := migrator.Up(ctx)
+		for _, m := range ran {
+			fmt.Printf("applied  %3d  %s\n", m.Version, m.Name)
+		}
+		if err == nil && len(ran) == 0 {
+			fmt.Println("no pending migrations")
+		}
+		return err
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				return fmt.Errorf("invalid steps %q", args[1])
+			}
+		}
+		reverted, err := migrator.Down(ctx, steps)
+		for _, m := range reverted {
+			fmt.Printf("reverted %3d  %s\n", m.Version, m.Name)
+		}
+		return err
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, st := range statuses {
+			applied := "pending"
+			if st.Applied {
+				applied = st.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%3d  %-32s  %s\n", st.Version, st.Name, applied)
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown migrate command %q", args[0])
+}
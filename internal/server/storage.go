@@ -0,0 +1,105 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+
+	"auth-microservice/internal/config"
+	"auth-microservice/internal/db"
+	"auth-microservice/internal/events"
+	"auth-microservice/internal/migrate"
+	"auth-microservice/internal/store"
+	"auth-microservice/internal/store/mongostore"
+	"auth-microservice/internal/store/redisstore"
+	"auth-microservice/internal/store/sqlstore"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// เชื่อมต่อ backend ตามการตั้งค่า แล้วคืน store ทั้งหมดพร้อม Migrator ของ backend นั้น
+// backend แบบ SQL เก็บ session และข้อมูลชั่วคราวไว้ในฐานข้อมูลเดียวกัน จึงไม่ต้องใช้ Redis
+func openStorage(cfg config.Config) (*store.Stores, *migrate.Migrator, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	switch cfg.StorageBackend {
+	case config.BackendPostgres:
+		sqlStore, err := sqlstore.Open(ctx, sqlstore.Postgres, cfg.PostgresDSN)
+		if err != nil {
+			return nil, nil, err
+		}
+		log.Println("Connected to PostgreSQL")
+		return sqlStore.Stores(), sqlStore.Migrator(), nil
+	case config.BackendSQLite:
+		sqlStore, err := sqlstore.Open(ctx, sqlstore.SQLite, sqliteDSN(cfg.SQLitePath))
+		if err != nil {
+			return nil, nil, err
+		}
+		log.Printf("Opened SQLite database %s", cfg.SQLitePath)
+		return sqlStore.Stores(), sqlStore.Migrator(), nil
+	case config.BackendMongo:
+		client, collections, err := db.InitMongo(cfg.MongoURI)
+		if err != nil {
+			return nil, nil, err
+		}
+		// outbox ต้องเขียนพร้อมข้อมูลใน transaction เดียวกัน จึงต้องใช้ replica set (ยกเว้นเปิด MONGO_ALLOW_STANDALONE สำหรับ dev)
+		ok, err := db.SupportsTransactions(ctx, client)
+		if err != nil {
+			client.Disconnect(context.Background())
+			return nil, nil, err
+		}
+		if !ok && !cfg.MongoAllowStandalone {
+			client.Disconnect(context.Background())
+			return nil, nil, fmt.Errorf("MongoDB at %s is a standalone server without transaction support: "+
+				"start it as a replica set (mongod --replSet rs0, then rs.initiate()) "+
+				"or set MONGO_ALLOW_STANDALONE=true to accept non-atomic outbox writes in development", cfg.MongoURI)
+		}
+		if !ok {
+			log.Println("MONGO_ALLOW_STANDALONE is set: outbox events are written without a transaction")
+		}
+
+		// MongoDB ยังเก็บ session และข้อมูลชั่วคราวไว้ใน Redis เหมือนเดิม
+		rdb := newRedisClient(cfg)
+		stores := mongostore.NewStores(client, collections, redisstore.NewSessionStore(rdb), redisstore.NewKeyValueStore(rdb), cfg.MongoAllowStandalone)
+		closeMongo := stores.Close
+		stores.Close = func(ctx context.Context) error {
+			rdb.Close()
+			return closeMongo(ctx)
+		}
+		return stores, db.NewMigrator(client), nil
+	}
+	return nil, nil, fmt.Errorf("unknown storage backend %q", cfg.StorageBackend)
+}
+
+// DSN ของไฟล์ SQLite: รอ lock แทนการ error ทันที และใช้ WAL เพื่อให้อ่านระหว่างเขียนได้
+func sqliteDSN(path string) string {
+	params := url.Values{}
+	params.Add("_pragma", "busy_timeout(5000)")
+	params.Add("_pragma", "journal_mode(WAL)")
+	params.Add("_pragma", "foreign_keys(1)")
+	return "file:" + path + "?" + params.Encode()
+}
+
+// สร้าง Redis client ตามการตั้งค่า
+func newRedisClient(cfg config.Config) *redis.Client {
+	return redis.NewClient(&redis.Options{
+		Addr:     cfg.RedisAddr,
+		Password: "",
+		DB:       0,
+	})
+}
+
+// สร้างช่องทางเผยแพร่ domain event ตาม EVENT_PUBLISHER
+// event ส่งให้ bus ภายใน process ด้วยเสมอ (WatchUsers ใช้เมื่อไม่มี change stream)
+func newPublisher(cfg config.Config, bus *events.MemoryBus) events.Publisher {
+	switch cfg.EventPublisher {
+	case config.PublisherRedis:
+		return events.Fanout{events.NewRedisStreamPublisher(newRedisClient(cfg), cfg.EventStream), bus}
+	case config.PublisherNATS:
+		return events.Fanout{events.NewNATSPublisher(cfg.NATSURL, cfg.EventStream), bus}
+	}
+	return bus
+}
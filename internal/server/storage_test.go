@@ -0,0 +1,38 @@
+package server
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"auth-microservice/internal/config"
+	"auth-microservice/internal/store"
+	"auth-microservice/internal/store/storetest"
+)
+
+// โหมด STORAGE_BACKEND=sqlite ต้องผ่านชุดทดสอบเดียวกับ backend อื่นโดยไม่ต้องมี MongoDB หรือ Redis
+func TestSQLiteStorageConformance(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) *store.Stores {
+		cfg := config.Config{StorageBackend: config.BackendSQLite, SQLitePath: filepath.Join(t.TempDir(), "auth.db")}
+		stores, migrator, err := openStorage(cfg)
+		if err != nil {
+			t.Fatalf("openStorage: %v", err)
+		}
+		t.Cleanup(func() { stores.Close(context.Background()) })
+		if _, err := migrator.Up(context.Background()); err != nil {
+			t.Fatalf("migrate: %v", err)
+		}
+		// ทุก store ต้องมาจากไฟล์เดียว ไม่มีส่วนไหนที่ต้องพึ่ง Redis
+		for name, missing := range map[string]bool{
+			"Sessions":  stores.Sessions == nil,
+			"Cache":     stores.Cache == nil,
+			"Blacklist": stores.Blacklist == nil,
+			"Settings":  stores.Settings == nil,
+		} {
+			if missing {
+				t.Fatalf("sqlite storage has no %s store", name)
+			}
+		}
+		return stores
+	})
+}
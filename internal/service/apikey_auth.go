@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+
+	pb "auth-microservice/auth-microservice/proto"
+	"auth-microservice/internal/auth"
+	models "auth-microservice/internal/model"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	apiKeyPrefix        = "ak_"                 // ส่วนนำหน้าของ API key ทุกตัว ช่วยให้ secret scanner จำแนกได้
+	apiKeyPrefixLen     = len(apiKeyPrefix) + 8 // ความยาวของส่วนต้นที่เก็บไว้แสดงผล
+	apiKeyTouchInterval = time.Minute           // บันทึกเวลาใช้งานล่าสุด (และ audit) ไม่ถี่กว่านี้
+)
+
+// scope ที่ต้องมีเพื่อเรียกแต่ละ RPC ด้วย API key หรือ token ที่ออกให้ client ของ OAuth
+// RPC ที่ไม่อยู่ในรายการ (เช่น Login, ChangePassword และการจัดการ API key) ต้องใช้ JWT ของผู้ใช้เท่านั้น
+var methodScopes = map[string]string{
+	pb.UserService_GetUserById_FullMethodName:         models.ScopeUsersRead,
+	pb.UserService_ListUsers_FullMethodName:           models.ScopeUsersRead,
+	pb.UserService_GetProfileSchema_FullMethodName:    models.ScopeUsersRead,
+	pb.UserService_ExportMyData_FullMethodName:        models.ScopeUsersRead,
+	pb.UserService_ExportUserData_FullMethodName:      models.ScopeUsersRead,
+	pb.UserService_UpdateUser_FullMethodName:          models.ScopeUsersWrite,
+	pb.UserService_DeleteUser_FullMethodName:          models.ScopeUsersWrite,
+	pb.UserService_RestoreUser_FullMethodName:         models.ScopeUsersWrite,
+	pb.UserService_PurgeUser_FullMethodName:           models.ScopeUsersWrite,
+	pb.UserService_UpdateProfileSchema_FullMethodName: models.ScopeUsersWrite,
+
+	pb.GroupService_ListGroups_FullMethodName:       models.ScopeGroupsRead,
+	pb.GroupService_ListGroupMembers_FullMethodName: models.ScopeGroupsRead,
+	pb.GroupService_ListUserGroups_FullMethodName:   models.ScopeGroupsRead,
+	pb.GroupService_CreateGroup_FullMethodName:      models.ScopeGroupsWrite,
+	pb.GroupService_DeleteGroup_FullMethodName:      models.ScopeGroupsWrite,
+	pb.GroupService_AddMember_FullMethodName:        models.ScopeGroupsWrite,
+	pb.GroupService_RemoveMember_FullMethodName:     models.ScopeGroupsWrite,
+	pb.GroupService_InviteMember_FullMethodName:     models.ScopeGroupsWrite,
+
+	pb.TenantService_GetTenant_FullMethodName:        models.ScopeTenantsRead,
+	pb.TenantService_ListTenants_FullMethodName:      models.ScopeTenantsRead,
+	pb.TenantService_CreateTenant_FullMethodName:     models.ScopeTenantsWrite,
+	pb.TenantService_UpdateTenant_FullMethodName:     models.ScopeTenantsWrite,
+	pb.TenantService_SuspendTenant_FullMethodName:    models.ScopeTenantsWrite,
+	pb.TenantService_ActivateTenant_FullMethodName:   models.ScopeTenantsWrite,
+	pb.TenantService_RotateSigningKey_FullMethodName: models.ScopeTenantsWrite,
+}
+
+// key ของ context ที่เก็บ claims ของ API key ที่ผ่านการตรวจสอบแล้ว
+type apiKeyClaimsKey struct{}
+
+// claims ของ request ที่ใช้ API key (ใส่ไว้ใน context โดย interceptor)
+func apiKeyClaims(ctx context.Context) (map[string]interface{}, bool) {
+	claims, ok := ctx.Value(apiKeyClaimsKey{}).(map[string]interface{})
+	return claims, ok
+}
+
+// สร้าง API key แบบสุ่ม (256 bit) คืน key จริงกับส่วนต้นสำหรับแสดงผล
+func newAPIKey() (string, string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	key := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(b)
+	return key, key[:apiKeyPrefixLen], nil
+}
+
+// hash ของ API key สำหรับเก็บและค้นหา (key มี entropy สูงจึงใช้ SHA-256 ได้โดยไม่ต้องใช้ bcrypt)
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// interceptor สำหรับ unary RPC: ตรวจสอบ API key (ถ้ามี) ก่อนเรียก handler
+func (s *APIKeyService) UnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := s.authenticateAPIKey(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// interceptor สำหรับ streaming RPC (เช่น ExportUserData)
+func (s *APIKeyService) StreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := s.authenticateAPIKey(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &apiKeyStream{ServerStream: ss, ctx: ctx})
+}
+
+// ServerStream ที่แทน context ด้วย context ที่มี claims ของ API key
+type apiKeyStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *apiKeyStream) Context() context.Context {
+	return s.ctx
+}
+
+// ตรวจสอบ API key ของ request (key ถูกต้อง, ไม่หมดอายุ, มี scope ของ RPC และไม่เกิน rate limit)
+// แล้วใส่ claims ของเจ้าของ key ลงใน context ให้ authenticate ใช้แทน JWT
+// request ที่ไม่ได้ใช้ API key คืน context เดิม
+func (s *APIKeyService) authenticateAPIKey(ctx context.Context, method string) (context.Context, error) {
+	key, ok := auth.APIKeyFromContext(ctx)
+	if !ok {
+		return ctx, nil
+	}
+	scope, ok := methodScopes[method]
+	if !ok {
+		return nil, status.Error(codes.PermissionDenied, "RPC นี้ใช้ API key ไม่ได้ กรุณาใช้ JWT")
+	}
+
+	apiKey, err := s.APIKeys.GetAPIKeyByHash(ctx, hashAPIKey(key))
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "API key ไม่ถูกต้อง")
+	}
+	now := time.Now()
+	if apiKey.RevokedAt != nil {
+		return nil, status.Error(codes.Unauthenticated, "API key นี้ถูกยกเลิกแล้ว")
+	}
+	if now.After(apiKey.ExpiresAt) {
+		return nil, status.Error(codes.Unauthenticated, "API key นี้หมดอายุแล้ว")
+	}
+	if !hasScope(apiKey.Scopes, scope) {
+		return nil, status.Errorf(codes.PermissionDenied, "API key นี้ไม่มี scope %q", scope)
+	}
+
+	// key ใช้ได้เฉพาะเมื่อ tenant ยังเปิดใช้งานและเจ้าของ key ยังไม่ถูกลบ (role ตามข้อมูลล่าสุดของเจ้าของ)
+	if _, err := activeTenant(ctx, s.Tenants, apiKey.TenantID); err != nil {
+		return nil, err
+	}
+	user, err := s.Users.GetUserByID(ctx, apiKey.UserID.Hex(), false)
+	if err != nil || user.TenantID != apiKey.TenantID {
+		return nil, status.Error(codes.Unauthenticated, "ไม่พบเจ้าของ API key")
+	}
+
+	if err := s.checkAPIKeyRateLimit(ctx, apiKey, user.Email); err != nil {
+		return nil, err
+	}
+
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) >= apiKeyTouchInterval {
+		if err := s.APIKeys.TouchAPIKey(ctx, apiKey.ID.Hex(), now); err == nil {
+			s.Audit.Record(ctx, models.AuditEvent{
+				TenantID:     apiKey.TenantID,
+				Action:       "api_key.used",
+				ActorEmail:   user.Email,
+				SubjectID:    user.ID.Hex(),
+				SubjectEmail: user.Email,
+				Details:      map[string]interface{}{"apiKeyId": apiKey.ID.Hex(), "method": method},
+			})
+		}
+	}
+
+	claims := map[string]interface{}{
+		"email":          user.Email,
+		"role":           user.Role,
+		auth.TenantClaim: apiKey.TenantID,
+		"apiKeyId":       apiKey.ID.Hex(),
+		"scopes":         apiKey.Scopes,
+	}
+	return context.WithValue(ctx, apiKeyClaimsKey{}, claims), nil
+}
+
+// จำกัดจำนวน request ต่อนาทีของแต่ละ key (บันทึก audit ครั้งแรกที่เกินในแต่ละนาที)
+func (s *APIKeyService) checkAPIKeyRateLimit(ctx context.Context, apiKey *models.APIKey, ownerEmail string) error {
+	attempts, err := s.Cache.Incr(ctx, "apikey_rate:"+apiKey.ID.Hex(), time.Minute)
+	if err != nil {
+		return status.Error(codes.Internal, "ไม่สามารถตรวจสอบ Rate Limit ได้")
+	}
+	if attempts <= int64(apiKey.RateLimit) {
+		return nil
+	}
+	if attempts == int64(apiKey.RateLimit)+1 {
+		s.Audit.Record(ctx, models.AuditEvent{
+			TenantID:     apiKey.TenantID,
+			Action:       "api_key.rate_limited",
+			ActorEmail:   ownerEmail,
+			SubjectID:    apiKey.UserID.Hex(),
+			SubjectEmail: ownerEmail,
+			Details:      map[string]interface{}{"apiKeyId": apiKey.ID.Hex(), "rateLimit": apiKey.RateLimit},
+		})
+	}
+	return status.Errorf(codes.ResourceExhausted, "API key นี้เรียกได้ไม่เกิน %d ครั้งต่อนาที", apiKey.RateLimit)
+}
+
+func hasScope(scopes []string, scope string) bool {
+	for _, s := range scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
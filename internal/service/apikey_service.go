@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	pb "auth-microservice/auth-microservice/proto"
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/store"
+	"auth-microservice/internal/validation"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultAPIKeyTTL       = 90 * 24 * time.Hour  // อายุของ key เมื่อไม่ระบุ expiresAt
+	maxAPIKeyTTL           = 365 * 24 * time.Hour // อายุสูงสุดของ key
+	defaultAPIKeyRateLimit = 60                   // request ต่อนาทีเมื่อไม่ระบุ rateLimit
+)
+
+func (s *APIKeyService) CreateAPIKey(ctx context.Context, in *pb.CreateAPIKeyRequest) (*pb.CreateAPIKeyReply, error) {
+	_, claims, err := authenticate(ctx, s.Blacklist, s.Tenants)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.callerUser(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validation.ValidateAPIKeyName(in.GetName()); err != nil {
+		return nil, err
+	}
+	scopes := uniqueStrings(in.GetScopes())
+	if err := validation.ValidateScopes(scopes); err != nil {
+		return nil, err
+	}
+	rateLimit := int(in.GetRateLimit())
+	if rateLimit == 0 {
+		rateLimit = defaultAPIKeyRateLimit
+	}
+	if err := validation.ValidateAPIKeyRateLimit(rateLimit); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(defaultAPIKeyTTL)
+	if in.GetExpiresAt() != "" {
+		if expiresAt, err = time.Parse(time.RFC3339, in.GetExpiresAt()); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "expiresAt ต้องอยู่ในรูปแบบ RFC3339")
+		}
+		if !expiresAt.After(now) || expiresAt.Sub(now) > maxAPIKeyTTL {
+			return nil, status.Error(codes.InvalidArgument, "expiresAt ต้องอยู่ในอนาคตและไม่เกิน 1 ปี")
+		}
+	}
+
+	key, prefix, err := newAPIKey()
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถสร้าง API key ได้")
+	}
+	apiKey := &models.APIKey{
+		TenantID:  user.TenantID,
+		UserID:    user.ID,
+		Name:      in.GetName(),
+		Prefix:    prefix,
+		Hash:      hashAPIKey(key),
+		Scopes:    scopes,
+		RateLimit: rateLimit,
+		ExpiresAt: expiresAt,
+		CreatedAt: now,
+	}
+	if err := s.APIKeys.CreateAPIKey(ctx, apiKey); err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถบันทึก API key ได้")
+	}
+
+	s.Audit.Record(ctx, models.AuditEvent{
+		TenantID:     user.TenantID,
+		Action:       "api_key.created",
+		ActorEmail:   user.Email,
+		SubjectID:    user.ID.Hex(),
+		SubjectEmail: user.Email,
+		Details:      map[string]interface{}{"apiKeyId": apiKey.ID.Hex(), "name": apiKey.Name, "scopes": scopes},
+	})
+	return &pb.CreateAPIKeyReply{
+		ApiKey: toAPIKeyReply(apiKey),
+		Key:    key,
+	}, nil
+}
+
+func (s *APIKeyService) ListAPIKeys(ctx context.Context, in *pb.ListAPIKeysRequest) (*pb.ListAPIKeysReply, error) {
+	_, claims, err := authenticate(ctx, s.Blacklist, s.Tenants)
+	if err != nil {
+		return nil, err
+	}
+
+	tenantID, userID := claimsTenant(claims), in.GetUserId()
+	if userID == "" {
+		user, err := s.callerUser(ctx, claims)
+		if err != nil {
+			return nil, err
+		}
+		userID = user.ID.Hex()
+	} else if !isAdmin(claims) {
+		// ผู้ใช้ทั่วไปดูได้เฉพาะ key ของตัวเอง
+		user, err := s.callerUser(ctx, claims)
+		if err != nil {
+			return nil, err
+		}
+		if user.ID.Hex() != userID {
+			return nil, status.Error(codes.PermissionDenied, "ดู API key ของผู้ใช้อื่นได้เฉพาะ Admin เท่านั้น")
+		}
+	} else {
+		tenantID = scopeTenant(ctx, claims)
+	}
+
+	keys, err := s.APIKeys.ListAPIKeys(ctx, tenantID, userID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถดึงรายการ API key ได้")
+	}
+	reply := &pb.ListAPIKeysReply{}
+	for i := range keys {
+		reply.ApiKeys = append(reply.ApiKeys, toAPIKeyReply(&keys[i]))
+	}
+	return reply, nil
+}
+
+func (s *APIKeyService) RevokeAPIKey(ctx context.Context, in *pb.RevokeAPIKeyRequest) (*pb.RevokeAPIKeyReply, error) {
+	_, claims, err := authenticate(ctx, s.Blacklist, s.Tenants)
+	if err != nil {
+		return nil, err
+	}
+	caller, err := s.callerUser(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	// admin ยกเลิก key ของผู้ใช้ใดก็ได้ใน tenant ส่วนผู้ใช้ทั่วไปยกเลิกได้เฉพาะ key ของตัวเอง
+	tenantID := scopeTenant(ctx, claims)
+	apiKey, err := s.APIKeys.GetAPIKey(ctx, tenantID, in.GetId())
+	if err != nil || (!isAdmin(claims) && apiKey.UserID != caller.ID) {
+		return nil, status.Error(codes.NotFound, "ไม่พบ API key")
+	}
+	err = s.APIKeys.RevokeAPIKey(ctx, tenantID, in.GetId(), time.Now())
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, status.Error(codes.FailedPrecondition, "API key นี้ถูกยกเลิกไปแล้ว")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถยกเลิก API key ได้")
+	}
+
+	s.Audit.Record(ctx, models.AuditEvent{
+		TenantID:   tenantID,
+		Action:     "api_key.revoked",
+		ActorEmail: caller.Email,
+		SubjectID:  apiKey.UserID.Hex(),
+		Details:    map[string]interface{}{"apiKeyId": apiKey.ID.Hex(), "name": apiKey.Name},
+	})
+	return &pb.RevokeAPIKeyReply{
+		Message: "ยกเลิก API key สำเร็จ",
+	}, nil
+}
+
+// ผู้ใช้เจ้าของ token
+func (s *APIKeyService) callerUser(ctx context.Context, claims map[string]interface{}) (*models.User, error) {
+	email, _ := claims["email"].(string)
+	user, err := s.Users.GetUserByEmail(ctx, claimsTenant(claims), email)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, "ไม่พบผู้ใช้")
+	}
+	return user, nil
+}
+
+// ตัดค่าที่ซ้ำออกโดยคงลำดับเดิม
+func uniqueStrings(values []string) []string {
+	seen := map[string]bool{}
+	unique := []string{}
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			unique = append(unique, v)
+		}
+	}
+	return unique
+}
+
+func toAPIKeyReply(k *models.APIKey) *pb.APIKey {
+	reply := &pb.APIKey{
+		Id:        k.ID.Hex(),
+		UserId:    k.UserID.Hex(),
+		Name:      k.Name,
+		Prefix:    k.Prefix,
+		Scopes:    k.Scopes,
+		RateLimit: int32(k.RateLimit),
+		ExpiresAt: k.ExpiresAt.Format(time.RFC3339),
+		CreatedAt: k.CreatedAt.Format(time.RFC3339),
+	}
+	if k.LastUsedAt != nil {
+		reply.LastUsedAt = k.LastUsedAt.Format(time.RFC3339)
+	}
+	if k.RevokedAt != nil {
+		reply.RevokedAt = k.RevokedAt.Format(time.RFC3339)
+	}
+	return reply
+}
@@ -0,0 +1,291 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	pb "auth-microservice/auth-microservice/proto"
+	"auth-microservice/internal/audit"
+	"auth-microservice/internal/events"
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/store"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+)
+
+// APIKeyService กับ UserService ที่เรียกผ่าน interceptor เหมือน gRPC server พร้อม admin ของ tenant default
+type apiKeyFixture struct {
+	stores   *store.Stores
+	service  *APIKeyService
+	users    *UserService
+	adminCtx context.Context
+}
+
+func newAPIKeyFixture(t *testing.T) *apiKeyFixture {
+	t.Helper()
+	stores := newTestStores(t)
+	auditLogger := audit.NewLogger(stores.Audit)
+	return &apiKeyFixture{
+		stores:   stores,
+		service:  NewAPIKeyService(stores, auditLogger),
+		users:    NewUserService(stores, events.NewMemoryBus(), auditLogger),
+		adminCtx: adminContext(t, stores, auditLogger),
+	}
+}
+
+func (f *apiKeyFixture) create(t *testing.T, in *pb.CreateAPIKeyRequest) *pb.CreateAPIKeyReply {
+	t.Helper()
+	reply, err := f.service.CreateAPIKey(f.adminCtx, in)
+	if err != nil {
+		t.Fatalf("CreateAPIKey: %v", err)
+	}
+	return reply
+}
+
+// เรียก RPC method ผ่าน UnaryInterceptor ด้วย "authorization: ApiKey <key>"
+func (f *apiKeyFixture) call(key string, method string, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "ApiKey "+key))
+	return f.service.UnaryInterceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+}
+
+func (f *apiKeyFixture) listUsers(key string) error {
+	_, err := f.call(key, pb.UserService_ListUsers_FullMethodName, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return f.users.ListUsers(ctx, &pb.ListUsersRequest{})
+	})
+	return err
+}
+
+func (f *apiKeyFixture) auditActions(t *testing.T, action string) []models.AuditEvent {
+	t.Helper()
+	events, err := f.stores.Audit.ListEvents(context.Background(), store.AuditListQuery{TenantID: models.DefaultTenantID, Action: action, Limit: 100})
+	if err != nil {
+		t.Fatalf("ListEvents: %v", err)
+	}
+	return events
+}
+
+func TestAPIKeyIsShownOnceAndStoredHashed(t *testing.T) {
+	f := newAPIKeyFixture(t)
+	reply := f.create(t, &pb.CreateAPIKeyRequest{Name: "ci", Scopes: []string{models.ScopeUsersRead, models.ScopeUsersRead}})
+
+	key := reply.GetKey()
+	if !strings.HasPrefix(key, apiKeyPrefix) || !strings.HasPrefix(key, reply.GetApiKey().GetPrefix()) || len(reply.GetApiKey().GetPrefix()) != apiKeyPrefixLen {
+		t.Fatalf("key %q with prefix %q", key, reply.GetApiKey().GetPrefix())
+	}
+	if scopes := reply.GetApiKey().GetScopes(); len(scopes) != 1 || scopes[0] != models.ScopeUsersRead {
+		t.Fatalf("scopes = %v, want duplicates removed", scopes)
+	}
+
+	stored, err := f.stores.APIKeys.GetAPIKeyByHash(context.Background(), hashAPIKey(key))
+	if err != nil {
+		t.Fatalf("key is not stored under its hash: %v", err)
+	}
+	if stored.Hash == key || strings.Contains(stored.Hash, key) {
+		t.Fatal("key is stored in plaintext")
+	}
+	if expires := time.Until(stored.ExpiresAt); expires < defaultAPIKeyTTL-time.Minute || expires > defaultAPIKeyTTL {
+		t.Fatalf("key expires in %v, want the default %v", expires, defaultAPIKeyTTL)
+	}
+	if stored.RateLimit != defaultAPIKeyRateLimit {
+		t.Fatalf("rate limit = %d, want the default %d", stored.RateLimit, defaultAPIKeyRateLimit)
+	}
+
+	list, err := f.service.ListAPIKeys(f.adminCtx, &pb.ListAPIKeysRequest{})
+	if err != nil {
+		t.Fatalf("ListAPIKeys: %v", err)
+	}
+	if len(list.GetApiKeys()) != 1 || list.GetApiKeys()[0].GetId() != reply.GetApiKey().GetId() || list.GetApiKeys()[0].GetLastUsedAt() != "" {
+		t.Fatalf("ListAPIKeys = %v", list.GetApiKeys())
+	}
+}
+
+func TestCreateAPIKeyValidation(t *testing.T) {
+	f := newAPIKeyFixture(t)
+	for name, in := range map[string]*pb.CreateAPIKeyRequest{
+		"unknown scope":      {Name: "ci", Scopes: []string{"users:admin"}},
+		"expiry in the past": {Name: "ci", Scopes: []string{models.ScopeUsersRead}, ExpiresAt: time.Now().Add(-time.Hour).Format(time.RFC3339)},
+		"expiry over a year": {Name: "ci", Scopes: []string{models.ScopeUsersRead}, ExpiresAt: time.Now().Add(400 * 24 * time.Hour).Format(time.RFC3339)},
+		"expiry not RFC3339": {Name: "ci", Scopes: []string{models.ScopeUsersRead}, ExpiresAt: "tomorrow"},
+	} {
+		_, err := f.service.CreateAPIKey(f.adminCtx, in)
+		wantCode(t, "CreateAPIKey with "+name, err, codes.InvalidArgument)
+	}
+
+	// การจัดการ API key ต้องใช้ JWT ของผู้ใช้ ไม่ใช่ API key
+	key := f.create(t, &pb.CreateAPIKeyRequest{Name: "ci", Scopes: []string{models.ScopeUsersRead}}).GetKey()
+	_, err := f.call(key, pb.APIKeyService_CreateAPIKey_FullMethodName, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return f.service.CreateAPIKey(ctx, &pb.CreateAPIKeyRequest{Name: "escalated", Scopes: []string{models.ScopeUsersWrite}})
+	})
+	wantCode(t, "CreateAPIKey with an API key", err, codes.PermissionDenied)
+}
+
+func TestAPIKeyScopes(t *testing.T) {
+	f := newAPIKeyFixture(t)
+	key := f.create(t, &pb.CreateAPIKeyRequest{Name: "reader", Scopes: []string{models.ScopeUsersRead, models.ScopeGroupsRead}}).GetKey()
+
+	for _, tc := range []struct {
+		method string
+		want   codes.Code
+	}{
+		{pb.UserService_ListUsers_FullMethodName, codes.OK},
+		{pb.UserService_GetUserById_FullMethodName, codes.OK},
+		{pb.GroupService_ListGroups_FullMethodName, codes.OK},
+		{pb.UserService_UpdateUser_FullMethodName, codes.PermissionDenied},
+		{pb.UserService_DeleteUser_FullMethodName, codes.PermissionDenied},
+		{pb.TenantService_GetTenant_FullMethodName, codes.PermissionDenied},
+		{pb.TenantService_RotateSigningKey_FullMethodName, codes.PermissionDenied},
+		{pb.AuthService_ChangePassword_FullMethodName, codes.PermissionDenied}, // ไม่มี scope ที่ใช้ได้
+		{pb.AuthService_Logout_FullMethodName, codes.PermissionDenied},
+	} {
+		var claims map[string]interface{}
+		_, err := f.call(key, tc.method, func(ctx context.Context, req interface{}) (interface{}, error) {
+			claims, _ = apiKeyClaims(ctx)
+			return nil, nil
+		})
+		wantCode(t, tc.method, err, tc.want)
+		if tc.want != codes.OK {
+			continue
+		}
+		if claims["email"] != "admin@example.com" || claims["role"] != "admin" || claimsTenant(claims) != models.DefaultTenantID {
+			t.Fatalf("%s: claims = %v, want the key owner", tc.method, claims)
+		}
+	}
+
+	// handler จริงยืนยันตัวตนด้วย claims ของเจ้าของ key
+	if err := f.listUsers(key); err != nil {
+		t.Fatalf("ListUsers with an API key: %v", err)
+	}
+	// request ที่ไม่มี API key ผ่าน interceptor ไปโดยไม่มี claims
+	_, err := f.service.UnaryInterceptor(f.adminCtx, nil, &grpc.UnaryServerInfo{FullMethod: pb.AuthService_ChangePassword_FullMethodName}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		if _, ok := apiKeyClaims(ctx); ok {
+			t.Fatal("request with a JWT got API key claims")
+		}
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatalf("interceptor with a JWT: %v", err)
+	}
+}
+
+func TestAPIKeyRejected(t *testing.T) {
+	f := newAPIKeyFixture(t)
+	ctx := context.Background()
+
+	revoked := f.create(t, &pb.CreateAPIKeyRequest{Name: "revoked", Scopes: []string{models.ScopeUsersRead}})
+	if _, err := f.service.RevokeAPIKey(f.adminCtx, &pb.RevokeAPIKeyRequest{Id: revoked.GetApiKey().GetId()}); err != nil {
+		t.Fatalf("RevokeAPIKey: %v", err)
+	}
+	_, err := f.service.RevokeAPIKey(f.adminCtx, &pb.RevokeAPIKeyRequest{Id: revoked.GetApiKey().GetId()})
+	wantCode(t, "second RevokeAPIKey", err, codes.FailedPrecondition)
+
+	// key ที่หมดอายุแล้ว (CreateAPIKey ไม่รับวันหมดอายุในอดีตจึงบันทึกลง store ตรง ๆ)
+	owner, err := f.stores.Users.GetUserByEmail(ctx, models.DefaultTenantID, "admin@example.com")
+	if err != nil {
+		t.Fatalf("GetUserByEmail: %v", err)
+	}
+	expired, prefix, _ := newAPIKey()
+	if err := f.stores.APIKeys.CreateAPIKey(ctx, &models.APIKey{
+		TenantID:  models.DefaultTenantID,
+		UserID:    owner.ID,
+		Name:      "expired",
+		Prefix:    prefix,
+		Hash:      hashAPIKey(expired),
+		Scopes:    []string{models.ScopeUsersRead},
+		RateLimit: defaultAPIKeyRateLimit,
+		ExpiresAt: time.Now().Add(-time.Minute),
+		CreatedAt: time.Now().Add(-time.Hour),
+	}); err != nil {
+		t.Fatalf("CreateAPIKey: %v", err)
+	}
+
+	valid := f.create(t, &pb.CreateAPIKeyRequest{Name: "valid", Scopes: []string{models.ScopeUsersRead}}).GetKey()
+	for name, key := range map[string]string{
+		"unknown key":   apiKeyPrefix + "not-a-real-key",
+		"empty key":     "",
+		"revoked key":   revoked.GetKey(),
+		"expired key":   expired,
+		"truncated key": valid[:len(valid)-1],
+	} {
+		wantCode(t, "ListUsers with "+name, f.listUsers(key), codes.Unauthenticated)
+	}
+	if err := f.listUsers(valid); err != nil {
+		t.Fatalf("ListUsers with a valid key: %v", err)
+	}
+}
+
+func TestAPIKeyRateLimitAndUsage(t *testing.T) {
+	f := newAPIKeyFixture(t)
+	reply := f.create(t, &pb.CreateAPIKeyRequest{Name: "limited", Scopes: []string{models.ScopeUsersRead}, RateLimit: 2})
+	key := reply.GetKey()
+
+	for i := 0; i < 2; i++ {
+		if err := f.listUsers(key); err != nil {
+			t.Fatalf("request %d: %v", i+1, err)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		wantCode(t, "request over the rate limit", f.listUsers(key), codes.ResourceExhausted)
+	}
+
+	// เวลาใช้งานล่าสุดและ audit บันทึกไม่ถี่กว่า apiKeyTouchInterval ส่วนการเกิน limit บันทึกครั้งเดียวต่อนาที
+	if used := f.auditActions(t, "api_key.used"); len(used) != 1 || used[0].Details["apiKeyId"] != reply.GetApiKey().GetId() {
+		t.Fatalf("api_key.used events = %+v, want one", used)
+	}
+	if limited := f.auditActions(t, "api_key.rate_limited"); len(limited) != 1 {
+		t.Fatalf("api_key.rate_limited events = %+v, want one", limited)
+	}
+	stored, err := f.stores.APIKeys.GetAPIKey(context.Background(), models.DefaultTenantID, reply.GetApiKey().GetId())
+	if err != nil {
+		t.Fatalf("GetAPIKey: %v", err)
+	}
+	if stored.LastUsedAt == nil || time.Since(*stored.LastUsedAt) > time.Minute {
+		t.Fatalf("last used at = %v, want now", stored.LastUsedAt)
+	}
+
+	// ตัวนับแยกตาม key
+	other := f.create(t, &pb.CreateAPIKeyRequest{Name: "other", Scopes: []string{models.ScopeUsersRead}, RateLimit: 2}).GetKey()
+	if err := f.listUsers(other); err != nil {
+		t.Fatalf("another key after the first hit its limit: %v", err)
+	}
+}
+
+func TestAPIKeyOwnerAndTenant(t *testing.T) {
+	f := newAPIKeyFixture(t)
+	ctx := context.Background()
+	auditLogger := audit.NewLogger(f.stores.Audit)
+	bob := registerAndLogin(t, f.stores, auditLogger, "bob@example.com", "bob")
+	reply, err := f.service.CreateAPIKey(bob, &pb.CreateAPIKeyRequest{Name: "bob", Scopes: []string{models.ScopeUsersRead}})
+	if err != nil {
+		t.Fatalf("CreateAPIKey: %v", err)
+	}
+
+	// ผู้ใช้ทั่วไปดู key ของผู้อื่นไม่ได้ ส่วน admin ยกเลิก key ของผู้ใช้ใน tenant ได้
+	admin, _ := f.stores.Users.GetUserByEmail(ctx, models.DefaultTenantID, "admin@example.com")
+	_, err = f.service.ListAPIKeys(bob, &pb.ListAPIKeysRequest{UserId: admin.ID.Hex()})
+	wantCode(t, "ListAPIKeys of another user", err, codes.PermissionDenied)
+	list, err := f.service.ListAPIKeys(f.adminCtx, &pb.ListAPIKeysRequest{UserId: reply.GetApiKey().GetUserId()})
+	if err != nil || len(list.GetApiKeys()) != 1 {
+		t.Fatalf("admin ListAPIKeys of bob = %v, %v", list.GetApiKeys(), err)
+	}
+
+	// key ใช้ role ล่าสุดของเจ้าของ: bob ไม่ใช่ admin จึงเรียก ListUsers ไม่ได้แม้มี scope
+	wantCode(t, "ListUsers with a user's key", f.listUsers(reply.GetKey()), codes.PermissionDenied)
+
+	// key ใช้ไม่ได้เมื่อ tenant ถูกระงับ
+	tenant, err := loadTenant(ctx, f.stores.Tenants, models.DefaultTenantID)
+	if err != nil {
+		t.Fatalf("loadTenant: %v", err)
+	}
+	adminKey := f.create(t, &pb.CreateAPIKeyRequest{Name: "admin", Scopes: []string{models.ScopeUsersRead}}).GetKey()
+	tenant.Status = models.TenantSuspended
+	if err := f.stores.Tenants.SaveTenant(ctx, tenant); err != nil {
+		t.Fatalf("SaveTenant: %v", err)
+	}
+	if err := f.listUsers(adminKey); err == nil {
+		t.Fatal("API key was accepted for a suspended tenant")
+	}
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	pb "auth-microservice/auth-microservice/proto"
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/store"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const maxAuditFilterLength = 100
+
+func (s *AuditService) ListAuditEvents(ctx context.Context, in *pb.ListAuditEventsRequest) (*pb.ListAuditEventsReply, error) {
+	// ตรวจสอบสิทธิ์ admin
+	claims, err := requireAdmin(ctx, s.Blacklist, s.Tenants)
+	if err != nil {
+		return nil, err
+	}
+
+	if in.GetAfterId() != "" {
+		if _, err := primitive.ObjectIDFromHex(in.GetAfterId()); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "afterId ไม่ถูกต้อง")
+		}
+	}
+	if len(in.GetAction()) > maxAuditFilterLength || len(in.GetSubjectId()) > maxAuditFilterLength {
+		return nil, status.Error(codes.InvalidArgument, "ตัวกรองยาวเกินไป")
+	}
+
+	list, err := s.Events.ListEvents(ctx, store.AuditListQuery{
+		TenantID:  scopeTenant(ctx, claims),
+		AfterID:   in.GetAfterId(),
+		Action:    in.GetAction(),
+		SubjectID: in.GetSubjectId(),
+		Latest:    in.GetLatest(),
+		Limit:     int(clampLimit(in.GetLimit())),
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, "เกิดข้อผิดพลาดในการดึง audit log")
+	}
+
+	reply := &pb.ListAuditEventsReply{LastId: in.GetAfterId()}
+	for _, ev := range list {
+		reply.Events = append(reply.Events, toAuditEvent(ev))
+		reply.LastId = ev.ID.Hex()
+	}
+	return reply, nil
+}
+
+// แปลงเหตุการณ์ใน audit log เป็น protobuf (details เป็น JSON)
+func toAuditEvent(ev models.AuditEvent) *pb.AuditEvent {
+	item := &pb.AuditEvent{
+		Id:           ev.ID.Hex(),
+		TenantId:     ev.TenantID,
+		Action:       ev.Action,
+		ActorEmail:   ev.ActorEmail,
+		SubjectId:    ev.SubjectID,
+		SubjectEmail: ev.SubjectEmail,
+		CreatedAt:    ev.CreatedAt.UTC().Format(time.RFC3339),
+	}
+	if len(ev.Details) > 0 {
+		if data, err := json.Marshal(ev.Details); err == nil {
+			item.Details = string(data)
+		}
+	}
+	return item
+}
@@ -3,78 +3,143 @@ package service
 import (
 	pb "auth-microservice/auth-microservice/proto"
 	"context"
-	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"auth-microservice/internal/auth"
 	models "auth-microservice/internal/model"
 	"auth-microservice/internal/validation"
 
-	"github.com/golang-jwt/jwt"
-	"go.mongodb.org/mongo-driver/bson"
-	"golang.org/x/crypto/bcrypt"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
 func (s *AuthService) Register(ctx context.Context, in *pb.RegisterRequest) (*pb.RegisterReply, error) {
-	// ตรวจสอบความถูกต้องของอีเมล (เช่น ซ้ำกับผู้ใช้อื่นหรือไม่)
-	if err := validation.ValidateEmail(in.GetEmail(), ctx, s.UserCollection); err != nil {
+	// ผู้ใช้ใหม่อยู่ใน tenant ที่ระบุใน metadata (ไม่ระบุ = tenant default)
+	tenant, err := requestTenant(ctx, s.Tenants)
+	if err != nil {
+		return nil, err
+	}
+
+	// สมัครพร้อมคำเชิญเข้ากลุ่ม: คำเชิญต้องเป็นของอีเมลนี้ใน tenant เดียวกัน
+	var inv *invitation
+	if in.GetInviteToken() != "" {
+		if inv, err = loadInvitation(ctx, s.Cache, in.GetInviteToken()); err != nil {
+			return nil, err
+		}
+		if inv.TenantID != tenant.ID || !strings.EqualFold(inv.Email, in.GetEmail()) {
+			return nil, status.Error(codes.InvalidArgument, "คำเชิญนี้ไม่ใช่ของอีเมลนี้")
+		}
+	}
+
+	// สมัครเองได้เฉพาะ role user ส่วน role อื่นกำหนดโดย admin (SetUserRole, ImportUsers หรือคำสั่ง create-admin)
+	if in.GetRole() != "" && in.GetRole() != roleUser {
+		return nil, status.Error(codes.PermissionDenied, "การสมัครสมาชิกได้รับ role user เท่านั้น")
+	}
+
+	// ตรวจสอบความถูกต้องของอีเมล (เช่น ซ้ำกับผู้ใช้อื่นใน tenant หรือไม่)
+	if err := validation.ValidateEmail(in.GetEmail(), ctx, s.Users, tenant.ID); err != nil {
 		return nil, err
 	}
 
 	// ตรวจสอบความถูกต้องของ username
-	if err := validation.ValidateUsername(in.GetUsername(), ctx, s.UserCollection); err != nil {
+	if err := validation.ValidateUsername(in.GetUsername(), ctx, s.Users, tenant.ID); err != nil {
 		return nil, err
 	}
 
-	// ตรวจสอบความแข็งแรงของรหัสผ่านและเข้ารหัสด้วย bcrypt
-	hashedPassword, err1 := validation.ValidatePassword(in.GetPassword())
+	// ตรวจสอบความแข็งแรงของรหัสผ่านตาม policy ของ tenant และเข้ารหัสด้วย bcrypt
+	hashedPassword, err1 := validation.ValidatePassword(in.GetPassword(), tenant.PasswordPolicy)
 	if err1 != nil {
 		return nil, err1
 	}
 
-	// สร้าง user document เตรียมสำหรับ insert ลง MongoDB
-	user := map[string]interface{}{
-		"email":     in.GetEmail(),
-		"username":  in.GetUsername(),
-		"password":  string(hashedPassword),
-		"createdAt": time.Now(),
-		"updatedAt": time.Now(),
-		"deleted":   false,
-		"deletedAt": nil,
-		"role":      in.GetRole(),
+	// สร้างข้อมูลผู้ใช้ใหม่
+	now := time.Now()
+	user := &models.User{
+		TenantID:          tenant.ID,
+		Email:             in.GetEmail(),
+		Username:          in.GetUsername(),
+		Password:          hashedPassword,
+		PasswordHistory:   []string{},
+		PasswordChangedAt: &now,
+		Role:              roleUser,
+		CreatedAt:         now,
+		UpdatedAt:         now,
 	}
 
-	// บันทึกลงใน collection ของผู้ใช้
-	_, err := s.UserCollection.InsertOne(ctx, user)
+	// บันทึกลงในที่เก็บข้อมูลผู้ใช้พร้อม event "user.registered" ใน transaction เดียวกัน
+	// (unique constraint กันอีเมล/username ซ้ำเมื่อมี request พร้อมกัน)
+	event, err := newOutboxEvent(tenant.ID, "user.registered", map[string]interface{}{
+		"email":     user.Email,
+		"username":  user.Username,
+		"role":      user.Role,
+		"createdAt": now.UTC(),
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถสร้าง event ได้")
+	}
+	err = s.Outbox.CreateUserWithEvent(ctx, user, event)
+	if dupErr := duplicateKeyError(err); dupErr != nil {
+		return nil, dupErr
+	}
 	if err != nil {
 		return nil, err
 	}
 
+	s.Audit.Record(ctx, models.AuditEvent{
+		TenantID:     tenant.ID,
+		Action:       "user.registered",
+		ActorEmail:   in.GetEmail(),
+		SubjectID:    user.ID.Hex(),
+		SubjectEmail: in.GetEmail(),
+	})
+
+	// บัญชีถูกสร้างแล้ว ถ้ารับคำเชิญไม่สำเร็จผู้ใช้ยังเรียก AcceptInvitation ภายหลังได้
+	if inv != nil {
+		joined, err := acceptInvitation(ctx, s.Cache, s.Groups, in.GetInviteToken(), inv, user)
+		if err != nil {
+			log.Printf("รับคำเชิญเข้ากลุ่มของ %s ไม่สำเร็จ: %v", in.GetEmail(), err)
+		} else {
+			s.Audit.Record(ctx, models.AuditEvent{
+				TenantID:     tenant.ID,
+				Action:       "group.invitation_accepted",
+				ActorEmail:   in.GetEmail(),
+				SubjectID:    user.ID.Hex(),
+				SubjectEmail: in.GetEmail(),
+				Details:      map[string]interface{}{"groupId": joined.Group.ID.Hex(), "role": joined.Role, "invitedBy": inv.InvitedBy},
+			})
+		}
+	}
+
 	// ส่ง response กลับไปยัง client
 	return &pb.RegisterReply{
 		Email:     in.GetEmail(),
 		Username:  in.GetUsername(),
-		CreatedAt: time.Now().Format(time.RFC3339),
+		CreatedAt: now.Format(time.RFC3339),
 	}, nil
 }
 
 func (s *AuthService) Login(ctx context.Context, in *pb.LoginRequest) (*pb.LoginReply, error) {
-	// สร้าง filter เพื่อค้นหาผู้ใช้จาก email
-	filter := bson.M{"email": in.GetEmail(), "deleted": bson.M{"$ne": true}}
+	// ผู้ใช้เข้าสู่ระบบใน tenant ที่ระบุใน metadata (ไม่ระบุ = tenant default)
+	tenant, err := requestTenant(ctx, s.Tenants)
+	if err != nil {
+		return nil, err
+	}
 
-	var user map[string]interface{}
+	// อีเมลในโดเมนของ directory ขององค์กร ตรวจสอบรหัสผ่านกับ directory แทน
+	if reply, handled, err := s.directoryLogin(ctx, tenant, in); handled || err != nil {
+		return reply, err
+	}
 
-	// ค้นหา user จาก MongoDB
-	err := s.UserCollection.FindOne(ctx, filter).Decode(&user)
+	// ค้นหา user จาก email ใน tenant (เฉพาะผู้ใช้ที่ยังไม่ถูกลบ)
+	user, err := s.Users.GetUserByEmail(ctx, tenant.ID, in.GetEmail())
 	if err != nil {
 		return nil, status.Error(codes.NotFound, "ไม่พบผู้ใช้ที่มีอีเมลนี้")
 	}
 
 	// ตรวจสอบว่าเกิน rate limit หรือไม่
-	isLimited, err := s.isRateLimited(ctx, in.GetEmail())
+	isLimited, err := s.isRateLimited(ctx, tenant.ID, in.GetEmail())
 	if err != nil {
 		return nil, status.Error(codes.Internal, "ไม่สามารถตรวจสอบ Rate Limit ได้")
 	}
@@ -83,49 +148,38 @@ func (s *AuthService) Login(ctx context.Context, in *pb.LoginRequest) (*pb.Login
 	}
 
 	// ตรวจสอบรหัสผ่านว่าตรงกับที่เก็บไว้หรือไม่
-	hashedPassword := user["password"].(string)
-	err = bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(in.GetPassword()))
+	err = auth.ComparePassword(user.Password, in.GetPassword())
 	if err != nil {
 		// ถ้ารหัสผ่านผิด ก็ยังคงเพิ่ม count ให้ rate limit
-		s.isRateLimited(ctx, in.GetEmail()) // เพิ่มการนับ rate limit เมื่อใส่รหัสผิดด้วย
+		s.isRateLimited(ctx, tenant.ID, in.GetEmail()) // เพิ่มการนับ rate limit เมื่อใส่รหัสผิดด้วย
+		s.recordLogin(ctx, "user.login_failed", user)
 		return nil, status.Error(codes.Unauthenticated, "รหัสผ่านไม่ถูกต้อง")
 	}
 
+	// ผู้ใช้ที่บังคับใช้ passkey ต้องยืนยันด้วย passkey ก่อนจึงจะได้ token
+	if user.PasskeyRequired {
+		return beginPasskeySecondFactor(ctx, s.Cache, user, isPasswordExpired(user, tenant.PasswordPolicy))
+	}
+
 	// ตรวจสอบและทำให้โทเค็นเก่าใช้งานไม่ได้
-	userEmail := in.GetEmail()
-	redisKey := fmt.Sprintf("active_token:%s", userEmail)
-
-	oldToken, err := s.Redis.Get(ctx, redisKey).Result()
-	if err == nil && oldToken != "" {
-		// ถ้ามี token เก่าอยู่, ให้เพิ่มเข้า Blacklist
-		blacklistedToken := models.BlacklistedToken{
-			Token:     oldToken,
-			ExpiresAt: time.Now(), // หมดอายุทันที
-		}
-		_, err := s.BlacklistCollection.InsertOne(ctx, blacklistedToken)
-		if err != nil {
-			return nil, status.Error(codes.Internal, "ไม่สามารถเพิ่ม token เข้า blacklisted ได้")
-		}
+	if err := s.revokeActiveToken(ctx, tenant.ID, in.GetEmail()); err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถเพิ่ม token เข้า blacklisted ได้")
 	}
 
-	// สร้าง JWT Token
-	token, err := auth.GenerateJWT(in.GetEmail(), user["role"].(string))
+	// สร้าง JWT Token และบันทึกเป็น active token
+	token, err := s.issueToken(ctx, tenant, user.ID.Hex(), in.GetEmail(), user.Role)
 	if err != nil {
 		return nil, status.Error(codes.Internal, "เจอข้อผิดพลาดในการสร้างโทเค็น")
 	}
 
-	// บันทึก token ใหม่ลง Redis พร้อมระบุเวลา expiration
-	jwtExpiration := time.Hour * 24
-	err = s.Redis.Set(ctx, redisKey, token, jwtExpiration).Err()
-	if err != nil {
-		log.Printf("Could not set active token in Redis for user %s: %v", userEmail, err)
-	}
+	s.recordLogin(ctx, "user.login", user)
 
 	// ส่งข้อมูลกลับไปยัง client
 	return &pb.LoginReply{
-		Email:    user["email"].(string),
-		Username: user["username"].(string),
-		Token:    token,
+		Email:           user.Email,
+		Username:        user.Username,
+		Token:           token,
+		PasswordExpired: isPasswordExpired(user, tenant.PasswordPolicy),
 	}, nil
 }
 
@@ -146,25 +200,15 @@ func (s *AuthService) Logout(ctx context.Context, in *pb.LogoutRequest) (*pb.Log
 		return nil, status.Error(codes.FailedPrecondition, "โทเค็นนี้ถูกบล็อกแล้ว")
 	}
 
-	// ตรวจสอบ JWT token ว่าถูกต้อง
-	token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("วิธีการเซ็นชื่อโทเค็นไม่ถูกต้อง:: %v", token.Header["alg"])
-		}
-		return auth.JwtSecret, nil
-	})
-
-	if err != nil || !token.Valid {
+	// ตรวจสอบ JWT token ว่าถูกต้อง (ด้วย signing key ของ tenant เจ้าของ token)
+	claims, err := auth.ParseToken(tokenStr, models.DefaultTenantID, tenantKeys(ctx, s.Tenants))
+	if err != nil {
 		return nil, status.Error(codes.Unauthenticated, "โทเค็นไม่ถูกต้องหรือหมดอายุ")
 	}
 
-	// ดึง email ของผู้ใช้
-	var userEmail string
-	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		if email, ok := claims["email"].(string); ok {
-			userEmail = email
-		}
-	}
+	// ดึง email และ tenant ของผู้ใช้
+	userEmail, _ := claims["email"].(string)
+	tenantID := claimsTenant(claims)
 
 	if userEmail == "" {
 		return nil, status.Error(codes.InvalidArgument, "ไม่สามารถระบุผู้ใช้จากโทเค็นได้")
@@ -176,80 +220,45 @@ func (s *AuthService) Logout(ctx context.Context, in *pb.LogoutRequest) (*pb.Log
 		exp = time.Now().Add(24 * time.Hour) // กำหนด default expiration
 	}
 
-	// เพิ่ม token เข้า blacklist
-	blacklistedToken := models.BlacklistedToken{
-		Token:     tokenStr,
-		ExpiresAt: exp,
-	}
-
-	// ลบ token ออกจาก Redis
-	_, err = s.BlacklistCollection.InsertOne(ctx, blacklistedToken)
+	// เพิ่ม token เข้า blacklist พร้อม event "user.logout" ใน transaction เดียวกัน (ส่งเฉพาะ hash ของ token)
+	event, err := newOutboxEvent(tenantID, "user.logout", map[string]interface{}{
+		"email":     userEmail,
+		"tokenHash": hashAPIKey(tokenStr),
+		"expiresAt": exp.UTC(),
+	})
 	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถสร้าง event ได้")
+	}
+	if err := s.Outbox.AddTokenWithEvent(ctx, tokenStr, exp, event); err != nil {
 		return nil, status.Error(codes.Internal, "ไม่สามารถบล็อกโทเค็นได้")
 	}
 
-	// ลบ Active Token ออกจาก Redis
-	redisKey := fmt.Sprintf("active_token:%s", userEmail)
-	if err := s.Redis.Del(ctx, redisKey).Err(); err != nil {
-		log.Printf("Could not delete active token from Redis for user %s: %v", userEmail, err)
+	// ลบ Active Token ของผู้ใช้
+	if err := s.Sessions.DeleteActiveToken(ctx, tenantID, userEmail); err != nil {
+		log.Printf("Could not delete active token for user %s: %v", userEmail, err)
 	}
 
+	s.Audit.Record(ctx, models.AuditEvent{
+		TenantID:     tenantID,
+		Action:       "user.logout",
+		ActorEmail:   userEmail,
+		SubjectEmail: userEmail,
+	})
+
 	// ส่งข้อความว่า logout สำเร็จ
 	return &pb.LogoutReply{
 		Message: "ออกจากระบบสำเร็จ",
 	}, nil
 }
 
-// func (s *AuthService) RegisterBulkUsers(ctx context.Context, count int) error {
-// 	const batchSize = 1000
-
-// 	var wg sync.WaitGroup
-// 	sem := make(chan struct{}, 10) // จำกัด concurrency สูงสุด 10 batch พร้อมกัน
-
-// 	startTime := time.Now()
-
-// 	for i := 0; i < count; i += batchSize {
-// 		wg.Add(1)
-// 		sem <- struct{}{}
-
-// 		go func(start int) {
-// 			defer wg.Done()
-// 			defer func() { <-sem }()
-
-// 			var users []interface{}
-// 			now := time.Now()
-
-// 			for j := start; j < start+batchSize && j < count; j++ {
-// 				email := fmt.Sprintf("bulkuser%06d@example.com", j)
-// 				username := fmt.Sprintf("bulkuser%06d", j)
-// 				password := "Password123!"
-
-// 				// เข้ารหัสรหัสผ่านเลย โดยไม่เช็คซ้ำ
-// 				hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-
-// 				user := bson.M{
-// 					"email":     email,
-// 					"username":  username,
-// 					"password":  string(hashedPassword),
-// 					"createdAt": now,
-// 					"updatedAt": now,
-// 					"deleted":   false,
-// 					"deletedAt": nil,
-// 					"role":      "user",
-// 				}
-// 				users = append(users, user)
-// 			}
-
-// 			_, err := s.UserCollection.InsertMany(ctx, users)
-// 			if err != nil {
-// 				log.Printf("❌ Insert batch %d-%d failed: %v", start, start+batchSize, err)
-// 			} else {
-// 				log.Printf("✅ Inserted batch %d-%d", start, start+batchSize)
-// 			}
-// 		}(i)
-// 	}
-
-// 	wg.Wait()
-// 	log.Printf("🎉 Bulk register %d users completed in %s", count, time.Since(startTime))
-// 	return nil
-// }
+// บันทึกผลการเข้าสู่ระบบลง audit log (ใช้เป็นประวัติการเข้าสู่ระบบ)
+func (s *AuthService) recordLogin(ctx context.Context, action string, user *models.User) {
+	s.Audit.Record(ctx, models.AuditEvent{
+		TenantID:     user.TenantID,
+		Action:       action,
+		ActorEmail:   user.Email,
+		SubjectID:    user.ID.Hex(),
+		SubjectEmail: user.Email,
+		Details:      requestDetails(ctx),
+	})
+}
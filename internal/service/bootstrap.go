@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"auth-microservice/internal/audit"
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/store"
+	"auth-microservice/internal/validation"
+)
+
+// สร้าง admin ของระบบ (role admin ใน tenant default) โดยไม่ผ่าน gRPC ใช้ตอนติดตั้งครั้งแรก
+// ถ้ามีผู้ใช้อีเมลนี้อยู่แล้วจะเปลี่ยน role เป็น admin (ไม่เปลี่ยนรหัสผ่าน) คืน true ถ้าสร้างผู้ใช้ใหม่
+func BootstrapAdmin(ctx context.Context, stores *store.Stores, auditLogger *audit.Logger, email string, username string, password string) (bool, error) {
+	tenant, err := loadTenant(ctx, stores.Tenants, models.DefaultTenantID)
+	if err != nil {
+		return false, err
+	}
+
+	existing, err := stores.Users.GetUserByEmail(ctx, tenant.ID, email)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		return false, err
+	}
+	now := time.Now()
+	if existing != nil {
+		if existing.Role == roleAdmin {
+			return false, nil
+		}
+		role := roleAdmin
+		event, err := newUserEvent(existing, "user.updated", map[string]interface{}{"email": existing.Email, "fields": []string{"role"}})
+		if err != nil {
+			return false, err
+		}
+		if err := stores.Outbox.UpdateProfileWithEvent(ctx, existing.ID.Hex(), store.ProfilePatch{Role: &role}, now, event); err != nil {
+			return false, err
+		}
+		if err := revokeActiveToken(ctx, stores.Sessions, stores.Blacklist, tenant.ID, existing.Email); err != nil {
+			return false, err
+		}
+		auditLogger.Record(ctx, models.AuditEvent{
+			TenantID:     tenant.ID,
+			Action:       "user.role_changed",
+			SubjectID:    existing.ID.Hex(),
+			SubjectEmail: existing.Email,
+			Details:      map[string]interface{}{"from": existing.Role, "to": role, "via": "create-admin"},
+		})
+		return false, nil
+	}
+
+	if err := validation.ValidateEmail(email, ctx, stores.Users, tenant.ID); err != nil {
+		return false, err
+	}
+	if err := validation.ValidateUsername(username, ctx, stores.Users, tenant.ID); err != nil {
+		return false, err
+	}
+	hashedPassword, err := validation.ValidatePassword(password, tenant.PasswordPolicy)
+	if err != nil {
+		return false, err
+	}
+	user := &models.User{
+		TenantID:          tenant.ID,
+		Email:             email,
+		Username:          username,
+		Password:          hashedPassword,
+		PasswordHistory:   []string{},
+		PasswordChangedAt: &now,
+		Role:              roleAdmin,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+	event, err := newOutboxEvent(tenant.ID, "user.registered", map[string]interface{}{
+		"email":     user.Email,
+		"username":  user.Username,
+		"role":      user.Role,
+		"createdAt": now.UTC(),
+	})
+	if err != nil {
+		return false, err
+	}
+	if err := stores.Outbox.CreateUserWithEvent(ctx, user, event); err != nil {
+		return false, err
+	}
+	auditLogger.Record(ctx, models.AuditEvent{
+		TenantID:     tenant.ID,
+		Action:       "user.registered",
+		SubjectID:    user.ID.Hex(),
+		SubjectEmail: user.Email,
+		Details:      map[string]interface{}{"role": roleAdmin, "via": "create-admin"},
+	})
+	return true, nil
+}
@@ -0,0 +1,293 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	pb "auth-microservice/auth-microservice/proto"
+	"auth-microservice/internal/directory"
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/store"
+	"auth-microservice/internal/validation"
+
+	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// connector ของ directory ที่สร้างแล้ว (สร้างใหม่เมื่อการตั้งค่าเปลี่ยน)
+type cachedDirectory struct {
+	tenantID  string
+	updatedAt time.Time
+	dir       directory.Directory
+}
+
+// เข้าสู่ระบบด้วยรหัสผ่านใน directory ขององค์กร ถ้าอีเมลอยู่ในโดเมนของ directory ใน tenant
+// handled = false หมายถึงให้ Login ตรวจสอบรหัสผ่านในระบบนี้ตามปกติ
+func (s *AuthService) directoryLogin(ctx context.Context, tenant *models.Tenant, in *pb.LoginRequest) (*pb.LoginReply, bool, error) {
+	cfg, err := s.matchDirectory(ctx, tenant.ID, in.GetEmail())
+	if err != nil || cfg == nil {
+		return nil, err != nil, err
+	}
+	dir, err := s.directory(cfg)
+	if err != nil {
+		log.Printf("สร้าง connector ของ directory %s ไม่สำเร็จ: %v", cfg.Name, err)
+		return nil, true, status.Error(codes.Unavailable, "ไม่สามารถเชื่อมต่อ directory ได้")
+	}
+
+	isLimited, err := s.isRateLimited(ctx, tenant.ID, in.GetEmail())
+	if err != nil {
+		return nil, true, status.Error(codes.Internal, "ไม่สามารถตรวจสอบ Rate Limit ได้")
+	}
+	if isLimited {
+		return nil, true, status.Error(codes.ResourceExhausted, "คุณพยายามเข้าสู่ระบบบ่อยเกินไป กรุณารอ 1 นาที")
+	}
+
+	entry, err := dir.Authenticate(ctx, in.GetEmail(), in.GetPassword())
+	switch {
+	case errors.Is(err, directory.ErrUserNotFound) && cfg.AllowLocalFallback:
+		// ไม่ใช่พนักงานใน directory ให้ใช้รหัสผ่านในระบบนี้ (rate limit จะนับใน Login อีกครั้ง)
+		return nil, false, nil
+	case errors.Is(err, directory.ErrInvalidCredentials), errors.Is(err, directory.ErrUserNotFound):
+		s.isRateLimited(ctx, tenant.ID, in.GetEmail())
+		if user, err := s.Users.GetUserByEmail(ctx, tenant.ID, in.GetEmail()); err == nil {
+			s.recordDirectoryLogin(ctx, "user.login_failed", user, cfg)
+		}
+		return nil, true, status.Error(codes.Unauthenticated, "อีเมลหรือรหัสผ่านไม่ถูกต้อง")
+	case err != nil:
+		log.Printf("ตรวจสอบรหัสผ่านของ %s กับ directory %s ไม่สำเร็จ: %v", in.GetEmail(), cfg.Name, err)
+		return nil, true, status.Error(codes.Unavailable, "ไม่สามารถเชื่อมต่อ directory ได้")
+	}
+
+	user, err := s.directoryUser(ctx, cfg, in.GetEmail(), entry)
+	if err != nil {
+		return nil, true, err
+	}
+
+	if user.PasskeyRequired {
+		reply, err := beginPasskeySecondFactor(ctx, s.Cache, user, false)
+		return reply, true, err
+	}
+
+	if err := s.revokeActiveToken(ctx, tenant.ID, user.Email); err != nil {
+		return nil, true, status.Error(codes.Internal, "ไม่สามารถเพิ่ม token เข้า blacklisted ได้")
+	}
+	token, err := s.issueToken(ctx, tenant, user.ID.Hex(), user.Email, user.Role)
+	if err != nil {
+		return nil, true, status.Error(codes.Internal, "เจอข้อผิดพลาดในการสร้างโทเค็น")
+	}
+
+	s.recordDirectoryLogin(ctx, "user.login", user, cfg)
+
+	// รหัสผ่านอยู่ใน directory จึงไม่ใช้ policy อายุรหัสผ่านของ tenant
+	return &pb.LoginReply{
+		Email:    user.Email,
+		Username: user.Username,
+		Token:    token,
+	}, true, nil
+}
+
+// ผู้ใช้ในระบบนี้ของบัญชีใน directory: สร้างใหม่เมื่อเข้าสู่ระบบครั้งแรก และปรับ role ตามกลุ่มทุกครั้งที่เข้าสู่ระบบ
+func (s *AuthService) directoryUser(ctx context.Context, cfg *models.Directory, email string, entry *directory.Entry) (*models.User, error) {
+	role := directoryRole(cfg, entry.Groups)
+
+	user, err := s.Users.GetUserByEmail(ctx, cfg.TenantID, email)
+	if err == nil {
+		if user.Role != role {
+			if err := s.Users.UpdateProfile(ctx, user.ID.Hex(), store.ProfilePatch{Role: &role}, time.Now()); err != nil {
+				return nil, status.Error(codes.Internal, "ไม่สามารถปรับ role ของผู้ใช้ได้")
+			}
+			s.Audit.Record(ctx, models.AuditEvent{
+				TenantID:     user.TenantID,
+				Action:       "user.role_changed",
+				ActorEmail:   user.Email,
+				SubjectID:    user.ID.Hex(),
+				SubjectEmail: user.Email,
+				Details:      map[string]interface{}{"directory": cfg.Name, "from": user.Role, "to": role},
+			})
+			user.Role = role
+		}
+		return user, nil
+	}
+	if !errors.Is(err, store.ErrNotFound) {
+		return nil, status.Error(codes.Internal, "ไม่สามารถค้นหาผู้ใช้ได้")
+	}
+
+	if err := validation.ValidateEmailFormat(email); err != nil {
+		return nil, err
+	}
+	username, err := availableUsername(ctx, s.Users, cfg.TenantID, entry.Username, email)
+	if err != nil {
+		return nil, err
+	}
+	// รหัสผ่านในระบบนี้เป็นค่าสุ่มที่ไม่มีใครรู้ การเข้าสู่ระบบทำผ่าน directory เท่านั้น
+	password, err := generateRandomToken(32)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถสร้างรหัสผ่านได้")
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถสร้างรหัสผ่านได้")
+	}
+
+	now := time.Now()
+	user = &models.User{
+		TenantID:          cfg.TenantID,
+		Email:             email,
+		Username:          username,
+		Password:          string(hashedPassword),
+		PasswordHistory:   []string{},
+		PasswordChangedAt: &now,
+		Role:              role,
+		EmailVerified:     true,
+		DisplayName:       entry.DisplayName,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+	err = s.Users.CreateUser(ctx, user)
+	if dupErr := duplicateKeyError(err); dupErr != nil {
+		return nil, dupErr
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถสร้างผู้ใช้ได้")
+	}
+
+	s.Audit.Record(ctx, models.AuditEvent{
+		TenantID:     user.TenantID,
+		Action:       "user.registered",
+		ActorEmail:   user.Email,
+		SubjectID:    user.ID.Hex(),
+		SubjectEmail: user.Email,
+		Details:      map[string]interface{}{"directory": cfg.Name},
+	})
+	return user, nil
+}
+
+// directory ที่ดูแลอีเมลนี้ใน tenant (directory ที่ระบุโดเมนตรงกันมาก่อน directory ที่ไม่ระบุโดเมน) คืน nil ถ้าไม่มี
+func (s *AuthService) matchDirectory(ctx context.Context, tenantID string, email string) (*models.Directory, error) {
+	if s.Identities == nil {
+		return nil, nil
+	}
+	dirs, err := s.Identities.ListDirectories(ctx, tenantID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถดึงรายการ directory ได้")
+	}
+	s.pruneDirectories(tenantID, dirs)
+
+	var fallback *models.Directory
+	for i := range dirs {
+		dir := &dirs[i]
+		if len(dir.Domains) == 0 {
+			if fallback == nil {
+				fallback = dir
+			}
+			continue
+		}
+		if emailDomainAllowed(email, dir.Domains) {
+			return dir, nil
+		}
+	}
+	return fallback, nil
+}
+
+// connector ของ directory (ใช้ connection pool ร่วมกันระหว่างการเข้าสู่ระบบ)
+func (s *AuthService) directory(cfg *models.Directory) (directory.Directory, error) {
+	s.directoriesMu.Lock()
+	defer s.directoriesMu.Unlock()
+
+	id := cfg.ID.Hex()
+	if cached, ok := s.directories[id]; ok {
+		if cached.updatedAt.Equal(cfg.UpdatedAt) {
+			return cached.dir, nil
+		}
+		cached.dir.Close()
+		delete(s.directories, id)
+	}
+	dir, err := s.NewDirectory(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if s.directories == nil {
+		s.directories = map[string]cachedDirectory{}
+	}
+	s.directories[id] = cachedDirectory{tenantID: cfg.TenantID, updatedAt: cfg.UpdatedAt, dir: dir}
+	return dir, nil
+}
+
+// ปิด connector ของ directory ที่ถูกลบหรือเปลี่ยนการตั้งค่าแล้วใน tenant
+func (s *AuthService) pruneDirectories(tenantID string, dirs []models.Directory) {
+	s.directoriesMu.Lock()
+	defer s.directoriesMu.Unlock()
+
+	current := map[string]time.Time{}
+	for _, dir := range dirs {
+		current[dir.ID.Hex()] = dir.UpdatedAt
+	}
+	for id, cached := range s.directories {
+		if cached.tenantID != tenantID {
+			continue
+		}
+		if updatedAt, ok := current[id]; !ok || !updatedAt.Equal(cached.updatedAt) {
+			cached.dir.Close()
+			delete(s.directories, id)
+		}
+	}
+}
+
+// connector เริ่มต้น: LDAP ตามการตั้งค่าของ directory
+func newLDAPDirectory(cfg *models.Directory) (directory.Directory, error) {
+	return directory.NewLDAP(directory.Config{
+		URL:                  cfg.URL,
+		StartTLS:             cfg.StartTLS,
+		RootCA:               cfg.RootCA,
+		BindDN:               cfg.BindDN,
+		BindPassword:         cfg.BindPassword,
+		UserDNTemplate:       cfg.UserDNTemplate,
+		BaseDN:               cfg.BaseDN,
+		UserFilter:           cfg.UserFilter,
+		UsernameAttribute:    cfg.UsernameAttribute,
+		DisplayNameAttribute: cfg.DisplayNameAttribute,
+		GroupFilter:          cfg.GroupFilter,
+		PoolSize:             cfg.PoolSize,
+	})
+}
+
+// role ของผู้ใช้ตาม mapping แรกที่ตรงกับกลุ่มของผู้ใช้ (ไม่ตรงเลย = DefaultRole)
+func directoryRole(cfg *models.Directory, groups []string) string {
+	for _, mapping := range cfg.RoleMappings {
+		for _, group := range groups {
+			if directory.EqualDN(mapping.Group, group) {
+				return mapping.Role
+			}
+		}
+	}
+	return cfg.DefaultRole
+}
+
+// บันทึกผลการเข้าสู่ระบบผ่าน directory ลง audit log
+func (s *AuthService) recordDirectoryLogin(ctx context.Context, action string, user *models.User, cfg *models.Directory) {
+	details := requestDetails(ctx)
+	details["directory"] = cfg.Name
+	s.Audit.Record(ctx, models.AuditEvent{
+		TenantID:     user.TenantID,
+		Action:       action,
+		ActorEmail:   user.Email,
+		SubjectID:    user.ID.Hex(),
+		SubjectEmail: user.Email,
+		Details:      details,
+	})
+}
+
+// ผู้ใช้ที่ต้องเปลี่ยนรหัสผ่านที่ directory ขององค์กร (ไม่ใช่ในระบบนี้)
+func (s *AuthService) directoryManagedPassword(ctx context.Context, tenantID string, email string) error {
+	cfg, err := s.matchDirectory(ctx, tenantID, email)
+	if err != nil {
+		return err
+	}
+	if cfg != nil && !cfg.AllowLocalFallback {
+		return status.Errorf(codes.FailedPrecondition, "รหัสผ่านของอีเมลนี้อยู่ใน directory %s กรุณาเปลี่ยนรหัสผ่านที่ระบบขององค์กร", cfg.Name)
+	}
+	return nil
+}
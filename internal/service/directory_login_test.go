@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	pb "auth-microservice/auth-microservice/proto"
+	"auth-microservice/internal/audit"
+	"auth-microservice/internal/directory/directorytest"
+	"auth-microservice/internal/notify"
+	"auth-microservice/internal/store"
+
+	"google.golang.org/grpc/codes"
+)
+
+const (
+	ldapAdmins = "cn=admins,ou=groups,dc=corp,dc=example"
+	ldapStaff  = "cn=staff,ou=groups,dc=corp,dc=example"
+)
+
+// AuthService ที่ตรวจรหัสผ่านของอีเมล @corp.example.com กับ LDAP server ใน process
+type directoryFixture struct {
+	stores      *store.Stores
+	auditLogger *audit.Logger
+	ldap        *directorytest.Server
+	auth        *AuthService
+	federation  *FederationService
+	adminCtx    context.Context
+	directoryID string
+}
+
+// สร้าง directory "corp" แบบค้นหาด้วย service account ก่อน bind โดยแก้การตั้งค่าด้วย configure ได้
+func newDirectoryFixture(t *testing.T, configure func(req *pb.CreateDirectoryRequest, ldap *directorytest.Server)) *directoryFixture {
+	t.Helper()
+	stores := newTestStores(t)
+	auditLogger := audit.NewLogger(stores.Audit)
+	f := &directoryFixture{
+		stores:      stores,
+		auditLogger: auditLogger,
+		ldap:        directorytest.NewServer(t),
+		auth:        NewAuthService(stores, notify.NewLogNotifier(), auditLogger),
+		federation:  NewFederationService(stores, "http://localhost:8080", false, auditLogger),
+		adminCtx:    adminContext(t, stores, auditLogger),
+	}
+	t.Cleanup(func() {
+		for _, cached := range f.auth.directories {
+			cached.dir.Close()
+		}
+	})
+	f.ldap.Add(directorytest.Entry{DN: "cn=svc,dc=corp,dc=example", Password: "svc-secret"})
+	f.ldap.Add(directorytest.Entry{
+		DN:       "uid=dana,ou=people,dc=corp,dc=example",
+		Password: "dana-pw",
+		Attributes: map[string][]string{
+			"objectClass": {"person"},
+			"uid":         {"dana"},
+			"cn":          {"Dana Kim"},
+			"mail":        {"dana@corp.example.com"},
+			"memberOf":    {ldapStaff, ldapAdmins},
+		},
+	})
+
+	req := &pb.CreateDirectoryRequest{
+		Name:         "corp",
+		Url:          f.ldap.URL,
+		BindDn:       "cn=svc,dc=corp,dc=example",
+		BindPassword: "svc-secret",
+		BaseDn:       "dc=corp,dc=example",
+		UserFilter:   "(&(objectClass=person)(mail={email}))",
+		RoleMappings: []*pb.RoleMapping{{Group: "CN=Admins,OU=Groups,DC=corp,DC=example", Role: "admin"}},
+		Domains:      []string{"@Corp.Example.com"},
+	}
+	if configure != nil {
+		configure(req, f.ldap)
+	}
+	dir, err := f.federation.CreateDirectory(f.adminCtx, req)
+	if err != nil {
+		t.Fatalf("CreateDirectory: %v", err)
+	}
+	f.directoryID = dir.GetId()
+	return f
+}
+
+func (f *directoryFixture) login(email string, password string) (*pb.LoginReply, error) {
+	return f.auth.Login(context.Background(), &pb.LoginRequest{Email: email, Password: password})
+}
+
+func TestDirectoryLoginProvisionsUser(t *testing.T) {
+	f := newDirectoryFixture(t, nil)
+
+	reply, err := f.login("dana@corp.example.com", "dana-pw")
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if reply.GetToken() == "" || reply.GetUsername() != "dana" {
+		t.Fatalf("Login = %+v, want a token for dana", reply)
+	}
+	user, err := f.stores.Users.GetUserByEmail(context.Background(), "default", "dana@corp.example.com")
+	if err != nil {
+		t.Fatalf("provisioned user: %v", err)
+	}
+	if user.Role != "admin" || !user.EmailVerified || user.DisplayName != "Dana Kim" {
+		t.Fatalf("provisioned user = %+v, want a verified admin named Dana Kim", user)
+	}
+
+	// role ตามกลุ่มใน directory ถูกปรับทุกครั้งที่เข้าสู่ระบบ
+	f.ldap.SetAttribute("uid=dana,ou=people,dc=corp,dc=example", "memberOf", ldapStaff)
+	if _, err := f.login("dana@corp.example.com", "dana-pw"); err != nil {
+		t.Fatalf("second Login: %v", err)
+	}
+	user, _ = f.stores.Users.GetUserByEmail(context.Background(), "default", "dana@corp.example.com")
+	if user.Role != "user" {
+		t.Fatalf("role after leaving the admins group = %q, want user", user.Role)
+	}
+
+	// รหัสผ่านอยู่ใน directory จึงเปลี่ยนในระบบนี้ไม่ได้
+	_, err = f.auth.ChangePassword(withToken(mustToken(t, f, "dana@corp.example.com", "dana-pw")), &pb.ChangePasswordRequest{CurrentPassword: "dana-pw", NewPassword: "NewSecret123!"})
+	wantCode(t, "ChangePassword of a directory user", err, codes.FailedPrecondition)
+}
+
+func mustToken(t *testing.T, f *directoryFixture, email string, password string) string {
+	t.Helper()
+	reply, err := f.login(email, password)
+	if err != nil {
+		t.Fatalf("Login(%s): %v", email, err)
+	}
+	return reply.GetToken()
+}
+
+func TestDirectoryLoginRejectsWrongPassword(t *testing.T) {
+	f := newDirectoryFixture(t, nil)
+
+	_, err := f.login("dana@corp.example.com", "wrong")
+	wantCode(t, "Login with a wrong directory password", err, codes.Unauthenticated)
+	_, err = f.login("erin@corp.example.com", "whatever")
+	wantCode(t, "Login of an email missing from the directory", err, codes.Unauthenticated)
+	if _, err := f.stores.Users.GetUserByEmail(context.Background(), "default", "dana@corp.example.com"); err == nil {
+		t.Fatal("a user was provisioned after a failed directory login")
+	}
+}
+
+func TestDirectoryLoginLocalFallback(t *testing.T) {
+	f := newDirectoryFixture(t, func(req *pb.CreateDirectoryRequest, _ *directorytest.Server) { req.AllowLocalFallback = true })
+	registerAndLogin(t, f.stores, f.auditLogger, "contractor@corp.example.com", "contractor")
+
+	// ผู้ใช้ที่ไม่อยู่ใน directory ใช้รหัสผ่านในระบบนี้ได้ แต่พนักงานใน directory ต้องใช้รหัสผ่านของ directory
+	if _, err := f.login("contractor@corp.example.com", testPassword); err != nil {
+		t.Fatalf("Login of a local user: %v", err)
+	}
+	if _, err := f.login("dana@corp.example.com", "dana-pw"); err != nil {
+		t.Fatalf("Login of a directory user: %v", err)
+	}
+}
+
+func TestDirectoryLoginWhileServerIsDown(t *testing.T) {
+	f := newDirectoryFixture(t, nil)
+	f.ldap.Close()
+
+	// อีเมลนอกโดเมนของ directory เข้าสู่ระบบตามปกติโดยไม่ติดต่อ directory
+	registerAndLogin(t, f.stores, f.auditLogger, "owner@example.com", "owner")
+
+	_, err := f.login("dana@corp.example.com", "dana-pw")
+	wantCode(t, "Login while the directory is down", err, codes.Unavailable)
+}
+
+func TestDirectoryLoginStartTLS(t *testing.T) {
+	f := newDirectoryFixture(t, func(req *pb.CreateDirectoryRequest, ldap *directorytest.Server) {
+		req.StartTls = true
+		req.RootCa = ldap.RootCA
+	})
+	f.ldap.RequireTLS = true
+
+	if _, err := f.login("dana@corp.example.com", "dana-pw"); err != nil {
+		t.Fatalf("Login over StartTLS: %v", err)
+	}
+}
+
+func TestDirectoryLoginRequiresTLSWhenServerDoes(t *testing.T) {
+	f := newDirectoryFixture(t, nil)
+	f.ldap.RequireTLS = true
+
+	// server ปฏิเสธรหัสผ่านบน connection ที่ไม่เข้ารหัส ซึ่งไม่ใช่รหัสผ่านผิด
+	_, err := f.login("dana@corp.example.com", "dana-pw")
+	wantCode(t, "Login without StartTLS to a server that requires it", err, codes.Unavailable)
+}
+
+func TestDirectoryLoginAfterDeleteDirectory(t *testing.T) {
+	f := newDirectoryFixture(t, nil)
+	if _, err := f.login("dana@corp.example.com", "dana-pw"); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if _, err := f.federation.DeleteDirectory(f.adminCtx, &pb.DeleteDirectoryRequest{Id: f.directoryID}); err != nil {
+		t.Fatalf("DeleteDirectory: %v", err)
+	}
+
+	// รหัสผ่านในระบบนี้ของผู้ใช้ที่สร้างจาก directory เป็นค่าสุ่ม จึงใช้รหัสผ่านของ directory ไม่ได้อีก
+	_, err := f.login("dana@corp.example.com", "dana-pw")
+	wantCode(t, "Login after the directory was deleted", err, codes.Unauthenticated)
+	if len(f.auth.directories) != 0 {
+		t.Fatalf("%d directory connectors still open after delete", len(f.auth.directories))
+	}
+}
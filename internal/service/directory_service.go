@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	pb "auth-microservice/auth-microservice/proto"
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/store"
+	"auth-microservice/internal/validation"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// attribute เริ่มต้นของผู้ใช้ใน directory (OpenLDAP) เมื่อ admin ไม่ได้ระบุ
+const (
+	defaultUsernameAttribute    = "uid"
+	defaultDisplayNameAttribute = "cn"
+)
+
+func (s *FederationService) CreateDirectory(ctx context.Context, in *pb.CreateDirectoryRequest) (*pb.Directory, error) {
+	claims, err := requireAdmin(ctx, s.Blacklist, s.Tenants)
+	if err != nil {
+		return nil, err
+	}
+
+	var domains []string
+	for _, domain := range in.GetDomains() {
+		domains = append(domains, strings.ToLower(strings.TrimPrefix(domain, "@")))
+	}
+	mappings := []models.RoleMapping{}
+	for _, m := range in.GetRoleMappings() {
+		mappings = append(mappings, models.RoleMapping{Group: strings.TrimSpace(m.GetGroup()), Role: m.GetRole()})
+	}
+	now := time.Now()
+	dir := &models.Directory{
+		TenantID:             scopeTenant(ctx, claims),
+		Name:                 in.GetName(),
+		URL:                  in.GetUrl(),
+		StartTLS:             in.GetStartTls(),
+		RootCA:               in.GetRootCa(),
+		BindDN:               in.GetBindDn(),
+		BindPassword:         in.GetBindPassword(),
+		UserDNTemplate:       in.GetUserDnTemplate(),
+		BaseDN:               in.GetBaseDn(),
+		UserFilter:           in.GetUserFilter(),
+		UsernameAttribute:    in.GetUsernameAttribute(),
+		DisplayNameAttribute: in.GetDisplayNameAttribute(),
+		GroupFilter:          in.GetGroupFilter(),
+		RoleMappings:         mappings,
+		DefaultRole:          in.GetDefaultRole(),
+		Domains:              uniqueStrings(domains),
+		AllowLocalFallback:   in.GetAllowLocalFallback(),
+		PoolSize:             int(in.GetPoolSize()),
+		CreatedAt:            now,
+		UpdatedAt:            now,
+	}
+	if dir.UsernameAttribute == "" {
+		dir.UsernameAttribute = defaultUsernameAttribute
+	}
+	if dir.DisplayNameAttribute == "" {
+		dir.DisplayNameAttribute = defaultDisplayNameAttribute
+	}
+	if dir.DefaultRole == "" {
+		dir.DefaultRole = federatedUserRole
+	}
+	if err := validation.ValidateDirectory(dir); err != nil {
+		return nil, err
+	}
+
+	err = s.Identities.CreateDirectory(ctx, dir)
+	if dup, ok := store.IsDuplicate(err); ok && dup.Field == "name" {
+		return nil, status.Error(codes.AlreadyExists, "ชื่อ directory ถูกใช้งานแล้ว")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถสร้าง directory ได้")
+	}
+
+	s.recordDirectoryEvent(ctx, "directory.created", dir, claims, map[string]interface{}{
+		"url":     dir.URL,
+		"domains": dir.Domains,
+	})
+	return toDirectoryReply(dir), nil
+}
+
+func (s *FederationService) ListDirectories(ctx context.Context, in *pb.ListDirectoriesRequest) (*pb.ListDirectoriesReply, error) {
+	claims, err := requireAdmin(ctx, s.Blacklist, s.Tenants)
+	if err != nil {
+		return nil, err
+	}
+	dirs, err := s.Identities.ListDirectories(ctx, scopeTenant(ctx, claims))
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถดึงรายการ directory ได้")
+	}
+	reply := &pb.ListDirectoriesReply{}
+	for i := range dirs {
+		reply.Directories = append(reply.Directories, toDirectoryReply(&dirs[i]))
+	}
+	return reply, nil
+}
+
+func (s *FederationService) DeleteDirectory(ctx context.Context, in *pb.DeleteDirectoryRequest) (*pb.DeleteDirectoryReply, error) {
+	claims, err := requireAdmin(ctx, s.Blacklist, s.Tenants)
+	if err != nil {
+		return nil, err
+	}
+	tenantID := scopeTenant(ctx, claims)
+	dir, err := s.Identities.GetDirectory(ctx, tenantID, in.GetId())
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, "ไม่พบ directory")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถดึงข้อมูล directory ได้")
+	}
+	err = s.Identities.DeleteDirectory(ctx, tenantID, in.GetId())
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, "ไม่พบ directory")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถลบ directory ได้")
+	}
+
+	s.recordDirectoryEvent(ctx, "directory.deleted", dir, claims, nil)
+	return &pb.DeleteDirectoryReply{
+		Message: "ลบ directory สำเร็จ",
+	}, nil
+}
+
+// บันทึกเหตุการณ์ของ directory ลง audit log
+func (s *FederationService) recordDirectoryEvent(ctx context.Context, action string, dir *models.Directory, claims map[string]interface{}, details map[string]interface{}) {
+	actor, _ := claims["email"].(string)
+	if details == nil {
+		details = map[string]interface{}{}
+	}
+	details["directoryId"] = dir.ID.Hex()
+	details["directory"] = dir.Name
+	s.Audit.Record(ctx, models.AuditEvent{
+		TenantID:   dir.TenantID,
+		Action:     action,
+		ActorEmail: actor,
+		Details:    details,
+	})
+}
+
+func toDirectoryReply(d *models.Directory) *pb.Directory {
+	reply := &pb.Directory{
+		Id:                   d.ID.Hex(),
+		Name:                 d.Name,
+		Url:                  d.URL,
+		StartTls:             d.StartTLS,
+		RootCa:               d.RootCA,
+		BindDn:               d.BindDN,
+		UserDnTemplate:       d.UserDNTemplate,
+		BaseDn:               d.BaseDN,
+		UserFilter:           d.UserFilter,
+		UsernameAttribute:    d.UsernameAttribute,
+		DisplayNameAttribute: d.DisplayNameAttribute,
+		GroupFilter:          d.GroupFilter,
+		DefaultRole:          d.DefaultRole,
+		Domains:              d.Domains,
+		AllowLocalFallback:   d.AllowLocalFallback,
+		PoolSize:             int32(d.PoolSize),
+		CreatedAt:            d.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:            d.UpdatedAt.Format(time.RFC3339),
+	}
+	for _, m := range d.RoleMappings {
+		reply.RoleMappings = append(reply.RoleMappings, &pb.RoleMapping{Group: m.Group, Role: m.Role})
+	}
+	return reply
+}
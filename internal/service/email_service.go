@@ -0,0 +1,268 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	pb "auth-microservice/auth-microservice/proto"
+	"auth-microservice/internal/auth"
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/notify"
+	"auth-microservice/internal/validation"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	emailLinkBaseURL      = "http://localhost:8080" // URL ของหน้าเว็บที่ใช้สร้างลิงก์ในอีเมล
+	emailChangeConfirmTTL = time.Hour               // อายุของ token ยืนยันอีเมลใหม่
+	emailChangeRevertTTL  = 7 * 24 * time.Hour      // อายุของลิงก์ย้อนกลับที่ส่งไปยังอีเมลเดิม
+)
+
+// ข้อมูลการเปลี่ยนอีเมลที่เก็บไว้ใน cache ระหว่างรอยืนยันหรือย้อนกลับ
+type emailChange struct {
+	TenantID string `json:"tenantId,omitempty"` // ค่าว่าง = คำขอที่สร้างก่อนมี tenant (tenant default)
+	OldEmail string `json:"oldEmail"`
+	NewEmail string `json:"newEmail"`
+}
+
+func (s *AuthService) RequestEmailChange(ctx context.Context, in *pb.RequestEmailChangeRequest) (*pb.RequestEmailChangeReply, error) {
+	// ตรวจสอบ token ของผู้ใช้ที่เรียก
+	_, claims, err := s.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	email, _ := claims["email"].(string)
+	if email == "" {
+		return nil, status.Error(codes.InvalidArgument, "ไม่สามารถระบุผู้ใช้จากโทเค็นได้")
+	}
+	if in.GetNewEmail() == email {
+		return nil, status.Error(codes.InvalidArgument, "อีเมลใหม่ต้องไม่ซ้ำกับอีเมลเดิม")
+	}
+
+	tenantID := claimsTenant(claims)
+
+	// ค้นหาผู้ใช้เพื่อยืนยันรหัสผ่าน
+	user, err := s.Users.GetUserByEmail(ctx, tenantID, email)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, "ไม่พบผู้ใช้")
+	}
+
+	isLimited, err := s.isRateLimited(ctx, tenantID, email)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถตรวจสอบ Rate Limit ได้")
+	}
+	if isLimited {
+		return nil, status.Error(codes.ResourceExhausted, "คุณพยายามบ่อยเกินไป กรุณารอ 1 นาที")
+	}
+	if err := auth.ComparePassword(user.Password, in.GetPassword()); err != nil {
+		return nil, status.Error(codes.Unauthenticated, "รหัสผ่านไม่ถูกต้อง")
+	}
+
+	// ตรวจสอบรูปแบบและความซ้ำของอีเมลใหม่
+	if err := validation.ValidateEmail(in.GetNewEmail(), ctx, s.Users, tenantID); err != nil {
+		return nil, err
+	}
+
+	// สร้าง token ยืนยันและเก็บไว้ใน cache
+	token, err := s.storeEmailChange(ctx, "email_change", emailChange{TenantID: tenantID, OldEmail: email, NewEmail: in.GetNewEmail()}, emailChangeConfirmTTL)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถสร้างคำขอเปลี่ยนอีเมลได้")
+	}
+
+	// ส่ง token ยืนยันไปยังอีเมลใหม่
+	err = s.Notifier.Send(ctx, notify.Message{
+		To:      in.GetNewEmail(),
+		Subject: "ยืนยันการเปลี่ยนอีเมล",
+		Body:    fmt.Sprintf("รหัสยืนยันของคุณคือ %s\nหรือกดลิงก์ %s/email/confirm?token=%s\nลิงก์นี้มีอายุ %s", token, emailLinkBaseURL, token, emailChangeConfirmTTL),
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถส่งอีเมลยืนยันได้")
+	}
+
+	return &pb.RequestEmailChangeReply{
+		Message: "ส่งอีเมลยืนยันไปยังอีเมลใหม่แล้ว",
+	}, nil
+}
+
+func (s *AuthService) ConfirmEmailChange(ctx context.Context, in *pb.ConfirmEmailChangeRequest) (*pb.ConfirmEmailChangeReply, error) {
+	// ต้องยืนยันจาก session ของเจ้าของบัญชีเท่านั้น
+	_, claims, err := s.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	email, _ := claims["email"].(string)
+
+	change, key, err := s.loadEmailChange(ctx, "email_change", in.GetToken())
+	if err != nil {
+		return nil, err
+	}
+	if change.OldEmail != email || change.TenantID != claimsTenant(claims) {
+		return nil, status.Error(codes.PermissionDenied, "token นี้ไม่ใช่ของผู้ใช้ปัจจุบัน")
+	}
+	tenant, err := activeTenant(ctx, s.Tenants, change.TenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	// ตรวจสอบอีกครั้ง เผื่อมีผู้ใช้อื่นใช้อีเมลนี้ระหว่างรอยืนยัน
+	if err := validation.ValidateEmail(change.NewEmail, ctx, s.Users, tenant.ID); err != nil {
+		return nil, err
+	}
+	if err := s.consumeEmailChange(ctx, key, emailChangeConfirmTTL); err != nil {
+		return nil, err
+	}
+
+	userID, role, err := s.updateUserEmail(ctx, tenant.ID, change.OldEmail, change.NewEmail)
+	if err != nil {
+		return nil, err
+	}
+	s.Audit.Record(ctx, models.AuditEvent{
+		TenantID:     tenant.ID,
+		Action:       "email.changed",
+		ActorEmail:   change.OldEmail,
+		SubjectID:    userID,
+		SubjectEmail: change.NewEmail,
+		Details:      map[string]interface{}{"oldEmail": change.OldEmail},
+	})
+
+	// ย้าย session และ key ชั่วคราวไปยังอีเมลใหม่
+	if err := s.moveEmailKeys(ctx, tenant.ID, change.OldEmail, change.NewEmail); err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถย้าย session ไปยังอีเมลใหม่ได้")
+	}
+	token, err := s.issueToken(ctx, tenant, userID, change.NewEmail, role)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "เจอข้อผิดพลาดในการสร้างโทเค็น")
+	}
+
+	// แจ้งอีเมลเดิมพร้อมลิงก์ย้อนกลับที่มีอายุจำกัด
+	revertToken, err := s.storeEmailChange(ctx, "email_revert", change, emailChangeRevertTTL)
+	if err != nil {
+		log.Printf("Could not create email revert token for %s: %v", change.OldEmail, err)
+	} else {
+		err = s.Notifier.Send(ctx, notify.Message{
+			To:      change.OldEmail,
+			Subject: "อีเมลของบัญชีถูกเปลี่ยน",
+			Body:    fmt.Sprintf("อีเมลของบัญชีถูกเปลี่ยนเป็น %s\nถ้าคุณไม่ได้ทำรายการนี้ กดลิงก์ %s/email/revert?token=%s เพื่อย้อนกลับ (มีอายุ %s)", change.NewEmail, emailLinkBaseURL, revertToken, emailChangeRevertTTL),
+		})
+		if err != nil {
+			log.Printf("Could not notify old email %s: %v", change.OldEmail, err)
+		}
+	}
+
+	return &pb.ConfirmEmailChangeReply{
+		Message: "เปลี่ยนอีเมลสำเร็จ",
+		Email:   change.NewEmail,
+		Token:   token,
+	}, nil
+}
+
+func (s *AuthService) RevertEmailChange(ctx context.Context, in *pb.RevertEmailChangeRequest) (*pb.RevertEmailChangeReply, error) {
+	change, key, err := s.loadEmailChange(ctx, "email_revert", in.GetToken())
+	if err != nil {
+		return nil, err
+	}
+
+	// อีเมลเดิมอาจถูกคนอื่นใน tenant สมัครไปแล้วระหว่างนี้
+	if err := validation.ValidateEmail(change.OldEmail, ctx, s.Users, change.TenantID); err != nil {
+		return nil, err
+	}
+	if err := s.consumeEmailChange(ctx, key, emailChangeRevertTTL); err != nil {
+		return nil, err
+	}
+
+	userID, _, err := s.updateUserEmail(ctx, change.TenantID, change.NewEmail, change.OldEmail)
+	if err != nil {
+		return nil, err
+	}
+	s.Audit.Record(ctx, models.AuditEvent{
+		TenantID:     change.TenantID,
+		Action:       "email.reverted",
+		ActorEmail:   change.OldEmail,
+		SubjectID:    userID,
+		SubjectEmail: change.OldEmail,
+		Details:      map[string]interface{}{"revertedEmail": change.NewEmail},
+	})
+
+	// session ที่ใช้อีเมลใหม่อาจเป็นของผู้ไม่หวังดี จึงยกเลิกทั้งหมด
+	if err := s.moveEmailKeys(ctx, change.TenantID, change.NewEmail, change.OldEmail); err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถยกเลิก session ของอีเมลใหม่ได้")
+	}
+
+	return &pb.RevertEmailChangeReply{
+		Message: "ย้อนกลับอีเมลสำเร็จ กรุณาเข้าสู่ระบบใหม่และเปลี่ยนรหัสผ่าน",
+		Email:   change.OldEmail,
+	}, nil
+}
+
+// เก็บข้อมูลการเปลี่ยนอีเมลไว้ใน cache ภายใต้ "<prefix>:<hash ของ token>" แล้วคืน token
+func (s *AuthService) storeEmailChange(ctx context.Context, prefix string, change emailChange, ttl time.Duration) (string, error) {
+	token, err := generateRandomToken(32)
+	if err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(change)
+	if err != nil {
+		return "", err
+	}
+	if err := s.Cache.Set(ctx, prefix+":"+hashAPIKey(token), string(data), ttl); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// อ่านข้อมูลการเปลี่ยนอีเมลจาก cache ตาม hash ของ token แล้วคืน key ที่เก็บไว้ (ใช้กับ consumeEmailChange)
+func (s *AuthService) loadEmailChange(ctx context.Context, prefix string, token string) (emailChange, string, error) {
+	var change emailChange
+	if token == "" {
+		return change, "", status.Error(codes.InvalidArgument, "ต้องระบุ token")
+	}
+	key := prefix + ":" + hashAPIKey(token)
+	data, err := s.Cache.Get(ctx, key)
+	if err != nil {
+		return change, "", status.Error(codes.NotFound, "token ไม่ถูกต้องหรือหมดอายุแล้ว")
+	}
+	if err := json.Unmarshal([]byte(data), &change); err != nil {
+		return change, "", status.Error(codes.Internal, "ข้อมูลการเปลี่ยนอีเมลไม่ถูกต้อง")
+	}
+	if change.TenantID == "" {
+		change.TenantID = models.DefaultTenantID
+	}
+	return change, key, nil
+}
+
+// ใช้ token การเปลี่ยนอีเมลได้ครั้งเดียว แม้มี request ที่ใช้ token เดียวกันพร้อมกัน
+func (s *AuthService) consumeEmailChange(ctx context.Context, key string, ttl time.Duration) error {
+	if ok, err := consumeOnce(ctx, s.Cache, key+":used", ttl); err != nil || !ok {
+		return status.Error(codes.NotFound, "token ไม่ถูกต้องหรือหมดอายุแล้ว")
+	}
+	s.Cache.Delete(ctx, key)
+	return nil
+}
+
+// เปลี่ยนอีเมลของผู้ใช้แล้วคืน ID และ role ของผู้ใช้
+// ผู้ใช้พิสูจน์แล้วว่าเป็นเจ้าของอีเมลปลายทาง (ผ่าน token ที่ส่งไปยังอีเมลนั้น) จึงถือว่ายืนยันอีเมลแล้ว
+func (s *AuthService) updateUserEmail(ctx context.Context, tenantID string, from string, to string) (string, string, error) {
+	user, err := s.Users.UpdateEmail(ctx, tenantID, from, to, time.Now())
+	if dupErr := duplicateKeyError(err); dupErr != nil {
+		return "", "", dupErr
+	}
+	if err != nil {
+		return "", "", status.Error(codes.NotFound, "ไม่พบผู้ใช้ที่ต้องการเปลี่ยนอีเมล")
+	}
+	return user.ID.Hex(), user.Role, nil
+}
+
+// ย้าย key ชั่วคราวที่อิงกับอีเมลไปยังอีเมลใหม่ใน tenant เดียวกัน
+// active token เดิมมี email อยู่ใน claims จึงต้องยกเลิกแทนการย้าย
+func (s *AuthService) moveEmailKeys(ctx context.Context, tenantID string, from string, to string) error {
+	if err := s.revokeActiveToken(ctx, tenantID, from); err != nil {
+		return err
+	}
+
+	return s.Cache.Rename(ctx, loginAttemptKey(tenantID, from), loginAttemptKey(tenantID, to))
+}
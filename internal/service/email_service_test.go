@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"testing"
+	"time"
+
+	pb "auth-microservice/auth-microservice/proto"
+	"auth-microservice/internal/audit"
+	models "auth-microservice/internal/model"
+
+	"google.golang.org/grpc/codes"
+)
+
+var emailChangeTokenPattern = regexp.MustCompile(`รหัสยืนยันของคุณคือ (\S+)`)
+
+func TestEmailChangeTokenIsStoredHashed(t *testing.T) {
+	ctx := context.Background()
+	stores := newTestStores(t)
+	auditLogger := audit.NewLogger(stores.Audit)
+	notifier := &recordingNotifier{}
+	service := NewAuthService(stores, notifier, auditLogger)
+	alice := registerAndLogin(t, stores, auditLogger, "alice@example.com", "alice")
+
+	if _, err := service.RequestEmailChange(alice, &pb.RequestEmailChangeRequest{NewEmail: "alice.new@example.com", Password: testPassword}); err != nil {
+		t.Fatalf("RequestEmailChange: %v", err)
+	}
+	match := emailChangeTokenPattern.FindStringSubmatch(notifier.last(t, "alice.new@example.com").Body)
+	if match == nil {
+		t.Fatal("confirmation email has no token")
+	}
+	token := match[1]
+	if _, err := stores.Cache.Get(ctx, "email_change:"+token); err == nil {
+		t.Fatal("email change token is stored in plaintext")
+	}
+	if _, err := stores.Cache.Get(ctx, "email_change:"+hashAPIKey(token)); err != nil {
+		t.Fatalf("email change is not stored under the token hash: %v", err)
+	}
+
+	// รายการที่เก็บภายใต้ token ตรง ๆ (รูปแบบเดิม) ใช้ยืนยันไม่ได้
+	legacy, _ := json.Marshal(emailChange{TenantID: models.DefaultTenantID, OldEmail: "alice@example.com", NewEmail: "alice.legacy@example.com"})
+	if err := stores.Cache.Set(ctx, "email_change:legacy-token", string(legacy), time.Hour); err != nil {
+		t.Fatalf("Cache.Set: %v", err)
+	}
+	_, err := service.ConfirmEmailChange(alice, &pb.ConfirmEmailChangeRequest{Token: "legacy-token"})
+	wantCode(t, "ConfirmEmailChange with a plaintext cache entry", err, codes.NotFound)
+
+	if _, err := service.ConfirmEmailChange(alice, &pb.ConfirmEmailChangeRequest{Token: token}); err != nil {
+		t.Fatalf("ConfirmEmailChange: %v", err)
+	}
+	if _, err := stores.Users.GetUserByEmail(ctx, models.DefaultTenantID, "alice.new@example.com"); err != nil {
+		t.Fatalf("email was not changed: %v", err)
+	}
+}
@@ -0,0 +1,26 @@
+package service
+
+import (
+	"auth-microservice/internal/store"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// แปลงข้อมูลซ้ำจาก unique constraint เป็น AlreadyExists (คืน nil ถ้าไม่ใช่ error ประเภทนี้)
+// เกิดได้เมื่อมี request พร้อมกันผ่าน ValidateEmail/ValidateUsername ไปได้ทั้งคู่
+func duplicateKeyError(err error) error {
+	dup, ok := store.IsDuplicate(err)
+	if !ok {
+		return nil
+	}
+	switch dup.Field {
+	case "email":
+		return status.Error(codes.AlreadyExists, "อีเมลถูกใช้งานแล้ว")
+	case "username":
+		return status.Error(codes.AlreadyExists, "ชื่อผู้ใช้ถูกใช้งานแล้ว")
+	case "id":
+		return status.Error(codes.AlreadyExists, "ID นี้ถูกใช้งานแล้ว")
+	}
+	return status.Error(codes.AlreadyExists, "ข้อมูลซ้ำกับที่มีอยู่แล้ว")
+}
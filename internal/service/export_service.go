@@ -0,0 +1,337 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	pb "auth-microservice/auth-microservice/proto"
+	"auth-microservice/internal/auth"
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/search"
+	"auth-microservice/internal/store"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	exportChunkSize    = 64 * 1024 // ขนาดข้อมูลสูงสุดต่อ chunk ที่ส่งกลับใน stream
+	exportUsersPerPage = 500       // จำนวนผู้ใช้ต่อ chunk ของ ExportUsers
+)
+
+// คอลัมน์ของไฟล์ที่ ExportUsers ส่งออก (ตามด้วย "metadata.<key>" ของทุก key ใน schema)
+var exportUserColumns = []string{"id", "tenantId", "email", "username", "role", "displayName", "avatarUrl",
+	"locale", "timezone", "phone", "emailVerified", "createdAt", "updatedAt", "deleted"}
+
+// field ใน user document ที่เป็นความลับ ห้ามส่งออกไปนอก service
+var sensitiveUserFields = []string{"password", "passwordHistory"}
+
+func (s *UserService) ExportMyData(in *pb.ExportMyDataRequest, stream grpc.ServerStreamingServer[pb.DataExportChunk]) error {
+	ctx := stream.Context()
+
+	// ตรวจสอบ token ของผู้ใช้ที่เรียก
+	_, claims, err := authenticate(ctx, s.Blacklist, s.Tenants)
+	if err != nil {
+		return err
+	}
+	email, _ := claims["email"].(string)
+
+	user, err := s.Users.GetUserByEmail(ctx, claimsTenant(claims), email)
+	if err != nil {
+		return status.Error(codes.NotFound, "ไม่พบผู้ใช้")
+	}
+
+	s.Audit.Record(ctx, models.AuditEvent{
+		TenantID:     user.TenantID,
+		Action:       "user.data_exported",
+		ActorEmail:   email,
+		SubjectID:    user.ID.Hex(),
+		SubjectEmail: email,
+	})
+	return s.streamUserExport(ctx, user, stream)
+}
+
+func (s *UserService) ExportUserData(in *pb.ExportUserDataRequest, stream grpc.ServerStreamingServer[pb.DataExportChunk]) error {
+	ctx := stream.Context()
+
+	// ตรวจสอบสิทธิ์ admin
+	claims, err := requireAdmin(ctx, s.Blacklist, s.Tenants)
+	if err != nil {
+		return err
+	}
+
+	objID, err := primitive.ObjectIDFromHex(in.GetId())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, "ID ไม่ถูกต้อง")
+	}
+
+	// admin ส่งออกได้แม้ผู้ใช้ถูก soft delete ไปแล้ว (เฉพาะผู้ใช้ใน tenant ที่ดูแล)
+	user, err := s.tenantUser(ctx, scopeTenant(ctx, claims), objID.Hex(), true)
+	if err != nil {
+		return err
+	}
+
+	adminEmail, _ := claims["email"].(string)
+	s.Audit.Record(ctx, models.AuditEvent{
+		TenantID:     user.TenantID,
+		Action:       "user.data_exported",
+		ActorEmail:   adminEmail,
+		SubjectID:    objID.Hex(),
+		SubjectEmail: user.Email,
+	})
+	return s.streamUserExport(ctx, user, stream)
+}
+
+// สร้างไฟล์ zip ของข้อมูลผู้ใช้แล้วส่งกลับทีละ chunk
+func (s *UserService) streamUserExport(ctx context.Context, user *models.User, stream grpc.ServerStreamingServer[pb.DataExportChunk]) error {
+	archive, err := s.buildUserExport(ctx, user)
+	if err != nil {
+		return status.Error(codes.Internal, "ไม่สามารถสร้างไฟล์ส่งออกข้อมูลได้")
+	}
+
+	filename := fmt.Sprintf("user-data-%s-%s.zip", user.ID.Hex(), time.Now().Format("20060102150405"))
+	for offset := 0; offset < len(archive); offset += exportChunkSize {
+		end := offset + exportChunkSize
+		if end > len(archive) {
+			end = len(archive)
+		}
+		chunk := &pb.DataExportChunk{Data: archive[offset:end]}
+		if offset == 0 {
+			chunk.Filename = filename
+		}
+		if err := stream.Send(chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// รวบรวมข้อมูลของผู้ใช้เป็นไฟล์ JSON หลายไฟล์ภายใน zip
+func (s *UserService) buildUserExport(ctx context.Context, user *models.User) ([]byte, error) {
+	// แปลงเป็น document เพื่อให้ได้ชื่อ field เดียวกันทุก backend แล้วตัด hash รหัสผ่านหรือข้อมูลลับออก
+	data, err := bson.Marshal(user)
+	if err != nil {
+		return nil, err
+	}
+	var profile bson.M
+	if err := bson.Unmarshal(data, &profile); err != nil {
+		return nil, err
+	}
+	for _, field := range sensitiveUserFields {
+		delete(profile, field)
+	}
+
+	// แยกประวัติการเข้าสู่ระบบออกจากเหตุการณ์อื่น ๆ
+	events, err := s.Audit.FindBySubject(ctx, user.TenantID, user.ID.Hex(), user.Email)
+	if err != nil {
+		return nil, err
+	}
+	loginHistory := []models.AuditEvent{}
+	auditEvents := []models.AuditEvent{}
+	for _, ev := range events {
+		if ev.Action == "user.login" || ev.Action == "user.login_failed" {
+			loginHistory = append(loginHistory, ev)
+		} else {
+			auditEvents = append(auditEvents, ev)
+		}
+	}
+
+	files := []struct {
+		name string
+		data interface{}
+	}{
+		{"profile.json", profile},
+		{"sessions.json", bson.M{"sessions": s.exportSessions(ctx, user.TenantID, user.Email)}},
+		{"login_history.json", bson.M{"events": loginHistory}},
+		{"audit_events.json", bson.M{"events": auditEvents}},
+		{"mfa.json", bson.M{"enrolled": false, "methods": bson.A{}}},
+		{"consents.json", bson.M{"consents": bson.A{}}},
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, f := range files {
+		data, err := bson.MarshalExtJSONIndent(f.data, false, false, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		w, err := zw.Create(f.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(data); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ข้อมูล session ที่ใช้งานอยู่ของผู้ใช้ (ไม่รวมตัว token)
+func (s *UserService) exportSessions(ctx context.Context, tenantID string, email string) bson.A {
+	sessions := bson.A{}
+	token, err := s.Sessions.GetActiveToken(ctx, tenantID, email)
+	if err != nil || token == "" {
+		return sessions
+	}
+	session := bson.M{"active": true}
+	if exp, err := auth.GetTokenExpiration(token); err == nil {
+		session["expiresAt"] = exp
+	}
+	return append(sessions, session)
+}
+
+func (s *UserService) ExportUsers(in *pb.ExportUsersRequest, stream grpc.ServerStreamingServer[pb.ExportUsersChunk]) error {
+	ctx := stream.Context()
+
+	// ตรวจสอบสิทธิ์ admin
+	claims, err := requireAdmin(ctx, s.Blacklist, s.Tenants)
+	if err != nil {
+		return err
+	}
+	format := in.GetFormat()
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "jsonl" {
+		return status.Error(codes.InvalidArgument, "format ต้องเป็น csv หรือ jsonl")
+	}
+	deleted, err := search.ParseDeletedFilter(in.GetDeleted())
+	if err != nil {
+		return err
+	}
+	filter := search.UserQuery{TenantID: scopeTenant(ctx, claims), Role: in.GetRole(), Deleted: deleted}
+
+	// dry run: นับจำนวนผู้ใช้ที่จะส่งออกอย่างเดียว
+	if in.GetDryRun() {
+		total, _, err := s.Users.CountUsers(ctx, filter, false)
+		if err != nil {
+			return status.Error(codes.Internal, "ไม่สามารถนับผู้ใช้ทั้งหมดได้")
+		}
+		return stream.Send(&pb.ExportUsersChunk{Total: int32(total)})
+	}
+
+	// เรียงตามเวลาสร้างแล้วส่งออกทีละหน้า resumeToken คือ cursor ของผู้ใช้คนสุดท้ายใน chunk
+	order, err := parseOrderBy("")
+	if err != nil {
+		return err
+	}
+	query := store.UserListQuery{Filter: filter, Order: order, Limit: exportUsersPerPage + 1}
+	if in.GetResumeToken() != "" {
+		if query.After, err = decodePageToken(in.GetResumeToken(), order); err != nil {
+			return err
+		}
+	}
+	schema, err := s.loadProfileSchema(ctx)
+	if err != nil {
+		return status.Error(codes.Internal, "ไม่สามารถโหลด schema ของโปรไฟล์ได้")
+	}
+
+	adminEmail, _ := claims["email"].(string)
+	s.Audit.Record(ctx, models.AuditEvent{
+		TenantID:   filter.TenantID,
+		Action:     "users.exported",
+		ActorEmail: adminEmail,
+		Details:    map[string]interface{}{"format": format, "resumed": in.GetResumeToken() != ""},
+	})
+
+	header := in.GetResumeToken() == ""
+	for {
+		page, err := s.Users.ListUsers(ctx, query)
+		if err != nil {
+			return status.Error(codes.Internal, "เกิดข้อผิดพลาดในการค้นหา")
+		}
+		chunk := &pb.ExportUsersChunk{}
+		if len(page) > exportUsersPerPage {
+			page = page[:exportUsersPerPage]
+			chunk.ResumeToken = encodePageToken(order, page[len(page)-1])
+		}
+		if format == "csv" {
+			chunk.Data, err = encodeUsersCSV(page, schema, header)
+		} else {
+			chunk.Data, err = encodeUsersJSONL(page)
+		}
+		if err != nil {
+			return status.Error(codes.Internal, "ไม่สามารถสร้างไฟล์ส่งออกได้")
+		}
+		chunk.Rows = int32(len(page))
+		if err := stream.Send(chunk); err != nil {
+			return err
+		}
+		if chunk.ResumeToken == "" {
+			return nil
+		}
+		header = false
+		query.After, err = decodePageToken(chunk.ResumeToken, order)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// แปลงผู้ใช้เป็นแถว CSV (ไม่มี hash รหัสผ่าน) metadata แยกคอลัมน์ตาม key ใน schema
+func encodeUsersCSV(users []models.User, schema []models.ProfileAttribute, header bool) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if header {
+		columns := append([]string{}, exportUserColumns...)
+		for _, attr := range schema {
+			columns = append(columns, "metadata."+attr.Key)
+		}
+		if err := w.Write(columns); err != nil {
+			return nil, err
+		}
+	}
+	for _, u := range users {
+		record := []string{u.ID.Hex(), u.TenantID, u.Email, u.Username, u.Role, u.DisplayName, u.AvatarURL,
+			u.Locale, u.Timezone, u.Phone, strconv.FormatBool(u.EmailVerified),
+			u.CreatedAt.UTC().Format(time.RFC3339), u.UpdatedAt.UTC().Format(time.RFC3339), strconv.FormatBool(u.Deleted)}
+		for _, attr := range schema {
+			record = append(record, u.Metadata[attr.Key])
+		}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// แปลงผู้ใช้เป็น JSON หนึ่งบรรทัดต่อคน (field เดียวกับ UserItem ไม่มี hash รหัสผ่าน)
+func encodeUsersJSONL(users []models.User) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, u := range users {
+		if err := enc.Encode(map[string]interface{}{
+			"id":            u.ID.Hex(),
+			"tenantId":      u.TenantID,
+			"email":         u.Email,
+			"username":      u.Username,
+			"role":          u.Role,
+			"displayName":   u.DisplayName,
+			"avatarUrl":     u.AvatarURL,
+			"locale":        u.Locale,
+			"timezone":      u.Timezone,
+			"phone":         u.Phone,
+			"emailVerified": u.EmailVerified,
+			"metadata":      u.Metadata,
+			"createdAt":     u.CreatedAt.UTC().Format(time.RFC3339),
+			"updatedAt":     u.UpdatedAt.UTC().Format(time.RFC3339),
+			"deleted":       u.Deleted,
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	pb "auth-microservice/auth-microservice/proto"
+	"auth-microservice/internal/audit"
+	"auth-microservice/internal/events"
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/notify"
+
+	"google.golang.org/grpc/codes"
+)
+
+// รวม chunk ของ ExportMyData/ExportUserData แล้วคืนไฟล์ JSON ใน zip ตามชื่อไฟล์
+func readDataExport(t *testing.T, stream *testServerStream[pb.DataExportChunk]) map[string]map[string]interface{} {
+	t.Helper()
+	chunks := stream.drain()
+	if len(chunks) == 0 || !strings.HasPrefix(chunks[0].GetFilename(), "user-data-") || !strings.HasSuffix(chunks[0].GetFilename(), ".zip") {
+		t.Fatalf("export chunks = %v, want a zip filename in the first chunk", chunks)
+	}
+	var archive []byte
+	for _, chunk := range chunks {
+		archive = append(archive, chunk.GetData()...)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		t.Fatalf("open zip: %v", err)
+	}
+	files := map[string]map[string]interface{}{}
+	for _, f := range zr.File {
+		r, err := f.Open()
+		if err != nil {
+			t.Fatalf("open %s: %v", f.Name, err)
+		}
+		data, err := io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			t.Fatalf("read %s: %v", f.Name, err)
+		}
+		var doc map[string]interface{}
+		if err := json.Unmarshal(data, &doc); err != nil {
+			t.Fatalf("%s is not JSON: %v", f.Name, err)
+		}
+		files[f.Name] = doc
+	}
+	return files
+}
+
+// action ของเหตุการณ์ใน {"events": [...]}
+func exportedActions(doc map[string]interface{}) []string {
+	var actions []string
+	list, _ := doc["events"].([]interface{})
+	for _, ev := range list {
+		if m, ok := ev.(map[string]interface{}); ok {
+			action, _ := m["action"].(string)
+			actions = append(actions, action)
+		}
+	}
+	return actions
+}
+
+func TestExportMyDataContents(t *testing.T) {
+	ctx := context.Background()
+	stores := newTestStores(t)
+	auditLogger := audit.NewLogger(stores.Audit)
+	service := NewUserService(stores, events.NewMemoryBus(), auditLogger)
+	authService := NewAuthService(stores, notify.NewLogNotifier(), auditLogger)
+
+	userCtx := registerAndLogin(t, stores, auditLogger, "alice@example.com", "alice")
+	if _, err := authService.Login(ctx, &pb.LoginRequest{Email: "alice@example.com", Password: "Wrong123!"}); err == nil {
+		t.Fatal("Login with a wrong password succeeded")
+	}
+	reply, err := authService.ChangePassword(userCtx, &pb.ChangePasswordRequest{CurrentPassword: testPassword, NewPassword: "Secret124!"})
+	if err != nil {
+		t.Fatalf("ChangePassword: %v", err)
+	}
+	userCtx = withToken(reply.GetToken())
+
+	stream := newTestServerStream[pb.DataExportChunk](userCtx)
+	if err := service.ExportMyData(&pb.ExportMyDataRequest{}, stream); err != nil {
+		t.Fatalf("ExportMyData: %v", err)
+	}
+	files := readDataExport(t, stream)
+
+	contains := func(list []string, want string) bool {
+		for _, v := range list {
+			if v == want {
+				return true
+			}
+		}
+		return false
+	}
+	for _, tc := range []struct {
+		file  string
+		check func(doc map[string]interface{}) bool
+	}{
+		{"profile.json", func(doc map[string]interface{}) bool {
+			_, hasPassword := doc["password"]
+			_, hasHistory := doc["passwordHistory"]
+			return doc["email"] == "alice@example.com" && doc["username"] == "alice" && !hasPassword && !hasHistory
+		}},
+		{"sessions.json", func(doc map[string]interface{}) bool {
+			sessions, _ := doc["sessions"].([]interface{})
+			return len(sessions) == 1
+		}},
+		{"login_history.json", func(doc map[string]interface{}) bool {
+			actions := exportedActions(doc)
+			return contains(actions, "user.login") && contains(actions, "user.login_failed") && !contains(actions, "password.changed")
+		}},
+		{"audit_events.json", func(doc map[string]interface{}) bool {
+			actions := exportedActions(doc)
+			return contains(actions, "user.registered") && contains(actions, "password.changed") && !contains(actions, "user.login")
+		}},
+		{"mfa.json", func(doc map[string]interface{}) bool { return doc["enrolled"] == false }},
+		{"consents.json", func(doc map[string]interface{}) bool { _, ok := doc["consents"]; return ok }},
+	} {
+		doc, ok := files[tc.file]
+		if !ok {
+			t.Errorf("export has no %s", tc.file)
+			continue
+		}
+		if !tc.check(doc) {
+			t.Errorf("%s = %v", tc.file, doc)
+		}
+	}
+	if len(files) != 6 {
+		t.Errorf("export has %d files, want 6", len(files))
+	}
+	// hash รหัสผ่านไม่อยู่ในไฟล์ใดเลย
+	user, _ := stores.Users.GetUserByEmail(ctx, models.DefaultTenantID, "alice@example.com")
+	for name, doc := range files {
+		data, _ := json.Marshal(doc)
+		if bytes.Contains(data, []byte(user.Password)) || bytes.Contains(data, []byte(user.PasswordHistory[0])) {
+			t.Errorf("%s contains a password hash", name)
+		}
+	}
+}
+
+func TestExportUserDataByAdmin(t *testing.T) {
+	ctx := context.Background()
+	stores := newTestStores(t)
+	auditLogger := audit.NewLogger(stores.Audit)
+	service := NewUserService(stores, events.NewMemoryBus(), auditLogger)
+	adminCtx := adminContext(t, stores, auditLogger)
+	registerAndLogin(t, stores, auditLogger, "alice@example.com", "alice")
+	bob := registerAndLogin(t, stores, auditLogger, "bob@example.com", "bob")
+	alice, _ := stores.Users.GetUserByEmail(ctx, models.DefaultTenantID, "alice@example.com")
+
+	_, err := service.DeleteUser(adminCtx, &pb.DeleteUserRequest{Id: alice.ID.Hex()})
+	if err != nil {
+		t.Fatalf("DeleteUser: %v", err)
+	}
+
+	for _, tc := range []struct {
+		name string
+		ctx  context.Context
+		id   string
+		want codes.Code
+	}{
+		{"soft deleted user", adminCtx, alice.ID.Hex(), codes.OK},
+		{"invalid ID", adminCtx, "alice", codes.InvalidArgument},
+		{"unknown user", adminCtx, "000000000000000000000000", codes.NotFound},
+		{"non-admin", bob, alice.ID.Hex(), codes.PermissionDenied},
+	} {
+		stream := newTestServerStream[pb.DataExportChunk](tc.ctx)
+		err := service.ExportUserData(&pb.ExportUserDataRequest{Id: tc.id}, stream)
+		wantCode(t, "ExportUserData of "+tc.name, err, tc.want)
+		if tc.want != codes.OK {
+			continue
+		}
+		profile := readDataExport(t, stream)["profile.json"]
+		if profile["email"] != "alice@example.com" || profile["deleted"] != true {
+			t.Fatalf("profile of a deleted user = %v", profile)
+		}
+	}
+}
@@ -0,0 +1,270 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	pb "auth-microservice/auth-microservice/proto"
+	"auth-microservice/internal/federation"
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/store"
+	"auth-microservice/internal/validation"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	samlLoginTTL      = time.Minute     // เวลาที่แอปมีเพื่อนำ state และ code ไปเรียก CompleteFederatedLogin หลังตรวจสอบ assertion
+	samlReplayMargin  = 2 * time.Minute // เก็บ ID ของ assertion ที่ใช้แล้วเลยเวลาหมดอายุไปอีกเล็กน้อย (ครอบคลุม clock skew)
+	samlRequestPrefix = "id-"           // ID ของ AuthnRequest ต้องไม่ขึ้นต้นด้วยตัวเลข
+)
+
+// ผลการตรวจสอบ assertion ที่รอแอปนำ code มาแลก (ผูกกับ state ที่ได้พร้อมกัน)
+type samlLogin struct {
+	StateHash string              `json:"stateHash"`
+	Identity  federation.Identity `json:"identity"`
+}
+
+// การตั้งค่าของ SAML IdP จาก request (ค่าที่ระบุใน saml มาก่อนค่าใน metadata)
+func samlSettingsFromRequest(in *pb.CreateIdentityProviderRequest, tenantID string, allowLocal bool) (*models.SAMLSettings, error) {
+	if err := validation.ValidateRedirectURIs([]string{in.GetRedirectUri()}); err != nil {
+		return nil, err
+	}
+	cfg := in.GetSaml()
+	settings := &models.SAMLSettings{
+		EntityID:          cfg.GetEntityId(),
+		SSOURL:            cfg.GetSsoUrl(),
+		Certificates:      cfg.GetCertificates(),
+		EmailAttribute:    cfg.GetEmailAttribute(),
+		NameAttribute:     cfg.GetNameAttribute(),
+		UsernameAttribute: cfg.GetUsernameAttribute(),
+		GroupsAttribute:   cfg.GetGroupsAttribute(),
+		DefaultRole:       cfg.GetDefaultRole(),
+		AllowIdPInitiated: cfg.GetAllowIdpInitiated(),
+	}
+	for _, mapping := range cfg.GetRoleMappings() {
+		settings.RoleMappings = append(settings.RoleMappings, models.RoleMapping{Group: mapping.GetGroup(), Role: mapping.GetRole()})
+	}
+	if in.GetSamlMetadata() != "" {
+		meta, err := federation.ParseSAMLIdPMetadata([]byte(in.GetSamlMetadata()))
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "metadata ของ IdP ไม่ถูกต้อง")
+		}
+		if settings.EntityID == "" {
+			settings.EntityID = meta.EntityID
+		}
+		if settings.SSOURL == "" {
+			settings.SSOURL = meta.SSOURL
+		}
+		if len(settings.Certificates) == 0 {
+			settings.Certificates = meta.Certificates
+		}
+	}
+	if len(settings.RoleMappings) > 0 && settings.DefaultRole == "" {
+		settings.DefaultRole = federatedUserRole
+	}
+	if err := validation.ValidateSAMLSettings(settings, tenantID, allowLocal); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// SP metadata ของ provider สำหรับนำเข้าที่ IdP (GET /saml/{tenant}/{provider}/metadata)
+func (s *FederationService) SAMLMetadata(ctx context.Context, tenantID string, name string) ([]byte, error) {
+	provider, err := s.samlIdentityProvider(ctx, tenantID, name)
+	if err != nil {
+		return nil, err
+	}
+	sp, err := s.samlServiceProvider(ctx, provider)
+	if err != nil {
+		return nil, err
+	}
+	metadata, err := sp.Metadata()
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถสร้าง metadata ได้")
+	}
+	return metadata, nil
+}
+
+// ตรวจสอบ SAMLResponse ที่ IdP POST มาที่ ACS (POST /saml/{tenant}/{provider}/acs)
+// แล้วคืน URL ที่ส่งผู้ใช้กลับไปยังแอป (redirect URI ของ provider พร้อม state และ code สำหรับ CompleteFederatedLogin)
+// RelayState ที่ไม่ใช่ state ของระบบนี้ถือเป็น IdP-initiated และไม่ถูกใช้เป็น URL ปลายทาง
+func (s *FederationService) ConsumeSAMLResponse(ctx context.Context, tenantID string, name string, samlResponse string, relayState string) (string, error) {
+	provider, err := s.samlIdentityProvider(ctx, tenantID, name)
+	if err != nil {
+		return "", err
+	}
+	if samlResponse == "" {
+		return "", status.Error(codes.InvalidArgument, "ต้องระบุ SAMLResponse")
+	}
+
+	stateID, requestID := relayState, ""
+	var state federationState
+	if relayState != "" && getJSON(ctx, s.Cache, "federation_state:"+hashAPIKey(relayState), &state) == nil &&
+		state.ProviderID == provider.ID.Hex() {
+		requestID = samlRequestPrefix + state.Nonce
+	} else {
+		if !provider.SAML.AllowIdPInitiated {
+			return "", status.Error(codes.FailedPrecondition, "state ไม่ถูกต้องหรือหมดอายุ")
+		}
+		if stateID, err = generateRandomToken(32); err != nil {
+			return "", status.Error(codes.Internal, "ไม่สามารถสร้าง state ได้")
+		}
+		state = federationState{TenantID: provider.TenantID, ProviderID: provider.ID.Hex()}
+		if err := setJSON(ctx, s.Cache, "federation_state:"+hashAPIKey(stateID), state, federationStateTTL); err != nil {
+			return "", status.Error(codes.Internal, "ไม่สามารถบันทึก state ได้")
+		}
+	}
+
+	sp, err := s.samlServiceProvider(ctx, provider)
+	if err != nil {
+		return "", err
+	}
+	assertion, err := sp.ParseResponse(samlResponse, requestID)
+	if errors.Is(err, federation.ErrInvalidGrant) {
+		return "", status.Error(codes.Unauthenticated, "identity provider ยืนยันตัวตนไม่สำเร็จ")
+	}
+	if err != nil {
+		return "", status.Error(codes.Internal, "ไม่สามารถตรวจสอบ SAMLResponse ได้")
+	}
+
+	// assertion แต่ละตัวใช้ได้ครั้งเดียวจนกว่าจะหมดอายุ
+	ttl := time.Until(assertion.ExpiresAt) + samlReplayMargin
+	if ok, err := consumeOnce(ctx, s.Cache, "saml_assertion_used:"+hashAPIKey(provider.SAML.EntityID+"\n"+assertion.ID), ttl); err != nil || !ok {
+		return "", status.Error(codes.Unauthenticated, "assertion นี้ถูกใช้ไปแล้ว")
+	}
+
+	code, err := generateRandomToken(32)
+	if err != nil {
+		return "", status.Error(codes.Internal, "ไม่สามารถสร้าง code ได้")
+	}
+	login := samlLogin{StateHash: hashAPIKey(stateID), Identity: assertion.Identity}
+	if err := setJSON(ctx, s.Cache, "saml_login:"+hashAPIKey(code), login, samlLoginTTL); err != nil {
+		return "", status.Error(codes.Internal, "ไม่สามารถบันทึกผลการเข้าสู่ระบบได้")
+	}
+
+	redirect, err := url.Parse(provider.RedirectURI)
+	if err != nil {
+		return "", status.Error(codes.Internal, "redirect URI ของ provider ไม่ถูกต้อง")
+	}
+	query := redirect.Query()
+	query.Set("state", stateID)
+	query.Set("code", code)
+	redirect.RawQuery = query.Encode()
+	return redirect.String(), nil
+}
+
+// ข้อมูลผู้ใช้ของ code ที่ได้จาก ACS (ใช้ได้ครั้งเดียวและต้องมาพร้อม state เดียวกัน)
+func (s *FederationService) samlIdentity(ctx context.Context, stateID string, code string) (*federation.Identity, error) {
+	key := "saml_login:" + hashAPIKey(code)
+	var login samlLogin
+	if getJSON(ctx, s.Cache, key, &login) != nil {
+		return nil, status.Error(codes.Unauthenticated, "identity provider ยืนยันตัวตนไม่สำเร็จ")
+	}
+	s.Cache.Delete(ctx, key)
+	if login.StateHash != hashAPIKey(stateID) {
+		return nil, status.Error(codes.Unauthenticated, "identity provider ยืนยันตัวตนไม่สำเร็จ")
+	}
+	return &login.Identity, nil
+}
+
+// SAML provider ตามชื่อใน tenant ที่ยังใช้งานได้
+func (s *FederationService) samlIdentityProvider(ctx context.Context, tenantID string, name string) (*models.IdentityProvider, error) {
+	if _, err := activeTenant(ctx, s.Tenants, tenantID); err != nil {
+		return nil, err
+	}
+	provider, err := s.Identities.GetIdentityProviderByName(ctx, tenantID, name)
+	if errors.Is(err, store.ErrNotFound) || (err == nil && provider.SAML == nil) {
+		return nil, status.Error(codes.NotFound, "ไม่พบ SAML provider")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถดึงข้อมูล provider ได้")
+	}
+	return provider, nil
+}
+
+// SAMLServiceProvider ของ provider: เซ็น AuthnRequest ด้วย signing key ปัจจุบันของ ID token
+func (s *FederationService) samlServiceProvider(ctx context.Context, provider *models.IdentityProvider) (*federation.SAMLServiceProvider, error) {
+	keys, err := s.samlKeys.load(ctx, s.Settings)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถโหลด signing key ได้")
+	}
+	certificate, err := federation.SAMLCertificate(keys[0].Key, s.Issuer)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถสร้าง certificate ได้")
+	}
+	idpCertificates, err := federation.ParseSAMLCertificates(provider.SAML.Certificates)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "certificate ของ IdP ไม่ถูกต้อง")
+	}
+	return federation.NewSAMLServiceProvider(federation.SAMLConfig{
+		EntityID:          samlEndpoint(s.Issuer, provider, "metadata"),
+		ACSURL:            samlEndpoint(s.Issuer, provider, "acs"),
+		Key:               keys[0].Key,
+		Certificate:       certificate,
+		IdPEntityID:       provider.SAML.EntityID,
+		IdPSSOURL:         provider.SAML.SSOURL,
+		IdPCertificates:   idpCertificates,
+		EmailAttribute:    provider.SAML.EmailAttribute,
+		NameAttribute:     provider.SAML.NameAttribute,
+		UsernameAttribute: provider.SAML.UsernameAttribute,
+		GroupsAttribute:   provider.SAML.GroupsAttribute,
+	}), nil
+}
+
+// URL ของ endpoint SAML ของ provider (entity ID ของระบบนี้คือ URL ของ metadata)
+func samlEndpoint(issuer string, provider *models.IdentityProvider, endpoint string) string {
+	return issuer + "/saml/" + url.PathEscape(provider.TenantID) + "/" + provider.Name + "/" + endpoint
+}
+
+// role ตาม mapping แรกที่ตรงกับกลุ่มใน assertion (ไม่ตรงเลย = DefaultRole)
+// ok = false เมื่อ provider ไม่ได้กำหนด role จาก assertion
+func samlRole(provider *models.IdentityProvider, groups []string) (string, bool) {
+	if provider.SAML == nil || len(provider.SAML.RoleMappings) == 0 {
+		return "", false
+	}
+	for _, mapping := range provider.SAML.RoleMappings {
+		for _, group := range groups {
+			if strings.EqualFold(mapping.Group, group) {
+				return mapping.Role, true
+			}
+		}
+	}
+	return provider.SAML.DefaultRole, true
+}
+
+// ปรับ role ของผู้ใช้ตามกลุ่มใน assertion ทุกครั้งที่เข้าสู่ระบบ
+func (s *FederationService) syncSAMLRole(ctx context.Context, user *models.User, provider *models.IdentityProvider, identity *federation.Identity) error {
+	role, ok := samlRole(provider, identity.Groups)
+	if !ok || user.Role == role {
+		return nil
+	}
+	if err := s.Users.UpdateProfile(ctx, user.ID.Hex(), store.ProfilePatch{Role: &role}, time.Now()); err != nil {
+		return status.Error(codes.Internal, "ไม่สามารถปรับ role ของผู้ใช้ได้")
+	}
+	s.recordIdentityEvent(ctx, "user.role_changed", user, map[string]interface{}{"provider": provider.Name, "from": user.Role, "to": role})
+	user.Role = role
+	return nil
+}
+
+func toSAMLSettingsReply(s *models.SAMLSettings) *pb.SAMLSettings {
+	reply := &pb.SAMLSettings{
+		EntityId:          s.EntityID,
+		SsoUrl:            s.SSOURL,
+		Certificates:      s.Certificates,
+		EmailAttribute:    s.EmailAttribute,
+		NameAttribute:     s.NameAttribute,
+		UsernameAttribute: s.UsernameAttribute,
+		GroupsAttribute:   s.GroupsAttribute,
+		DefaultRole:       s.DefaultRole,
+		AllowIdpInitiated: s.AllowIdPInitiated,
+	}
+	for _, mapping := range s.RoleMappings {
+		reply.RoleMappings = append(reply.RoleMappings, &pb.RoleMapping{Group: mapping.Group, Role: mapping.Role})
+	}
+	return reply
+}
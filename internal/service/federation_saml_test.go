@@ -0,0 +1,256 @@
+package service
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"net/url"
+	"strings"
+	"testing"
+
+	pb "auth-microservice/auth-microservice/proto"
+	"auth-microservice/internal/audit"
+	"auth-microservice/internal/federation/federationtest"
+	"auth-microservice/internal/store"
+
+	"github.com/beevik/etree"
+	"google.golang.org/grpc/codes"
+)
+
+// FederationService กับ SAML provider ชื่อ "corp" ที่นำเข้าจาก metadata ของ IdP ปลอม
+type samlFixture struct {
+	stores  *store.Stores
+	service *FederationService
+	idp     *federationtest.SAMLIdP
+}
+
+func newSAMLFixture(t *testing.T, allowIdPInitiated bool) *samlFixture {
+	t.Helper()
+	stores := newTestStores(t)
+	auditLogger := audit.NewLogger(stores.Audit)
+	f := &samlFixture{
+		stores:  stores,
+		service: NewFederationService(stores, "http://localhost:8080", true, auditLogger),
+		idp:     federationtest.NewSAMLIdP(t, "https://idp.corp.example.com", "http://127.0.0.1:9999/sso"),
+	}
+	_, err := f.service.CreateIdentityProvider(adminContext(t, stores, auditLogger), &pb.CreateIdentityProviderRequest{
+		Name:         "corp",
+		Protocol:     "saml",
+		RedirectUri:  "http://localhost:8080/federation/callback",
+		AllowSignup:  true,
+		SamlMetadata: f.idp.Metadata(),
+		Saml: &pb.SAMLSettings{
+			RoleMappings:      []*pb.RoleMapping{{Group: "Admins", Role: "admin"}},
+			AllowIdpInitiated: allowIdPInitiated,
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateIdentityProvider: %v", err)
+	}
+	return f
+}
+
+// certificate ที่ใช้เซ็น AuthnRequest จาก SP metadata ของ provider
+func (f *samlFixture) spCertificate(t *testing.T) *x509.Certificate {
+	t.Helper()
+	metadata, err := f.service.SAMLMetadata(context.Background(), "default", "corp")
+	if err != nil {
+		t.Fatalf("SAMLMetadata: %v", err)
+	}
+	doc := etree.NewDocument()
+	if err := doc.ReadFromBytes(metadata); err != nil {
+		t.Fatalf("SP metadata: %v", err)
+	}
+	el := doc.FindElement("//X509Certificate")
+	if el == nil {
+		t.Fatalf("SP metadata has no certificate: %s", metadata)
+	}
+	der, err := base64.StdEncoding.DecodeString(strings.TrimSpace(el.Text()))
+	if err != nil {
+		t.Fatalf("SP certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("SP certificate: %v", err)
+	}
+	return cert
+}
+
+// เริ่มเข้าสู่ระบบจากแอป แล้วคืน AuthnRequest ที่ IdP ได้รับ
+func (f *samlFixture) start(t *testing.T) federationtest.SAMLAuthnRequest {
+	t.Helper()
+	start, err := f.service.StartFederatedLogin(context.Background(), &pb.StartFederatedLoginRequest{Provider: "corp"})
+	if err != nil {
+		t.Fatalf("StartFederatedLogin: %v", err)
+	}
+	req := f.idp.ParseAuthnRequest(t, start.GetAuthorizationUrl(), f.spCertificate(t))
+	if req.RelayState != start.GetState() || req.ACSURL != "http://localhost:8080/saml/default/corp/acs" ||
+		req.Issuer != "http://localhost:8080/saml/default/corp/metadata" {
+		t.Fatalf("AuthnRequest = %+v", req)
+	}
+	return req
+}
+
+// assertion ของผู้ใช้ที่ตอบ AuthnRequest (req.ID ว่าง = IdP-initiated)
+func samlAssertionFor(req federationtest.SAMLAuthnRequest, subject string, email string, groups ...string) federationtest.SAMLAssertion {
+	return federationtest.SAMLAssertion{
+		NameID:       subject,
+		Audience:     "http://localhost:8080/saml/default/corp/metadata",
+		Recipient:    "http://localhost:8080/saml/default/corp/acs",
+		Destination:  "http://localhost:8080/saml/default/corp/acs",
+		InResponseTo: req.ID,
+		Attributes:   map[string][]string{"email": {email}, "groups": groups},
+	}
+}
+
+// ส่ง Response ไปที่ ACS แล้วแลก state และ code ที่ได้กับ token
+func (f *samlFixture) complete(t *testing.T, samlResponse string, relayState string) (*pb.CompleteFederatedLoginReply, string) {
+	t.Helper()
+	redirect, err := f.service.ConsumeSAMLResponse(context.Background(), "default", "corp", samlResponse, relayState)
+	if err != nil {
+		t.Fatalf("ConsumeSAMLResponse: %v", err)
+	}
+	u, err := url.Parse(redirect)
+	if err != nil || !strings.HasPrefix(redirect, "http://localhost:8080/federation/callback?") {
+		t.Fatalf("ConsumeSAMLResponse redirect = %s", redirect)
+	}
+	state := u.Query().Get("state")
+	reply, err := f.service.CompleteFederatedLogin(context.Background(), &pb.CompleteFederatedLoginRequest{State: state, Code: u.Query().Get("code")})
+	if err != nil {
+		t.Fatalf("CompleteFederatedLogin: %v", err)
+	}
+	return reply, state
+}
+
+func (f *samlFixture) role(t *testing.T, email string) string {
+	t.Helper()
+	user, err := f.stores.Users.GetUserByEmail(context.Background(), "default", email)
+	if err != nil {
+		t.Fatalf("GetUserByEmail(%s): %v", email, err)
+	}
+	return user.Role
+}
+
+func TestSAMLLoginSPInitiated(t *testing.T) {
+	f := newSAMLFixture(t, false)
+	req := f.start(t)
+
+	response := f.idp.Response(t, samlAssertionFor(req, "idp-1", "kate@corp.example.com", "Engineering", "Admins"), federationtest.SignAssertion, nil)
+	reply, state := f.complete(t, response, req.RelayState)
+	if state != req.RelayState || !reply.GetCreated() || reply.GetToken() == "" || reply.GetEmail() != "kate@corp.example.com" {
+		t.Fatalf("CompleteFederatedLogin = %+v (state %q), want a new user", reply, state)
+	}
+	if role := f.role(t, "kate@corp.example.com"); role != "admin" {
+		t.Fatalf("role = %q, want admin from the Admins group", role)
+	}
+
+	// state ถูกใช้ไปแล้ว จึงส่ง Response เดิมซ้ำไม่ได้
+	_, err := f.service.ConsumeSAMLResponse(context.Background(), "default", "corp", response, req.RelayState)
+	wantCode(t, "ConsumeSAMLResponse with a used state", err, codes.FailedPrecondition)
+
+	// role ถูกปรับตามกลุ่มทุกครั้งที่เข้าสู่ระบบ
+	req = f.start(t)
+	f.complete(t, f.idp.Response(t, samlAssertionFor(req, "idp-1", "kate@corp.example.com", "Engineering"), federationtest.SignResponse, nil), req.RelayState)
+	if role := f.role(t, "kate@corp.example.com"); role != "user" {
+		t.Fatalf("role after leaving Admins = %q, want user", role)
+	}
+}
+
+func TestSAMLLoginRejectsResponseForAnotherRequest(t *testing.T) {
+	f := newSAMLFixture(t, false)
+	first := f.start(t)
+	second := f.start(t)
+
+	// Response ที่ตอบ AuthnRequest แรกใช้กับ state ของ AuthnRequest ที่สองไม่ได้
+	response := f.idp.Response(t, samlAssertionFor(first, "idp-2", "liam@corp.example.com"), federationtest.SignAssertion, nil)
+	_, err := f.service.ConsumeSAMLResponse(context.Background(), "default", "corp", response, second.RelayState)
+	wantCode(t, "ConsumeSAMLResponse with the state of another request", err, codes.Unauthenticated)
+
+	// Response ที่ไม่ได้เซ็นด้วย key ของ IdP ใช้ไม่ได้
+	other := federationtest.NewSAMLIdP(t, f.idp.EntityID, f.idp.SSOURL)
+	response = other.Response(t, samlAssertionFor(first, "idp-2", "liam@corp.example.com"), federationtest.SignAssertion, nil)
+	_, err = f.service.ConsumeSAMLResponse(context.Background(), "default", "corp", response, first.RelayState)
+	wantCode(t, "ConsumeSAMLResponse signed by another IdP", err, codes.Unauthenticated)
+}
+
+func TestSAMLLoginCodeIsBoundToState(t *testing.T) {
+	f := newSAMLFixture(t, false)
+	req := f.start(t)
+	other := f.start(t)
+
+	redirect, err := f.service.ConsumeSAMLResponse(context.Background(), "default", "corp",
+		f.idp.Response(t, samlAssertionFor(req, "idp-3", "mia@corp.example.com"), federationtest.SignAssertion, nil), req.RelayState)
+	if err != nil {
+		t.Fatalf("ConsumeSAMLResponse: %v", err)
+	}
+	u, _ := url.Parse(redirect)
+	_, err = f.service.CompleteFederatedLogin(context.Background(), &pb.CompleteFederatedLoginRequest{State: other.RelayState, Code: u.Query().Get("code")})
+	wantCode(t, "CompleteFederatedLogin with the code of another login", err, codes.Unauthenticated)
+}
+
+func TestSAMLLoginIdPInitiated(t *testing.T) {
+	idpInitiated := federationtest.SAMLAuthnRequest{}
+
+	f := newSAMLFixture(t, false)
+	response := f.idp.Response(t, samlAssertionFor(idpInitiated, "idp-4", "noah@corp.example.com"), federationtest.SignAssertion, nil)
+	_, err := f.service.ConsumeSAMLResponse(context.Background(), "default", "corp", response, "https://evil.example.com/")
+	wantCode(t, "IdP-initiated login to a provider that does not allow it", err, codes.FailedPrecondition)
+
+	f = newSAMLFixture(t, true)
+	response = f.idp.Response(t, samlAssertionFor(idpInitiated, "idp-4", "noah@corp.example.com"), federationtest.SignAssertion, nil)
+	// RelayState ที่ไม่ใช่ state ของระบบนี้ไม่ถูกใช้เป็นปลายทาง
+	reply, state := f.complete(t, response, "https://evil.example.com/")
+	if state == "" || state == "https://evil.example.com/" || reply.GetEmail() != "noah@corp.example.com" {
+		t.Fatalf("IdP-initiated login = %+v (state %q)", reply, state)
+	}
+
+	// assertion แต่ละตัวใช้ได้ครั้งเดียว แม้ไม่มี state ของ AuthnRequest
+	_, err = f.service.ConsumeSAMLResponse(context.Background(), "default", "corp", response, "")
+	wantCode(t, "ConsumeSAMLResponse of a used assertion", err, codes.Unauthenticated)
+}
+
+func TestSAMLMetadataOfUnknownProvider(t *testing.T) {
+	f := newSAMLFixture(t, false)
+	_, err := f.service.SAMLMetadata(context.Background(), "default", "other")
+	wantCode(t, "SAMLMetadata of an unknown provider", err, codes.NotFound)
+	_, err = f.service.ConsumeSAMLResponse(context.Background(), "default", "corp", "", "")
+	wantCode(t, "ConsumeSAMLResponse without a response", err, codes.InvalidArgument)
+}
+
+func TestCreateSAMLProviderRejectsLocalSSOURL(t *testing.T) {
+	stores := newTestStores(t)
+	auditLogger := audit.NewLogger(stores.Audit)
+	service := NewFederationService(stores, "https://auth.example.com", false, auditLogger)
+	ctx := adminContext(t, stores, auditLogger)
+	idp := federationtest.NewSAMLIdP(t, "https://idp.corp.example.com", "https://idp.corp.example.com/sso")
+	for _, ssoURL := range []string{
+		"http://localhost:9999/sso",
+		"https://127.0.0.1:9999/sso",
+		"https://sso.localhost/sso",
+		"https://192.168.1.10/sso",
+		"http://idp.corp.example.com/sso",
+	} {
+		_, err := service.CreateIdentityProvider(ctx, &pb.CreateIdentityProviderRequest{
+			Name:        "corp",
+			Protocol:    "saml",
+			RedirectUri: "https://app.example.com/federation/callback",
+			Saml: &pb.SAMLSettings{
+				EntityId:     idp.EntityID,
+				SsoUrl:       ssoURL,
+				Certificates: []string{idp.CertificatePEM()},
+			},
+		})
+		wantCode(t, "CreateIdentityProvider with SSO URL "+ssoURL, err, codes.InvalidArgument)
+	}
+
+	// SSO URL สาธารณะแบบ https ใช้ได้
+	_, err := service.CreateIdentityProvider(ctx, &pb.CreateIdentityProviderRequest{
+		Name:         "corp",
+		Protocol:     "saml",
+		RedirectUri:  "https://app.example.com/federation/callback",
+		SamlMetadata: idp.Metadata(),
+	})
+	if err != nil {
+		t.Fatalf("CreateIdentityProvider with a public SSO URL: %v", err)
+	}
+}
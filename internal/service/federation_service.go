@@ -0,0 +1,667 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	pb "auth-microservice/auth-microservice/proto"
+	"auth-microservice/internal/auth"
+	"auth-microservice/internal/federation"
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/store"
+	"auth-microservice/internal/validation"
+
+	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	federationStateTTL = 10 * time.Minute // เวลาที่ผู้ใช้มีเพื่อเข้าสู่ระบบกับ provider
+	federatedUserRole  = "user"           // role ของผู้ใช้ที่สร้างจากการเข้าสู่ระบบผ่าน provider
+)
+
+// scope ที่ขอจาก provider เมื่อ admin ไม่ได้ระบุ
+var defaultFederationScopes = []string{models.ScopeOpenID, models.ScopeEmail, models.ScopeProfile}
+
+// อักขระที่ใช้ใน username ไม่ได้ (ValidateUsername รับเฉพาะตัวอักษรและตัวเลข)
+var usernameInvalidChars = regexp.MustCompile(`[^a-zA-Z0-9]+`)
+
+// ข้อมูลของการเข้าสู่ระบบที่เริ่มไว้ เก็บใน cache ตาม state จนกว่าจะถูกใช้
+type federationState struct {
+	TenantID   string `json:"tenantId"`
+	ProviderID string `json:"providerId"`
+	Nonce      string `json:"nonce"`
+	Verifier   string `json:"verifier"` // PKCE code verifier
+}
+
+// connector ของ provider ที่สร้างไว้แล้ว (สร้างใหม่เมื่อ provider ถูกแก้ไข)
+type cachedConnector struct {
+	updatedAt time.Time
+	connector federation.Connector
+}
+
+func (s *FederationService) CreateIdentityProvider(ctx context.Context, in *pb.CreateIdentityProviderRequest) (*pb.IdentityProvider, error) {
+	claims, err := requireAdmin(ctx, s.Blacklist, s.Tenants)
+	if err != nil {
+		return nil, err
+	}
+	if err := validation.ValidateIdentityProviderName(in.GetName()); err != nil {
+		return nil, err
+	}
+	if err := validation.ValidateIdentityProviderDisplayName(in.GetDisplayName()); err != nil {
+		return nil, err
+	}
+	tenantID := scopeTenant(ctx, claims)
+	provider := &models.IdentityProvider{
+		TenantID:    tenantID,
+		Name:        in.GetName(),
+		RedirectURI: in.GetRedirectUri(),
+		AllowSignup: in.GetAllowSignup(),
+	}
+	switch in.GetProtocol() {
+	case "", models.ProtocolOIDC:
+		provider.Protocol = models.ProtocolOIDC
+		provider.Issuer = strings.TrimSuffix(in.GetIssuer(), "/")
+		if err := validation.ValidateIdentityProviderURLs(provider.Issuer, in.GetRedirectUri(), s.AllowLocal); err != nil {
+			return nil, err
+		}
+		if err := validation.ValidateIdentityProviderClient(in.GetClientId(), in.GetClientSecret()); err != nil {
+			return nil, err
+		}
+		provider.ClientID, provider.ClientSecret = in.GetClientId(), in.GetClientSecret()
+		provider.Scopes = uniqueStrings(in.GetScopes())
+		if len(provider.Scopes) == 0 {
+			provider.Scopes = defaultFederationScopes
+		}
+		if err := validation.ValidateFederationScopes(provider.Scopes); err != nil {
+			return nil, err
+		}
+	case models.ProtocolSAML:
+		provider.Protocol = models.ProtocolSAML
+		if provider.SAML, err = samlSettingsFromRequest(in, tenantID, s.AllowLocal); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, status.Error(codes.InvalidArgument, "protocol ต้องเป็น oidc หรือ saml")
+	}
+	var domains []string
+	for _, domain := range in.GetAllowedDomains() {
+		domains = append(domains, strings.ToLower(strings.TrimPrefix(domain, "@")))
+	}
+	domains = uniqueStrings(domains)
+	if err := validation.ValidateEmailDomains(domains); err != nil {
+		return nil, err
+	}
+
+	provider.DisplayName = in.GetDisplayName()
+	if provider.DisplayName == "" {
+		provider.DisplayName = in.GetName()
+	}
+	provider.AllowedDomains = domains
+	provider.CreatedAt = time.Now()
+	provider.UpdatedAt = provider.CreatedAt
+	err = s.Identities.CreateIdentityProvider(ctx, provider)
+	if dup, ok := store.IsDuplicate(err); ok && dup.Field == "name" {
+		return nil, status.Error(codes.AlreadyExists, "ชื่อ provider ถูกใช้งานแล้ว")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถสร้าง provider ได้")
+	}
+
+	issuer := provider.Issuer
+	if provider.SAML != nil {
+		issuer = provider.SAML.EntityID
+	}
+	s.recordProviderEvent(ctx, "identity_provider.created", provider, claims, map[string]interface{}{
+		"protocol":    provider.Protocol,
+		"issuer":      issuer,
+		"allowSignup": provider.AllowSignup,
+	})
+	return toIdentityProviderReply(provider, s.Issuer), nil
+}
+
+func (s *FederationService) ListIdentityProviders(ctx context.Context, in *pb.ListIdentityProvidersRequest) (*pb.ListIdentityProvidersReply, error) {
+	claims, err := requireAdmin(ctx, s.Blacklist, s.Tenants)
+	if err != nil {
+		return nil, err
+	}
+	providers, err := s.Identities.ListIdentityProviders(ctx, scopeTenant(ctx, claims))
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถดึงรายการ provider ได้")
+	}
+	reply := &pb.ListIdentityProvidersReply{}
+	for i := range providers {
+		reply.Providers = append(reply.Providers, toIdentityProviderReply(&providers[i], s.Issuer))
+	}
+	return reply, nil
+}
+
+func (s *FederationService) DeleteIdentityProvider(ctx context.Context, in *pb.DeleteIdentityProviderRequest) (*pb.DeleteIdentityProviderReply, error) {
+	claims, err := requireAdmin(ctx, s.Blacklist, s.Tenants)
+	if err != nil {
+		return nil, err
+	}
+	provider, err := s.findProvider(ctx, scopeTenant(ctx, claims), in.GetId())
+	if err != nil {
+		return nil, err
+	}
+	err = s.Identities.DeleteIdentityProvider(ctx, provider.TenantID, in.GetId())
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, "ไม่พบ provider")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถลบ provider ได้")
+	}
+
+	s.connectorsMu.Lock()
+	delete(s.connectors, provider.ID.Hex())
+	s.connectorsMu.Unlock()
+
+	s.recordProviderEvent(ctx, "identity_provider.deleted", provider, claims, nil)
+	return &pb.DeleteIdentityProviderReply{
+		Message: "ลบ provider สำเร็จ",
+	}, nil
+}
+
+func (s *FederationService) StartFederatedLogin(ctx context.Context, in *pb.StartFederatedLoginRequest) (*pb.StartFederatedLoginReply, error) {
+	tenant, err := requestTenant(ctx, s.Tenants)
+	if err != nil {
+		return nil, err
+	}
+	provider, err := s.Identities.GetIdentityProviderByName(ctx, tenant.ID, in.GetProvider())
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, "ไม่พบ provider")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถดึงข้อมูล provider ได้")
+	}
+
+	stateID, err1 := generateRandomToken(32)
+	nonce, err2 := generateRandomToken(16)
+	verifier, err3 := generateRandomToken(32)
+	if err1 != nil || err2 != nil || err3 != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถสร้าง state ได้")
+	}
+	var authURL string
+	if provider.SAML != nil {
+		sp, err := s.samlServiceProvider(ctx, provider)
+		if err != nil {
+			return nil, err
+		}
+		if authURL, err = sp.AuthnRequestURL(samlRequestPrefix+nonce, stateID); err != nil {
+			return nil, status.Error(codes.Internal, "ไม่สามารถสร้าง AuthnRequest ได้")
+		}
+	} else if authURL, err = s.connector(provider).AuthCodeURL(ctx, stateID, nonce, auth.PKCEChallenge(verifier)); err != nil {
+		return nil, status.Error(codes.Unavailable, "ไม่สามารถเชื่อมต่อ identity provider ได้")
+	}
+
+	state := federationState{TenantID: tenant.ID, ProviderID: provider.ID.Hex(), Nonce: nonce, Verifier: verifier}
+	if err := setJSON(ctx, s.Cache, "federation_state:"+hashAPIKey(stateID), state, federationStateTTL); err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถบันทึก state ได้")
+	}
+	return &pb.StartFederatedLoginReply{
+		AuthorizationUrl: authURL,
+		State:            stateID,
+	}, nil
+}
+
+func (s *FederationService) CompleteFederatedLogin(ctx context.Context, in *pb.CompleteFederatedLoginRequest) (*pb.CompleteFederatedLoginReply, error) {
+	state, provider, identity, err := s.exchange(ctx, in.GetState(), in.GetCode())
+	if err != nil {
+		return nil, err
+	}
+	tenant, err := activeTenant(ctx, s.Tenants, state.TenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	user, created, err := s.federatedUser(ctx, provider, identity)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		if err := s.syncSAMLRole(ctx, user, provider, identity); err != nil {
+			return nil, err
+		}
+	}
+
+	// ผู้ใช้ที่บังคับใช้ passkey ต้องยืนยันด้วย passkey ก่อนได้ token เหมือนการเข้าสู่ระบบด้วยรหัสผ่าน
+	if user.PasskeyRequired {
+		pending, err := beginPasskeySecondFactor(ctx, s.Cache, user, false)
+		if err != nil {
+			return nil, err
+		}
+		s.Identities.TouchLinkedIdentity(ctx, provider.ID.Hex(), identity.Subject, time.Now())
+		return &pb.CompleteFederatedLoginReply{
+			Email:                user.Email,
+			Username:             user.Username,
+			Created:              created,
+			SecondFactorRequired: true,
+			SecondFactorToken:    pending.GetSecondFactorToken(),
+		}, nil
+	}
+
+	if err := revokeActiveToken(ctx, s.Sessions, s.Blacklist, tenant.ID, user.Email); err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถเพิ่ม token เข้า blacklisted ได้")
+	}
+	token, err := issueToken(ctx, s.Sessions, s.Groups, tenant, user.ID.Hex(), user.Email, user.Role)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "เจอข้อผิดพลาดในการสร้างโทเค็น")
+	}
+	s.Identities.TouchLinkedIdentity(ctx, provider.ID.Hex(), identity.Subject, time.Now())
+
+	details := requestDetails(ctx)
+	details["provider"] = provider.Name
+	s.recordIdentityEvent(ctx, "user.login", user, details)
+	return &pb.CompleteFederatedLoginReply{
+		Email:    user.Email,
+		Username: user.Username,
+		Token:    token,
+		Created:  created,
+	}, nil
+}
+
+func (s *FederationService) LinkIdentity(ctx context.Context, in *pb.LinkIdentityRequest) (*pb.LinkedIdentity, error) {
+	user, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	state, provider, identity, err := s.exchange(ctx, in.GetState(), in.GetCode())
+	if err != nil {
+		return nil, err
+	}
+	if state.TenantID != user.TenantID {
+		return nil, status.Error(codes.PermissionDenied, "provider นี้ไม่ได้อยู่ใน tenant ของผู้ใช้")
+	}
+
+	link, err := s.linkIdentity(ctx, user, provider, identity)
+	if err != nil {
+		return nil, err
+	}
+	return toLinkedIdentityReply(link, provider.Name), nil
+}
+
+func (s *FederationService) UnlinkIdentity(ctx context.Context, in *pb.UnlinkIdentityRequest) (*pb.UnlinkIdentityReply, error) {
+	user, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	provider, err := s.Identities.GetIdentityProviderByName(ctx, user.TenantID, in.GetProvider())
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, "ไม่พบ provider")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถดึงข้อมูล provider ได้")
+	}
+	err = s.Identities.DeleteLinkedIdentity(ctx, user.TenantID, user.ID.Hex(), provider.ID.Hex())
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, "ยังไม่ได้ผูกบัญชีกับ provider นี้")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถยกเลิกการผูกบัญชีได้")
+	}
+
+	s.recordIdentityEvent(ctx, "identity.unlinked", user, map[string]interface{}{"provider": provider.Name})
+	return &pb.UnlinkIdentityReply{
+		Message: "ยกเลิกการผูกบัญชีสำเร็จ",
+	}, nil
+}
+
+func (s *FederationService) ListLinkedIdentities(ctx context.Context, in *pb.ListLinkedIdentitiesRequest) (*pb.ListLinkedIdentitiesReply, error) {
+	user, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	links, err := s.Identities.ListLinkedIdentities(ctx, user.TenantID, user.ID.Hex())
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถดึงรายการบัญชีที่ผูกไว้ได้")
+	}
+	providers, err := s.Identities.ListIdentityProviders(ctx, user.TenantID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถดึงรายการ provider ได้")
+	}
+	names := map[string]string{}
+	for _, p := range providers {
+		names[p.ID.Hex()] = p.Name
+	}
+
+	reply := &pb.ListLinkedIdentitiesReply{}
+	for i := range links {
+		reply.Identities = append(reply.Identities, toLinkedIdentityReply(&links[i], names[links[i].ProviderID.Hex()]))
+	}
+	return reply, nil
+}
+
+// ใช้ state (ได้ครั้งเดียว) แล้วแลก code กับ provider เป็นข้อมูลผู้ใช้ที่ผ่านการตรวจสอบแล้ว
+// (provider แบบ SAML: code มาจาก ACS ซึ่งตรวจสอบ assertion ไว้แล้ว)
+func (s *FederationService) exchange(ctx context.Context, stateID string, code string) (*federationState, *models.IdentityProvider, *federation.Identity, error) {
+	if stateID == "" || code == "" {
+		return nil, nil, nil, status.Error(codes.InvalidArgument, "ต้องระบุ state และ code")
+	}
+	key := "federation_state:" + hashAPIKey(stateID)
+	var state federationState
+	if getJSON(ctx, s.Cache, key, &state) != nil {
+		return nil, nil, nil, status.Error(codes.FailedPrecondition, "state ไม่ถูกต้องหรือหมดอายุ")
+	}
+	if ok, err := consumeOnce(ctx, s.Cache, "federation_state_used:"+hashAPIKey(stateID), federationStateTTL); err != nil || !ok {
+		return nil, nil, nil, status.Error(codes.FailedPrecondition, "state ไม่ถูกต้องหรือหมดอายุ")
+	}
+	s.Cache.Delete(ctx, key)
+
+	provider, err := s.findProvider(ctx, state.TenantID, state.ProviderID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if provider.SAML != nil {
+		identity, err := s.samlIdentity(ctx, stateID, code)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		return &state, provider, identity, nil
+	}
+	identity, err := s.connector(provider).Exchange(ctx, code, state.Verifier, state.Nonce)
+	if errors.Is(err, federation.ErrInvalidGrant) {
+		return nil, nil, nil, status.Error(codes.Unauthenticated, "identity provider ยืนยันตัวตนไม่สำเร็จ")
+	}
+	if err != nil {
+		return nil, nil, nil, status.Error(codes.Unavailable, "ไม่สามารถเชื่อมต่อ identity provider ได้")
+	}
+	return &state, provider, identity, nil
+}
+
+// หาผู้ใช้ของบัญชีภายนอก: บัญชีที่ผูกไว้แล้ว > ผู้ใช้ที่มีอีเมล (ยืนยันแล้ว) ตรงกัน > สร้างผู้ใช้ใหม่ถ้า provider อนุญาต
+func (s *FederationService) federatedUser(ctx context.Context, provider *models.IdentityProvider, identity *federation.Identity) (*models.User, bool, error) {
+	link, err := s.Identities.GetLinkedIdentity(ctx, provider.ID.Hex(), identity.Subject)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		return nil, false, status.Error(codes.Internal, "ไม่สามารถดึงข้อมูลบัญชีที่ผูกไว้ได้")
+	}
+	if err == nil {
+		user, err := s.Users.GetUserByID(ctx, link.UserID.Hex(), true)
+		switch {
+		case err == nil && user.Deleted:
+			return nil, false, status.Error(codes.PermissionDenied, "บัญชีผู้ใช้ถูกลบแล้ว")
+		case err == nil:
+			return user, false, nil
+		case errors.Is(err, store.ErrNotFound):
+			// ผู้ใช้ถูกลบถาวรไปแล้ว ลบการผูกบัญชีที่ค้างอยู่แล้วหาผู้ใช้ด้วยอีเมลต่อ
+			s.Identities.DeleteLinkedIdentity(ctx, link.TenantID, link.UserID.Hex(), provider.ID.Hex())
+		default:
+			return nil, false, status.Error(codes.Internal, "เกิดข้อผิดพลาดในการค้นหาผู้ใช้")
+		}
+	}
+
+	// ผูกบัญชีด้วยอีเมลได้เฉพาะอีเมลที่ provider ยืนยันแล้ว
+	if identity.Email == "" || !identity.EmailVerified {
+		return nil, false, status.Error(codes.PermissionDenied, "identity provider ไม่ได้ยืนยันอีเมลของบัญชีนี้")
+	}
+	user, err := s.Users.GetUserByEmail(ctx, provider.TenantID, identity.Email)
+	if err == nil {
+		if _, err := s.linkIdentity(ctx, user, provider, identity); err != nil {
+			return nil, false, err
+		}
+		return user, false, nil
+	}
+	if !errors.Is(err, store.ErrNotFound) {
+		return nil, false, status.Error(codes.Internal, "เกิดข้อผิดพลาดในการค้นหาผู้ใช้")
+	}
+
+	if !provider.AllowSignup || !emailDomainAllowed(identity.Email, provider.AllowedDomains) {
+		return nil, false, status.Error(codes.PermissionDenied, "ไม่พบผู้ใช้ที่ใช้อีเมลนี้ กรุณาติดต่อผู้ดูแลระบบ")
+	}
+	user, err = s.createFederatedUser(ctx, provider, identity)
+	if err != nil {
+		return nil, false, err
+	}
+	if _, err := s.linkIdentity(ctx, user, provider, identity); err != nil {
+		return nil, false, err
+	}
+	return user, true, nil
+}
+
+// สร้างผู้ใช้ใหม่จากข้อมูลของ provider (รหัสผ่านเป็นค่าสุ่ม ผู้ใช้ตั้งรหัสผ่านเองได้ด้วยการรีเซ็ตรหัสผ่าน)
+func (s *FederationService) createFederatedUser(ctx context.Context, provider *models.IdentityProvider, identity *federation.Identity) (*models.User, error) {
+	if err := validation.ValidateEmailFormat(identity.Email); err != nil {
+		return nil, err
+	}
+	username, err := availableUsername(ctx, s.Users, provider.TenantID, identity.PreferredUsername, identity.Email)
+	if err != nil {
+		return nil, err
+	}
+	password, err := generateRandomToken(32)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถสร้างรหัสผ่านได้")
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถสร้างรหัสผ่านได้")
+	}
+	role, ok := samlRole(provider, identity.Groups)
+	if !ok {
+		role = federatedUserRole
+	}
+
+	now := time.Now()
+	user := &models.User{
+		TenantID:          provider.TenantID,
+		Email:             identity.Email,
+		Username:          username,
+		Password:          string(hashedPassword),
+		PasswordHistory:   []string{},
+		PasswordChangedAt: &now,
+		Role:              role,
+		EmailVerified:     true,
+		DisplayName:       identity.Name,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+	err = s.Users.CreateUser(ctx, user)
+	if dupErr := duplicateKeyError(err); dupErr != nil {
+		return nil, dupErr
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถสร้างผู้ใช้ได้")
+	}
+
+	s.recordIdentityEvent(ctx, "user.registered", user, map[string]interface{}{"provider": provider.Name})
+	return user, nil
+}
+
+// username สำหรับผู้ใช้ที่สร้างจากบัญชีภายนอก: จากชื่อที่บัญชีภายนอกใช้ หรือส่วนหน้าของอีเมล
+// (ตัดอักขระที่ใช้ไม่ได้ และเติมตัวเลขถ้าซ้ำ)
+func availableUsername(ctx context.Context, users store.UserStore, tenantID string, preferred string, email string) (string, error) {
+	base := usernameInvalidChars.ReplaceAllString(preferred, "")
+	if len(base) < 3 {
+		local, _, _ := strings.Cut(email, "@")
+		base = usernameInvalidChars.ReplaceAllString(local, "")
+	}
+	if len(base) < 3 {
+		base = "user" + base
+	}
+	if len(base) > 14 {
+		base = base[:14]
+	}
+
+	candidate := base
+	for i := 0; i < 5; i++ {
+		taken, err := users.UsernameTaken(ctx, tenantID, candidate)
+		if err != nil {
+			return "", status.Error(codes.Internal, "เกิดข้อผิดพลาดในการตรวจสอบชื่อผู้ใช้")
+		}
+		if !taken {
+			return candidate, nil
+		}
+		suffix, err := generateRandomToken(3)
+		if err != nil {
+			return "", status.Error(codes.Internal, "ไม่สามารถสร้างชื่อผู้ใช้ได้")
+		}
+		candidate = base + suffix
+	}
+	return "", status.Error(codes.AlreadyExists, "ชื่อผู้ใช้ถูกใช้งานแล้ว")
+}
+
+// ผูกบัญชีภายนอกกับผู้ใช้
+func (s *FederationService) linkIdentity(ctx context.Context, user *models.User, provider *models.IdentityProvider, identity *federation.Identity) (*models.LinkedIdentity, error) {
+	link := &models.LinkedIdentity{
+		TenantID:   user.TenantID,
+		UserID:     user.ID,
+		ProviderID: provider.ID,
+		Subject:    identity.Subject,
+		Email:      identity.Email,
+		CreatedAt:  time.Now(),
+	}
+	err := s.Identities.CreateLinkedIdentity(ctx, link)
+	if dup, ok := store.IsDuplicate(err); ok {
+		if dup.Field == "subject" {
+			return nil, status.Error(codes.AlreadyExists, "บัญชีภายนอกนี้ผูกกับผู้ใช้แล้ว")
+		}
+		return nil, status.Error(codes.AlreadyExists, "ผู้ใช้ผูกบัญชีกับ provider นี้ไว้แล้ว")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถผูกบัญชีได้")
+	}
+
+	s.recordIdentityEvent(ctx, "identity.linked", user, map[string]interface{}{"provider": provider.Name, "subject": identity.Subject})
+	return link, nil
+}
+
+// connector ของ provider (สร้างครั้งเดียวต่อการแก้ไข provider เพื่อใช้ discovery document และ JWKS ที่โหลดไว้ซ้ำ)
+func (s *FederationService) connector(provider *models.IdentityProvider) federation.Connector {
+	s.connectorsMu.Lock()
+	defer s.connectorsMu.Unlock()
+
+	id := provider.ID.Hex()
+	if cached, ok := s.connectors[id]; ok && cached.updatedAt.Equal(provider.UpdatedAt) {
+		return cached.connector
+	}
+	if s.connectors == nil {
+		s.connectors = map[string]cachedConnector{}
+	}
+	connector := s.NewConnector(provider)
+	s.connectors[id] = cachedConnector{updatedAt: provider.UpdatedAt, connector: connector}
+	return connector
+}
+
+// connector เริ่มต้น: OpenID Connect ตาม discovery document ของ issuer
+func (s *FederationService) newOIDCConnector(provider *models.IdentityProvider) federation.Connector {
+	return federation.NewOIDCConnector(federation.Config{
+		Issuer:       provider.Issuer,
+		ClientID:     provider.ClientID,
+		ClientSecret: provider.ClientSecret,
+		RedirectURI:  provider.RedirectURI,
+		Scopes:       provider.Scopes,
+		AllowLocal:   s.AllowLocal,
+	})
+}
+
+// ผู้ใช้เจ้าของ token ที่แนบมา
+func (s *FederationService) currentUser(ctx context.Context) (*models.User, error) {
+	_, claims, err := authenticate(ctx, s.Blacklist, s.Tenants)
+	if err != nil {
+		return nil, err
+	}
+	email, _ := claims["email"].(string)
+	user, err := s.Users.GetUserByEmail(ctx, claimsTenant(claims), email)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, "ไม่พบผู้ใช้")
+	}
+	return user, nil
+}
+
+// ดึง provider ใน tenant
+func (s *FederationService) findProvider(ctx context.Context, tenantID string, id string) (*models.IdentityProvider, error) {
+	provider, err := s.Identities.GetIdentityProvider(ctx, tenantID, id)
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, "ไม่พบ provider")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถดึงข้อมูล provider ได้")
+	}
+	return provider, nil
+}
+
+// บันทึกเหตุการณ์ของ provider ลง audit log
+func (s *FederationService) recordProviderEvent(ctx context.Context, action string, provider *models.IdentityProvider, claims map[string]interface{}, details map[string]interface{}) {
+	actor, _ := claims["email"].(string)
+	if details == nil {
+		details = map[string]interface{}{}
+	}
+	details["providerId"] = provider.ID.Hex()
+	details["provider"] = provider.Name
+	s.Audit.Record(ctx, models.AuditEvent{
+		TenantID:   provider.TenantID,
+		Action:     action,
+		ActorEmail: actor,
+		Details:    details,
+	})
+}
+
+// บันทึกเหตุการณ์ที่ผู้ใช้ทำผ่าน provider ลง audit log
+func (s *FederationService) recordIdentityEvent(ctx context.Context, action string, user *models.User, details map[string]interface{}) {
+	s.Audit.Record(ctx, models.AuditEvent{
+		TenantID:     user.TenantID,
+		Action:       action,
+		ActorEmail:   user.Email,
+		SubjectID:    user.ID.Hex(),
+		SubjectEmail: user.Email,
+		Details:      details,
+	})
+}
+
+// โดเมนของอีเมลอยู่ในรายการที่อนุญาต (รายการว่าง = ทุกโดเมน)
+func emailDomainAllowed(email string, domains []string) bool {
+	if len(domains) == 0 {
+		return true
+	}
+	_, domain, _ := strings.Cut(email, "@")
+	for _, allowed := range domains {
+		if strings.EqualFold(domain, allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+func toIdentityProviderReply(p *models.IdentityProvider, issuer string) *pb.IdentityProvider {
+	reply := &pb.IdentityProvider{
+		Id:             p.ID.Hex(),
+		Name:           p.Name,
+		DisplayName:    p.DisplayName,
+		Issuer:         p.Issuer,
+		ClientId:       p.ClientID,
+		RedirectUri:    p.RedirectURI,
+		Scopes:         p.Scopes,
+		AllowSignup:    p.AllowSignup,
+		AllowedDomains: p.AllowedDomains,
+		CreatedAt:      p.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:      p.UpdatedAt.Format(time.RFC3339),
+		Protocol:       p.Protocol,
+	}
+	if p.Protocol == "" {
+		reply.Protocol = models.ProtocolOIDC
+	}
+	if p.SAML != nil {
+		reply.Saml = toSAMLSettingsReply(p.SAML)
+		reply.SpEntityId = samlEndpoint(issuer, p, "metadata")
+		reply.AcsUrl = samlEndpoint(issuer, p, "acs")
+		reply.SpMetadataUrl = reply.SpEntityId
+	}
+	return reply
+}
+
+func toLinkedIdentityReply(l *models.LinkedIdentity, providerName string) *pb.LinkedIdentity {
+	reply := &pb.LinkedIdentity{
+		ProviderId: l.ProviderID.Hex(),
+		Provider:   providerName,
+		Subject:    l.Subject,
+		Email:      l.Email,
+		CreatedAt:  l.CreatedAt.Format(time.RFC3339),
+	}
+	if l.LastLoginAt != nil {
+		reply.LastLoginAt = l.LastLoginAt.Format(time.RFC3339)
+	}
+	return reply
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	pb "auth-microservice/auth-microservice/proto"
+	"auth-microservice/internal/audit"
+	"auth-microservice/internal/federation/federationtest"
+	"auth-microservice/internal/store"
+
+	"google.golang.org/grpc/codes"
+)
+
+// FederationService กับ provider ชื่อ "corp" ที่ชี้ไปยัง OpenID Connect provider ปลอม
+type federationFixture struct {
+	stores      *store.Stores
+	auditLogger *audit.Logger
+	service     *FederationService
+	issuer      *federationtest.OIDCIssuer
+}
+
+func newFederationFixture(t *testing.T, allowSignup bool) *federationFixture {
+	t.Helper()
+	stores := newTestStores(t)
+	auditLogger := audit.NewLogger(stores.Audit)
+	f := &federationFixture{
+		stores:      stores,
+		auditLogger: auditLogger,
+		service:     NewFederationService(stores, "http://localhost:8080", true, auditLogger),
+		issuer:      federationtest.NewOIDCIssuer(t, "auth-service", "idp-secret"),
+	}
+	_, err := f.service.CreateIdentityProvider(adminContext(t, stores, auditLogger), &pb.CreateIdentityProviderRequest{
+		Name:         "corp",
+		Issuer:       f.issuer.URL,
+		ClientId:     f.issuer.ClientID,
+		ClientSecret: f.issuer.ClientSecret,
+		RedirectUri:  "http://localhost:8080/federation/callback",
+		AllowSignup:  allowSignup,
+	})
+	if err != nil {
+		t.Fatalf("CreateIdentityProvider: %v", err)
+	}
+	return f
+}
+
+// ผู้ใช้เข้าสู่ระบบที่ provider ด้วย claims ที่กำหนด แล้วคืน state และ code ที่ provider ส่งกลับมา
+func (f *federationFixture) authorize(t *testing.T, claims map[string]interface{}) (string, string) {
+	t.Helper()
+	f.issuer.SetUser(claims)
+	start, err := f.service.StartFederatedLogin(context.Background(), &pb.StartFederatedLoginRequest{Provider: "corp"})
+	if err != nil {
+		t.Fatalf("StartFederatedLogin: %v", err)
+	}
+	return start.GetState(), f.issuer.Authorize(t, start.GetAuthorizationUrl(), start.GetState())
+}
+
+func (f *federationFixture) login(t *testing.T, claims map[string]interface{}) (*pb.CompleteFederatedLoginReply, error) {
+	t.Helper()
+	state, code := f.authorize(t, claims)
+	return f.service.CompleteFederatedLogin(context.Background(), &pb.CompleteFederatedLoginRequest{State: state, Code: code})
+}
+
+func TestFederatedLoginCreatesUserOnFirstLogin(t *testing.T) {
+	f := newFederationFixture(t, true)
+	claims := map[string]interface{}{"sub": "idp-1", "email": "dana@corp.example.com", "email_verified": true, "preferred_username": "dana.k"}
+
+	first, err := f.login(t, claims)
+	if err != nil {
+		t.Fatalf("CompleteFederatedLogin: %v", err)
+	}
+	if !first.GetCreated() || first.GetToken() == "" || first.GetEmail() != "dana@corp.example.com" || first.GetUsername() != "danak" {
+		t.Fatalf("first login = %+v, want a new user with a token", first)
+	}
+	user, err := f.stores.Users.GetUserByEmail(context.Background(), "default", "dana@corp.example.com")
+	if err != nil || !user.EmailVerified {
+		t.Fatalf("created user = %+v, %v, want a verified user", user, err)
+	}
+
+	// ครั้งต่อไปใช้บัญชีที่ผูกไว้ แม้อีเมลที่ provider ส่งมาจะเปลี่ยนไป
+	claims["email"] = "dana.k@corp.example.com"
+	second, err := f.login(t, claims)
+	if err != nil {
+		t.Fatalf("second CompleteFederatedLogin: %v", err)
+	}
+	if second.GetCreated() || second.GetEmail() != "dana@corp.example.com" {
+		t.Fatalf("second login = %+v, want the linked user", second)
+	}
+}
+
+func TestFederatedLoginLinksExistingUserByVerifiedEmail(t *testing.T) {
+	f := newFederationFixture(t, false)
+	registerAndLogin(t, f.stores, f.auditLogger, "erin@corp.example.com", "erin")
+
+	// อีเมลที่ provider ไม่ได้ยืนยันใช้ผูกบัญชีไม่ได้
+	_, err := f.login(t, map[string]interface{}{"sub": "idp-2", "email": "erin@corp.example.com", "email_verified": false})
+	wantCode(t, "login with an unverified email", err, codes.PermissionDenied)
+
+	reply, err := f.login(t, map[string]interface{}{"sub": "idp-2", "email": "erin@corp.example.com", "email_verified": true})
+	if err != nil {
+		t.Fatalf("CompleteFederatedLogin: %v", err)
+	}
+	if reply.GetCreated() || reply.GetUsername() != "erin" {
+		t.Fatalf("login = %+v, want the existing user", reply)
+	}
+
+	links, err := f.service.ListLinkedIdentities(withToken(reply.GetToken()), &pb.ListLinkedIdentitiesRequest{})
+	if err != nil || len(links.GetIdentities()) != 1 || links.GetIdentities()[0].GetProvider() != "corp" || links.GetIdentities()[0].GetSubject() != "idp-2" {
+		t.Fatalf("ListLinkedIdentities = %v, %v, want the corp account", links, err)
+	}
+}
+
+func TestFederatedLoginWithoutSignup(t *testing.T) {
+	f := newFederationFixture(t, false)
+	_, err := f.login(t, map[string]interface{}{"sub": "idp-3", "email": "frank@corp.example.com", "email_verified": true})
+	wantCode(t, "login of an unknown email without signup", err, codes.PermissionDenied)
+	if _, err := f.stores.Users.GetUserByEmail(context.Background(), "default", "frank@corp.example.com"); err == nil {
+		t.Fatal("a user was created although the provider does not allow signup")
+	}
+}
+
+func TestFederatedLoginStateIsSingleUse(t *testing.T) {
+	f := newFederationFixture(t, true)
+	state, code := f.authorize(t, map[string]interface{}{"sub": "idp-4", "email": "gina@corp.example.com", "email_verified": true})
+	if _, err := f.service.CompleteFederatedLogin(context.Background(), &pb.CompleteFederatedLoginRequest{State: state, Code: code}); err != nil {
+		t.Fatalf("CompleteFederatedLogin: %v", err)
+	}
+	_, err := f.service.CompleteFederatedLogin(context.Background(), &pb.CompleteFederatedLoginRequest{State: state, Code: code})
+	wantCode(t, "CompleteFederatedLogin with a used state", err, codes.FailedPrecondition)
+}
+
+func TestFederatedLoginRejectsInvalidIDToken(t *testing.T) {
+	f := newFederationFixture(t, true)
+	f.issuer.TamperClaims = func(claims map[string]interface{}) { claims["aud"] = "another-client" }
+	_, err := f.login(t, map[string]interface{}{"sub": "idp-5", "email": "hank@corp.example.com", "email_verified": true})
+	wantCode(t, "login with an ID token for another client", err, codes.Unauthenticated)
+}
+
+func TestLinkAndUnlinkIdentity(t *testing.T) {
+	f := newFederationFixture(t, false)
+	userCtx := registerAndLogin(t, f.stores, f.auditLogger, "ivy@example.com", "ivy")
+
+	// บัญชีภายนอกใช้อีเมลอื่นได้ เพราะผู้ใช้ยืนยันตัวตนด้วย token ของตัวเองแล้ว
+	state, code := f.authorize(t, map[string]interface{}{"sub": "idp-6", "email": "ivy@corp.example.com"})
+	link, err := f.service.LinkIdentity(userCtx, &pb.LinkIdentityRequest{State: state, Code: code})
+	if err != nil {
+		t.Fatalf("LinkIdentity: %v", err)
+	}
+	if link.GetProvider() != "corp" || link.GetSubject() != "idp-6" {
+		t.Fatalf("LinkIdentity = %+v", link)
+	}
+
+	reply, err := f.login(t, map[string]interface{}{"sub": "idp-6", "email": "ivy@corp.example.com"})
+	if err != nil || reply.GetEmail() != "ivy@example.com" {
+		t.Fatalf("login with the linked account = %+v, %v, want ivy@example.com", reply, err)
+	}
+	// เข้าสู่ระบบใหม่แล้ว token เดิมถูกยกเลิก
+	userCtx = withToken(reply.GetToken())
+
+	// บัญชีภายนอกหนึ่งบัญชีผูกกับผู้ใช้ได้คนเดียว
+	otherCtx := registerAndLogin(t, f.stores, f.auditLogger, "jack@example.com", "jack")
+	state, code = f.authorize(t, map[string]interface{}{"sub": "idp-6"})
+	_, err = f.service.LinkIdentity(otherCtx, &pb.LinkIdentityRequest{State: state, Code: code})
+	wantCode(t, "LinkIdentity of an account linked to another user", err, codes.AlreadyExists)
+
+	if _, err := f.service.UnlinkIdentity(userCtx, &pb.UnlinkIdentityRequest{Provider: "corp"}); err != nil {
+		t.Fatalf("UnlinkIdentity: %v", err)
+	}
+	links, err := f.service.ListLinkedIdentities(userCtx, &pb.ListLinkedIdentitiesRequest{})
+	if err != nil || len(links.GetIdentities()) != 0 {
+		t.Fatalf("ListLinkedIdentities after unlink = %v, %v, want none", links, err)
+	}
+	_, err = f.service.UnlinkIdentity(userCtx, &pb.UnlinkIdentityRequest{Provider: "corp"})
+	wantCode(t, "UnlinkIdentity twice", err, codes.NotFound)
+}
+
+func TestCreateIdentityProviderRejectsLocalIssuer(t *testing.T) {
+	stores := newTestStores(t)
+	auditLogger := audit.NewLogger(stores.Audit)
+	service := NewFederationService(stores, "https://auth.example.com", false, auditLogger)
+	ctx := adminContext(t, stores, auditLogger)
+	for _, issuer := range []string{
+		"http://localhost:9000",
+		"https://localhost:9000",
+		"https://idp.localhost",
+		"https://127.0.0.1",
+		"https://10.0.0.5/realms/corp",
+		"https://169.254.169.254",
+		"https://[::1]:8443",
+		"http://idp.example.com",
+	} {
+		_, err := service.CreateIdentityProvider(ctx, &pb.CreateIdentityProviderRequest{
+			Name:        "corp",
+			Issuer:      issuer,
+			ClientId:    "auth-service",
+			RedirectUri: "https://app.example.com/federation/callback",
+		})
+		wantCode(t, "CreateIdentityProvider with issuer "+issuer, err, codes.InvalidArgument)
+	}
+}
@@ -0,0 +1,451 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	pb "auth-microservice/auth-microservice/proto"
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/notify"
+	"auth-microservice/internal/store"
+	"auth-microservice/internal/validation"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// จำนวนกลุ่มสูงสุดที่ใส่ใน token (เกินนี้ token จะไม่มี claim กลุ่ม เพื่อไม่ให้ token ใหญ่เกินไป)
+const maxGroupClaims = 20
+
+func (s *GroupService) CreateGroup(ctx context.Context, in *pb.CreateGroupRequest) (*pb.Group, error) {
+	claims, err := requireAdmin(ctx, s.Blacklist, s.Tenants)
+	if err != nil {
+		return nil, err
+	}
+	if err := validation.ValidateGroupName(in.GetName()); err != nil {
+		return nil, err
+	}
+	if err := validation.ValidateGroupDescription(in.GetDescription()); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	group := &models.Group{
+		TenantID:    scopeTenant(ctx, claims),
+		Name:        in.GetName(),
+		Description: in.GetDescription(),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	err = s.Groups.CreateGroup(ctx, group)
+	if _, ok := store.IsDuplicate(err); ok {
+		return nil, status.Error(codes.AlreadyExists, "ชื่อกลุ่มถูกใช้งานแล้ว")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถสร้างกลุ่มได้")
+	}
+
+	s.recordGroupEvent(ctx, "group.created", group, claims, "", "", map[string]interface{}{"name": group.Name})
+	return toGroupReply(group), nil
+}
+
+func (s *GroupService) DeleteGroup(ctx context.Context, in *pb.DeleteGroupRequest) (*pb.DeleteGroupReply, error) {
+	claims, err := requireAdmin(ctx, s.Blacklist, s.Tenants)
+	if err != nil {
+		return nil, err
+	}
+
+	group, err := s.findGroup(ctx, scopeTenant(ctx, claims), in.GetId())
+	if err != nil {
+		return nil, err
+	}
+	err = s.Groups.DeleteGroup(ctx, group.TenantID, in.GetId())
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, "ไม่พบกลุ่ม")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถลบกลุ่มได้")
+	}
+
+	s.recordGroupEvent(ctx, "group.deleted", group, claims, "", "", map[string]interface{}{"name": group.Name})
+	return &pb.DeleteGroupReply{
+		Message: "ลบกลุ่มสำเร็จ",
+	}, nil
+}
+
+func (s *GroupService) ListGroups(ctx context.Context, in *pb.ListGroupsRequest) (*pb.ListGroupsReply, error) {
+	claims, err := requireAdmin(ctx, s.Blacklist, s.Tenants)
+	if err != nil {
+		return nil, err
+	}
+
+	groups, err := s.Groups.ListGroups(ctx, scopeTenant(ctx, claims))
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถดึงรายการกลุ่มได้")
+	}
+	reply := &pb.ListGroupsReply{}
+	for i := range groups {
+		reply.Groups = append(reply.Groups, toGroupReply(&groups[i]))
+	}
+	return reply, nil
+}
+
+func (s *GroupService) AddMember(ctx context.Context, in *pb.AddMemberRequest) (*pb.GroupMember, error) {
+	_, claims, err := authenticate(ctx, s.Blacklist, s.Tenants)
+	if err != nil {
+		return nil, err
+	}
+	role := in.GetRole()
+	if role == "" {
+		role = models.GroupRoleMember
+	}
+	if err := validation.ValidateGroupRole(role); err != nil {
+		return nil, err
+	}
+
+	tenantID := scopeTenant(ctx, claims)
+	group, callerRole, err := s.groupManager(ctx, claims, tenantID, in.GetGroupId())
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.tenantUser(ctx, tenantID, in.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+
+	// manager เพิ่มหรือแก้ไข owner ไม่ได้
+	current, err := s.Groups.GetMembership(ctx, tenantID, group.ID.Hex(), user.ID.Hex())
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		return nil, status.Error(codes.Internal, "ไม่สามารถดึงข้อมูลสมาชิกได้")
+	}
+	if callerRole != models.GroupRoleOwner && (role == models.GroupRoleOwner || (current != nil && current.Role == models.GroupRoleOwner)) {
+		return nil, status.Error(codes.PermissionDenied, "เฉพาะ owner ของกลุ่มเท่านั้นที่จัดการ owner ได้")
+	}
+
+	membership := models.GroupMembership{GroupID: group.ID, Role: role, JoinedAt: time.Now()}
+	if current != nil {
+		membership.JoinedAt = current.JoinedAt
+	}
+	err = s.Groups.AddMember(ctx, tenantID, user.ID.Hex(), membership)
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, "ไม่พบผู้ใช้")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถเพิ่มสมาชิกได้")
+	}
+
+	s.recordGroupEvent(ctx, "group.member_added", group, claims, user.ID.Hex(), user.Email, map[string]interface{}{"role": role})
+	return toGroupMemberReply(models.GroupMember{
+		UserID:   user.ID,
+		Email:    user.Email,
+		Username: user.Username,
+		Role:     membership.Role,
+		JoinedAt: membership.JoinedAt,
+	}), nil
+}
+
+func (s *GroupService) RemoveMember(ctx context.Context, in *pb.RemoveMemberRequest) (*pb.RemoveMemberReply, error) {
+	_, claims, err := authenticate(ctx, s.Blacklist, s.Tenants)
+	if err != nil {
+		return nil, err
+	}
+	tenantID := scopeTenant(ctx, claims)
+
+	// สมาชิกออกจากกลุ่มเองได้ ส่วนการนำผู้อื่นออกต้องเป็นผู้จัดการกลุ่ม
+	caller, err := s.callerUser(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+	group, err := s.findGroup(ctx, tenantID, in.GetGroupId())
+	if err != nil {
+		return nil, err
+	}
+	if caller.ID.Hex() != in.GetUserId() {
+		_, callerRole, err := s.groupManager(ctx, claims, tenantID, in.GetGroupId())
+		if err != nil {
+			return nil, err
+		}
+		target, err := s.Groups.GetMembership(ctx, tenantID, in.GetGroupId(), in.GetUserId())
+		if err == nil && target.Role == models.GroupRoleOwner && callerRole != models.GroupRoleOwner {
+			return nil, status.Error(codes.PermissionDenied, "เฉพาะ owner ของกลุ่มเท่านั้นที่จัดการ owner ได้")
+		}
+	}
+
+	err = s.Groups.RemoveMember(ctx, tenantID, in.GetGroupId(), in.GetUserId())
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, "ผู้ใช้ไม่ได้เป็นสมาชิกของกลุ่มนี้")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถนำสมาชิกออกจากกลุ่มได้")
+	}
+
+	s.recordGroupEvent(ctx, "group.member_removed", group, claims, in.GetUserId(), "", nil)
+	return &pb.RemoveMemberReply{
+		Message: "นำสมาชิกออกจากกลุ่มสำเร็จ",
+	}, nil
+}
+
+func (s *GroupService) ListGroupMembers(ctx context.Context, in *pb.ListGroupMembersRequest) (*pb.ListGroupMembersReply, error) {
+	_, claims, err := authenticate(ctx, s.Blacklist, s.Tenants)
+	if err != nil {
+		return nil, err
+	}
+	tenantID := scopeTenant(ctx, claims)
+
+	group, err := s.findGroup(ctx, tenantID, in.GetGroupId())
+	if err != nil {
+		return nil, err
+	}
+
+	// ดูรายชื่อได้เฉพาะสมาชิกของกลุ่มหรือ admin
+	if !isAdmin(claims) {
+		caller, err := s.callerUser(ctx, claims)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := s.Groups.GetMembership(ctx, tenantID, group.ID.Hex(), caller.ID.Hex()); err != nil {
+			return nil, status.Error(codes.PermissionDenied, "ดูรายชื่อได้เฉพาะสมาชิกของกลุ่มเท่านั้น")
+		}
+	}
+
+	members, err := s.Groups.ListMembers(ctx, tenantID, group.ID.Hex())
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถดึงรายชื่อสมาชิกได้")
+	}
+	reply := &pb.ListGroupMembersReply{}
+	for _, m := range members {
+		reply.Members = append(reply.Members, toGroupMemberReply(m))
+	}
+	return reply, nil
+}
+
+func (s *GroupService) ListUserGroups(ctx context.Context, in *pb.ListUserGroupsRequest) (*pb.ListUserGroupsReply, error) {
+	_, claims, err := authenticate(ctx, s.Blacklist, s.Tenants)
+	if err != nil {
+		return nil, err
+	}
+	tenantID := scopeTenant(ctx, claims)
+
+	var user *models.User
+	if in.GetUserId() == "" {
+		user, err = s.callerUser(ctx, claims)
+	} else {
+		user, err = s.tenantUser(ctx, tenantID, in.GetUserId())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// ดูกลุ่มของผู้ใช้อื่นได้เฉพาะ admin
+	if !isAdmin(claims) {
+		if email, _ := claims["email"].(string); email != user.Email {
+			return nil, status.Error(codes.PermissionDenied, "ดูกลุ่มของผู้ใช้อื่นได้เฉพาะ Admin เท่านั้น")
+		}
+	}
+
+	groups, err := s.Groups.ListUserGroups(ctx, user.TenantID, user.ID.Hex())
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถดึงกลุ่มของผู้ใช้ได้")
+	}
+	reply := &pb.ListUserGroupsReply{}
+	for _, g := range groups {
+		reply.Groups = append(reply.Groups, toUserGroupReply(g))
+	}
+	return reply, nil
+}
+
+func (s *GroupService) InviteMember(ctx context.Context, in *pb.InviteMemberRequest) (*pb.InviteMemberReply, error) {
+	_, claims, err := authenticate(ctx, s.Blacklist, s.Tenants)
+	if err != nil {
+		return nil, err
+	}
+	role := in.GetRole()
+	if role == "" {
+		role = models.GroupRoleMember
+	}
+	if err := validation.ValidateGroupRole(role); err != nil {
+		return nil, err
+	}
+	if err := validation.ValidateEmailFormat(in.GetEmail()); err != nil {
+		return nil, err
+	}
+
+	tenantID := scopeTenant(ctx, claims)
+	group, callerRole, err := s.groupManager(ctx, claims, tenantID, in.GetGroupId())
+	if err != nil {
+		return nil, err
+	}
+	if role == models.GroupRoleOwner && callerRole != models.GroupRoleOwner {
+		return nil, status.Error(codes.PermissionDenied, "เฉพาะ owner ของกลุ่มเท่านั้นที่จัดการ owner ได้")
+	}
+
+	inviter, _ := claims["email"].(string)
+	token, err := storeInvitation(ctx, s.Cache, invitation{
+		TenantID:  tenantID,
+		GroupID:   group.ID.Hex(),
+		Email:     in.GetEmail(),
+		Role:      role,
+		InvitedBy: inviter,
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถสร้างคำเชิญได้")
+	}
+	expiresAt := time.Now().Add(invitationTTL)
+
+	// ผู้ใช้ใหม่ส่ง token ตอน Register ส่วนผู้ใช้ที่มีบัญชีแล้วเรียก AcceptInvitation
+	err = s.Notifier.Send(ctx, notify.Message{
+		To:      in.GetEmail(),
+		Subject: fmt.Sprintf("คำเชิญเข้าร่วมกลุ่ม %s", group.Name),
+		Body:    fmt.Sprintf("%s เชิญคุณเข้าร่วมกลุ่ม %s\nรหัสคำเชิญของคุณคือ %s\nหรือกดลิงก์ %s/invitations/accept?token=%s\nคำเชิญนี้มีอายุ %s", inviter, group.Name, token, emailLinkBaseURL, token, invitationTTL),
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถส่งคำเชิญได้")
+	}
+
+	s.recordGroupEvent(ctx, "group.member_invited", group, claims, "", in.GetEmail(), map[string]interface{}{"role": role})
+	return &pb.InviteMemberReply{
+		Message:   "ส่งคำเชิญแล้ว",
+		ExpiresAt: expiresAt.Format(time.RFC3339),
+	}, nil
+}
+
+func (s *GroupService) AcceptInvitation(ctx context.Context, in *pb.AcceptInvitationRequest) (*pb.AcceptInvitationReply, error) {
+	_, claims, err := authenticate(ctx, s.Blacklist, s.Tenants)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.callerUser(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	inv, err := loadInvitation(ctx, s.Cache, in.GetToken())
+	if err != nil {
+		return nil, err
+	}
+	joined, err := acceptInvitation(ctx, s.Cache, s.Groups, in.GetToken(), inv, user)
+	if err != nil {
+		return nil, err
+	}
+	s.recordGroupEvent(ctx, "group.invitation_accepted", &joined.Group, claims, user.ID.Hex(), user.Email, map[string]interface{}{"role": joined.Role, "invitedBy": inv.InvitedBy})
+
+	// ออก token ใหม่ที่มีกลุ่มล่าสุด แทน token เดิม
+	tenant, err := activeTenant(ctx, s.Tenants, user.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	if err := revokeActiveToken(ctx, s.Sessions, s.Blacklist, user.TenantID, user.Email); err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถยกเลิกโทเค็นเดิมได้")
+	}
+	token, err := issueToken(ctx, s.Sessions, s.Groups, tenant, user.ID.Hex(), user.Email, user.Role)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "เจอข้อผิดพลาดในการสร้างโทเค็น")
+	}
+
+	return &pb.AcceptInvitationReply{
+		Message: "เข้าร่วมกลุ่มสำเร็จ",
+		Group:   toUserGroupReply(*joined),
+		Token:   token,
+	}, nil
+}
+
+// ดึงกลุ่มใน tenant
+func (s *GroupService) findGroup(ctx context.Context, tenantID string, id string) (*models.Group, error) {
+	group, err := s.Groups.GetGroup(ctx, tenantID, id)
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, "ไม่พบกลุ่ม")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถดึงข้อมูลกลุ่มได้")
+	}
+	return group, nil
+}
+
+// ตรวจสอบว่าผู้เรียกจัดการสมาชิกของกลุ่มได้ (admin หรือ owner/manager ของกลุ่ม)
+// แล้วคืนกลุ่มพร้อม role ของผู้เรียก (admin ถือเป็น owner)
+func (s *GroupService) groupManager(ctx context.Context, claims map[string]interface{}, tenantID string, groupID string) (*models.Group, string, error) {
+	group, err := s.findGroup(ctx, tenantID, groupID)
+	if err != nil {
+		return nil, "", err
+	}
+	if isAdmin(claims) {
+		return group, models.GroupRoleOwner, nil
+	}
+
+	caller, err := s.callerUser(ctx, claims)
+	if err != nil {
+		return nil, "", err
+	}
+	membership, err := s.Groups.GetMembership(ctx, tenantID, groupID, caller.ID.Hex())
+	if err != nil || (membership.Role != models.GroupRoleOwner && membership.Role != models.GroupRoleManager) {
+		return nil, "", status.Error(codes.PermissionDenied, "ต้องเป็น owner หรือ manager ของกลุ่มเท่านั้น")
+	}
+	return group, membership.Role, nil
+}
+
+// ผู้ใช้เจ้าของ token
+func (s *GroupService) callerUser(ctx context.Context, claims map[string]interface{}) (*models.User, error) {
+	email, _ := claims["email"].(string)
+	user, err := s.Users.GetUserByEmail(ctx, claimsTenant(claims), email)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, "ไม่พบผู้ใช้")
+	}
+	return user, nil
+}
+
+// ผู้ใช้ที่ยังไม่ถูกลบใน tenant (ผู้ใช้ของ tenant อื่นถือว่าไม่พบ)
+func (s *GroupService) tenantUser(ctx context.Context, tenantID string, id string) (*models.User, error) {
+	user, err := s.Users.GetUserByID(ctx, id, false)
+	if err != nil || user.TenantID != tenantID {
+		return nil, status.Error(codes.NotFound, "ไม่พบผู้ใช้")
+	}
+	return user, nil
+}
+
+// บันทึกเหตุการณ์ของกลุ่มลง audit log
+func (s *GroupService) recordGroupEvent(ctx context.Context, action string, group *models.Group, claims map[string]interface{}, subjectID string, subjectEmail string, details map[string]interface{}) {
+	actor, _ := claims["email"].(string)
+	if details == nil {
+		details = map[string]interface{}{}
+	}
+	details["groupId"] = group.ID.Hex()
+	s.Audit.Record(ctx, models.AuditEvent{
+		TenantID:     group.TenantID,
+		Action:       action,
+		ActorEmail:   actor,
+		SubjectID:    subjectID,
+		SubjectEmail: subjectEmail,
+		Details:      details,
+	})
+}
+
+func toGroupReply(g *models.Group) *pb.Group {
+	return &pb.Group{
+		Id:          g.ID.Hex(),
+		Name:        g.Name,
+		Description: g.Description,
+		CreatedAt:   g.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   g.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func toGroupMemberReply(m models.GroupMember) *pb.GroupMember {
+	return &pb.GroupMember{
+		UserId:   m.UserID.Hex(),
+		Email:    m.Email,
+		Username: m.Username,
+		Role:     m.Role,
+		JoinedAt: m.JoinedAt.Format(time.RFC3339),
+	}
+}
+
+func toUserGroupReply(g models.UserGroup) *pb.UserGroup {
+	return &pb.UserGroup{
+		Group:    toGroupReply(&g.Group),
+		Role:     g.Role,
+		JoinedAt: g.JoinedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,694 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	pb "auth-microservice/auth-microservice/proto"
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/store"
+	"auth-microservice/internal/validation"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	importBatchSize     = 200                // จำนวนแถวต่อ batch ที่บันทึกพร้อมกัน
+	importWorkers       = 4                  // จำนวน batch ที่ตรวจสอบ/บันทึกพร้อมกันสูงสุด (bcrypt ใช้ CPU มาก)
+	importMaxErrors     = 1000               // จำนวน error ของแถวที่ส่งกลับสูงสุด
+	importMaxLineSize   = 64 * 1024          // ความยาวสูงสุดของหนึ่งบรรทัดใน JSONL
+	importCheckpointTTL = 7 * 24 * time.Hour // อายุของ checkpoint สำหรับทำต่อด้วย importId เดิม
+)
+
+var importIDRegexp = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// คอลัมน์ที่ ExportUsers ส่งออกแต่นำเข้าไม่ได้ (ไม่สนใจเมื่อนำเข้าไฟล์ที่ส่งออกไป)
+var exportOnlyColumns = map[string]bool{"id": true, "tenantId": true, "createdAt": true, "updatedAt": true, "deleted": true}
+
+// ข้อมูลผู้ใช้หนึ่งแถวในไฟล์นำเข้า
+type importRow struct {
+	Email         string            `json:"email"`
+	Username      string            `json:"username"`
+	Password      string            `json:"password"`
+	PasswordHash  string            `json:"passwordHash"`
+	Role          string            `json:"role"`
+	DisplayName   string            `json:"displayName"`
+	AvatarURL     string            `json:"avatarUrl"`
+	Locale        string            `json:"locale"`
+	Timezone      string            `json:"timezone"`
+	Phone         string            `json:"phone"`
+	EmailVerified bool              `json:"emailVerified"`
+	Metadata      map[string]string `json:"metadata"`
+}
+
+// แถวที่อ่านจากไฟล์แล้ว (err = แถวนี้อ่านไม่ได้หรือซ้ำกับแถวก่อนหน้าในไฟล์)
+type parsedImportRow struct {
+	num int
+	row importRow
+	err error
+}
+
+// ความคืบหน้าของการนำเข้า เก็บใน cache เพื่อทำต่อด้วย importId เดิม
+// rowsDone = แถวแรก ๆ ที่ทำเสร็จต่อเนื่องกัน (batch หลังจากนี้อาจบันทึกไปบางส่วนแล้ว)
+type importCheckpoint struct {
+	RowsDone        int               `json:"rowsDone"`
+	Imported        int               `json:"imported"`
+	Skipped         int               `json:"skipped"`
+	Failed          int               `json:"failed"`
+	Errors          []importRowResult `json:"errors"`
+	ErrorsTruncated bool              `json:"errorsTruncated"`
+}
+
+// ผลของแถวที่ไม่ได้นำเข้า
+type importRowResult struct {
+	Row     int    `json:"row"`
+	Email   string `json:"email"`
+	Message string `json:"message"`
+}
+
+// ผลของ batch หนึ่ง
+type importBatchResult struct {
+	first, last int
+	imported    int
+	skipped     int
+	errors      []importRowResult
+}
+
+func (s *UserService) ImportUsers(stream grpc.ClientStreamingServer[pb.ImportUsersRequest, pb.ImportUsersReply]) error {
+	ctx := stream.Context()
+
+	// ตรวจสอบสิทธิ์ admin
+	claims, err := requireAdmin(ctx, s.Blacklist, s.Tenants)
+	if err != nil {
+		return err
+	}
+	tenantID := scopeTenant(ctx, claims)
+
+	// message แรกกำหนดรูปแบบไฟล์และ importId
+	first, err := stream.Recv()
+	if errors.Is(err, io.EOF) {
+		return status.Error(codes.InvalidArgument, "ไม่มีข้อมูลไฟล์นำเข้า")
+	}
+	if err != nil {
+		return err
+	}
+	importID := first.GetImportId()
+	if importID == "" {
+		importID = primitive.NewObjectID().Hex()
+	} else if !importIDRegexp.MatchString(importID) {
+		return status.Error(codes.InvalidArgument, "importId ต้องเป็น a-z, A-Z, 0-9, _ หรือ - ยาวไม่เกิน 64 ตัวอักษร")
+	}
+	dryRun := first.GetDryRun()
+	data := &importStreamReader{stream: stream, buf: first.GetData()}
+	var rows importRowReader
+	switch first.GetFormat() {
+	case "csv":
+		rows, err = newCSVImportReader(data)
+		if err != nil {
+			return err
+		}
+	case "jsonl":
+		rows = newJSONLImportReader(data)
+	default:
+		return status.Error(codes.InvalidArgument, "format ต้องเป็น csv หรือ jsonl")
+	}
+
+	tenant, err := activeTenant(ctx, s.Tenants, tenantID)
+	if err != nil {
+		return err
+	}
+	schema, err := s.loadProfileSchema(ctx)
+	if err != nil {
+		return status.Error(codes.Internal, "ไม่สามารถโหลด schema ของโปรไฟล์ได้")
+	}
+
+	// ทำต่อจาก checkpoint ของ importId เดิม (dry run ตรวจทั้งไฟล์เสมอ)
+	checkpointKey := "import:" + tenantID + ":" + importID
+	progress := &importProgress{}
+	resumed := false
+	if !dryRun {
+		raw, err := s.Cache.Get(ctx, checkpointKey)
+		switch {
+		case err == nil:
+			if err := json.Unmarshal([]byte(raw), &progress.checkpoint); err != nil {
+				return status.Error(codes.Internal, "checkpoint ของการนำเข้าเสียหาย")
+			}
+			resumed = true
+		case !errors.Is(err, store.ErrNotFound):
+			return status.Error(codes.Internal, "ไม่สามารถอ่าน checkpoint ของการนำเข้าได้")
+		}
+		progress.save = func(cp importCheckpoint) error {
+			data, err := json.Marshal(cp)
+			if err != nil {
+				return err
+			}
+			return s.Cache.Set(ctx, checkpointKey, string(data), importCheckpointTTL)
+		}
+	}
+
+	adminEmail, _ := claims["email"].(string)
+	job := &importJob{
+		service:    s,
+		tenant:     tenant,
+		schema:     schema,
+		importID:   importID,
+		actorEmail: adminEmail,
+		allowAdmin: isPlatformAdmin(claims),
+		dryRun:     dryRun,
+		resumed:    resumed,
+	}
+
+	// อ่านไฟล์ทีละแถวตามลำดับ แล้วตรวจสอบ/บันทึกทีละ batch พร้อมกันไม่เกิน importWorkers batch
+	workCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		fatalErr error
+	)
+	fail := func(err error) {
+		errOnce.Do(func() {
+			fatalErr = err
+			cancel()
+		})
+	}
+	sem := make(chan struct{}, importWorkers)
+	dispatch := func(batch []parsedImportRow) {
+		select {
+		case sem <- struct{}{}:
+		case <-workCtx.Done():
+			return
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			result, err := job.run(workCtx, batch)
+			if err != nil {
+				fail(err)
+				return
+			}
+			if err := progress.complete(result); err != nil {
+				fail(status.Error(codes.Internal, "ไม่สามารถบันทึก checkpoint ของการนำเข้าได้"))
+			}
+		}()
+	}
+
+	totalRows := 0
+	seenEmails := map[string]int{}
+	seenUsernames := map[string]int{}
+	var batch []parsedImportRow
+	for workCtx.Err() == nil {
+		row, err := rows.next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		var rowErr *importParseError
+		if err != nil && !errors.As(err, &rowErr) {
+			fail(err)
+			break
+		}
+		totalRows++
+		parsed := parsedImportRow{num: totalRows, row: row, err: err}
+
+		// ตรวจแถวซ้ำภายในไฟล์ตามลำดับ (รวมแถวที่ทำไปแล้วก่อนทำต่อ) แถวหลังถือว่าซ้ำ
+		if parsed.err == nil {
+			email, username := strings.ToLower(row.Email), strings.ToLower(row.Username)
+			if prev, ok := seenEmails[email]; ok && email != "" {
+				parsed.err = fmt.Errorf("อีเมลซ้ำกับแถวที่ %d", prev)
+			} else if prev, ok := seenUsernames[username]; ok && username != "" {
+				parsed.err = fmt.Errorf("ชื่อผู้ใช้ซ้ำกับแถวที่ %d", prev)
+			} else {
+				seenEmails[email] = totalRows
+				seenUsernames[username] = totalRows
+			}
+		}
+		if totalRows <= progress.checkpoint.RowsDone {
+			continue
+		}
+
+		batch = append(batch, parsed)
+		if len(batch) == importBatchSize {
+			dispatch(batch)
+			batch = nil
+		}
+	}
+	if len(batch) > 0 && workCtx.Err() == nil {
+		dispatch(batch)
+	}
+	wg.Wait()
+
+	if fatalErr == nil && ctx.Err() != nil {
+		fatalErr = status.FromContextError(ctx.Err()).Err()
+	}
+	if fatalErr != nil {
+		if _, ok := status.FromError(fatalErr); ok {
+			return fatalErr
+		}
+		return status.Error(codes.Internal, "เกิดข้อผิดพลาดในการนำเข้าผู้ใช้")
+	}
+
+	cp := progress.checkpoint
+	if !dryRun {
+		s.Audit.Record(ctx, models.AuditEvent{
+			TenantID:   tenantID,
+			Action:     "users.imported",
+			ActorEmail: job.actorEmail,
+			Details: map[string]interface{}{
+				"importId": importID,
+				"rows":     totalRows,
+				"imported": cp.Imported,
+				"skipped":  cp.Skipped,
+				"failed":   cp.Failed,
+			},
+		})
+	}
+
+	reply := &pb.ImportUsersReply{
+		ImportId:        importID,
+		TotalRows:       int32(totalRows),
+		Imported:        int32(cp.Imported),
+		Skipped:         int32(cp.Skipped),
+		Failed:          int32(cp.Failed),
+		ErrorsTruncated: cp.ErrorsTruncated,
+		DryRun:          dryRun,
+	}
+	for _, e := range cp.Errors {
+		reply.Errors = append(reply.Errors, &pb.ImportRowError{Row: int32(e.Row), Email: e.Email, Message: e.Message})
+	}
+	return stream.SendAndClose(reply)
+}
+
+// ข้อมูลที่ใช้ร่วมกันของทุก batch ในการนำเข้าครั้งหนึ่ง
+type importJob struct {
+	service    *UserService
+	tenant     *models.Tenant
+	schema     []models.ProfileAttribute
+	importID   string
+	actorEmail string
+	allowAdmin bool // เฉพาะ admin ของระบบที่นำเข้าผู้ใช้ role admin ได้
+	dryRun     bool
+	resumed    bool // ทำต่อจาก checkpoint: ผู้ใช้ที่มีอยู่แล้วอาจถูกนำเข้าไปก่อน stream ขาด จึงนับเป็น skipped
+}
+
+// ตรวจสอบแล้วบันทึกผู้ใช้ใน batch (error ที่คืน = หยุดการนำเข้าทั้งหมด)
+func (j *importJob) run(ctx context.Context, batch []parsedImportRow) (importBatchResult, error) {
+	s := j.service
+	result := importBatchResult{first: batch[0].num, last: batch[len(batch)-1].num}
+	reject := func(r parsedImportRow, err error) {
+		st, ok := status.FromError(err)
+		if ok && st.Code() == codes.AlreadyExists && j.resumed {
+			result.skipped++
+			return
+		}
+		msg := err.Error()
+		if ok {
+			msg = st.Message()
+		}
+		result.errors = append(result.errors, importRowResult{Row: r.num, Email: r.row.Email, Message: msg})
+	}
+
+	var (
+		valid  []parsedImportRow
+		users  []*models.User
+		events []*models.OutboxEvent
+	)
+	for _, r := range batch {
+		if r.err != nil {
+			reject(r, r.err)
+			continue
+		}
+		user, err := j.buildUser(ctx, r.row)
+		if err != nil {
+			if _, ok := status.FromError(err); !ok {
+				return result, err
+			}
+			reject(r, err)
+			continue
+		}
+		event, err := newOutboxEvent(user.TenantID, "user.registered", map[string]interface{}{
+			"email":     user.Email,
+			"username":  user.Username,
+			"role":      user.Role,
+			"createdAt": user.CreatedAt.UTC(),
+			"importId":  j.importID,
+		})
+		if err != nil {
+			return result, err
+		}
+		valid = append(valid, r)
+		users = append(users, user)
+		events = append(events, event)
+	}
+	if j.dryRun || len(users) == 0 {
+		result.imported = len(users)
+		return result, nil
+	}
+
+	// unique constraint กันอีเมล/username ที่ถูกสร้างหลังการตรวจสอบ (เช่น ผู้ใช้สมัครเองระหว่างนำเข้า)
+	rowErrs, err := s.Outbox.CreateUsersWithEvents(ctx, users, events)
+	if err != nil {
+		return result, err
+	}
+	for i, user := range users {
+		if rowErrs[i] != nil {
+			reject(valid[i], duplicateKeyError(rowErrs[i]))
+			continue
+		}
+		result.imported++
+		s.Audit.Record(ctx, models.AuditEvent{
+			TenantID:     user.TenantID,
+			Action:       "user.imported",
+			ActorEmail:   j.actorEmail,
+			SubjectID:    user.ID.Hex(),
+			SubjectEmail: user.Email,
+			Details:      map[string]interface{}{"importId": j.importID},
+		})
+	}
+	return result, nil
+}
+
+// ตรวจสอบแถวด้วยกฎเดียวกับการสมัครและการแก้ไขโปรไฟล์ แล้วสร้างข้อมูลผู้ใช้
+func (j *importJob) buildUser(ctx context.Context, row importRow) (*models.User, error) {
+	s := j.service
+	if err := validation.ValidateEmail(row.Email, ctx, s.Users, j.tenant.ID); err != nil {
+		return nil, err
+	}
+	if err := validation.ValidateUsername(row.Username, ctx, s.Users, j.tenant.ID); err != nil {
+		return nil, err
+	}
+
+	// รหัสผ่านปกติเข้ารหัสด้วย bcrypt ตาม policy ของ tenant ส่วน hash จากระบบเดิมเก็บตามเดิม
+	var hash string
+	switch {
+	case row.Password != "" && row.PasswordHash != "":
+		return nil, status.Error(codes.InvalidArgument, "ระบุ password หรือ passwordHash อย่างใดอย่างหนึ่งเท่านั้น")
+	case row.PasswordHash != "":
+		if err := validation.ValidatePasswordHash(row.PasswordHash); err != nil {
+			return nil, err
+		}
+		hash = row.PasswordHash
+	case row.Password != "":
+		var err error
+		if hash, err = validation.ValidatePassword(row.Password, j.tenant.PasswordPolicy); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, status.Error(codes.InvalidArgument, "ต้องระบุ password หรือ passwordHash")
+	}
+
+	role := row.Role
+	if role == "" {
+		role = "user"
+	}
+	if err := validation.ValidateRole(role, j.tenant.ID); err != nil {
+		return nil, err
+	}
+	if role == roleAdmin && !j.allowAdmin {
+		return nil, status.Error(codes.PermissionDenied, "เฉพาะ Admin ของระบบที่นำเข้าผู้ใช้ role admin ได้")
+	}
+
+	for _, err := range []error{
+		validation.ValidateDisplayName(row.DisplayName),
+		validation.ValidateAvatarURL(row.AvatarURL),
+		validation.ValidateLocale(row.Locale),
+		validation.ValidateTimezone(row.Timezone),
+		validation.ValidatePhone(row.Phone),
+		validation.ValidateMetadata(row.Metadata, j.schema),
+	} {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	return &models.User{
+		TenantID:          j.tenant.ID,
+		Email:             row.Email,
+		Username:          row.Username,
+		Password:          hash,
+		PasswordHistory:   []string{},
+		PasswordChangedAt: &now,
+		Role:              role,
+		EmailVerified:     row.EmailVerified,
+		DisplayName:       row.DisplayName,
+		AvatarURL:         row.AvatarURL,
+		Locale:            row.Locale,
+		Timezone:          row.Timezone,
+		Phone:             row.Phone,
+		Metadata:          row.Metadata,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}, nil
+}
+
+// รวมผลของ batch ที่เสร็จไม่ตามลำดับ และบันทึก checkpoint เมื่อแถวแรก ๆ เสร็จต่อเนื่องกันเพิ่มขึ้น
+type importProgress struct {
+	mu         sync.Mutex
+	checkpoint importCheckpoint
+	pending    map[int]importBatchResult // batch ที่เสร็จแล้วแต่ batch ก่อนหน้ายังไม่เสร็จ (key = แถวแรก)
+	save       func(importCheckpoint) error
+}
+
+func (p *importProgress) complete(result importBatchResult) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.pending == nil {
+		p.pending = map[int]importBatchResult{}
+	}
+	p.pending[result.first] = result
+
+	advanced := false
+	for {
+		next, ok := p.pending[p.checkpoint.RowsDone+1]
+		if !ok {
+			break
+		}
+		delete(p.pending, next.first)
+		cp := &p.checkpoint
+		cp.RowsDone = next.last
+		cp.Imported += next.imported
+		cp.Skipped += next.skipped
+		cp.Failed += len(next.errors)
+		for _, e := range next.errors {
+			if len(cp.Errors) == importMaxErrors {
+				cp.ErrorsTruncated = true
+				break
+			}
+			cp.Errors = append(cp.Errors, e)
+		}
+		advanced = true
+	}
+	if !advanced || p.save == nil {
+		return nil
+	}
+	return p.save(p.checkpoint)
+}
+
+// แถวที่อ่านไม่ได้ (ข้ามแถวนี้แล้วอ่านแถวถัดไปต่อได้)
+type importParseError struct {
+	message string
+}
+
+func (e *importParseError) Error() string { return e.message }
+
+// อ่านไฟล์นำเข้าทีละแถว คืน io.EOF เมื่อหมดไฟล์ และ *importParseError เมื่อแถวนั้นอ่านไม่ได้
+type importRowReader interface {
+	next() (importRow, error)
+}
+
+// อ่านข้อมูลไฟล์ต่อกันจาก message ใน stream
+type importStreamReader struct {
+	stream grpc.ClientStreamingServer[pb.ImportUsersRequest, pb.ImportUsersReply]
+	buf    []byte
+}
+
+func (r *importStreamReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		in, err := r.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+		r.buf = in.GetData()
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+type csvImportReader struct {
+	reader   *csv.Reader
+	columns  []string // ชื่อคอลัมน์ตามลำดับในไฟล์ ("" = ไม่สนใจ)
+	metadata map[int]string
+}
+
+func newCSVImportReader(r io.Reader) (*csvImportReader, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, status.Error(codes.InvalidArgument, "ไฟล์ CSV ต้องมีบรรทัดชื่อคอลัมน์")
+	}
+	if err != nil {
+		return nil, importReadError(err)
+	}
+
+	known := map[string]bool{}
+	for _, field := range []string{"email", "username", "password", "passwordHash", "role", "displayName",
+		"avatarUrl", "locale", "timezone", "phone", "emailVerified"} {
+		known[field] = true
+	}
+	c := &csvImportReader{reader: reader, columns: make([]string, len(header)), metadata: map[int]string{}}
+	seen := map[string]bool{}
+	for i, name := range header {
+		name = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+		if seen[name] {
+			return nil, status.Errorf(codes.InvalidArgument, "คอลัมน์ %q ซ้ำกัน", name)
+		}
+		seen[name] = true
+		switch {
+		case known[name]:
+			c.columns[i] = name
+		case strings.HasPrefix(name, "metadata.") && len(name) > len("metadata."):
+			c.metadata[i] = strings.TrimPrefix(name, "metadata.")
+		case exportOnlyColumns[name]:
+		default:
+			return nil, status.Errorf(codes.InvalidArgument, "ไม่รู้จักคอลัมน์ %q", name)
+		}
+	}
+	if !seen["email"] || !seen["username"] {
+		return nil, status.Error(codes.InvalidArgument, "ไฟล์ CSV ต้องมีคอลัมน์ email และ username")
+	}
+	return c, nil
+}
+
+func (c *csvImportReader) next() (importRow, error) {
+	var row importRow
+	record, err := c.reader.Read()
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return row, &importParseError{message: fmt.Sprintf("รูปแบบ CSV ไม่ถูกต้อง (บรรทัด %d)", parseErr.Line)}
+		}
+		if errors.Is(err, io.EOF) {
+			return row, io.EOF
+		}
+		return row, importReadError(err)
+	}
+	if len(record) != len(c.columns) {
+		return row, &importParseError{message: fmt.Sprintf("จำนวนคอลัมน์ต้องเท่ากับ %d", len(c.columns))}
+	}
+
+	for i, value := range record {
+		if key, ok := c.metadata[i]; ok {
+			// ค่าว่าง = ไม่กำหนด key นี้
+			if value != "" {
+				if row.Metadata == nil {
+					row.Metadata = map[string]string{}
+				}
+				row.Metadata[key] = value
+			}
+			continue
+		}
+		switch c.columns[i] {
+		case "email":
+			row.Email = value
+		case "username":
+			row.Username = value
+		case "password":
+			row.Password = value
+		case "passwordHash":
+			row.PasswordHash = value
+		case "role":
+			row.Role = value
+		case "displayName":
+			row.DisplayName = value
+		case "avatarUrl":
+			row.AvatarURL = value
+		case "locale":
+			row.Locale = value
+		case "timezone":
+			row.Timezone = value
+		case "phone":
+			row.Phone = value
+		case "emailVerified":
+			if value == "" {
+				continue
+			}
+			verified, err := strconv.ParseBool(value)
+			if err != nil {
+				return row, &importParseError{message: "emailVerified ต้องเป็น true หรือ false"}
+			}
+			row.EmailVerified = verified
+		}
+	}
+	return row, nil
+}
+
+type jsonlImportReader struct {
+	scanner *bufio.Scanner
+}
+
+func newJSONLImportReader(r io.Reader) *jsonlImportReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 4096), importMaxLineSize)
+	return &jsonlImportReader{scanner: scanner}
+}
+
+func (j *jsonlImportReader) next() (importRow, error) {
+	// แถวที่ส่งออกจาก ExportUsers มี field ที่นำเข้าไม่ได้ (id, createdAt, ...) ซึ่งไม่สนใจ
+	var row struct {
+		importRow
+		ID        json.RawMessage `json:"id"`
+		TenantID  json.RawMessage `json:"tenantId"`
+		CreatedAt json.RawMessage `json:"createdAt"`
+		UpdatedAt json.RawMessage `json:"updatedAt"`
+		Deleted   json.RawMessage `json:"deleted"`
+	}
+	for j.scanner.Scan() {
+		line := bytes.TrimSpace(j.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		decoder := json.NewDecoder(bytes.NewReader(line))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&row); err != nil {
+			return row.importRow, &importParseError{message: "JSON ไม่ถูกต้อง: " + err.Error()}
+		}
+		return row.importRow, nil
+	}
+	if err := j.scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return row.importRow, status.Errorf(codes.InvalidArgument, "แต่ละบรรทัดต้องยาวไม่เกิน %d ไบต์", importMaxLineSize)
+		}
+		return row.importRow, importReadError(err)
+	}
+	return row.importRow, io.EOF
+}
+
+// error จากการอ่าน stream (client ยกเลิกหรือ stream ขาด) คืนตามเดิม ส่วน error อื่นถือว่าไฟล์ไม่ถูกต้อง
+func importReadError(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+	return status.Error(codes.InvalidArgument, "อ่านไฟล์นำเข้าไม่ได้")
+}
@@ -0,0 +1,336 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	pb "auth-microservice/auth-microservice/proto"
+	"auth-microservice/internal/audit"
+	"auth-microservice/internal/events"
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/notify"
+	"auth-microservice/internal/search"
+	"auth-microservice/internal/store"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+// ClientStream ของ ImportUsers ที่ส่ง message ตามที่กำหนดแล้วเก็บคำตอบไว้
+type importStream struct {
+	grpc.ServerStream
+	ctx   context.Context
+	msgs  []*pb.ImportUsersRequest
+	reply *pb.ImportUsersReply
+}
+
+func (s *importStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *importStream) Recv() (*pb.ImportUsersRequest, error) {
+	if len(s.msgs) == 0 {
+		return nil, io.EOF
+	}
+	msg := s.msgs[0]
+	s.msgs = s.msgs[1:]
+	return msg, nil
+}
+
+func (s *importStream) SendAndClose(reply *pb.ImportUsersReply) error {
+	s.reply = reply
+	return nil
+}
+
+// UserService กับ admin ของระบบที่ใช้นำเข้าและส่งออกผู้ใช้
+type importFixture struct {
+	stores   *store.Stores
+	service  *UserService
+	auth     *AuthService
+	adminCtx context.Context
+}
+
+func newImportFixture(t *testing.T) *importFixture {
+	t.Helper()
+	stores := newTestStores(t)
+	auditLogger := audit.NewLogger(stores.Audit)
+	return &importFixture{
+		stores:   stores,
+		service:  NewUserService(stores, events.NewMemoryBus(), auditLogger),
+		auth:     NewAuthService(stores, notify.NewLogNotifier(), auditLogger),
+		adminCtx: adminContext(t, stores, auditLogger),
+	}
+}
+
+// นำเข้าไฟล์โดยแบ่งข้อมูลเป็นหลาย message (message แรกมีส่วนหัวของไฟล์ด้วย)
+func (f *importFixture) importFile(ctx context.Context, first *pb.ImportUsersRequest, file string) (*pb.ImportUsersReply, error) {
+	const chunkSize = 37
+	msgs := []*pb.ImportUsersRequest{first}
+	for len(file) > 0 {
+		n := min(chunkSize, len(file))
+		if msgs[len(msgs)-1].Data == nil {
+			msgs[len(msgs)-1].Data = []byte(file[:n])
+		} else {
+			msgs = append(msgs, &pb.ImportUsersRequest{Data: []byte(file[:n])})
+		}
+		file = file[n:]
+	}
+	stream := &importStream{ctx: ctx, msgs: msgs}
+	if err := f.service.ImportUsers(stream); err != nil {
+		return nil, err
+	}
+	return stream.reply, nil
+}
+
+func (f *importFixture) mustImport(t *testing.T, first *pb.ImportUsersRequest, file string) *pb.ImportUsersReply {
+	t.Helper()
+	reply, err := f.importFile(f.adminCtx, first, file)
+	if err != nil {
+		t.Fatalf("ImportUsers: %v", err)
+	}
+	return reply
+}
+
+func (f *importFixture) userCount(t *testing.T) int {
+	t.Helper()
+	total, _, err := f.stores.Users.CountUsers(context.Background(), search.UserQuery{TenantID: models.DefaultTenantID}, false)
+	if err != nil {
+		t.Fatalf("CountUsers: %v", err)
+	}
+	return int(total)
+}
+
+// ข้อความ error ของแต่ละแถวตามลำดับแถว
+func rowErrors(reply *pb.ImportUsersReply) map[int32]string {
+	errs := map[int32]string{}
+	for _, e := range reply.GetErrors() {
+		errs[e.GetRow()] = e.GetMessage()
+	}
+	return errs
+}
+
+// hash argon2id ในรูปแบบ PHC เหมือนที่ส่งออกจากระบบเดิม
+func argon2Hash(password string) string {
+	salt := []byte("legacy-salt-1234")
+	key := argon2.IDKey([]byte(password), salt, 1, 1024, 1, 32)
+	return fmt.Sprintf("$argon2id$v=%d$m=1024,t=1,p=1$%s$%s", argon2.Version,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key))
+}
+
+func TestImportUsersCSV(t *testing.T) {
+	f := newImportFixture(t)
+	bcryptHash, err := bcrypt.GenerateFromPassword([]byte("Legacy123!"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("bcrypt: %v", err)
+	}
+	file := strings.Join([]string{
+		"email,username,password,passwordHash,role,displayName,emailVerified",
+		"alice@example.com,alice," + testPassword + ",,,Alice,true",
+		"bob@example.com,bob,," + string(bcryptHash) + ",tenant_admin,Bob,",
+		"carol@example.com,carol,,\"" + argon2Hash("Legacy123!") + "\",,,", // hash ของ argon2 มีจุลภาค
+		"not-an-email,dave," + testPassword + ",,,,",
+		"ALICE@example.com,alice2," + testPassword + ",,,,",
+		"erin@example.com,erin,short,,,,",
+		"frank@example.com,frank,,,,,",
+		"grace@example.com,grace," + testPassword + ",$2a$04$broken,,,",
+		"heidi@example.com,heidi," + testPassword + ",,superuser,,",
+		"ivan@example.com,ivan," + testPassword + ",,,,maybe",
+		"admin@example.com,admin2," + testPassword + ",,,,",
+	}, "\n") + "\n"
+
+	reply := f.mustImport(t, &pb.ImportUsersRequest{Format: "csv"}, file)
+	if reply.GetTotalRows() != 11 || reply.GetImported() != 3 || reply.GetFailed() != 8 || reply.GetSkipped() != 0 || reply.GetDryRun() || reply.GetImportId() == "" {
+		t.Fatalf("ImportUsers = %v", reply)
+	}
+	errs := rowErrors(reply)
+	for _, row := range []int32{4, 5, 6, 7, 8, 9, 10, 11} {
+		if errs[row] == "" {
+			t.Errorf("row %d has no error, errors = %v", row, errs)
+		}
+	}
+	if !strings.Contains(errs[5], "แถวที่ 1") {
+		t.Errorf("duplicate email in the file: %q, want a reference to row 1", errs[5])
+	}
+
+	// รหัสผ่านปกติและ hash จากระบบเดิมใช้เข้าสู่ระบบได้
+	for email, password := range map[string]string{"alice@example.com": testPassword, "bob@example.com": "Legacy123!", "carol@example.com": "Legacy123!"} {
+		if _, err := f.auth.Login(context.Background(), &pb.LoginRequest{Email: email, Password: password}); err != nil {
+			t.Errorf("Login(%s) after import: %v", email, err)
+		}
+	}
+	alice, err := f.stores.Users.GetUserByEmail(context.Background(), models.DefaultTenantID, "alice@example.com")
+	if err != nil {
+		t.Fatalf("GetUserByEmail: %v", err)
+	}
+	if alice.Role != "user" || alice.DisplayName != "Alice" || !alice.EmailVerified || alice.Password == testPassword {
+		t.Fatalf("imported user = %+v", alice)
+	}
+	bob, _ := f.stores.Users.GetUserByEmail(context.Background(), models.DefaultTenantID, "bob@example.com")
+	if bob.Role != roleTenantAdmin || bob.Password != string(bcryptHash) {
+		t.Fatalf("imported user with a bcrypt hash = %+v", bob)
+	}
+}
+
+func TestImportUsersJSONLDryRun(t *testing.T) {
+	f := newImportFixture(t)
+	file := `{"email":"alice@example.com","username":"alice","password":"` + testPassword + `","metadata":{}}
+
+{"email":"bob@example.com","username":"bob","password":"` + testPassword + `","id":"ignored","createdAt":"2024-01-01T00:00:00Z"}
+{"email":"carol@example.com","username":"carol","password":"` + testPassword + `","nickname":"c"}
+{"email":
+`
+	before := f.userCount(t)
+	reply := f.mustImport(t, &pb.ImportUsersRequest{Format: "jsonl", DryRun: true}, file)
+	if !reply.GetDryRun() || reply.GetTotalRows() != 4 || reply.GetImported() != 2 || reply.GetFailed() != 2 {
+		t.Fatalf("dry run = %v", reply)
+	}
+	if errs := rowErrors(reply); !strings.Contains(errs[3], "nickname") || errs[4] == "" {
+		t.Fatalf("dry run errors = %v, want unknown field on row 3 and invalid JSON on row 4", errs)
+	}
+	if after := f.userCount(t); after != before {
+		t.Fatalf("dry run created %d users", after-before)
+	}
+
+	reply = f.mustImport(t, &pb.ImportUsersRequest{Format: "jsonl"}, file)
+	if reply.GetImported() != 2 || f.userCount(t) != before+2 {
+		t.Fatalf("ImportUsers after the dry run = %v", reply)
+	}
+}
+
+func TestImportUsersResume(t *testing.T) {
+	f := newImportFixture(t)
+	rows := []string{"email,username,password"}
+	for i := 1; i <= 5; i++ {
+		rows = append(rows, fmt.Sprintf("user%d@example.com,user%d,%s", i, i, testPassword))
+	}
+	file := func(n int) string { return strings.Join(rows[:n+1], "\n") + "\n" }
+
+	// stream ขาดหลังแถวที่ 3: ส่งไฟล์เดิมอีกครั้งด้วย importId เดิมเพื่อทำต่อ
+	first := f.mustImport(t, &pb.ImportUsersRequest{Format: "csv", ImportId: "nightly-1"}, file(3))
+	if first.GetImported() != 3 {
+		t.Fatalf("first part = %v", first)
+	}
+	resumed := f.mustImport(t, &pb.ImportUsersRequest{Format: "csv", ImportId: "nightly-1"}, file(5))
+	if resumed.GetTotalRows() != 5 || resumed.GetImported() != 5 || resumed.GetFailed() != 0 || resumed.GetSkipped() != 0 {
+		t.Fatalf("resumed import = %v, want rows 4-5 added to the checkpoint", resumed)
+	}
+
+	// checkpoint ตามหลัง batch ที่บันทึกไปแล้ว: ผู้ใช้ที่มีอยู่แล้วนับเป็น skipped ไม่ใช่ failed
+	if err := f.stores.Cache.Set(context.Background(), "import:"+models.DefaultTenantID+":nightly-2", `{"rowsDone":0}`, importCheckpointTTL); err != nil {
+		t.Fatalf("Cache.Set: %v", err)
+	}
+	again := f.mustImport(t, &pb.ImportUsersRequest{Format: "csv", ImportId: "nightly-2"}, file(5))
+	if again.GetSkipped() != 5 || again.GetFailed() != 0 || again.GetImported() != 0 {
+		t.Fatalf("import after a lost checkpoint = %v, want every row skipped", again)
+	}
+
+	// importId ใหม่กับผู้ใช้ที่มีอยู่แล้ว: เป็น error ของแต่ละแถว
+	fresh := f.mustImport(t, &pb.ImportUsersRequest{Format: "csv"}, file(2))
+	if fresh.GetFailed() != 2 || fresh.GetSkipped() != 0 {
+		t.Fatalf("new import of existing users = %v", fresh)
+	}
+}
+
+func TestImportUsersRejectsInvalidFiles(t *testing.T) {
+	f := newImportFixture(t)
+	user := registerAndLogin(t, f.stores, audit.NewLogger(f.stores.Audit), "alice@example.com", "alice")
+
+	for _, tc := range []struct {
+		name  string
+		ctx   context.Context
+		first *pb.ImportUsersRequest
+		file  string
+		want  codes.Code
+	}{
+		{"non-admin", user, &pb.ImportUsersRequest{Format: "csv"}, "email,username\n", codes.PermissionDenied},
+		{"unknown format", f.adminCtx, &pb.ImportUsersRequest{Format: "xml"}, "<users/>", codes.InvalidArgument},
+		{"invalid import ID", f.adminCtx, &pb.ImportUsersRequest{Format: "csv", ImportId: "../etc"}, "email,username\n", codes.InvalidArgument},
+		{"empty CSV", f.adminCtx, &pb.ImportUsersRequest{Format: "csv"}, "", codes.InvalidArgument},
+		{"missing username column", f.adminCtx, &pb.ImportUsersRequest{Format: "csv"}, "email,password\n", codes.InvalidArgument},
+		{"unknown column", f.adminCtx, &pb.ImportUsersRequest{Format: "csv"}, "email,username,nickname\n", codes.InvalidArgument},
+		{"duplicate column", f.adminCtx, &pb.ImportUsersRequest{Format: "csv"}, "email,username,email\n", codes.InvalidArgument},
+		{"JSONL line too long", f.adminCtx, &pb.ImportUsersRequest{Format: "jsonl"}, `{"email":"` + strings.Repeat("a", importMaxLineSize) + `"}`, codes.InvalidArgument},
+	} {
+		_, err := f.importFile(tc.ctx, tc.first, tc.file)
+		wantCode(t, "ImportUsers with "+tc.name, err, tc.want)
+	}
+
+	stream := &importStream{ctx: f.adminCtx}
+	wantCode(t, "ImportUsers without messages", f.service.ImportUsers(stream), codes.InvalidArgument)
+}
+
+func TestExportUsers(t *testing.T) {
+	f := newImportFixture(t)
+	f.mustImport(t, &pb.ImportUsersRequest{Format: "csv"}, "email,username,password,role\n"+
+		"alice@example.com,alice,"+testPassword+",user\n"+
+		"bob@example.com,bob,"+testPassword+",tenant_admin\n")
+	total := f.userCount(t) // รวม admin
+
+	export := func(in *pb.ExportUsersRequest) []*pb.ExportUsersChunk {
+		t.Helper()
+		stream := newTestServerStream[pb.ExportUsersChunk](f.adminCtx)
+		if err := f.service.ExportUsers(in, stream); err != nil {
+			t.Fatalf("ExportUsers(%v): %v", in, err)
+		}
+		return stream.drain()
+	}
+
+	if chunks := export(&pb.ExportUsersRequest{DryRun: true}); len(chunks) != 1 || int(chunks[0].GetTotal()) != total || len(chunks[0].GetData()) != 0 {
+		t.Fatalf("dry run = %v, want one chunk with total %d", chunks, total)
+	}
+
+	chunks := export(&pb.ExportUsersRequest{Format: "csv"})
+	if len(chunks) != 1 || int(chunks[0].GetRows()) != total || chunks[0].GetResumeToken() != "" {
+		t.Fatalf("CSV export = %v", chunks)
+	}
+	records, err := csv.NewReader(bytes.NewReader(chunks[0].GetData())).ReadAll()
+	if err != nil {
+		t.Fatalf("read CSV: %v", err)
+	}
+	if strings.Join(records[0], ",") != strings.Join(exportUserColumns, ",") || len(records) != total+1 {
+		t.Fatalf("CSV header %v with %d rows", records[0], len(records)-1)
+	}
+
+	chunks = export(&pb.ExportUsersRequest{Format: "jsonl", Role: roleTenantAdmin})
+	lines := strings.Split(strings.TrimSpace(string(chunks[0].GetData())), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("JSONL export with a role filter = %q", chunks[0].GetData())
+	}
+	var row map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &row); err != nil {
+		t.Fatalf("JSONL row: %v", err)
+	}
+	if row["email"] != "bob@example.com" {
+		t.Fatalf("JSONL row = %v", row)
+	}
+	for _, field := range []string{"password", "passwordHash", "passwordHistory"} {
+		if _, ok := row[field]; ok {
+			t.Fatalf("JSONL export contains %s", field)
+		}
+	}
+
+	// ไฟล์ที่ส่งออกนำเข้ากลับได้ (คอลัมน์ที่ส่งออกอย่างเดียวไม่สนใจ) แต่ไม่มีรหัสผ่านจึงต้องเพิ่มเอง
+	exported := strings.ReplaceAll(string(chunks[0].GetData()), "bob", "robert")
+	reply := f.mustImport(t, &pb.ImportUsersRequest{Format: "jsonl", DryRun: true}, exported)
+	if reply.GetFailed() != 1 || !strings.Contains(reply.GetErrors()[0].GetMessage(), "password") {
+		t.Fatalf("re-import of an export = %v, want a missing password error", reply)
+	}
+
+	for name, in := range map[string]*pb.ExportUsersRequest{
+		"unknown format":       {Format: "xml"},
+		"invalid resume token": {ResumeToken: "not-a-token"},
+		"invalid deleted":      {Deleted: "sometimes"},
+	} {
+		err := f.service.ExportUsers(in, newTestServerStream[pb.ExportUsersChunk](f.adminCtx))
+		wantCode(t, "ExportUsers with "+name, err, codes.InvalidArgument)
+	}
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/store"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const invitationTTL = 7 * 24 * time.Hour // อายุของคำเชิญเข้ากลุ่ม
+
+// คำเชิญเข้ากลุ่มที่เก็บไว้ใน cache ภายใต้ "invitation:<token>" จนกว่าจะถูกตอบรับหรือหมดอายุ
+type invitation struct {
+	TenantID  string `json:"tenantId"`
+	GroupID   string `json:"groupId"`
+	Email     string `json:"email"`
+	Role      string `json:"role"`
+	InvitedBy string `json:"invitedBy"`
+}
+
+// เก็บคำเชิญไว้ใน cache แล้วคืน token
+func storeInvitation(ctx context.Context, cache store.KeyValueStore, inv invitation) (string, error) {
+	token, err := generateRandomToken(32)
+	if err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(inv)
+	if err != nil {
+		return "", err
+	}
+	if err := cache.Set(ctx, "invitation:"+token, string(data), invitationTTL); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// อ่านคำเชิญจาก cache ตาม token
+func loadInvitation(ctx context.Context, cache store.KeyValueStore, token string) (*invitation, error) {
+	if token == "" {
+		return nil, status.Error(codes.InvalidArgument, "ต้องระบุ token คำเชิญ")
+	}
+	data, err := cache.Get(ctx, "invitation:"+token)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, "คำเชิญไม่ถูกต้องหรือหมดอายุแล้ว")
+	}
+	var inv invitation
+	if err := json.Unmarshal([]byte(data), &inv); err != nil {
+		return nil, status.Error(codes.Internal, "ข้อมูลคำเชิญไม่ถูกต้อง")
+	}
+	return &inv, nil
+}
+
+// เพิ่มผู้ใช้เข้ากลุ่มตามคำเชิญแล้วลบคำเชิญทิ้ง คำเชิญใช้ได้เฉพาะอีเมลที่ถูกเชิญใน tenant เดียวกัน
+// ผู้ใช้ที่เป็นสมาชิกอยู่แล้วคง role เดิมไว้
+func acceptInvitation(ctx context.Context, cache store.KeyValueStore, groups store.GroupStore, token string, inv *invitation, user *models.User) (*models.UserGroup, error) {
+	if user.TenantID != inv.TenantID || !strings.EqualFold(user.Email, inv.Email) {
+		return nil, status.Error(codes.PermissionDenied, "คำเชิญนี้ไม่ใช่ของผู้ใช้นี้")
+	}
+
+	group, err := groups.GetGroup(ctx, inv.TenantID, inv.GroupID)
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, "ไม่พบกลุ่มของคำเชิญนี้")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถดึงข้อมูลกลุ่มได้")
+	}
+
+	membership, err := groups.GetMembership(ctx, inv.TenantID, inv.GroupID, user.ID.Hex())
+	if errors.Is(err, store.ErrNotFound) {
+		membership = &models.GroupMembership{GroupID: group.ID, Role: inv.Role, JoinedAt: time.Now()}
+		err = groups.AddMember(ctx, inv.TenantID, user.ID.Hex(), *membership)
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถเพิ่มผู้ใช้เข้ากลุ่มได้")
+	}
+
+	cache.Delete(ctx, "invitation:"+token)
+	return &models.UserGroup{Group: *group, Role: membership.Role, JoinedAt: membership.JoinedAt}, nil
+}
@@ -0,0 +1,307 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	pb "auth-microservice/auth-microservice/proto"
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/notify"
+	"auth-microservice/internal/store"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	loginCodeTTL         = 10 * time.Minute // อายุของรหัสและ magic link
+	loginCodeDigits      = 6
+	loginCodeMaxAttempts = 5 // ใส่รหัสผิดได้กี่ครั้งก่อนรหัสนั้นใช้ไม่ได้
+)
+
+// คำขอเข้าสู่ระบบทางอีเมลที่รอยืนยัน (เก็บเฉพาะ hash ของรหัสและ device token)
+type loginCode struct {
+	TenantID   string `json:"tenantId"`
+	UserID     string `json:"userId"`
+	Email      string `json:"email"`
+	CodeHash   string `json:"codeHash"`
+	DeviceHash string `json:"deviceHash"`
+}
+
+// ข้อมูลใน magic link (เซ็นด้วย key ที่ได้จาก signing key ปัจจุบันของ tenant)
+type magicLinkClaims struct {
+	TenantID  string `json:"tid"`
+	ID        string `json:"id"`
+	KeyID     string `json:"kid,omitempty"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+func (s *AuthService) RequestLoginCode(ctx context.Context, in *pb.RequestLoginCodeRequest) (*pb.RequestLoginCodeReply, error) {
+	tenant, err := requestTenant(ctx, s.Tenants)
+	if err != nil {
+		return nil, err
+	}
+	email := in.GetEmail()
+	if email == "" {
+		return nil, status.Error(codes.InvalidArgument, "ต้องระบุอีเมล")
+	}
+
+	// ใช้ตัวนับเดียวกับ Login จึงขอรหัสรัว ๆ หรือสลับกับการเดารหัสผ่านไม่ได้
+	isLimited, err := s.isRateLimited(ctx, tenant.ID, email)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถตรวจสอบ Rate Limit ได้")
+	}
+	if isLimited {
+		return nil, status.Error(codes.ResourceExhausted, "คุณพยายามเข้าสู่ระบบบ่อยเกินไป กรุณารอ 1 นาที")
+	}
+
+	deviceToken, err := generateRandomToken(32)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถสร้าง token ได้")
+	}
+	reply := &pb.RequestLoginCodeReply{
+		Message:     "ถ้ามีบัญชีที่ใช้อีเมลนี้ ระบบได้ส่งรหัสเข้าสู่ระบบไปแล้ว",
+		DeviceToken: deviceToken,
+		ExpiresIn:   int64(loginCodeTTL.Seconds()),
+	}
+
+	// ไม่บอกว่ามีผู้ใช้ที่มีอีเมลนี้หรือไม่
+	user, err := s.Users.GetUserByEmail(ctx, tenant.ID, email)
+	if err != nil {
+		return reply, nil
+	}
+
+	id, err := generateRandomToken(16)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถสร้างรหัสเข้าสู่ระบบได้")
+	}
+	code, err := generateNumericCode(loginCodeDigits)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถสร้างรหัสเข้าสู่ระบบได้")
+	}
+	magicToken, err := signMagicLink(tenant, id, time.Now().Add(loginCodeTTL))
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถสร้าง magic link ได้")
+	}
+
+	// ขอรหัสใหม่แล้วรหัสและลิงก์เดิมใช้ไม่ได้อีก
+	indexKey := loginCodeIndexKey(tenant.ID, user.Email)
+	if previous, err := s.Cache.Get(ctx, indexKey); err == nil {
+		s.Cache.Delete(ctx, "login_code:"+previous)
+	}
+	pending := loginCode{
+		TenantID:   tenant.ID,
+		UserID:     user.ID.Hex(),
+		Email:      user.Email,
+		CodeHash:   hashAPIKey(id + ":" + code),
+		DeviceHash: hashAPIKey(deviceToken),
+	}
+	if err := setJSON(ctx, s.Cache, "login_code:"+id, pending, loginCodeTTL); err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถบันทึกรหัสเข้าสู่ระบบได้")
+	}
+	if err := s.Cache.Set(ctx, indexKey, id, loginCodeTTL); err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถบันทึกรหัสเข้าสู่ระบบได้")
+	}
+
+	err = s.Notifier.Send(ctx, notify.Message{
+		To:      user.Email,
+		Subject: "รหัสเข้าสู่ระบบ",
+		Body: fmt.Sprintf("รหัสเข้าสู่ระบบของคุณคือ %s\nหรือกดลิงก์ %s/login/magic?token=%s บนอุปกรณ์ที่ขอเข้าสู่ระบบ\nรหัสและลิงก์มีอายุ %s และใช้ได้ครั้งเดียว",
+			code, emailLinkBaseURL, magicToken, loginCodeTTL),
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถส่งอีเมลได้")
+	}
+
+	s.Audit.Record(ctx, models.AuditEvent{
+		TenantID:     tenant.ID,
+		Action:       "user.login_code_requested",
+		ActorEmail:   user.Email,
+		SubjectID:    user.ID.Hex(),
+		SubjectEmail: user.Email,
+		Details:      requestDetails(ctx),
+	})
+	return reply, nil
+}
+
+func (s *AuthService) VerifyLoginCode(ctx context.Context, in *pb.VerifyLoginCodeRequest) (*pb.LoginReply, error) {
+	if in.GetDeviceToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "ต้องระบุ deviceToken")
+	}
+
+	var (
+		tenant *models.Tenant
+		id     string
+		method string
+		err    error
+	)
+	invalid := status.Error(codes.Unauthenticated, "รหัสไม่ถูกต้องหรือหมดอายุ")
+	switch {
+	case in.GetMagicToken() != "":
+		claims, err := verifyMagicLink(ctx, s.Tenants, in.GetMagicToken())
+		if err != nil {
+			return nil, err
+		}
+		if tenant, err = activeTenant(ctx, s.Tenants, claims.TenantID); err != nil {
+			return nil, err
+		}
+		id, method = claims.ID, "magic_link"
+	case in.GetEmail() != "" && in.GetCode() != "":
+		if tenant, err = requestTenant(ctx, s.Tenants); err != nil {
+			return nil, err
+		}
+		isLimited, err := s.isRateLimited(ctx, tenant.ID, in.GetEmail())
+		if err != nil {
+			return nil, status.Error(codes.Internal, "ไม่สามารถตรวจสอบ Rate Limit ได้")
+		}
+		if isLimited {
+			return nil, status.Error(codes.ResourceExhausted, "คุณพยายามเข้าสู่ระบบบ่อยเกินไป กรุณารอ 1 นาที")
+		}
+		if id, err = s.Cache.Get(ctx, loginCodeIndexKey(tenant.ID, in.GetEmail())); err != nil {
+			return nil, invalid
+		}
+		method = "email_code"
+	default:
+		return nil, status.Error(codes.InvalidArgument, "ต้องระบุ email และ code หรือ magicToken")
+	}
+
+	key := "login_code:" + id
+	var pending loginCode
+	if getJSON(ctx, s.Cache, key, &pending) != nil || pending.TenantID != tenant.ID {
+		return nil, invalid
+	}
+	// รหัสและลิงก์ผูกกับอุปกรณ์ที่ขอ (ลิงก์ที่ถูกส่งต่อหรือเปิดบนอุปกรณ์อื่นใช้ไม่ได้)
+	if subtle.ConstantTimeCompare([]byte(hashAPIKey(in.GetDeviceToken())), []byte(pending.DeviceHash)) != 1 {
+		return nil, status.Error(codes.PermissionDenied, "ต้องยืนยันจากอุปกรณ์ที่ขอรหัสเข้าสู่ระบบ")
+	}
+	if method == "email_code" {
+		// นับรหัสผิดต่อคำขอ เกินกำหนดแล้วต้องขอรหัสใหม่
+		if subtle.ConstantTimeCompare([]byte(hashAPIKey(id+":"+in.GetCode())), []byte(pending.CodeHash)) != 1 {
+			s.isRateLimited(ctx, tenant.ID, in.GetEmail())
+			if attempts, err := s.Cache.Incr(ctx, key+":attempts", loginCodeTTL); err != nil || attempts >= loginCodeMaxAttempts {
+				s.Cache.Delete(ctx, key)
+			}
+			if user, err := s.Users.GetUserByID(ctx, pending.UserID, false); err == nil {
+				s.recordCodeLogin(ctx, "user.login_failed", user, method)
+			}
+			return nil, invalid
+		}
+	}
+	if ok, err := consumeOnce(ctx, s.Cache, key+":used", loginCodeTTL); err != nil || !ok {
+		return nil, invalid
+	}
+	s.Cache.Delete(ctx, key)
+	s.Cache.Delete(ctx, loginCodeIndexKey(tenant.ID, pending.Email))
+
+	user, err := s.Users.GetUserByID(ctx, pending.UserID, false)
+	if err != nil || user.TenantID != tenant.ID {
+		return nil, invalid
+	}
+
+	// อีเมลเป็นปัจจัยเดียว ผู้ใช้ที่บังคับใช้ passkey ยังต้องยืนยันด้วย passkey
+	if user.PasskeyRequired {
+		return beginPasskeySecondFactor(ctx, s.Cache, user, false)
+	}
+
+	if err := s.revokeActiveToken(ctx, tenant.ID, user.Email); err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถเพิ่ม token เข้า blacklisted ได้")
+	}
+	token, err := s.issueToken(ctx, tenant, user.ID.Hex(), user.Email, user.Role)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "เจอข้อผิดพลาดในการสร้างโทเค็น")
+	}
+
+	s.recordCodeLogin(ctx, "user.login", user, method)
+	return &pb.LoginReply{
+		Email:    user.Email,
+		Username: user.Username,
+		Token:    token,
+	}, nil
+}
+
+// บันทึกการเข้าสู่ระบบทางอีเมลลง audit log (method = email_code หรือ magic_link)
+func (s *AuthService) recordCodeLogin(ctx context.Context, action string, user *models.User, method string) {
+	details := requestDetails(ctx)
+	details["method"] = method
+	s.Audit.Record(ctx, models.AuditEvent{
+		TenantID:     user.TenantID,
+		Action:       action,
+		ActorEmail:   user.Email,
+		SubjectID:    user.ID.Hex(),
+		SubjectEmail: user.Email,
+		Details:      details,
+	})
+}
+
+// key ที่ชี้ไปยังคำขอล่าสุดของอีเมลใน tenant
+func loginCodeIndexKey(tenantID string, email string) string {
+	return "login_code_email:" + hashAPIKey(tenantID+":"+strings.ToLower(email))
+}
+
+// รหัสตัวเลขแบบสุ่ม (มีเลข 0 นำหน้าได้)
+func generateNumericCode(digits int) (string, error) {
+	max := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(digits)), nil)
+	n, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%0*d", digits, n), nil
+}
+
+// key ที่ใช้เซ็น magic link แยกจาก signing key ของ JWT (HMAC-SHA256 ของ signing key กับ label "magic-link")
+// ลายเซ็นของ magic link จึงนำไปใช้เป็นลายเซ็นของ token ไม่ได้ และกลับกัน
+func magicLinkKey(secret []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte("magic-link"))
+	return mac.Sum(nil)
+}
+
+// magic link: payload (JSON) และ HMAC-SHA256 ของ payload เข้ารหัสแบบ base64url คั่นด้วยจุด
+func signMagicLink(tenant *models.Tenant, id string, expiresAt time.Time) (string, error) {
+	keyID, secret, err := signingKey(tenant)
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(magicLinkClaims{TenantID: tenant.ID, ID: id, KeyID: keyID, ExpiresAt: expiresAt.Unix()})
+	if err != nil {
+		return "", err
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	mac := hmac.New(sha256.New, magicLinkKey(secret))
+	mac.Write([]byte(encoded))
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// ตรวจสอบลายเซ็น (ด้วย key ที่ระบุใน link) และวันหมดอายุของ magic link
+func verifyMagicLink(ctx context.Context, tenants store.TenantStore, token string) (*magicLinkClaims, error) {
+	invalid := status.Error(codes.Unauthenticated, "ลิงก์ไม่ถูกต้องหรือหมดอายุ")
+	encoded, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, invalid
+	}
+	payload, err1 := base64.RawURLEncoding.DecodeString(encoded)
+	signature, err2 := base64.RawURLEncoding.DecodeString(sig)
+	var claims magicLinkClaims
+	if err1 != nil || err2 != nil || json.Unmarshal(payload, &claims) != nil {
+		return nil, invalid
+	}
+	secret, err := tenantKeys(ctx, tenants)(claims.TenantID, claims.KeyID)
+	if err != nil {
+		return nil, invalid
+	}
+	mac := hmac.New(sha256.New, magicLinkKey(secret))
+	mac.Write([]byte(encoded))
+	if !hmac.Equal(signature, mac.Sum(nil)) || time.Now().Unix() >= claims.ExpiresAt {
+		return nil, invalid
+	}
+	return &claims, nil
+}
@@ -0,0 +1,248 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	pb "auth-microservice/auth-microservice/proto"
+	"auth-microservice/internal/audit"
+	"auth-microservice/internal/auth"
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/store"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+)
+
+var (
+	loginCodePattern = regexp.MustCompile(`รหัสเข้าสู่ระบบของคุณคือ (\d{6})`)
+	magicLinkPattern = regexp.MustCompile(`token=([A-Za-z0-9_.-]+)`)
+)
+
+// AuthService ที่เก็บอีเมลที่ส่งไว้ พร้อมผู้ใช้ alice@example.com ใน tenant default
+type loginCodeFixture struct {
+	stores   *store.Stores
+	service  *AuthService
+	notifier *recordingNotifier
+}
+
+func newLoginCodeFixture(t *testing.T) *loginCodeFixture {
+	t.Helper()
+	stores := newTestStores(t)
+	f := &loginCodeFixture{stores: stores, notifier: &recordingNotifier{}}
+	f.service = NewAuthService(stores, f.notifier, audit.NewLogger(stores.Audit))
+	if _, err := f.service.Register(context.Background(), &pb.RegisterRequest{Email: "alice@example.com", Username: "alice", Password: testPassword}); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	return f
+}
+
+// ขอรหัสเข้าสู่ระบบ แล้วคืน device token พร้อมรหัสและ magic token จากอีเมล
+func (f *loginCodeFixture) request(t *testing.T, email string) (deviceToken string, code string, magicToken string) {
+	t.Helper()
+	reply, err := f.service.RequestLoginCode(context.Background(), &pb.RequestLoginCodeRequest{Email: email})
+	if err != nil {
+		t.Fatalf("RequestLoginCode: %v", err)
+	}
+	body := f.notifier.last(t, email).Body
+	codeMatch, linkMatch := loginCodePattern.FindStringSubmatch(body), magicLinkPattern.FindStringSubmatch(body)
+	if codeMatch == nil || linkMatch == nil {
+		t.Fatalf("login code email = %q", body)
+	}
+	// แต่ละคำขอนับเป็นหนึ่งครั้งของ rate limit เดียวกับ Login จึงล้างตัวนับเพื่อทดสอบหลายคำขอได้
+	f.stores.Cache.Delete(context.Background(), loginAttemptKey(models.DefaultTenantID, email))
+	return reply.GetDeviceToken(), codeMatch[1], linkMatch[1]
+}
+
+func (f *loginCodeFixture) verifyLink(magicToken string, deviceToken string) (*pb.LoginReply, error) {
+	return f.service.VerifyLoginCode(context.Background(), &pb.VerifyLoginCodeRequest{MagicToken: magicToken, DeviceToken: deviceToken})
+}
+
+func (f *loginCodeFixture) tenant(t *testing.T, id string) *models.Tenant {
+	t.Helper()
+	tenant, err := loadTenant(context.Background(), f.stores.Tenants, id)
+	if err != nil {
+		t.Fatalf("loadTenant(%s): %v", id, err)
+	}
+	return tenant
+}
+
+// claims ใน magic token (ไม่ตรวจลายเซ็น)
+func magicLinkPayload(t *testing.T, token string) magicLinkClaims {
+	t.Helper()
+	encoded, _, _ := strings.Cut(token, ".")
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	var claims magicLinkClaims
+	if err != nil || json.Unmarshal(payload, &claims) != nil {
+		t.Fatalf("magic token payload %q: %v", encoded, err)
+	}
+	return claims
+}
+
+func TestLoginCodeSignsInOnce(t *testing.T) {
+	f := newLoginCodeFixture(t)
+	deviceToken, code, _ := f.request(t, "alice@example.com")
+
+	in := &pb.VerifyLoginCodeRequest{Email: "alice@example.com", Code: code, DeviceToken: deviceToken}
+	reply, err := f.service.VerifyLoginCode(context.Background(), in)
+	if err != nil || reply.GetToken() == "" || reply.GetEmail() != "alice@example.com" {
+		t.Fatalf("VerifyLoginCode = %+v, %v", reply, err)
+	}
+	_, err = f.service.VerifyLoginCode(context.Background(), in)
+	wantCode(t, "VerifyLoginCode with a used code", err, codes.Unauthenticated)
+}
+
+func TestLoginCodeRejectsWrongCodes(t *testing.T) {
+	f := newLoginCodeFixture(t)
+	deviceToken, code, _ := f.request(t, "alice@example.com")
+	wrong := "000000"
+	if code == wrong {
+		wrong = "111111"
+	}
+
+	_, err := f.service.VerifyLoginCode(context.Background(), &pb.VerifyLoginCodeRequest{Email: "alice@example.com", Code: code, DeviceToken: "other-device"})
+	wantCode(t, "VerifyLoginCode from another device", err, codes.PermissionDenied)
+
+	// ใส่รหัสผิดครบกำหนดแล้วรหัสที่ถูกต้องก็ใช้ไม่ได้
+	for i := 0; i < loginCodeMaxAttempts; i++ {
+		f.stores.Cache.Delete(context.Background(), loginAttemptKey(models.DefaultTenantID, "alice@example.com"))
+		_, err := f.service.VerifyLoginCode(context.Background(), &pb.VerifyLoginCodeRequest{Email: "alice@example.com", Code: wrong, DeviceToken: deviceToken})
+		wantCode(t, "VerifyLoginCode with a wrong code", err, codes.Unauthenticated)
+	}
+	f.stores.Cache.Delete(context.Background(), loginAttemptKey(models.DefaultTenantID, "alice@example.com"))
+	_, err = f.service.VerifyLoginCode(context.Background(), &pb.VerifyLoginCodeRequest{Email: "alice@example.com", Code: code, DeviceToken: deviceToken})
+	wantCode(t, "VerifyLoginCode after too many wrong codes", err, codes.Unauthenticated)
+}
+
+func TestLoginCodeForUnknownEmail(t *testing.T) {
+	f := newLoginCodeFixture(t)
+	sent := f.notifier.count()
+	reply, err := f.service.RequestLoginCode(context.Background(), &pb.RequestLoginCodeRequest{Email: "nobody@example.com"})
+	// ตอบเหมือนกันทุกอีเมลเพื่อไม่บอกว่ามีบัญชีหรือไม่
+	if err != nil || reply.GetDeviceToken() == "" {
+		t.Fatalf("RequestLoginCode for an unknown email = %+v, %v", reply, err)
+	}
+	if f.notifier.count() != sent {
+		t.Fatal("RequestLoginCode sent an email for an unknown address")
+	}
+}
+
+func TestMagicLinkSignsInOnce(t *testing.T) {
+	f := newLoginCodeFixture(t)
+	deviceToken, _, magicToken := f.request(t, "alice@example.com")
+
+	_, err := f.verifyLink(magicToken, "other-device")
+	wantCode(t, "magic link from another device", err, codes.PermissionDenied)
+
+	reply, err := f.verifyLink(magicToken, deviceToken)
+	if err != nil || reply.GetToken() == "" || reply.GetEmail() != "alice@example.com" {
+		t.Fatalf("VerifyLoginCode with a magic link = %+v, %v", reply, err)
+	}
+	_, err = f.verifyLink(magicToken, deviceToken)
+	wantCode(t, "magic link used twice", err, codes.Unauthenticated)
+}
+
+func TestMagicLinkIsReplacedByNewRequest(t *testing.T) {
+	f := newLoginCodeFixture(t)
+	deviceToken, _, oldLink := f.request(t, "alice@example.com")
+	newDevice, _, newLink := f.request(t, "alice@example.com")
+
+	_, err := f.verifyLink(oldLink, deviceToken)
+	wantCode(t, "magic link of a replaced request", err, codes.Unauthenticated)
+	if _, err := f.verifyLink(newLink, newDevice); err != nil {
+		t.Fatalf("VerifyLoginCode with the latest magic link: %v", err)
+	}
+}
+
+func TestMagicLinkRejectsExpiredAndTamperedLinks(t *testing.T) {
+	f := newLoginCodeFixture(t)
+	deviceToken, _, magicToken := f.request(t, "alice@example.com")
+	claims := magicLinkPayload(t, magicToken)
+	tenant := f.tenant(t, models.DefaultTenantID)
+	keyID, secret, err := signingKey(tenant)
+	if err != nil {
+		t.Fatalf("signingKey: %v", err)
+	}
+
+	expired, err := signMagicLink(tenant, claims.ID, time.Now().Add(-time.Second))
+	if err != nil {
+		t.Fatalf("signMagicLink: %v", err)
+	}
+	encoded, sig, _ := strings.Cut(magicToken, ".")
+	// payload อื่นที่ใช้ลายเซ็นเดิม
+	otherPayload, _ := json.Marshal(magicLinkClaims{TenantID: claims.TenantID, ID: claims.ID, KeyID: claims.KeyID, ExpiresAt: claims.ExpiresAt + 3600})
+	extended := base64.RawURLEncoding.EncodeToString(otherPayload) + "." + sig
+	// เซ็นด้วย signing key ของ JWT โดยตรง (ต้องใช้ key ที่แยกไว้สำหรับ magic link เท่านั้น)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(encoded))
+	jwtKeySigned := encoded + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+	flipped, _ := base64.RawURLEncoding.DecodeString(sig)
+	flipped[0] ^= 1
+	// JWT ที่เซ็นด้วย key เดียวกันใช้แทน magic link ไม่ได้
+	jwtToken, err := auth.GenerateJWT("alice@example.com", "user", models.DefaultTenantID, "jti-1", keyID, secret, time.Hour, nil)
+	if err != nil {
+		t.Fatalf("GenerateJWT: %v", err)
+	}
+
+	for name, token := range map[string]string{
+		"expired":             expired,
+		"extended expiry":     extended,
+		"flipped signature":   encoded + "." + base64.RawURLEncoding.EncodeToString(flipped),
+		"missing signature":   encoded,
+		"signed with JWT key": jwtKeySigned,
+		"JWT":                 jwtToken,
+	} {
+		_, err := f.verifyLink(token, deviceToken)
+		wantCode(t, "magic link "+name, err, codes.Unauthenticated)
+	}
+
+	// ลิงก์เดิมยังใช้ได้หลังความพยายามที่ล้มเหลว
+	if _, err := f.verifyLink(magicToken, deviceToken); err != nil {
+		t.Fatalf("VerifyLoginCode with the original magic link: %v", err)
+	}
+}
+
+func TestMagicLinkAcrossTenants(t *testing.T) {
+	f := newLoginCodeFixture(t)
+	ctx := context.Background()
+	key, err := newSigningKey(time.Now())
+	if err != nil {
+		t.Fatalf("newSigningKey: %v", err)
+	}
+	acme := &models.Tenant{ID: "acme", Name: "Acme", Status: models.TenantActive, SigningKeys: []models.SigningKey{key}, CreatedAt: time.Now()}
+	if err := f.stores.Tenants.CreateTenant(ctx, acme); err != nil {
+		t.Fatalf("CreateTenant: %v", err)
+	}
+	deviceToken, _, magicToken := f.request(t, "alice@example.com")
+	claims := magicLinkPayload(t, magicToken)
+	_, sig, _ := strings.Cut(magicToken, ".")
+
+	// ลิงก์ที่ tenant อื่นเซ็นให้คำขอของ tenant default
+	acmeSigned, err := signMagicLink(acme, claims.ID, time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("signMagicLink: %v", err)
+	}
+	// เปลี่ยน tenant ในลิงก์โดยใช้ลายเซ็นเดิม
+	moved, _ := json.Marshal(magicLinkClaims{TenantID: acme.ID, ID: claims.ID, KeyID: acme.SigningKeys[0].ID, ExpiresAt: claims.ExpiresAt})
+	for name, token := range map[string]string{
+		"signed by another tenant":       acmeSigned,
+		"tenant replaced in the payload": base64.RawURLEncoding.EncodeToString(moved) + "." + sig,
+	} {
+		_, err := f.verifyLink(token, deviceToken)
+		wantCode(t, "magic link "+name, err, codes.Unauthenticated)
+	}
+
+	// tenant ของลิงก์มาจากลิงก์เอง x-tenant-id ของ request ไม่มีผล
+	acmeCtx := metadata.NewIncomingContext(ctx, metadata.Pairs(auth.TenantMetadataKey, acme.ID))
+	reply, err := f.service.VerifyLoginCode(acmeCtx, &pb.VerifyLoginCodeRequest{MagicToken: magicToken, DeviceToken: deviceToken})
+	if err != nil || reply.GetEmail() != "alice@example.com" {
+		t.Fatalf("VerifyLoginCode with x-tenant-id of another tenant = %+v, %v", reply, err)
+	}
+}
@@ -0,0 +1,367 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	pb "auth-microservice/auth-microservice/proto"
+	"auth-microservice/internal/auth"
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/store"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	authorizationRequestTTL = 10 * time.Minute // เวลาที่ผู้ใช้มีในการเข้าสู่ระบบและให้ความยินยอม
+	authorizationCodeTTL    = time.Minute      // authorization code ต้องแลกเป็น token ภายในเวลานี้
+	pkceChallengeLength     = 43               // ความยาวของ SHA-256 ที่เข้ารหัสแบบ base64url
+)
+
+// authorization request ที่ผ่านการตรวจสอบแล้ว เก็บใน cache ภายใต้ "oauth_request:<id>" ระหว่างรอผู้ใช้ตัดสินใจ
+type authorizationRequest struct {
+	ClientID      string    `json:"clientId"`
+	TenantID      string    `json:"tenantId"`
+	RedirectURI   string    `json:"redirectUri"`
+	Scopes        []string  `json:"scopes"`
+	State         string    `json:"state"`
+	Nonce         string    `json:"nonce"`
+	CodeChallenge string    `json:"codeChallenge"`
+	ExpiresAt     time.Time `json:"expiresAt"`
+}
+
+// สิทธิ์ที่ผู้ใช้ให้ client ผูกอยู่กับ authorization code และ refresh token
+type userGrant struct {
+	ClientID    string    `json:"clientId"`
+	TenantID    string    `json:"tenantId"`
+	UserID      string    `json:"userId"`
+	Scopes      []string  `json:"scopes"`
+	GrantID     string    `json:"grantId"` // ต้องตรงกับความยินยอมปัจจุบันของผู้ใช้
+	AuthTime    time.Time `json:"authTime"`
+	Nonce       string    `json:"nonce,omitempty"`
+	RedirectURI string    `json:"redirectUri,omitempty"` // เฉพาะ authorization code
+	Challenge   string    `json:"challenge,omitempty"`   // เฉพาะ authorization code
+	ExpiresAt   time.Time `json:"expiresAt"`
+}
+
+func (s *OAuthService) Authorize(ctx context.Context, in *pb.AuthorizeRequest) (*pb.AuthorizeReply, error) {
+	// client_id หรือ redirect_uri ที่ไม่ถูกต้องต้องไม่ redirect กลับ (กัน open redirect)
+	client, err := s.OAuthClients.GetOAuthClientByClientID(ctx, in.GetClientId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "client_id ไม่ถูกต้อง")
+	}
+	redirectURI := in.GetRedirectUri()
+	if redirectURI == "" && len(client.RedirectURIs) == 1 {
+		redirectURI = client.RedirectURIs[0]
+	}
+	if !hasScope(client.RedirectURIs, redirectURI) {
+		return nil, status.Error(codes.InvalidArgument, "redirect_uri ไม่ตรงกับที่ลงทะเบียนไว้")
+	}
+
+	// จากนี้ error ส่งกลับไปยัง client ผ่าน redirect_uri
+	fail := func(code string, description string) (*pb.AuthorizeReply, error) {
+		return &pb.AuthorizeReply{
+			RedirectTo: s.redirectURL(redirectURI, map[string]string{"error": code, "error_description": description, "state": in.GetState()}),
+		}, nil
+	}
+	if in.GetResponseType() != "code" {
+		return fail("unsupported_response_type", "รองรับเฉพาะ response_type=code")
+	}
+	if in.GetCodeChallengeMethod() != "S256" || len(in.GetCodeChallenge()) != pkceChallengeLength {
+		return fail("invalid_request", "ต้องใช้ PKCE (code_challenge แบบ S256)")
+	}
+	scopes := uniqueStrings(strings.Fields(in.GetScope()))
+	if len(scopes) == 0 {
+		return fail("invalid_scope", "ต้องระบุ scope")
+	}
+	for _, scope := range scopes {
+		if !hasScope(client.Scopes, scope) {
+			return fail("invalid_scope", "client นี้ขอ scope "+scope+" ไม่ได้")
+		}
+	}
+	if _, err := activeTenant(ctx, s.Tenants, client.TenantID); err != nil {
+		return fail("access_denied", "tenant ของ client นี้ถูกระงับการใช้งาน")
+	}
+
+	requestID, err := generateRandomToken(32)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถสร้าง authorization request ได้")
+	}
+	req := authorizationRequest{
+		ClientID:      client.ClientID,
+		TenantID:      client.TenantID,
+		RedirectURI:   redirectURI,
+		Scopes:        scopes,
+		State:         in.GetState(),
+		Nonce:         in.GetNonce(),
+		CodeChallenge: in.GetCodeChallenge(),
+		ExpiresAt:     time.Now().Add(authorizationRequestTTL),
+	}
+	if err := setJSON(ctx, s.Cache, "oauth_request:"+requestID, req, authorizationRequestTTL); err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถบันทึก authorization request ได้")
+	}
+	return &pb.AuthorizeReply{RequestId: requestID}, nil
+}
+
+func (s *OAuthService) GetAuthorizationRequest(ctx context.Context, in *pb.GetAuthorizationRequestRequest) (*pb.AuthorizationRequestInfo, error) {
+	req, err := s.loadAuthorizationRequest(ctx, in.GetRequestId())
+	if err != nil {
+		return nil, err
+	}
+	client, err := s.OAuthClients.GetOAuthClientByClientID(ctx, req.ClientID)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, "ไม่พบ client ของคำขอนี้")
+	}
+
+	info := &pb.AuthorizationRequestInfo{
+		RequestId:   in.GetRequestId(),
+		ClientId:    client.ClientID,
+		ClientName:  client.Name,
+		Scopes:      req.Scopes,
+		RedirectUri: req.RedirectURI,
+		Trusted:     client.Trusted,
+		ExpiresAt:   req.ExpiresAt.Format(time.RFC3339),
+	}
+	// ผู้ใช้ที่เข้าสู่ระบบอยู่แล้วและเคยยินยอมครบทุก scope ไม่ต้องเห็นหน้าขอความยินยอมอีก
+	if _, err := auth.TokenFromContext(ctx); err == nil {
+		if user, err := s.tokenUser(ctx, req.TenantID); err == nil {
+			consent, err := s.OAuthClients.GetConsent(ctx, req.TenantID, user.ID.Hex(), client.ClientID)
+			info.ConsentGranted = err == nil && containsAll(consent.Scopes, req.Scopes)
+		}
+	}
+	return info, nil
+}
+
+func (s *OAuthService) DecideAuthorization(ctx context.Context, in *pb.DecideAuthorizationRequest) (*pb.DecideAuthorizationReply, error) {
+	req, err := s.loadAuthorizationRequest(ctx, in.GetRequestId())
+	if err != nil {
+		return nil, err
+	}
+	client, err := s.OAuthClients.GetOAuthClientByClientID(ctx, req.ClientID)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, "ไม่พบ client ของคำขอนี้")
+	}
+
+	// ยืนยันตัวตนก่อนใช้คำขอ (ใส่รหัสผ่านผิดแล้วลองใหม่ได้จนกว่าคำขอจะหมดอายุ)
+	var user *models.User
+	if in.GetEmail() != "" {
+		user, err = s.passwordUser(ctx, req.TenantID, in.GetEmail(), in.GetPassword(), client.ClientID)
+	} else {
+		user, err = s.tokenUser(ctx, req.TenantID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	granted := req.Scopes
+	if len(in.GetScopes()) > 0 {
+		granted = uniqueStrings(in.GetScopes())
+		if !containsAll(req.Scopes, granted) {
+			return nil, status.Error(codes.InvalidArgument, "อนุญาตได้เฉพาะ scope ที่ client ขอ")
+		}
+	}
+
+	// authorization request ใช้ได้ครั้งเดียว
+	if ok, err := consumeOnce(ctx, s.Cache, "oauth_request_used:"+in.GetRequestId(), authorizationRequestTTL); err != nil || !ok {
+		return nil, status.Error(codes.NotFound, "คำขอนี้ไม่ถูกต้องหรือหมดอายุแล้ว")
+	}
+	s.Cache.Delete(ctx, "oauth_request:"+in.GetRequestId())
+
+	if !in.GetApprove() {
+		s.recordOAuthEvent(ctx, "oauth.consent_denied", user, client.ClientID, map[string]interface{}{"scopes": req.Scopes})
+		return &pb.DecideAuthorizationReply{
+			RedirectTo: s.redirectURL(req.RedirectURI, map[string]string{"error": "access_denied", "error_description": "ผู้ใช้ไม่อนุญาต", "state": req.State}),
+		}, nil
+	}
+
+	consent, err := s.saveConsent(ctx, user, client.ClientID, granted)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถบันทึกความยินยอมได้")
+	}
+
+	code, err := generateRandomToken(32)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถสร้าง authorization code ได้")
+	}
+	now := time.Now()
+	grant := userGrant{
+		ClientID:    client.ClientID,
+		TenantID:    req.TenantID,
+		UserID:      user.ID.Hex(),
+		Scopes:      granted,
+		GrantID:     consent.GrantID,
+		AuthTime:    now,
+		Nonce:       req.Nonce,
+		RedirectURI: req.RedirectURI,
+		Challenge:   req.CodeChallenge,
+		ExpiresAt:   now.Add(authorizationCodeTTL),
+	}
+	if err := setJSON(ctx, s.Cache, "oauth_code:"+hashAPIKey(code), grant, authorizationCodeTTL); err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถบันทึก authorization code ได้")
+	}
+
+	s.recordOAuthEvent(ctx, "oauth.consent_granted", user, client.ClientID, map[string]interface{}{"scopes": granted})
+	return &pb.DecideAuthorizationReply{
+		RedirectTo: s.redirectURL(req.RedirectURI, map[string]string{"code": code, "state": req.State}),
+	}, nil
+}
+
+func (s *OAuthService) loadAuthorizationRequest(ctx context.Context, requestID string) (*authorizationRequest, error) {
+	var req authorizationRequest
+	if requestID == "" || getJSON(ctx, s.Cache, "oauth_request:"+requestID, &req) != nil {
+		return nil, status.Error(codes.NotFound, "คำขอนี้ไม่ถูกต้องหรือหมดอายุแล้ว")
+	}
+	return &req, nil
+}
+
+// ผู้ใช้ที่ยืนยันตัวตนด้วยอีเมลและรหัสผ่านใน tenant ของ client (ใช้ rate limit เดียวกับ Login)
+func (s *OAuthService) passwordUser(ctx context.Context, tenantID string, email string, password string, clientID string) (*models.User, error) {
+	isLimited, err := loginRateLimited(ctx, s.Cache, tenantID, email)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถตรวจสอบ Rate Limit ได้")
+	}
+	if isLimited {
+		return nil, status.Error(codes.ResourceExhausted, "คุณพยายามเข้าสู่ระบบบ่อยเกินไป กรุณารอ 1 นาที")
+	}
+
+	user, err := s.Users.GetUserByEmail(ctx, tenantID, email)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "อีเมลหรือรหัสผ่านไม่ถูกต้อง")
+	}
+	if auth.ComparePassword(user.Password, password) != nil {
+		loginRateLimited(ctx, s.Cache, tenantID, email)
+		s.recordOAuthEvent(ctx, "user.login_failed", user, clientID, nil)
+		return nil, status.Error(codes.Unauthenticated, "อีเมลหรือรหัสผ่านไม่ถูกต้อง")
+	}
+	// รหัสผ่านอย่างเดียวไม่พอสำหรับผู้ใช้ที่บังคับใช้ passkey ต้องเข้าสู่ระบบด้วย passkey แล้วส่ง token มาแทน
+	if user.PasskeyRequired {
+		return nil, status.Error(codes.FailedPrecondition, "บัญชีนี้ต้องยืนยันตัวตนด้วย passkey กรุณาเข้าสู่ระบบด้วย passkey แล้วส่ง token แทนอีเมลและรหัสผ่าน")
+	}
+	s.recordOAuthEvent(ctx, "user.login", user, clientID, nil)
+	return user, nil
+}
+
+// ผู้ใช้เจ้าของ token ใน metadata (token ต้องเป็นของผู้ใช้ใน tenant ของ client ไม่ใช่ token ที่ออกให้ client อื่น)
+func (s *OAuthService) tokenUser(ctx context.Context, tenantID string) (*models.User, error) {
+	tokenStr, err := auth.TokenFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	claims, err := verifyToken(ctx, s.Blacklist, s.Tenants, tokenStr)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := claims[auth.ClientIDClaim]; ok {
+		return nil, status.Error(codes.PermissionDenied, "ต้องใช้ token จากการเข้าสู่ระบบของผู้ใช้")
+	}
+	if claimsTenant(claims) != tenantID {
+		return nil, status.Error(codes.PermissionDenied, "โทเค็นนี้ใช้กับ tenant อื่นไม่ได้")
+	}
+	email, _ := claims["email"].(string)
+	user, err := s.Users.GetUserByEmail(ctx, tenantID, email)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "ไม่พบผู้ใช้")
+	}
+	return user, nil
+}
+
+// บันทึกความยินยอม: รวม scope กับที่เคยให้ไว้และคง GrantID เดิม (refresh token เดิมยังใช้ได้)
+func (s *OAuthService) saveConsent(ctx context.Context, user *models.User, clientID string, scopes []string) (*models.OAuthConsent, error) {
+	now := time.Now()
+	consent, err := s.OAuthClients.GetConsent(ctx, user.TenantID, user.ID.Hex(), clientID)
+	if errors.Is(err, store.ErrNotFound) {
+		grantID, genErr := generateRandomToken(16)
+		if genErr != nil {
+			return nil, genErr
+		}
+		consent, err = &models.OAuthConsent{
+			TenantID:  user.TenantID,
+			UserID:    user.ID,
+			ClientID:  clientID,
+			GrantID:   grantID,
+			CreatedAt: now,
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	consent.Scopes = uniqueStrings(append(consent.Scopes, scopes...))
+	consent.UpdatedAt = now
+	if err := s.OAuthClients.SaveConsent(ctx, consent); err != nil {
+		return nil, err
+	}
+	return consent, nil
+}
+
+// URL สำหรับ redirect กลับไปยัง client พร้อมพารามิเตอร์ (ค่าว่างไม่ใส่) และ iss ตาม RFC 9207
+func (s *OAuthService) redirectURL(redirectURI string, params map[string]string) string {
+	u, err := url.Parse(redirectURI)
+	if err != nil {
+		return redirectURI
+	}
+	q := u.Query()
+	for k, v := range params {
+		if v != "" {
+			q.Set(k, v)
+		}
+	}
+	q.Set("iss", s.Issuer)
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// บันทึกเหตุการณ์ของผู้ใช้กับ OAuth client ลง audit log
+func (s *OAuthService) recordOAuthEvent(ctx context.Context, action string, user *models.User, clientID string, details map[string]interface{}) {
+	if details == nil {
+		details = requestDetails(ctx)
+	}
+	details["clientId"] = clientID
+	s.Audit.Record(ctx, models.AuditEvent{
+		TenantID:     user.TenantID,
+		Action:       action,
+		ActorEmail:   user.Email,
+		SubjectID:    user.ID.Hex(),
+		SubjectEmail: user.Email,
+		Details:      details,
+	})
+}
+
+// ทำเครื่องหมายว่า key ถูกใช้แล้ว คืน false ถ้าเคยถูกใช้ไปก่อนหน้า (ใช้ทำ code และ token ที่ใช้ได้ครั้งเดียว)
+func consumeOnce(ctx context.Context, cache store.KeyValueStore, key string, ttl time.Duration) (bool, error) {
+	uses, err := cache.Incr(ctx, key, ttl)
+	if err != nil {
+		return false, err
+	}
+	return uses == 1, nil
+}
+
+func setJSON(ctx context.Context, cache store.KeyValueStore, key string, value interface{}, ttl time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return cache.Set(ctx, key, string(data), ttl)
+}
+
+func getJSON(ctx context.Context, cache store.KeyValueStore, key string, value interface{}) error {
+	data, err := cache.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal([]byte(data), value)
+}
+
+// values มีครบทุกค่าใน wanted
+func containsAll(values []string, wanted []string) bool {
+	for _, w := range wanted {
+		if !hasScope(values, w) {
+			return false
+		}
+	}
+	return true
+}
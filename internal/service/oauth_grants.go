@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	pb "auth-microservice/auth-microservice/proto"
+	"auth-microservice/internal/auth"
+	models "auth-microservice/internal/model"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	refreshTokenTTL    = 30 * 24 * time.Hour // นับใหม่ทุกครั้งที่ refresh
+	refreshTokenPrefix = "rt_"
+)
+
+// แลก authorization code เป็น token (RFC 6749 ข้อ 4.1.3) ต้องส่ง code_verifier ที่ตรงกับ code_challenge
+func (s *OAuthService) authorizationCodeToken(ctx context.Context, in *pb.TokenRequest) (*pb.TokenReply, error) {
+	client, err := s.authenticateOAuthClient(ctx, in.GetClientId(), in.GetClientSecret())
+	if err != nil {
+		return nil, err
+	}
+	if in.GetCode() == "" || in.GetCodeVerifier() == "" {
+		return nil, status.Error(codes.InvalidArgument, "ต้องระบุ code และ code_verifier")
+	}
+
+	key := hashAPIKey(in.GetCode())
+	var grant userGrant
+	if getJSON(ctx, s.Cache, "oauth_code:"+key, &grant) != nil {
+		return nil, errInvalidGrant
+	}
+	// code ใช้ได้ครั้งเดียว แม้ตรวจสอบไม่ผ่าน
+	if ok, err := consumeOnce(ctx, s.Cache, "oauth_code_used:"+key, authorizationCodeTTL); err != nil || !ok {
+		return nil, errInvalidGrant
+	}
+	s.Cache.Delete(ctx, "oauth_code:"+key)
+
+	if grant.ClientID != client.ClientID || grant.RedirectURI != in.GetRedirectUri() || !auth.VerifyPKCE(in.GetCodeVerifier(), grant.Challenge) {
+		return nil, errInvalidGrant
+	}
+	return s.issueUserTokens(ctx, client, grant, grant.Scopes, grantTypeAuthorizationCode)
+}
+
+// แลก refresh token เป็น token ชุดใหม่ refresh token เดิมใช้ไม่ได้อีก
+// ถ้ามีการนำ refresh token ที่ใช้ไปแล้วมาใช้ซ้ำ (อาจถูกขโมย) จะยกเลิกความยินยอมทั้งหมดของผู้ใช้กับ client นี้
+func (s *OAuthService) refreshToken(ctx context.Context, in *pb.TokenRequest) (*pb.TokenReply, error) {
+	client, err := s.authenticateOAuthClient(ctx, in.GetClientId(), in.GetClientSecret())
+	if err != nil {
+		return nil, err
+	}
+	grant, key, err := s.loadRefreshToken(ctx, in.GetRefreshToken())
+	if err != nil || grant.ClientID != client.ClientID {
+		return nil, errInvalidGrant
+	}
+
+	ok, err := consumeOnce(ctx, s.Cache, "oauth_refresh_used:"+key, time.Until(grant.ExpiresAt))
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถตรวจสอบ refresh token ได้")
+	}
+	if !ok {
+		s.OAuthClients.DeleteConsent(ctx, grant.TenantID, grant.UserID, grant.ClientID)
+		s.Audit.Record(ctx, models.AuditEvent{
+			TenantID:   grant.TenantID,
+			Action:     "oauth.refresh_token_reused",
+			ActorEmail: client.ClientID,
+			SubjectID:  grant.UserID,
+			Details:    map[string]interface{}{"clientId": client.ClientID},
+		})
+		return nil, errInvalidGrant
+	}
+
+	// scope ที่ขอต้องเป็นส่วนหนึ่งของ scope เดิม (ไม่ระบุ = scope เดิมทั้งหมด)
+	scopes := grant.Scopes
+	if requested := uniqueStrings(strings.Fields(in.GetScope())); len(requested) > 0 {
+		if !containsAll(grant.Scopes, requested) {
+			return nil, status.Error(codes.PermissionDenied, "ขอ scope เกินกว่าที่ผู้ใช้อนุญาตไม่ได้")
+		}
+		scopes = requested
+	}
+	return s.issueUserTokens(ctx, client, *grant, scopes, grantTypeRefreshToken)
+}
+
+// ยืนยันตัวตนของ OAuth client: public client ต้องไม่ส่ง secret ส่วน confidential client ต้องส่ง secret ที่ถูกต้อง
+func (s *OAuthService) authenticateOAuthClient(ctx context.Context, clientID string, secret string) (*models.OAuthClient, error) {
+	if clientID == "" {
+		return nil, errInvalidClient
+	}
+	client, err := s.OAuthClients.GetOAuthClientByClientID(ctx, clientID)
+	if err != nil {
+		return nil, errInvalidClient
+	}
+	if client.Public {
+		if secret != "" {
+			return nil, errInvalidClient
+		}
+		return client, nil
+	}
+	if secret == "" || !matchClientSecret(client.Secrets, secret) {
+		return nil, errInvalidClient
+	}
+	return client, nil
+}
+
+// grant ของ refresh token คืน hash ของ token ไว้ใช้เป็น key ด้วย
+func (s *OAuthService) loadRefreshToken(ctx context.Context, token string) (*userGrant, string, error) {
+	if !strings.HasPrefix(token, refreshTokenPrefix) {
+		return nil, "", errInvalidGrant
+	}
+	key := hashAPIKey(token)
+	var grant userGrant
+	if err := getJSON(ctx, s.Cache, "oauth_refresh:"+key, &grant); err != nil {
+		return nil, "", errInvalidGrant
+	}
+	return &grant, key, nil
+}
+
+// ออก access token (และ ID token / refresh token ตาม scope) ให้ client ในนามของผู้ใช้
+// ผู้ใช้ต้องยังอยู่ และความยินยอมต้องยังเป็นชุดเดียวกับตอนที่ออก grant (ถูกยกเลิกแล้ว = ใช้ไม่ได้)
+func (s *OAuthService) issueUserTokens(ctx context.Context, client *models.OAuthClient, grant userGrant, scopes []string, grantType string) (*pb.TokenReply, error) {
+	tenant, err := activeTenant(ctx, s.Tenants, grant.TenantID)
+	if status.Code(err) == codes.Internal {
+		return nil, err
+	}
+	if err != nil {
+		return nil, errInvalidGrant
+	}
+	user, err := s.Users.GetUserByID(ctx, grant.UserID, false)
+	if err != nil || user.TenantID != tenant.ID {
+		return nil, errInvalidGrant
+	}
+	consent, err := s.OAuthClients.GetConsent(ctx, tenant.ID, grant.UserID, client.ClientID)
+	if err != nil || consent.GrantID != grant.GrantID {
+		return nil, errInvalidGrant
+	}
+
+	scope := strings.Join(scopes, " ")
+	jti, err := generateRandomToken(16)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถสร้าง Token ได้")
+	}
+	keyID, secret, err := signingKey(tenant)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถสร้าง Token ได้")
+	}
+	accessToken, err := auth.GenerateDelegatedJWT(user.ID.Hex(), user.Email, user.Role, tenant.ID, client.ClientID, scope, jti, keyID, secret, tenant.AccessTokenTTL)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถสร้าง Token ได้")
+	}
+	reply := &pb.TokenReply{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(tenant.AccessTokenTTL / time.Second),
+		Scope:       scope,
+	}
+
+	now := time.Now()
+	if hasScope(scopes, models.ScopeOpenID) {
+		reply.IdToken, err = s.idToken(ctx, client, user, tenant, grant, scopes, accessToken, now)
+		if err != nil {
+			return nil, status.Error(codes.Internal, "ไม่สามารถสร้าง ID token ได้")
+		}
+	}
+
+	// refresh token ได้เฉพาะเมื่อผู้ใช้อนุญาต offline_access และจำ scope ทั้งหมดของ grant ไว้ (ไม่ใช่เฉพาะที่ขอครั้งนี้)
+	if hasScope(grant.Scopes, models.ScopeOfflineAccess) {
+		token, err := generateRandomToken(32)
+		if err != nil {
+			return nil, status.Error(codes.Internal, "ไม่สามารถสร้าง refresh token ได้")
+		}
+		token = refreshTokenPrefix + token
+		next := userGrant{
+			ClientID:  grant.ClientID,
+			TenantID:  grant.TenantID,
+			UserID:    grant.UserID,
+			Scopes:    grant.Scopes,
+			GrantID:   grant.GrantID,
+			AuthTime:  grant.AuthTime,
+			ExpiresAt: now.Add(refreshTokenTTL),
+		}
+		if err := setJSON(ctx, s.Cache, "oauth_refresh:"+hashAPIKey(token), next, refreshTokenTTL); err != nil {
+			return nil, status.Error(codes.Internal, "ไม่สามารถบันทึก refresh token ได้")
+		}
+		reply.RefreshToken = token
+	}
+
+	s.recordOAuthEvent(ctx, "oauth.token_issued", user, client.ClientID, map[string]interface{}{
+		"grantType": grantType,
+		"scope":     scope,
+	})
+	return reply, nil
+}
+
+// ID token ตาม OpenID Connect Core ข้อ 2 เซ็นด้วย RSA key ของ provider (ตรวจสอบได้จาก JWKS)
+func (s *OAuthService) idToken(ctx context.Context, client *models.OAuthClient, user *models.User, tenant *models.Tenant, grant userGrant, scopes []string, accessToken string, now time.Time) (string, error) {
+	keys, err := s.signingKeys(ctx)
+	if err != nil {
+		return "", err
+	}
+	claims := UserInfoClaims(toUserInfoReply(user, scopes))
+	claims["iss"] = s.Issuer
+	claims["aud"] = client.ClientID
+	claims["azp"] = client.ClientID
+	claims["iat"] = now.Unix()
+	claims["exp"] = now.Add(tenant.AccessTokenTTL).Unix()
+	claims["auth_time"] = grant.AuthTime.Unix()
+	claims["at_hash"] = auth.AccessTokenHash(accessToken)
+	claims[auth.TenantClaim] = tenant.ID
+	if grant.Nonce != "" {
+		claims["nonce"] = grant.Nonce
+	}
+	return auth.SignIDToken(claims, keys[0].ID, keys[0].Key)
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	pb "auth-microservice/auth-microservice/proto"
+	"auth-microservice/internal/auth"
+	models "auth-microservice/internal/model"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	grantTypeClientCredentials = "client_credentials"
+	grantTypeAuthorizationCode = "authorization_code"
+	grantTypeRefreshToken      = "refresh_token"
+)
+
+var (
+	errInvalidClient = status.Error(codes.Unauthenticated, "client ไม่ถูกต้อง")
+	// code หรือ refresh token ใช้ไม่ได้ (หมดอายุ ถูกใช้ไปแล้ว หรือไม่ใช่ของ client นี้)
+	errInvalidGrant = status.Error(codes.FailedPrecondition, "grant ไม่ถูกต้องหรือหมดอายุแล้ว")
+)
+
+// แลก credential เป็น token ตาม grant_type
+func (s *OAuthService) Token(ctx context.Context, in *pb.TokenRequest) (*pb.TokenReply, error) {
+	switch in.GetGrantType() {
+	case grantTypeClientCredentials:
+		return s.clientCredentialsToken(ctx, in)
+	case grantTypeAuthorizationCode:
+		return s.authorizationCodeToken(ctx, in)
+	case grantTypeRefreshToken:
+		return s.refreshToken(ctx, in)
+	}
+	return nil, status.Error(codes.Unimplemented, "ไม่รองรับ grant_type นี้")
+}
+
+// ออก access token ให้ service account ตาม OAuth2 client credentials grant (RFC 6749 ข้อ 4.4)
+// client ยืนยันตัวตนด้วย client secret หรือ private_key_jwt (RFC 7523)
+func (s *OAuthService) clientCredentialsToken(ctx context.Context, in *pb.TokenRequest) (*pb.TokenReply, error) {
+	var account *models.ServiceAccount
+	var method string
+	var err error
+	if in.GetClientAssertion() != "" || in.GetClientAssertionType() != "" {
+		account, err = s.authenticateClientAssertion(ctx, in)
+		method = "private_key_jwt"
+	} else {
+		account, err = s.authenticateClientSecret(ctx, in.GetClientId(), in.GetClientSecret())
+		method = "client_secret"
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// client ของ tenant ที่ถูกระงับถือว่ายืนยันตัวตนไม่ผ่าน
+	tenant, err := activeTenant(ctx, s.Tenants, account.TenantID)
+	if status.Code(err) == codes.Internal {
+		return nil, err
+	}
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "tenant ของ client นี้ถูกระงับการใช้งาน")
+	}
+
+	// scope ที่ขอต้องเป็นส่วนหนึ่งของ scope ของ service account (ไม่ระบุ = ได้ทั้งหมด)
+	scopes := account.Scopes
+	if requested := strings.Fields(in.GetScope()); len(requested) > 0 {
+		for _, scope := range requested {
+			if !hasScope(account.Scopes, scope) {
+				return nil, status.Errorf(codes.PermissionDenied, "service account นี้ไม่มี scope %q", scope)
+			}
+		}
+		scopes = uniqueStrings(requested)
+	}
+	scope := strings.Join(scopes, " ")
+
+	keyID, secret, err := signingKey(tenant)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถสร้าง Token ได้")
+	}
+	token, err := auth.GenerateClientJWT(account.ClientID, account.Role, tenant.ID, scope, keyID, secret, tenant.AccessTokenTTL)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถสร้าง Token ได้")
+	}
+
+	s.Audit.Record(ctx, models.AuditEvent{
+		TenantID:   tenant.ID,
+		Action:     "service_account.token_issued",
+		ActorEmail: account.ClientID,
+		Details: map[string]interface{}{
+			"serviceAccountId": account.ID.Hex(),
+			"clientId":         account.ClientID,
+			"authMethod":       method,
+			"scope":            scope,
+		},
+	})
+	return &pb.TokenReply{
+		AccessToken: token,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(tenant.AccessTokenTTL / time.Second),
+		Scope:       scope,
+	}, nil
+}
+
+// ยืนยันตัวตนด้วย client secret (เทียบ hash แบบ constant time กับทุก secret ที่ยังไม่หมดอายุ)
+func (s *OAuthService) authenticateClientSecret(ctx context.Context, clientID string, secret string) (*models.ServiceAccount, error) {
+	if clientID == "" || secret == "" {
+		return nil, errInvalidClient
+	}
+	account, err := s.ServiceAccounts.GetServiceAccountByClientID(ctx, clientID)
+	if err != nil || !matchClientSecret(account.Secrets, secret) {
+		return nil, errInvalidClient
+	}
+	return account, nil
+}
+
+// ยืนยันตัวตนด้วย JWT ที่ client ลงนามด้วย private key (assertion แต่ละตัวใช้ได้ครั้งเดียว)
+func (s *OAuthService) authenticateClientAssertion(ctx context.Context, in *pb.TokenRequest) (*models.ServiceAccount, error) {
+	if in.GetClientAssertionType() != auth.ClientAssertionType {
+		return nil, status.Error(codes.InvalidArgument, "client_assertion_type ไม่ถูกต้อง")
+	}
+	clientID, err := auth.ClientAssertionIssuer(in.GetClientAssertion())
+	if err != nil || (in.GetClientId() != "" && in.GetClientId() != clientID) {
+		return nil, errInvalidClient
+	}
+	account, err := s.ServiceAccounts.GetServiceAccountByClientID(ctx, clientID)
+	if err != nil {
+		return nil, errInvalidClient
+	}
+
+	audiences := []string{s.Issuer, strings.TrimSuffix(s.Issuer, "/") + "/oauth/token"}
+	jti, exp, err := auth.VerifyClientAssertion(in.GetClientAssertion(), clientID, audiences, func(keyID string) (interface{}, error) {
+		for _, key := range account.PublicKeys {
+			// assertion ที่ไม่ระบุ kid ใช้ได้เมื่อ service account มี public key เพียง key เดียว
+			if key.ID == keyID || (keyID == "" && len(account.PublicKeys) == 1) {
+				return auth.ParsePublicKeyPEM(key.PEM)
+			}
+		}
+		return nil, errors.New("unknown key id")
+	})
+	if err != nil {
+		return nil, errInvalidClient
+	}
+
+	// กันการนำ assertion เดิมมาใช้ซ้ำจนกว่าจะหมดอายุ
+	uses, err := s.Cache.Incr(ctx, "client_assertion:"+clientID+":"+jti, time.Until(exp))
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถตรวจสอบ client assertion ได้")
+	}
+	if uses > 1 {
+		return nil, errInvalidClient
+	}
+	return account, nil
+}
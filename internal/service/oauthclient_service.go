@@ -0,0 +1,338 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	pb "auth-microservice/auth-microservice/proto"
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/store"
+	"auth-microservice/internal/validation"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const oauthClientIDPrefix = "oc_" // ส่วนนำหน้าของ client ID ของ OAuth client
+
+func (s *OAuthClientService) CreateOAuthClient(ctx context.Context, in *pb.CreateOAuthClientRequest) (*pb.CreateOAuthClientReply, error) {
+	claims, err := requireAdmin(ctx, s.Blacklist, s.Tenants)
+	if err != nil {
+		return nil, err
+	}
+	if err := validation.ValidateOAuthClientName(in.GetName()); err != nil {
+		return nil, err
+	}
+	redirectURIs := uniqueStrings(in.GetRedirectUris())
+	if err := validation.ValidateRedirectURIs(redirectURIs); err != nil {
+		return nil, err
+	}
+	scopes := uniqueStrings(in.GetScopes())
+	if err := validation.ValidateOAuthScopes(scopes); err != nil {
+		return nil, err
+	}
+
+	clientID, err := generateRandomToken(12)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถสร้าง client ID ได้")
+	}
+	now := time.Now()
+	client := &models.OAuthClient{
+		TenantID:     scopeTenant(ctx, claims),
+		ClientID:     oauthClientIDPrefix + clientID,
+		Name:         in.GetName(),
+		RedirectURIs: redirectURIs,
+		Scopes:       scopes,
+		Public:       in.GetPublic(),
+		Trusted:      in.GetTrusted(),
+		Secrets:      []models.ClientSecret{},
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	// public client (SPA, mobile) เก็บ secret ไม่ได้จึงไม่มี secret
+	var secret string
+	if !client.Public {
+		var clientSecret models.ClientSecret
+		secret, clientSecret, err = newClientSecret(now)
+		if err != nil {
+			return nil, status.Error(codes.Internal, "ไม่สามารถสร้าง client secret ได้")
+		}
+		client.Secrets = []models.ClientSecret{clientSecret}
+	}
+
+	err = s.OAuthClients.CreateOAuthClient(ctx, client)
+	if dup, ok := store.IsDuplicate(err); ok && dup.Field == "name" {
+		return nil, status.Error(codes.AlreadyExists, "ชื่อ client ถูกใช้งานแล้ว")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถสร้าง client ได้")
+	}
+
+	s.recordOAuthClientEvent(ctx, "oauth_client.created", client, claims, map[string]interface{}{
+		"public":  client.Public,
+		"trusted": client.Trusted,
+		"scopes":  scopes,
+	})
+	return &pb.CreateOAuthClientReply{
+		Client:       toOAuthClientReply(client),
+		ClientSecret: secret,
+	}, nil
+}
+
+func (s *OAuthClientService) GetOAuthClient(ctx context.Context, in *pb.GetOAuthClientRequest) (*pb.OAuthClient, error) {
+	claims, err := requireAdmin(ctx, s.Blacklist, s.Tenants)
+	if err != nil {
+		return nil, err
+	}
+	client, err := s.findOAuthClient(ctx, scopeTenant(ctx, claims), in.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return toOAuthClientReply(client), nil
+}
+
+func (s *OAuthClientService) ListOAuthClients(ctx context.Context, in *pb.ListOAuthClientsRequest) (*pb.ListOAuthClientsReply, error) {
+	claims, err := requireAdmin(ctx, s.Blacklist, s.Tenants)
+	if err != nil {
+		return nil, err
+	}
+	clients, err := s.OAuthClients.ListOAuthClients(ctx, scopeTenant(ctx, claims))
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถดึงรายการ client ได้")
+	}
+	reply := &pb.ListOAuthClientsReply{}
+	for i := range clients {
+		reply.Clients = append(reply.Clients, toOAuthClientReply(&clients[i]))
+	}
+	return reply, nil
+}
+
+func (s *OAuthClientService) UpdateOAuthClient(ctx context.Context, in *pb.UpdateOAuthClientRequest) (*pb.OAuthClient, error) {
+	claims, err := requireAdmin(ctx, s.Blacklist, s.Tenants)
+	if err != nil {
+		return nil, err
+	}
+	client, err := s.findOAuthClient(ctx, scopeTenant(ctx, claims), in.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	if in.GetName() != "" {
+		if err := validation.ValidateOAuthClientName(in.GetName()); err != nil {
+			return nil, err
+		}
+		client.Name = in.GetName()
+	}
+	if len(in.GetRedirectUris()) > 0 {
+		redirectURIs := uniqueStrings(in.GetRedirectUris())
+		if err := validation.ValidateRedirectURIs(redirectURIs); err != nil {
+			return nil, err
+		}
+		client.RedirectURIs = redirectURIs
+	}
+	if len(in.GetScopes()) > 0 {
+		scopes := uniqueStrings(in.GetScopes())
+		if err := validation.ValidateOAuthScopes(scopes); err != nil {
+			return nil, err
+		}
+		client.Scopes = scopes
+	}
+	client.Trusted = in.GetTrusted()
+	client.UpdatedAt = time.Now()
+	if err := s.saveOAuthClient(ctx, client); err != nil {
+		return nil, err
+	}
+
+	s.recordOAuthClientEvent(ctx, "oauth_client.updated", client, claims, map[string]interface{}{
+		"redirectUris": client.RedirectURIs,
+		"scopes":       client.Scopes,
+		"trusted":      client.Trusted,
+	})
+	return toOAuthClientReply(client), nil
+}
+
+func (s *OAuthClientService) DeleteOAuthClient(ctx context.Context, in *pb.DeleteOAuthClientRequest) (*pb.DeleteOAuthClientReply, error) {
+	claims, err := requireAdmin(ctx, s.Blacklist, s.Tenants)
+	if err != nil {
+		return nil, err
+	}
+	client, err := s.findOAuthClient(ctx, scopeTenant(ctx, claims), in.GetId())
+	if err != nil {
+		return nil, err
+	}
+	err = s.OAuthClients.DeleteOAuthClient(ctx, client.TenantID, in.GetId())
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, "ไม่พบ client")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถลบ client ได้")
+	}
+
+	s.recordOAuthClientEvent(ctx, "oauth_client.deleted", client, claims, nil)
+	return &pb.DeleteOAuthClientReply{
+		Message: "ลบ client สำเร็จ",
+	}, nil
+}
+
+func (s *OAuthClientService) RotateOAuthClientSecret(ctx context.Context, in *pb.RotateOAuthClientSecretRequest) (*pb.CreateOAuthClientReply, error) {
+	claims, err := requireAdmin(ctx, s.Blacklist, s.Tenants)
+	if err != nil {
+		return nil, err
+	}
+	overlap, err := secretOverlap(in.GetOverlapSeconds())
+	if err != nil {
+		return nil, err
+	}
+	client, err := s.findOAuthClient(ctx, scopeTenant(ctx, claims), in.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if client.Public {
+		return nil, status.Error(codes.FailedPrecondition, "public client ไม่มี client secret")
+	}
+
+	now := time.Now()
+	secret, clientSecret, err := newClientSecret(now)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถสร้าง client secret ได้")
+	}
+	client.Secrets = rotateSecrets(client.Secrets, clientSecret, now.Add(overlap), in.GetRevokePrevious())
+	client.UpdatedAt = now
+	if err := s.saveOAuthClient(ctx, client); err != nil {
+		return nil, err
+	}
+
+	s.recordOAuthClientEvent(ctx, "oauth_client.secret_rotated", client, claims, map[string]interface{}{
+		"secretId":       clientSecret.ID,
+		"overlapSeconds": int64(overlap / time.Second),
+		"revokePrevious": in.GetRevokePrevious(),
+	})
+	return &pb.CreateOAuthClientReply{
+		Client:       toOAuthClientReply(client),
+		ClientSecret: secret,
+	}, nil
+}
+
+func (s *OAuthClientService) ListMyConsents(ctx context.Context, in *pb.ListMyConsentsRequest) (*pb.ListMyConsentsReply, error) {
+	user, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	consents, err := s.OAuthClients.ListConsents(ctx, user.TenantID, user.ID.Hex())
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถดึงรายการความยินยอมได้")
+	}
+
+	reply := &pb.ListMyConsentsReply{}
+	for _, consent := range consents {
+		item := &pb.OAuthConsent{
+			ClientId:  consent.ClientID,
+			Scopes:    consent.Scopes,
+			CreatedAt: consent.CreatedAt.Format(time.RFC3339),
+			UpdatedAt: consent.UpdatedAt.Format(time.RFC3339),
+		}
+		if client, err := s.OAuthClients.GetOAuthClientByClientID(ctx, consent.ClientID); err == nil {
+			item.ClientName = client.Name
+		}
+		reply.Consents = append(reply.Consents, item)
+	}
+	return reply, nil
+}
+
+func (s *OAuthClientService) RevokeConsent(ctx context.Context, in *pb.RevokeConsentRequest) (*pb.RevokeConsentReply, error) {
+	user, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	err = s.OAuthClients.DeleteConsent(ctx, user.TenantID, user.ID.Hex(), in.GetClientId())
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, "ไม่พบความยินยอมที่ให้กับ client นี้")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถยกเลิกความยินยอมได้")
+	}
+
+	s.Audit.Record(ctx, models.AuditEvent{
+		TenantID:     user.TenantID,
+		Action:       "oauth.consent_revoked",
+		ActorEmail:   user.Email,
+		SubjectID:    user.ID.Hex(),
+		SubjectEmail: user.Email,
+		Details:      map[string]interface{}{"clientId": in.GetClientId()},
+	})
+	return &pb.RevokeConsentReply{
+		Message: "ยกเลิกความยินยอมสำเร็จ",
+	}, nil
+}
+
+// ผู้ใช้เจ้าของ token ที่แนบมา
+func (s *OAuthClientService) currentUser(ctx context.Context) (*models.User, error) {
+	_, claims, err := authenticate(ctx, s.Blacklist, s.Tenants)
+	if err != nil {
+		return nil, err
+	}
+	email, _ := claims["email"].(string)
+	user, err := s.Users.GetUserByEmail(ctx, claimsTenant(claims), email)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, "ไม่พบผู้ใช้")
+	}
+	return user, nil
+}
+
+// ดึง OAuth client ใน tenant
+func (s *OAuthClientService) findOAuthClient(ctx context.Context, tenantID string, id string) (*models.OAuthClient, error) {
+	client, err := s.OAuthClients.GetOAuthClient(ctx, tenantID, id)
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, "ไม่พบ client")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถดึงข้อมูล client ได้")
+	}
+	return client, nil
+}
+
+func (s *OAuthClientService) saveOAuthClient(ctx context.Context, client *models.OAuthClient) error {
+	err := s.OAuthClients.SaveOAuthClient(ctx, client)
+	if errors.Is(err, store.ErrNotFound) {
+		return status.Error(codes.NotFound, "ไม่พบ client")
+	}
+	if dup, ok := store.IsDuplicate(err); ok && dup.Field == "name" {
+		return status.Error(codes.AlreadyExists, "ชื่อ client ถูกใช้งานแล้ว")
+	}
+	if err != nil {
+		return status.Error(codes.Internal, "ไม่สามารถบันทึก client ได้")
+	}
+	return nil
+}
+
+// บันทึกเหตุการณ์ของ OAuth client ลง audit log
+func (s *OAuthClientService) recordOAuthClientEvent(ctx context.Context, action string, client *models.OAuthClient, claims map[string]interface{}, details map[string]interface{}) {
+	actor, _ := claims["email"].(string)
+	if details == nil {
+		details = map[string]interface{}{}
+	}
+	details["oauthClientId"] = client.ID.Hex()
+	details["clientId"] = client.ClientID
+	s.Audit.Record(ctx, models.AuditEvent{
+		TenantID:   client.TenantID,
+		Action:     action,
+		ActorEmail: actor,
+		Details:    details,
+	})
+}
+
+func toOAuthClientReply(c *models.OAuthClient) *pb.OAuthClient {
+	return &pb.OAuthClient{
+		Id:           c.ID.Hex(),
+		ClientId:     c.ClientID,
+		Name:         c.Name,
+		RedirectUris: c.RedirectURIs,
+		Scopes:       c.Scopes,
+		Public:       c.Public,
+		Trusted:      c.Trusted,
+		Secrets:      toClientSecretReplies(c.Secrets),
+		CreatedAt:    c.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:    c.UpdatedAt.Format(time.RFC3339),
+	}
+}
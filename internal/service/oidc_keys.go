@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"crypto/rsa"
+	"errors"
+	"sync"
+	"time"
+
+	"auth-microservice/internal/auth"
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/store"
+)
+
+// signing key ของ ID token ที่โหลดจาก settings แล้ว (โหลดครั้งแรกที่ใช้งาน)
+type oidcKeyCache struct {
+	mu   sync.Mutex
+	keys []oidcKey // ตัวแรกคือ key ที่ใช้เซ็น
+}
+
+type oidcKey struct {
+	ID  string
+	Key *rsa.PrivateKey
+}
+
+// key ทั้งหมดของ OpenID Connect สร้างชุดแรกให้อัตโนมัติถ้ายังไม่มี
+func (s *OAuthService) signingKeys(ctx context.Context) ([]oidcKey, error) {
+	return s.oidcKeys.load(ctx, s.Settings)
+}
+
+// โหลด key จาก settings ครั้งแรกที่ใช้งาน (ใช้ร่วมกันระหว่าง ID token และ SAML AuthnRequest)
+// ทุก instance ได้ key ชุดเดียวกันเพราะ InitOIDCKeys บันทึกเฉพาะเมื่อยังไม่มี แล้วอ่านกลับจาก store เสมอ
+func (c *oidcKeyCache) load(ctx context.Context, settings store.SettingsStore) ([]oidcKey, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.keys) > 0 {
+		return c.keys, nil
+	}
+
+	set, err := settings.GetOIDCKeys(ctx)
+	if errors.Is(err, store.ErrNotFound) {
+		if err = initSigningKeys(ctx, settings); err == nil {
+			set, err = settings.GetOIDCKeys(ctx)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]oidcKey, 0, len(set.Keys))
+	for _, k := range set.Keys {
+		key, err := auth.ParseOIDCKey(k.PrivateKey)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, oidcKey{ID: k.ID, Key: key})
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no OIDC signing key")
+	}
+	c.keys = keys
+	return keys, nil
+}
+
+func initSigningKeys(ctx context.Context, settings store.SettingsStore) error {
+	pem, err := auth.GenerateOIDCKey()
+	if err != nil {
+		return err
+	}
+	keyID, err := generateRandomToken(8)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	return settings.InitOIDCKeys(ctx, models.OIDCKeySet{
+		Keys:      []models.OIDCSigningKey{{ID: keyID, PrivateKey: pem, CreatedAt: now}},
+		UpdatedAt: now,
+	})
+}
+
+// public key ทั้งหมดในรูปแบบ JWKS สำหรับ relying party ใช้ตรวจสอบ ID token
+func (s *OAuthService) JWKS(ctx context.Context) (map[string]interface{}, error) {
+	keys, err := s.signingKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	jwks := make([]map[string]interface{}, 0, len(keys))
+	for _, k := range keys {
+		jwks = append(jwks, auth.PublicJWK(k.ID, &k.Key.PublicKey))
+	}
+	return map[string]interface{}{"keys": jwks}, nil
+}
@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	pb "auth-microservice/auth-microservice/proto"
+	"auth-microservice/internal/auth"
+	models "auth-microservice/internal/model"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ข้อมูลผู้ใช้ตาม scope ของ access token ที่ออกให้ OAuth client (OpenID Connect Core ข้อ 5.3)
+func (s *OAuthService) UserInfo(ctx context.Context, in *pb.UserInfoRequest) (*pb.UserInfoReply, error) {
+	tokenStr, err := auth.TokenFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	claims, err := verifyToken(ctx, s.Blacklist, s.Tenants, tokenStr)
+	if err != nil {
+		return nil, err
+	}
+	scope, _ := claims[auth.ScopeClaim].(string)
+	scopes := strings.Fields(scope)
+	if _, ok := claims[auth.ClientIDClaim]; !ok || !hasScope(scopes, models.ScopeOpenID) {
+		return nil, status.Error(codes.PermissionDenied, "token นี้ไม่มี scope openid")
+	}
+
+	userID, _ := claims["sub"].(string)
+	user, err := s.Users.GetUserByID(ctx, userID, false)
+	if err != nil || user.TenantID != claimsTenant(claims) {
+		return nil, status.Error(codes.Unauthenticated, "ไม่พบผู้ใช้")
+	}
+	return toUserInfoReply(user, scopes), nil
+}
+
+// claim ของผู้ใช้เฉพาะที่อยู่ใน scope
+func toUserInfoReply(user *models.User, scopes []string) *pb.UserInfoReply {
+	reply := &pb.UserInfoReply{Sub: user.ID.Hex()}
+	if hasScope(scopes, models.ScopeProfile) {
+		reply.Name = user.DisplayName
+		if reply.Name == "" {
+			reply.Name = user.Username
+		}
+		reply.PreferredUsername = user.Username
+		reply.Picture = user.AvatarURL
+		reply.Locale = user.Locale
+		reply.Zoneinfo = user.Timezone
+		reply.UpdatedAt = user.UpdatedAt.Unix()
+	}
+	if hasScope(scopes, models.ScopeEmail) {
+		reply.Email = user.Email
+		reply.EmailVerified = user.EmailVerified
+	}
+	if hasScope(scopes, models.ScopePhone) {
+		reply.PhoneNumber = user.Phone
+	}
+	return reply
+}
+
+// แปลง UserInfoReply เป็น claim ตามชื่อมาตรฐานของ OpenID Connect (ไม่ใส่ claim ที่ไม่มีค่า)
+// ใช้ทั้งใน ID token และ response ของ /oauth/userinfo
+func UserInfoClaims(reply *pb.UserInfoReply) map[string]interface{} {
+	claims := map[string]interface{}{"sub": reply.GetSub()}
+	optional := map[string]string{
+		"name":               reply.GetName(),
+		"preferred_username": reply.GetPreferredUsername(),
+		"picture":            reply.GetPicture(),
+		"locale":             reply.GetLocale(),
+		"zoneinfo":           reply.GetZoneinfo(),
+		"phone_number":       reply.GetPhoneNumber(),
+	}
+	for name, value := range optional {
+		if value != "" {
+			claims[name] = value
+		}
+	}
+	if reply.GetEmail() != "" {
+		claims["email"] = reply.GetEmail()
+		claims["email_verified"] = reply.GetEmailVerified()
+	}
+	if reply.GetUpdatedAt() > 0 {
+		claims["updated_at"] = reply.GetUpdatedAt()
+	}
+	return claims
+}
+
+// ยกเลิก token ที่ออกให้ client (RFC 7009) ตอบสำเร็จเสมอแม้ token ไม่ถูกต้อง เพื่อไม่ให้ใช้เดา token ได้
+func (s *OAuthService) Revoke(ctx context.Context, in *pb.RevokeRequest) (*pb.RevokeReply, error) {
+	client, err := s.authenticateOAuthClient(ctx, in.GetClientId(), in.GetClientSecret())
+	if err != nil {
+		return nil, err
+	}
+	if in.GetToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "ต้องระบุ token")
+	}
+
+	if strings.HasPrefix(in.GetToken(), refreshTokenPrefix) {
+		if grant, key, err := s.loadRefreshToken(ctx, in.GetToken()); err == nil && grant.ClientID == client.ClientID {
+			if err := s.Cache.Delete(ctx, "oauth_refresh:"+key); err != nil {
+				return nil, status.Error(codes.Internal, "ไม่สามารถยกเลิก refresh token ได้")
+			}
+			s.recordTokenRevoked(ctx, client, grant.UserID, "refresh_token")
+		}
+		return &pb.RevokeReply{}, nil
+	}
+
+	claims, err := verifyToken(ctx, s.Blacklist, s.Tenants, in.GetToken())
+	if err != nil {
+		return &pb.RevokeReply{}, nil
+	}
+	if clientID, _ := claims[auth.ClientIDClaim].(string); clientID != client.ClientID {
+		return &pb.RevokeReply{}, nil
+	}
+	exp, err := auth.GetTokenExpiration(in.GetToken())
+	if err != nil {
+		return &pb.RevokeReply{}, nil
+	}
+	if err := s.Blacklist.AddToken(ctx, in.GetToken(), exp); err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถยกเลิก access token ได้")
+	}
+	sub, _ := claims["sub"].(string)
+	s.recordTokenRevoked(ctx, client, sub, "access_token")
+	return &pb.RevokeReply{}, nil
+}
+
+func (s *OAuthService) recordTokenRevoked(ctx context.Context, client *models.OAuthClient, userID string, tokenType string) {
+	s.Audit.Record(ctx, models.AuditEvent{
+		TenantID:   client.TenantID,
+		Action:     "oauth.token_revoked",
+		ActorEmail: client.ClientID,
+		SubjectID:  userID,
+		Details:    map[string]interface{}{"clientId": client.ClientID, "tokenType": tokenType},
+	})
+}
+
+// สถานะของ token ใน tenant ของ client (RFC 7662) ใช้ได้เฉพาะ confidential client เช่น resource server
+func (s *OAuthService) Introspect(ctx context.Context, in *pb.IntrospectRequest) (*pb.IntrospectReply, error) {
+	client, err := s.authenticateOAuthClient(ctx, in.GetClientId(), in.GetClientSecret())
+	if err != nil {
+		return nil, err
+	}
+	if client.Public {
+		return nil, errInvalidClient
+	}
+	inactive := &pb.IntrospectReply{}
+
+	if strings.HasPrefix(in.GetToken(), refreshTokenPrefix) {
+		grant, key, err := s.loadRefreshToken(ctx, in.GetToken())
+		if err != nil || grant.TenantID != client.TenantID {
+			return inactive, nil
+		}
+		if _, err := s.Cache.Get(ctx, "oauth_refresh_used:"+key); err == nil {
+			return inactive, nil
+		}
+		consent, err := s.OAuthClients.GetConsent(ctx, grant.TenantID, grant.UserID, grant.ClientID)
+		if err != nil || consent.GrantID != grant.GrantID {
+			return inactive, nil
+		}
+		user, err := s.Users.GetUserByID(ctx, grant.UserID, false)
+		if err != nil {
+			return inactive, nil
+		}
+		return &pb.IntrospectReply{
+			Active:    true,
+			Scope:     strings.Join(grant.Scopes, " "),
+			ClientId:  grant.ClientID,
+			Username:  user.Email,
+			TokenType: "refresh_token",
+			Exp:       grant.ExpiresAt.Unix(),
+			Sub:       grant.UserID,
+			Iss:       s.Issuer,
+			TenantId:  grant.TenantID,
+		}, nil
+	}
+
+	claims, err := verifyToken(ctx, s.Blacklist, s.Tenants, in.GetToken())
+	if err != nil || claimsTenant(claims) != client.TenantID {
+		return inactive, nil
+	}
+	reply := &pb.IntrospectReply{
+		Active:    true,
+		TokenType: "access_token",
+		Iss:       s.Issuer,
+		TenantId:  claimsTenant(claims),
+	}
+	reply.Scope, _ = claims[auth.ScopeClaim].(string)
+	reply.ClientId, _ = claims[auth.ClientIDClaim].(string)
+	reply.Username, _ = claims["email"].(string)
+	reply.Sub, _ = claims["sub"].(string)
+	if exp, ok := claims["exp"].(float64); ok {
+		reply.Exp = int64(exp)
+	}
+	if iat, ok := claims["iat"].(float64); ok {
+		reply.Iat = int64(iat)
+	}
+	return reply, nil
+}
+
+// metadata ของ provider สำหรับ /.well-known/openid-configuration (OpenID Connect Discovery 1.0)
+func (s *OAuthService) Discovery() map[string]interface{} {
+	return map[string]interface{}{
+		"issuer":                                s.Issuer,
+		"authorization_endpoint":                s.Issuer + "/oauth/authorize",
+		"token_endpoint":                        s.Issuer + "/oauth/token",
+		"userinfo_endpoint":                     s.Issuer + "/oauth/userinfo",
+		"jwks_uri":                              s.Issuer + "/.well-known/jwks.json",
+		"revocation_endpoint":                   s.Issuer + "/oauth/revoke",
+		"introspection_endpoint":                s.Issuer + "/oauth/introspect",
+		"response_types_supported":              []string{"code"},
+		"response_modes_supported":              []string{"query"},
+		"grant_types_supported":                 []string{grantTypeAuthorizationCode, grantTypeRefreshToken, grantTypeClientCredentials},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"token_endpoint_auth_methods_supported": []string{"client_secret_basic", "client_secret_post", "private_key_jwt", "none"},
+		"code_challenge_methods_supported":      []string{"S256"},
+		"scopes_supported":                      []string{models.ScopeOpenID, models.ScopeProfile, models.ScopeEmail, models.ScopePhone, models.ScopeOfflineAccess},
+		"claims_supported": []string{
+			"sub", "iss", "aud", "exp", "iat", "auth_time", "nonce", "at_hash", auth.TenantClaim,
+			"name", "preferred_username", "picture", "locale", "zoneinfo", "updated_at",
+			"email", "email_verified", "phone_number",
+		},
+		"authorization_response_iss_parameter_supported": true,
+	}
+}
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"encoding/json"
+	"time"
+
+	models "auth-microservice/internal/model"
+)
+
+// สร้าง domain event สำหรับบันทึกลง outbox พร้อมการเปลี่ยนแปลงข้อมูล (data = ข้อมูลของ event ห้ามมีรหัสผ่านหรือ token)
+func newOutboxEvent(tenantID string, eventType string, data map[string]interface{}) (*models.OutboxEvent, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	return &models.OutboxEvent{
+		TenantID:      tenantID,
+		Type:          eventType,
+		Payload:       string(payload),
+		CreatedAt:     now,
+		NextAttemptAt: now,
+	}, nil
+}
+
+// สร้าง event ของผู้ใช้ที่มีอยู่แล้ว (AggregateID = ID ของผู้ใช้)
+func newUserEvent(user *models.User, eventType string, data map[string]interface{}) (*models.OutboxEvent, error) {
+	event, err := newOutboxEvent(user.TenantID, eventType, data)
+	if err != nil {
+		return nil, err
+	}
+	event.AggregateID = user.ID.Hex()
+	return event, nil
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/store"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultListLimit = 10  // จำนวนรายการต่อหน้าเริ่มต้น
+	maxListLimit     = 100 // จำนวนรายการต่อหน้าสูงสุดที่ server ยอมให้
+)
+
+// field ที่อนุญาตให้ใช้เรียงลำดับใน ListUsers
+var userSortFields = map[string]bool{
+	"createdAt": true,
+	"updatedAt": true,
+	"username":  true,
+	"email":     true,
+}
+
+// ข้อมูลภายใน page token: ตำแหน่งของรายการสุดท้ายในหน้าก่อนหน้า
+type pageCursor struct {
+	Field string `json:"f"`
+	Desc  bool   `json:"d"`
+	Value string `json:"v"`
+	ID    string `json:"id"`
+}
+
+// แปลง orderBy เช่น "createdAt desc" เป็น store.SortOrder
+func parseOrderBy(orderBy string) (store.SortOrder, error) {
+	parts := strings.Fields(orderBy)
+	if len(parts) == 0 {
+		return store.SortOrder{Field: "createdAt"}, nil
+	}
+	order := store.SortOrder{Field: parts[0]}
+	if !userSortFields[order.Field] {
+		return order, status.Errorf(codes.InvalidArgument, "ไม่รองรับการเรียงตาม %q", order.Field)
+	}
+	if len(parts) > 2 {
+		return order, status.Error(codes.InvalidArgument, "รูปแบบ orderBy ไม่ถูกต้อง")
+	}
+	if len(parts) == 2 {
+		switch strings.ToLower(parts[1]) {
+		case "asc":
+		case "desc":
+			order.Desc = true
+		default:
+			return order, status.Error(codes.InvalidArgument, "ทิศทางการเรียงต้องเป็น asc หรือ desc")
+		}
+	}
+	return order, nil
+}
+
+// ค่าของ field ที่ใช้เรียงของผู้ใช้ เก็บเป็น string ใน page token
+func sortValue(u models.User, field string) string {
+	switch field {
+	case "createdAt":
+		return u.CreatedAt.UTC().Format(time.RFC3339Nano)
+	case "updatedAt":
+		return u.UpdatedAt.UTC().Format(time.RFC3339Nano)
+	case "username":
+		return u.Username
+	default:
+		return u.Email
+	}
+}
+
+// สร้าง page token จากรายการสุดท้ายของหน้า
+func encodePageToken(order store.SortOrder, last models.User) string {
+	data, _ := json.Marshal(pageCursor{
+		Field: order.Field,
+		Desc:  order.Desc,
+		Value: sortValue(last, order.Field),
+		ID:    last.ID.Hex(),
+	})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// แปลง page token เป็นตำแหน่งของรายการสุดท้ายในหน้าก่อน
+func decodePageToken(token string, order store.SortOrder) (*store.Cursor, error) {
+	invalid := status.Error(codes.InvalidArgument, "pageToken ไม่ถูกต้อง")
+
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, invalid
+	}
+	var cursor pageCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, invalid
+	}
+	// token ต้องมาจากการเรียงลำดับแบบเดียวกัน
+	if cursor.Field != order.Field || cursor.Desc != order.Desc {
+		return nil, status.Error(codes.InvalidArgument, "pageToken ไม่ตรงกับ orderBy")
+	}
+	if !primitive.IsValidObjectID(cursor.ID) {
+		return nil, invalid
+	}
+
+	var value interface{} = cursor.Value
+	if order.Field == "createdAt" || order.Field == "updatedAt" {
+		t, err := time.Parse(time.RFC3339Nano, cursor.Value)
+		if err != nil {
+			return nil, invalid
+		}
+		value = t
+	}
+	return &store.Cursor{Value: value, ID: cursor.ID}, nil
+}
+
+// ปรับค่า limit ให้อยู่ในช่วงที่ server อนุญาต
+func clampLimit(limit int32) int64 {
+	if limit <= 0 {
+		return defaultListLimit
+	}
+	if limit > maxListLimit {
+		return maxListLimit
+	}
+	return int64(limit)
+}
@@ -0,0 +1,509 @@
+package service
+
+import (
+	pb "auth-microservice/auth-microservice/proto"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/store"
+	"auth-microservice/internal/webauthn"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	passkeySessionTTL      = 5 * time.Minute // อายุของ challenge ที่รอ response จาก authenticator
+	passkeySecondFactorTTL = 5 * time.Minute // เวลาที่ให้ยืนยัน passkey หลังรหัสผ่านถูกต้อง
+	passkeyNameMaxLength   = 64
+	maxPasskeysPerUser     = 20
+)
+
+// challenge ที่ส่งให้ authenticator (เก็บใน cache จนกว่าจะได้ response กลับมา)
+type passkeySession struct {
+	TenantID     string `json:"tenantId"`
+	UserID       string `json:"userId,omitempty"` // ค่าว่าง = เข้าสู่ระบบด้วย discoverable credential
+	Challenge    []byte `json:"challenge"`
+	SecondFactor string `json:"secondFactor,omitempty"` // hash ของ secondFactorToken เมื่อเป็นการยืนยันปัจจัยที่สอง
+}
+
+// ผู้ใช้ที่ใส่รหัสผ่านถูกต้องแล้วและรอยืนยันด้วย passkey
+type passkeySecondFactor struct {
+	TenantID        string `json:"tenantId"`
+	UserID          string `json:"userId"`
+	PasswordExpired bool   `json:"passwordExpired"`
+}
+
+func (s *PasskeyService) BeginPasskeyRegistration(ctx context.Context, in *pb.BeginPasskeyRegistrationRequest) (*pb.BeginPasskeyRegistrationReply, error) {
+	user, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	passkeys, err := s.Identities.ListPasskeys(ctx, user.TenantID, user.ID.Hex())
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถดึงรายการ passkey ได้")
+	}
+	if len(passkeys) >= maxPasskeysPerUser {
+		return nil, status.Error(codes.ResourceExhausted, "ลงทะเบียน passkey ครบจำนวนสูงสุดแล้ว")
+	}
+
+	// ไม่ให้ลงทะเบียน authenticator ที่มี passkey ของผู้ใช้อยู่แล้วซ้ำ
+	exclude, err := passkeyDescriptors(passkeys)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ข้อมูล passkey ไม่ถูกต้อง")
+	}
+	displayName := user.DisplayName
+	if displayName == "" {
+		displayName = user.Username
+	}
+	sessionID, session, err := s.newSession(ctx, passkeySession{TenantID: user.TenantID, UserID: user.ID.Hex()}, "passkey_registration:")
+	if err != nil {
+		return nil, err
+	}
+	options, err := s.WebAuthn.CreationOptions(webauthn.User{ID: user.ID[:], Name: user.Email, DisplayName: displayName}, session.Challenge, exclude)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถสร้าง options ของ passkey ได้")
+	}
+	return &pb.BeginPasskeyRegistrationReply{
+		SessionId:        sessionID,
+		PublicKeyOptions: string(options),
+	}, nil
+}
+
+func (s *PasskeyService) FinishPasskeyRegistration(ctx context.Context, in *pb.FinishPasskeyRegistrationRequest) (*pb.Passkey, error) {
+	user, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	name := strings.TrimSpace(in.GetName())
+	if name == "" {
+		name = "Passkey"
+	}
+	if utf8.RuneCountInString(name) > passkeyNameMaxLength {
+		return nil, status.Error(codes.InvalidArgument, "ชื่อ passkey ยาวเกินไป")
+	}
+	session, err := s.consumeSession(ctx, "passkey_registration:", in.GetSessionId())
+	if err != nil {
+		return nil, err
+	}
+	if session.UserID != user.ID.Hex() {
+		return nil, status.Error(codes.PermissionDenied, "session นี้ไม่ใช่ของผู้ใช้นี้")
+	}
+
+	cred, err := s.WebAuthn.VerifyRegistration([]byte(in.GetCredential()), session.Challenge, false)
+	if errors.Is(err, webauthn.ErrUnsupportedAttestation) {
+		return nil, status.Error(codes.InvalidArgument, "ไม่รองรับ attestation ของ authenticator นี้")
+	}
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "ข้อมูลจาก authenticator ไม่ถูกต้อง")
+	}
+
+	passkey := &models.Passkey{
+		TenantID:          user.TenantID,
+		UserID:            user.ID,
+		Name:              name,
+		CredentialID:      webauthn.EncodeBase64URL(cred.ID),
+		PublicKey:         cred.PublicKey,
+		Algorithm:         cred.Algorithm,
+		SignCount:         cred.SignCount,
+		AAGUID:            formatAAGUID(cred.AAGUID),
+		AttestationFormat: cred.AttestationFormat,
+		Transports:        cred.Transports,
+		BackupEligible:    cred.BackupEligible,
+		BackedUp:          cred.BackedUp,
+		CreatedAt:         time.Now(),
+	}
+	if passkey.Transports == nil {
+		passkey.Transports = []string{}
+	}
+	err = s.Identities.CreatePasskey(ctx, passkey)
+	if _, ok := store.IsDuplicate(err); ok {
+		return nil, status.Error(codes.AlreadyExists, "passkey นี้ลงทะเบียนแล้ว")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถบันทึก passkey ได้")
+	}
+
+	s.recordPasskeyEvent(ctx, "passkey.registered", user, map[string]interface{}{
+		"passkeyId": passkey.ID.Hex(), "name": passkey.Name, "attestationFormat": passkey.AttestationFormat,
+	})
+	return toPasskeyReply(passkey), nil
+}
+
+func (s *PasskeyService) ListPasskeys(ctx context.Context, in *pb.ListPasskeysRequest) (*pb.ListPasskeysReply, error) {
+	user, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	passkeys, err := s.Identities.ListPasskeys(ctx, user.TenantID, user.ID.Hex())
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถดึงรายการ passkey ได้")
+	}
+	reply := &pb.ListPasskeysReply{PasskeyRequired: user.PasskeyRequired}
+	for i := range passkeys {
+		reply.Passkeys = append(reply.Passkeys, toPasskeyReply(&passkeys[i]))
+	}
+	return reply, nil
+}
+
+func (s *PasskeyService) RenamePasskey(ctx context.Context, in *pb.RenamePasskeyRequest) (*pb.Passkey, error) {
+	user, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	name := strings.TrimSpace(in.GetName())
+	if name == "" {
+		return nil, status.Error(codes.InvalidArgument, "ต้องระบุชื่อ passkey")
+	}
+	if utf8.RuneCountInString(name) > passkeyNameMaxLength {
+		return nil, status.Error(codes.InvalidArgument, "ชื่อ passkey ยาวเกินไป")
+	}
+	passkey, err := s.Identities.RenamePasskey(ctx, user.TenantID, user.ID.Hex(), in.GetId(), name)
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, "ไม่พบ passkey")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถเปลี่ยนชื่อ passkey ได้")
+	}
+
+	s.recordPasskeyEvent(ctx, "passkey.renamed", user, map[string]interface{}{"passkeyId": passkey.ID.Hex(), "name": passkey.Name})
+	return toPasskeyReply(passkey), nil
+}
+
+func (s *PasskeyService) DeletePasskey(ctx context.Context, in *pb.DeletePasskeyRequest) (*pb.DeletePasskeyReply, error) {
+	user, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	err = s.Identities.DeletePasskey(ctx, user.TenantID, user.ID.Hex(), in.GetId())
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, "ไม่พบ passkey")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถลบ passkey ได้")
+	}
+
+	// ไม่มี passkey เหลือแล้ว ยกเลิกการบังคับใช้ปัจจัยที่สอง เพื่อไม่ให้ผู้ใช้เข้าสู่ระบบไม่ได้
+	if user.PasskeyRequired {
+		remaining, err := s.Identities.ListPasskeys(ctx, user.TenantID, user.ID.Hex())
+		if err == nil && len(remaining) == 0 {
+			required := false
+			if err := s.Users.UpdateProfile(ctx, user.ID.Hex(), store.ProfilePatch{PasskeyRequired: &required}, time.Now()); err != nil {
+				log.Printf("ยกเลิกการบังคับใช้ passkey ของ %s ไม่สำเร็จ: %v", user.Email, err)
+			}
+		}
+	}
+
+	s.recordPasskeyEvent(ctx, "passkey.deleted", user, map[string]interface{}{"passkeyId": in.GetId()})
+	return &pb.DeletePasskeyReply{
+		Message: "ลบ passkey สำเร็จ",
+	}, nil
+}
+
+func (s *PasskeyService) SetPasskeyRequired(ctx context.Context, in *pb.SetPasskeyRequiredRequest) (*pb.SetPasskeyRequiredReply, error) {
+	user, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if in.GetRequired() {
+		passkeys, err := s.Identities.ListPasskeys(ctx, user.TenantID, user.ID.Hex())
+		if err != nil {
+			return nil, status.Error(codes.Internal, "ไม่สามารถดึงรายการ passkey ได้")
+		}
+		if len(passkeys) == 0 {
+			return nil, status.Error(codes.FailedPrecondition, "ต้องลงทะเบียน passkey ก่อน")
+		}
+	}
+	required := in.GetRequired()
+	if err := s.Users.UpdateProfile(ctx, user.ID.Hex(), store.ProfilePatch{PasskeyRequired: &required}, time.Now()); err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถบันทึกการตั้งค่าได้")
+	}
+
+	s.recordPasskeyEvent(ctx, "passkey.required_changed", user, map[string]interface{}{"required": required})
+	message := "ยกเลิกการบังคับใช้ passkey แล้ว"
+	if required {
+		message = "บังคับใช้ passkey หลังเข้าสู่ระบบด้วยรหัสผ่านแล้ว"
+	}
+	return &pb.SetPasskeyRequiredReply{
+		Required: required,
+		Message:  message,
+	}, nil
+}
+
+func (s *PasskeyService) BeginPasskeyLogin(ctx context.Context, in *pb.BeginPasskeyLoginRequest) (*pb.BeginPasskeyLoginReply, error) {
+	tenant, err := requestTenant(ctx, s.Tenants)
+	if err != nil {
+		return nil, err
+	}
+
+	session := passkeySession{TenantID: tenant.ID}
+	var user *models.User
+	switch {
+	case in.GetSecondFactorToken() != "":
+		// ปัจจัยที่สอง: ผู้ใช้ผ่านการตรวจรหัสผ่านแล้ว ใช้ passkey ยืนยันว่ามีอุปกรณ์อยู่จริง
+		var pending passkeySecondFactor
+		if getJSON(ctx, s.Cache, "passkey_mfa:"+hashAPIKey(in.GetSecondFactorToken()), &pending) != nil || pending.TenantID != tenant.ID {
+			return nil, status.Error(codes.FailedPrecondition, "secondFactorToken ไม่ถูกต้องหรือหมดอายุ")
+		}
+		if user, err = s.Users.GetUserByID(ctx, pending.UserID, false); err != nil {
+			return nil, status.Error(codes.FailedPrecondition, "secondFactorToken ไม่ถูกต้องหรือหมดอายุ")
+		}
+		session.SecondFactor = hashAPIKey(in.GetSecondFactorToken())
+	case in.GetEmail() != "":
+		if user, err = s.Users.GetUserByEmail(ctx, tenant.ID, in.GetEmail()); err != nil {
+			return nil, status.Error(codes.NotFound, "ไม่พบผู้ใช้ที่มีอีเมลนี้")
+		}
+	}
+
+	// ไม่ระบุผู้ใช้ = authenticator ให้ผู้ใช้เลือก passkey แล้วส่ง user handle กลับมา
+	var allow []webauthn.Descriptor
+	if user != nil {
+		passkeys, err := s.Identities.ListPasskeys(ctx, tenant.ID, user.ID.Hex())
+		if err != nil {
+			return nil, status.Error(codes.Internal, "ไม่สามารถดึงรายการ passkey ได้")
+		}
+		if len(passkeys) == 0 {
+			return nil, status.Error(codes.FailedPrecondition, "ผู้ใช้ยังไม่ได้ลงทะเบียน passkey")
+		}
+		if allow, err = passkeyDescriptors(passkeys); err != nil {
+			return nil, status.Error(codes.Internal, "ข้อมูล passkey ไม่ถูกต้อง")
+		}
+		session.UserID = user.ID.Hex()
+	}
+
+	sessionID, stored, err := s.newSession(ctx, session, "passkey_login:")
+	if err != nil {
+		return nil, err
+	}
+	options, err := s.WebAuthn.RequestOptions(stored.Challenge, allow, passkeyUserVerification(stored))
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถสร้าง options ของ passkey ได้")
+	}
+	return &pb.BeginPasskeyLoginReply{
+		SessionId:        sessionID,
+		PublicKeyOptions: string(options),
+	}, nil
+}
+
+func (s *PasskeyService) FinishPasskeyLogin(ctx context.Context, in *pb.FinishPasskeyLoginRequest) (*pb.FinishPasskeyLoginReply, error) {
+	session, err := s.consumeSession(ctx, "passkey_login:", in.GetSessionId())
+	if err != nil {
+		return nil, err
+	}
+	tenant, err := activeTenant(ctx, s.Tenants, session.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	assertion, err := webauthn.ParseAssertion([]byte(in.GetCredential()))
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "ข้อมูลจาก authenticator ไม่ถูกต้อง")
+	}
+
+	failed := status.Error(codes.Unauthenticated, "passkey ไม่ถูกต้อง")
+	passkey, err := s.Identities.GetPasskeyByCredentialID(ctx, tenant.ID, webauthn.EncodeBase64URL(assertion.CredentialID))
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, failed
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถดึงข้อมูล passkey ได้")
+	}
+	// passkey ต้องเป็นของผู้ใช้ที่ระบุตอนเริ่ม และ user handle (ถ้ามี) ต้องตรงกับเจ้าของ passkey
+	if session.UserID != "" && passkey.UserID.Hex() != session.UserID {
+		return nil, failed
+	}
+	if (session.UserID == "" || len(assertion.UserHandle) > 0) && !bytes.Equal(assertion.UserHandle, passkey.UserID[:]) {
+		return nil, failed
+	}
+	user, err := s.Users.GetUserByID(ctx, passkey.UserID.Hex(), false)
+	if err != nil || user.TenantID != tenant.ID {
+		return nil, failed
+	}
+
+	var pending passkeySecondFactor
+	if session.SecondFactor != "" {
+		if getJSON(ctx, s.Cache, "passkey_mfa:"+session.SecondFactor, &pending) != nil {
+			return nil, status.Error(codes.FailedPrecondition, "secondFactorToken ไม่ถูกต้องหรือหมดอายุ")
+		}
+	}
+
+	result, err := s.WebAuthn.VerifyAssertion(assertion, session.Challenge, &webauthn.Credential{
+		ID:        assertion.CredentialID,
+		PublicKey: passkey.PublicKey,
+		Algorithm: passkey.Algorithm,
+		SignCount: passkey.SignCount,
+	}, passkeyUserVerification(session) == webauthn.VerificationRequired)
+	if err == nil {
+		// counter ถูกอัปเดตเฉพาะเมื่อยังเป็นค่าที่ตรวจไว้ (กัน assertion เดียวกันถูกใช้ซ้ำพร้อมกัน)
+		err = s.Identities.UpdatePasskeyUsage(ctx, passkey.ID.Hex(), passkey.SignCount, result.SignCount, result.BackedUp, time.Now())
+	}
+	if err != nil {
+		if errors.Is(err, webauthn.ErrSignCount) {
+			log.Printf("sign counter ของ passkey %s ของ %s ไม่เพิ่มขึ้น (authenticator อาจถูกคัดลอก)", passkey.ID.Hex(), user.Email)
+		}
+		s.recordLogin(ctx, "user.login_failed", user, passkey)
+		return nil, failed
+	}
+
+	// ปัจจัยที่สองใช้ได้ครั้งเดียว
+	if session.SecondFactor != "" {
+		if ok, err := consumeOnce(ctx, s.Cache, "passkey_mfa_used:"+session.SecondFactor, passkeySecondFactorTTL); err != nil || !ok {
+			return nil, status.Error(codes.FailedPrecondition, "secondFactorToken ไม่ถูกต้องหรือหมดอายุ")
+		}
+		s.Cache.Delete(ctx, "passkey_mfa:"+session.SecondFactor)
+	}
+
+	if err := revokeActiveToken(ctx, s.Sessions, s.Blacklist, tenant.ID, user.Email); err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถเพิ่ม token เข้า blacklisted ได้")
+	}
+	token, err := issueToken(ctx, s.Sessions, s.Groups, tenant, user.ID.Hex(), user.Email, user.Role)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "เจอข้อผิดพลาดในการสร้างโทเค็น")
+	}
+
+	s.recordLogin(ctx, "user.login", user, passkey)
+	return &pb.FinishPasskeyLoginReply{
+		Email:           user.Email,
+		Username:        user.Username,
+		Token:           token,
+		PasswordExpired: pending.PasswordExpired,
+	}, nil
+}
+
+// เก็บผู้ใช้ที่ใส่รหัสผ่านถูกต้องแล้วรอยืนยันด้วย passkey คืน reply ที่มี secondFactorToken แทน token
+func beginPasskeySecondFactor(ctx context.Context, cache store.KeyValueStore, user *models.User, passwordExpired bool) (*pb.LoginReply, error) {
+	token, err := generateRandomToken(32)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถสร้าง token ได้")
+	}
+	pending := passkeySecondFactor{TenantID: user.TenantID, UserID: user.ID.Hex(), PasswordExpired: passwordExpired}
+	if err := setJSON(ctx, cache, "passkey_mfa:"+hashAPIKey(token), pending, passkeySecondFactorTTL); err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถบันทึกการเข้าสู่ระบบได้")
+	}
+	return &pb.LoginReply{
+		Email:                user.Email,
+		Username:             user.Username,
+		SecondFactorRequired: true,
+		SecondFactorToken:    token,
+	}, nil
+}
+
+// สร้าง challenge ใหม่แล้วเก็บ session ไว้ใน cache ตาม prefix คืน session ID ที่ client ต้องส่งกลับมา
+func (s *PasskeyService) newSession(ctx context.Context, session passkeySession, prefix string) (string, *passkeySession, error) {
+	session.Challenge = make([]byte, 32)
+	if _, err := rand.Read(session.Challenge); err != nil {
+		return "", nil, status.Error(codes.Internal, "ไม่สามารถสร้าง challenge ได้")
+	}
+	sessionID, err := generateRandomToken(32)
+	if err != nil {
+		return "", nil, status.Error(codes.Internal, "ไม่สามารถสร้าง session ได้")
+	}
+	if err := setJSON(ctx, s.Cache, prefix+hashAPIKey(sessionID), session, passkeySessionTTL); err != nil {
+		return "", nil, status.Error(codes.Internal, "ไม่สามารถบันทึก session ได้")
+	}
+	return sessionID, &session, nil
+}
+
+// ดึง session (ใช้ได้ครั้งเดียว challenge จึงถูกใช้ซ้ำไม่ได้)
+func (s *PasskeyService) consumeSession(ctx context.Context, prefix string, sessionID string) (*passkeySession, error) {
+	if sessionID == "" {
+		return nil, status.Error(codes.InvalidArgument, "ต้องระบุ sessionId")
+	}
+	key := prefix + hashAPIKey(sessionID)
+	var session passkeySession
+	if getJSON(ctx, s.Cache, key, &session) != nil {
+		return nil, status.Error(codes.FailedPrecondition, "session ไม่ถูกต้องหรือหมดอายุ")
+	}
+	if ok, err := consumeOnce(ctx, s.Cache, key+":used", passkeySessionTTL); err != nil || !ok {
+		return nil, status.Error(codes.FailedPrecondition, "session ไม่ถูกต้องหรือหมดอายุ")
+	}
+	s.Cache.Delete(ctx, key)
+	return &session, nil
+}
+
+// ดึงผู้ใช้เจ้าของ token
+func (s *PasskeyService) currentUser(ctx context.Context) (*models.User, error) {
+	_, claims, err := authenticate(ctx, s.Blacklist, s.Tenants)
+	if err != nil {
+		return nil, err
+	}
+	email, _ := claims["email"].(string)
+	user, err := s.Users.GetUserByEmail(ctx, claimsTenant(claims), email)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, "ไม่พบผู้ใช้")
+	}
+	return user, nil
+}
+
+func (s *PasskeyService) recordPasskeyEvent(ctx context.Context, action string, user *models.User, details map[string]interface{}) {
+	s.Audit.Record(ctx, models.AuditEvent{
+		TenantID:     user.TenantID,
+		Action:       action,
+		ActorEmail:   user.Email,
+		SubjectID:    user.ID.Hex(),
+		SubjectEmail: user.Email,
+		Details:      details,
+	})
+}
+
+// บันทึกผลการเข้าสู่ระบบด้วย passkey ลง audit log (action เดียวกับการเข้าสู่ระบบด้วยรหัสผ่าน)
+func (s *PasskeyService) recordLogin(ctx context.Context, action string, user *models.User, passkey *models.Passkey) {
+	details := requestDetails(ctx)
+	details["method"] = "passkey"
+	details["passkeyId"] = passkey.ID.Hex()
+	s.recordPasskeyEvent(ctx, action, user, details)
+}
+
+// การเข้าสู่ระบบด้วย passkey อย่างเดียวต้องยืนยันตัวตนบน authenticator (PIN หรือ biometric)
+// ส่วนปัจจัยที่สองผ่านการตรวจรหัสผ่านมาแล้ว จึงต้องการเพียงการมีอุปกรณ์อยู่จริง
+func passkeyUserVerification(session *passkeySession) string {
+	if session.SecondFactor != "" {
+		return webauthn.VerificationPreferred
+	}
+	return webauthn.VerificationRequired
+}
+
+func passkeyDescriptors(passkeys []models.Passkey) ([]webauthn.Descriptor, error) {
+	list := make([]webauthn.Descriptor, 0, len(passkeys))
+	for _, p := range passkeys {
+		id, err := webauthn.DecodeBase64URL(p.CredentialID)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, webauthn.Descriptor{ID: id, Transports: p.Transports})
+	}
+	return list, nil
+}
+
+// AAGUID ในรูปแบบ UUID (ค่าว่างถ้า authenticator ไม่ระบุรุ่น)
+func formatAAGUID(aaguid []byte) string {
+	if len(aaguid) != 16 || bytes.Equal(aaguid, make([]byte, 16)) {
+		return ""
+	}
+	h := hex.EncodeToString(aaguid)
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:32]
+}
+
+func toPasskeyReply(p *models.Passkey) *pb.Passkey {
+	reply := &pb.Passkey{
+		Id:                p.ID.Hex(),
+		Name:              p.Name,
+		Aaguid:            p.AAGUID,
+		AttestationFormat: p.AttestationFormat,
+		Transports:        p.Transports,
+		BackupEligible:    p.BackupEligible,
+		BackedUp:          p.BackedUp,
+		CreatedAt:         p.CreatedAt.Format(time.RFC3339),
+	}
+	if p.LastUsedAt != nil {
+		reply.LastUsedAt = p.LastUsedAt.Format(time.RFC3339)
+	}
+	return reply
+}
@@ -0,0 +1,268 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	pb "auth-microservice/auth-microservice/proto"
+	"auth-microservice/internal/audit"
+	"auth-microservice/internal/notify"
+	"auth-microservice/internal/store"
+	"auth-microservice/internal/webauthn"
+	"auth-microservice/internal/webauthn/webauthntest"
+
+	"google.golang.org/grpc/codes"
+)
+
+const passkeyOrigin = "http://localhost:8080"
+
+// PasskeyService ของ relying party "localhost" และ authenticator ของผู้ใช้
+type passkeyFixture struct {
+	stores      *store.Stores
+	auditLogger *audit.Logger
+	service     *PasskeyService
+	device      *webauthntest.Authenticator
+}
+
+func newPasskeyFixture(t *testing.T) *passkeyFixture {
+	t.Helper()
+	stores := newTestStores(t)
+	auditLogger := audit.NewLogger(stores.Audit)
+	return &passkeyFixture{
+		stores:      stores,
+		auditLogger: auditLogger,
+		service:     NewPasskeyService(stores, webauthn.Config{RPID: "localhost", RPName: "Test", Origins: []string{passkeyOrigin}}, auditLogger),
+		device:      webauthntest.New(passkeyOrigin),
+	}
+}
+
+// ลงทะเบียน passkey ใหม่ของ authenticator ให้ผู้ใช้เจ้าของ ctx
+func (f *passkeyFixture) register(t *testing.T, ctx context.Context, device *webauthntest.Authenticator, name string) (*pb.Passkey, *webauthntest.Credential) {
+	t.Helper()
+	begin, err := f.service.BeginPasskeyRegistration(ctx, &pb.BeginPasskeyRegistrationRequest{})
+	if err != nil {
+		t.Fatalf("BeginPasskeyRegistration: %v", err)
+	}
+	response, cred := device.Create(t, begin.GetPublicKeyOptions())
+	passkey, err := f.service.FinishPasskeyRegistration(ctx, &pb.FinishPasskeyRegistrationRequest{SessionId: begin.GetSessionId(), Name: name, Credential: response})
+	if err != nil {
+		t.Fatalf("FinishPasskeyRegistration: %v", err)
+	}
+	return passkey, cred
+}
+
+// เริ่มเข้าสู่ระบบด้วย passkey แล้วคืน session ID กับ credential ที่ authenticator ตอบ
+func (f *passkeyFixture) assert(t *testing.T, in *pb.BeginPasskeyLoginRequest, device *webauthntest.Authenticator, cred *webauthntest.Credential) (string, string) {
+	t.Helper()
+	begin, err := f.service.BeginPasskeyLogin(context.Background(), in)
+	if err != nil {
+		t.Fatalf("BeginPasskeyLogin: %v", err)
+	}
+	return begin.GetSessionId(), device.Get(t, begin.GetPublicKeyOptions(), cred)
+}
+
+func (f *passkeyFixture) login(t *testing.T, in *pb.BeginPasskeyLoginRequest, device *webauthntest.Authenticator, cred *webauthntest.Credential) (*pb.FinishPasskeyLoginReply, error) {
+	t.Helper()
+	sessionID, response := f.assert(t, in, device, cred)
+	return f.service.FinishPasskeyLogin(context.Background(), &pb.FinishPasskeyLoginRequest{SessionId: sessionID, Credential: response})
+}
+
+func TestPasskeyRegisterAndLogin(t *testing.T) {
+	f := newPasskeyFixture(t)
+	ctx := registerAndLogin(t, f.stores, f.auditLogger, "alice@example.com", "alice")
+
+	passkey, cred := f.register(t, ctx, f.device, "  Laptop  ")
+	if passkey.GetName() != "Laptop" || passkey.GetAttestationFormat() != "none" || len(passkey.GetTransports()) != 1 {
+		t.Fatalf("FinishPasskeyRegistration = %+v", passkey)
+	}
+
+	// discoverable credential: ไม่ระบุอีเมล ใช้ user handle จาก authenticator
+	reply, err := f.login(t, &pb.BeginPasskeyLoginRequest{}, f.device, cred)
+	if err != nil {
+		t.Fatalf("FinishPasskeyLogin: %v", err)
+	}
+	if reply.GetEmail() != "alice@example.com" || reply.GetToken() == "" {
+		t.Fatalf("FinishPasskeyLogin = %+v", reply)
+	}
+	// เข้าสู่ระบบใหม่ token เดิมถูกยกเลิกเหมือนการเข้าสู่ระบบด้วยรหัสผ่าน
+	reply, err = f.login(t, &pb.BeginPasskeyLoginRequest{Email: "alice@example.com"}, f.device, cred)
+	if err != nil {
+		t.Fatalf("FinishPasskeyLogin with an email: %v", err)
+	}
+
+	list, err := f.service.ListPasskeys(withToken(reply.GetToken()), &pb.ListPasskeysRequest{})
+	if err != nil {
+		t.Fatalf("ListPasskeys: %v", err)
+	}
+	if len(list.GetPasskeys()) != 1 || list.GetPasskeys()[0].GetLastUsedAt() == "" {
+		t.Fatalf("ListPasskeys = %+v, want one used passkey", list)
+	}
+	stored, err := f.stores.Identities.GetPasskeyByCredentialID(context.Background(), "default", webauthn.EncodeBase64URL(cred.ID))
+	if err != nil || stored.SignCount != cred.SignCount {
+		t.Fatalf("stored passkey = %+v, %v, want sign count %d", stored, err, cred.SignCount)
+	}
+}
+
+func TestPasskeyMultipleAuthenticators(t *testing.T) {
+	f := newPasskeyFixture(t)
+	ctx := registerAndLogin(t, f.stores, f.auditLogger, "bob@example.com", "bob")
+
+	laptop, _ := f.register(t, ctx, f.device, "Laptop")
+	phone := webauthntest.New(passkeyOrigin)
+	phone.BackupEligible = true
+	phone.AAGUID = []byte{0xea, 0x9b, 0x8d, 0x66, 0x4d, 0x01, 0x1d, 0x21, 0x3c, 0xe4, 0xb6, 0xb4, 0x8c, 0xb5, 0x75, 0xd4}
+	phoneKey, phoneCred := f.register(t, ctx, phone, "Phone")
+	if !phoneKey.GetBackedUp() || phoneKey.GetAaguid() != "ea9b8d66-4d01-1d21-3ce4-b6b48cb575d4" {
+		t.Fatalf("synced passkey = %+v", phoneKey)
+	}
+
+	// passkey ที่มีอยู่แล้วอยู่ใน excludeCredentials เพื่อไม่ให้ลงทะเบียน authenticator เดิมซ้ำ
+	begin, err := f.service.BeginPasskeyRegistration(ctx, &pb.BeginPasskeyRegistrationRequest{})
+	if err != nil {
+		t.Fatalf("BeginPasskeyRegistration: %v", err)
+	}
+	if !strings.Contains(begin.GetPublicKeyOptions(), webauthn.EncodeBase64URL(phoneCred.ID)) {
+		t.Fatalf("creation options do not exclude the registered passkey: %s", begin.GetPublicKeyOptions())
+	}
+
+	if _, err := f.service.RenamePasskey(ctx, &pb.RenamePasskeyRequest{Id: laptop.GetId(), Name: "Work laptop"}); err != nil {
+		t.Fatalf("RenamePasskey: %v", err)
+	}
+	if _, err := f.service.DeletePasskey(ctx, &pb.DeletePasskeyRequest{Id: laptop.GetId()}); err != nil {
+		t.Fatalf("DeletePasskey: %v", err)
+	}
+	list, _ := f.service.ListPasskeys(ctx, &pb.ListPasskeysRequest{})
+	if len(list.GetPasskeys()) != 1 || list.GetPasskeys()[0].GetName() != "Phone" {
+		t.Fatalf("ListPasskeys after delete = %+v", list)
+	}
+	if _, err := f.login(t, &pb.BeginPasskeyLoginRequest{}, phone, phoneCred); err != nil {
+		t.Fatalf("FinishPasskeyLogin with the remaining passkey: %v", err)
+	}
+
+	// ผู้ใช้อื่นจัดการ passkey ของ bob ไม่ได้
+	other := registerAndLogin(t, f.stores, f.auditLogger, "carol@example.com", "carol")
+	_, err = f.service.DeletePasskey(other, &pb.DeletePasskeyRequest{Id: phoneKey.GetId()})
+	wantCode(t, "DeletePasskey of another user", err, codes.NotFound)
+}
+
+func TestPasskeyRegistrationRejects(t *testing.T) {
+	f := newPasskeyFixture(t)
+	ctx := registerAndLogin(t, f.stores, f.auditLogger, "dave@example.com", "dave")
+
+	begin, err := f.service.BeginPasskeyRegistration(ctx, &pb.BeginPasskeyRegistrationRequest{})
+	if err != nil {
+		t.Fatalf("BeginPasskeyRegistration: %v", err)
+	}
+	phishing := webauthntest.New("https://evil.example.com")
+	response, _ := phishing.Create(t, begin.GetPublicKeyOptions())
+	_, err = f.service.FinishPasskeyRegistration(ctx, &pb.FinishPasskeyRegistrationRequest{SessionId: begin.GetSessionId(), Credential: response})
+	wantCode(t, "FinishPasskeyRegistration from another origin", err, codes.InvalidArgument)
+	// session ใช้ได้ครั้งเดียวแม้การลงทะเบียนไม่สำเร็จ
+	response, _ = f.device.Create(t, begin.GetPublicKeyOptions())
+	_, err = f.service.FinishPasskeyRegistration(ctx, &pb.FinishPasskeyRegistrationRequest{SessionId: begin.GetSessionId(), Credential: response})
+	wantCode(t, "FinishPasskeyRegistration with a used session", err, codes.FailedPrecondition)
+
+	// session ของผู้ใช้คนหนึ่งใช้ลงทะเบียนให้อีกคนไม่ได้
+	begin, _ = f.service.BeginPasskeyRegistration(ctx, &pb.BeginPasskeyRegistrationRequest{})
+	response, _ = f.device.Create(t, begin.GetPublicKeyOptions())
+	other := registerAndLogin(t, f.stores, f.auditLogger, "erin@example.com", "erin")
+	_, err = f.service.FinishPasskeyRegistration(other, &pb.FinishPasskeyRegistrationRequest{SessionId: begin.GetSessionId(), Credential: response})
+	wantCode(t, "FinishPasskeyRegistration with the session of another user", err, codes.PermissionDenied)
+}
+
+func TestPasskeyLoginRejects(t *testing.T) {
+	f := newPasskeyFixture(t)
+	ctx := registerAndLogin(t, f.stores, f.auditLogger, "frank@example.com", "frank")
+	_, cred := f.register(t, ctx, f.device, "Laptop")
+
+	// response เดิมส่งซ้ำไม่ได้เพราะ session ถูกใช้ไปแล้ว
+	sessionID, response := f.assert(t, &pb.BeginPasskeyLoginRequest{}, f.device, cred)
+	if _, err := f.service.FinishPasskeyLogin(context.Background(), &pb.FinishPasskeyLoginRequest{SessionId: sessionID, Credential: response}); err != nil {
+		t.Fatalf("FinishPasskeyLogin: %v", err)
+	}
+	_, err := f.service.FinishPasskeyLogin(context.Background(), &pb.FinishPasskeyLoginRequest{SessionId: sessionID, Credential: response})
+	wantCode(t, "FinishPasskeyLogin with a used session", err, codes.FailedPrecondition)
+
+	// authenticator ที่ถูกคัดลอกมี sign counter เก่า
+	clone := *cred
+	clone.SignCount = 0
+	_, err = f.login(t, &pb.BeginPasskeyLoginRequest{}, f.device, &clone)
+	wantCode(t, "FinishPasskeyLogin with a stale sign counter", err, codes.Unauthenticated)
+
+	// เว็บไซต์ปลอมที่ส่งต่อ challenge มาให้ผู้ใช้
+	phishing := *f.device
+	phishing.Origin = "https://evil.example.com"
+	_, err = f.login(t, &pb.BeginPasskeyLoginRequest{}, &phishing, cred)
+	wantCode(t, "FinishPasskeyLogin from another origin", err, codes.Unauthenticated)
+
+	// การเข้าสู่ระบบด้วย passkey อย่างเดียวต้องยืนยันตัวตนบน authenticator
+	unverified := *f.device
+	unverified.UserVerified = false
+	_, err = f.login(t, &pb.BeginPasskeyLoginRequest{}, &unverified, cred)
+	wantCode(t, "FinishPasskeyLogin without user verification", err, codes.Unauthenticated)
+
+	// passkey ของผู้ใช้อื่นใช้กับอีเมลนี้ไม่ได้
+	otherCtx := registerAndLogin(t, f.stores, f.auditLogger, "grace@example.com", "grace")
+	otherDevice := webauthntest.New(passkeyOrigin)
+	_, otherCred := f.register(t, otherCtx, otherDevice, "Phone")
+	begin, err := f.service.BeginPasskeyLogin(context.Background(), &pb.BeginPasskeyLoginRequest{Email: "frank@example.com"})
+	if err != nil {
+		t.Fatalf("BeginPasskeyLogin: %v", err)
+	}
+	response = otherDevice.Get(t, strings.Replace(begin.GetPublicKeyOptions(), webauthn.EncodeBase64URL(cred.ID), webauthn.EncodeBase64URL(otherCred.ID), 1), otherCred)
+	_, err = f.service.FinishPasskeyLogin(context.Background(), &pb.FinishPasskeyLoginRequest{SessionId: begin.GetSessionId(), Credential: response})
+	wantCode(t, "FinishPasskeyLogin with the passkey of another user", err, codes.Unauthenticated)
+
+	_, err = f.service.BeginPasskeyLogin(context.Background(), &pb.BeginPasskeyLoginRequest{Email: "nobody@example.com"})
+	wantCode(t, "BeginPasskeyLogin of an unknown email", err, codes.NotFound)
+	registerAndLogin(t, f.stores, f.auditLogger, "heidi@example.com", "heidi")
+	_, err = f.service.BeginPasskeyLogin(context.Background(), &pb.BeginPasskeyLoginRequest{Email: "heidi@example.com"})
+	wantCode(t, "BeginPasskeyLogin of a user without passkeys", err, codes.FailedPrecondition)
+}
+
+func TestPasskeySecondFactor(t *testing.T) {
+	f := newPasskeyFixture(t)
+	ctx := registerAndLogin(t, f.stores, f.auditLogger, "ivan@example.com", "ivan")
+	_, err := f.service.SetPasskeyRequired(ctx, &pb.SetPasskeyRequiredRequest{Required: true})
+	wantCode(t, "SetPasskeyRequired without passkeys", err, codes.FailedPrecondition)
+	passkey, cred := f.register(t, ctx, f.device, "Security key")
+	if _, err := f.service.SetPasskeyRequired(ctx, &pb.SetPasskeyRequiredRequest{Required: true}); err != nil {
+		t.Fatalf("SetPasskeyRequired: %v", err)
+	}
+
+	// รหัสผ่านถูกต้องแล้วยังไม่ได้ token จนกว่าจะยืนยันด้วย passkey
+	authService := NewAuthService(f.stores, notify.NewLogNotifier(), f.auditLogger)
+	pending, err := authService.Login(context.Background(), &pb.LoginRequest{Email: "ivan@example.com", Password: testPassword})
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if !pending.GetSecondFactorRequired() || pending.GetToken() != "" || pending.GetSecondFactorToken() == "" {
+		t.Fatalf("Login = %+v, want a second factor challenge", pending)
+	}
+
+	// ปัจจัยที่สองไม่ต้องยืนยันตัวตนบน authenticator (security key ที่ไม่มี PIN ใช้ได้)
+	securityKey := *f.device
+	securityKey.UserVerified = false
+	in := &pb.BeginPasskeyLoginRequest{SecondFactorToken: pending.GetSecondFactorToken()}
+	reply, err := f.login(t, in, &securityKey, cred)
+	if err != nil {
+		t.Fatalf("FinishPasskeyLogin as a second factor: %v", err)
+	}
+	if reply.GetEmail() != "ivan@example.com" || reply.GetToken() == "" {
+		t.Fatalf("FinishPasskeyLogin = %+v", reply)
+	}
+
+	// secondFactorToken ใช้ได้ครั้งเดียว
+	_, err = f.service.BeginPasskeyLogin(context.Background(), in)
+	wantCode(t, "BeginPasskeyLogin with a used second factor token", err, codes.FailedPrecondition)
+
+	// ลบ passkey สุดท้ายแล้ว การบังคับใช้ถูกยกเลิก เข้าสู่ระบบด้วยรหัสผ่านได้ตามปกติ
+	if _, err := f.service.DeletePasskey(withToken(reply.GetToken()), &pb.DeletePasskeyRequest{Id: passkey.GetId()}); err != nil {
+		t.Fatalf("DeletePasskey: %v", err)
+	}
+	after, err := authService.Login(context.Background(), &pb.LoginRequest{Email: "ivan@example.com", Password: testPassword})
+	if err != nil || after.GetSecondFactorRequired() || after.GetToken() == "" {
+		t.Fatalf("Login after deleting the last passkey = %+v, %v", after, err)
+	}
+}
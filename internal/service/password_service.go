@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	pb "auth-microservice/auth-microservice/proto"
+	"auth-microservice/internal/auth"
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/validation"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *AuthService) ChangePassword(ctx context.Context, in *pb.ChangePasswordRequest) (*pb.ChangePasswordReply, error) {
+	// ตรวจสอบ token ของผู้ใช้ที่เรียก
+	_, claims, err := s.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	email, _ := claims["email"].(string)
+	if email == "" {
+		return nil, status.Error(codes.InvalidArgument, "ไม่สามารถระบุผู้ใช้จากโทเค็นได้")
+	}
+
+	// รหัสผ่านใหม่ต้องเป็นไปตาม policy ของ tenant ของผู้ใช้
+	tenant, err := activeTenant(ctx, s.Tenants, claimsTenant(claims))
+	if err != nil {
+		return nil, err
+	}
+
+	// ผู้ใช้ของ directory เปลี่ยนรหัสผ่านที่ directory ขององค์กร
+	if err := s.directoryManagedPassword(ctx, tenant.ID, email); err != nil {
+		return nil, err
+	}
+
+	// ค้นหาผู้ใช้พร้อมรหัสผ่านปัจจุบันและประวัติรหัสผ่าน
+	user, err := s.Users.GetUserByEmail(ctx, tenant.ID, email)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, "ไม่พบผู้ใช้")
+	}
+
+	// ใช้ rate limit เดียวกับ Login เพื่อกันการเดารหัสผ่านปัจจุบัน
+	isLimited, err := s.isRateLimited(ctx, tenant.ID, email)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถตรวจสอบ Rate Limit ได้")
+	}
+	if isLimited {
+		return nil, status.Error(codes.ResourceExhausted, "คุณพยายามบ่อยเกินไป กรุณารอ 1 นาที")
+	}
+
+	// ตรวจสอบรหัสผ่านปัจจุบัน
+	if err := auth.ComparePassword(user.Password, in.GetCurrentPassword()); err != nil {
+		return nil, status.Error(codes.Unauthenticated, "รหัสผ่านปัจจุบันไม่ถูกต้อง")
+	}
+
+	// ตรวจสอบรหัสผ่านใหม่ตาม policy และเข้ารหัส
+	hashedPassword, err := validation.ValidatePassword(in.GetNewPassword(), tenant.PasswordPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	// ห้ามใช้รหัสผ่านซ้ำกับรหัสปัจจุบันหรือ N รหัสล่าสุด
+	for _, oldHash := range append([]string{user.Password}, user.PasswordHistory...) {
+		if auth.ComparePassword(oldHash, in.GetNewPassword()) == nil {
+			return nil, status.Error(codes.InvalidArgument, "ไม่สามารถใช้รหัสผ่านที่เคยใช้ล่าสุดได้")
+		}
+	}
+
+	// บันทึกรหัสผ่านใหม่ และเก็บ hash เดิมไว้ในประวัติ (จำกัดจำนวนตาม HistorySize ของ tenant)
+	now := time.Now()
+	if err := s.Users.UpdatePassword(ctx, user.ID.Hex(), user.Password, hashedPassword, tenant.PasswordPolicy.HistorySize, now); err != nil {
+		return nil, status.Error(codes.Internal, "เกิดข้อผิดพลาดในการเปลี่ยนรหัสผ่าน")
+	}
+
+	s.Audit.Record(ctx, models.AuditEvent{
+		TenantID:     tenant.ID,
+		Action:       "password.changed",
+		ActorEmail:   email,
+		SubjectID:    user.ID.Hex(),
+		SubjectEmail: email,
+		CreatedAt:    now,
+	})
+
+	// ยกเลิก token เดิมทั้งหมด แล้วออก token ใหม่ให้ session ปัจจุบัน
+	if err := s.revokeActiveToken(ctx, tenant.ID, email); err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถยกเลิกโทเค็นเดิมได้")
+	}
+	// refresh token ที่ออกให้ OAuth client ก่อนเปลี่ยนรหัสผ่านต้องใช้ไม่ได้เช่นกัน
+	if _, err := revokeConsents(ctx, s.Consents, tenant.ID, user.ID.Hex()); err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถยกเลิกความยินยอมของผู้ใช้ได้")
+	}
+	token, err := s.issueToken(ctx, tenant, user.ID.Hex(), email, user.Role)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "เจอข้อผิดพลาดในการสร้างโทเค็น")
+	}
+
+	return &pb.ChangePasswordReply{
+		Message:           "เปลี่ยนรหัสผ่านสำเร็จ",
+		Token:             token,
+		PasswordChangedAt: now.Format(time.RFC3339),
+	}, nil
+}
+
+// ตรวจสอบว่ารหัสผ่านของผู้ใช้มีอายุเกิน MaxAge ของ policy หรือไม่ (0 = ไม่มีวันหมดอายุ)
+// ผู้ใช้เก่าที่ยังไม่มี passwordChangedAt จะใช้ createdAt แทน
+func isPasswordExpired(user *models.User, policy models.PasswordPolicy) bool {
+	if policy.MaxAge <= 0 {
+		return false
+	}
+	changedAt := user.CreatedAt
+	if user.PasswordChangedAt != nil {
+		changedAt = *user.PasswordChangedAt
+	}
+	if changedAt.IsZero() {
+		return false
+	}
+	return time.Since(changedAt) > policy.MaxAge
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	pb "auth-microservice/auth-microservice/proto"
+	"auth-microservice/internal/audit"
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/notify"
+
+	"google.golang.org/grpc/codes"
+)
+
+func TestChangePasswordRejectsRecentPasswords(t *testing.T) {
+	// รหัสผ่านที่เคยใช้ตามลำดับ: passwords[0] คือรหัสตอนสมัคร และ passwords[3] คือรหัสปัจจุบัน
+	passwords := []string{testPassword, "Secret124!", "Secret125!", "Secret126!"}
+
+	for _, tc := range []struct {
+		historySize int
+		reuse       int // index ของรหัสผ่านที่ลองใช้ซ้ำ
+		wantReject  bool
+	}{
+		{0, 3, true}, // รหัสปัจจุบันใช้ซ้ำไม่ได้เสมอ
+		{0, 2, false},
+		{0, 0, false},
+		{2, 3, true},
+		{2, 2, true},
+		{2, 1, true},
+		{2, 0, false}, // เก่ากว่า HistorySize รหัส
+		{5, 0, true},
+	} {
+		t.Run(fmt.Sprintf("history %d reuse %d", tc.historySize, tc.reuse), func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+			stores := newTestStores(t)
+			auditLogger := audit.NewLogger(stores.Audit)
+			tenant, err := loadTenant(ctx, stores.Tenants, models.DefaultTenantID)
+			if err != nil {
+				t.Fatalf("loadTenant: %v", err)
+			}
+			tenant.PasswordPolicy.HistorySize = tc.historySize
+			if err := stores.Tenants.SaveTenant(ctx, tenant); err != nil {
+				t.Fatalf("SaveTenant: %v", err)
+			}
+
+			service := NewAuthService(stores, notify.NewLogNotifier(), auditLogger)
+			userCtx := registerAndLogin(t, stores, auditLogger, "alice@example.com", "alice")
+			for i := 1; i < len(passwords); i++ {
+				reply, err := service.ChangePassword(userCtx, &pb.ChangePasswordRequest{CurrentPassword: passwords[i-1], NewPassword: passwords[i]})
+				if err != nil {
+					t.Fatalf("ChangePassword to password %d: %v", i, err)
+				}
+				userCtx = withToken(reply.GetToken())
+			}
+
+			_, err = service.ChangePassword(userCtx, &pb.ChangePasswordRequest{CurrentPassword: passwords[3], NewPassword: passwords[tc.reuse]})
+			if tc.wantReject {
+				wantCode(t, "ChangePassword to a recent password", err, codes.InvalidArgument)
+				return
+			}
+			if err != nil {
+				t.Fatalf("ChangePassword to an old password: %v", err)
+			}
+		})
+	}
+}
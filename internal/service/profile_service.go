@@ -0,0 +1,237 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	pb "auth-microservice/auth-microservice/proto"
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/store"
+	"auth-microservice/internal/validation"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *UserService) GetProfileSchema(ctx context.Context, in *pb.GetProfileSchemaRequest) (*pb.ProfileSchema, error) {
+	// ผู้ใช้ที่เข้าสู่ระบบแล้วดู schema ได้ เพื่อใช้สร้างฟอร์มแก้ไขโปรไฟล์
+	if _, _, err := authenticate(ctx, s.Blacklist, s.Tenants); err != nil {
+		return nil, err
+	}
+
+	schema, err := s.loadProfileSchema(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถดึง schema ของโปรไฟล์ได้")
+	}
+	return toProfileSchemaReply(schema), nil
+}
+
+func (s *UserService) UpdateProfileSchema(ctx context.Context, in *pb.ProfileSchema) (*pb.ProfileSchema, error) {
+	// schema ใช้ร่วมกันทุก tenant จึงแก้ไขได้เฉพาะ admin ของระบบ
+	claims, err := requirePlatformAdmin(ctx, s.Blacklist, s.Tenants)
+	if err != nil {
+		return nil, err
+	}
+
+	attributes := make([]models.ProfileAttribute, 0, len(in.GetAttributes()))
+	for _, attr := range in.GetAttributes() {
+		attributes = append(attributes, models.ProfileAttribute{
+			Key:           attr.GetKey(),
+			Type:          attr.GetType(),
+			Required:      attr.GetRequired(),
+			MaxLength:     int(attr.GetMaxLength()),
+			AllowedValues: attr.GetAllowedValues(),
+		})
+	}
+	if err := validation.ValidateProfileSchema(attributes); err != nil {
+		return nil, err
+	}
+
+	// บันทึก schema (สร้างใหม่ถ้ายังไม่มี)
+	schema := models.ProfileSchema{
+		Attributes: attributes,
+		UpdatedAt:  time.Now(),
+	}
+	if err := s.Settings.SaveProfileSchema(ctx, schema); err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถบันทึก schema ของโปรไฟล์ได้")
+	}
+
+	adminEmail, _ := claims["email"].(string)
+	s.Audit.Record(ctx, models.AuditEvent{
+		Action:     "profile_schema.updated",
+		ActorEmail: adminEmail,
+	})
+	return toProfileSchemaReply(schema.Attributes), nil
+}
+
+// ดึง schema ของ metadata (ถ้ายังไม่เคยกำหนด จะได้ schema ว่าง = ไม่อนุญาต key ใด ๆ)
+func (s *UserService) loadProfileSchema(ctx context.Context) ([]models.ProfileAttribute, error) {
+	return s.Settings.GetProfileSchema(ctx)
+}
+
+// ตรวจสอบ field ที่ระบุใน updateMask แล้วสร้างรายการแก้ไขโปรไฟล์
+func (s *UserService) buildProfileUpdate(ctx context.Context, tenantID string, in *pb.UpdateUserRequest) (store.ProfilePatch, error) {
+	var patch store.ProfilePatch
+	paths := in.GetUpdateMask().GetPaths()
+	if len(paths) == 0 {
+		// ไม่ระบุ mask: อัปเดตเฉพาะ field ที่ส่งค่ามา (รองรับ client เดิมที่ส่งแค่ username)
+		paths = presentProfilePaths(in)
+	}
+	if len(paths) == 0 {
+		return patch, status.Error(codes.InvalidArgument, "ไม่มีข้อมูลที่ต้องการอัปเดต")
+	}
+
+	var schema []models.ProfileAttribute
+	for _, path := range paths {
+		if path == "metadata" || strings.HasPrefix(path, "metadata.") {
+			var err error
+			if schema, err = s.loadProfileSchema(ctx); err != nil {
+				return patch, status.Error(codes.Internal, "ไม่สามารถดึง schema ของโปรไฟล์ได้")
+			}
+			break
+		}
+	}
+
+	// ค่าว่างหมายถึงลบค่าของ field นั้นออก
+	value := func(v string) *string { return &v }
+
+	for _, path := range paths {
+		switch {
+		case path == "username":
+			if err := validation.ValidateUsername(in.GetUsername(), ctx, s.Users, tenantID); err != nil {
+				return patch, err
+			}
+			patch.Username = value(in.GetUsername())
+		case path == "displayName":
+			if err := validation.ValidateDisplayName(in.GetDisplayName()); err != nil {
+				return patch, err
+			}
+			patch.DisplayName = value(in.GetDisplayName())
+		case path == "avatarUrl":
+			if err := validation.ValidateAvatarURL(in.GetAvatarUrl()); err != nil {
+				return patch, err
+			}
+			patch.AvatarURL = value(in.GetAvatarUrl())
+		case path == "locale":
+			if err := validation.ValidateLocale(in.GetLocale()); err != nil {
+				return patch, err
+			}
+			patch.Locale = value(in.GetLocale())
+		case path == "timezone":
+			if err := validation.ValidateTimezone(in.GetTimezone()); err != nil {
+				return patch, err
+			}
+			patch.Timezone = value(in.GetTimezone())
+		case path == "phone":
+			if err := validation.ValidatePhone(in.GetPhone()); err != nil {
+				return patch, err
+			}
+			patch.Phone = value(in.GetPhone())
+		case path == "metadata":
+			// แทนที่ metadata ทั้งชุด
+			if err := validation.ValidateMetadata(in.GetMetadata(), schema); err != nil {
+				return patch, err
+			}
+			patch.ReplaceMetadata = true
+			patch.Metadata = in.GetMetadata()
+		case strings.HasPrefix(path, "metadata."):
+			// แก้ไขเฉพาะ key เดียว โดยไม่กระทบ key อื่น
+			key := strings.TrimPrefix(path, "metadata.")
+			v := in.GetMetadata()[key]
+			if v == "" {
+				if attr, ok := validation.FindProfileAttribute(key, schema); ok && attr.Required {
+					return patch, status.Errorf(codes.InvalidArgument, "ไม่สามารถลบ %q ที่จำเป็นต้องมีได้", key)
+				}
+			} else if err := validation.ValidateMetadataValue(key, v, schema); err != nil {
+				return patch, err
+			}
+			if patch.Metadata == nil {
+				patch.Metadata = map[string]string{}
+			}
+			patch.Metadata[key] = v
+		default:
+			return patch, status.Errorf(codes.InvalidArgument, "ไม่รองรับการอัปเดต field %q", path)
+		}
+	}
+	return patch, nil
+}
+
+// รายการ field ที่มีค่าส่งมาใน request ใช้แทน updateMask เมื่อ client ไม่ได้ระบุ
+func presentProfilePaths(in *pb.UpdateUserRequest) []string {
+	var paths []string
+	fields := []struct {
+		path  string
+		value string
+	}{
+		{"username", in.GetUsername()},
+		{"displayName", in.GetDisplayName()},
+		{"avatarUrl", in.GetAvatarUrl()},
+		{"locale", in.GetLocale()},
+		{"timezone", in.GetTimezone()},
+		{"phone", in.GetPhone()},
+	}
+	for _, f := range fields {
+		if f.value != "" {
+			paths = append(paths, f.path)
+		}
+	}
+	for key := range in.GetMetadata() {
+		paths = append(paths, "metadata."+key)
+	}
+	return paths
+}
+
+// แปลง models.User เป็น UserIdReply
+func toUserIdReply(u models.User) *pb.UserIdReply {
+	return &pb.UserIdReply{
+		Id:            u.ID.Hex(),
+		TenantId:      u.TenantID,
+		Email:         u.Email,
+		Username:      u.Username,
+		CreatedAt:     u.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:     u.UpdatedAt.Format(time.RFC3339),
+		Role:          u.Role,
+		DisplayName:   u.DisplayName,
+		AvatarUrl:     u.AvatarURL,
+		Locale:        u.Locale,
+		Timezone:      u.Timezone,
+		Phone:         u.Phone,
+		Metadata:      u.Metadata,
+		EmailVerified: u.EmailVerified,
+	}
+}
+
+// แปลง models.User เป็น UserItem สำหรับ ListUsers
+func toUserItem(u models.User) *pb.UserItem {
+	return &pb.UserItem{
+		Id:            u.ID.Hex(),
+		TenantId:      u.TenantID,
+		Email:         u.Email,
+		Username:      u.Username,
+		CreatedAt:     u.CreatedAt.Format(time.RFC3339),
+		Role:          u.Role,
+		UpdatedAt:     u.UpdatedAt.Format(time.RFC3339),
+		DisplayName:   u.DisplayName,
+		AvatarUrl:     u.AvatarURL,
+		Locale:        u.Locale,
+		Timezone:      u.Timezone,
+		Phone:         u.Phone,
+		Metadata:      u.Metadata,
+		EmailVerified: u.EmailVerified,
+	}
+}
+
+func toProfileSchemaReply(attributes []models.ProfileAttribute) *pb.ProfileSchema {
+	reply := &pb.ProfileSchema{}
+	for _, attr := range attributes {
+		reply.Attributes = append(reply.Attributes, &pb.ProfileAttribute{
+			Key:           attr.Key,
+			Type:          attr.Type,
+			Required:      attr.Required,
+			MaxLength:     int32(attr.MaxLength),
+			AllowedValues: attr.AllowedValues,
+		})
+	}
+	return reply
+}
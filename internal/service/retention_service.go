@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// ลบผู้ใช้ถาวรทุกคนที่ถูก soft delete มานานกว่า retention แล้วคืนจำนวนที่ลบได้
+func (s *UserService) PurgeExpiredUsers(ctx context.Context, retention time.Duration) (int, error) {
+	ids, err := s.Users.ListDeletedBefore(ctx, time.Now().Add(-retention))
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, id := range ids {
+		user, err := s.Users.GetUserByID(ctx, id, true)
+		if err != nil {
+			log.Printf("Could not load user %s: %v", id, err)
+			continue
+		}
+		if err := s.purgeUser(ctx, user, "retention-job"); err != nil {
+			log.Printf("Could not purge user %s: %v", id, err)
+			continue
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// รันงานลบผู้ใช้ที่หมดระยะเก็บรักษาเป็นรอบ ๆ จนกว่า ctx จะถูกยกเลิก
+func (s *UserService) RunRetentionJob(ctx context.Context, interval time.Duration, retention time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := s.PurgeExpiredUsers(ctx, retention)
+		if err != nil {
+			log.Printf("Retention job failed: %v", err)
+		} else if purged > 0 {
+			log.Printf("Retention job purged %d users", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	pb "auth-microservice/auth-microservice/proto"
+	"auth-microservice/internal/audit"
+	"auth-microservice/internal/events"
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/notify"
+	"auth-microservice/internal/store"
+)
+
+func TestPurgeExpiredUsersCutoff(t *testing.T) {
+	const retention = 30 * 24 * time.Hour
+	ctx := context.Background()
+	stores := newTestStores(t)
+	auditLogger := audit.NewLogger(stores.Audit)
+	authService := NewAuthService(stores, notify.NewLogNotifier(), auditLogger)
+	userService := NewUserService(stores, events.NewMemoryBus(), auditLogger)
+	now := time.Now()
+
+	cases := []struct {
+		name       string
+		deletedAgo time.Duration // 0 = ไม่ถูกลบ
+		wantPurged bool
+	}{
+		{"never deleted", 0, false},
+		{"deleted yesterday", 24 * time.Hour, false},
+		{"one hour before the cutoff", retention - time.Hour, false},
+		{"one hour past the cutoff", retention + time.Hour, true},
+		{"long ago", 3 * retention, true},
+	}
+	ids := make([]string, len(cases))
+	for i, tc := range cases {
+		email := fmt.Sprintf("user%d@example.com", i)
+		if _, err := authService.Register(ctx, &pb.RegisterRequest{Email: email, Username: fmt.Sprintf("user%d", i), Password: testPassword}); err != nil {
+			t.Fatalf("Register(%s): %v", tc.name, err)
+		}
+		user, err := stores.Users.GetUserByEmail(ctx, models.DefaultTenantID, email)
+		if err != nil {
+			t.Fatalf("GetUserByEmail(%s): %v", tc.name, err)
+		}
+		ids[i] = user.ID.Hex()
+		if tc.deletedAgo > 0 {
+			if _, err := stores.Users.SoftDeleteUser(ctx, ids[i], now.Add(-tc.deletedAgo)); err != nil {
+				t.Fatalf("SoftDeleteUser(%s): %v", tc.name, err)
+			}
+		}
+	}
+
+	purged, err := userService.PurgeExpiredUsers(ctx, retention)
+	if err != nil {
+		t.Fatalf("PurgeExpiredUsers: %v", err)
+	}
+	want := 0
+	for i, tc := range cases {
+		_, err := stores.Users.GetUserByID(ctx, ids[i], true)
+		if gone := errors.Is(err, store.ErrNotFound); gone != tc.wantPurged {
+			t.Errorf("%s: purged = %v (%v), want %v", tc.name, gone, err, tc.wantPurged)
+		}
+		if tc.wantPurged {
+			want++
+		}
+	}
+	if purged != want {
+		t.Fatalf("PurgeExpiredUsers = %d, want %d", purged, want)
+	}
+
+	// รันซ้ำไม่มีอะไรให้ลบ
+	if purged, err := userService.PurgeExpiredUsers(ctx, retention); err != nil || purged != 0 {
+		t.Fatalf("second PurgeExpiredUsers = %d, %v, want 0", purged, err)
+	}
+}
@@ -1,35 +1,327 @@
 package service
 
 import (
-	pb "auth-microservice/auth-microservice/proto"
+	"strings"
+	"sync"
 
-	"github.com/redis/go-redis/v9"
-	"go.mongodb.org/mongo-driver/mongo"
+	pb "auth-microservice/auth-microservice/proto"
+	"auth-microservice/internal/audit"
+	"auth-microservice/internal/directory"
+	"auth-microservice/internal/events"
+	"auth-microservice/internal/federation"
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/notify"
+	"auth-microservice/internal/store"
+	"auth-microservice/internal/webauthn"
 )
 
 // ฝัง default implementation เข้าไปใน struct ของเรา
 type AuthService struct {
-	UserCollection                    *mongo.Collection // MongoDB collection สำหรับเก็บข้อมูลผู้ใช้
-	BlacklistCollection               *mongo.Collection // MongoDB collection สำหรับเก็บ token ที่ถูก blacklist
-	Redis                             *redis.Client     // Redis client สำหรับใช้เก็บข้อมูลชั่วคราว เช่น rate limit และ token
-	pb.UnimplementedAuthServiceServer                   // ฝัง default implementation ของ AuthService (จาก gRPC proto)
+	Tenants    store.TenantStore      // ที่เก็บ tenant พร้อม policy และ signing key
+	Users      store.UserStore        // ที่เก็บข้อมูลผู้ใช้ (MongoDB หรือ PostgreSQL)
+	Groups     store.GroupStore       // ที่เก็บกลุ่มและสมาชิก ใช้ใส่กลุ่มใน token และรับคำเชิญตอนสมัคร
+	Blacklist  store.BlacklistStore   // ที่เก็บ token ที่ถูก blacklist
+	Sessions   store.SessionStore     // ที่เก็บ active token ของผู้ใช้
+	Cache      store.KeyValueStore    // ที่เก็บข้อมูลชั่วคราว เช่น rate limit และ token ยืนยันอีเมล
+	Notifier   notify.Notifier        // ช่องทางส่งข้อความถึงผู้ใช้ เช่น อีเมลยืนยัน
+	Audit      *audit.Logger          // บันทึกเหตุการณ์สำคัญ เช่น การเข้าสู่ระบบ
+	Identities store.IdentityStore    // ที่เก็บ directory (LDAP) ที่ใช้ตรวจสอบรหัสผ่านแทนรหัสผ่านในระบบนี้
+	Outbox     store.OutboxStore      // บันทึกผู้ใช้ใหม่และ token ที่ถูกยกเลิกพร้อม domain event ใน transaction เดียวกัน
+	Consents   store.OAuthClientStore // ความยินยอมของ OAuth client ยกเลิกเมื่อเปลี่ยนรหัสผ่านเพื่อให้ refresh token ใช้ไม่ได้
+	// สร้าง connector ของ directory (ค่าเริ่มต้นคือ LDAP)
+	NewDirectory func(cfg *models.Directory) (directory.Directory, error)
+
+	directoriesMu                     sync.Mutex
+	directories                       map[string]cachedDirectory // connector ที่สร้างแล้วตาม ID ของ directory
+	pb.UnimplementedAuthServiceServer                            // ฝัง default implementation ของ AuthService (จาก gRPC proto)
 }
 
-// สร้างอินสแตนซ์ของ AuthService พร้อมกำหนด collection และ redis client
-func NewAuthService(userCol *mongo.Collection, blacklistCol *mongo.Collection, rdb *redis.Client) *AuthService { //dependecy injection
+// สร้างอินสแตนซ์ของ AuthService พร้อมกำหนด store, notifier และ audit logger
+func NewAuthService(stores *store.Stores, notifier notify.Notifier, auditLogger *audit.Logger) *AuthService { //dependecy injection
 	return &AuthService{
-		UserCollection:      userCol,
-		BlacklistCollection: blacklistCol,
-		Redis:               rdb,
+		Tenants:      stores.Tenants,
+		Users:        stores.Users,
+		Groups:       stores.Groups,
+		Blacklist:    stores.Blacklist,
+		Sessions:     stores.Sessions,
+		Cache:        stores.Cache,
+		Notifier:     notifier,
+		Audit:        auditLogger,
+		Identities:   stores.Identities,
+		Outbox:       stores.Outbox,
+		Consents:     stores.OAuthClients,
+		NewDirectory: newLDAPDirectory,
 	}
 }
 
 type UserService struct {
-	UserCollection *mongo.Collection
+	Tenants    store.TenantStore      // ที่เก็บ tenant ใช้ตรวจสอบ token และ tenant ของผู้เรียก
+	Users      store.UserStore        // ที่เก็บข้อมูลผู้ใช้
+	Identities store.IdentityStore    // ที่เก็บบัญชีภายนอกที่ผูกไว้ ลบตามเมื่อลบผู้ใช้ถาวร
+	Blacklist  store.BlacklistStore   // ที่เก็บ token ที่ถูก blacklist
+	Sessions   store.SessionStore     // ที่เก็บ active token ของผู้ใช้
+	Settings   store.SettingsStore    // ที่เก็บการตั้งค่า เช่น schema ของโปรไฟล์
+	Cache      store.KeyValueStore    // ที่เก็บข้อมูลชั่วคราว ใช้ลบ key ของผู้ใช้ เช่น rate limit
+	Outbox     store.OutboxStore      // แก้ไข/ลบผู้ใช้พร้อมบันทึก domain event ใน transaction เดียวกัน
+	Changes    store.UserWatcher      // change stream ของผู้ใช้ (nil = ใช้ event จาก outbox)
+	Consents   store.OAuthClientStore // ความยินยอมของ OAuth client ยกเลิกเพื่อให้ refresh token ใช้ไม่ได้
+	Bus        *events.MemoryBus      // แจ้ง WatchUsers เมื่อ relay เผยแพร่ event ใหม่
+	Audit      *audit.Logger          // บันทึกเหตุการณ์สำคัญ เช่น การลบหรือกู้คืนผู้ใช้
 	pb.UnimplementedUserServiceServer
 }
 
 // สร้างอินสแตนซ์ของ UserService
-func NewUserService(col *mongo.Collection) *UserService {
-	return &UserService{UserCollection: col}
+func NewUserService(stores *store.Stores, bus *events.MemoryBus, auditLogger *audit.Logger) *UserService {
+	return &UserService{
+		Tenants:    stores.Tenants,
+		Users:      stores.Users,
+		Identities: stores.Identities,
+		Blacklist:  stores.Blacklist,
+		Sessions:   stores.Sessions,
+		Settings:   stores.Settings,
+		Cache:      stores.Cache,
+		Outbox:     stores.Outbox,
+		Changes:    stores.UserChanges,
+		Consents:   stores.OAuthClients,
+		Bus:        bus,
+		Audit:      auditLogger,
+	}
+}
+
+type TenantService struct {
+	Tenants   store.TenantStore    // ที่เก็บ tenant พร้อม policy และ signing key
+	Blacklist store.BlacklistStore // ที่เก็บ token ที่ถูก blacklist
+	Audit     *audit.Logger        // บันทึกเหตุการณ์สำคัญ เช่น การสร้างหรือระงับ tenant
+	pb.UnimplementedTenantServiceServer
+}
+
+// สร้างอินสแตนซ์ของ TenantService
+func NewTenantService(stores *store.Stores, auditLogger *audit.Logger) *TenantService {
+	return &TenantService{
+		Tenants:   stores.Tenants,
+		Blacklist: stores.Blacklist,
+		Audit:     auditLogger,
+	}
+}
+
+type GroupService struct {
+	Tenants   store.TenantStore    // ที่เก็บ tenant ใช้ตรวจสอบ token และออก token ใหม่
+	Users     store.UserStore      // ที่เก็บข้อมูลผู้ใช้
+	Groups    store.GroupStore     // ที่เก็บกลุ่มและสมาชิก
+	Blacklist store.BlacklistStore // ที่เก็บ token ที่ถูก blacklist
+	Sessions  store.SessionStore   // ที่เก็บ active token ของผู้ใช้
+	Cache     store.KeyValueStore  // ที่เก็บคำเชิญที่ยังไม่ถูกตอบรับ
+	Notifier  notify.Notifier      // ช่องทางส่งคำเชิญถึงผู้ใช้
+	Audit     *audit.Logger        // บันทึกเหตุการณ์สำคัญ เช่น การเพิ่มหรือนำสมาชิกออก
+	pb.UnimplementedGroupServiceServer
+}
+
+// สร้างอินสแตนซ์ของ GroupService
+func NewGroupService(stores *store.Stores, notifier notify.Notifier, auditLogger *audit.Logger) *GroupService {
+	return &GroupService{
+		Tenants:   stores.Tenants,
+		Users:     stores.Users,
+		Groups:    stores.Groups,
+		Blacklist: stores.Blacklist,
+		Sessions:  stores.Sessions,
+		Cache:     stores.Cache,
+		Notifier:  notifier,
+		Audit:     auditLogger,
+	}
+}
+
+type APIKeyService struct {
+	Tenants   store.TenantStore    // ที่เก็บ tenant ใช้ตรวจสอบ token และสถานะของ tenant
+	Users     store.UserStore      // ที่เก็บข้อมูลผู้ใช้ ใช้ดึงเจ้าของ key
+	APIKeys   store.APIKeyStore    // ที่เก็บ API key (เฉพาะ hash)
+	Blacklist store.BlacklistStore // ที่เก็บ token ที่ถูก blacklist
+	Cache     store.KeyValueStore  // ที่เก็บข้อมูลชั่วคราว ใช้นับ rate limit ของแต่ละ key
+	Audit     *audit.Logger        // บันทึกเหตุการณ์สำคัญ เช่น การสร้าง ใช้งาน หรือยกเลิก key
+	pb.UnimplementedAPIKeyServiceServer
+}
+
+// สร้างอินสแตนซ์ของ APIKeyService (ใช้เป็น interceptor ตรวจสอบ API key ของทุก service ด้วย)
+func NewAPIKeyService(stores *store.Stores, auditLogger *audit.Logger) *APIKeyService {
+	return &APIKeyService{
+		Tenants:   stores.Tenants,
+		Users:     stores.Users,
+		APIKeys:   stores.APIKeys,
+		Blacklist: stores.Blacklist,
+		Cache:     stores.Cache,
+		Audit:     auditLogger,
+	}
+}
+
+type ServiceAccountService struct {
+	Tenants         store.TenantStore         // ที่เก็บ tenant ใช้ตรวจสอบ token ของ admin
+	ServiceAccounts store.ServiceAccountStore // ที่เก็บ service account พร้อม client secret และ public key
+	Blacklist       store.BlacklistStore      // ที่เก็บ token ที่ถูก blacklist
+	Audit           *audit.Logger             // บันทึกเหตุการณ์สำคัญ เช่น การสร้างหรือ rotate secret
+	pb.UnimplementedServiceAccountServiceServer
+}
+
+// สร้างอินสแตนซ์ของ ServiceAccountService
+func NewServiceAccountService(stores *store.Stores, auditLogger *audit.Logger) *ServiceAccountService {
+	return &ServiceAccountService{
+		Tenants:         stores.Tenants,
+		ServiceAccounts: stores.ServiceAccounts,
+		Blacklist:       stores.Blacklist,
+		Audit:           auditLogger,
+	}
+}
+
+type OAuthService struct {
+	Tenants         store.TenantStore         // ที่เก็บ tenant พร้อม signing key และอายุของ token
+	Users           store.UserStore           // ที่เก็บผู้ใช้ ใช้ยืนยันตัวตนตอนอนุญาต client และออก ID token
+	ServiceAccounts store.ServiceAccountStore // ที่เก็บ service account ใช้ยืนยันตัวตนของ client_credentials
+	OAuthClients    store.OAuthClientStore    // ที่เก็บ OAuth client และความยินยอมของผู้ใช้
+	Blacklist       store.BlacklistStore      // ที่เก็บ token ที่ถูกยกเลิก
+	Cache           store.KeyValueStore       // authorization request, code, refresh token และกัน client assertion ถูกใช้ซ้ำ
+	Settings        store.SettingsStore       // ที่เก็บ signing key ของ ID token
+	Audit           *audit.Logger             // บันทึกการออก token และการให้ความยินยอม
+	Issuer          string                    // URL ของ service (iss ของ ID token และ aud ของ client assertion)
+
+	oidcKeys oidcKeyCache // signing key ของ ID token ที่โหลดแล้ว
+	pb.UnimplementedOAuthServiceServer
+}
+
+// สร้างอินสแตนซ์ของ OAuthService
+func NewOAuthService(stores *store.Stores, issuer string, auditLogger *audit.Logger) *OAuthService {
+	return &OAuthService{
+		Tenants:         stores.Tenants,
+		Users:           stores.Users,
+		ServiceAccounts: stores.ServiceAccounts,
+		OAuthClients:    stores.OAuthClients,
+		Blacklist:       stores.Blacklist,
+		Cache:           stores.Cache,
+		Settings:        stores.Settings,
+		Audit:           auditLogger,
+		Issuer:          strings.TrimSuffix(issuer, "/"),
+	}
+}
+
+type OAuthClientService struct {
+	Tenants      store.TenantStore      // ที่เก็บ tenant ใช้ตรวจสอบ token
+	Users        store.UserStore        // ที่เก็บผู้ใช้ ใช้หาเจ้าของความยินยอม
+	OAuthClients store.OAuthClientStore // ที่เก็บ OAuth client และความยินยอมของผู้ใช้
+	Blacklist    store.BlacklistStore   // ที่เก็บ token ที่ถูก blacklist
+	Audit        *audit.Logger          // บันทึกการลงทะเบียน client และการยกเลิกความยินยอม
+	pb.UnimplementedOAuthClientServiceServer
+}
+
+// สร้างอินสแตนซ์ของ OAuthClientService
+func NewOAuthClientService(stores *store.Stores, auditLogger *audit.Logger) *OAuthClientService {
+	return &OAuthClientService{
+		Tenants:      stores.Tenants,
+		Users:        stores.Users,
+		OAuthClients: stores.OAuthClients,
+		Blacklist:    stores.Blacklist,
+		Audit:        auditLogger,
+	}
+}
+
+type FederationService struct {
+	Tenants    store.TenantStore    // ที่เก็บ tenant พร้อม signing key ใช้ออก token หลังเข้าสู่ระบบ
+	Users      store.UserStore      // ที่เก็บผู้ใช้ ใช้หาหรือสร้างผู้ใช้ของบัญชีภายนอก
+	Groups     store.GroupStore     // ที่เก็บกลุ่มและสมาชิก ใช้ใส่กลุ่มใน token
+	Identities store.IdentityStore  // ที่เก็บ identity provider และบัญชีที่ผูกไว้
+	Blacklist  store.BlacklistStore // ที่เก็บ token ที่ถูก blacklist
+	Sessions   store.SessionStore   // ที่เก็บ active token ของผู้ใช้
+	Cache      store.KeyValueStore  // state ของการเข้าสู่ระบบที่ยังไม่เสร็จ และ SAML assertion ที่ใช้ไปแล้ว
+	Settings   store.SettingsStore  // ที่เก็บ key ที่ใช้เซ็น SAML AuthnRequest (key เดียวกับ ID token)
+	Audit      *audit.Logger        // บันทึกการเข้าสู่ระบบ การผูกบัญชี และการจัดการ provider
+	Issuer     string               // URL ของ service ใช้สร้าง entity ID และ ACS URL ของ SAML
+	// ยอมให้ provider ใช้ http://localhost และที่อยู่ภายใน (FEDERATION_ALLOW_LOCAL สำหรับเครื่อง dev เท่านั้น)
+	AllowLocal bool
+	// สร้าง connector ของ provider (ค่าเริ่มต้นคือ OpenID Connect เปลี่ยนได้เพื่อรองรับ provider แบบอื่น)
+	NewConnector func(provider *models.IdentityProvider) federation.Connector
+
+	connectorsMu sync.Mutex
+	connectors   map[string]cachedConnector // connector ที่สร้างแล้วตาม ID ของ provider
+	samlKeys     oidcKeyCache               // key ที่ใช้เซ็น SAML AuthnRequest ที่โหลดแล้ว
+	pb.UnimplementedFederationServiceServer
+}
+
+// สร้างอินสแตนซ์ของ FederationService
+func NewFederationService(stores *store.Stores, issuer string, allowLocal bool, auditLogger *audit.Logger) *FederationService {
+	s := &FederationService{
+		Tenants:    stores.Tenants,
+		Users:      stores.Users,
+		Groups:     stores.Groups,
+		Identities: stores.Identities,
+		Blacklist:  stores.Blacklist,
+		Sessions:   stores.Sessions,
+		Cache:      stores.Cache,
+		Settings:   stores.Settings,
+		Audit:      auditLogger,
+		Issuer:     strings.TrimSuffix(issuer, "/"),
+		AllowLocal: allowLocal,
+	}
+	s.NewConnector = s.newOIDCConnector
+	return s
+}
+
+type PasskeyService struct {
+	Tenants    store.TenantStore    // ที่เก็บ tenant พร้อม signing key ใช้ออก token หลังเข้าสู่ระบบ
+	Users      store.UserStore      // ที่เก็บผู้ใช้เจ้าของ passkey
+	Groups     store.GroupStore     // ที่เก็บกลุ่มและสมาชิก ใช้ใส่กลุ่มใน token
+	Identities store.IdentityStore  // ที่เก็บ passkey ของผู้ใช้
+	Blacklist  store.BlacklistStore // ที่เก็บ token ที่ถูก blacklist
+	Sessions   store.SessionStore   // ที่เก็บ active token ของผู้ใช้
+	Cache      store.KeyValueStore  // challenge ของการลงทะเบียน/เข้าสู่ระบบที่ยังไม่เสร็จ และการยืนยันปัจจัยที่สองที่รออยู่
+	Audit      *audit.Logger        // บันทึกการลงทะเบียน passkey และการเข้าสู่ระบบ
+	WebAuthn   *webauthn.RelyingParty
+	pb.UnimplementedPasskeyServiceServer
+}
+
+// สร้างอินสแตนซ์ของ PasskeyService
+func NewPasskeyService(stores *store.Stores, rp webauthn.Config, auditLogger *audit.Logger) *PasskeyService {
+	return &PasskeyService{
+		Tenants:    stores.Tenants,
+		Users:      stores.Users,
+		Groups:     stores.Groups,
+		Identities: stores.Identities,
+		Blacklist:  stores.Blacklist,
+		Sessions:   stores.Sessions,
+		Cache:      stores.Cache,
+		Audit:      auditLogger,
+		WebAuthn:   webauthn.NewRelyingParty(rp),
+	}
+}
+
+type WebhookService struct {
+	Tenants   store.TenantStore    // ที่เก็บ tenant ใช้ตรวจสอบ token ของ admin
+	Webhooks  store.WebhookStore   // ที่เก็บ webhook และคิวการส่ง
+	Blacklist store.BlacklistStore // ที่เก็บ token ที่ถูก blacklist
+	Audit     *audit.Logger        // บันทึกการสร้างและลบ webhook
+	// ยอมให้ลงทะเบียน http://localhost และที่อยู่ภายใน (WEBHOOK_ALLOW_LOCAL สำหรับเครื่อง dev เท่านั้น)
+	AllowLocal bool
+	pb.UnimplementedWebhookServiceServer
+}
+
+// สร้างอินสแตนซ์ของ WebhookService
+func NewWebhookService(stores *store.Stores, allowLocal bool, auditLogger *audit.Logger) *WebhookService {
+	return &WebhookService{
+		Tenants:    stores.Tenants,
+		Webhooks:   stores.Webhooks,
+		Blacklist:  stores.Blacklist,
+		Audit:      auditLogger,
+		AllowLocal: allowLocal,
+	}
+}
+
+type AuditService struct {
+	Tenants   store.TenantStore    // ที่เก็บ tenant ใช้ตรวจสอบ token ของ admin
+	Events    store.AuditStore     // ที่เก็บ audit log
+	Blacklist store.BlacklistStore // ที่เก็บ token ที่ถูก blacklist
+	pb.UnimplementedAuditServiceServer
+}
+
+// สร้างอินสแตนซ์ของ AuditService
+func NewAuditService(stores *store.Stores) *AuditService {
+	return &AuditService{
+		Tenants:   stores.Tenants,
+		Events:    stores.Audit,
+		Blacklist: stores.Blacklist,
+	}
 }
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"net/url"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	pb "auth-microservice/auth-microservice/proto"
+	"auth-microservice/internal/audit"
+	"auth-microservice/internal/notify"
+	"auth-microservice/internal/store"
+	"auth-microservice/internal/store/sqlstore"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const testPassword = "Secret123!"
+
+// store ทั้งหมดบน SQLite ในไดเรกทอรีชั่วคราว (ไม่ต้องใช้ MongoDB หรือ Redis)
+func newTestStores(t *testing.T) *store.Stores {
+	t.Helper()
+	ctx := context.Background()
+	params := url.Values{}
+	params.Add("_pragma", "busy_timeout(5000)")
+	params.Add("_pragma", "foreign_keys(1)")
+	sqlStore, err := sqlstore.Open(ctx, sqlstore.SQLite, "file:"+filepath.Join(t.TempDir(), "auth.db")+"?"+params.Encode())
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if _, err := sqlStore.Migrator().Up(ctx); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	stores := sqlStore.Stores()
+	t.Cleanup(func() { stores.Close(context.Background()) })
+	// เหมือนตอนเริ่ม server: tenant default ได้ signing key ของตัวเอง
+	if _, err := EnsureSigningKeys(ctx, stores.Tenants); err != nil {
+		t.Fatalf("EnsureSigningKeys: %v", err)
+	}
+	return stores
+}
+
+// context ที่แนบ token เหมือน metadata ของ gRPC
+func withToken(token string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+}
+
+// สร้างผู้ใช้ด้วย Register แล้วคืน context ที่เข้าสู่ระบบเป็นผู้ใช้นั้น
+func registerAndLogin(t *testing.T, stores *store.Stores, auditLogger *audit.Logger, email string, username string) context.Context {
+	t.Helper()
+	authService := NewAuthService(stores, notify.NewLogNotifier(), auditLogger)
+	if _, err := authService.Register(context.Background(), &pb.RegisterRequest{Email: email, Username: username, Password: testPassword}); err != nil {
+		t.Fatalf("Register(%s): %v", email, err)
+	}
+	return login(t, stores, auditLogger, email)
+}
+
+func login(t *testing.T, stores *store.Stores, auditLogger *audit.Logger, email string) context.Context {
+	t.Helper()
+	reply, err := NewAuthService(stores, notify.NewLogNotifier(), auditLogger).Login(context.Background(), &pb.LoginRequest{Email: email, Password: testPassword})
+	if err != nil {
+		t.Fatalf("Login(%s): %v", email, err)
+	}
+	return withToken(reply.GetToken())
+}
+
+// context ของ admin ระบบ (role admin ใน tenant default)
+func adminContext(t *testing.T, stores *store.Stores, auditLogger *audit.Logger) context.Context {
+	t.Helper()
+	if _, err := BootstrapAdmin(context.Background(), stores, auditLogger, "admin@example.com", "admin", testPassword); err != nil {
+		t.Fatalf("BootstrapAdmin: %v", err)
+	}
+	return login(t, stores, auditLogger, "admin@example.com")
+}
+
+func wantCode(t *testing.T, what string, err error, want codes.Code) {
+	t.Helper()
+	if got := status.Code(err); got != want {
+		t.Fatalf("%s: got %v (%v), want %v", what, got, err, want)
+	}
+}
+
+// Notifier ที่เก็บข้อความที่ส่งไว้ให้ test อ่าน (เช่น รหัสและลิงก์ในอีเมล)
+type recordingNotifier struct {
+	mu       sync.Mutex
+	messages []notify.Message
+}
+
+func (n *recordingNotifier) Send(ctx context.Context, msg notify.Message) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.messages = append(n.messages, msg)
+	return nil
+}
+
+// ข้อความล่าสุดที่ส่งถึง to
+func (n *recordingNotifier) last(t *testing.T, to string) notify.Message {
+	t.Helper()
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for i := len(n.messages) - 1; i >= 0; i-- {
+		if n.messages[i].To == to {
+			return n.messages[i]
+		}
+	}
+	t.Fatalf("no message was sent to %s", to)
+	return notify.Message{}
+}
+
+func (n *recordingNotifier) count() int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return len(n.messages)
+}
+
+// ServerStream ของ gRPC ที่เก็บข้อความที่ส่งไว้ให้ test อ่าน (เช่น WatchUsers และ ExportUsers)
+type testServerStream[T any] struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent chan *T
+}
+
+func newTestServerStream[T any](ctx context.Context) *testServerStream[T] {
+	return &testServerStream[T]{ctx: ctx, sent: make(chan *T, 1000)}
+}
+
+func (s *testServerStream[T]) Context() context.Context {
+	return s.ctx
+}
+
+func (s *testServerStream[T]) Send(msg *T) error {
+	select {
+	case s.sent <- msg:
+		return nil
+	case <-s.ctx.Done():
+		return s.ctx.Err()
+	}
+}
+
+// ข้อความถัดไปที่ส่ง (รอไม่เกิน timeout)
+func (s *testServerStream[T]) next(t *testing.T, timeout time.Duration) *T {
+	t.Helper()
+	select {
+	case msg := <-s.sent:
+		return msg
+	case <-time.After(timeout):
+		t.Fatalf("no message was sent within %v", timeout)
+		return nil
+	}
+}
+
+// ข้อความทั้งหมดที่ส่งแล้วแต่ยังไม่ได้อ่าน
+func (s *testServerStream[T]) drain() []*T {
+	var msgs []*T
+	for {
+		select {
+		case msg := <-s.sent:
+			msgs = append(msgs, msg)
+		default:
+			return msgs
+		}
+	}
+}
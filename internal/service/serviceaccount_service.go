@@ -0,0 +1,410 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	pb "auth-microservice/auth-microservice/proto"
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/store"
+	"auth-microservice/internal/validation"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	clientSecretPrefix     = "cs_"          // ส่วนนำหน้าของ client secret
+	clientIDPrefix         = "sa_"          // ส่วนนำหน้าของ client ID
+	defaultSecretOverlap   = 24 * time.Hour // secret เดิมใช้ได้ต่อหลัง rotate เมื่อไม่ระบุ
+	maxSecretOverlap       = 7 * 24 * time.Hour
+	maxClientSecrets       = 3 // จำนวน secret ที่ใช้ได้พร้อมกันสูงสุด
+	maxClientPublicKeys    = 5
+	clientSecretPrefixSize = len(clientSecretPrefix) + 8
+)
+
+func (s *ServiceAccountService) CreateServiceAccount(ctx context.Context, in *pb.CreateServiceAccountRequest) (*pb.CreateServiceAccountReply, error) {
+	claims, err := requireAdmin(ctx, s.Blacklist, s.Tenants)
+	if err != nil {
+		return nil, err
+	}
+	tenantID := scopeTenant(ctx, claims)
+
+	if err := validation.ValidateServiceAccountName(in.GetName()); err != nil {
+		return nil, err
+	}
+	if err := validation.ValidateServiceAccountDescription(in.GetDescription()); err != nil {
+		return nil, err
+	}
+	scopes := uniqueStrings(in.GetScopes())
+	if err := validation.ValidateScopes(scopes); err != nil {
+		return nil, err
+	}
+	role := in.GetRole()
+	if role == "" {
+		role = models.ServiceAccountRole
+	}
+	if err := checkServiceAccountRole(claims, tenantID, role); err != nil {
+		return nil, err
+	}
+
+	clientID, err := generateRandomToken(12)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถสร้าง client ID ได้")
+	}
+	now := time.Now()
+	secret, clientSecret, err := newClientSecret(now)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถสร้าง client secret ได้")
+	}
+	account := &models.ServiceAccount{
+		TenantID:    tenantID,
+		Name:        in.GetName(),
+		Description: in.GetDescription(),
+		ClientID:    clientIDPrefix + clientID,
+		Role:        role,
+		Scopes:      scopes,
+		Secrets:     []models.ClientSecret{clientSecret},
+		PublicKeys:  []models.ClientPublicKey{},
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	err = s.ServiceAccounts.CreateServiceAccount(ctx, account)
+	if dup, ok := store.IsDuplicate(err); ok && dup.Field == "name" {
+		return nil, status.Error(codes.AlreadyExists, "ชื่อ service account ถูกใช้งานแล้ว")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถสร้าง service account ได้")
+	}
+
+	s.recordServiceAccountEvent(ctx, "service_account.created", account, claims, map[string]interface{}{"role": role, "scopes": scopes})
+	return &pb.CreateServiceAccountReply{
+		ServiceAccount: toServiceAccountReply(account),
+		ClientSecret:   secret,
+	}, nil
+}
+
+func (s *ServiceAccountService) GetServiceAccount(ctx context.Context, in *pb.GetServiceAccountRequest) (*pb.ServiceAccount, error) {
+	claims, err := requireAdmin(ctx, s.Blacklist, s.Tenants)
+	if err != nil {
+		return nil, err
+	}
+	account, err := s.findServiceAccount(ctx, scopeTenant(ctx, claims), in.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return toServiceAccountReply(account), nil
+}
+
+func (s *ServiceAccountService) ListServiceAccounts(ctx context.Context, in *pb.ListServiceAccountsRequest) (*pb.ListServiceAccountsReply, error) {
+	claims, err := requireAdmin(ctx, s.Blacklist, s.Tenants)
+	if err != nil {
+		return nil, err
+	}
+	accounts, err := s.ServiceAccounts.ListServiceAccounts(ctx, scopeTenant(ctx, claims))
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถดึงรายการ service account ได้")
+	}
+	reply := &pb.ListServiceAccountsReply{}
+	for i := range accounts {
+		reply.ServiceAccounts = append(reply.ServiceAccounts, toServiceAccountReply(&accounts[i]))
+	}
+	return reply, nil
+}
+
+func (s *ServiceAccountService) DeleteServiceAccount(ctx context.Context, in *pb.DeleteServiceAccountRequest) (*pb.DeleteServiceAccountReply, error) {
+	claims, err := requireAdmin(ctx, s.Blacklist, s.Tenants)
+	if err != nil {
+		return nil, err
+	}
+	account, err := s.findServiceAccount(ctx, scopeTenant(ctx, claims), in.GetId())
+	if err != nil {
+		return nil, err
+	}
+	err = s.ServiceAccounts.DeleteServiceAccount(ctx, account.TenantID, in.GetId())
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, "ไม่พบ service account")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถลบ service account ได้")
+	}
+
+	s.recordServiceAccountEvent(ctx, "service_account.deleted", account, claims, nil)
+	return &pb.DeleteServiceAccountReply{
+		Message: "ลบ service account สำเร็จ",
+	}, nil
+}
+
+func (s *ServiceAccountService) RotateClientSecret(ctx context.Context, in *pb.RotateClientSecretRequest) (*pb.RotateClientSecretReply, error) {
+	claims, err := requireAdmin(ctx, s.Blacklist, s.Tenants)
+	if err != nil {
+		return nil, err
+	}
+	overlap, err := secretOverlap(in.GetOverlapSeconds())
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := s.findServiceAccount(ctx, scopeTenant(ctx, claims), in.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	secret, clientSecret, err := newClientSecret(now)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถสร้าง client secret ได้")
+	}
+	account.Secrets = rotateSecrets(account.Secrets, clientSecret, now.Add(overlap), in.GetRevokePrevious())
+	account.UpdatedAt = now
+	if err := s.saveServiceAccount(ctx, account); err != nil {
+		return nil, err
+	}
+
+	s.recordServiceAccountEvent(ctx, "service_account.secret_rotated", account, claims, map[string]interface{}{
+		"secretId":       clientSecret.ID,
+		"overlapSeconds": int64(overlap / time.Second),
+		"revokePrevious": in.GetRevokePrevious(),
+	})
+	return &pb.RotateClientSecretReply{
+		ServiceAccount: toServiceAccountReply(account),
+		ClientSecret:   secret,
+	}, nil
+}
+
+func (s *ServiceAccountService) AddClientPublicKey(ctx context.Context, in *pb.AddClientPublicKeyRequest) (*pb.ServiceAccount, error) {
+	claims, err := requireAdmin(ctx, s.Blacklist, s.Tenants)
+	if err != nil {
+		return nil, err
+	}
+	if err := validation.ValidateClientPublicKey(in.GetPublicKeyPem()); err != nil {
+		return nil, err
+	}
+	if err := validation.ValidateClientKeyID(in.GetKeyId()); err != nil {
+		return nil, err
+	}
+
+	account, err := s.findServiceAccount(ctx, scopeTenant(ctx, claims), in.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if len(account.PublicKeys) >= maxClientPublicKeys {
+		return nil, status.Errorf(codes.FailedPrecondition, "service account มี public key ได้ไม่เกิน %d key", maxClientPublicKeys)
+	}
+
+	keyID := in.GetKeyId()
+	if keyID == "" {
+		if keyID, err = generateRandomToken(8); err != nil {
+			return nil, status.Error(codes.Internal, "ไม่สามารถสร้าง keyId ได้")
+		}
+	}
+	for _, key := range account.PublicKeys {
+		if key.ID == keyID {
+			return nil, status.Error(codes.AlreadyExists, "keyId นี้ถูกใช้งานแล้ว")
+		}
+	}
+
+	now := time.Now()
+	account.PublicKeys = append(account.PublicKeys, models.ClientPublicKey{ID: keyID, PEM: in.GetPublicKeyPem(), CreatedAt: now})
+	account.UpdatedAt = now
+	if err := s.saveServiceAccount(ctx, account); err != nil {
+		return nil, err
+	}
+
+	s.recordServiceAccountEvent(ctx, "service_account.public_key_added", account, claims, map[string]interface{}{"keyId": keyID})
+	return toServiceAccountReply(account), nil
+}
+
+func (s *ServiceAccountService) RemoveClientPublicKey(ctx context.Context, in *pb.RemoveClientPublicKeyRequest) (*pb.ServiceAccount, error) {
+	claims, err := requireAdmin(ctx, s.Blacklist, s.Tenants)
+	if err != nil {
+		return nil, err
+	}
+	account, err := s.findServiceAccount(ctx, scopeTenant(ctx, claims), in.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	keys := []models.ClientPublicKey{}
+	for _, key := range account.PublicKeys {
+		if key.ID != in.GetKeyId() {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == len(account.PublicKeys) {
+		return nil, status.Error(codes.NotFound, "ไม่พบ public key")
+	}
+	account.PublicKeys = keys
+	account.UpdatedAt = time.Now()
+	if err := s.saveServiceAccount(ctx, account); err != nil {
+		return nil, err
+	}
+
+	s.recordServiceAccountEvent(ctx, "service_account.public_key_removed", account, claims, map[string]interface{}{"keyId": in.GetKeyId()})
+	return toServiceAccountReply(account), nil
+}
+
+// role ที่ให้ service account ได้: "service" หรือ "tenant_admin" และ "admin" เฉพาะ tenant default โดย admin ของระบบ
+func checkServiceAccountRole(claims map[string]interface{}, tenantID string, role string) error {
+	switch role {
+	case models.ServiceAccountRole, roleTenantAdmin:
+		return nil
+	case roleAdmin:
+		if tenantID == models.DefaultTenantID && isPlatformAdmin(claims) {
+			return nil
+		}
+		return status.Error(codes.PermissionDenied, "role admin ให้ได้เฉพาะ service account ของ tenant default โดย admin ของระบบ")
+	}
+	return status.Error(codes.InvalidArgument, "role ของ service account ต้องเป็น service, tenant_admin หรือ admin")
+}
+
+// ช่วงเวลาที่ secret เดิมยังใช้ได้หลัง rotate (0 = ค่าเริ่มต้น)
+func secretOverlap(seconds int64) (time.Duration, error) {
+	overlap := time.Duration(seconds) * time.Second
+	if overlap == 0 {
+		overlap = defaultSecretOverlap
+	}
+	if overlap < 0 || overlap > maxSecretOverlap {
+		return 0, status.Error(codes.InvalidArgument, "overlapSeconds ต้องไม่เกิน 7 วัน")
+	}
+	return overlap, nil
+}
+
+// ใส่ secret ใหม่ไว้หน้าสุด secret เดิมที่ยังไม่หมดอายุใช้ได้ต่อถึง until (หรือยกเลิกทันทีถ้า revokePrevious)
+func rotateSecrets(secrets []models.ClientSecret, latest models.ClientSecret, until time.Time, revokePrevious bool) []models.ClientSecret {
+	rotated := []models.ClientSecret{latest}
+	if !revokePrevious {
+		for _, old := range secrets {
+			if old.ExpiresAt != nil && !old.ExpiresAt.After(latest.CreatedAt) {
+				continue
+			}
+			if old.ExpiresAt == nil || old.ExpiresAt.After(until) {
+				old.ExpiresAt = &until
+			}
+			rotated = append(rotated, old)
+		}
+	}
+	if len(rotated) > maxClientSecrets {
+		rotated = rotated[:maxClientSecrets]
+	}
+	return rotated
+}
+
+// secret ตรงกับ secret ที่ยังไม่หมดอายุตัวใดตัวหนึ่ง (เทียบ hash แบบ constant time)
+func matchClientSecret(secrets []models.ClientSecret, secret string) bool {
+	if secret == "" {
+		return false
+	}
+	hash := []byte(hashAPIKey(secret))
+	now := time.Now()
+	for _, candidate := range secrets {
+		if candidate.ExpiresAt != nil && !candidate.ExpiresAt.After(now) {
+			continue
+		}
+		if subtle.ConstantTimeCompare(hash, []byte(candidate.Hash)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// สร้าง client secret แบบสุ่ม (256 bit) คืน secret จริงกับข้อมูลที่ใช้เก็บ
+func newClientSecret(now time.Time) (string, models.ClientSecret, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", models.ClientSecret{}, err
+	}
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return "", models.ClientSecret{}, err
+	}
+	secret := clientSecretPrefix + base64.RawURLEncoding.EncodeToString(b)
+	return secret, models.ClientSecret{
+		ID:        hex.EncodeToString(id),
+		Hash:      hashAPIKey(secret),
+		Prefix:    secret[:clientSecretPrefixSize],
+		CreatedAt: now,
+	}, nil
+}
+
+// ดึง service account ใน tenant
+func (s *ServiceAccountService) findServiceAccount(ctx context.Context, tenantID string, id string) (*models.ServiceAccount, error) {
+	account, err := s.ServiceAccounts.GetServiceAccount(ctx, tenantID, id)
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, "ไม่พบ service account")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถดึงข้อมูล service account ได้")
+	}
+	return account, nil
+}
+
+func (s *ServiceAccountService) saveServiceAccount(ctx context.Context, account *models.ServiceAccount) error {
+	err := s.ServiceAccounts.SaveServiceAccount(ctx, account)
+	if errors.Is(err, store.ErrNotFound) {
+		return status.Error(codes.NotFound, "ไม่พบ service account")
+	}
+	if err != nil {
+		return status.Error(codes.Internal, "ไม่สามารถบันทึก service account ได้")
+	}
+	return nil
+}
+
+// บันทึกเหตุการณ์ของ service account ลง audit log
+func (s *ServiceAccountService) recordServiceAccountEvent(ctx context.Context, action string, account *models.ServiceAccount, claims map[string]interface{}, details map[string]interface{}) {
+	actor, _ := claims["email"].(string)
+	if details == nil {
+		details = map[string]interface{}{}
+	}
+	details["serviceAccountId"] = account.ID.Hex()
+	details["clientId"] = account.ClientID
+	s.Audit.Record(ctx, models.AuditEvent{
+		TenantID:   account.TenantID,
+		Action:     action,
+		ActorEmail: actor,
+		Details:    details,
+	})
+}
+
+func toServiceAccountReply(a *models.ServiceAccount) *pb.ServiceAccount {
+	reply := &pb.ServiceAccount{
+		Id:          a.ID.Hex(),
+		Name:        a.Name,
+		Description: a.Description,
+		ClientId:    a.ClientID,
+		Role:        a.Role,
+		Scopes:      a.Scopes,
+		CreatedAt:   a.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   a.UpdatedAt.Format(time.RFC3339),
+		Secrets:     toClientSecretReplies(a.Secrets),
+	}
+	for _, key := range a.PublicKeys {
+		reply.PublicKeys = append(reply.PublicKeys, &pb.ClientPublicKey{
+			Id:        key.ID,
+			CreatedAt: key.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	return reply
+}
+
+// ข้อมูลของ client secret ที่แสดงได้ (prefix และวันหมดอายุ ไม่มี hash)
+func toClientSecretReplies(secrets []models.ClientSecret) []*pb.ClientSecret {
+	replies := []*pb.ClientSecret{}
+	for _, secret := range secrets {
+		item := &pb.ClientSecret{
+			Id:        secret.ID,
+			Prefix:    secret.Prefix,
+			CreatedAt: secret.CreatedAt.Format(time.RFC3339),
+		}
+		if secret.ExpiresAt != nil {
+			item.ExpiresAt = secret.ExpiresAt.Format(time.RFC3339)
+		}
+		replies = append(replies, item)
+	}
+	return replies
+}
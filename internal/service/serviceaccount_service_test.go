@@ -0,0 +1,243 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	pb "auth-microservice/auth-microservice/proto"
+	"auth-microservice/internal/audit"
+	"auth-microservice/internal/auth"
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/store"
+
+	"google.golang.org/grpc/codes"
+)
+
+// ServiceAccountService กับ OAuthService ที่แลก client secret เป็น token พร้อม admin ของ tenant default
+type serviceAccountFixture struct {
+	stores   *store.Stores
+	service  *ServiceAccountService
+	oauth    *OAuthService
+	adminCtx context.Context
+}
+
+func newServiceAccountFixture(t *testing.T) *serviceAccountFixture {
+	t.Helper()
+	stores := newTestStores(t)
+	auditLogger := audit.NewLogger(stores.Audit)
+	return &serviceAccountFixture{
+		stores:   stores,
+		service:  NewServiceAccountService(stores, auditLogger),
+		oauth:    NewOAuthService(stores, "http://localhost:8080", auditLogger),
+		adminCtx: adminContext(t, stores, auditLogger),
+	}
+}
+
+func (f *serviceAccountFixture) create(t *testing.T, name string, scopes ...string) *pb.CreateServiceAccountReply {
+	t.Helper()
+	reply, err := f.service.CreateServiceAccount(f.adminCtx, &pb.CreateServiceAccountRequest{Name: name, Scopes: scopes})
+	if err != nil {
+		t.Fatalf("CreateServiceAccount: %v", err)
+	}
+	return reply
+}
+
+func (f *serviceAccountFixture) rotate(t *testing.T, in *pb.RotateClientSecretRequest) *pb.RotateClientSecretReply {
+	t.Helper()
+	reply, err := f.service.RotateClientSecret(f.adminCtx, in)
+	if err != nil {
+		t.Fatalf("RotateClientSecret: %v", err)
+	}
+	return reply
+}
+
+func (f *serviceAccountFixture) token(clientID string, secret string, scope string) (*pb.TokenReply, error) {
+	return f.oauth.Token(context.Background(), &pb.TokenRequest{GrantType: grantTypeClientCredentials, ClientId: clientID, ClientSecret: secret, Scope: scope})
+}
+
+func TestClientCredentialsToken(t *testing.T) {
+	f := newServiceAccountFixture(t)
+	account := f.create(t, "billing", models.ScopeUsersRead, models.ScopeGroupsRead)
+	clientID, secret := account.GetServiceAccount().GetClientId(), account.GetClientSecret()
+	if !strings.HasPrefix(clientID, clientIDPrefix) || !strings.HasPrefix(secret, clientSecretPrefix) {
+		t.Fatalf("client ID %q, secret %q", clientID, secret)
+	}
+	if secrets := account.GetServiceAccount().GetSecrets(); len(secrets) != 1 || !strings.HasPrefix(secret, secrets[0].GetPrefix()) || secrets[0].GetExpiresAt() != "" {
+		t.Fatalf("secrets = %v, want the new secret without expiry", secrets)
+	}
+
+	for _, tc := range []struct {
+		scope     string
+		wantScope string
+	}{
+		{"", models.ScopeUsersRead + " " + models.ScopeGroupsRead},
+		{models.ScopeGroupsRead, models.ScopeGroupsRead},
+		{models.ScopeUsersRead + " " + models.ScopeUsersRead, models.ScopeUsersRead},
+	} {
+		reply, err := f.token(clientID, secret, tc.scope)
+		if err != nil {
+			t.Fatalf("Token(scope %q): %v", tc.scope, err)
+		}
+		if reply.GetScope() != tc.wantScope || reply.GetTokenType() != "Bearer" {
+			t.Fatalf("Token(scope %q) = %v", tc.scope, reply)
+		}
+		claims, err := auth.ParseToken(reply.GetAccessToken(), models.DefaultTenantID, tenantKeys(context.Background(), f.stores.Tenants))
+		if err != nil {
+			t.Fatalf("ParseToken: %v", err)
+		}
+		if claims[auth.ClientIDClaim] != clientID || claims[auth.ScopeClaim] != tc.wantScope || claims["role"] != models.ServiceAccountRole {
+			t.Fatalf("claims = %v", claims)
+		}
+	}
+
+	for name, tc := range map[string]struct {
+		clientID, secret, scope string
+		want                    codes.Code
+	}{
+		"wrong secret":         {clientID, secret + "x", "", codes.Unauthenticated},
+		"empty secret":         {clientID, "", "", codes.Unauthenticated},
+		"unknown client":       {"sa_unknown", secret, "", codes.Unauthenticated},
+		"scope not granted":    {clientID, secret, models.ScopeUsersWrite, codes.PermissionDenied},
+		"one scope of two bad": {clientID, secret, models.ScopeUsersRead + " " + models.ScopeTenantsWrite, codes.PermissionDenied},
+	} {
+		_, err := f.token(tc.clientID, tc.secret, tc.scope)
+		wantCode(t, "Token with "+name, err, tc.want)
+	}
+}
+
+func TestRotateClientSecret(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		overlap     int64
+		revoke      bool
+		wantOverlap time.Duration // 0 = secret เดิมถูกยกเลิกทันที
+	}{
+		{"default overlap", 0, false, defaultSecretOverlap},
+		{"custom overlap", 3600, false, time.Hour},
+		{"revoke previous", 3600, true, 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			f := newServiceAccountFixture(t)
+			account := f.create(t, "billing", models.ScopeUsersRead)
+			id, clientID, oldSecret := account.GetServiceAccount().GetId(), account.GetServiceAccount().GetClientId(), account.GetClientSecret()
+
+			rotated := f.rotate(t, &pb.RotateClientSecretRequest{Id: id, OverlapSeconds: tc.overlap, RevokePrevious: tc.revoke})
+			newSecret := rotated.GetClientSecret()
+			if newSecret == "" || newSecret == oldSecret {
+				t.Fatalf("rotated secret = %q", newSecret)
+			}
+			if _, err := f.token(clientID, newSecret, ""); err != nil {
+				t.Fatalf("Token with the new secret: %v", err)
+			}
+
+			secrets := rotated.GetServiceAccount().GetSecrets()
+			if secrets[0].GetExpiresAt() != "" || !strings.HasPrefix(newSecret, secrets[0].GetPrefix()) {
+				t.Fatalf("latest secret = %v, want the new secret first without expiry", secrets[0])
+			}
+			_, err := f.token(clientID, oldSecret, "")
+			if tc.wantOverlap == 0 {
+				if len(secrets) != 1 {
+					t.Fatalf("secrets = %v, want only the new secret", secrets)
+				}
+				wantCode(t, "Token with the revoked secret", err, codes.Unauthenticated)
+				return
+			}
+			if err != nil {
+				t.Fatalf("Token with the previous secret during the overlap: %v", err)
+			}
+			expiresAt, _ := time.Parse(time.RFC3339, secrets[1].GetExpiresAt())
+			if len(secrets) != 2 || time.Until(expiresAt) > tc.wantOverlap || time.Until(expiresAt) < tc.wantOverlap-time.Minute {
+				t.Fatalf("secrets = %v, want the previous secret to expire in %v", secrets, tc.wantOverlap)
+			}
+
+			// หลังช่วง overlap secret เดิมใช้ไม่ได้
+			stored, err := f.stores.ServiceAccounts.GetServiceAccount(context.Background(), models.DefaultTenantID, id)
+			if err != nil {
+				t.Fatalf("GetServiceAccount: %v", err)
+			}
+			past := time.Now().Add(-time.Second)
+			stored.Secrets[1].ExpiresAt = &past
+			if err := f.stores.ServiceAccounts.SaveServiceAccount(context.Background(), stored); err != nil {
+				t.Fatalf("SaveServiceAccount: %v", err)
+			}
+			_, err = f.token(clientID, oldSecret, "")
+			wantCode(t, "Token with the previous secret after the overlap", err, codes.Unauthenticated)
+		})
+	}
+}
+
+func TestRotateClientSecretKeepsAtMostThreeSecrets(t *testing.T) {
+	f := newServiceAccountFixture(t)
+	account := f.create(t, "billing", models.ScopeUsersRead)
+	id, clientID := account.GetServiceAccount().GetId(), account.GetServiceAccount().GetClientId()
+
+	secrets := []string{account.GetClientSecret()}
+	for i := 0; i < maxClientSecrets; i++ {
+		secrets = append(secrets, f.rotate(t, &pb.RotateClientSecretRequest{Id: id}).GetClientSecret())
+	}
+	// secret แรกสุดถูกตัดออก ส่วน maxClientSecrets ตัวล่าสุดใช้ได้
+	_, err := f.token(clientID, secrets[0], "")
+	wantCode(t, "Token with the oldest secret", err, codes.Unauthenticated)
+	for i, secret := range secrets[1:] {
+		if _, err := f.token(clientID, secret, ""); err != nil {
+			t.Fatalf("Token with secret %d: %v", i+1, err)
+		}
+	}
+
+	for name, in := range map[string]*pb.RotateClientSecretRequest{
+		"negative overlap":    {Id: id, OverlapSeconds: -1},
+		"overlap over 7 days": {Id: id, OverlapSeconds: int64(maxSecretOverlap/time.Second) + 1},
+	} {
+		_, err := f.service.RotateClientSecret(f.adminCtx, in)
+		wantCode(t, "RotateClientSecret with "+name, err, codes.InvalidArgument)
+	}
+	_, err = f.service.RotateClientSecret(f.adminCtx, &pb.RotateClientSecretRequest{Id: "000000000000000000000000"})
+	wantCode(t, "RotateClientSecret of an unknown account", err, codes.NotFound)
+
+	// ผู้ใช้ทั่วไป rotate ไม่ได้
+	bob := registerAndLogin(t, f.stores, audit.NewLogger(f.stores.Audit), "bob@example.com", "bob")
+	_, err = f.service.RotateClientSecret(bob, &pb.RotateClientSecretRequest{Id: id})
+	wantCode(t, "RotateClientSecret by a user", err, codes.PermissionDenied)
+}
+
+func TestRotateSecrets(t *testing.T) {
+	now := time.Now()
+	until := now.Add(time.Hour)
+	at := func(d time.Duration) *time.Time {
+		ts := now.Add(d)
+		return &ts
+	}
+	latest := models.ClientSecret{ID: "new", CreatedAt: now}
+
+	for _, tc := range []struct {
+		name    string
+		secrets []models.ClientSecret
+		revoke  bool
+		want    map[string]*time.Time // ID ของ secret ที่เหลือกับวันหมดอายุ
+	}{
+		{"previous secret gets the overlap", []models.ClientSecret{{ID: "a"}}, false, map[string]*time.Time{"new": nil, "a": &until}},
+		{"expired secret is dropped", []models.ClientSecret{{ID: "a"}, {ID: "b", ExpiresAt: at(-time.Minute)}}, false, map[string]*time.Time{"new": nil, "a": &until}},
+		{"earlier expiry is kept", []models.ClientSecret{{ID: "a"}, {ID: "b", ExpiresAt: at(time.Minute)}}, false, map[string]*time.Time{"new": nil, "a": &until, "b": at(time.Minute)}},
+		{"later expiry is shortened", []models.ClientSecret{{ID: "a", ExpiresAt: at(48 * time.Hour)}}, false, map[string]*time.Time{"new": nil, "a": &until}},
+		{"revoke previous", []models.ClientSecret{{ID: "a"}, {ID: "b", ExpiresAt: at(time.Minute)}}, true, map[string]*time.Time{"new": nil}},
+		{"at most three", []models.ClientSecret{{ID: "a"}, {ID: "b"}, {ID: "c"}}, false, map[string]*time.Time{"new": nil, "a": &until, "b": &until}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := rotateSecrets(tc.secrets, latest, until, tc.revoke)
+			if len(got) != len(tc.want) || got[0].ID != "new" {
+				t.Fatalf("rotateSecrets = %+v, want %d secrets starting with the new one", got, len(tc.want))
+			}
+			for _, secret := range got {
+				want, ok := tc.want[secret.ID]
+				if !ok {
+					t.Fatalf("unexpected secret %s", secret.ID)
+				}
+				if (want == nil) != (secret.ExpiresAt == nil) || (want != nil && !want.Equal(*secret.ExpiresAt)) {
+					t.Fatalf("secret %s expires at %v, want %v", secret.ID, secret.ExpiresAt, want)
+				}
+			}
+		})
+	}
+}
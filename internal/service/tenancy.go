@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"auth-microservice/internal/auth"
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/store"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	roleUser        = "user"         // ผู้ใช้ทั่วไป (role เดียวที่สมัครเองได้)
+	roleAdmin       = "admin"        // admin ของ tenant default คือ admin ของทั้งระบบ ส่วน tenant อื่นเทียบเท่า tenant_admin
+	roleTenantAdmin = "tenant_admin" // admin เฉพาะ tenant ของตัวเอง
+	maxSigningKeys  = 3              // จำนวน key ล่าสุดที่ยังใช้ตรวจสอบ token ได้หลัง rotate
+)
+
+// ดึง tenant ตาม ID (ค่าว่าง = tenant default ซึ่งใช้ค่าเริ่มต้นถ้ายังไม่เคยบันทึก)
+func loadTenant(ctx context.Context, tenants store.TenantStore, id string) (*models.Tenant, error) {
+	if id == "" {
+		id = models.DefaultTenantID
+	}
+	tenant, err := tenants.GetTenant(ctx, id)
+	if errors.Is(err, store.ErrNotFound) {
+		if id == models.DefaultTenantID {
+			return models.DefaultTenant(), nil
+		}
+		return nil, status.Error(codes.NotFound, "ไม่พบ tenant")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถดึงข้อมูล tenant ได้")
+	}
+	return tenant, nil
+}
+
+// ดึง tenant ที่ยังไม่ถูกระงับ
+func activeTenant(ctx context.Context, tenants store.TenantStore, id string) (*models.Tenant, error) {
+	tenant, err := loadTenant(ctx, tenants, id)
+	if err != nil {
+		return nil, err
+	}
+	if tenant.Status == models.TenantSuspended {
+		return nil, status.Error(codes.PermissionDenied, "tenant นี้ถูกระงับการใช้งาน")
+	}
+	return tenant, nil
+}
+
+// tenant ของ request ที่ยังไม่ได้เข้าสู่ระบบ (จาก metadata "x-tenant-id")
+func requestTenant(ctx context.Context, tenants store.TenantStore) (*models.Tenant, error) {
+	return activeTenant(ctx, tenants, auth.TenantFromContext(ctx))
+}
+
+// ค้นหา secret สำหรับตรวจสอบ token ตาม tenant และ kid (tenant ที่ถูกระงับตรวจสอบไม่ผ่าน)
+// token ที่ไม่มี kid หรือ kid ไม่ตรงกับ key ของ tenant ตรวจสอบไม่ผ่านเสมอ (ไม่มี secret ร่วมของระบบ)
+func tenantKeys(ctx context.Context, tenants store.TenantStore) auth.KeyLookup {
+	return func(tenantID string, keyID string) ([]byte, error) {
+		tenant, err := activeTenant(ctx, tenants, tenantID)
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range tenant.SigningKeys {
+			if key.ID == keyID && len(key.Secret) > 0 {
+				return key.Secret, nil
+			}
+		}
+		return nil, errors.New("ไม่พบ signing key ของโทเค็น")
+	}
+}
+
+// key ที่ใช้เซ็น token ใหม่ของ tenant (tenant ที่ไม่มี key เซ็นไม่ได้ ดู EnsureSigningKeys)
+func signingKey(tenant *models.Tenant) (string, []byte, error) {
+	if len(tenant.SigningKeys) == 0 || len(tenant.SigningKeys[0].Secret) == 0 {
+		return "", nil, fmt.Errorf("tenant %s ไม่มี signing key", tenant.ID)
+	}
+	return tenant.SigningKeys[0].ID, tenant.SigningKeys[0].Secret, nil
+}
+
+// สร้าง signing key ให้ tenant ที่ยังไม่มี (รวมถึงบันทึก tenant default ครั้งแรก) และตัด key ว่างที่เคยใช้ secret ร่วมของระบบออก
+// เรียกตอนเริ่ม server หลัง migration คืน ID ของ tenant ที่ถูกแก้ไข
+func EnsureSigningKeys(ctx context.Context, tenants store.TenantStore) ([]string, error) {
+	list, err := tenants.ListTenants(ctx)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	hasDefault := false
+	for _, tenant := range list {
+		hasDefault = hasDefault || tenant.ID == models.DefaultTenantID
+	}
+	var updated []string
+	if !hasDefault {
+		key, err := newSigningKey(now)
+		if err != nil {
+			return nil, err
+		}
+		tenant := models.DefaultTenant()
+		tenant.SigningKeys = []models.SigningKey{key}
+		tenant.CreatedAt, tenant.UpdatedAt = now, now
+		var dup *store.DuplicateError
+		switch err := tenants.CreateTenant(ctx, tenant); {
+		case err == nil:
+			updated = append(updated, tenant.ID)
+		case errors.As(err, &dup):
+			// server อีกตัวที่เริ่มพร้อมกันสร้างไปก่อนแล้ว ใช้ key ของตัวนั้น
+		default:
+			return nil, err
+		}
+	}
+
+	for i := range list {
+		tenant := &list[i]
+		keys := make([]models.SigningKey, 0, len(tenant.SigningKeys))
+		for _, key := range tenant.SigningKeys {
+			if len(key.Secret) > 0 {
+				keys = append(keys, key)
+			}
+		}
+		if len(keys) > 0 && len(keys) == len(tenant.SigningKeys) {
+			continue
+		}
+		if len(keys) == 0 {
+			key, err := newSigningKey(now)
+			if err != nil {
+				return updated, err
+			}
+			keys = append(keys, key)
+		}
+		tenant.SigningKeys = keys
+		tenant.UpdatedAt = now
+		if err := tenants.SaveTenant(ctx, tenant); err != nil {
+			return updated, err
+		}
+		updated = append(updated, tenant.ID)
+	}
+	return updated, nil
+}
+
+// สร้าง signing key แบบสุ่ม
+func newSigningKey(now time.Time) (models.SigningKey, error) {
+	id := make([]byte, 8)
+	secret := make([]byte, 32)
+	if _, err := rand.Read(id); err != nil {
+		return models.SigningKey{}, err
+	}
+	if _, err := rand.Read(secret); err != nil {
+		return models.SigningKey{}, err
+	}
+	return models.SigningKey{ID: hex.EncodeToString(id), Secret: secret, CreatedAt: now}, nil
+}
+
+// tenant ของผู้ใช้เจ้าของ token
+func claimsTenant(claims map[string]interface{}) string {
+	tenantID, _ := claims[auth.TenantClaim].(string)
+	return tenantID
+}
+
+// admin ของทั้งระบบ (role admin ใน tenant default)
+func isPlatformAdmin(claims map[string]interface{}) bool {
+	role, _ := claims["role"].(string)
+	return role == roleAdmin && claimsTenant(claims) == models.DefaultTenantID
+}
+
+// admin ของทั้งระบบหรือของ tenant ใด tenant หนึ่ง
+func isAdmin(claims map[string]interface{}) bool {
+	role, _ := claims["role"].(string)
+	return role == roleAdmin || role == roleTenantAdmin
+}
+
+// tenant ที่ request นี้ทำงานด้วย: admin ของระบบเลือก tenant ได้ด้วย metadata "x-tenant-id"
+// ผู้ใช้อื่นใช้ได้เฉพาะ tenant ของตัวเอง
+func scopeTenant(ctx context.Context, claims map[string]interface{}) string {
+	if requested := auth.TenantFromContext(ctx); requested != "" && isPlatformAdmin(claims) {
+		return requested
+	}
+	return claimsTenant(claims)
+}
+
+// key ใน cache ของตัวนับ rate limit (tenant default ใช้ key เดิม)
+func loginAttemptKey(tenantID string, email string) string {
+	if tenantID == models.DefaultTenantID {
+		return "login_attempt:" + email
+	}
+	return "login_attempt:" + tenantID + ":" + email
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"auth-microservice/internal/auth"
+	models "auth-microservice/internal/model"
+)
+
+func TestEnsureSigningKeys(t *testing.T) {
+	ctx := context.Background()
+	stores := newTestStores(t) // เรียก EnsureSigningKeys แล้วหนึ่งครั้ง
+
+	def, err := stores.Tenants.GetTenant(ctx, models.DefaultTenantID)
+	if err != nil {
+		t.Fatalf("tenant default was not saved: %v", err)
+	}
+	if len(def.SigningKeys) != 1 || def.SigningKeys[0].ID == "" || len(def.SigningKeys[0].Secret) != 32 {
+		t.Fatalf("signing keys of tenant default = %+v", def.SigningKeys)
+	}
+
+	// tenant เก่าที่ไม่มี key หรือมีแต่ key ว่าง (secret ร่วมของระบบเดิม)
+	key, err := newSigningKey(time.Now())
+	if err != nil {
+		t.Fatalf("newSigningKey: %v", err)
+	}
+	for _, tenant := range []*models.Tenant{
+		{ID: "no-keys", Name: "No keys", Status: models.TenantActive},
+		{ID: "legacy", Name: "Legacy", Status: models.TenantActive, SigningKeys: []models.SigningKey{{}}},
+		{ID: "rotated", Name: "Rotated", Status: models.TenantActive, SigningKeys: []models.SigningKey{key, {}}},
+	} {
+		if err := stores.Tenants.CreateTenant(ctx, tenant); err != nil {
+			t.Fatalf("CreateTenant(%s): %v", tenant.ID, err)
+		}
+	}
+
+	updated, err := EnsureSigningKeys(ctx, stores.Tenants)
+	if err != nil {
+		t.Fatalf("EnsureSigningKeys: %v", err)
+	}
+	if len(updated) != 3 {
+		t.Fatalf("EnsureSigningKeys updated %v, want the three tenants without usable keys", updated)
+	}
+	for _, id := range []string{"no-keys", "legacy", "rotated"} {
+		tenant, err := stores.Tenants.GetTenant(ctx, id)
+		if err != nil {
+			t.Fatalf("GetTenant(%s): %v", id, err)
+		}
+		if len(tenant.SigningKeys) != 1 || len(tenant.SigningKeys[0].Secret) == 0 {
+			t.Fatalf("signing keys of %s = %+v, want one generated key", id, tenant.SigningKeys)
+		}
+		if id == "rotated" && !bytes.Equal(tenant.SigningKeys[0].Secret, key.Secret) {
+			t.Fatalf("EnsureSigningKeys replaced the existing key of %s", id)
+		}
+	}
+
+	// รันซ้ำไม่เปลี่ยน key เดิม
+	if updated, err := EnsureSigningKeys(ctx, stores.Tenants); err != nil || len(updated) != 0 {
+		t.Fatalf("second EnsureSigningKeys = %v, %v, want no changes", updated, err)
+	}
+	again, _ := stores.Tenants.GetTenant(ctx, models.DefaultTenantID)
+	if !bytes.Equal(again.SigningKeys[0].Secret, def.SigningKeys[0].Secret) {
+		t.Fatal("second EnsureSigningKeys replaced the key of tenant default")
+	}
+}
+
+func TestSigningKeysFailClosed(t *testing.T) {
+	ctx := context.Background()
+	stores := newTestStores(t)
+
+	if _, _, err := signingKey(models.DefaultTenant()); err == nil {
+		t.Fatal("signingKey of a tenant without keys succeeded")
+	}
+	if _, _, err := signingKey(&models.Tenant{ID: "legacy", SigningKeys: []models.SigningKey{{}}}); err == nil {
+		t.Fatal("signingKey of a tenant with an empty key succeeded")
+	}
+
+	tenant, err := loadTenant(ctx, stores.Tenants, models.DefaultTenantID)
+	if err != nil {
+		t.Fatalf("loadTenant: %v", err)
+	}
+	keyID, secret, err := signingKey(tenant)
+	if err != nil {
+		t.Fatalf("signingKey: %v", err)
+	}
+	keys := tenantKeys(ctx, stores.Tenants)
+	for name, sign := range map[string]struct {
+		keyID  string
+		secret []byte
+	}{
+		"old shared secret without kid": {"", []byte("secret-key")},
+		"tenant key without kid":        {"", secret},
+		"unknown kid":                   {"0000000000000000", secret},
+		"tenant kid with another key":   {keyID, []byte("secret-key")},
+	} {
+		token, err := auth.GenerateJWT("alice@example.com", "admin", models.DefaultTenantID, "jti-"+name, sign.keyID, sign.secret, time.Minute, nil)
+		if err != nil {
+			t.Fatalf("GenerateJWT: %v", err)
+		}
+		if claims, err := auth.ParseToken(token, models.DefaultTenantID, keys); err == nil {
+			t.Errorf("token signed with %s was accepted: %v", name, claims)
+		}
+	}
+
+	token, err := auth.GenerateJWT("alice@example.com", "user", models.DefaultTenantID, "jti-1", keyID, secret, time.Minute, nil)
+	if err != nil {
+		t.Fatalf("GenerateJWT: %v", err)
+	}
+	if _, err := auth.ParseToken(token, models.DefaultTenantID, keys); err != nil {
+		t.Fatalf("token signed with the tenant key: %v", err)
+	}
+}
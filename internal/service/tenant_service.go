@@ -0,0 +1,338 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	pb "auth-microservice/auth-microservice/proto"
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/validation"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *TenantService) CreateTenant(ctx context.Context, in *pb.CreateTenantRequest) (*pb.Tenant, error) {
+	// สร้าง tenant ได้เฉพาะ admin ของระบบ
+	claims, err := requirePlatformAdmin(ctx, s.Blacklist, s.Tenants)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validation.ValidateTenantID(in.GetId()); err != nil {
+		return nil, err
+	}
+	if err := validation.ValidateTenantName(in.GetName()); err != nil {
+		return nil, err
+	}
+
+	// การตั้งค่าที่ไม่ระบุใช้ค่าเริ่มต้นเดียวกับ tenant default
+	now := time.Now()
+	tenant := models.DefaultTenant()
+	tenant.ID = in.GetId()
+	tenant.Name = in.GetName()
+	tenant.CreatedAt = now
+	tenant.UpdatedAt = now
+	if in.GetPasswordPolicy() != nil {
+		tenant.PasswordPolicy = fromPasswordPolicyProto(in.GetPasswordPolicy())
+	}
+	if in.GetAccessTokenTtlSeconds() > 0 {
+		tenant.AccessTokenTTL = time.Duration(in.GetAccessTokenTtlSeconds()) * time.Second
+	}
+	if in.GetSessionTtlSeconds() > 0 {
+		tenant.SessionTTL = time.Duration(in.GetSessionTtlSeconds()) * time.Second
+	}
+	if err := validateTenantSettings(tenant); err != nil {
+		return nil, err
+	}
+
+	// tenant ใหม่มี signing key ของตัวเองตั้งแต่แรก
+	key, err := newSigningKey(now)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถสร้าง signing key ได้")
+	}
+	tenant.SigningKeys = []models.SigningKey{key}
+
+	err = s.Tenants.CreateTenant(ctx, tenant)
+	if dupErr := duplicateKeyError(err); dupErr != nil {
+		return nil, dupErr
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถสร้าง tenant ได้")
+	}
+
+	s.recordTenantEvent(ctx, "tenant.created", tenant, claims, nil)
+	return toTenantReply(tenant), nil
+}
+
+func (s *TenantService) GetTenant(ctx context.Context, in *pb.GetTenantRequest) (*pb.Tenant, error) {
+	claims, err := requireAdmin(ctx, s.Blacklist, s.Tenants)
+	if err != nil {
+		return nil, err
+	}
+
+	tenantID, err := manageableTenant(claims, in.GetId())
+	if err != nil {
+		return nil, err
+	}
+	tenant, err := loadTenant(ctx, s.Tenants, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return toTenantReply(tenant), nil
+}
+
+func (s *TenantService) ListTenants(ctx context.Context, in *pb.ListTenantsRequest) (*pb.ListTenantsReply, error) {
+	// ดูรายการ tenant ทั้งหมดได้เฉพาะ admin ของระบบ
+	if _, err := requirePlatformAdmin(ctx, s.Blacklist, s.Tenants); err != nil {
+		return nil, err
+	}
+
+	tenants, err := s.Tenants.ListTenants(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถดึงรายการ tenant ได้")
+	}
+
+	// tenant default อาจยังไม่เคยถูกบันทึก แต่มีอยู่เสมอ
+	reply := &pb.ListTenantsReply{}
+	hasDefault := false
+	for i := range tenants {
+		hasDefault = hasDefault || tenants[i].ID == models.DefaultTenantID
+		reply.Tenants = append(reply.Tenants, toTenantReply(&tenants[i]))
+	}
+	if !hasDefault {
+		reply.Tenants = append([]*pb.Tenant{toTenantReply(models.DefaultTenant())}, reply.Tenants...)
+	}
+	return reply, nil
+}
+
+func (s *TenantService) UpdateTenant(ctx context.Context, in *pb.UpdateTenantRequest) (*pb.Tenant, error) {
+	claims, err := requireAdmin(ctx, s.Blacklist, s.Tenants)
+	if err != nil {
+		return nil, err
+	}
+
+	tenantID, err := manageableTenant(claims, in.GetId())
+	if err != nil {
+		return nil, err
+	}
+	tenant, err := loadTenant(ctx, s.Tenants, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := in.GetUpdateMask().GetPaths()
+	if len(paths) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "ต้องระบุ updateMask")
+	}
+	for _, path := range paths {
+		switch path {
+		case "name":
+			if err := validation.ValidateTenantName(in.GetName()); err != nil {
+				return nil, err
+			}
+			tenant.Name = in.GetName()
+		case "passwordPolicy":
+			tenant.PasswordPolicy = fromPasswordPolicyProto(in.GetPasswordPolicy())
+		case "accessTokenTtlSeconds":
+			tenant.AccessTokenTTL = time.Duration(in.GetAccessTokenTtlSeconds()) * time.Second
+		case "sessionTtlSeconds":
+			tenant.SessionTTL = time.Duration(in.GetSessionTtlSeconds()) * time.Second
+		default:
+			return nil, status.Errorf(codes.InvalidArgument, "ไม่รองรับการแก้ไข field %q", path)
+		}
+	}
+	if err := validateTenantSettings(tenant); err != nil {
+		return nil, err
+	}
+
+	tenant.UpdatedAt = time.Now()
+	if err := s.saveTenant(ctx, tenant); err != nil {
+		return nil, err
+	}
+
+	s.recordTenantEvent(ctx, "tenant.updated", tenant, claims, map[string]interface{}{"fields": paths})
+	return toTenantReply(tenant), nil
+}
+
+func (s *TenantService) SuspendTenant(ctx context.Context, in *pb.SuspendTenantRequest) (*pb.Tenant, error) {
+	// ระงับ tenant ได้เฉพาะ admin ของระบบ
+	claims, err := requirePlatformAdmin(ctx, s.Blacklist, s.Tenants)
+	if err != nil {
+		return nil, err
+	}
+
+	// ระงับ tenant default จะทำให้ admin ของระบบเข้าใช้งานไม่ได้เอง
+	if in.GetId() == "" || in.GetId() == models.DefaultTenantID {
+		return nil, status.Error(codes.InvalidArgument, "ไม่สามารถระงับ tenant default ได้")
+	}
+
+	tenant, err := s.setTenantStatus(ctx, in.GetId(), models.TenantSuspended)
+	if err != nil {
+		return nil, err
+	}
+
+	s.recordTenantEvent(ctx, "tenant.suspended", tenant, claims, map[string]interface{}{"reason": in.GetReason()})
+	return toTenantReply(tenant), nil
+}
+
+func (s *TenantService) ActivateTenant(ctx context.Context, in *pb.ActivateTenantRequest) (*pb.Tenant, error) {
+	claims, err := requirePlatformAdmin(ctx, s.Blacklist, s.Tenants)
+	if err != nil {
+		return nil, err
+	}
+
+	tenant, err := s.setTenantStatus(ctx, in.GetId(), models.TenantActive)
+	if err != nil {
+		return nil, err
+	}
+
+	s.recordTenantEvent(ctx, "tenant.activated", tenant, claims, nil)
+	return toTenantReply(tenant), nil
+}
+
+func (s *TenantService) RotateSigningKey(ctx context.Context, in *pb.RotateSigningKeyRequest) (*pb.Tenant, error) {
+	claims, err := requireAdmin(ctx, s.Blacklist, s.Tenants)
+	if err != nil {
+		return nil, err
+	}
+
+	tenantID, err := manageableTenant(claims, in.GetId())
+	if err != nil {
+		return nil, err
+	}
+	tenant, err := loadTenant(ctx, s.Tenants, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	key, err := newSigningKey(now)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถสร้าง signing key ได้")
+	}
+
+	// key ใหม่อยู่หน้าสุด key เดิมยังตรวจสอบ token ที่ออกไปแล้วได้จนกว่าจะถูกตัดออก
+	tenant.SigningKeys = append([]models.SigningKey{key}, tenant.SigningKeys...)
+	if len(tenant.SigningKeys) > maxSigningKeys {
+		tenant.SigningKeys = tenant.SigningKeys[:maxSigningKeys]
+	}
+	tenant.UpdatedAt = now
+	if err := s.saveTenant(ctx, tenant); err != nil {
+		return nil, err
+	}
+
+	s.recordTenantEvent(ctx, "tenant.signing_key_rotated", tenant, claims, map[string]interface{}{"keyId": key.ID})
+	return toTenantReply(tenant), nil
+}
+
+// เปลี่ยนสถานะของ tenant
+func (s *TenantService) setTenantStatus(ctx context.Context, id string, tenantStatus string) (*models.Tenant, error) {
+	tenant, err := loadTenant(ctx, s.Tenants, id)
+	if err != nil {
+		return nil, err
+	}
+	if tenant.Status == tenantStatus {
+		return nil, status.Errorf(codes.FailedPrecondition, "tenant อยู่ในสถานะ %s อยู่แล้ว", tenantStatus)
+	}
+
+	tenant.Status = tenantStatus
+	tenant.UpdatedAt = time.Now()
+	if err := s.saveTenant(ctx, tenant); err != nil {
+		return nil, err
+	}
+	return tenant, nil
+}
+
+func (s *TenantService) saveTenant(ctx context.Context, tenant *models.Tenant) error {
+	if tenant.CreatedAt.IsZero() {
+		// tenant default ที่ยังไม่เคยบันทึก
+		tenant.CreatedAt = tenant.UpdatedAt
+	}
+	if err := s.Tenants.SaveTenant(ctx, tenant); err != nil {
+		return status.Error(codes.Internal, "ไม่สามารถบันทึก tenant ได้")
+	}
+	return nil
+}
+
+// บันทึกเหตุการณ์ของ tenant ลง audit log ของ tenant นั้น
+func (s *TenantService) recordTenantEvent(ctx context.Context, action string, tenant *models.Tenant, claims map[string]interface{}, details map[string]interface{}) {
+	adminEmail, _ := claims["email"].(string)
+	if details == nil {
+		details = map[string]interface{}{}
+	}
+	details["actorTenantId"] = claimsTenant(claims)
+	s.Audit.Record(ctx, models.AuditEvent{
+		TenantID:   tenant.ID,
+		Action:     action,
+		ActorEmail: adminEmail,
+		SubjectID:  tenant.ID,
+		Details:    details,
+	})
+}
+
+// tenant ที่ admin ระบุใน request (ว่าง = tenant ของตัวเอง)
+// admin ของ tenant จัดการได้เฉพาะ tenant ของตัวเอง
+func manageableTenant(claims map[string]interface{}, requested string) (string, error) {
+	own := claimsTenant(claims)
+	if requested == "" {
+		return own, nil
+	}
+	if requested != own && !isPlatformAdmin(claims) {
+		return "", status.Error(codes.PermissionDenied, "จัดการได้เฉพาะ tenant ของตัวเองเท่านั้น")
+	}
+	return requested, nil
+}
+
+func validateTenantSettings(tenant *models.Tenant) error {
+	if err := validation.ValidatePasswordPolicy(tenant.PasswordPolicy); err != nil {
+		return err
+	}
+	return validation.ValidateTokenTTLs(tenant.AccessTokenTTL, tenant.SessionTTL)
+}
+
+func fromPasswordPolicyProto(p *pb.PasswordPolicy) models.PasswordPolicy {
+	return models.PasswordPolicy{
+		MinLength:     int(p.GetMinLength()),
+		RequireUpper:  p.GetRequireUpper(),
+		RequireLower:  p.GetRequireLower(),
+		RequireNumber: p.GetRequireNumber(),
+		RequireSymbol: p.GetRequireSymbol(),
+		HistorySize:   int(p.GetHistorySize()),
+		MaxAge:        time.Duration(p.GetMaxAgeDays()) * 24 * time.Hour,
+	}
+}
+
+// แปลง models.Tenant เป็น protobuf (ไม่ส่ง secret ของ signing key ออกไป)
+func toTenantReply(t *models.Tenant) *pb.Tenant {
+	reply := &pb.Tenant{
+		Id:     t.ID,
+		Name:   t.Name,
+		Status: t.Status,
+		PasswordPolicy: &pb.PasswordPolicy{
+			MinLength:     int32(t.PasswordPolicy.MinLength),
+			RequireUpper:  t.PasswordPolicy.RequireUpper,
+			RequireLower:  t.PasswordPolicy.RequireLower,
+			RequireNumber: t.PasswordPolicy.RequireNumber,
+			RequireSymbol: t.PasswordPolicy.RequireSymbol,
+			HistorySize:   int32(t.PasswordPolicy.HistorySize),
+			MaxAgeDays:    int32(t.PasswordPolicy.MaxAge / (24 * time.Hour)),
+		},
+		AccessTokenTtlSeconds: int64(t.AccessTokenTTL / time.Second),
+		SessionTtlSeconds:     int64(t.SessionTTL / time.Second),
+	}
+	if !t.CreatedAt.IsZero() {
+		reply.CreatedAt = t.CreatedAt.Format(time.RFC3339)
+	}
+	if !t.UpdatedAt.IsZero() {
+		reply.UpdatedAt = t.UpdatedAt.Format(time.RFC3339)
+	}
+	for i, key := range t.SigningKeys {
+		reply.SigningKeys = append(reply.SigningKeys, &pb.SigningKeyInfo{
+			Id:        key.ID,
+			CreatedAt: key.CreatedAt.Format(time.RFC3339),
+			Active:    i == 0,
+		})
+	}
+	return reply
+}
@@ -2,18 +2,242 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log"
+	"strings"
+	"time"
 
-	"go.mongodb.org/mongo-driver/bson"
+	"auth-microservice/internal/auth"
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/store"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
 )
 
 func (s *AuthService) IsTokenBlacklisted(ctx context.Context, token string) (bool, error) {
-	// สร้าง filter สำหรับค้นหา token ที่ถูก block
-	filter := bson.M{"token": token}
-	// ใช้ MongoDB นับจำนวน token ที่ตรงกับ filter
-	// ถ้ามีแสดงว่า token นี้ถูกเพิ่มใน blacklist แล้ว
-	count, err := s.BlacklistCollection.CountDocuments(ctx, filter)
+	return s.Blacklist.IsBlacklisted(ctx, token)
+}
+
+func (s *AuthService) authenticate(ctx context.Context) (string, map[string]interface{}, error) {
+	return authenticate(ctx, s.Blacklist, s.Tenants)
+}
+
+// ตรวจสอบ token ที่แนบมากับ request (ต้องถูกต้องและไม่อยู่ใน blacklist) แล้วคืน token กับ claims
+// claims จะมี tenant ของผู้ใช้เสมอ (token เดิมที่ไม่มี tenant ถือเป็นของ tenant default)
+// request ที่ใช้ API key คืน claims ของเจ้าของ key ที่ interceptor ตรวจสอบไว้แล้ว (token เป็นค่าว่าง)
+func authenticate(ctx context.Context, blacklist store.BlacklistStore, tenants store.TenantStore) (string, map[string]interface{}, error) {
+	if claims, ok := apiKeyClaims(ctx); ok {
+		return "", claims, checkClaimsTenant(ctx, claims)
+	}
+
+	tokenStr, err := auth.TokenFromContext(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	claims, err := verifyToken(ctx, blacklist, tenants, tokenStr)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if err := checkClaimsTenant(ctx, claims); err != nil {
+		return "", nil, err
+	}
+	if err := checkClientScope(ctx, claims); err != nil {
+		return "", nil, err
+	}
+	return tokenStr, claims, nil
+}
+
+// ตรวจสอบลายเซ็นและวันหมดอายุของ token และต้องไม่อยู่ใน blacklist
+func verifyToken(ctx context.Context, blacklist store.BlacklistStore, tenants store.TenantStore, tokenStr string) (map[string]interface{}, error) {
+	isBlacklisted, err := blacklist.IsBlacklisted(ctx, tokenStr)
 	if err != nil {
-		return false, err
+		return nil, status.Error(codes.Internal, "เจอข้อผิดพลาดในการตรวจสอบโทเค็น")
+	}
+	if isBlacklisted {
+		return nil, status.Error(codes.Unauthenticated, "โทเค็นนี้ถูกบล็อกแล้ว")
+	}
+
+	claims, err := auth.ParseToken(tokenStr, models.DefaultTenantID, tenantKeys(ctx, tenants))
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+	return claims, nil
+}
+
+// token ที่ออกให้ client (service account หรือแอปที่ผู้ใช้อนุญาต) เรียกได้เฉพาะ RPC ที่อยู่ใน scope ของ token
+func checkClientScope(ctx context.Context, claims map[string]interface{}) error {
+	if _, ok := claims[auth.ClientIDClaim]; !ok {
+		return nil
+	}
+	method, _ := grpc.Method(ctx)
+	scope, ok := methodScopes[method]
+	if !ok {
+		return status.Error(codes.PermissionDenied, "RPC นี้ใช้ token ของ client ไม่ได้")
+	}
+	granted, _ := claims[auth.ScopeClaim].(string)
+	if !hasScope(strings.Fields(granted), scope) {
+		return status.Errorf(codes.PermissionDenied, "token นี้ไม่มี scope %q", scope)
+	}
+	return nil
+}
+
+// token ใช้ได้เฉพาะ tenant ของตัวเอง ยกเว้น admin ของระบบ
+func checkClaimsTenant(ctx context.Context, claims map[string]interface{}) error {
+	if requested := auth.TenantFromContext(ctx); requested != "" && requested != claimsTenant(claims) && !isPlatformAdmin(claims) {
+		return status.Error(codes.PermissionDenied, "โทเค็นนี้ใช้กับ tenant อื่นไม่ได้")
+	}
+	return nil
+}
+
+// ตรวจสอบ token แล้วบังคับว่าต้องเป็น admin (ของระบบหรือของ tenant) เท่านั้น
+func requireAdmin(ctx context.Context, blacklist store.BlacklistStore, tenants store.TenantStore) (map[string]interface{}, error) {
+	_, claims, err := authenticate(ctx, blacklist, tenants)
+	if err != nil {
+		return nil, err
+	}
+	if !isAdmin(claims) {
+		return nil, status.Error(codes.PermissionDenied, "สามารถทำรายการนี้ได้เฉพาะ Admin เท่านั้น")
+	}
+	return claims, nil
+}
+
+// ตรวจสอบ token แล้วบังคับว่าต้องเป็น admin ของทั้งระบบเท่านั้น
+func requirePlatformAdmin(ctx context.Context, blacklist store.BlacklistStore, tenants store.TenantStore) (map[string]interface{}, error) {
+	_, claims, err := authenticate(ctx, blacklist, tenants)
+	if err != nil {
+		return nil, err
+	}
+	if !isPlatformAdmin(claims) {
+		return nil, status.Error(codes.PermissionDenied, "สามารถทำรายการนี้ได้เฉพาะ Admin ของระบบเท่านั้น")
+	}
+	return claims, nil
+}
+
+func (s *AuthService) revokeActiveToken(ctx context.Context, tenantID string, email string) error {
+	return revokeActiveToken(ctx, s.Sessions, s.Blacklist, tenantID, email)
+}
+
+// ยกเลิก active token ของผู้ใช้ใน tenant (เพิ่มเข้า blacklist และลบ session)
+func revokeActiveToken(ctx context.Context, sessions store.SessionStore, blacklist store.BlacklistStore, tenantID string, email string) error {
+	oldToken, err := sessions.GetActiveToken(ctx, tenantID, email)
+	if err != nil || oldToken == "" {
+		// ไม่มี token ที่ใช้งานอยู่
+		return nil
+	}
+
+	// เก็บใน blacklist จนกว่า token จะหมดอายุเอง
+	exp, err := auth.GetTokenExpiration(oldToken)
+	if err != nil {
+		exp = time.Now().Add(24 * time.Hour)
+	}
+	if err := blacklist.AddToken(ctx, oldToken, exp); err != nil {
+		return err
+	}
+
+	return sessions.DeleteActiveToken(ctx, tenantID, email)
+}
+
+// ลบความยินยอมทั้งหมดของผู้ใช้ ทำให้ refresh token ที่ออกให้ OAuth client ใช้ไม่ได้ คืนจำนวนที่ลบ
+func revokeConsents(ctx context.Context, consents store.OAuthClientStore, tenantID string, userID string) (int, error) {
+	list, err := consents.ListConsents(ctx, tenantID, userID)
+	if err != nil {
+		return 0, err
+	}
+	for _, consent := range list {
+		if err := consents.DeleteConsent(ctx, tenantID, userID, consent.ClientID); err != nil && !errors.Is(err, store.ErrNotFound) {
+			return 0, err
+		}
+	}
+	return len(list), nil
+}
+
+func (s *AuthService) issueToken(ctx context.Context, tenant *models.Tenant, userID string, email string, role string) (string, error) {
+	return issueToken(ctx, s.Sessions, s.Groups, tenant, userID, email, role)
+}
+
+// สร้าง JWT token ใหม่ตามอายุและ signing key ของ tenant แล้วบันทึกเป็น active token ของผู้ใช้
+func issueToken(ctx context.Context, sessions store.SessionStore, groups store.GroupStore, tenant *models.Tenant, userID string, email string, role string) (string, error) {
+	keyID, secret, err := signingKey(tenant)
+	if err != nil {
+		return "", err
+	}
+	jti, err := generateRandomToken(16)
+	if err != nil {
+		return "", err
+	}
+	token, err := auth.GenerateJWT(email, role, tenant.ID, jti, keyID, secret, tenant.AccessTokenTTL, groupClaims(ctx, groups, tenant.ID, userID))
+	if err != nil {
+		return "", err
+	}
+
+	// บันทึก token ใหม่พร้อมระบุเวลา expiration ของ session
+	if err := sessions.SetActiveToken(ctx, tenant.ID, email, token, tenant.SessionTTL); err != nil {
+		log.Printf("Could not save active token for user %s: %v", email, err)
+	}
+	return token, nil
+}
+
+// กลุ่มของผู้ใช้สำหรับใส่ใน token (nil = ไม่ใส่ เมื่อกลุ่มเกิน maxGroupClaims หรือดึงข้อมูลไม่ได้)
+func groupClaims(ctx context.Context, groups store.GroupStore, tenantID string, userID string) []auth.GroupClaim {
+	userGroups, err := groups.ListUserGroups(ctx, tenantID, userID)
+	if err != nil {
+		log.Printf("Could not load groups for user %s: %v", userID, err)
+		return nil
+	}
+	if len(userGroups) > maxGroupClaims {
+		return nil
+	}
+	claims := make([]auth.GroupClaim, 0, len(userGroups))
+	for _, g := range userGroups {
+		claims = append(claims, auth.GroupClaim{ID: g.Group.ID.Hex(), Name: g.Group.Name, Role: g.Role})
+	}
+	return claims
+}
+
+// สร้าง token แบบสุ่มขนาด n ไบต์ (แปลงเป็น hex) สำหรับลิงก์ยืนยันต่าง ๆ
+func generateRandomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// ดึงอีเมลของผู้ที่เรียก request จาก token (ถ้ามี) ใช้สำหรับบันทึก audit log
+func actorEmail(ctx context.Context, tenants store.TenantStore) string {
+	if claims, ok := apiKeyClaims(ctx); ok {
+		email, _ := claims["email"].(string)
+		return email
+	}
+	tokenStr, err := auth.TokenFromContext(ctx)
+	if err != nil {
+		return ""
+	}
+	claims, err := auth.ParseToken(tokenStr, models.DefaultTenantID, tenantKeys(ctx, tenants))
+	if err != nil {
+		return ""
+	}
+	email, _ := claims["email"].(string)
+	return email
+}
+
+// ดึงข้อมูลอุปกรณ์ของผู้เรียกจาก gRPC (IP และ user-agent) สำหรับบันทึกลง audit log
+func requestDetails(ctx context.Context) map[string]interface{} {
+	details := map[string]interface{}{}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		details["ip"] = p.Addr.String()
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ua := md.Get("user-agent"); len(ua) > 0 {
+			details["userAgent"] = ua[0]
+		}
 	}
-	return count > 0, nil
+	return details
 }
@@ -2,208 +2,513 @@ package service
 
 import (
 	"context"
+	"errors"
 	"strings"
 	"time"
 
-	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo/options"
 	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 
 	pb "auth-microservice/auth-microservice/proto"
-	"auth-microservice/internal/auth"
 	models "auth-microservice/internal/model"
+	"auth-microservice/internal/search"
+	"auth-microservice/internal/store"
 	"auth-microservice/internal/validation"
 )
 
 func (s *UserService) GetUserById(ctx context.Context, in *pb.UserIdRequest) (*pb.UserIdReply, error) {
-	// แปลง string เป็น ObjectID
+	// ตรวจสอบรูปแบบ ID
 	objID, err := primitive.ObjectIDFromHex(in.GetId())
 	if err != nil {
 		return nil, status.Error(codes.InvalidArgument, "ไอดีไม่ถูกต้อง")
 	}
 
-	// กำหนด filte
-	filter := bson.M{
-		"_id":     objID,
-		"deleted": bson.M{"$ne": true}, // ดึงเฉพาะที่ยังไม่ถูกลบ != true
-	}
-
-	// ดึงข้อมูลผู้ใช้จาก MongoDB
-	err = s.UserCollection.FindOne(ctx, filter).Decode(&models.User)
+	// ดึงข้อมูลผู้ใช้ใน tenant (เฉพาะที่ยังไม่ถูกลบ) ได้เฉพาะตัวเองหรือ admin
+	user, err := s.accessibleUser(ctx, objID.Hex())
 	if err != nil {
-		return nil, status.Error(codes.NotFound, "ไม่พบผู้ใช้")
+		return nil, err
 	}
 
 	// ส่งข้อมูลกลับในรูปแบบ protobuf
-	return &pb.UserIdReply{
-		Id:        models.User.ID.Hex(),
-		Email:     models.User.Email,
-		Username:  models.User.Username,
-		CreatedAt: models.User.CreatedAt.Format(time.RFC3339),
-		UpdatedAt: models.User.UpdatedAt.Format(time.RFC3339),
-	}, nil
+	return toUserIdReply(*user), nil
 }
 
 func (s *UserService) UpdateUser(ctx context.Context, in *pb.UpdateUserRequest) (*pb.UpdateUserReply, error) {
-	// แปลง id เป็น ObjectID
+	// ตรวจสอบรูปแบบ ID
 	objID, err := primitive.ObjectIDFromHex(in.GetId())
 	if err != nil {
 		return nil, status.Error(codes.InvalidArgument, "ID ไม่ถูกต้อง")
 	}
 
-	// ตรวจสอบความถูกต้องของ username
-	if err := validation.ValidateUsername(in.GetUsername(), ctx, s.UserCollection); err != nil {
+	// แก้ไขได้เฉพาะตัวเองหรือ admin ของ tenant
+	user, err := s.accessibleUser(ctx, objID.Hex())
+	if err != nil {
 		return nil, err
 	}
 
-	// กำหนดข้อมูลที่จะอัปเดต
-	update := bson.M{
-		"$set": bson.M{
-			"username":  in.GetUsername(),
-			"updatedAt": time.Now(),
-		},
+	// ตรวจสอบข้อมูลตาม field mask และสร้างรายการแก้ไข
+	patch, err := s.buildProfileUpdate(ctx, user.TenantID, in)
+	if err != nil {
+		return nil, err
 	}
 
-	// อัปเดตข้อมูลใน MongoDB
-	filter := bson.M{"_id": objID}
+	fields := in.GetUpdateMask().GetPaths()
+	if len(fields) == 0 {
+		fields = presentProfilePaths(in)
+	}
 
-	// เช็คว่ามีผู้ใช้ตรงกับ id หรือไม่
-	result, err := s.UserCollection.UpdateOne(ctx, filter, update)
+	// อัปเดตข้อมูลพร้อม event "user.updated" และเช็คว่ามีผู้ใช้ตรงกับ id หรือไม่
+	event, err := newUserEvent(user, "user.updated", map[string]interface{}{"email": user.Email, "fields": fields})
 	if err != nil {
-		return nil, status.Error(codes.Internal, "เกิดข้อผิดพลาดในการอัปเดตข้อมูล")
+		return nil, status.Error(codes.Internal, "ไม่สามารถสร้าง event ได้")
 	}
-
-	if result.MatchedCount == 0 {
+	err = s.Outbox.UpdateProfileWithEvent(ctx, objID.Hex(), patch, time.Now(), event)
+	if dupErr := duplicateKeyError(err); dupErr != nil {
+		return nil, dupErr
+	}
+	if errors.Is(err, store.ErrNotFound) {
 		return nil, status.Error(codes.NotFound, "ไม่พบผู้ใช้ที่ต้องการอัปเดต")
 	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "เกิดข้อผิดพลาดในการอัปเดตข้อมูล")
+	}
+	s.Audit.Record(ctx, models.AuditEvent{
+		TenantID:     user.TenantID,
+		Action:       "user.updated",
+		ActorEmail:   actorEmail(ctx, s.Tenants),
+		SubjectID:    objID.Hex(),
+		SubjectEmail: user.Email,
+		Details:      map[string]interface{}{"fields": fields},
+	})
 
 	return &pb.UpdateUserReply{
 		Message: "อัปเดตข้อมูลผู้ใช้สำเร็จ",
 	}, nil
 }
 func (s *UserService) DeleteUser(ctx context.Context, in *pb.DeleteUserRequest) (*pb.DeleteUserReply, error) {
-	// แปลง id จาก string เป็น MongoDB ObjectID
+	// ตรวจสอบรูปแบบ ID
 	objID, err := primitive.ObjectIDFromHex(in.GetId())
 	if err != nil {
 		return nil, status.Error(codes.InvalidArgument, "ID ไม่ถูกต้อง")
 	}
 
-	//filter
-	filter := bson.M{"_id": objID, "deleted": bson.M{"$ne": true}} // ต้องไม่ถูกลบอยู่ก่อนแล้ว
-	update := bson.M{
-		"$set": bson.M{
-			"deleted":   true,
-			"deletedAt": time.Now(),
-		},
+	// ลบได้เฉพาะตัวเองหรือ admin ของ tenant
+	current, err := s.accessibleUser(ctx, objID.Hex())
+	if err != nil {
+		return nil, err
 	}
 
-	// อัปเดตข้อมูล
-	result, err := s.UserCollection.UpdateOne(ctx, filter, update)
+	// soft delete (ต้องไม่ถูกลบอยู่ก่อนแล้ว) พร้อม event "user.deleted" และดึงอีเมลของผู้ใช้กลับมาเพื่อใช้ยกเลิก token
+	event, err := newUserEvent(current, "user.deleted", map[string]interface{}{"email": current.Email})
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถสร้าง event ได้")
+	}
+	user, err := s.Outbox.SoftDeleteUserWithEvent(ctx, objID.Hex(), time.Now(), event)
+	if errors.Is(err, store.ErrNotFound) {
+		// เช็คว่ามีผู้ใช้ตรงกับ id หรือไม่ หรือถูกลบไปแล้ว
+		return nil, status.Error(codes.NotFound, "ไม่พบผู้ใช้ที่ต้องการลบหรือถูกลบไปแล้ว")
+	}
 	if err != nil {
 		return nil, status.Error(codes.Internal, "เกิดข้อผิดพลาดในการลบผู้ใช้")
 	}
 
-	// เช็คว่ามีผู้ใช้ตรงกับ id หรือไม่ หรือถูกลบไปแล้ว
-	if result.MatchedCount == 0 {
-		return nil, status.Error(codes.NotFound, "ไม่พบผู้ใช้ที่ต้องการลบหรือถูกลบไปแล้ว")
+	// ยกเลิก token ทั้งหมดของผู้ใช้ทันที
+	if err := revokeActiveToken(ctx, s.Sessions, s.Blacklist, user.TenantID, user.Email); err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถยกเลิกโทเค็นของผู้ใช้ได้")
 	}
 
+	s.Audit.Record(ctx, models.AuditEvent{
+		TenantID:     user.TenantID,
+		Action:       "user.deleted",
+		ActorEmail:   actorEmail(ctx, s.Tenants),
+		SubjectID:    objID.Hex(),
+		SubjectEmail: user.Email,
+	})
+
 	return &pb.DeleteUserReply{
 		Message: "ลบข้อมูลผู้ใช้สำเร็จ (soft delete)",
 	}, nil
 }
-func (s *UserService) ListUsers(ctx context.Context, in *pb.ListUsersRequest) (*pb.ListUsersReply, error) {
-	// ดึง metadata จาก context
-	md, ok := metadata.FromIncomingContext(ctx)
-	if !ok {
-		return nil, status.Error(codes.Unauthenticated, "missing metadata")
+
+func (s *UserService) RestoreUser(ctx context.Context, in *pb.RestoreUserRequest) (*pb.RestoreUserReply, error) {
+	// ตรวจสอบสิทธิ์ admin
+	claims, err := requireAdmin(ctx, s.Blacklist, s.Tenants)
+	if err != nil {
+		return nil, err
 	}
 
-	// ดึงค่า authorization header
-	authHeaders := md["authorization"]
-	if len(authHeaders) == 0 {
-		return nil, status.Error(codes.Unauthenticated, "authorization token is not supplied")
+	objID, err := primitive.ObjectIDFromHex(in.GetId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "ID ไม่ถูกต้อง")
+	}
+	current, err := s.tenantUser(ctx, scopeTenant(ctx, claims), objID.Hex(), true)
+	if err != nil {
+		return nil, err
 	}
 
-	// ดึง token จาก "Bearer <token>"
-	tokenStr := strings.TrimPrefix(authHeaders[0], "Bearer ")
-	if tokenStr == "" {
-		return nil, status.Error(codes.Unauthenticated, "authorization token is empty")
+	// กู้คืนได้เฉพาะผู้ใช้ที่ถูก soft delete อยู่ (พร้อม event "user.restored")
+	event, err := newUserEvent(current, "user.restored", map[string]interface{}{"email": current.Email})
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถสร้าง event ได้")
+	}
+	user, err := s.Outbox.RestoreUserWithEvent(ctx, objID.Hex(), time.Now(), event)
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, "ไม่พบผู้ใช้ที่ถูกลบตาม ID นี้")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "เกิดข้อผิดพลาดในการกู้คืนผู้ใช้")
 	}
 
-	// เรียก ParseToken โดยส่ง token string
-	// ตรวจสอบและแปลง token เป็น claims
-	claims, err := auth.ParseToken(tokenStr)
+	adminEmail, _ := claims["email"].(string)
+	s.Audit.Record(ctx, models.AuditEvent{
+		TenantID:     user.TenantID,
+		Action:       "user.restored",
+		ActorEmail:   adminEmail,
+		SubjectID:    objID.Hex(),
+		SubjectEmail: user.Email,
+	})
+
+	return &pb.RestoreUserReply{
+		Message: "กู้คืนผู้ใช้สำเร็จ",
+	}, nil
+}
+
+func (s *UserService) PurgeUser(ctx context.Context, in *pb.PurgeUserRequest) (*pb.PurgeUserReply, error) {
+	// ตรวจสอบสิทธิ์ admin
+	claims, err := requireAdmin(ctx, s.Blacklist, s.Tenants)
 	if err != nil {
-		return nil, status.Error(codes.Unauthenticated, "invalid token")
+		return nil, err
 	}
 
-	// ตรวจสอบ role  == admin
-	role, ok := claims["role"].(string)
-	if !ok || role != "admin" {
-		return nil, status.Error(codes.PermissionDenied, "สามารถดูได้เฉพาะ Admin เท่านั้น")
+	objID, err := primitive.ObjectIDFromHex(in.GetId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "ID ไม่ถูกต้อง")
+	}
+	user, err := s.tenantUser(ctx, scopeTenant(ctx, claims), objID.Hex(), true)
+	if err != nil {
+		return nil, err
+	}
+
+	adminEmail, _ := claims["email"].(string)
+	if err := s.purgeUser(ctx, user, adminEmail); err != nil {
+		return nil, err
+	}
+
+	return &pb.PurgeUserReply{
+		Message: "ลบข้อมูลผู้ใช้ถาวรสำเร็จ",
+	}, nil
+}
+
+func (s *UserService) SetUserRole(ctx context.Context, in *pb.SetUserRoleRequest) (*pb.SetUserRoleReply, error) {
+	// ตรวจสอบสิทธิ์ admin
+	claims, err := requireAdmin(ctx, s.Blacklist, s.Tenants)
+	if err != nil {
+		return nil, err
 	}
-	// สร้าง filter ค้นหา (ไม่รวมผู้ถูกลบ)
-	filter := bson.M{"deleted": bson.M{"$ne": true}}
 
-	// เงื่อนไขค้นหาด้วยชื่อ/อีเมล
-	if in.GetName() != "" {
-		filter["username"] = bson.M{"$regex": in.GetName(), "$options": "i"}
+	objID, err := primitive.ObjectIDFromHex(in.GetId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "ID ไม่ถูกต้อง")
 	}
-	if in.GetEmail() != "" {
-		filter["email"] = bson.M{"$regex": in.GetEmail(), "$options": "i"}
+	tenantID := scopeTenant(ctx, claims)
+	role := in.GetRole()
+	if err := validation.ValidateRole(role, tenantID); err != nil {
+		return nil, err
+	}
+	user, err := s.tenantUser(ctx, tenantID, objID.Hex(), false)
+	if err != nil {
+		return nil, err
 	}
 
-	// กำหนดค่าการแบ่งหน้า
-	page := in.GetPage()
-	if page < 1 {
-		page = 1
+	// ให้หรือถอน role admin ของระบบได้เฉพาะ admin ของระบบ
+	if (role == roleAdmin || user.Role == roleAdmin) && !isPlatformAdmin(claims) {
+		return nil, status.Error(codes.PermissionDenied, "เฉพาะ admin ของระบบที่เปลี่ยน role admin ได้")
 	}
-	limit := in.GetLimit()
-	if limit <= 0 {
-		limit = 10
+	adminEmail, _ := claims["email"].(string)
+	if user.Email == adminEmail {
+		return nil, status.Error(codes.FailedPrecondition, "ไม่สามารถเปลี่ยน role ของตัวเองได้")
+	}
+	if user.Role == role {
+		return &pb.SetUserRoleReply{Message: "ผู้ใช้มี role นี้อยู่แล้ว", User: toUserItem(*user)}, nil
 	}
-	skip := (page - 1) * limit
 
-	// ดึงข้อมูลจาก MongoDB
-	cursor, err := s.UserCollection.Find(ctx, filter, options.Find().SetLimit(int64(limit)).SetSkip(int64(skip)))
+	event, err := newUserEvent(user, "user.updated", map[string]interface{}{"email": user.Email, "fields": []string{"role"}})
 	if err != nil {
-		return nil, status.Error(codes.Internal, "เกิดข้อผิดพลาดในการค้นหา")
+		return nil, status.Error(codes.Internal, "ไม่สามารถสร้าง event ได้")
+	}
+	now := time.Now()
+	err = s.Outbox.UpdateProfileWithEvent(ctx, objID.Hex(), store.ProfilePatch{Role: &role}, now, event)
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, "ไม่พบผู้ใช้")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "เกิดข้อผิดพลาดในการเปลี่ยน role")
 	}
-	defer cursor.Close(ctx)
 
-	// สร้างรายการผู้ใช้
-	var users []*pb.UserItem
-	for cursor.Next(ctx) {
-		var u struct {
-			ID        primitive.ObjectID `bson:"_id"`
-			Email     string             `bson:"email"`
-			Username  string             `bson:"username"`
-			CreatedAt time.Time          `bson:"createdAt"`
-		}
-		if err := cursor.Decode(&u); err != nil {
-			continue
+	// token เดิมยังมี role เก่าอยู่ ให้ผู้ใช้เข้าสู่ระบบใหม่
+	if err := revokeActiveToken(ctx, s.Sessions, s.Blacklist, user.TenantID, user.Email); err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถยกเลิกโทเค็นของผู้ใช้ได้")
+	}
+	s.Audit.Record(ctx, models.AuditEvent{
+		TenantID:     user.TenantID,
+		Action:       "user.role_changed",
+		ActorEmail:   adminEmail,
+		SubjectID:    objID.Hex(),
+		SubjectEmail: user.Email,
+		Details:      map[string]interface{}{"from": user.Role, "to": role},
+	})
+
+	user.Role = role
+	user.UpdatedAt = now
+	return &pb.SetUserRoleReply{
+		Message: "เปลี่ยน role สำเร็จ",
+		User:    toUserItem(*user),
+	}, nil
+}
+
+func (s *UserService) RevokeUserSessions(ctx context.Context, in *pb.RevokeUserSessionsRequest) (*pb.RevokeUserSessionsReply, error) {
+	// ตรวจสอบสิทธิ์ admin
+	claims, err := requireAdmin(ctx, s.Blacklist, s.Tenants)
+	if err != nil {
+		return nil, err
+	}
+
+	objID, err := primitive.ObjectIDFromHex(in.GetId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "ID ไม่ถูกต้อง")
+	}
+	user, err := s.tenantUser(ctx, scopeTenant(ctx, claims), objID.Hex(), true)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := revokeActiveToken(ctx, s.Sessions, s.Blacklist, user.TenantID, user.Email); err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถยกเลิกโทเค็นของผู้ใช้ได้")
+	}
+
+	// ลบความยินยอมของ OAuth client ทำให้ refresh token ที่ออกให้ client เหล่านั้นใช้ไม่ได้
+	consents, err := revokeConsents(ctx, s.Consents, user.TenantID, objID.Hex())
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถยกเลิกความยินยอมของผู้ใช้ได้")
+	}
+
+	adminEmail, _ := claims["email"].(string)
+	s.Audit.Record(ctx, models.AuditEvent{
+		TenantID:     user.TenantID,
+		Action:       "user.sessions_revoked",
+		ActorEmail:   adminEmail,
+		SubjectID:    objID.Hex(),
+		SubjectEmail: user.Email,
+		Details:      map[string]interface{}{"consents": consents},
+	})
+
+	return &pb.RevokeUserSessionsReply{
+		Message:         "ยกเลิก session ของผู้ใช้สำเร็จ",
+		RevokedConsents: int32(consents),
+	}, nil
+}
+
+func (s *UserService) UnlockUser(ctx context.Context, in *pb.UnlockUserRequest) (*pb.UnlockUserReply, error) {
+	// ตรวจสอบสิทธิ์ admin
+	claims, err := requireAdmin(ctx, s.Blacklist, s.Tenants)
+	if err != nil {
+		return nil, err
+	}
+
+	objID, err := primitive.ObjectIDFromHex(in.GetId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "ID ไม่ถูกต้อง")
+	}
+	user, err := s.tenantUser(ctx, scopeTenant(ctx, claims), objID.Hex(), false)
+	if err != nil {
+		return nil, err
+	}
+
+	// ล้างตัวนับการเข้าสู่ระบบผิดพลาด
+	if err := s.Cache.Delete(ctx, loginAttemptKey(user.TenantID, user.Email)); err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถปลดล็อกผู้ใช้ได้")
+	}
+
+	adminEmail, _ := claims["email"].(string)
+	s.Audit.Record(ctx, models.AuditEvent{
+		TenantID:     user.TenantID,
+		Action:       "user.unlocked",
+		ActorEmail:   adminEmail,
+		SubjectID:    objID.Hex(),
+		SubjectEmail: user.Email,
+	})
+
+	return &pb.UnlockUserReply{
+		Message: "ปลดล็อกผู้ใช้สำเร็จ",
+	}, nil
+}
+
+// ผู้ใช้ที่ผู้เรียกมีสิทธิ์จัดการ: admin เข้าถึงผู้ใช้ใน tenant ที่ตนดูแล ผู้ใช้อื่นเข้าถึงได้เฉพาะบัญชีของตัวเอง
+// (tenant มาจาก token เสมอ ไม่เชื่อ metadata "x-tenant-id" ของผู้เรียกที่ไม่ใช่ admin ของระบบ)
+func (s *UserService) accessibleUser(ctx context.Context, id string) (*models.User, error) {
+	_, claims, err := authenticate(ctx, s.Blacklist, s.Tenants)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.tenantUser(ctx, scopeTenant(ctx, claims), id, false)
+	if isAdmin(claims) {
+		return user, err
+	}
+
+	// ไม่บอกว่าผู้ใช้อื่นมีอยู่หรือไม่
+	email, _ := claims["email"].(string)
+	if status.Code(err) == codes.NotFound || (err == nil && (email == "" || !strings.EqualFold(email, user.Email))) {
+		return nil, status.Error(codes.PermissionDenied, "ไม่มีสิทธิ์เข้าถึงข้อมูลผู้ใช้นี้")
+	}
+	return user, err
+}
+
+// ดึงผู้ใช้ตาม ID เฉพาะใน tenant ที่กำหนด (ผู้ใช้ของ tenant อื่นถือว่าไม่พบ)
+func (s *UserService) tenantUser(ctx context.Context, tenantID string, id string, includeDeleted bool) (*models.User, error) {
+	user, err := s.Users.GetUserByID(ctx, id, includeDeleted)
+	if errors.Is(err, store.ErrNotFound) || (err == nil && user.TenantID != tenantID) {
+		return nil, status.Error(codes.NotFound, "ไม่พบผู้ใช้")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "เกิดข้อผิดพลาดในการค้นหาผู้ใช้")
+	}
+	return user, nil
+}
+
+// ลบผู้ใช้ถาวร พร้อม session, token และข้อมูลส่วนบุคคลใน audit log
+func (s *UserService) purgeUser(ctx context.Context, user *models.User, actor string) error {
+	id := user.ID.Hex()
+
+	// ยกเลิก token และลบ key ชั่วคราวที่อิงกับอีเมลของผู้ใช้
+	if err := revokeActiveToken(ctx, s.Sessions, s.Blacklist, user.TenantID, user.Email); err != nil {
+		return status.Error(codes.Internal, "ไม่สามารถยกเลิกโทเค็นของผู้ใช้ได้")
+	}
+	s.Cache.Delete(ctx, loginAttemptKey(user.TenantID, user.Email))
+
+	if err := s.Identities.DeleteUserIdentities(ctx, user.TenantID, id); err != nil {
+		return status.Error(codes.Internal, "ไม่สามารถลบบัญชีภายนอกที่ผูกไว้ได้")
+	}
+
+	// event "user.purged" ไม่มีอีเมล เพราะข้อมูลส่วนบุคคลต้องถูกลบทั้งหมด
+	event, err := newUserEvent(user, "user.purged", map[string]interface{}{})
+	if err != nil {
+		return status.Error(codes.Internal, "ไม่สามารถสร้าง event ได้")
+	}
+	if err := s.Outbox.DeleteUserWithEvent(ctx, id, event); err != nil && !errors.Is(err, store.ErrNotFound) {
+		return status.Error(codes.Internal, "เกิดข้อผิดพลาดในการลบผู้ใช้ถาวร")
+	}
+
+	// ลบข้อมูลส่วนบุคคลออกจาก audit log
+	if err := s.Audit.RedactSubject(ctx, user.TenantID, id, user.Email); err != nil {
+		return status.Error(codes.Internal, "ไม่สามารถลบข้อมูลส่วนบุคคลใน audit log ได้")
+	}
+
+	s.Audit.Record(ctx, models.AuditEvent{
+		TenantID:   user.TenantID,
+		Action:     "user.purged",
+		ActorEmail: actor,
+		SubjectID:  id,
+	})
+	return nil
+}
+
+func (s *UserService) ListUsers(ctx context.Context, in *pb.ListUsersRequest) (*pb.ListUsersReply, error) {
+	// ตรวจสอบ token และ role == admin
+	claims, err := requireAdmin(ctx, s.Blacklist, s.Tenants)
+	if err != nil {
+		return nil, err
+	}
+	// เงื่อนไขค้นหาจาก request (ค่าเริ่มต้นไม่รวมผู้ถูกลบ) เฉพาะผู้ใช้ใน tenant ที่ดูแล
+	filter, err := buildUserSearchQuery(in)
+	if err != nil {
+		return nil, err
+	}
+	filter.TenantID = scopeTenant(ctx, claims)
+
+	// การเรียงลำดับ (ลำดับคงที่ด้วย ID ทำให้หน้าไม่ซ้อนหรือข้ามกัน)
+	order, err := parseOrderBy(in.GetOrderBy())
+	if err != nil {
+		return nil, err
+	}
+	limit := clampLimit(in.GetLimit())
+	query := store.UserListQuery{
+		Filter: filter,
+		Order:  order,
+		Limit:  limit + 1, // ดึงเกิน 1 รายการเพื่อรู้ว่ามีหน้าถัดไปหรือไม่
+	}
+
+	// cursor-based pagination: ต่อจากรายการสุดท้ายของหน้าก่อน
+	if in.GetPageToken() != "" {
+		if query.After, err = decodePageToken(in.GetPageToken(), order); err != nil {
+			return nil, err
 		}
-		users = append(users, &pb.UserItem{
-			Id:        u.ID.Hex(),
-			Email:     u.Email,
-			Username:  u.Username,
-			CreatedAt: u.CreatedAt.Format(time.RFC3339),
-		})
+	} else if in.GetPage() > 1 {
+		// รองรับ client เดิมที่ใช้ page (ช้าเมื่อข้อมูลเยอะ)
+		query.Skip = int64(in.GetPage()-1) * limit
 	}
 
-	// นับจำนวนทั้งหมด
-	total, err := s.UserCollection.CountDocuments(ctx, filter)
+	page, err := s.Users.ListUsers(ctx, query)
 	if err != nil {
-		return nil, status.Error(codes.Internal, "ไม่สามารถนับผู้ใช้ทั้งหมดได้")
+		return nil, status.Error(codes.Internal, "เกิดข้อผิดพลาดในการค้นหา")
+	}
+
+	reply := &pb.ListUsersReply{}
+	if int64(len(page)) > limit {
+		page = page[:limit]
+		reply.NextPageToken = encodePageToken(order, page[len(page)-1])
+	}
+	for _, u := range page {
+		reply.Users = append(reply.Users, toUserItem(u))
+	}
+
+	// นับจำนวนทั้งหมดเฉพาะเมื่อร้องขอ (หรือ client เดิมที่ใช้ page)
+	// ถ้าไม่มีเงื่อนไขค้นหาและไม่ได้ขอจำนวนที่แน่นอน จะนับแบบจำกัดจำนวนและเวลา (ถ้า backend รองรับ)
+	if in.GetIncludeTotal() || in.GetPage() > 0 {
+		total, estimated, err := s.Users.CountUsers(ctx, filter, filter.IsDefault() && !in.GetIncludeTotal())
+		if err != nil {
+			return nil, status.Error(codes.Internal, "ไม่สามารถนับผู้ใช้ทั้งหมดได้")
+		}
+		reply.Total = int32(total)
+		reply.TotalIsEstimate = estimated
 	}
 
 	// ส่งข้อมูลกลับเป็น protobuf
-	return &pb.ListUsersReply{
-		Users: users,
-		Total: int32(total),
-	}, nil
+	return reply, nil
+}
+
+// แปลงเงื่อนไขค้นหาใน ListUsersRequest เป็น search.UserQuery
+func buildUserSearchQuery(in *pb.ListUsersRequest) (search.UserQuery, error) {
+	var query search.UserQuery
+	mode, err := search.ParseMatchMode(in.GetMatchMode())
+	if err != nil {
+		return query, err
+	}
+	deleted, err := search.ParseDeletedFilter(in.GetDeleted())
+	if err != nil {
+		return query, err
+	}
+	createdAfter, err := search.ParseTime(in.GetCreatedAfter())
+	if err != nil {
+		return query, err
+	}
+	createdBefore, err := search.ParseTime(in.GetCreatedBefore())
+	if err != nil {
+		return query, err
+	}
+
+	query = search.UserQuery{
+		Username:      in.GetName(),
+		Email:         in.GetEmail(),
+		Mode:          mode,
+		Role:          in.GetRole(),
+		CreatedAfter:  createdAfter,
+		CreatedBefore: createdBefore,
+		Deleted:       deleted,
+	}
+	if in.GetVerified() != nil {
+		verified := in.GetVerified().GetValue()
+		query.Verified = &verified
+	}
+	return query, query.Validate()
 }
@@ -0,0 +1,272 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	pb "auth-microservice/auth-microservice/proto"
+	"auth-microservice/internal/events"
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/store"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	maxWatchUserIDs    = 100
+	watchBatchSize     = 100
+	watchPollInterval  = 2 * time.Second // ตรวจ outbox แม้ไม่มีแจ้งจาก bus (event ที่ relay ของ instance อื่นเผยแพร่)
+	watchSettleDelay   = 5 * time.Second // transaction ที่ได้ ID ก่อนอาจ commit หลัง event ที่ใหม่กว่านี้
+	changeStreamPrefix = "cs."           // resume token จาก change stream ของฐานข้อมูล
+	outboxTokenPrefix  = "ev."           // resume token = ID ของ event ใน outbox
+)
+
+// ประเภทการเปลี่ยนแปลงของผู้ใช้ตามประเภทของ event ใน outbox
+var userEventChanges = map[string]string{
+	"user.registered": store.UserCreated,
+	"user.updated":    store.UserUpdated,
+	"user.deleted":    store.UserDeleted,
+	"user.restored":   store.UserRestored,
+	"user.purged":     store.UserPurged,
+}
+
+func (s *UserService) WatchUsers(in *pb.WatchUsersRequest, stream grpc.ServerStreamingServer[pb.UserChangeEvent]) error {
+	ctx := stream.Context()
+
+	// ตรวจสอบสิทธิ์ admin
+	claims, err := requireAdmin(ctx, s.Blacklist, s.Tenants)
+	if err != nil {
+		return err
+	}
+	tenantID := scopeTenant(ctx, claims)
+
+	// ตรวจสอบตัวกรอง
+	wanted := map[string]bool{}
+	for _, t := range in.GetTypes() {
+		if !isUserChangeType(t) {
+			return status.Error(codes.InvalidArgument, "ประเภทการเปลี่ยนแปลงไม่ถูกต้อง: "+t)
+		}
+		wanted[t] = true
+	}
+	userIDs := uniqueStrings(in.GetUserIds())
+	if len(userIDs) > maxWatchUserIDs {
+		return status.Error(codes.InvalidArgument, "ระบุผู้ใช้ได้ไม่เกิน 100 รายการ")
+	}
+	for _, id := range userIDs {
+		if _, err := primitive.ObjectIDFromHex(id); err != nil {
+			return status.Error(codes.InvalidArgument, "ID ไม่ถูกต้อง: "+id)
+		}
+	}
+
+	// stream สิ้นสุดเมื่อ token ของ admin หมดอายุ ให้เชื่อมต่อใหม่ด้วย token ใหม่และ resumeToken ล่าสุด
+	watchCtx := ctx
+	if exp, ok := claims["exp"].(float64); ok {
+		var cancel context.CancelFunc
+		watchCtx, cancel = context.WithDeadline(ctx, time.Unix(int64(exp), 0))
+		defer cancel()
+	}
+
+	send := func(change store.UserChange) error {
+		if len(wanted) > 0 && !wanted[change.Type] {
+			return nil
+		}
+		return stream.Send(toUserChangeEvent(change))
+	}
+
+	token := in.GetResumeToken()
+	switch {
+	case strings.HasPrefix(token, outboxTokenPrefix):
+		err = s.watchOutbox(watchCtx, tenantID, userIDs, strings.TrimPrefix(token, outboxTokenPrefix), send)
+	case token != "" && !strings.HasPrefix(token, changeStreamPrefix):
+		return status.Error(codes.InvalidArgument, "resumeToken ไม่ถูกต้อง")
+	case s.Changes == nil && token != "":
+		return status.Error(codes.FailedPrecondition, "ฐานข้อมูลนี้ไม่รองรับ resumeToken จาก change stream")
+	case s.Changes == nil:
+		err = s.watchOutbox(watchCtx, tenantID, userIDs, "", send)
+	default:
+		query := store.UserWatchQuery{TenantID: tenantID, UserIDs: userIDs, ResumeToken: strings.TrimPrefix(token, changeStreamPrefix)}
+		err = s.Changes.WatchUsers(watchCtx, query, func(change store.UserChange) error {
+			change.ResumeToken = changeStreamPrefix + change.ResumeToken
+			return send(change)
+		})
+		if errors.Is(err, store.ErrWatchUnsupported) {
+			if token != "" {
+				return status.Error(codes.FailedPrecondition, "ฐานข้อมูลนี้ไม่รองรับ resumeToken จาก change stream")
+			}
+			// MongoDB แบบ standalone ไม่มี change stream ใช้ event จาก outbox แทน
+			err = s.watchOutbox(watchCtx, tenantID, userIDs, "", send)
+		}
+	}
+
+	switch {
+	case ctx.Err() != nil:
+		// client ยกเลิกเอง
+		return status.FromContextError(ctx.Err()).Err()
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.Unauthenticated, "โทเค็นหมดอายุ กรุณาเชื่อมต่อใหม่พร้อม resumeToken ล่าสุด")
+	case errors.Is(err, store.ErrInvalidResumeToken):
+		return status.Error(codes.OutOfRange, "resumeToken หมดอายุหรือใช้ไม่ได้แล้ว กรุณาโหลดข้อมูลใหม่ด้วย ListUsers แล้วเริ่มติดตามใหม่")
+	case err != nil:
+		if _, ok := status.FromError(err); ok {
+			return err
+		}
+		return status.Error(codes.Internal, "เกิดข้อผิดพลาดในการติดตามการเปลี่ยนแปลงของผู้ใช้")
+	}
+	return nil
+}
+
+// ติดตามการเปลี่ยนแปลงจาก event ใน outbox ตามลำดับ ID (afterID = ID ของ event ล่าสุดที่ client ได้รับ)
+// ตรวจ outbox ใหม่เมื่อ relay เผยแพร่ event ของ tenant ผ่าน bus หรือทุก watchPollInterval
+func (s *UserService) watchOutbox(ctx context.Context, tenantID string, userIDs []string, afterID string, send func(store.UserChange) error) error {
+	cursor := afterID
+	if cursor == "" {
+		// ไม่มี resumeToken: เริ่มจาก event ที่บันทึกหลังจากนี้ (ObjectID เรียงตามเวลา)
+		cursor = primitive.NewObjectIDFromTimestamp(time.Now()).Hex()
+	} else {
+		id, err := primitive.ObjectIDFromHex(cursor)
+		if err != nil {
+			return status.Error(codes.InvalidArgument, "resumeToken ไม่ถูกต้อง")
+		}
+		// event ที่เผยแพร่แล้วถูกลบหลังระยะเก็บรักษา
+		if time.Since(id.Timestamp()) > events.DefaultOutboxRetention {
+			return store.ErrInvalidResumeToken
+		}
+	}
+
+	wake := make(chan struct{}, 1)
+	if s.Bus != nil {
+		msgs, cancel := s.Bus.Subscribe(16)
+		defer cancel()
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case msg := <-msgs:
+					if msg.TenantID != tenantID {
+						continue
+					}
+					select {
+					case wake <- struct{}{}:
+					default:
+					}
+				}
+			}
+		}()
+	}
+
+	types := make([]string, 0, len(userEventChanges))
+	for t := range userEventChanges {
+		types = append(types, t)
+	}
+	ticker := time.NewTicker(watchPollInterval)
+	defer ticker.Stop()
+
+	// cursor เลื่อนผ่านเฉพาะ event ที่พ้น watchSettleDelay แล้ว event ที่ใหม่กว่าจะถูกอ่านซ้ำในรอบถัดไป
+	// จึงจำ ID ที่ส่งไปแล้วไว้ไม่ให้ส่งซ้ำ
+	sent := map[string]bool{}
+	for {
+		list, err := s.Outbox.ListOutboxEvents(ctx, store.OutboxListQuery{
+			TenantID:     tenantID,
+			AfterID:      cursor,
+			Types:        types,
+			AggregateIDs: userIDs,
+			Limit:        watchBatchSize,
+		})
+		if err != nil {
+			return err
+		}
+
+		settled := time.Now().Add(-watchSettleDelay)
+		advance := true
+		for _, ev := range list {
+			id := ev.ID.Hex()
+			if !sent[id] {
+				change, err := s.outboxChange(ctx, ev)
+				if err != nil {
+					return err
+				}
+				if err := send(change); err != nil {
+					return err
+				}
+				sent[id] = true
+			}
+			if advance && ev.CreatedAt.Before(settled) {
+				cursor = id
+				delete(sent, id)
+			} else {
+				advance = false
+			}
+		}
+		if advance && len(list) == watchBatchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// แปลง event ใน outbox เป็นการเปลี่ยนแปลงของผู้ใช้ พร้อมข้อมูลล่าสุดของผู้ใช้
+func (s *UserService) outboxChange(ctx context.Context, ev models.OutboxEvent) (store.UserChange, error) {
+	change := store.UserChange{
+		ResumeToken: outboxTokenPrefix + ev.ID.Hex(),
+		Type:        userEventChanges[ev.Type],
+		UserID:      ev.AggregateID,
+		TenantID:    ev.TenantID,
+		OccurredAt:  ev.CreatedAt,
+	}
+	if change.Type == store.UserUpdated {
+		var data struct {
+			Fields []string `json:"fields"`
+		}
+		if err := json.Unmarshal([]byte(ev.Payload), &data); err == nil {
+			change.UpdatedFields = data.Fields
+		}
+	}
+	if change.Type == store.UserPurged {
+		return change, nil
+	}
+
+	user, err := s.Users.GetUserByID(ctx, ev.AggregateID, true)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		return change, err
+	}
+	change.User = user
+	return change, nil
+}
+
+func isUserChangeType(t string) bool {
+	for _, changeType := range userEventChanges {
+		if t == changeType {
+			return true
+		}
+	}
+	return false
+}
+
+// แปลงการเปลี่ยนแปลงเป็น UserChangeEvent (UserItem ไม่มี hash รหัสผ่าน)
+func toUserChangeEvent(change store.UserChange) *pb.UserChangeEvent {
+	event := &pb.UserChangeEvent{
+		ResumeToken:   change.ResumeToken,
+		Type:          change.Type,
+		UserId:        change.UserID,
+		TenantId:      change.TenantID,
+		OccurredAt:    change.OccurredAt.UTC().Format(time.RFC3339),
+		UpdatedFields: change.UpdatedFields,
+	}
+	if change.User != nil {
+		event.User = toUserItem(*change.User)
+	}
+	return event
+}
@@ -0,0 +1,266 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	pb "auth-microservice/auth-microservice/proto"
+	"auth-microservice/internal/audit"
+	"auth-microservice/internal/events"
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/notify"
+	"auth-microservice/internal/store"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+)
+
+// event จาก outbox ถึง WatchUsers ภายในรอบ poll ถัดไปเสมอ
+const watchTimeout = 3 * watchPollInterval
+
+// UserService บน SQLite (ไม่มี change stream จึงใช้ event จาก outbox) กับ relay ที่แจ้ง WatchUsers ผ่าน bus
+type watchFixture struct {
+	stores      *store.Stores
+	auditLogger *audit.Logger
+	service     *UserService
+	relay       *events.Relay
+	adminCtx    context.Context
+}
+
+func newWatchFixture(t *testing.T) *watchFixture {
+	t.Helper()
+	stores := newTestStores(t)
+	auditLogger := audit.NewLogger(stores.Audit)
+	bus := events.NewMemoryBus()
+	return &watchFixture{
+		stores:      stores,
+		auditLogger: auditLogger,
+		service:     NewUserService(stores, bus, auditLogger),
+		relay:       events.NewRelay(stores.Outbox, bus),
+		adminCtx:    adminContext(t, stores, auditLogger),
+	}
+}
+
+// สมัครผู้ใช้แล้วคืน ID
+func (f *watchFixture) register(t *testing.T, email string, username string) string {
+	t.Helper()
+	authService := NewAuthService(f.stores, notify.NewLogNotifier(), f.auditLogger)
+	if _, err := authService.Register(context.Background(), &pb.RegisterRequest{Email: email, Username: username, Password: testPassword}); err != nil {
+		t.Fatalf("Register(%s): %v", email, err)
+	}
+	user, err := f.stores.Users.GetUserByEmail(context.Background(), models.DefaultTenantID, email)
+	if err != nil {
+		t.Fatalf("GetUserByEmail(%s): %v", email, err)
+	}
+	return user.ID.Hex()
+}
+
+// เริ่ม WatchUsers ใน goroutine คืน stream, ฟังก์ชันยกเลิก และ error เมื่อ WatchUsers จบ
+func (f *watchFixture) watch(in *pb.WatchUsersRequest) (*testServerStream[pb.UserChangeEvent], context.CancelFunc, <-chan error) {
+	ctx, cancel := context.WithCancel(f.adminCtx)
+	stream := newTestServerStream[pb.UserChangeEvent](ctx)
+	done := make(chan error, 1)
+	go func() { done <- f.service.WatchUsers(in, stream) }()
+	if in.GetResumeToken() == "" {
+		// ไม่มี resumeToken: WatchUsers เริ่มจากเวลาที่เริ่มติดตาม จึงรอให้เริ่มก่อนแก้ไขผู้ใช้
+		time.Sleep(200 * time.Millisecond)
+	}
+	return stream, cancel, done
+}
+
+// เผยแพร่ event ใน outbox เพื่อแจ้ง WatchUsers ผ่าน bus
+func (f *watchFixture) publish(t *testing.T) {
+	t.Helper()
+	if _, err := f.relay.PublishPending(context.Background()); err != nil {
+		t.Fatalf("PublishPending: %v", err)
+	}
+}
+
+func (f *watchFixture) update(t *testing.T, id string, displayName string) {
+	t.Helper()
+	if _, err := f.service.UpdateUser(f.adminCtx, &pb.UpdateUserRequest{Id: id, DisplayName: displayName}); err != nil {
+		t.Fatalf("UpdateUser: %v", err)
+	}
+}
+
+func wantChange(t *testing.T, event *pb.UserChangeEvent, changeType string, userID string) {
+	t.Helper()
+	if event.GetType() != changeType || event.GetUserId() != userID || event.GetTenantId() != models.DefaultTenantID {
+		t.Fatalf("event = %v, want %s of %s", event, changeType, userID)
+	}
+}
+
+func TestWatchUsersResumesFromToken(t *testing.T) {
+	f := newWatchFixture(t)
+	alice := f.register(t, "alice@example.com", "alice")
+	bob := f.register(t, "bob@example.com", "bob")
+
+	// ไม่มี resumeToken: ได้เฉพาะการเปลี่ยนแปลงหลังเริ่มติดตาม
+	stream, cancel, done := f.watch(&pb.WatchUsersRequest{})
+	f.update(t, alice, "Alice")
+	f.publish(t)
+	first := stream.next(t, watchTimeout)
+	wantChange(t, first, store.UserUpdated, alice)
+	if len(first.GetUpdatedFields()) != 1 || first.GetUpdatedFields()[0] != "displayName" || first.GetUser().GetDisplayName() != "Alice" {
+		t.Fatalf("updated event = %v", first)
+	}
+	cancel()
+	wantCode(t, "WatchUsers after cancel", <-done, codes.Canceled)
+
+	// การเปลี่ยนแปลงระหว่างที่ไม่ได้เชื่อมต่อ
+	f.update(t, bob, "Bob")
+	if _, err := f.service.DeleteUser(f.adminCtx, &pb.DeleteUserRequest{Id: alice}); err != nil {
+		t.Fatalf("DeleteUser: %v", err)
+	}
+	f.publish(t)
+
+	for _, tc := range []struct {
+		name string
+		in   *pb.WatchUsersRequest
+		want [][2]string // ประเภทกับ ID ของผู้ใช้ตามลำดับ
+	}{
+		{"all changes", &pb.WatchUsersRequest{}, [][2]string{{store.UserUpdated, bob}, {store.UserDeleted, alice}}},
+		{"type filter", &pb.WatchUsersRequest{Types: []string{store.UserDeleted}}, [][2]string{{store.UserDeleted, alice}}},
+		{"user filter", &pb.WatchUsersRequest{UserIds: []string{bob}}, [][2]string{{store.UserUpdated, bob}}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.in.ResumeToken = first.GetResumeToken()
+			stream, cancel, done := f.watch(tc.in)
+			defer cancel()
+			for _, want := range tc.want {
+				wantChange(t, stream.next(t, watchTimeout), want[0], want[1])
+			}
+			// event ที่ยังไม่พ้น watchSettleDelay ถูกอ่านซ้ำในรอบ poll ถัดไปแต่ต้องไม่ถูกส่งซ้ำ
+			select {
+			case extra := <-stream.sent:
+				t.Fatalf("unexpected event after resuming: %v", extra)
+			case <-time.After(watchPollInterval + 500*time.Millisecond):
+			}
+			cancel()
+			<-done
+		})
+	}
+}
+
+func TestWatchUsersOmitsPasswordHash(t *testing.T) {
+	f := newWatchFixture(t)
+	stream, cancel, done := f.watch(&pb.WatchUsersRequest{Types: []string{store.UserCreated}})
+	defer func() { cancel(); <-done }()
+
+	id := f.register(t, "alice@example.com", "alice")
+	f.publish(t)
+	event := stream.next(t, watchTimeout)
+	wantChange(t, event, store.UserCreated, id)
+	if event.GetUser().GetEmail() != "alice@example.com" {
+		t.Fatalf("created event = %v, want the user", event)
+	}
+
+	user, err := f.stores.Users.GetUserByID(context.Background(), id, false)
+	if err != nil {
+		t.Fatalf("GetUserByID: %v", err)
+	}
+	data, err := proto.Marshal(event)
+	if err != nil {
+		t.Fatalf("proto.Marshal: %v", err)
+	}
+	if user.Password == "" || bytes.Contains(data, []byte(user.Password)) {
+		t.Fatal("WatchUsers sent the password hash")
+	}
+}
+
+func TestWatchUsersRejectsInvalidRequests(t *testing.T) {
+	f := newWatchFixture(t)
+	expired := primitive.NewObjectIDFromTimestamp(time.Now().Add(-events.DefaultOutboxRetention - time.Hour)).Hex()
+	user := registerAndLogin(t, f.stores, f.auditLogger, "alice@example.com", "alice")
+
+	for _, tc := range []struct {
+		name string
+		ctx  context.Context
+		in   *pb.WatchUsersRequest
+		want codes.Code
+	}{
+		{"non-admin", user, &pb.WatchUsersRequest{}, codes.PermissionDenied},
+		{"unknown type", f.adminCtx, &pb.WatchUsersRequest{Types: []string{"renamed"}}, codes.InvalidArgument},
+		{"invalid user ID", f.adminCtx, &pb.WatchUsersRequest{UserIds: []string{"alice"}}, codes.InvalidArgument},
+		{"unknown token prefix", f.adminCtx, &pb.WatchUsersRequest{ResumeToken: "abc"}, codes.InvalidArgument},
+		{"malformed outbox token", f.adminCtx, &pb.WatchUsersRequest{ResumeToken: outboxTokenPrefix + "abc"}, codes.InvalidArgument},
+		{"outbox token past retention", f.adminCtx, &pb.WatchUsersRequest{ResumeToken: outboxTokenPrefix + expired}, codes.OutOfRange},
+		{"change stream token without change streams", f.adminCtx, &pb.WatchUsersRequest{ResumeToken: changeStreamPrefix + "abc"}, codes.FailedPrecondition},
+	} {
+		err := f.service.WatchUsers(tc.in, newTestServerStream[pb.UserChangeEvent](tc.ctx))
+		wantCode(t, "WatchUsers with "+tc.name, err, tc.want)
+	}
+}
+
+// change stream ปลอมที่ส่งการเปลี่ยนแปลงที่กำหนดแล้วคืน err
+type fakeUserWatcher struct {
+	changes []store.UserChange
+	err     error
+	query   store.UserWatchQuery
+}
+
+func (w *fakeUserWatcher) WatchUsers(ctx context.Context, q store.UserWatchQuery, fn func(store.UserChange) error) error {
+	w.query = q
+	for _, change := range w.changes {
+		if err := fn(change); err != nil {
+			return err
+		}
+	}
+	return w.err
+}
+
+func TestWatchUsersChangeStreamTokens(t *testing.T) {
+	f := newWatchFixture(t)
+	changes := []store.UserChange{
+		{ResumeToken: "t1", Type: store.UserCreated, UserID: "u1", TenantID: models.DefaultTenantID},
+		{ResumeToken: "t2", Type: store.UserPurged, UserID: "u1", TenantID: models.DefaultTenantID},
+	}
+
+	for _, tc := range []struct {
+		name      string
+		watcher   *fakeUserWatcher
+		token     string
+		want      codes.Code
+		wantQuery string   // resumeToken ที่ส่งต่อให้ change stream
+		wantSent  []string // resumeToken ของ event ที่ client ได้รับ
+	}{
+		{"tokens get the change stream prefix", &fakeUserWatcher{changes: changes}, "", codes.OK, "", []string{"cs.t1", "cs.t2"}},
+		{"resume strips the prefix", &fakeUserWatcher{changes: changes[1:]}, "cs.t1", codes.OK, "t1", []string{"cs.t2"}},
+		{"expired change stream token", &fakeUserWatcher{err: store.ErrInvalidResumeToken}, "cs.t0", codes.OutOfRange, "t0", nil},
+		{"unsupported with a token", &fakeUserWatcher{err: store.ErrWatchUnsupported}, "cs.t1", codes.FailedPrecondition, "t1", nil},
+		{"store error", &fakeUserWatcher{err: errors.New("connection reset")}, "", codes.Internal, "", nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			f.service.Changes = tc.watcher
+			stream := newTestServerStream[pb.UserChangeEvent](f.adminCtx)
+			err := f.service.WatchUsers(&pb.WatchUsersRequest{ResumeToken: tc.token}, stream)
+			wantCode(t, "WatchUsers", err, tc.want)
+			if tc.watcher.query.ResumeToken != tc.wantQuery || tc.watcher.query.TenantID != models.DefaultTenantID {
+				t.Fatalf("change stream query = %+v, want resume token %q", tc.watcher.query, tc.wantQuery)
+			}
+			var sent []string
+			for _, event := range stream.drain() {
+				sent = append(sent, event.GetResumeToken())
+			}
+			if len(sent) != len(tc.wantSent) || (len(sent) > 0 && sent[0] != tc.wantSent[0]) || (len(sent) > 1 && sent[1] != tc.wantSent[1]) {
+				t.Fatalf("sent tokens %v, want %v", sent, tc.wantSent)
+			}
+		})
+	}
+
+	// ฐานข้อมูลไม่รองรับ change stream และไม่มี token: ใช้ event จาก outbox แทน
+	f.service.Changes = &fakeUserWatcher{err: store.ErrWatchUnsupported}
+	stream, cancel, done := f.watch(&pb.WatchUsersRequest{})
+	defer func() { cancel(); <-done }()
+	id := f.register(t, "alice@example.com", "alice")
+	f.publish(t)
+	event := stream.next(t, watchTimeout)
+	wantChange(t, event, store.UserCreated, id)
+	if event.GetResumeToken()[:len(outboxTokenPrefix)] != outboxTokenPrefix {
+		t.Fatalf("fallback resume token %q, want an outbox token", event.GetResumeToken())
+	}
+}
@@ -0,0 +1,285 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	pb "auth-microservice/auth-microservice/proto"
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/netguard"
+	"auth-microservice/internal/store"
+	"auth-microservice/internal/validation"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	webhookSecretPrefix   = "whsec_" // ส่วนนำหน้าของ secret ที่ใช้ลงลายเซ็น payload
+	maxWebhooksPerTenant  = 20
+	webhookSecretByteSize = 24
+	webhookResolveTimeout = 5 * time.Second // เวลารอ DNS ตอนตรวจ host ของ webhook
+)
+
+func (s *WebhookService) CreateWebhook(ctx context.Context, in *pb.CreateWebhookRequest) (*pb.CreateWebhookReply, error) {
+	claims, err := requireAdmin(ctx, s.Blacklist, s.Tenants)
+	if err != nil {
+		return nil, err
+	}
+	tenantID := scopeTenant(ctx, claims)
+
+	if err := validation.ValidateWebhookURL(in.GetUrl(), s.AllowLocal); err != nil {
+		return nil, err
+	}
+	if !s.AllowLocal {
+		if err := checkWebhookHost(ctx, in.GetUrl()); err != nil {
+			return nil, err
+		}
+	}
+	events := uniqueStrings(in.GetEvents())
+	if err := validation.ValidateWebhookEvents(events); err != nil {
+		return nil, err
+	}
+	if err := validation.ValidateWebhookDescription(in.GetDescription()); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.Webhooks.ListWebhooks(ctx, tenantID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถดึงรายการ webhook ได้")
+	}
+	if len(existing) >= maxWebhooksPerTenant {
+		return nil, status.Errorf(codes.ResourceExhausted, "สร้าง webhook ได้สูงสุด %d รายการต่อ tenant", maxWebhooksPerTenant)
+	}
+
+	secret, err := generateRandomToken(webhookSecretByteSize)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถสร้าง secret ได้")
+	}
+	webhook := &models.Webhook{
+		TenantID:    tenantID,
+		URL:         in.GetUrl(),
+		Secret:      webhookSecretPrefix + secret,
+		Events:      events,
+		Description: strings.TrimSpace(in.GetDescription()),
+		CreatedAt:   time.Now(),
+	}
+	if err := s.Webhooks.CreateWebhook(ctx, webhook); err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถสร้าง webhook ได้")
+	}
+
+	s.recordWebhookEvent(ctx, "webhook.created", webhook, claims, map[string]interface{}{"events": events})
+	return &pb.CreateWebhookReply{
+		Webhook: toWebhookReply(webhook),
+		Secret:  webhook.Secret,
+	}, nil
+}
+
+func (s *WebhookService) ListWebhooks(ctx context.Context, in *pb.ListWebhooksRequest) (*pb.ListWebhooksReply, error) {
+	claims, err := requireAdmin(ctx, s.Blacklist, s.Tenants)
+	if err != nil {
+		return nil, err
+	}
+	webhooks, err := s.Webhooks.ListWebhooks(ctx, scopeTenant(ctx, claims))
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถดึงรายการ webhook ได้")
+	}
+	reply := &pb.ListWebhooksReply{}
+	for i := range webhooks {
+		reply.Webhooks = append(reply.Webhooks, toWebhookReply(&webhooks[i]))
+	}
+	return reply, nil
+}
+
+func (s *WebhookService) DeleteWebhook(ctx context.Context, in *pb.DeleteWebhookRequest) (*pb.DeleteWebhookReply, error) {
+	claims, err := requireAdmin(ctx, s.Blacklist, s.Tenants)
+	if err != nil {
+		return nil, err
+	}
+	webhook, err := s.findWebhook(ctx, scopeTenant(ctx, claims), in.GetId())
+	if err != nil {
+		return nil, err
+	}
+	err = s.Webhooks.DeleteWebhook(ctx, webhook.TenantID, webhook.ID.Hex())
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, "ไม่พบ webhook")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถลบ webhook ได้")
+	}
+
+	s.recordWebhookEvent(ctx, "webhook.deleted", webhook, claims, nil)
+	return &pb.DeleteWebhookReply{
+		Message: "ลบ webhook สำเร็จ",
+	}, nil
+}
+
+func (s *WebhookService) ListDeliveries(ctx context.Context, in *pb.ListDeliveriesRequest) (*pb.ListDeliveriesReply, error) {
+	claims, err := requireAdmin(ctx, s.Blacklist, s.Tenants)
+	if err != nil {
+		return nil, err
+	}
+	tenantID := scopeTenant(ctx, claims)
+
+	switch in.GetStatus() {
+	case "", models.DeliveryPending, models.DeliverySucceeded, models.DeliveryDead:
+	default:
+		return nil, status.Error(codes.InvalidArgument, "status ต้องเป็น pending, succeeded หรือ dead")
+	}
+	if in.GetWebhookId() != "" {
+		if _, err := s.findWebhook(ctx, tenantID, in.GetWebhookId()); err != nil {
+			return nil, err
+		}
+	}
+	// page token คือ ID ของรายการสุดท้ายในหน้าก่อน (ID เรียงตามเวลาที่สร้าง)
+	if in.GetPageToken() != "" && !primitive.IsValidObjectID(in.GetPageToken()) {
+		return nil, status.Error(codes.InvalidArgument, "pageToken ไม่ถูกต้อง")
+	}
+
+	limit := clampLimit(in.GetLimit())
+	deliveries, err := s.Webhooks.ListDeliveries(ctx, store.DeliveryListQuery{
+		TenantID:  tenantID,
+		WebhookID: in.GetWebhookId(),
+		Status:    in.GetStatus(),
+		BeforeID:  in.GetPageToken(),
+		Limit:     limit,
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถดึงประวัติการส่ง webhook ได้")
+	}
+
+	reply := &pb.ListDeliveriesReply{}
+	for i := range deliveries {
+		reply.Deliveries = append(reply.Deliveries, toDeliveryReply(&deliveries[i]))
+	}
+	if int64(len(deliveries)) == limit {
+		reply.NextPageToken = deliveries[len(deliveries)-1].ID.Hex()
+	}
+	return reply, nil
+}
+
+func (s *WebhookService) RedeliverEvent(ctx context.Context, in *pb.RedeliverEventRequest) (*pb.WebhookDelivery, error) {
+	claims, err := requireAdmin(ctx, s.Blacklist, s.Tenants)
+	if err != nil {
+		return nil, err
+	}
+	tenantID := scopeTenant(ctx, claims)
+
+	previous, err := s.Webhooks.GetDelivery(ctx, tenantID, in.GetDeliveryId())
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, "ไม่พบประวัติการส่ง")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถดึงประวัติการส่งได้")
+	}
+	webhook, err := s.findWebhook(ctx, tenantID, previous.WebhookID.Hex())
+	if err != nil {
+		return nil, err
+	}
+
+	// ส่ง payload และ event ID เดิม ปลายทางที่เคยได้รับแล้วจะรู้ว่าเป็นเหตุการณ์ซ้ำ
+	now := time.Now()
+	delivery := &models.WebhookDelivery{
+		TenantID:      tenantID,
+		WebhookID:     webhook.ID,
+		EventID:       previous.EventID,
+		EventType:     previous.EventType,
+		Payload:       previous.Payload,
+		Status:        models.DeliveryPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}
+	if err := s.Webhooks.EnqueueDeliveries(ctx, []*models.WebhookDelivery{delivery}); err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถส่งเหตุการณ์ซ้ำได้")
+	}
+
+	s.recordWebhookEvent(ctx, "webhook.redelivered", webhook, claims, map[string]interface{}{
+		"eventId":    previous.EventID,
+		"deliveryId": delivery.ID.Hex(),
+	})
+	return toDeliveryReply(delivery), nil
+}
+
+// ดึง webhook ใน tenant (แปลง error ของ store เป็น gRPC status)
+func (s *WebhookService) findWebhook(ctx context.Context, tenantID string, id string) (*models.Webhook, error) {
+	webhook, err := s.Webhooks.GetWebhook(ctx, tenantID, id)
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, "ไม่พบ webhook")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ไม่สามารถดึงข้อมูล webhook ได้")
+	}
+	return webhook, nil
+}
+
+func (s *WebhookService) recordWebhookEvent(ctx context.Context, action string, w *models.Webhook, claims map[string]interface{}, details map[string]interface{}) {
+	actor, _ := claims["email"].(string)
+	if details == nil {
+		details = map[string]interface{}{}
+	}
+	details["webhookId"] = w.ID.Hex()
+	details["url"] = w.URL
+	s.Audit.Record(ctx, models.AuditEvent{
+		TenantID:   w.TenantID,
+		Action:     action,
+		ActorEmail: actor,
+		Details:    details,
+	})
+}
+
+func toWebhookReply(w *models.Webhook) *pb.Webhook {
+	return &pb.Webhook{
+		Id:          w.ID.Hex(),
+		Url:         w.URL,
+		Events:      w.Events,
+		Description: w.Description,
+		CreatedAt:   w.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func toDeliveryReply(d *models.WebhookDelivery) *pb.WebhookDelivery {
+	reply := &pb.WebhookDelivery{
+		Id:             d.ID.Hex(),
+		WebhookId:      d.WebhookID.Hex(),
+		EventId:        d.EventID,
+		EventType:      d.EventType,
+		Status:         d.Status,
+		Attempts:       int32(d.Attempts),
+		LastError:      d.LastError,
+		ResponseStatus: int32(d.ResponseStatus),
+		CreatedAt:      d.CreatedAt.Format(time.RFC3339),
+		Payload:        d.Payload,
+	}
+	if d.Status == models.DeliveryPending {
+		reply.NextAttemptAt = d.NextAttemptAt.Format(time.RFC3339)
+	}
+	if d.DeliveredAt != nil {
+		reply.DeliveredAt = d.DeliveredAt.Format(time.RFC3339)
+	}
+	return reply
+}
+
+// resolve host ของ webhook แล้วตรวจว่าทุก IP เป็นที่อยู่สาธารณะ (ตอนส่งจะตรวจ IP ที่เชื่อมต่อจริงอีกครั้ง)
+func checkWebhookHost(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, "URL ของ webhook ไม่ถูกต้อง")
+	}
+	ctx, cancel := context.WithTimeout(ctx, webhookResolveTimeout)
+	defer cancel()
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, u.Hostname())
+	if err != nil || len(addrs) == 0 {
+		return status.Error(codes.InvalidArgument, "ไม่สามารถ resolve host ของ webhook ได้")
+	}
+	for _, addr := range addrs {
+		if !netguard.IsPublicIP(addr.IP) {
+			return status.Error(codes.InvalidArgument, "URL ของ webhook ต้องชี้ไปยังที่อยู่สาธารณะ (ไม่ใช่ loopback, private หรือ link-local)")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,277 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	pb "auth-microservice/auth-microservice/proto"
+	"auth-microservice/internal/audit"
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/notify"
+	"auth-microservice/internal/store"
+	"auth-microservice/internal/webhook"
+
+	"google.golang.org/grpc/codes"
+)
+
+// HTTP server ใน process ที่รับ webhook แล้วเก็บ request ไว้ ตอบด้วย status ที่กำหนด
+type webhookReceiver struct {
+	*httptest.Server
+	mu       sync.Mutex
+	status   int
+	requests []receivedWebhook
+}
+
+type receivedWebhook struct {
+	Header http.Header
+	Body   []byte
+}
+
+func newWebhookReceiver(t *testing.T) *webhookReceiver {
+	t.Helper()
+	r := &webhookReceiver{status: http.StatusNoContent}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.requests = append(r.requests, receivedWebhook{Header: req.Header.Clone(), Body: body})
+		w.WriteHeader(r.status)
+	}))
+	t.Cleanup(r.Close)
+	return r
+}
+
+func (r *webhookReceiver) respond(status int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.status = status
+}
+
+func (r *webhookReceiver) received() []receivedWebhook {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]receivedWebhook{}, r.requests...)
+}
+
+// WebhookService กับ Dispatcher ที่รับเหตุการณ์จาก audit log และส่งไปยัง receiver
+type webhookFixture struct {
+	stores      *store.Stores
+	auditLogger *audit.Logger
+	service     *WebhookService
+	dispatcher  *webhook.Dispatcher
+	adminCtx    context.Context
+	receiver    *webhookReceiver
+	secret      string
+}
+
+func newWebhookFixture(t *testing.T, events ...string) *webhookFixture {
+	t.Helper()
+	stores := newTestStores(t)
+	auditLogger := audit.NewLogger(stores.Audit)
+	dispatcher := webhook.NewDispatcher(stores.Webhooks, true)
+	dispatcher.BaseBackoff = 0 // retry ได้ทันทีในการทดสอบ
+	auditLogger.Sinks = append(auditLogger.Sinks, dispatcher)
+	f := &webhookFixture{
+		stores:      stores,
+		auditLogger: auditLogger,
+		service:     NewWebhookService(stores, true, auditLogger),
+		dispatcher:  dispatcher,
+		adminCtx:    adminContext(t, stores, auditLogger),
+		receiver:    newWebhookReceiver(t),
+	}
+	created, err := f.service.CreateWebhook(f.adminCtx, &pb.CreateWebhookRequest{Url: f.receiver.URL + "/hooks", Events: events})
+	if err != nil {
+		t.Fatalf("CreateWebhook: %v", err)
+	}
+	f.secret = created.GetSecret()
+	return f
+}
+
+func (f *webhookFixture) deliver(t *testing.T) {
+	t.Helper()
+	if err := f.dispatcher.DeliverDue(context.Background()); err != nil {
+		t.Fatalf("DeliverDue: %v", err)
+	}
+}
+
+func (f *webhookFixture) deliveries(t *testing.T, status string) []*pb.WebhookDelivery {
+	t.Helper()
+	reply, err := f.service.ListDeliveries(f.adminCtx, &pb.ListDeliveriesRequest{Status: status})
+	if err != nil {
+		t.Fatalf("ListDeliveries: %v", err)
+	}
+	return reply.GetDeliveries()
+}
+
+// ตรวจลายเซ็นแบบเดียวกับที่ปลายทางทำ: HMAC-SHA256(secret, "<t>.<body>") และ t ต้องไม่เก่าเกินไป
+func verifyWebhookSignature(t *testing.T, secret string, got receivedWebhook) {
+	t.Helper()
+	var timestamp, signature string
+	for _, part := range strings.Split(got.Header.Get(webhook.HeaderSignature), ",") {
+		key, value, _ := strings.Cut(part, "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signature = value
+		}
+	}
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || time.Since(time.Unix(unix, 0)) > time.Minute {
+		t.Fatalf("signature timestamp %q is not recent", timestamp)
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(got.Body)
+	if !hmac.Equal([]byte(signature), []byte(hex.EncodeToString(mac.Sum(nil)))) {
+		t.Fatalf("signature %q does not match the body", got.Header.Get(webhook.HeaderSignature))
+	}
+}
+
+func TestWebhookDeliversSignedEvents(t *testing.T) {
+	f := newWebhookFixture(t, "user.registered")
+	authService := NewAuthService(f.stores, notify.NewLogNotifier(), f.auditLogger)
+	if _, err := authService.Register(context.Background(), &pb.RegisterRequest{Email: "judy@example.com", Username: "judy", Password: testPassword}); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	// เหตุการณ์ที่ webhook ไม่สนใจไม่ถูกส่ง
+	login(t, f.stores, f.auditLogger, "judy@example.com")
+	f.deliver(t)
+
+	received := f.receiver.received()
+	if len(received) != 1 {
+		t.Fatalf("receiver got %d requests, want 1", len(received))
+	}
+	got := received[0]
+	verifyWebhookSignature(t, f.secret, got)
+	var event webhook.Event
+	if err := json.Unmarshal(got.Body, &event); err != nil {
+		t.Fatalf("payload: %v\n%s", err, got.Body)
+	}
+	if event.Type != "user.registered" || event.TenantID != "default" || event.Data.Email != "judy@example.com" || event.Data.UserID == "" ||
+		got.Header.Get(webhook.HeaderEvent) != "user.registered" || got.Header.Get(webhook.HeaderEventID) != event.ID {
+		t.Fatalf("webhook = %+v %s", got.Header, got.Body)
+	}
+	if strings.Contains(string(got.Body), testPassword) {
+		t.Fatal("payload contains the password")
+	}
+
+	delivered := f.deliveries(t, models.DeliverySucceeded)
+	if len(delivered) != 1 || delivered[0].GetAttempts() != 1 || delivered[0].GetResponseStatus() != http.StatusNoContent || delivered[0].GetDeliveredAt() == "" {
+		t.Fatalf("ListDeliveries = %+v", delivered)
+	}
+	// ส่งสำเร็จแล้วไม่ถูกส่งซ้ำ
+	f.deliver(t)
+	if n := len(f.receiver.received()); n != 1 {
+		t.Fatalf("receiver got %d requests after a second run, want 1", n)
+	}
+}
+
+func TestWebhookRetriesThenDeadLetters(t *testing.T) {
+	f := newWebhookFixture(t, "user.*")
+	f.dispatcher.MaxAttempts = 3
+	f.receiver.respond(http.StatusServiceUnavailable)
+	registerAndLogin(t, f.stores, f.auditLogger, "kim@example.com", "kim")
+	if n := len(f.deliveries(t, models.DeliveryPending)); n != 2 {
+		t.Fatalf("%d pending deliveries, want user.registered and user.login", n)
+	}
+
+	f.deliver(t)
+	pending := f.deliveries(t, models.DeliveryPending)
+	if len(pending) != 2 || pending[0].GetAttempts() != 1 || pending[0].GetResponseStatus() != http.StatusServiceUnavailable ||
+		!strings.Contains(pending[0].GetLastError(), "503") || pending[0].GetNextAttemptAt() == "" {
+		t.Fatalf("pending deliveries after a failure = %+v", pending)
+	}
+
+	// ครบจำนวนครั้งแล้วย้ายไป dead letter และไม่ถูกส่งอีก
+	f.deliver(t)
+	f.deliver(t)
+	dead := f.deliveries(t, models.DeliveryDead)
+	if len(dead) != 2 || dead[0].GetAttempts() != 3 || len(f.deliveries(t, models.DeliveryPending)) != 0 {
+		t.Fatalf("dead deliveries = %+v", dead)
+	}
+	f.deliver(t)
+	if n := len(f.receiver.received()); n != 6 {
+		t.Fatalf("receiver got %d requests, want 6 (3 attempts of 2 events)", n)
+	}
+
+	// ส่งซ้ำจาก dead letter ด้วย event ID และ payload เดิม
+	f.receiver.respond(http.StatusOK)
+	redelivery, err := f.service.RedeliverEvent(f.adminCtx, &pb.RedeliverEventRequest{DeliveryId: dead[0].GetId()})
+	if err != nil {
+		t.Fatalf("RedeliverEvent: %v", err)
+	}
+	if redelivery.GetStatus() != models.DeliveryPending || redelivery.GetEventId() != dead[0].GetEventId() || redelivery.GetId() == dead[0].GetId() {
+		t.Fatalf("RedeliverEvent = %+v", redelivery)
+	}
+	f.deliver(t)
+	received := f.receiver.received()
+	last := received[len(received)-1]
+	verifyWebhookSignature(t, f.secret, last)
+	if last.Header.Get(webhook.HeaderEventID) != dead[0].GetEventId() || string(last.Body) != dead[0].GetPayload() {
+		t.Fatalf("redelivered webhook = %+v %s", last.Header, last.Body)
+	}
+	if delivered := f.deliveries(t, models.DeliverySucceeded); len(delivered) != 1 || delivered[0].GetId() != redelivery.GetId() {
+		t.Fatalf("succeeded deliveries = %+v", delivered)
+	}
+
+	_, err = f.service.RedeliverEvent(f.adminCtx, &pb.RedeliverEventRequest{DeliveryId: "000000000000000000000000"})
+	wantCode(t, "RedeliverEvent of an unknown delivery", err, codes.NotFound)
+}
+
+func TestWebhookDeliveryAfterDeleteWebhook(t *testing.T) {
+	f := newWebhookFixture(t, "user.registered")
+	registerAndLogin(t, f.stores, f.auditLogger, "leo@example.com", "leo")
+	list, _ := f.service.ListWebhooks(f.adminCtx, &pb.ListWebhooksRequest{})
+	if _, err := f.service.DeleteWebhook(f.adminCtx, &pb.DeleteWebhookRequest{Id: list.GetWebhooks()[0].GetId()}); err != nil {
+		t.Fatalf("DeleteWebhook: %v", err)
+	}
+
+	// webhook ถูกลบระหว่างรอส่ง รายการในคิวไม่ถูกส่งและไม่ retry
+	f.deliver(t)
+	if n := len(f.receiver.received()); n != 0 {
+		t.Fatalf("receiver got %d requests from a deleted webhook", n)
+	}
+}
+
+func TestWebhookRejectsLocalAddresses(t *testing.T) {
+	stores := newTestStores(t)
+	auditLogger := audit.NewLogger(stores.Audit)
+	adminCtx := adminContext(t, stores, auditLogger)
+	receiver := newWebhookReceiver(t)
+
+	// production ไม่ให้ลงทะเบียน webhook ที่ชี้ไปยังที่อยู่ภายใน
+	for _, url := range []string{receiver.URL, "https://127.0.0.1/hooks", "https://localhost/hooks", "https://10.0.0.1/hooks"} {
+		_, err := NewWebhookService(stores, false, auditLogger).CreateWebhook(adminCtx, &pb.CreateWebhookRequest{Url: url, Events: []string{"*"}})
+		wantCode(t, "CreateWebhook of "+url, err, codes.InvalidArgument)
+	}
+
+	// webhook ที่ลงทะเบียนไว้ก่อนแล้ว (หรือ DNS เปลี่ยนหลังลงทะเบียน) ก็ส่งไปยังที่อยู่ภายในไม่ได้
+	if _, err := NewWebhookService(stores, true, auditLogger).CreateWebhook(adminCtx, &pb.CreateWebhookRequest{Url: receiver.URL, Events: []string{"*"}}); err != nil {
+		t.Fatalf("CreateWebhook: %v", err)
+	}
+	dispatcher := webhook.NewDispatcher(stores.Webhooks, false)
+	auditLogger.Sinks = append(auditLogger.Sinks, dispatcher)
+	registerAndLogin(t, stores, auditLogger, "mona@example.com", "mona")
+	if err := dispatcher.DeliverDue(context.Background()); err != nil {
+		t.Fatalf("DeliverDue: %v", err)
+	}
+	if n := len(receiver.received()); n != 0 {
+		t.Fatalf("receiver on a loopback address got %d requests", n)
+	}
+	reply, _ := NewWebhookService(stores, true, auditLogger).ListDeliveries(adminCtx, &pb.ListDeliveriesRequest{})
+	if len(reply.GetDeliveries()) == 0 || !strings.Contains(reply.GetDeliveries()[0].GetLastError(), "not a public address") {
+		t.Fatalf("ListDeliveries = %+v, want a forbidden address error", reply.GetDeliveries())
+	}
+}
@@ -0,0 +1,96 @@
+package mongostore
+
+import (
+	"context"
+	"time"
+
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/store"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// APIKeyStore เก็บ API key ใน collection api_keys
+type APIKeyStore struct {
+	Collection *mongo.Collection
+}
+
+// สร้างอินสแตนซ์ของ APIKeyStore
+func NewAPIKeyStore(col *mongo.Collection) *APIKeyStore {
+	return &APIKeyStore{Collection: col}
+}
+
+func (s *APIKeyStore) CreateAPIKey(ctx context.Context, k *models.APIKey) error {
+	k.ID = primitive.NewObjectID()
+	_, err := s.Collection.InsertOne(ctx, k)
+	return err
+}
+
+func (s *APIKeyStore) GetAPIKey(ctx context.Context, tenantID string, id string) (*models.APIKey, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, store.ErrNotFound
+	}
+	return s.findOne(ctx, bson.M{"_id": objID, "tenantId": tenantID})
+}
+
+func (s *APIKeyStore) GetAPIKeyByHash(ctx context.Context, hash string) (*models.APIKey, error) {
+	return s.findOne(ctx, bson.M{"hash": hash})
+}
+
+func (s *APIKeyStore) ListAPIKeys(ctx context.Context, tenantID string, userID string) ([]models.APIKey, error) {
+	filter := bson.M{"tenantId": tenantID}
+	if userID != "" {
+		objID, err := primitive.ObjectIDFromHex(userID)
+		if err != nil {
+			return []models.APIKey{}, nil
+		}
+		filter["userId"] = objID
+	}
+	cursor, err := s.Collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+	keys := []models.APIKey{}
+	if err := cursor.All(ctx, &keys); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (s *APIKeyStore) RevokeAPIKey(ctx context.Context, tenantID string, id string, revokedAt time.Time) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return store.ErrNotFound
+	}
+	res, err := s.Collection.UpdateOne(ctx,
+		bson.M{"_id": objID, "tenantId": tenantID, "revokedAt": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"revokedAt": revokedAt}})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (s *APIKeyStore) TouchAPIKey(ctx context.Context, id string, usedAt time.Time) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return store.ErrNotFound
+	}
+	_, err = s.Collection.UpdateOne(ctx, bson.M{"_id": objID}, bson.M{"$set": bson.M{"lastUsedAt": usedAt}})
+	return err
+}
+
+func (s *APIKeyStore) findOne(ctx context.Context, filter bson.M) (*models.APIKey, error) {
+	var k models.APIKey
+	if err := s.Collection.FindOne(ctx, filter).Decode(&k); err != nil {
+		return nil, mapError(err)
+	}
+	return &k, nil
+}
@@ -0,0 +1,97 @@
+package mongostore
+
+import (
+	"context"
+
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/store"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// AuditStore เก็บ audit log ใน collection audit_logs
+type AuditStore struct {
+	Collection *mongo.Collection
+}
+
+// สร้างอินสแตนซ์ของ AuditStore
+func NewAuditStore(col *mongo.Collection) *AuditStore {
+	return &AuditStore{Collection: col}
+}
+
+func (s *AuditStore) InsertEvent(ctx context.Context, ev models.AuditEvent) error {
+	_, err := s.Collection.InsertOne(ctx, ev)
+	return err
+}
+
+func (s *AuditStore) FindBySubject(ctx context.Context, tenantID string, subjectID string, email string) ([]models.AuditEvent, error) {
+	filter := bson.M{"$or": bson.A{
+		bson.M{"subjectId": subjectID},
+		bson.M{"tenantId": tenantID, "subjectEmail": email},
+	}}
+	cursor, err := s.Collection.Find(ctx, filter, options.Find().SetSort(bson.M{"createdAt": 1}))
+	if err != nil {
+		return nil, err
+	}
+	var events []models.AuditEvent
+	if err := cursor.All(ctx, &events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func (s *AuditStore) ListEvents(ctx context.Context, q store.AuditListQuery) ([]models.AuditEvent, error) {
+	filter := bson.M{"tenantId": q.TenantID}
+	if q.AfterID != "" {
+		afterID, err := primitive.ObjectIDFromHex(q.AfterID)
+		if err != nil {
+			return nil, store.ErrNotFound
+		}
+		filter["_id"] = bson.M{"$gt": afterID}
+	}
+	if q.Action != "" {
+		filter["action"] = q.Action
+	}
+	if q.SubjectID != "" {
+		filter["subjectId"] = q.SubjectID
+	}
+	direction := 1
+	if q.Latest {
+		direction = -1
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: direction}}).SetLimit(int64(q.Limit))
+	cursor, err := s.Collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	events := []models.AuditEvent{}
+	if err := cursor.All(ctx, &events); err != nil {
+		return nil, err
+	}
+	if q.Latest {
+		for i, j := 0, len(events)-1; i < j; i, j = i+1, j-1 {
+			events[i], events[j] = events[j], events[i]
+		}
+	}
+	return events, nil
+}
+
+// ลบอีเมลและรายละเอียดออก แต่ยังเก็บเหตุการณ์ไว้ (อีเมลเดียวกันใน tenant อื่นไม่ถูกแตะ)
+func (s *AuditStore) RedactSubject(ctx context.Context, tenantID string, subjectID string, email string) error {
+	redacted := bson.M{"$set": bson.M{"subjectEmail": "", "details": bson.M{}}}
+	if _, err := s.Collection.UpdateMany(ctx, bson.M{"subjectId": subjectID}, redacted); err != nil {
+		return err
+	}
+	if email == "" {
+		return nil
+	}
+	if _, err := s.Collection.UpdateMany(ctx, bson.M{"tenantId": tenantID, "subjectEmail": email}, redacted); err != nil {
+		return err
+	}
+	_, err := s.Collection.UpdateMany(ctx, bson.M{"tenantId": tenantID, "actorEmail": email}, bson.M{"$set": bson.M{"actorEmail": ""}})
+	return err
+}
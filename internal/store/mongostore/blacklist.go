@@ -0,0 +1,35 @@
+package mongostore
+
+import (
+	"context"
+	"time"
+
+	models "auth-microservice/internal/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// BlacklistStore เก็บ token ที่ถูกยกเลิกใน collection blacklisted_tokens (TTL index ลบเมื่อหมดอายุ)
+type BlacklistStore struct {
+	Collection *mongo.Collection
+}
+
+// สร้างอินสแตนซ์ของ BlacklistStore
+func NewBlacklistStore(col *mongo.Collection) *BlacklistStore {
+	return &BlacklistStore{Collection: col}
+}
+
+func (s *BlacklistStore) AddToken(ctx context.Context, token string, expiresAt time.Time) error {
+	_, err := s.Collection.InsertOne(ctx, models.BlacklistedToken{Token: token, ExpiresAt: expiresAt})
+	return err
+}
+
+func (s *BlacklistStore) IsBlacklisted(ctx context.Context, token string) (bool, error) {
+	// ถ้ามี token ที่ตรงกันแสดงว่า token นี้ถูกเพิ่มใน blacklist แล้ว
+	count, err := s.Collection.CountDocuments(ctx, bson.M{"token": token})
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,73 @@
+package mongostore
+
+import (
+	"regexp"
+
+	"auth-microservice/internal/search"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// สร้าง MongoDB filter จากเงื่อนไขค้นหา ต้องใช้คู่กับ collation db.CaseInsensitive
+func userFilter(q search.UserQuery) (bson.M, error) {
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+	filter := bson.M{}
+	if q.TenantID != "" {
+		filter["tenantId"] = q.TenantID
+	}
+
+	switch q.Deleted {
+	case search.DeletedInclude:
+	case search.DeletedOnly:
+		filter["deleted"] = true
+	default:
+		filter["deleted"] = bson.M{"$ne": true}
+	}
+
+	for field, term := range map[string]string{"username": q.Username, "email": q.Email} {
+		if term != "" {
+			filter[field] = matchCondition(term, q.Mode)
+		}
+	}
+
+	if q.Role != "" {
+		filter["role"] = q.Role
+	}
+
+	if q.CreatedAfter != nil || q.CreatedBefore != nil {
+		createdAt := bson.M{}
+		if q.CreatedAfter != nil {
+			createdAt["$gte"] = *q.CreatedAfter
+		}
+		if q.CreatedBefore != nil {
+			createdAt["$lt"] = *q.CreatedBefore
+		}
+		filter["createdAt"] = createdAt
+	}
+
+	if q.Verified != nil {
+		if *q.Verified {
+			filter["emailVerified"] = true
+		} else {
+			filter["emailVerified"] = bson.M{"$ne": true}
+		}
+	}
+	return filter, nil
+}
+
+// เงื่อนไขจับคู่ของคำค้นหาหนึ่งคำ (ข้อความจากผู้ใช้ไม่ถูกตีความเป็น regex)
+func matchCondition(term string, mode search.MatchMode) interface{} {
+	switch mode {
+	case search.MatchExact:
+		// collation ทำให้เทียบแบบไม่สนตัวพิมพ์และใช้ index ได้
+		return term
+	case search.MatchContains:
+		// escape ทุกอักขระพิเศษ จึงไม่มี regex injection หรือ ReDoS
+		return bson.M{"$regex": regexp.QuoteMeta(term), "$options": "i"}
+	default:
+		// prefix แปลงเป็นช่วงค่าเพื่อให้ใช้ index แบบ collation ได้
+		return bson.M{"$gte": term, "$lt": term + "\uffff"}
+	}
+}
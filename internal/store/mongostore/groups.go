@@ -0,0 +1,229 @@
+package mongostore
+
+import (
+	"context"
+	"sort"
+
+	"auth-microservice/internal/db"
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/store"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// GroupStore เก็บกลุ่มใน collection groups ส่วนสมาชิกเก็บเป็น array "groups" ใน document ของผู้ใช้
+// ทำให้ดึงกลุ่มของผู้ใช้ (ใช้ตอนออก token) ได้จาก document เดียว
+type GroupStore struct {
+	Collection *mongo.Collection
+	Users      *mongo.Collection
+}
+
+// สร้างอินสแตนซ์ของ GroupStore
+func NewGroupStore(groups *mongo.Collection, users *mongo.Collection) *GroupStore {
+	return &GroupStore{Collection: groups, Users: users}
+}
+
+func (s *GroupStore) CreateGroup(ctx context.Context, g *models.Group) error {
+	g.ID = primitive.NewObjectID()
+	_, err := s.Collection.InsertOne(ctx, g)
+	if mongo.IsDuplicateKeyError(err) {
+		return &store.DuplicateError{Field: "name"}
+	}
+	return err
+}
+
+func (s *GroupStore) GetGroup(ctx context.Context, tenantID string, id string) (*models.Group, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, store.ErrNotFound
+	}
+	var g models.Group
+	if err := s.Collection.FindOne(ctx, bson.M{"_id": objID, "tenantId": tenantID}).Decode(&g); err != nil {
+		return nil, mapError(err)
+	}
+	return &g, nil
+}
+
+func (s *GroupStore) ListGroups(ctx context.Context, tenantID string) ([]models.Group, error) {
+	return s.findGroups(ctx, bson.M{"tenantId": tenantID})
+}
+
+func (s *GroupStore) DeleteGroup(ctx context.Context, tenantID string, id string) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return store.ErrNotFound
+	}
+	res, err := s.Collection.DeleteOne(ctx, bson.M{"_id": objID, "tenantId": tenantID})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return store.ErrNotFound
+	}
+	_, err = s.Users.UpdateMany(ctx,
+		bson.M{"tenantId": tenantID, "groups.groupId": objID},
+		bson.M{"$pull": bson.M{"groups": bson.M{"groupId": objID}}})
+	return err
+}
+
+func (s *GroupStore) AddMember(ctx context.Context, tenantID string, userID string, m models.GroupMembership) error {
+	objID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return store.ErrNotFound
+	}
+	filter := bson.M{"_id": objID, "tenantId": tenantID, "deleted": notDeleted}
+
+	// เป็นสมาชิกอยู่แล้ว: เปลี่ยนแค่ role
+	filter["groups.groupId"] = m.GroupID
+	res, err := s.Users.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"groups.$.role": m.Role}})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount > 0 {
+		return nil
+	}
+
+	// ยังไม่เป็นสมาชิก ($ne กันการเพิ่มซ้ำเมื่อมี request พร้อมกัน)
+	filter["groups.groupId"] = bson.M{"$ne": m.GroupID}
+	res, err = s.Users.UpdateOne(ctx, filter, bson.M{"$push": bson.M{"groups": m}})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (s *GroupStore) RemoveMember(ctx context.Context, tenantID string, groupID string, userID string) error {
+	groupObjID, err1 := primitive.ObjectIDFromHex(groupID)
+	userObjID, err2 := primitive.ObjectIDFromHex(userID)
+	if err1 != nil || err2 != nil {
+		return store.ErrNotFound
+	}
+	res, err := s.Users.UpdateOne(ctx,
+		bson.M{"_id": userObjID, "tenantId": tenantID, "groups.groupId": groupObjID},
+		bson.M{"$pull": bson.M{"groups": bson.M{"groupId": groupObjID}}})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (s *GroupStore) GetMembership(ctx context.Context, tenantID string, groupID string, userID string) (*models.GroupMembership, error) {
+	groupObjID, err1 := primitive.ObjectIDFromHex(groupID)
+	userObjID, err2 := primitive.ObjectIDFromHex(userID)
+	if err1 != nil || err2 != nil {
+		return nil, store.ErrNotFound
+	}
+	var doc struct {
+		Groups []models.GroupMembership `bson:"groups"`
+	}
+	err := s.Users.FindOne(ctx,
+		bson.M{"_id": userObjID, "tenantId": tenantID, "deleted": notDeleted, "groups.groupId": groupObjID},
+		options.FindOne().SetProjection(bson.M{"groups.$": 1})).Decode(&doc)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	if len(doc.Groups) == 0 {
+		return nil, store.ErrNotFound
+	}
+	return &doc.Groups[0], nil
+}
+
+func (s *GroupStore) ListMembers(ctx context.Context, tenantID string, groupID string) ([]models.GroupMember, error) {
+	groupObjID, err := primitive.ObjectIDFromHex(groupID)
+	if err != nil {
+		return nil, nil
+	}
+	cursor, err := s.Users.Find(ctx,
+		bson.M{"tenantId": tenantID, "deleted": notDeleted, "groups.groupId": groupObjID},
+		options.Find().SetProjection(bson.M{
+			"email":    1,
+			"username": 1,
+			"groups":   bson.M{"$elemMatch": bson.M{"groupId": groupObjID}},
+		}))
+	if err != nil {
+		return nil, err
+	}
+	var docs []struct {
+		ID       primitive.ObjectID       `bson:"_id"`
+		Email    string                   `bson:"email"`
+		Username string                   `bson:"username"`
+		Groups   []models.GroupMembership `bson:"groups"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+
+	members := make([]models.GroupMember, 0, len(docs))
+	for _, d := range docs {
+		if len(d.Groups) == 0 {
+			continue
+		}
+		members = append(members, models.GroupMember{
+			UserID:   d.ID,
+			Email:    d.Email,
+			Username: d.Username,
+			Role:     d.Groups[0].Role,
+			JoinedAt: d.Groups[0].JoinedAt,
+		})
+	}
+	sort.SliceStable(members, func(i, j int) bool { return members[i].JoinedAt.Before(members[j].JoinedAt) })
+	return members, nil
+}
+
+func (s *GroupStore) ListUserGroups(ctx context.Context, tenantID string, userID string) ([]models.UserGroup, error) {
+	objID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, store.ErrNotFound
+	}
+	var doc struct {
+		Groups []models.GroupMembership `bson:"groups"`
+	}
+	err = s.Users.FindOne(ctx, bson.M{"_id": objID, "tenantId": tenantID},
+		options.FindOne().SetProjection(bson.M{"groups": 1})).Decode(&doc)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	if len(doc.Groups) == 0 {
+		return nil, nil
+	}
+
+	memberships := make(map[primitive.ObjectID]models.GroupMembership, len(doc.Groups))
+	ids := make([]primitive.ObjectID, 0, len(doc.Groups))
+	for _, m := range doc.Groups {
+		memberships[m.GroupID] = m
+		ids = append(ids, m.GroupID)
+	}
+	groups, err := s.findGroups(ctx, bson.M{"tenantId": tenantID, "_id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.UserGroup, 0, len(groups))
+	for _, g := range groups {
+		m := memberships[g.ID]
+		result = append(result, models.UserGroup{Group: g, Role: m.Role, JoinedAt: m.JoinedAt})
+	}
+	return result, nil
+}
+
+// ค้นหากลุ่มเรียงตามชื่อ (ไม่สนตัวพิมพ์เล็ก/ใหญ่)
+func (s *GroupStore) findGroups(ctx context.Context, filter bson.M) ([]models.Group, error) {
+	cursor, err := s.Collection.Find(ctx, filter, options.Find().SetSort(bson.M{"name": 1}).SetCollation(db.CaseInsensitive))
+	if err != nil {
+		return nil, err
+	}
+	var groups []models.Group
+	if err := cursor.All(ctx, &groups); err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
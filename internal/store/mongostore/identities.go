@@ -0,0 +1,220 @@
+package mongostore
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"auth-microservice/internal/db"
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/store"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// IdentityStore เก็บ provider ใน collection identity_providers, บัญชีที่ผูกไว้ใน linked_identities,
+// directory (LDAP) ใน directories และ passkey ใน passkeys
+type IdentityStore struct {
+	Providers   *mongo.Collection
+	Links       *mongo.Collection
+	Directories *mongo.Collection
+	Passkeys    *mongo.Collection
+}
+
+// สร้างอินสแตนซ์ของ IdentityStore
+func NewIdentityStore(providers *mongo.Collection, links *mongo.Collection, directories *mongo.Collection, passkeys *mongo.Collection) *IdentityStore {
+	return &IdentityStore{Providers: providers, Links: links, Directories: directories, Passkeys: passkeys}
+}
+
+func (s *IdentityStore) CreateIdentityProvider(ctx context.Context, p *models.IdentityProvider) error {
+	p.ID = primitive.NewObjectID()
+	_, err := s.Providers.InsertOne(ctx, p)
+	if mongo.IsDuplicateKeyError(err) {
+		return &store.DuplicateError{Field: "name"}
+	}
+	return err
+}
+
+func (s *IdentityStore) GetIdentityProvider(ctx context.Context, tenantID string, id string) (*models.IdentityProvider, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, store.ErrNotFound
+	}
+	return s.findProvider(ctx, bson.M{"_id": objID, "tenantId": tenantID}, options.FindOne())
+}
+
+func (s *IdentityStore) GetIdentityProviderByName(ctx context.Context, tenantID string, name string) (*models.IdentityProvider, error) {
+	return s.findProvider(ctx, bson.M{"tenantId": tenantID, "name": name}, options.FindOne().SetCollation(db.CaseInsensitive))
+}
+
+func (s *IdentityStore) ListIdentityProviders(ctx context.Context, tenantID string) ([]models.IdentityProvider, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}}).SetCollation(db.CaseInsensitive)
+	cursor, err := s.Providers.Find(ctx, bson.M{"tenantId": tenantID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	providers := []models.IdentityProvider{}
+	if err := cursor.All(ctx, &providers); err != nil {
+		return nil, err
+	}
+	return providers, nil
+}
+
+func (s *IdentityStore) DeleteIdentityProvider(ctx context.Context, tenantID string, id string) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return store.ErrNotFound
+	}
+	res, err := s.Providers.DeleteOne(ctx, bson.M{"_id": objID, "tenantId": tenantID})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return store.ErrNotFound
+	}
+	_, err = s.Links.DeleteMany(ctx, bson.M{"providerId": objID})
+	return err
+}
+
+func (s *IdentityStore) CreateLinkedIdentity(ctx context.Context, l *models.LinkedIdentity) error {
+	_, err := s.Links.InsertOne(ctx, l)
+	if mongo.IsDuplicateKeyError(err) {
+		if strings.Contains(err.Error(), "providerId_1_subject_1") {
+			return &store.DuplicateError{Field: "subject"}
+		}
+		return &store.DuplicateError{Field: "provider"}
+	}
+	return err
+}
+
+func (s *IdentityStore) GetLinkedIdentity(ctx context.Context, providerID string, subject string) (*models.LinkedIdentity, error) {
+	objID, err := primitive.ObjectIDFromHex(providerID)
+	if err != nil {
+		return nil, store.ErrNotFound
+	}
+	var l models.LinkedIdentity
+	if err := s.Links.FindOne(ctx, bson.M{"providerId": objID, "subject": subject}).Decode(&l); err != nil {
+		return nil, mapError(err)
+	}
+	return &l, nil
+}
+
+func (s *IdentityStore) ListLinkedIdentities(ctx context.Context, tenantID string, userID string) ([]models.LinkedIdentity, error) {
+	objID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return []models.LinkedIdentity{}, nil
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}})
+	cursor, err := s.Links.Find(ctx, bson.M{"tenantId": tenantID, "userId": objID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	links := []models.LinkedIdentity{}
+	if err := cursor.All(ctx, &links); err != nil {
+		return nil, err
+	}
+	return links, nil
+}
+
+func (s *IdentityStore) TouchLinkedIdentity(ctx context.Context, providerID string, subject string, at time.Time) error {
+	objID, err := primitive.ObjectIDFromHex(providerID)
+	if err != nil {
+		return store.ErrNotFound
+	}
+	res, err := s.Links.UpdateOne(ctx, bson.M{"providerId": objID, "subject": subject}, bson.M{"$set": bson.M{"lastLoginAt": at}})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (s *IdentityStore) DeleteLinkedIdentity(ctx context.Context, tenantID string, userID string, providerID string) error {
+	userObjID, err1 := primitive.ObjectIDFromHex(userID)
+	providerObjID, err2 := primitive.ObjectIDFromHex(providerID)
+	if err1 != nil || err2 != nil {
+		return store.ErrNotFound
+	}
+	res, err := s.Links.DeleteOne(ctx, bson.M{"tenantId": tenantID, "userId": userObjID, "providerId": providerObjID})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (s *IdentityStore) DeleteUserIdentities(ctx context.Context, tenantID string, userID string) error {
+	objID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil
+	}
+	if _, err := s.Links.DeleteMany(ctx, bson.M{"tenantId": tenantID, "userId": objID}); err != nil {
+		return err
+	}
+	_, err = s.Passkeys.DeleteMany(ctx, bson.M{"tenantId": tenantID, "userId": objID})
+	return err
+}
+
+func (s *IdentityStore) CreateDirectory(ctx context.Context, d *models.Directory) error {
+	d.ID = primitive.NewObjectID()
+	_, err := s.Directories.InsertOne(ctx, d)
+	if mongo.IsDuplicateKeyError(err) {
+		return &store.DuplicateError{Field: "name"}
+	}
+	return err
+}
+
+func (s *IdentityStore) GetDirectory(ctx context.Context, tenantID string, id string) (*models.Directory, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, store.ErrNotFound
+	}
+	var d models.Directory
+	if err := s.Directories.FindOne(ctx, bson.M{"_id": objID, "tenantId": tenantID}).Decode(&d); err != nil {
+		return nil, mapError(err)
+	}
+	return &d, nil
+}
+
+func (s *IdentityStore) ListDirectories(ctx context.Context, tenantID string) ([]models.Directory, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}}).SetCollation(db.CaseInsensitive)
+	cursor, err := s.Directories.Find(ctx, bson.M{"tenantId": tenantID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	directories := []models.Directory{}
+	if err := cursor.All(ctx, &directories); err != nil {
+		return nil, err
+	}
+	return directories, nil
+}
+
+func (s *IdentityStore) DeleteDirectory(ctx context.Context, tenantID string, id string) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return store.ErrNotFound
+	}
+	res, err := s.Directories.DeleteOne(ctx, bson.M{"_id": objID, "tenantId": tenantID})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (s *IdentityStore) findProvider(ctx context.Context, filter bson.M, opts *options.FindOneOptions) (*models.IdentityProvider, error) {
+	var p models.IdentityProvider
+	if err := s.Providers.FindOne(ctx, filter, opts).Decode(&p); err != nil {
+		return nil, mapError(err)
+	}
+	return &p, nil
+}
@@ -0,0 +1,34 @@
+// Package mongostore เก็บข้อมูลของ service ใน MongoDB
+package mongostore
+
+import (
+	"auth-microservice/internal/db"
+	"auth-microservice/internal/store"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// สร้าง store ทั้งหมดบน MongoDB โดย session และข้อมูลชั่วคราวเก็บใน store ที่ส่งเข้ามา (เช่น Redis)
+// allowStandalone = ยอมเขียน outbox โดยไม่มี transaction เมื่อ MongoDB ไม่ใช่ replica set (ดู OutboxStore.AllowStandalone)
+func NewStores(client *mongo.Client, collections *db.Collections, sessions store.SessionStore, cache store.KeyValueStore, allowStandalone bool) *store.Stores {
+	users := NewUserStore(collections.Users)
+	blacklist := NewBlacklistStore(collections.Blacklist)
+	return &store.Stores{
+		Tenants:         NewTenantStore(collections.Tenants),
+		Users:           users,
+		Groups:          NewGroupStore(collections.Groups, collections.Users),
+		APIKeys:         NewAPIKeyStore(collections.APIKeys),
+		ServiceAccounts: NewServiceAccountStore(collections.Clients),
+		OAuthClients:    NewOAuthClientStore(collections.OAuthApps, collections.Consents),
+		Identities:      NewIdentityStore(collections.IdPs, collections.Links, collections.Dirs, collections.Passkeys),
+		Webhooks:        NewWebhookStore(collections.Webhooks, collections.HookQueue),
+		Outbox:          NewOutboxStore(client, users, blacklist, collections.Outbox, allowStandalone),
+		UserChanges:     NewUserWatcher(collections.Users),
+		Blacklist:       blacklist,
+		Sessions:        sessions,
+		Cache:           cache,
+		Audit:           NewAuditStore(collections.AuditLogs),
+		Settings:        NewSettingsStore(collections.Settings),
+		Close:           client.Disconnect,
+	}
+}
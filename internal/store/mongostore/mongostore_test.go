@@ -0,0 +1,54 @@
+package mongostore_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"auth-microservice/internal/db"
+	"auth-microservice/internal/migrate"
+	"auth-microservice/internal/store"
+	"auth-microservice/internal/store/mongostore"
+	"auth-microservice/internal/store/storetest"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ต้องตั้ง MONGO_TEST_URI (เช่น mongodb://localhost:27017) แต่ละกรณีทดสอบใช้ฐานข้อมูลใหม่แล้วลบทิ้งเมื่อจบ
+// session และข้อมูลชั่วคราวของ backend นี้อยู่ใน Redis จึงไม่อยู่ในชุดทดสอบนี้
+func TestMongo(t *testing.T) {
+	uri := os.Getenv("MONGO_TEST_URI")
+	if uri == "" {
+		t.Skip("MONGO_TEST_URI is not set: the MongoDB backend was NOT tested against the store conformance suite")
+	}
+	n := 0
+	storetest.Run(t, func(t *testing.T) *store.Stores {
+		n++
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+		if err != nil {
+			t.Fatalf("connect: %v", err)
+		}
+		database := client.Database(fmt.Sprintf("storetest_%d_%d", time.Now().UnixNano(), n))
+		t.Cleanup(func() {
+			database.Drop(context.Background())
+			client.Disconnect(context.Background())
+		})
+
+		migrator := migrate.NewMigrator(migrate.NewMongoJournal(database), db.Migrations(database))
+		if _, err := migrator.Up(ctx); err != nil {
+			t.Fatalf("migrate: %v", err)
+		}
+		// standalone server ใช้ทดสอบได้ แต่ outbox จะเขียนโดยไม่มี transaction
+		transactions, err := db.SupportsTransactions(ctx, client)
+		if err != nil {
+			t.Fatalf("hello: %v", err)
+		}
+		return mongostore.NewStores(client, db.NewCollections(database), nil, nil, !transactions)
+	})
+}
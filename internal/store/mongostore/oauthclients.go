@@ -0,0 +1,151 @@
+package mongostore
+
+import (
+	"context"
+	"strings"
+
+	"auth-microservice/internal/db"
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/store"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// OAuthClientStore เก็บ client ใน collection oauth_clients และความยินยอมของผู้ใช้ใน oauth_consents
+type OAuthClientStore struct {
+	Clients  *mongo.Collection
+	Consents *mongo.Collection
+}
+
+// สร้างอินสแตนซ์ของ OAuthClientStore
+func NewOAuthClientStore(clients *mongo.Collection, consents *mongo.Collection) *OAuthClientStore {
+	return &OAuthClientStore{Clients: clients, Consents: consents}
+}
+
+func (s *OAuthClientStore) CreateOAuthClient(ctx context.Context, c *models.OAuthClient) error {
+	c.ID = primitive.NewObjectID()
+	_, err := s.Clients.InsertOne(ctx, c)
+	return oauthClientDuplicate(err)
+}
+
+func (s *OAuthClientStore) GetOAuthClient(ctx context.Context, tenantID string, id string) (*models.OAuthClient, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, store.ErrNotFound
+	}
+	return s.findOne(ctx, bson.M{"_id": objID, "tenantId": tenantID})
+}
+
+func (s *OAuthClientStore) GetOAuthClientByClientID(ctx context.Context, clientID string) (*models.OAuthClient, error) {
+	return s.findOne(ctx, bson.M{"clientId": clientID})
+}
+
+func (s *OAuthClientStore) ListOAuthClients(ctx context.Context, tenantID string) ([]models.OAuthClient, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}}).SetCollation(db.CaseInsensitive)
+	cursor, err := s.Clients.Find(ctx, bson.M{"tenantId": tenantID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	clients := []models.OAuthClient{}
+	if err := cursor.All(ctx, &clients); err != nil {
+		return nil, err
+	}
+	return clients, nil
+}
+
+func (s *OAuthClientStore) SaveOAuthClient(ctx context.Context, c *models.OAuthClient) error {
+	res, err := s.Clients.ReplaceOne(ctx, bson.M{"_id": c.ID, "tenantId": c.TenantID}, c)
+	if err != nil {
+		return oauthClientDuplicate(err)
+	}
+	if res.MatchedCount == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (s *OAuthClientStore) DeleteOAuthClient(ctx context.Context, tenantID string, id string) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return store.ErrNotFound
+	}
+	var c models.OAuthClient
+	err = s.Clients.FindOneAndDelete(ctx, bson.M{"_id": objID, "tenantId": tenantID}).Decode(&c)
+	if err != nil {
+		return mapError(err)
+	}
+	_, err = s.Consents.DeleteMany(ctx, bson.M{"tenantId": tenantID, "clientId": c.ClientID})
+	return err
+}
+
+func (s *OAuthClientStore) GetConsent(ctx context.Context, tenantID string, userID string, clientID string) (*models.OAuthConsent, error) {
+	objID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, store.ErrNotFound
+	}
+	var c models.OAuthConsent
+	err = s.Consents.FindOne(ctx, bson.M{"tenantId": tenantID, "userId": objID, "clientId": clientID}).Decode(&c)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	return &c, nil
+}
+
+func (s *OAuthClientStore) SaveConsent(ctx context.Context, c *models.OAuthConsent) error {
+	filter := bson.M{"tenantId": c.TenantID, "userId": c.UserID, "clientId": c.ClientID}
+	_, err := s.Consents.ReplaceOne(ctx, filter, c, options.Replace().SetUpsert(true))
+	return err
+}
+
+func (s *OAuthClientStore) ListConsents(ctx context.Context, tenantID string, userID string) ([]models.OAuthConsent, error) {
+	objID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return []models.OAuthConsent{}, nil
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "updatedAt", Value: -1}})
+	cursor, err := s.Consents.Find(ctx, bson.M{"tenantId": tenantID, "userId": objID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	consents := []models.OAuthConsent{}
+	if err := cursor.All(ctx, &consents); err != nil {
+		return nil, err
+	}
+	return consents, nil
+}
+
+func (s *OAuthClientStore) DeleteConsent(ctx context.Context, tenantID string, userID string, clientID string) error {
+	objID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return store.ErrNotFound
+	}
+	res, err := s.Consents.DeleteOne(ctx, bson.M{"tenantId": tenantID, "userId": objID, "clientId": clientID})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (s *OAuthClientStore) findOne(ctx context.Context, filter bson.M) (*models.OAuthClient, error) {
+	var c models.OAuthClient
+	if err := s.Clients.FindOne(ctx, filter).Decode(&c); err != nil {
+		return nil, mapError(err)
+	}
+	return &c, nil
+}
+
+func oauthClientDuplicate(err error) error {
+	if mongo.IsDuplicateKeyError(err) {
+		if strings.Contains(err.Error(), "clientId_1") {
+			return &store.DuplicateError{Field: "clientId"}
+		}
+		return &store.DuplicateError{Field: "name"}
+	}
+	return err
+}
@@ -0,0 +1,234 @@
+package mongostore
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/store"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// รหัส error IllegalOperation ที่ MongoDB แบบ standalone ตอบเมื่อใช้ transaction
+const codeIllegalOperation = 20
+
+// MongoDB แบบ standalone ไม่รองรับ transaction จึงเขียนข้อมูลพร้อม event ไม่ได้
+var ErrTransactionsUnsupported = errors.New("MongoDB does not support transactions (standalone server): run it as a replica set")
+
+// OutboxStore เก็บ domain event ใน collection outbox และเขียนพร้อมข้อมูลผู้ใช้/blacklist ใน transaction เดียวกัน
+// (transaction ต้องใช้ replica set หรือ sharded cluster)
+type OutboxStore struct {
+	Client    *mongo.Client
+	Users     *UserStore
+	Blacklist *BlacklistStore
+	Outbox    *mongo.Collection
+	// true = ยอมเขียนตามลำดับโดยไม่มี transaction เมื่อ MongoDB เป็น standalone (สำหรับเครื่อง dev เท่านั้น)
+	// ซึ่งอาจได้ข้อมูลที่ไม่มี event ถ้า process หยุดระหว่างสองคำสั่ง false = คืน ErrTransactionsUnsupported
+	AllowStandalone bool
+
+	warnOnce sync.Once
+}
+
+// สร้างอินสแตนซ์ของ OutboxStore
+func NewOutboxStore(client *mongo.Client, users *UserStore, blacklist *BlacklistStore, outbox *mongo.Collection, allowStandalone bool) *OutboxStore {
+	return &OutboxStore{Client: client, Users: users, Blacklist: blacklist, Outbox: outbox, AllowStandalone: allowStandalone}
+}
+
+func (s *OutboxStore) CreateUserWithEvent(ctx context.Context, u *models.User, ev *models.OutboxEvent) error {
+	return s.withTransaction(ctx, func(ctx context.Context) error {
+		if err := s.Users.CreateUser(ctx, u); err != nil {
+			return err
+		}
+		ev.AggregateID = u.ID.Hex()
+		return s.insertEvent(ctx, ev)
+	})
+}
+
+func (s *OutboxStore) AddTokenWithEvent(ctx context.Context, token string, expiresAt time.Time, ev *models.OutboxEvent) error {
+	return s.withTransaction(ctx, func(ctx context.Context) error {
+		if err := s.Blacklist.AddToken(ctx, token, expiresAt); err != nil {
+			return err
+		}
+		return s.insertEvent(ctx, ev)
+	})
+}
+
+func (s *OutboxStore) UpdateProfileWithEvent(ctx context.Context, id string, patch store.ProfilePatch, updatedAt time.Time, ev *models.OutboxEvent) error {
+	return s.withTransaction(ctx, func(ctx context.Context) error {
+		if err := s.Users.UpdateProfile(ctx, id, patch, updatedAt); err != nil {
+			return err
+		}
+		return s.insertEvent(ctx, ev)
+	})
+}
+
+func (s *OutboxStore) SoftDeleteUserWithEvent(ctx context.Context, id string, deletedAt time.Time, ev *models.OutboxEvent) (*models.User, error) {
+	var user *models.User
+	err := s.withTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		if user, err = s.Users.SoftDeleteUser(ctx, id, deletedAt); err != nil {
+			return err
+		}
+		return s.insertEvent(ctx, ev)
+	})
+	return user, err
+}
+
+func (s *OutboxStore) RestoreUserWithEvent(ctx context.Context, id string, updatedAt time.Time, ev *models.OutboxEvent) (*models.User, error) {
+	var user *models.User
+	err := s.withTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		if user, err = s.Users.RestoreUser(ctx, id, updatedAt); err != nil {
+			return err
+		}
+		return s.insertEvent(ctx, ev)
+	})
+	return user, err
+}
+
+func (s *OutboxStore) DeleteUserWithEvent(ctx context.Context, id string, ev *models.OutboxEvent) error {
+	return s.withTransaction(ctx, func(ctx context.Context) error {
+		if err := s.Users.DeleteUser(ctx, id); err != nil {
+			return err
+		}
+		return s.insertEvent(ctx, ev)
+	})
+}
+
+func (s *OutboxStore) CreateUsersWithEvents(ctx context.Context, users []*models.User, events []*models.OutboxEvent) ([]error, error) {
+	// บันทึกทีละคนใน transaction ของแต่ละคน: write error (เช่น key ซ้ำ) ใน transaction ของ MongoDB
+	// ทำให้ transaction ถูกยกเลิกทั้งชุด จึงรวมหลายคนไว้ใน transaction เดียวไม่ได้
+	results := make([]error, len(users))
+	for i, u := range users {
+		err := s.CreateUserWithEvent(ctx, u, events[i])
+		if _, ok := store.IsDuplicate(err); ok {
+			results[i] = err
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
+
+func (s *OutboxStore) ListOutboxEvents(ctx context.Context, q store.OutboxListQuery) ([]models.OutboxEvent, error) {
+	filter := bson.M{"tenantId": q.TenantID}
+	if q.AfterID != "" {
+		afterID, err := primitive.ObjectIDFromHex(q.AfterID)
+		if err != nil {
+			return nil, store.ErrNotFound
+		}
+		filter["_id"] = bson.M{"$gt": afterID}
+	}
+	if len(q.Types) > 0 {
+		filter["type"] = bson.M{"$in": q.Types}
+	}
+	if len(q.AggregateIDs) > 0 {
+		filter["aggregateId"] = bson.M{"$in": q.AggregateIDs}
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetLimit(int64(q.Limit))
+	cursor, err := s.Outbox.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	list := []models.OutboxEvent{}
+	if err := cursor.All(ctx, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (s *OutboxStore) ClaimOutboxEvents(ctx context.Context, now time.Time, leaseUntil time.Time, limit int) ([]models.OutboxEvent, error) {
+	// จองทีละรายการด้วย findOneAndUpdate เพื่อไม่ให้สอง relay ได้ event เดียวกัน
+	claimed := []models.OutboxEvent{}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "_id", Value: 1}}).
+		SetReturnDocument(options.After)
+	for len(claimed) < limit {
+		var ev models.OutboxEvent
+		err := s.Outbox.FindOneAndUpdate(ctx,
+			bson.M{"publishedAt": nil, "nextAttemptAt": bson.M{"$lte": now}},
+			bson.M{"$set": bson.M{"nextAttemptAt": leaseUntil}},
+			opts,
+		).Decode(&ev)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			break
+		}
+		if err != nil {
+			return claimed, err
+		}
+		claimed = append(claimed, ev)
+	}
+	return claimed, nil
+}
+
+func (s *OutboxStore) MarkOutboxPublished(ctx context.Context, id string, publishedAt time.Time) error {
+	return s.update(ctx, id, bson.M{"publishedAt": publishedAt, "lastError": ""})
+}
+
+func (s *OutboxStore) MarkOutboxFailed(ctx context.Context, id string, attempts int, nextAttemptAt time.Time, lastError string) error {
+	return s.update(ctx, id, bson.M{"attempts": attempts, "nextAttemptAt": nextAttemptAt, "lastError": lastError})
+}
+
+func (s *OutboxStore) PurgePublishedOutbox(ctx context.Context, before time.Time) (int64, error) {
+	res, err := s.Outbox.DeleteMany(ctx, bson.M{"publishedAt": bson.M{"$lt": before}})
+	if err != nil {
+		return 0, err
+	}
+	return res.DeletedCount, nil
+}
+
+func (s *OutboxStore) update(ctx context.Context, id string, set bson.M) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return store.ErrNotFound
+	}
+	res, err := s.Outbox.UpdateByID(ctx, objID, bson.M{"$set": set})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (s *OutboxStore) insertEvent(ctx context.Context, ev *models.OutboxEvent) error {
+	ev.ID = primitive.NewObjectID()
+	_, err := s.Outbox.InsertOne(ctx, ev)
+	return err
+}
+
+// รัน fn ใน transaction ถ้า MongoDB เป็น standalone (ไม่รองรับ transaction) จะไม่เขียนอะไรเลย
+// เว้นแต่เปิด AllowStandalone ไว้จึงเขียนตามลำดับแทน
+func (s *OutboxStore) withTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	session, err := s.Client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sc)
+	})
+	var serverErr mongo.ServerError
+	if errors.As(err, &serverErr) && serverErr.HasErrorCode(codeIllegalOperation) {
+		if !s.AllowStandalone {
+			return ErrTransactionsUnsupported
+		}
+		s.warnOnce.Do(func() {
+			log.Println("MongoDB does not support transactions (standalone server); outbox events are written without a transaction")
+		})
+		return fn(ctx)
+	}
+	return err
+}
@@ -0,0 +1,102 @@
+package mongostore
+
+import (
+	"context"
+	"time"
+
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/store"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (s *IdentityStore) CreatePasskey(ctx context.Context, p *models.Passkey) error {
+	p.ID = primitive.NewObjectID()
+	_, err := s.Passkeys.InsertOne(ctx, p)
+	if mongo.IsDuplicateKeyError(err) {
+		return &store.DuplicateError{Field: "credentialId"}
+	}
+	return err
+}
+
+func (s *IdentityStore) GetPasskeyByCredentialID(ctx context.Context, tenantID string, credentialID string) (*models.Passkey, error) {
+	var p models.Passkey
+	if err := s.Passkeys.FindOne(ctx, bson.M{"tenantId": tenantID, "credentialId": credentialID}).Decode(&p); err != nil {
+		return nil, mapError(err)
+	}
+	return &p, nil
+}
+
+func (s *IdentityStore) ListPasskeys(ctx context.Context, tenantID string, userID string) ([]models.Passkey, error) {
+	objID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return []models.Passkey{}, nil
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}})
+	cursor, err := s.Passkeys.Find(ctx, bson.M{"tenantId": tenantID, "userId": objID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	passkeys := []models.Passkey{}
+	if err := cursor.All(ctx, &passkeys); err != nil {
+		return nil, err
+	}
+	return passkeys, nil
+}
+
+func (s *IdentityStore) UpdatePasskeyUsage(ctx context.Context, id string, prevCount uint32, signCount uint32, backedUp bool, at time.Time) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return store.ErrNotFound
+	}
+	res, err := s.Passkeys.UpdateOne(ctx, bson.M{"_id": objID, "signCount": prevCount},
+		bson.M{"$set": bson.M{"signCount": signCount, "backedUp": backedUp, "lastUsedAt": at}})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (s *IdentityStore) RenamePasskey(ctx context.Context, tenantID string, userID string, id string, name string) (*models.Passkey, error) {
+	filter, ok := passkeyFilter(tenantID, userID, id)
+	if !ok {
+		return nil, store.ErrNotFound
+	}
+	var p models.Passkey
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	if err := s.Passkeys.FindOneAndUpdate(ctx, filter, bson.M{"$set": bson.M{"name": name}}, opts).Decode(&p); err != nil {
+		return nil, mapError(err)
+	}
+	return &p, nil
+}
+
+func (s *IdentityStore) DeletePasskey(ctx context.Context, tenantID string, userID string, id string) error {
+	filter, ok := passkeyFilter(tenantID, userID, id)
+	if !ok {
+		return store.ErrNotFound
+	}
+	res, err := s.Passkeys.DeleteOne(ctx, filter)
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+// passkey ของผู้ใช้ใน tenant (ok = false ถ้า ID ไม่ถูกต้อง)
+func passkeyFilter(tenantID string, userID string, id string) (bson.M, bool) {
+	userObjID, err1 := primitive.ObjectIDFromHex(userID)
+	objID, err2 := primitive.ObjectIDFromHex(id)
+	if err1 != nil || err2 != nil {
+		return nil, false
+	}
+	return bson.M{"_id": objID, "tenantId": tenantID, "userId": userObjID}, true
+}
@@ -0,0 +1,97 @@
+package mongostore
+
+import (
+	"context"
+	"strings"
+
+	"auth-microservice/internal/db"
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/store"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ServiceAccountStore เก็บ service account ใน collection service_accounts
+// (client secret และ public key เก็บเป็น array ใน document เดียวกัน)
+type ServiceAccountStore struct {
+	Collection *mongo.Collection
+}
+
+// สร้างอินสแตนซ์ของ ServiceAccountStore
+func NewServiceAccountStore(col *mongo.Collection) *ServiceAccountStore {
+	return &ServiceAccountStore{Collection: col}
+}
+
+func (s *ServiceAccountStore) CreateServiceAccount(ctx context.Context, a *models.ServiceAccount) error {
+	a.ID = primitive.NewObjectID()
+	_, err := s.Collection.InsertOne(ctx, a)
+	if mongo.IsDuplicateKeyError(err) {
+		if strings.Contains(err.Error(), "clientId_1") {
+			return &store.DuplicateError{Field: "clientId"}
+		}
+		return &store.DuplicateError{Field: "name"}
+	}
+	return err
+}
+
+func (s *ServiceAccountStore) GetServiceAccount(ctx context.Context, tenantID string, id string) (*models.ServiceAccount, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, store.ErrNotFound
+	}
+	return s.findOne(ctx, bson.M{"_id": objID, "tenantId": tenantID})
+}
+
+func (s *ServiceAccountStore) GetServiceAccountByClientID(ctx context.Context, clientID string) (*models.ServiceAccount, error) {
+	return s.findOne(ctx, bson.M{"clientId": clientID})
+}
+
+func (s *ServiceAccountStore) ListServiceAccounts(ctx context.Context, tenantID string) ([]models.ServiceAccount, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}}).SetCollation(db.CaseInsensitive)
+	cursor, err := s.Collection.Find(ctx, bson.M{"tenantId": tenantID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	accounts := []models.ServiceAccount{}
+	if err := cursor.All(ctx, &accounts); err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
+
+func (s *ServiceAccountStore) SaveServiceAccount(ctx context.Context, a *models.ServiceAccount) error {
+	res, err := s.Collection.ReplaceOne(ctx, bson.M{"_id": a.ID, "tenantId": a.TenantID}, a)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (s *ServiceAccountStore) DeleteServiceAccount(ctx context.Context, tenantID string, id string) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return store.ErrNotFound
+	}
+	res, err := s.Collection.DeleteOne(ctx, bson.M{"_id": objID, "tenantId": tenantID})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (s *ServiceAccountStore) findOne(ctx context.Context, filter bson.M) (*models.ServiceAccount, error) {
+	var a models.ServiceAccount
+	if err := s.Collection.FindOne(ctx, filter).Decode(&a); err != nil {
+		return nil, mapError(err)
+	}
+	return &a, nil
+}
@@ -0,0 +1,62 @@
+package mongostore
+
+import (
+	"context"
+	"errors"
+
+	models "auth-microservice/internal/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ID ของ document ใน collection settings
+const (
+	profileSchemaID = "profile_schema"    // schema ของ metadata
+	oidcKeysID      = "oidc_signing_keys" // signing key ของ OpenID Connect
+)
+
+// SettingsStore เก็บการตั้งค่าของระบบใน collection settings (หนึ่ง document ต่อการตั้งค่า)
+type SettingsStore struct {
+	Collection *mongo.Collection
+}
+
+// สร้างอินสแตนซ์ของ SettingsStore
+func NewSettingsStore(col *mongo.Collection) *SettingsStore {
+	return &SettingsStore{Collection: col}
+}
+
+func (s *SettingsStore) GetProfileSchema(ctx context.Context) ([]models.ProfileAttribute, error) {
+	var schema models.ProfileSchema
+	err := s.Collection.FindOne(ctx, bson.M{"_id": profileSchemaID}).Decode(&schema)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return schema.Attributes, nil
+}
+
+func (s *SettingsStore) SaveProfileSchema(ctx context.Context, schema models.ProfileSchema) error {
+	// บันทึก schema (สร้างใหม่ถ้ายังไม่มี)
+	schema.ID = profileSchemaID
+	_, err := s.Collection.ReplaceOne(ctx, bson.M{"_id": profileSchemaID}, schema, options.Replace().SetUpsert(true))
+	return err
+}
+
+func (s *SettingsStore) GetOIDCKeys(ctx context.Context) (*models.OIDCKeySet, error) {
+	var keys models.OIDCKeySet
+	if err := s.Collection.FindOne(ctx, bson.M{"_id": oidcKeysID}).Decode(&keys); err != nil {
+		return nil, mapError(err)
+	}
+	return &keys, nil
+}
+
+func (s *SettingsStore) InitOIDCKeys(ctx context.Context, keys models.OIDCKeySet) error {
+	// $setOnInsert ไม่แก้ document เดิมถ้ามี key อยู่แล้ว
+	update := bson.M{"$setOnInsert": bson.M{"keys": keys.Keys, "updatedAt": keys.UpdatedAt}}
+	_, err := s.Collection.UpdateOne(ctx, bson.M{"_id": oidcKeysID}, update, options.Update().SetUpsert(true))
+	return err
+}
@@ -0,0 +1,55 @@
+package mongostore
+
+import (
+	"context"
+
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/store"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// TenantStore เก็บ tenant ใน collection tenants (_id คือ ID ของ tenant)
+type TenantStore struct {
+	Collection *mongo.Collection
+}
+
+// สร้างอินสแตนซ์ของ TenantStore
+func NewTenantStore(col *mongo.Collection) *TenantStore {
+	return &TenantStore{Collection: col}
+}
+
+func (s *TenantStore) CreateTenant(ctx context.Context, t *models.Tenant) error {
+	_, err := s.Collection.InsertOne(ctx, t)
+	if mongo.IsDuplicateKeyError(err) {
+		return &store.DuplicateError{Field: "id"}
+	}
+	return err
+}
+
+func (s *TenantStore) GetTenant(ctx context.Context, id string) (*models.Tenant, error) {
+	var t models.Tenant
+	if err := s.Collection.FindOne(ctx, bson.M{"_id": id}).Decode(&t); err != nil {
+		return nil, mapError(err)
+	}
+	return &t, nil
+}
+
+func (s *TenantStore) ListTenants(ctx context.Context) ([]models.Tenant, error) {
+	cursor, err := s.Collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	var tenants []models.Tenant
+	if err := cursor.All(ctx, &tenants); err != nil {
+		return nil, err
+	}
+	return tenants, nil
+}
+
+func (s *TenantStore) SaveTenant(ctx context.Context, t *models.Tenant) error {
+	_, err := s.Collection.ReplaceOne(ctx, bson.M{"_id": t.ID}, t, options.Replace().SetUpsert(true))
+	return err
+}
@@ -0,0 +1,361 @@
+package mongostore
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"auth-microservice/internal/db"
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/search"
+	"auth-microservice/internal/store"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// การนับแบบประมาณของ CountUsers
+const (
+	estimateCountLimit = 10000           // นับจริงสูงสุดเท่านี้ เกินกว่านี้คืน limit เป็นค่าขั้นต่ำ
+	estimateCountTime  = 2 * time.Second // เวลาที่ให้ MongoDB นับ (maxTimeMS)
+)
+
+// UserStore เก็บข้อมูลผู้ใช้ใน collection users
+type UserStore struct {
+	Collection *mongo.Collection
+}
+
+// สร้างอินสแตนซ์ของ UserStore
+func NewUserStore(col *mongo.Collection) *UserStore {
+	return &UserStore{Collection: col}
+}
+
+// เงื่อนไขผู้ใช้ที่ยังไม่ถูกลบ
+var notDeleted = bson.M{"$ne": true}
+
+func (s *UserStore) CreateUser(ctx context.Context, u *models.User) error {
+	u.ID = primitive.NewObjectID()
+	if u.PasswordHistory == nil {
+		// ต้องเป็น array เสมอ เพื่อให้ $push ใน UpdatePassword ทำงานได้
+		u.PasswordHistory = []string{}
+	}
+	_, err := s.Collection.InsertOne(ctx, u)
+	return mapError(err)
+}
+
+func (s *UserStore) GetUserByID(ctx context.Context, id string, includeDeleted bool) (*models.User, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, store.ErrNotFound
+	}
+	filter := bson.M{"_id": objID}
+	if !includeDeleted {
+		filter["deleted"] = notDeleted
+	}
+	return s.findOne(ctx, filter)
+}
+
+func (s *UserStore) GetUserByEmail(ctx context.Context, tenantID string, email string) (*models.User, error) {
+	return s.findOne(ctx, bson.M{"tenantId": tenantID, "email": email, "deleted": notDeleted})
+}
+
+func (s *UserStore) EmailTaken(ctx context.Context, tenantID string, email string) (bool, error) {
+	return s.exists(ctx, bson.M{"tenantId": tenantID, "email": email})
+}
+
+func (s *UserStore) UsernameTaken(ctx context.Context, tenantID string, username string) (bool, error) {
+	return s.exists(ctx, bson.M{"tenantId": tenantID, "username": username})
+}
+
+func (s *UserStore) UpdateProfile(ctx context.Context, id string, patch store.ProfilePatch, updatedAt time.Time) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return store.ErrNotFound
+	}
+
+	set := bson.M{"updatedAt": updatedAt}
+	unset := bson.M{}
+	// กำหนดค่าให้ field ถ้าค่าว่างจะลบ field นั้นออก
+	setOrUnset := func(field string, value *string) {
+		switch {
+		case value == nil:
+		case *value == "":
+			unset[field] = ""
+		default:
+			set[field] = *value
+		}
+	}
+	if patch.Username != nil {
+		set["username"] = *patch.Username
+	}
+	if patch.Role != nil {
+		set["role"] = *patch.Role
+	}
+	if patch.PasskeyRequired != nil {
+		set["passkeyRequired"] = *patch.PasskeyRequired
+	}
+	setOrUnset("displayName", patch.DisplayName)
+	setOrUnset("avatarUrl", patch.AvatarURL)
+	setOrUnset("locale", patch.Locale)
+	setOrUnset("timezone", patch.Timezone)
+	setOrUnset("phone", patch.Phone)
+
+	if patch.ReplaceMetadata {
+		if len(patch.Metadata) == 0 {
+			unset["metadata"] = ""
+		} else {
+			set["metadata"] = patch.Metadata
+		}
+	} else {
+		// แก้ไขเฉพาะ key ที่ระบุ โดยไม่กระทบ key อื่น
+		for key, value := range patch.Metadata {
+			value := value
+			setOrUnset("metadata."+key, &value)
+		}
+	}
+
+	update := bson.M{"$set": set}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+	result, err := s.Collection.UpdateOne(ctx, bson.M{"_id": objID}, update)
+	if err != nil {
+		return mapError(err)
+	}
+	if result.MatchedCount == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (s *UserStore) UpdatePassword(ctx context.Context, id string, oldHash string, newHash string, historyLimit int, changedAt time.Time) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return store.ErrNotFound
+	}
+	// เก็บ hash เดิมไว้ในประวัติ (จำกัดจำนวนด้วย $slice)
+	update := bson.M{
+		"$set": bson.M{
+			"password":          newHash,
+			"passwordChangedAt": changedAt,
+			"updatedAt":         changedAt,
+		},
+		"$push": bson.M{
+			"passwordHistory": bson.M{
+				"$each":  []string{oldHash},
+				"$slice": -historyLimit,
+			},
+		},
+	}
+	result, err := s.Collection.UpdateOne(ctx, bson.M{"_id": objID, "deleted": notDeleted}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (s *UserStore) UpdateEmail(ctx context.Context, tenantID string, from string, to string, updatedAt time.Time) (*models.User, error) {
+	update := bson.M{
+		"$set": bson.M{
+			"email":         to,
+			"emailVerified": true,
+			"updatedAt":     updatedAt,
+		},
+	}
+	return s.findOneAndUpdate(ctx, bson.M{"tenantId": tenantID, "email": from, "deleted": notDeleted}, update)
+}
+
+func (s *UserStore) SoftDeleteUser(ctx context.Context, id string, deletedAt time.Time) (*models.User, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, store.ErrNotFound
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"deleted":   true,
+			"deletedAt": deletedAt,
+		},
+	}
+	return s.findOneAndUpdate(ctx, bson.M{"_id": objID, "deleted": notDeleted}, update)
+}
+
+func (s *UserStore) RestoreUser(ctx context.Context, id string, updatedAt time.Time) (*models.User, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, store.ErrNotFound
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"deleted":   false,
+			"deletedAt": nil,
+			"updatedAt": updatedAt,
+		},
+	}
+	return s.findOneAndUpdate(ctx, bson.M{"_id": objID, "deleted": true}, update)
+}
+
+func (s *UserStore) DeleteUser(ctx context.Context, id string) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return store.ErrNotFound
+	}
+	result, err := s.Collection.DeleteOne(ctx, bson.M{"_id": objID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (s *UserStore) ListDeletedBefore(ctx context.Context, before time.Time) ([]string, error) {
+	filter := bson.M{
+		"deleted":   true,
+		"deletedAt": bson.M{"$lt": before},
+	}
+	cursor, err := s.Collection.Find(ctx, filter, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var ids []string
+	for cursor.Next(ctx) {
+		var u struct {
+			ID primitive.ObjectID `bson:"_id"`
+		}
+		if err := cursor.Decode(&u); err != nil {
+			continue
+		}
+		ids = append(ids, u.ID.Hex())
+	}
+	return ids, cursor.Err()
+}
+
+func (s *UserStore) ListUsers(ctx context.Context, q store.UserListQuery) ([]models.User, error) {
+	filter, err := userFilter(q.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// การเรียงลำดับ (ลำดับคงที่ด้วย _id ทำให้หน้าไม่ซ้อนหรือข้ามกัน)
+	dir := 1
+	if q.Order.Desc {
+		dir = -1
+	}
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: q.Order.Field, Value: dir}, {Key: "_id", Value: dir}}).
+		SetLimit(q.Limit).
+		SetCollation(db.CaseInsensitive)
+
+	query := filter
+	if q.After != nil {
+		// cursor-based pagination: ต่อจากรายการสุดท้ายของหน้าก่อน
+		id, err := primitive.ObjectIDFromHex(q.After.ID)
+		if err != nil {
+			return nil, store.ErrNotFound
+		}
+		op := "$gt"
+		if q.Order.Desc {
+			op = "$lt"
+		}
+		after := bson.M{"$or": bson.A{
+			bson.M{q.Order.Field: bson.M{op: q.After.Value}},
+			bson.M{q.Order.Field: q.After.Value, "_id": bson.M{op: id}},
+		}}
+		query = bson.M{"$and": bson.A{filter, after}}
+	} else if q.Skip > 0 {
+		findOptions.SetSkip(q.Skip)
+	}
+
+	cursor, err := s.Collection.Find(ctx, query, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var users []models.User
+	for cursor.Next(ctx) {
+		var u models.User
+		if err := cursor.Decode(&u); err != nil {
+			continue
+		}
+		users = append(users, u)
+	}
+	return users, cursor.Err()
+}
+
+// ถ้า allowEstimate จะนับตามเงื่อนไขเดียวกันแต่หยุดที่ estimateCountLimit และภายใน estimateCountTime
+// (ค่าประมาณคือค่าขั้นต่ำ: นับถึง limit แล้วหยุด หรือคืน 0 ถ้านับไม่ทันเวลา)
+func (s *UserStore) CountUsers(ctx context.Context, q search.UserQuery, allowEstimate bool) (int64, bool, error) {
+	filter, err := userFilter(q)
+	if err != nil {
+		return 0, false, err
+	}
+	opts := options.Count().SetCollation(db.CaseInsensitive)
+	if !allowEstimate {
+		total, err := s.Collection.CountDocuments(ctx, filter, opts)
+		return total, false, err
+	}
+
+	opts.SetLimit(estimateCountLimit).SetMaxTime(estimateCountTime)
+	total, err := s.Collection.CountDocuments(ctx, filter, opts)
+	if mongo.IsTimeout(err) {
+		return 0, true, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return total, total >= estimateCountLimit, nil
+}
+
+func (s *UserStore) findOne(ctx context.Context, filter bson.M) (*models.User, error) {
+	var u models.User
+	if err := s.Collection.FindOne(ctx, filter).Decode(&u); err != nil {
+		return nil, mapError(err)
+	}
+	return &u, nil
+}
+
+// อัปเดตแล้วคืนข้อมูลผู้ใช้ก่อนอัปเดต
+func (s *UserStore) findOneAndUpdate(ctx context.Context, filter bson.M, update bson.M) (*models.User, error) {
+	var u models.User
+	if err := s.Collection.FindOneAndUpdate(ctx, filter, update).Decode(&u); err != nil {
+		return nil, mapError(err)
+	}
+	return &u, nil
+}
+
+// ตรวจสอบแบบไม่สนตัวพิมพ์ และรวมผู้ใช้ที่ถูกลบ (ตรงกับ unique index)
+func (s *UserStore) exists(ctx context.Context, filter bson.M) (bool, error) {
+	count, err := s.Collection.CountDocuments(ctx, filter, options.Count().SetCollation(db.CaseInsensitive).SetLimit(1))
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// แปลง error ของ MongoDB เป็น error ของ store
+func mapError(err error) error {
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return store.ErrNotFound
+	}
+	if !mongo.IsDuplicateKeyError(err) {
+		return err
+	}
+	// ชื่อ index ของ unique constraint อยู่ในข้อความ error (เช่น tenantId_1_email_1)
+	switch msg := err.Error(); {
+	case strings.Contains(msg, "_email_1"):
+		return &store.DuplicateError{Field: "email"}
+	case strings.Contains(msg, "_username_1"):
+		return &store.DuplicateError{Field: "username"}
+	}
+	return &store.DuplicateError{}
+}
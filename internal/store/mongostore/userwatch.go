@@ -0,0 +1,186 @@
+package mongostore
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"time"
+
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/store"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// รหัส error ของ MongoDB ที่เกี่ยวกับ change stream
+const (
+	codeChangeStreamUnsupported = 40573 // $changeStream ใช้ได้เฉพาะ replica set หรือ sharded cluster
+	codeChangeStreamFatal       = 280   // resume token ใช้ไม่ได้
+	codeChangeStreamHistoryLost = 286   // oplog ไม่มีประวัติย้อนไปถึง resume token แล้ว
+	codeUnknownField            = 40415 // MongoDB ก่อน 6.0 ไม่รู้จัก option fullDocumentBeforeChange
+)
+
+// UserWatcher ติดตามการเปลี่ยนแปลงของ collection users ด้วย change stream
+// hash รหัสผ่านถูกตัดออกใน pipeline ฝั่ง MongoDB จึงไม่ถูกส่งมาถึง service
+type UserWatcher struct {
+	Users *mongo.Collection
+}
+
+// สร้างอินสแตนซ์ของ UserWatcher
+func NewUserWatcher(users *mongo.Collection) *UserWatcher {
+	return &UserWatcher{Users: users}
+}
+
+// change event หลังผ่าน pipeline ของ WatchUsers
+type userChangeEvent struct {
+	ID            bson.Raw            `bson:"_id"` // resume token
+	OperationType string              `bson:"operationType"`
+	ClusterTime   primitive.Timestamp `bson:"clusterTime"`
+	WallTime      time.Time           `bson:"wallTime"` // มีตั้งแต่ MongoDB 6.0
+	DocumentKey   struct {
+		ID primitive.ObjectID `bson:"_id"`
+	} `bson:"documentKey"`
+	FullDocument   *models.User `bson:"fullDocument"`
+	BeforeTenantID string       `bson:"beforeTenantId"`
+	ChangedFields  []string     `bson:"changedFields"`
+	DeletedChange  *bool        `bson:"deletedChange"` // ค่าใหม่ของ field deleted ถ้าถูกแก้ในการ update นี้
+}
+
+func (w *UserWatcher) WatchUsers(ctx context.Context, q store.UserWatchQuery, fn func(store.UserChange) error) error {
+	// การลบถาวรไม่มี fullDocument จะรู้ tenant ได้จาก pre-image (ต้องเปิด changeStreamPreAndPostImages ของ collection users)
+	match := bson.M{
+		"operationType": bson.M{"$in": bson.A{"insert", "update", "replace", "delete"}},
+		"$or": bson.A{
+			bson.M{"fullDocument.tenantId": q.TenantID},
+			bson.M{"fullDocumentBeforeChange.tenantId": q.TenantID},
+		},
+	}
+	if len(q.UserIDs) > 0 {
+		ids := bson.A{}
+		for _, id := range q.UserIDs {
+			if objID, err := primitive.ObjectIDFromHex(id); err == nil {
+				ids = append(ids, objID)
+			}
+		}
+		match["documentKey._id"] = bson.M{"$in": ids}
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		// เก็บเฉพาะชื่อ field ที่เปลี่ยน แล้วตัดค่าที่เปลี่ยนและ field ที่เป็นความลับออกก่อนส่งออกจากฐานข้อมูล
+		{{Key: "$addFields", Value: bson.M{
+			"changedFields": bson.M{"$concatArrays": bson.A{
+				bson.M{"$map": bson.M{
+					"input": bson.M{"$objectToArray": bson.M{"$ifNull": bson.A{"$updateDescription.updatedFields", bson.M{}}}},
+					"as":    "field",
+					"in":    "$$field.k",
+				}},
+				bson.M{"$ifNull": bson.A{"$updateDescription.removedFields", bson.A{}}},
+			}},
+			"deletedChange":  "$updateDescription.updatedFields.deleted",
+			"beforeTenantId": "$fullDocumentBeforeChange.tenantId",
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"updateDescription":            0,
+			"fullDocumentBeforeChange":     0,
+			"fullDocument.password":        0,
+			"fullDocument.passwordHistory": 0,
+		}}},
+	}
+
+	opts := options.ChangeStream().
+		SetFullDocument(options.UpdateLookup).
+		SetFullDocumentBeforeChange(options.WhenAvailable)
+	if q.ResumeToken != "" {
+		token, err := decodeResumeToken(q.ResumeToken)
+		if err != nil {
+			return store.ErrInvalidResumeToken
+		}
+		opts.SetStartAfter(token)
+	}
+
+	stream, err := w.Users.Watch(ctx, pipeline, opts)
+	var serverErr mongo.ServerError
+	if errors.As(err, &serverErr) && serverErr.HasErrorCode(codeUnknownField) {
+		// ไม่มี pre-image จึงไม่รู้ tenant ของผู้ใช้ที่ถูกลบถาวร (ไม่ส่ง purged)
+		opts.FullDocumentBeforeChange = nil
+		stream, err = w.Users.Watch(ctx, pipeline, opts)
+	}
+	if err != nil {
+		return watchError(err)
+	}
+	defer stream.Close(context.Background())
+
+	for stream.Next(ctx) {
+		var ev userChangeEvent
+		if err := stream.Decode(&ev); err != nil {
+			return err
+		}
+		if err := fn(toUserChange(ev)); err != nil {
+			return err
+		}
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return watchError(stream.Err())
+}
+
+func toUserChange(ev userChangeEvent) store.UserChange {
+	change := store.UserChange{
+		ResumeToken: base64.RawURLEncoding.EncodeToString(ev.ID),
+		UserID:      ev.DocumentKey.ID.Hex(),
+		TenantID:    ev.BeforeTenantID,
+		OccurredAt:  ev.WallTime,
+		User:        ev.FullDocument,
+	}
+	if change.OccurredAt.IsZero() {
+		change.OccurredAt = time.Unix(int64(ev.ClusterTime.T), 0)
+	}
+	if ev.FullDocument != nil {
+		change.TenantID = ev.FullDocument.TenantID
+	}
+
+	switch {
+	case ev.OperationType == "insert":
+		change.Type = store.UserCreated
+	case ev.OperationType == "delete":
+		change.Type = store.UserPurged
+	case ev.DeletedChange != nil && *ev.DeletedChange:
+		change.Type = store.UserDeleted
+	case ev.DeletedChange != nil:
+		change.Type = store.UserRestored
+	default:
+		change.Type = store.UserUpdated
+		change.UpdatedFields = ev.ChangedFields
+	}
+	return change
+}
+
+func decodeResumeToken(token string) (bson.Raw, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, err
+	}
+	raw := bson.Raw(data)
+	if err := raw.Validate(); err != nil {
+		return nil, err
+	}
+	return raw, nil
+}
+
+// แปลง error ของ change stream เป็น error ของ store
+func watchError(err error) error {
+	var serverErr mongo.ServerError
+	if errors.As(err, &serverErr) {
+		switch {
+		case serverErr.HasErrorCode(codeChangeStreamUnsupported):
+			return store.ErrWatchUnsupported
+		case serverErr.HasErrorCode(codeChangeStreamFatal), serverErr.HasErrorCode(codeChangeStreamHistoryLost):
+			return store.ErrInvalidResumeToken
+		}
+	}
+	return err
+}
@@ -0,0 +1,171 @@
+package mongostore
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/store"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// WebhookStore เก็บ webhook ใน collection webhooks และคิวการส่งใน collection webhook_deliveries
+type WebhookStore struct {
+	Webhooks   *mongo.Collection
+	Deliveries *mongo.Collection
+}
+
+// สร้างอินสแตนซ์ของ WebhookStore
+func NewWebhookStore(webhooks *mongo.Collection, deliveries *mongo.Collection) *WebhookStore {
+	return &WebhookStore{Webhooks: webhooks, Deliveries: deliveries}
+}
+
+func (s *WebhookStore) CreateWebhook(ctx context.Context, w *models.Webhook) error {
+	w.ID = primitive.NewObjectID()
+	_, err := s.Webhooks.InsertOne(ctx, w)
+	return err
+}
+
+func (s *WebhookStore) GetWebhook(ctx context.Context, tenantID string, id string) (*models.Webhook, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, store.ErrNotFound
+	}
+	var w models.Webhook
+	if err := s.Webhooks.FindOne(ctx, bson.M{"_id": objID, "tenantId": tenantID}).Decode(&w); err != nil {
+		return nil, mapError(err)
+	}
+	return &w, nil
+}
+
+func (s *WebhookStore) ListWebhooks(ctx context.Context, tenantID string) ([]models.Webhook, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := s.Webhooks.Find(ctx, bson.M{"tenantId": tenantID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	webhooks := []models.Webhook{}
+	if err := cursor.All(ctx, &webhooks); err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+func (s *WebhookStore) DeleteWebhook(ctx context.Context, tenantID string, id string) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return store.ErrNotFound
+	}
+	res, err := s.Webhooks.DeleteOne(ctx, bson.M{"_id": objID, "tenantId": tenantID})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return store.ErrNotFound
+	}
+	_, err = s.Deliveries.DeleteMany(ctx, bson.M{"webhookId": objID})
+	return err
+}
+
+func (s *WebhookStore) EnqueueDeliveries(ctx context.Context, deliveries []*models.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	docs := make([]interface{}, 0, len(deliveries))
+	for _, d := range deliveries {
+		d.ID = primitive.NewObjectID()
+		docs = append(docs, d)
+	}
+	_, err := s.Deliveries.InsertMany(ctx, docs)
+	return err
+}
+
+func (s *WebhookStore) ClaimDueDeliveries(ctx context.Context, now time.Time, leaseUntil time.Time, limit int) ([]models.WebhookDelivery, error) {
+	// จองทีละรายการด้วย findOneAndUpdate เพื่อไม่ให้สอง instance ได้รายการเดียวกัน
+	claimed := []models.WebhookDelivery{}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "nextAttemptAt", Value: 1}}).
+		SetReturnDocument(options.After)
+	for len(claimed) < limit {
+		var d models.WebhookDelivery
+		err := s.Deliveries.FindOneAndUpdate(ctx,
+			bson.M{"status": models.DeliveryPending, "nextAttemptAt": bson.M{"$lte": now}},
+			bson.M{"$set": bson.M{"nextAttemptAt": leaseUntil}},
+			opts,
+		).Decode(&d)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			break
+		}
+		if err != nil {
+			return claimed, err
+		}
+		claimed = append(claimed, d)
+	}
+	return claimed, nil
+}
+
+func (s *WebhookStore) SaveDeliveryAttempt(ctx context.Context, d *models.WebhookDelivery) error {
+	res, err := s.Deliveries.UpdateByID(ctx, d.ID, bson.M{"$set": bson.M{
+		"status":         d.Status,
+		"attempts":       d.Attempts,
+		"nextAttemptAt":  d.NextAttemptAt,
+		"lastError":      d.LastError,
+		"responseStatus": d.ResponseStatus,
+		"deliveredAt":    d.DeliveredAt,
+	}})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (s *WebhookStore) GetDelivery(ctx context.Context, tenantID string, id string) (*models.WebhookDelivery, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, store.ErrNotFound
+	}
+	var d models.WebhookDelivery
+	if err := s.Deliveries.FindOne(ctx, bson.M{"_id": objID, "tenantId": tenantID}).Decode(&d); err != nil {
+		return nil, mapError(err)
+	}
+	return &d, nil
+}
+
+func (s *WebhookStore) ListDeliveries(ctx context.Context, q store.DeliveryListQuery) ([]models.WebhookDelivery, error) {
+	filter := bson.M{"tenantId": q.TenantID}
+	if q.WebhookID != "" {
+		objID, err := primitive.ObjectIDFromHex(q.WebhookID)
+		if err != nil {
+			return []models.WebhookDelivery{}, nil
+		}
+		filter["webhookId"] = objID
+	}
+	if q.Status != "" {
+		filter["status"] = q.Status
+	}
+	if q.BeforeID != "" {
+		objID, err := primitive.ObjectIDFromHex(q.BeforeID)
+		if err != nil {
+			return []models.WebhookDelivery{}, nil
+		}
+		filter["_id"] = bson.M{"$lt": objID}
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: -1}}).SetLimit(q.Limit)
+	cursor, err := s.Deliveries.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	deliveries := []models.WebhookDelivery{}
+	if err := cursor.All(ctx, &deliveries); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
@@ -0,0 +1,57 @@
+package redisstore
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"auth-microservice/internal/store"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// KeyValueStore เก็บข้อมูลชั่วคราวใน Redis โดยใช้ TTL ของ Redis
+type KeyValueStore struct {
+	Redis *redis.Client
+}
+
+// สร้างอินสแตนซ์ของ KeyValueStore
+func NewKeyValueStore(rdb *redis.Client) *KeyValueStore {
+	return &KeyValueStore{Redis: rdb}
+}
+
+func (s *KeyValueStore) Incr(ctx context.Context, key string, window time.Duration) (int64, error) {
+	count, err := s.Redis.Incr(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+	// เริ่มจับเวลาตั้งแต่ครั้งแรก
+	if count == 1 {
+		s.Redis.Expire(ctx, key, window)
+	}
+	return count, nil
+}
+
+func (s *KeyValueStore) Get(ctx context.Context, key string) (string, error) {
+	value, err := s.Redis.Get(ctx, key).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", store.ErrNotFound
+	}
+	return value, err
+}
+
+func (s *KeyValueStore) Set(ctx context.Context, key string, value string, ttl time.Duration) error {
+	return s.Redis.Set(ctx, key, value, ttl).Err()
+}
+
+func (s *KeyValueStore) Delete(ctx context.Context, key string) error {
+	return s.Redis.Del(ctx, key).Err()
+}
+
+func (s *KeyValueStore) Rename(ctx context.Context, from string, to string) error {
+	exists, err := s.Redis.Exists(ctx, from).Result()
+	if err != nil || exists == 0 {
+		return err
+	}
+	return s.Redis.Rename(ctx, from, to).Err()
+}
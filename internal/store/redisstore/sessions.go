@@ -0,0 +1,47 @@
+// Package redisstore เก็บ session และข้อมูลชั่วคราวของผู้ใช้ใน Redis
+package redisstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	models "auth-microservice/internal/model"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// SessionStore เก็บ active token ของผู้ใช้ไว้ที่ key "active_token:<tenant>:<email>"
+// tenant default ใช้ key เดิม "active_token:<email>" เพื่อให้ session ที่มีอยู่ยังใช้ได้
+type SessionStore struct {
+	Redis *redis.Client
+}
+
+// สร้างอินสแตนซ์ของ SessionStore
+func NewSessionStore(rdb *redis.Client) *SessionStore {
+	return &SessionStore{Redis: rdb}
+}
+
+func activeTokenKey(tenantID string, email string) string {
+	if tenantID == models.DefaultTenantID {
+		return fmt.Sprintf("active_token:%s", email)
+	}
+	return fmt.Sprintf("active_token:%s:%s", tenantID, email)
+}
+
+func (s *SessionStore) GetActiveToken(ctx context.Context, tenantID string, email string) (string, error) {
+	token, err := s.Redis.Get(ctx, activeTokenKey(tenantID, email)).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", nil
+	}
+	return token, err
+}
+
+func (s *SessionStore) SetActiveToken(ctx context.Context, tenantID string, email string, token string, ttl time.Duration) error {
+	return s.Redis.Set(ctx, activeTokenKey(tenantID, email), token, ttl).Err()
+}
+
+func (s *SessionStore) DeleteActiveToken(ctx context.Context, tenantID string, email string) error {
+	return s.Redis.Del(ctx, activeTokenKey(tenantID, email)).Err()
+}
@@ -0,0 +1,106 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/store"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ======== APIKeyStore ========
+
+const apiKeyColumns = `id, tenant_id, user_id, name, prefix, hash, scopes, rate_limit, expires_at, last_used_at, revoked_at, created_at`
+
+func (s *Store) CreateAPIKey(ctx context.Context, k *models.APIKey) error {
+	k.ID = primitive.NewObjectID()
+	_, err := s.DB.ExecContext(ctx, s.rebind(`INSERT INTO api_keys (`+apiKeyColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`),
+		k.ID.Hex(), k.TenantID, k.UserID.Hex(), k.Name, k.Prefix, k.Hash, marshalJSON(k.Scopes), k.RateLimit,
+		k.ExpiresAt.UTC(), nullTime(k.LastUsedAt), nullTime(k.RevokedAt), k.CreatedAt.UTC())
+	return err
+}
+
+func (s *Store) GetAPIKey(ctx context.Context, tenantID string, id string) (*models.APIKey, error) {
+	return s.getAPIKey(ctx, `id = ? AND tenant_id = ?`, id, tenantID)
+}
+
+func (s *Store) GetAPIKeyByHash(ctx context.Context, hash string) (*models.APIKey, error) {
+	return s.getAPIKey(ctx, `hash = ?`, hash)
+}
+
+func (s *Store) ListAPIKeys(ctx context.Context, tenantID string, userID string) ([]models.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE tenant_id = ?`
+	args := []interface{}{tenantID}
+	if userID != "" {
+		query += ` AND user_id = ?`
+		args = append(args, userID)
+	}
+	rows, err := s.DB.QueryContext(ctx, s.rebind(query+` ORDER BY created_at DESC, id DESC`), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []models.APIKey{}
+	for rows.Next() {
+		k, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, *k)
+	}
+	return keys, rows.Err()
+}
+
+func (s *Store) RevokeAPIKey(ctx context.Context, tenantID string, id string, revokedAt time.Time) error {
+	res, err := s.DB.ExecContext(ctx, s.rebind(`UPDATE api_keys SET revoked_at = ? WHERE id = ? AND tenant_id = ? AND revoked_at IS NULL`),
+		revokedAt.UTC(), id, tenantID)
+	return rowsAffected(res, err)
+}
+
+func (s *Store) TouchAPIKey(ctx context.Context, id string, usedAt time.Time) error {
+	_, err := s.DB.ExecContext(ctx, s.rebind(`UPDATE api_keys SET last_used_at = ? WHERE id = ?`), usedAt.UTC(), id)
+	return err
+}
+
+func (s *Store) getAPIKey(ctx context.Context, where string, args ...interface{}) (*models.APIKey, error) {
+	k, err := scanAPIKey(s.DB.QueryRowContext(ctx, s.rebind(`SELECT `+apiKeyColumns+` FROM api_keys WHERE `+where), args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, store.ErrNotFound
+	}
+	return k, err
+}
+
+func scanAPIKey(row rowScanner) (*models.APIKey, error) {
+	var (
+		k                   models.APIKey
+		id, userID, scopes  string
+		lastUsed, revokedAt sql.NullTime
+	)
+	if err := row.Scan(&id, &k.TenantID, &userID, &k.Name, &k.Prefix, &k.Hash, &scopes, &k.RateLimit,
+		&k.ExpiresAt, &lastUsed, &revokedAt, &k.CreatedAt); err != nil {
+		return nil, err
+	}
+	var err error
+	if k.ID, err = primitive.ObjectIDFromHex(id); err != nil {
+		return nil, err
+	}
+	if k.UserID, err = primitive.ObjectIDFromHex(userID); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(scopes), &k.Scopes); err != nil {
+		return nil, err
+	}
+	if lastUsed.Valid {
+		k.LastUsedAt = &lastUsed.Time
+	}
+	if revokedAt.Valid {
+		k.RevokedAt = &revokedAt.Time
+	}
+	return &k, nil
+}
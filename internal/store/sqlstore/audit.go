@@ -0,0 +1,121 @@
+package sqlstore
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/store"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ======== AuditStore ========
+
+func (s *Store) InsertEvent(ctx context.Context, ev models.AuditEvent) error {
+	if ev.ID.IsZero() {
+		ev.ID = primitive.NewObjectID()
+	}
+	details := ev.Details
+	if details == nil {
+		details = map[string]interface{}{}
+	}
+	_, err := s.DB.ExecContext(ctx, s.rebind(`INSERT INTO audit_logs (id, tenant_id, action, actor_email, subject_id, subject_email, details, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`),
+		ev.ID.Hex(), ev.TenantID, ev.Action, ev.ActorEmail, ev.SubjectID, ev.SubjectEmail, marshalJSON(details), ev.CreatedAt.UTC())
+	return err
+}
+
+func (s *Store) FindBySubject(ctx context.Context, tenantID string, subjectID string, email string) ([]models.AuditEvent, error) {
+	rows, err := s.DB.QueryContext(ctx, s.rebind(`SELECT id, tenant_id, action, actor_email, subject_id, subject_email, details, created_at
+		FROM audit_logs WHERE subject_id = ? OR (tenant_id = ? AND subject_email = ?) ORDER BY created_at`), subjectID, tenantID, email)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []models.AuditEvent
+	for rows.Next() {
+		ev, err := scanAuditEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, *ev)
+	}
+	return events, rows.Err()
+}
+
+func (s *Store) ListEvents(ctx context.Context, q store.AuditListQuery) ([]models.AuditEvent, error) {
+	where := []string{"tenant_id = ?", "id > ?"}
+	args := []interface{}{q.TenantID, q.AfterID}
+	if q.Action != "" {
+		where = append(where, "action = ?")
+		args = append(args, q.Action)
+	}
+	if q.SubjectID != "" {
+		where = append(where, "subject_id = ?")
+		args = append(args, q.SubjectID)
+	}
+	direction := ""
+	if q.Latest {
+		direction = " DESC"
+	}
+	args = append(args, q.Limit)
+
+	rows, err := s.DB.QueryContext(ctx, s.rebind(`SELECT id, tenant_id, action, actor_email, subject_id, subject_email, details, created_at
+		FROM audit_logs WHERE `+strings.Join(where, " AND ")+` ORDER BY id`+direction+` LIMIT ?`), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []models.AuditEvent{}
+	for rows.Next() {
+		ev, err := scanAuditEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, *ev)
+	}
+	if q.Latest {
+		for i, j := 0, len(events)-1; i < j; i, j = i+1, j-1 {
+			events[i], events[j] = events[j], events[i]
+		}
+	}
+	return events, rows.Err()
+}
+
+func scanAuditEvent(row rowScanner) (*models.AuditEvent, error) {
+	var ev models.AuditEvent
+	var id, details string
+	if err := row.Scan(&id, &ev.TenantID, &ev.Action, &ev.ActorEmail, &ev.SubjectID, &ev.SubjectEmail, &details, &ev.CreatedAt); err != nil {
+		return nil, err
+	}
+	var err error
+	if ev.ID, err = primitive.ObjectIDFromHex(id); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(details), &ev.Details); err != nil {
+		return nil, err
+	}
+	if len(ev.Details) == 0 {
+		ev.Details = nil
+	}
+	return &ev, nil
+}
+
+// ลบอีเมลและรายละเอียดออก แต่ยังเก็บเหตุการณ์ไว้ (อีเมลเดียวกันใน tenant อื่นไม่ถูกแตะ)
+func (s *Store) RedactSubject(ctx context.Context, tenantID string, subjectID string, email string) error {
+	if _, err := s.DB.ExecContext(ctx, s.rebind(`UPDATE audit_logs SET subject_email = '', details = '{}' WHERE subject_id = ?`), subjectID); err != nil {
+		return err
+	}
+	if email == "" {
+		return nil
+	}
+	if _, err := s.DB.ExecContext(ctx, s.rebind(`UPDATE audit_logs SET subject_email = '', details = '{}' WHERE tenant_id = ? AND subject_email = ?`), tenantID, email); err != nil {
+		return err
+	}
+	_, err := s.DB.ExecContext(ctx, s.rebind(`UPDATE audit_logs SET actor_email = '' WHERE tenant_id = ? AND actor_email = ?`), tenantID, email)
+	return err
+}
@@ -0,0 +1,168 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/store"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ======== GroupStore ========
+
+func (s *Store) CreateGroup(ctx context.Context, g *models.Group) error {
+	g.ID = primitive.NewObjectID()
+	_, err := s.DB.ExecContext(ctx, s.rebind(`INSERT INTO groups (id, tenant_id, name, description, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)`),
+		g.ID.Hex(), g.TenantID, g.Name, g.Description, g.CreatedAt.UTC(), g.UpdatedAt.UTC())
+	if _, ok := s.Dialect.UniqueViolation(err); ok {
+		return &store.DuplicateError{Field: "name"}
+	}
+	return err
+}
+
+func (s *Store) GetGroup(ctx context.Context, tenantID string, id string) (*models.Group, error) {
+	g, err := scanGroup(s.DB.QueryRowContext(ctx, s.rebind(`SELECT id, tenant_id, name, description, created_at, updated_at FROM groups WHERE id = ? AND tenant_id = ?`), id, tenantID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, store.ErrNotFound
+	}
+	return g, err
+}
+
+func (s *Store) ListGroups(ctx context.Context, tenantID string) ([]models.Group, error) {
+	rows, err := s.DB.QueryContext(ctx, s.rebind(`SELECT id, tenant_id, name, description, created_at, updated_at FROM groups WHERE tenant_id = ? ORDER BY lower(name)`), tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var groups []models.Group
+	for rows.Next() {
+		g, err := scanGroup(rows)
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, *g)
+	}
+	return groups, rows.Err()
+}
+
+// สมาชิกถูกลบตามด้วย ON DELETE CASCADE
+func (s *Store) DeleteGroup(ctx context.Context, tenantID string, id string) error {
+	res, err := s.DB.ExecContext(ctx, s.rebind(`DELETE FROM groups WHERE id = ? AND tenant_id = ?`), id, tenantID)
+	return rowsAffected(res, err)
+}
+
+func (s *Store) AddMember(ctx context.Context, tenantID string, userID string, m models.GroupMembership) error {
+	// เพิ่มได้เฉพาะเมื่อทั้งกลุ่มและผู้ใช้อยู่ใน tenant เดียวกัน
+	res, err := s.DB.ExecContext(ctx, s.rebind(`
+		INSERT INTO group_members (group_id, user_id, role, joined_at)
+		SELECT g.id, u.id, ?, ? FROM users u JOIN groups g ON g.id = ? AND g.tenant_id = u.tenant_id
+		WHERE u.id = ? AND u.tenant_id = ? AND u.deleted = ?
+		ON CONFLICT (group_id, user_id) DO UPDATE SET role = excluded.role`),
+		m.Role, m.JoinedAt.UTC(), m.GroupID.Hex(), userID, tenantID, false)
+	return rowsAffected(res, err)
+}
+
+func (s *Store) RemoveMember(ctx context.Context, tenantID string, groupID string, userID string) error {
+	res, err := s.DB.ExecContext(ctx, s.rebind(`
+		DELETE FROM group_members WHERE group_id = ? AND user_id = ?
+		AND group_id IN (SELECT id FROM groups WHERE tenant_id = ?)`), groupID, userID, tenantID)
+	return rowsAffected(res, err)
+}
+
+func (s *Store) GetMembership(ctx context.Context, tenantID string, groupID string, userID string) (*models.GroupMembership, error) {
+	var (
+		m  models.GroupMembership
+		id string
+	)
+	err := s.DB.QueryRowContext(ctx, s.rebind(`
+		SELECT m.group_id, m.role, m.joined_at FROM group_members m
+		JOIN groups g ON g.id = m.group_id JOIN users u ON u.id = m.user_id
+		WHERE m.group_id = ? AND m.user_id = ? AND g.tenant_id = ? AND u.deleted = ?`),
+		groupID, userID, tenantID, false).Scan(&id, &m.Role, &m.JoinedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, store.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if m.GroupID, err = primitive.ObjectIDFromHex(id); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (s *Store) ListMembers(ctx context.Context, tenantID string, groupID string) ([]models.GroupMember, error) {
+	rows, err := s.DB.QueryContext(ctx, s.rebind(`
+		SELECT u.id, u.email, u.username, m.role, m.joined_at FROM group_members m
+		JOIN groups g ON g.id = m.group_id JOIN users u ON u.id = m.user_id
+		WHERE m.group_id = ? AND g.tenant_id = ? AND u.deleted = ?
+		ORDER BY m.joined_at, u.id`), groupID, tenantID, false)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := []models.GroupMember{}
+	for rows.Next() {
+		var (
+			m  models.GroupMember
+			id string
+		)
+		if err := rows.Scan(&id, &m.Email, &m.Username, &m.Role, &m.JoinedAt); err != nil {
+			return nil, err
+		}
+		if m.UserID, err = primitive.ObjectIDFromHex(id); err != nil {
+			return nil, err
+		}
+		members = append(members, m)
+	}
+	return members, rows.Err()
+}
+
+func (s *Store) ListUserGroups(ctx context.Context, tenantID string, userID string) ([]models.UserGroup, error) {
+	rows, err := s.DB.QueryContext(ctx, s.rebind(`
+		SELECT g.id, g.tenant_id, g.name, g.description, g.created_at, g.updated_at, m.role, m.joined_at
+		FROM group_members m JOIN groups g ON g.id = m.group_id
+		WHERE m.user_id = ? AND g.tenant_id = ?
+		ORDER BY lower(g.name)`), userID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var groups []models.UserGroup
+	for rows.Next() {
+		var (
+			ug models.UserGroup
+			id string
+		)
+		g := &ug.Group
+		if err := rows.Scan(&id, &g.TenantID, &g.Name, &g.Description, &g.CreatedAt, &g.UpdatedAt, &ug.Role, &ug.JoinedAt); err != nil {
+			return nil, err
+		}
+		if g.ID, err = primitive.ObjectIDFromHex(id); err != nil {
+			return nil, err
+		}
+		groups = append(groups, ug)
+	}
+	return groups, rows.Err()
+}
+
+func scanGroup(row rowScanner) (*models.Group, error) {
+	var (
+		g  models.Group
+		id string
+	)
+	if err := row.Scan(&id, &g.TenantID, &g.Name, &g.Description, &g.CreatedAt, &g.UpdatedAt); err != nil {
+		return nil, err
+	}
+	var err error
+	if g.ID, err = primitive.ObjectIDFromHex(id); err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
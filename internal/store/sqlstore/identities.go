@@ -0,0 +1,252 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/store"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ======== IdentityStore ========
+
+const (
+	identityProviderColumns = `id, tenant_id, name, display_name, protocol, issuer, client_id, client_secret, redirect_uri, scopes, saml, allow_signup, allowed_domains, created_at, updated_at`
+	linkedIdentityColumns   = `tenant_id, user_id, provider_id, subject, email, created_at, last_login_at`
+	directoryColumns        = `id, tenant_id, name, url, start_tls, root_ca, bind_dn, bind_password, user_dn_template, base_dn, user_filter,
+		username_attribute, display_name_attribute, group_filter, role_mappings, default_role, domains, allow_local_fallback, pool_size, created_at, updated_at`
+)
+
+func (s *Store) CreateIdentityProvider(ctx context.Context, p *models.IdentityProvider) error {
+	p.ID = primitive.NewObjectID()
+	_, err := s.DB.ExecContext(ctx, s.rebind(`INSERT INTO identity_providers (`+identityProviderColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`),
+		p.ID.Hex(), p.TenantID, p.Name, p.DisplayName, p.Protocol, p.Issuer, p.ClientID, p.ClientSecret, p.RedirectURI, marshalJSON(p.Scopes),
+		marshalJSON(p.SAML), p.AllowSignup, marshalJSON(p.AllowedDomains), p.CreatedAt.UTC(), p.UpdatedAt.UTC())
+	if _, ok := s.Dialect.UniqueViolation(err); ok {
+		return &store.DuplicateError{Field: "name"}
+	}
+	return err
+}
+
+func (s *Store) GetIdentityProvider(ctx context.Context, tenantID string, id string) (*models.IdentityProvider, error) {
+	return s.getIdentityProvider(ctx, `id = ? AND tenant_id = ?`, id, tenantID)
+}
+
+func (s *Store) GetIdentityProviderByName(ctx context.Context, tenantID string, name string) (*models.IdentityProvider, error) {
+	return s.getIdentityProvider(ctx, `tenant_id = ? AND lower(name) = lower(?)`, tenantID, name)
+}
+
+func (s *Store) ListIdentityProviders(ctx context.Context, tenantID string) ([]models.IdentityProvider, error) {
+	rows, err := s.DB.QueryContext(ctx, s.rebind(`SELECT `+identityProviderColumns+` FROM identity_providers WHERE tenant_id = ? ORDER BY lower(name)`), tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	providers := []models.IdentityProvider{}
+	for rows.Next() {
+		p, err := scanIdentityProvider(rows)
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, *p)
+	}
+	return providers, rows.Err()
+}
+
+func (s *Store) DeleteIdentityProvider(ctx context.Context, tenantID string, id string) error {
+	// บัญชีที่ผูกกับ provider ถูกลบตาม (ON DELETE CASCADE)
+	res, err := s.DB.ExecContext(ctx, s.rebind(`DELETE FROM identity_providers WHERE id = ? AND tenant_id = ?`), id, tenantID)
+	return rowsAffected(res, err)
+}
+
+func (s *Store) CreateLinkedIdentity(ctx context.Context, l *models.LinkedIdentity) error {
+	_, err := s.DB.ExecContext(ctx, s.rebind(`INSERT INTO linked_identities (`+linkedIdentityColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?)`),
+		l.TenantID, l.UserID.Hex(), l.ProviderID.Hex(), l.Subject, l.Email, l.CreatedAt.UTC(), nullTime(l.LastLoginAt))
+	if constraint, ok := s.Dialect.UniqueViolation(err); ok {
+		if strings.Contains(constraint, "subject") {
+			return &store.DuplicateError{Field: "subject"}
+		}
+		return &store.DuplicateError{Field: "provider"}
+	}
+	return err
+}
+
+func (s *Store) GetLinkedIdentity(ctx context.Context, providerID string, subject string) (*models.LinkedIdentity, error) {
+	l, err := scanLinkedIdentity(s.DB.QueryRowContext(ctx, s.rebind(`SELECT `+linkedIdentityColumns+` FROM linked_identities
+		WHERE provider_id = ? AND subject = ?`), providerID, subject))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, store.ErrNotFound
+	}
+	return l, err
+}
+
+func (s *Store) ListLinkedIdentities(ctx context.Context, tenantID string, userID string) ([]models.LinkedIdentity, error) {
+	rows, err := s.DB.QueryContext(ctx, s.rebind(`SELECT `+linkedIdentityColumns+` FROM linked_identities
+		WHERE tenant_id = ? AND user_id = ? ORDER BY created_at`), tenantID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	links := []models.LinkedIdentity{}
+	for rows.Next() {
+		l, err := scanLinkedIdentity(rows)
+		if err != nil {
+			return nil, err
+		}
+		links = append(links, *l)
+	}
+	return links, rows.Err()
+}
+
+func (s *Store) TouchLinkedIdentity(ctx context.Context, providerID string, subject string, at time.Time) error {
+	res, err := s.DB.ExecContext(ctx, s.rebind(`UPDATE linked_identities SET last_login_at = ? WHERE provider_id = ? AND subject = ?`),
+		at.UTC(), providerID, subject)
+	return rowsAffected(res, err)
+}
+
+func (s *Store) DeleteLinkedIdentity(ctx context.Context, tenantID string, userID string, providerID string) error {
+	res, err := s.DB.ExecContext(ctx, s.rebind(`DELETE FROM linked_identities WHERE tenant_id = ? AND user_id = ? AND provider_id = ?`),
+		tenantID, userID, providerID)
+	return rowsAffected(res, err)
+}
+
+func (s *Store) DeleteUserIdentities(ctx context.Context, tenantID string, userID string) error {
+	if _, err := s.DB.ExecContext(ctx, s.rebind(`DELETE FROM linked_identities WHERE tenant_id = ? AND user_id = ?`), tenantID, userID); err != nil {
+		return err
+	}
+	_, err := s.DB.ExecContext(ctx, s.rebind(`DELETE FROM passkeys WHERE tenant_id = ? AND user_id = ?`), tenantID, userID)
+	return err
+}
+
+func (s *Store) CreateDirectory(ctx context.Context, d *models.Directory) error {
+	d.ID = primitive.NewObjectID()
+	_, err := s.DB.ExecContext(ctx, s.rebind(`INSERT INTO directories (`+directoryColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`),
+		d.ID.Hex(), d.TenantID, d.Name, d.URL, d.StartTLS, d.RootCA, d.BindDN, d.BindPassword, d.UserDNTemplate, d.BaseDN, d.UserFilter,
+		d.UsernameAttribute, d.DisplayNameAttribute, d.GroupFilter, marshalJSON(d.RoleMappings), d.DefaultRole, marshalJSON(d.Domains),
+		d.AllowLocalFallback, d.PoolSize, d.CreatedAt.UTC(), d.UpdatedAt.UTC())
+	if _, ok := s.Dialect.UniqueViolation(err); ok {
+		return &store.DuplicateError{Field: "name"}
+	}
+	return err
+}
+
+func (s *Store) GetDirectory(ctx context.Context, tenantID string, id string) (*models.Directory, error) {
+	d, err := scanDirectory(s.DB.QueryRowContext(ctx, s.rebind(`SELECT `+directoryColumns+` FROM directories WHERE id = ? AND tenant_id = ?`), id, tenantID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, store.ErrNotFound
+	}
+	return d, err
+}
+
+func (s *Store) ListDirectories(ctx context.Context, tenantID string) ([]models.Directory, error) {
+	rows, err := s.DB.QueryContext(ctx, s.rebind(`SELECT `+directoryColumns+` FROM directories WHERE tenant_id = ? ORDER BY lower(name)`), tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	directories := []models.Directory{}
+	for rows.Next() {
+		d, err := scanDirectory(rows)
+		if err != nil {
+			return nil, err
+		}
+		directories = append(directories, *d)
+	}
+	return directories, rows.Err()
+}
+
+func (s *Store) DeleteDirectory(ctx context.Context, tenantID string, id string) error {
+	res, err := s.DB.ExecContext(ctx, s.rebind(`DELETE FROM directories WHERE id = ? AND tenant_id = ?`), id, tenantID)
+	return rowsAffected(res, err)
+}
+
+func (s *Store) getIdentityProvider(ctx context.Context, where string, args ...interface{}) (*models.IdentityProvider, error) {
+	p, err := scanIdentityProvider(s.DB.QueryRowContext(ctx, s.rebind(`SELECT `+identityProviderColumns+` FROM identity_providers WHERE `+where), args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, store.ErrNotFound
+	}
+	return p, err
+}
+
+func scanIdentityProvider(row rowScanner) (*models.IdentityProvider, error) {
+	var (
+		p                   models.IdentityProvider
+		id, scopes, domains string
+		saml                sql.NullString
+	)
+	if err := row.Scan(&id, &p.TenantID, &p.Name, &p.DisplayName, &p.Protocol, &p.Issuer, &p.ClientID, &p.ClientSecret, &p.RedirectURI,
+		&scopes, &saml, &p.AllowSignup, &domains, &p.CreatedAt, &p.UpdatedAt); err != nil {
+		return nil, err
+	}
+	var err error
+	if p.ID, err = primitive.ObjectIDFromHex(id); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(scopes), &p.Scopes); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(domains), &p.AllowedDomains); err != nil {
+		return nil, err
+	}
+	if saml.Valid {
+		if err := json.Unmarshal([]byte(saml.String), &p.SAML); err != nil {
+			return nil, err
+		}
+	}
+	return &p, nil
+}
+
+func scanLinkedIdentity(row rowScanner) (*models.LinkedIdentity, error) {
+	var (
+		l                  models.LinkedIdentity
+		userID, providerID string
+		lastLogin          sql.NullTime
+	)
+	if err := row.Scan(&l.TenantID, &userID, &providerID, &l.Subject, &l.Email, &l.CreatedAt, &lastLogin); err != nil {
+		return nil, err
+	}
+	var err error
+	if l.UserID, err = primitive.ObjectIDFromHex(userID); err != nil {
+		return nil, err
+	}
+	if l.ProviderID, err = primitive.ObjectIDFromHex(providerID); err != nil {
+		return nil, err
+	}
+	if lastLogin.Valid {
+		l.LastLoginAt = &lastLogin.Time
+	}
+	return &l, nil
+}
+
+func scanDirectory(row rowScanner) (*models.Directory, error) {
+	var (
+		d                     models.Directory
+		id, mappings, domains string
+	)
+	if err := row.Scan(&id, &d.TenantID, &d.Name, &d.URL, &d.StartTLS, &d.RootCA, &d.BindDN, &d.BindPassword, &d.UserDNTemplate, &d.BaseDN,
+		&d.UserFilter, &d.UsernameAttribute, &d.DisplayNameAttribute, &d.GroupFilter, &mappings, &d.DefaultRole, &domains,
+		&d.AllowLocalFallback, &d.PoolSize, &d.CreatedAt, &d.UpdatedAt); err != nil {
+		return nil, err
+	}
+	var err error
+	if d.ID, err = primitive.ObjectIDFromHex(id); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(mappings), &d.RoleMappings); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(domains), &d.Domains); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
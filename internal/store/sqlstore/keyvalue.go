@@ -0,0 +1,92 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"time"
+
+	"auth-microservice/internal/store"
+)
+
+// ======== KeyValueStore ========
+
+// อ่านค่าที่ยังไม่หมดอายุภายใน transaction (ล็อกแถวถ้า dialect รองรับ)
+func (s *Store) getEntry(ctx context.Context, tx *sql.Tx, key string) (string, time.Time, error) {
+	var value string
+	var expiresAt time.Time
+	err := tx.QueryRowContext(ctx, s.rebind(`SELECT value, expires_at FROM cache_entries WHERE key = ?`+s.Dialect.ForUpdate), key).Scan(&value, &expiresAt)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && !expiresAt.After(time.Now())) {
+		return "", time.Time{}, store.ErrNotFound
+	}
+	return value, expiresAt, err
+}
+
+func (s *Store) putEntry(ctx context.Context, tx *sql.Tx, key string, value string, expiresAt time.Time) error {
+	_, err := tx.ExecContext(ctx, s.rebind(`INSERT INTO cache_entries (key, value, expires_at) VALUES (?, ?, ?)
+		ON CONFLICT (key) DO UPDATE SET value = excluded.value, expires_at = excluded.expires_at`),
+		key, value, expiresAt.UTC())
+	return err
+}
+
+func (s *Store) Incr(ctx context.Context, key string, window time.Duration) (int64, error) {
+	var count int64
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		value, expiresAt, err := s.getEntry(ctx, tx, key)
+		if errors.Is(err, store.ErrNotFound) {
+			// เริ่มจับเวลาตั้งแต่ครั้งแรก
+			count = 1
+			return s.putEntry(ctx, tx, key, "1", time.Now().Add(window))
+		}
+		if err != nil {
+			return err
+		}
+		if count, err = strconv.ParseInt(value, 10, 64); err != nil {
+			return err
+		}
+		count++
+		return s.putEntry(ctx, tx, key, strconv.FormatInt(count, 10), expiresAt)
+	})
+	return count, err
+}
+
+func (s *Store) Get(ctx context.Context, key string) (string, error) {
+	var value string
+	err := s.DB.QueryRowContext(ctx, s.rebind(`SELECT value FROM cache_entries WHERE key = ? AND expires_at > ?`), key, time.Now().UTC()).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", store.ErrNotFound
+	}
+	return value, err
+}
+
+func (s *Store) Set(ctx context.Context, key string, value string, ttl time.Duration) error {
+	// SQL ไม่มี TTL จึงลบค่าที่หมดอายุแล้วทิ้งทุกครั้งที่เขียนค่าใหม่
+	if _, err := s.DB.ExecContext(ctx, s.rebind(`DELETE FROM cache_entries WHERE expires_at < ?`), time.Now().UTC()); err != nil {
+		return err
+	}
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		return s.putEntry(ctx, tx, key, value, time.Now().Add(ttl))
+	})
+}
+
+func (s *Store) Delete(ctx context.Context, key string) error {
+	_, err := s.DB.ExecContext(ctx, s.rebind(`DELETE FROM cache_entries WHERE key = ?`), key)
+	return err
+}
+
+func (s *Store) Rename(ctx context.Context, from string, to string) error {
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		value, expiresAt, err := s.getEntry(ctx, tx, from)
+		if errors.Is(err, store.ErrNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, s.rebind(`DELETE FROM cache_entries WHERE key = ?`), from); err != nil {
+			return err
+		}
+		return s.putEntry(ctx, tx, to, value, expiresAt)
+	})
+}
@@ -0,0 +1,176 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/store"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ======== OAuthClientStore ========
+
+const (
+	oauthClientColumns  = `id, tenant_id, client_id, name, redirect_uris, scopes, public, trusted, secrets, created_at, updated_at`
+	oauthConsentColumns = `tenant_id, user_id, client_id, scopes, grant_id, created_at, updated_at`
+)
+
+func (s *Store) CreateOAuthClient(ctx context.Context, c *models.OAuthClient) error {
+	c.ID = primitive.NewObjectID()
+	_, err := s.DB.ExecContext(ctx, s.rebind(`INSERT INTO oauth_clients (`+oauthClientColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`),
+		c.ID.Hex(), c.TenantID, c.ClientID, c.Name, marshalJSON(c.RedirectURIs), marshalJSON(c.Scopes), c.Public, c.Trusted,
+		marshalJSON(c.Secrets), c.CreatedAt.UTC(), c.UpdatedAt.UTC())
+	return s.oauthClientDuplicate(err)
+}
+
+func (s *Store) GetOAuthClient(ctx context.Context, tenantID string, id string) (*models.OAuthClient, error) {
+	return s.getOAuthClient(ctx, `id = ? AND tenant_id = ?`, id, tenantID)
+}
+
+func (s *Store) GetOAuthClientByClientID(ctx context.Context, clientID string) (*models.OAuthClient, error) {
+	return s.getOAuthClient(ctx, `client_id = ?`, clientID)
+}
+
+func (s *Store) ListOAuthClients(ctx context.Context, tenantID string) ([]models.OAuthClient, error) {
+	rows, err := s.DB.QueryContext(ctx, s.rebind(`SELECT `+oauthClientColumns+` FROM oauth_clients WHERE tenant_id = ? ORDER BY lower(name)`), tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	clients := []models.OAuthClient{}
+	for rows.Next() {
+		c, err := scanOAuthClient(rows)
+		if err != nil {
+			return nil, err
+		}
+		clients = append(clients, *c)
+	}
+	return clients, rows.Err()
+}
+
+func (s *Store) SaveOAuthClient(ctx context.Context, c *models.OAuthClient) error {
+	res, err := s.DB.ExecContext(ctx, s.rebind(`
+		UPDATE oauth_clients SET name = ?, redirect_uris = ?, scopes = ?, public = ?, trusted = ?, secrets = ?, updated_at = ?
+		WHERE id = ? AND tenant_id = ?`),
+		c.Name, marshalJSON(c.RedirectURIs), marshalJSON(c.Scopes), c.Public, c.Trusted, marshalJSON(c.Secrets), c.UpdatedAt.UTC(),
+		c.ID.Hex(), c.TenantID)
+	if err := s.oauthClientDuplicate(err); err != nil {
+		return err
+	}
+	return rowsAffected(res, err)
+}
+
+func (s *Store) DeleteOAuthClient(ctx context.Context, tenantID string, id string) error {
+	// ความยินยอมของ client ถูกลบตาม (ON DELETE CASCADE)
+	res, err := s.DB.ExecContext(ctx, s.rebind(`DELETE FROM oauth_clients WHERE id = ? AND tenant_id = ?`), id, tenantID)
+	return rowsAffected(res, err)
+}
+
+func (s *Store) GetConsent(ctx context.Context, tenantID string, userID string, clientID string) (*models.OAuthConsent, error) {
+	c, err := scanOAuthConsent(s.DB.QueryRowContext(ctx, s.rebind(`SELECT `+oauthConsentColumns+` FROM oauth_consents
+		WHERE tenant_id = ? AND user_id = ? AND client_id = ?`), tenantID, userID, clientID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, store.ErrNotFound
+	}
+	return c, err
+}
+
+func (s *Store) SaveConsent(ctx context.Context, c *models.OAuthConsent) error {
+	_, err := s.DB.ExecContext(ctx, s.rebind(`INSERT INTO oauth_consents (`+oauthConsentColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (tenant_id, user_id, client_id) DO UPDATE SET
+			scopes = excluded.scopes, grant_id = excluded.grant_id, created_at = excluded.created_at, updated_at = excluded.updated_at`),
+		c.TenantID, c.UserID.Hex(), c.ClientID, marshalJSON(c.Scopes), c.GrantID, c.CreatedAt.UTC(), c.UpdatedAt.UTC())
+	return err
+}
+
+func (s *Store) ListConsents(ctx context.Context, tenantID string, userID string) ([]models.OAuthConsent, error) {
+	rows, err := s.DB.QueryContext(ctx, s.rebind(`SELECT `+oauthConsentColumns+` FROM oauth_consents
+		WHERE tenant_id = ? AND user_id = ? ORDER BY updated_at DESC`), tenantID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	consents := []models.OAuthConsent{}
+	for rows.Next() {
+		c, err := scanOAuthConsent(rows)
+		if err != nil {
+			return nil, err
+		}
+		consents = append(consents, *c)
+	}
+	return consents, rows.Err()
+}
+
+func (s *Store) DeleteConsent(ctx context.Context, tenantID string, userID string, clientID string) error {
+	res, err := s.DB.ExecContext(ctx, s.rebind(`DELETE FROM oauth_consents WHERE tenant_id = ? AND user_id = ? AND client_id = ?`),
+		tenantID, userID, clientID)
+	return rowsAffected(res, err)
+}
+
+func (s *Store) getOAuthClient(ctx context.Context, where string, args ...interface{}) (*models.OAuthClient, error) {
+	c, err := scanOAuthClient(s.DB.QueryRowContext(ctx, s.rebind(`SELECT `+oauthClientColumns+` FROM oauth_clients WHERE `+where), args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, store.ErrNotFound
+	}
+	return c, err
+}
+
+func (s *Store) oauthClientDuplicate(err error) error {
+	if constraint, ok := s.Dialect.UniqueViolation(err); ok {
+		if strings.Contains(constraint, "client_id") {
+			return &store.DuplicateError{Field: "clientId"}
+		}
+		return &store.DuplicateError{Field: "name"}
+	}
+	return err
+}
+
+func scanOAuthClient(row rowScanner) (*models.OAuthClient, error) {
+	var (
+		c                                 models.OAuthClient
+		id, redirectURIs, scopes, secrets string
+	)
+	if err := row.Scan(&id, &c.TenantID, &c.ClientID, &c.Name, &redirectURIs, &scopes, &c.Public, &c.Trusted, &secrets,
+		&c.CreatedAt, &c.UpdatedAt); err != nil {
+		return nil, err
+	}
+	var err error
+	if c.ID, err = primitive.ObjectIDFromHex(id); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(redirectURIs), &c.RedirectURIs); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(scopes), &c.Scopes); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(secrets), &c.Secrets); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func scanOAuthConsent(row rowScanner) (*models.OAuthConsent, error) {
+	var (
+		c              models.OAuthConsent
+		userID, scopes string
+	)
+	if err := row.Scan(&c.TenantID, &userID, &c.ClientID, &scopes, &c.GrantID, &c.CreatedAt, &c.UpdatedAt); err != nil {
+		return nil, err
+	}
+	var err error
+	if c.UserID, err = primitive.ObjectIDFromHex(userID); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(scopes), &c.Scopes); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
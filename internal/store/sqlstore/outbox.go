@@ -0,0 +1,233 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/store"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ======== OutboxStore ========
+
+const outboxColumns = `id, tenant_id, type, aggregate_id, payload, created_at, published_at, attempts, next_attempt_at, last_error`
+
+func (s *Store) CreateUserWithEvent(ctx context.Context, u *models.User, ev *models.OutboxEvent) error {
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		if err := s.createUser(ctx, tx, u); err != nil {
+			return err
+		}
+		ev.AggregateID = u.ID.Hex()
+		return s.insertOutboxEvent(ctx, tx, ev)
+	})
+}
+
+func (s *Store) AddTokenWithEvent(ctx context.Context, token string, expiresAt time.Time, ev *models.OutboxEvent) error {
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		if err := s.addToken(ctx, tx, token, expiresAt); err != nil {
+			return err
+		}
+		return s.insertOutboxEvent(ctx, tx, ev)
+	})
+}
+
+func (s *Store) UpdateProfileWithEvent(ctx context.Context, id string, patch store.ProfilePatch, updatedAt time.Time, ev *models.OutboxEvent) error {
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		if err := s.updateProfile(ctx, tx, id, patch, updatedAt); err != nil {
+			return err
+		}
+		return s.insertOutboxEvent(ctx, tx, ev)
+	})
+}
+
+func (s *Store) SoftDeleteUserWithEvent(ctx context.Context, id string, deletedAt time.Time, ev *models.OutboxEvent) (*models.User, error) {
+	return s.modifyUserWithEvent(ctx, softDeleteUser(id, deletedAt), ev)
+}
+
+func (s *Store) RestoreUserWithEvent(ctx context.Context, id string, updatedAt time.Time, ev *models.OutboxEvent) (*models.User, error) {
+	return s.modifyUserWithEvent(ctx, restoreUser(id, updatedAt), ev)
+}
+
+func (s *Store) modifyUserWithEvent(ctx context.Context, m userModification, ev *models.OutboxEvent) (*models.User, error) {
+	var user *models.User
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		if user, err = s.modifyUserTx(ctx, tx, m); err != nil {
+			return err
+		}
+		return s.insertOutboxEvent(ctx, tx, ev)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *Store) DeleteUserWithEvent(ctx context.Context, id string, ev *models.OutboxEvent) error {
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, s.rebind(`DELETE FROM users WHERE id = ?`), id)
+		if err := rowsAffected(res, err); err != nil {
+			return err
+		}
+		return s.insertOutboxEvent(ctx, tx, ev)
+	})
+}
+
+func (s *Store) CreateUsersWithEvents(ctx context.Context, users []*models.User, events []*models.OutboxEvent) ([]error, error) {
+	results := make([]error, len(users))
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		// savepoint ต่อคน: ผู้ใช้ที่ซ้ำถูกยกเลิกเฉพาะคนนั้น (PostgreSQL ยกเลิกทั้ง transaction ถ้าไม่มี savepoint)
+		for i, u := range users {
+			if _, err := tx.ExecContext(ctx, `SAVEPOINT import_user`); err != nil {
+				return err
+			}
+			err := s.createUser(ctx, tx, u)
+			if err == nil {
+				events[i].AggregateID = u.ID.Hex()
+				err = s.insertOutboxEvent(ctx, tx, events[i])
+			}
+			var dupErr *store.DuplicateError
+			if errors.As(err, &dupErr) {
+				results[i] = err
+				if _, err := tx.ExecContext(ctx, `ROLLBACK TO SAVEPOINT import_user`); err != nil {
+					return err
+				}
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			if _, err := tx.ExecContext(ctx, `RELEASE SAVEPOINT import_user`); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (s *Store) ListOutboxEvents(ctx context.Context, q store.OutboxListQuery) ([]models.OutboxEvent, error) {
+	where := []string{"tenant_id = ?", "id > ?"}
+	args := []interface{}{q.TenantID, q.AfterID}
+	for column, values := range map[string][]string{"type": q.Types, "aggregate_id": q.AggregateIDs} {
+		if len(values) == 0 {
+			continue
+		}
+		where = append(where, column+" IN (?"+strings.Repeat(", ?", len(values)-1)+")")
+		for _, v := range values {
+			args = append(args, v)
+		}
+	}
+	args = append(args, q.Limit)
+
+	rows, err := s.DB.QueryContext(ctx, s.rebind(`SELECT `+outboxColumns+` FROM outbox WHERE `+strings.Join(where, " AND ")+
+		` ORDER BY id LIMIT ?`), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []models.OutboxEvent{}
+	for rows.Next() {
+		ev, err := scanOutboxEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, *ev)
+	}
+	return list, rows.Err()
+}
+
+func (s *Store) ClaimOutboxEvents(ctx context.Context, now time.Time, leaseUntil time.Time, limit int) ([]models.OutboxEvent, error) {
+	rows, err := s.DB.QueryContext(ctx, s.rebind(`SELECT id FROM outbox WHERE published_at IS NULL AND next_attempt_at <= ?
+		ORDER BY id LIMIT ?`), now.UTC(), limit)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// จองด้วยเงื่อนไขเดิม ถ้า relay อื่นจองไปก่อนแล้วจะไม่มีแถวถูกแก้
+	claimed := []models.OutboxEvent{}
+	for _, id := range ids {
+		res, err := s.DB.ExecContext(ctx, s.rebind(`UPDATE outbox SET next_attempt_at = ? WHERE id = ? AND published_at IS NULL AND next_attempt_at <= ?`),
+			leaseUntil.UTC(), id, now.UTC())
+		if err := rowsAffected(res, err); errors.Is(err, store.ErrNotFound) {
+			continue
+		} else if err != nil {
+			return claimed, err
+		}
+		ev, err := scanOutboxEvent(s.DB.QueryRowContext(ctx, s.rebind(`SELECT `+outboxColumns+` FROM outbox WHERE id = ?`), id))
+		if err != nil {
+			return claimed, err
+		}
+		claimed = append(claimed, *ev)
+	}
+	return claimed, nil
+}
+
+func (s *Store) MarkOutboxPublished(ctx context.Context, id string, publishedAt time.Time) error {
+	res, err := s.DB.ExecContext(ctx, s.rebind(`UPDATE outbox SET published_at = ?, last_error = '' WHERE id = ?`), publishedAt.UTC(), id)
+	return rowsAffected(res, err)
+}
+
+func (s *Store) MarkOutboxFailed(ctx context.Context, id string, attempts int, nextAttemptAt time.Time, lastError string) error {
+	res, err := s.DB.ExecContext(ctx, s.rebind(`UPDATE outbox SET attempts = ?, next_attempt_at = ?, last_error = ? WHERE id = ?`),
+		attempts, nextAttemptAt.UTC(), lastError, id)
+	return rowsAffected(res, err)
+}
+
+func (s *Store) PurgePublishedOutbox(ctx context.Context, before time.Time) (int64, error) {
+	res, err := s.DB.ExecContext(ctx, s.rebind(`DELETE FROM outbox WHERE published_at < ?`), before.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (s *Store) insertOutboxEvent(ctx context.Context, tx *sql.Tx, ev *models.OutboxEvent) error {
+	ev.ID = primitive.NewObjectID()
+	_, err := tx.ExecContext(ctx, s.rebind(`INSERT INTO outbox (`+outboxColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`),
+		ev.ID.Hex(), ev.TenantID, ev.Type, ev.AggregateID, ev.Payload, ev.CreatedAt.UTC(), nullTime(ev.PublishedAt),
+		ev.Attempts, ev.NextAttemptAt.UTC(), ev.LastError)
+	return err
+}
+
+func scanOutboxEvent(row rowScanner) (*models.OutboxEvent, error) {
+	var (
+		ev          models.OutboxEvent
+		id          string
+		publishedAt sql.NullTime
+	)
+	if err := row.Scan(&id, &ev.TenantID, &ev.Type, &ev.AggregateID, &ev.Payload, &ev.CreatedAt, &publishedAt,
+		&ev.Attempts, &ev.NextAttemptAt, &ev.LastError); err != nil {
+		return nil, err
+	}
+	var err error
+	if ev.ID, err = primitive.ObjectIDFromHex(id); err != nil {
+		return nil, err
+	}
+	if publishedAt.Valid {
+		ev.PublishedAt = &publishedAt.Time
+	}
+	return &ev, nil
+}
@@ -0,0 +1,107 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/store"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const passkeyColumns = `id, tenant_id, user_id, name, credential_id, public_key, algorithm, sign_count, aaguid, attestation_format,
+	transports, backup_eligible, backed_up, created_at, last_used_at`
+
+func (s *Store) CreatePasskey(ctx context.Context, p *models.Passkey) error {
+	p.ID = primitive.NewObjectID()
+	_, err := s.DB.ExecContext(ctx, s.rebind(`INSERT INTO passkeys (`+passkeyColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`),
+		p.ID.Hex(), p.TenantID, p.UserID.Hex(), p.Name, p.CredentialID, p.PublicKey, p.Algorithm, int64(p.SignCount), p.AAGUID,
+		p.AttestationFormat, marshalJSON(p.Transports), p.BackupEligible, p.BackedUp, p.CreatedAt.UTC(), nullTime(p.LastUsedAt))
+	if _, ok := s.Dialect.UniqueViolation(err); ok {
+		return &store.DuplicateError{Field: "credentialId"}
+	}
+	return err
+}
+
+func (s *Store) GetPasskeyByCredentialID(ctx context.Context, tenantID string, credentialID string) (*models.Passkey, error) {
+	return s.getPasskey(ctx, `tenant_id = ? AND credential_id = ?`, tenantID, credentialID)
+}
+
+func (s *Store) ListPasskeys(ctx context.Context, tenantID string, userID string) ([]models.Passkey, error) {
+	rows, err := s.DB.QueryContext(ctx, s.rebind(`SELECT `+passkeyColumns+` FROM passkeys WHERE tenant_id = ? AND user_id = ? ORDER BY created_at`),
+		tenantID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	passkeys := []models.Passkey{}
+	for rows.Next() {
+		p, err := scanPasskey(rows)
+		if err != nil {
+			return nil, err
+		}
+		passkeys = append(passkeys, *p)
+	}
+	return passkeys, rows.Err()
+}
+
+func (s *Store) UpdatePasskeyUsage(ctx context.Context, id string, prevCount uint32, signCount uint32, backedUp bool, at time.Time) error {
+	res, err := s.DB.ExecContext(ctx, s.rebind(`UPDATE passkeys SET sign_count = ?, backed_up = ?, last_used_at = ? WHERE id = ? AND sign_count = ?`),
+		int64(signCount), backedUp, at.UTC(), id, int64(prevCount))
+	return rowsAffected(res, err)
+}
+
+func (s *Store) RenamePasskey(ctx context.Context, tenantID string, userID string, id string, name string) (*models.Passkey, error) {
+	res, err := s.DB.ExecContext(ctx, s.rebind(`UPDATE passkeys SET name = ? WHERE id = ? AND tenant_id = ? AND user_id = ?`),
+		name, id, tenantID, userID)
+	if err := rowsAffected(res, err); err != nil {
+		return nil, err
+	}
+	return s.getPasskey(ctx, `id = ?`, id)
+}
+
+func (s *Store) DeletePasskey(ctx context.Context, tenantID string, userID string, id string) error {
+	res, err := s.DB.ExecContext(ctx, s.rebind(`DELETE FROM passkeys WHERE id = ? AND tenant_id = ? AND user_id = ?`), id, tenantID, userID)
+	return rowsAffected(res, err)
+}
+
+func (s *Store) getPasskey(ctx context.Context, where string, args ...interface{}) (*models.Passkey, error) {
+	p, err := scanPasskey(s.DB.QueryRowContext(ctx, s.rebind(`SELECT `+passkeyColumns+` FROM passkeys WHERE `+where), args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, store.ErrNotFound
+	}
+	return p, err
+}
+
+func scanPasskey(row rowScanner) (*models.Passkey, error) {
+	var (
+		p                      models.Passkey
+		id, userID, transports string
+		signCount              int64
+		lastUsed               sql.NullTime
+	)
+	if err := row.Scan(&id, &p.TenantID, &userID, &p.Name, &p.CredentialID, &p.PublicKey, &p.Algorithm, &signCount, &p.AAGUID,
+		&p.AttestationFormat, &transports, &p.BackupEligible, &p.BackedUp, &p.CreatedAt, &lastUsed); err != nil {
+		return nil, err
+	}
+	var err error
+	if p.ID, err = primitive.ObjectIDFromHex(id); err != nil {
+		return nil, err
+	}
+	if p.UserID, err = primitive.ObjectIDFromHex(userID); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(transports), &p.Transports); err != nil {
+		return nil, err
+	}
+	p.SignCount = uint32(signCount)
+	if lastUsed.Valid {
+		p.LastUsedAt = &lastUsed.Time
+	}
+	return &p, nil
+}
@@ -0,0 +1,112 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/store"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ======== ServiceAccountStore ========
+
+const serviceAccountColumns = `id, tenant_id, name, description, client_id, role, scopes, credentials, created_at, updated_at`
+
+// client secret และ public key ที่เก็บเป็น JSON ในคอลัมน์ credentials
+type clientCredentials struct {
+	Secrets    []models.ClientSecret    `json:"secrets"`
+	PublicKeys []models.ClientPublicKey `json:"publicKeys"`
+}
+
+func (s *Store) CreateServiceAccount(ctx context.Context, a *models.ServiceAccount) error {
+	a.ID = primitive.NewObjectID()
+	_, err := s.DB.ExecContext(ctx, s.rebind(`INSERT INTO service_accounts (`+serviceAccountColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`),
+		a.ID.Hex(), a.TenantID, a.Name, a.Description, a.ClientID, a.Role, marshalJSON(a.Scopes), marshalCredentials(a),
+		a.CreatedAt.UTC(), a.UpdatedAt.UTC())
+	if constraint, ok := s.Dialect.UniqueViolation(err); ok {
+		if strings.Contains(constraint, "client_id") {
+			return &store.DuplicateError{Field: "clientId"}
+		}
+		return &store.DuplicateError{Field: "name"}
+	}
+	return err
+}
+
+func (s *Store) GetServiceAccount(ctx context.Context, tenantID string, id string) (*models.ServiceAccount, error) {
+	return s.getServiceAccount(ctx, `id = ? AND tenant_id = ?`, id, tenantID)
+}
+
+func (s *Store) GetServiceAccountByClientID(ctx context.Context, clientID string) (*models.ServiceAccount, error) {
+	return s.getServiceAccount(ctx, `client_id = ?`, clientID)
+}
+
+func (s *Store) ListServiceAccounts(ctx context.Context, tenantID string) ([]models.ServiceAccount, error) {
+	rows, err := s.DB.QueryContext(ctx, s.rebind(`SELECT `+serviceAccountColumns+` FROM service_accounts WHERE tenant_id = ? ORDER BY lower(name)`), tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	accounts := []models.ServiceAccount{}
+	for rows.Next() {
+		a, err := scanServiceAccount(rows)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, *a)
+	}
+	return accounts, rows.Err()
+}
+
+func (s *Store) SaveServiceAccount(ctx context.Context, a *models.ServiceAccount) error {
+	res, err := s.DB.ExecContext(ctx, s.rebind(`
+		UPDATE service_accounts SET name = ?, description = ?, role = ?, scopes = ?, credentials = ?, updated_at = ?
+		WHERE id = ? AND tenant_id = ?`),
+		a.Name, a.Description, a.Role, marshalJSON(a.Scopes), marshalCredentials(a), a.UpdatedAt.UTC(), a.ID.Hex(), a.TenantID)
+	return rowsAffected(res, err)
+}
+
+func (s *Store) DeleteServiceAccount(ctx context.Context, tenantID string, id string) error {
+	res, err := s.DB.ExecContext(ctx, s.rebind(`DELETE FROM service_accounts WHERE id = ? AND tenant_id = ?`), id, tenantID)
+	return rowsAffected(res, err)
+}
+
+func (s *Store) getServiceAccount(ctx context.Context, where string, args ...interface{}) (*models.ServiceAccount, error) {
+	a, err := scanServiceAccount(s.DB.QueryRowContext(ctx, s.rebind(`SELECT `+serviceAccountColumns+` FROM service_accounts WHERE `+where), args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, store.ErrNotFound
+	}
+	return a, err
+}
+
+func marshalCredentials(a *models.ServiceAccount) string {
+	return marshalJSON(clientCredentials{Secrets: a.Secrets, PublicKeys: a.PublicKeys})
+}
+
+func scanServiceAccount(row rowScanner) (*models.ServiceAccount, error) {
+	var (
+		a                       models.ServiceAccount
+		id, scopes, credentials string
+	)
+	if err := row.Scan(&id, &a.TenantID, &a.Name, &a.Description, &a.ClientID, &a.Role, &scopes, &credentials, &a.CreatedAt, &a.UpdatedAt); err != nil {
+		return nil, err
+	}
+	var err error
+	if a.ID, err = primitive.ObjectIDFromHex(id); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(scopes), &a.Scopes); err != nil {
+		return nil, err
+	}
+	var creds clientCredentials
+	if err := json.Unmarshal([]byte(credentials), &creds); err != nil {
+		return nil, err
+	}
+	a.Secrets, a.PublicKeys = creds.Secrets, creds.PublicKeys
+	return &a, nil
+}
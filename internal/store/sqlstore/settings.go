@@ -0,0 +1,68 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/store"
+)
+
+// key ในตาราง settings
+const (
+	profileSchemaKey = "profile_schema"    // schema ของ metadata
+	oidcKeysKey      = "oidc_signing_keys" // signing key ของ OpenID Connect
+)
+
+// ======== SettingsStore ========
+
+func (s *Store) GetProfileSchema(ctx context.Context) ([]models.ProfileAttribute, error) {
+	var value string
+	err := s.DB.QueryRowContext(ctx, s.rebind(`SELECT value FROM settings WHERE key = ?`), profileSchemaKey).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var attributes []models.ProfileAttribute
+	if err := json.Unmarshal([]byte(value), &attributes); err != nil {
+		return nil, err
+	}
+	return attributes, nil
+}
+
+func (s *Store) SaveProfileSchema(ctx context.Context, schema models.ProfileSchema) error {
+	_, err := s.DB.ExecContext(ctx, s.rebind(`INSERT INTO settings (key, value, updated_at) VALUES (?, ?, ?)
+		ON CONFLICT (key) DO UPDATE SET value = excluded.value, updated_at = excluded.updated_at`),
+		profileSchemaKey, marshalJSON(schema.Attributes), schema.UpdatedAt.UTC())
+	return err
+}
+
+func (s *Store) GetOIDCKeys(ctx context.Context) (*models.OIDCKeySet, error) {
+	var (
+		value string
+		keys  models.OIDCKeySet
+	)
+	err := s.DB.QueryRowContext(ctx, s.rebind(`SELECT value, updated_at FROM settings WHERE key = ?`), oidcKeysKey).Scan(&value, &keys.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, store.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(value), &keys.Keys); err != nil {
+		return nil, err
+	}
+	keys.ID = oidcKeysKey
+	return &keys, nil
+}
+
+func (s *Store) InitOIDCKeys(ctx context.Context, keys models.OIDCKeySet) error {
+	_, err := s.DB.ExecContext(ctx, s.rebind(`INSERT INTO settings (key, value, updated_at) VALUES (?, ?, ?)
+		ON CONFLICT (key) DO NOTHING`),
+		oidcKeysKey, marshalJSON(keys.Keys), keys.UpdatedAt.UTC())
+	return err
+}
@@ -0,0 +1,433 @@
+package sqlstore
+
+import (
+	"errors"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// SQLite คือ dialect ของ SQLite แบบ embedded (driver pure Go ไม่ต้องใช้ cgo)
+// ใช้การเชื่อมต่อเดียวเพราะ SQLite เขียนได้ทีละ transaction อยู่แล้ว
+var SQLite = &Dialect{
+	Name:         "sqlite",
+	DriverName:   "sqlite",
+	MaxOpenConns: 1,
+	UniqueViolation: func(err error) (string, bool) {
+		var sqliteErr *sqlite.Error
+		if errors.As(err, &sqliteErr) && (sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE || sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY) {
+			// ข้อความ error มีชื่อ index หรือคอลัมน์ เช่น "UNIQUE constraint failed: index 'users_email_key'"
+			return sqliteErr.Error(), true
+		}
+		return "", false
+	},
+	// เวลาเก็บเป็นข้อความ UTC รูปแบบเดียวกันทั้งหมด จึงเทียบและเรียงแบบข้อความได้ถูกต้อง
+	Migrations: []SchemaMigration{
+		{
+			Version: 1,
+			Name:    "create_users",
+			Up: `
+				CREATE TABLE users (
+					id TEXT PRIMARY KEY,
+					email TEXT NOT NULL,
+					username TEXT NOT NULL,
+					password TEXT NOT NULL,
+					password_history TEXT NOT NULL DEFAULT '[]',
+					password_changed_at TIMESTAMP,
+					role TEXT NOT NULL DEFAULT '',
+					email_verified BOOLEAN NOT NULL DEFAULT FALSE,
+					display_name TEXT NOT NULL DEFAULT '',
+					avatar_url TEXT NOT NULL DEFAULT '',
+					locale TEXT NOT NULL DEFAULT '',
+					timezone TEXT NOT NULL DEFAULT '',
+					phone TEXT NOT NULL DEFAULT '',
+					metadata TEXT NOT NULL DEFAULT '{}',
+					deleted BOOLEAN NOT NULL DEFAULT FALSE,
+					deleted_at TIMESTAMP,
+					created_at TIMESTAMP NOT NULL,
+					updated_at TIMESTAMP NOT NULL
+				);
+				CREATE UNIQUE INDEX users_email_key ON users (lower(email));
+				CREATE UNIQUE INDEX users_username_key ON users (lower(username))`,
+			Down: `DROP TABLE users`,
+		},
+		{
+			Version: 2,
+			Name:    "create_tokens_and_sessions",
+			Up: `
+				CREATE TABLE blacklisted_tokens (
+					token TEXT PRIMARY KEY,
+					expires_at TIMESTAMP NOT NULL
+				);
+				CREATE INDEX blacklisted_tokens_expires_at_idx ON blacklisted_tokens (expires_at);
+				CREATE TABLE sessions (
+					email TEXT PRIMARY KEY,
+					token TEXT NOT NULL,
+					expires_at TIMESTAMP NOT NULL
+				);
+				CREATE INDEX sessions_expires_at_idx ON sessions (expires_at)`,
+			Down: `DROP TABLE sessions; DROP TABLE blacklisted_tokens`,
+		},
+		{
+			Version: 3,
+			Name:    "create_audit_logs_and_settings",
+			Up: `
+				CREATE TABLE audit_logs (
+					id TEXT PRIMARY KEY,
+					action TEXT NOT NULL,
+					actor_email TEXT NOT NULL DEFAULT '',
+					subject_id TEXT NOT NULL DEFAULT '',
+					subject_email TEXT NOT NULL DEFAULT '',
+					details TEXT NOT NULL DEFAULT '{}',
+					created_at TIMESTAMP NOT NULL
+				);
+				CREATE INDEX audit_logs_subject_id_idx ON audit_logs (subject_id, created_at);
+				CREATE INDEX audit_logs_subject_email_idx ON audit_logs (subject_email);
+				CREATE INDEX audit_logs_actor_email_idx ON audit_logs (actor_email);
+				CREATE TABLE settings (
+					key TEXT PRIMARY KEY,
+					value TEXT NOT NULL,
+					updated_at TIMESTAMP NOT NULL
+				)`,
+			Down: `DROP TABLE settings; DROP TABLE audit_logs`,
+		},
+		{
+			Version: 4,
+			Name:    "query_indexes",
+			Up: `
+				CREATE INDEX users_deleted_created_idx ON users (deleted, created_at, id);
+				CREATE INDEX users_role_created_idx ON users (role, created_at);
+				CREATE INDEX users_deleted_at_idx ON users (deleted, deleted_at)`,
+			Down: `
+				DROP INDEX users_deleted_at_idx;
+				DROP INDEX users_role_created_idx;
+				DROP INDEX users_deleted_created_idx`,
+		},
+		{
+			Version: 5,
+			Name:    "create_cache_entries",
+			Up: `
+				CREATE TABLE cache_entries (
+					key TEXT PRIMARY KEY,
+					value TEXT NOT NULL,
+					expires_at TIMESTAMP NOT NULL
+				);
+				CREATE INDEX cache_entries_expires_at_idx ON cache_entries (expires_at)`,
+			Down: `DROP TABLE cache_entries`,
+		},
+		{
+			Version: 6,
+			Name:    "tenants",
+			Up: `
+				CREATE TABLE tenants (
+					id TEXT PRIMARY KEY,
+					name TEXT NOT NULL,
+					status TEXT NOT NULL,
+					settings TEXT NOT NULL DEFAULT '{}',
+					created_at TIMESTAMP NOT NULL,
+					updated_at TIMESTAMP NOT NULL
+				);
+				ALTER TABLE users ADD COLUMN tenant_id TEXT NOT NULL DEFAULT 'default';
+				DROP INDEX users_email_key;
+				DROP INDEX users_username_key;
+				DROP INDEX users_deleted_created_idx;
+				CREATE UNIQUE INDEX users_tenant_email_key ON users (tenant_id, lower(email));
+				CREATE UNIQUE INDEX users_tenant_username_key ON users (tenant_id, lower(username));
+				CREATE INDEX users_tenant_deleted_created_idx ON users (tenant_id, deleted, created_at, id);
+				ALTER TABLE audit_logs ADD COLUMN tenant_id TEXT NOT NULL DEFAULT 'default';
+				CREATE INDEX audit_logs_tenant_subject_email_idx ON audit_logs (tenant_id, subject_email);
+				DROP TABLE sessions;
+				CREATE TABLE sessions (
+					tenant_id TEXT NOT NULL,
+					email TEXT NOT NULL,
+					token TEXT NOT NULL,
+					expires_at TIMESTAMP NOT NULL,
+					PRIMARY KEY (tenant_id, email)
+				);
+				CREATE INDEX sessions_expires_at_idx ON sessions (expires_at)`,
+			// ย้อนกลับได้เฉพาะเมื่ออีเมลและ username ยังไม่ซ้ำกันข้าม tenant
+			Down: `
+				DROP TABLE sessions;
+				CREATE TABLE sessions (
+					email TEXT PRIMARY KEY,
+					token TEXT NOT NULL,
+					expires_at TIMESTAMP NOT NULL
+				);
+				CREATE INDEX sessions_expires_at_idx ON sessions (expires_at);
+				DROP INDEX audit_logs_tenant_subject_email_idx;
+				ALTER TABLE audit_logs DROP COLUMN tenant_id;
+				DROP INDEX users_tenant_deleted_created_idx;
+				DROP INDEX users_tenant_username_key;
+				DROP INDEX users_tenant_email_key;
+				ALTER TABLE users DROP COLUMN tenant_id;
+				CREATE INDEX users_deleted_created_idx ON users (deleted, created_at, id);
+				CREATE UNIQUE INDEX users_email_key ON users (lower(email));
+				CREATE UNIQUE INDEX users_username_key ON users (lower(username));
+				DROP TABLE tenants`,
+		},
+		{
+			Version: 7,
+			Name:    "groups",
+			Up: `
+				CREATE TABLE groups (
+					id CHAR(24) PRIMARY KEY,
+					tenant_id TEXT NOT NULL,
+					name TEXT NOT NULL,
+					description TEXT NOT NULL DEFAULT '',
+					created_at TIMESTAMP NOT NULL,
+					updated_at TIMESTAMP NOT NULL
+				);
+				CREATE UNIQUE INDEX groups_tenant_name_key ON groups (tenant_id, lower(name));
+				CREATE TABLE group_members (
+					group_id CHAR(24) NOT NULL REFERENCES groups (id) ON DELETE CASCADE,
+					user_id CHAR(24) NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+					role TEXT NOT NULL,
+					joined_at TIMESTAMP NOT NULL,
+					PRIMARY KEY (group_id, user_id)
+				);
+				CREATE INDEX group_members_user_idx ON group_members (user_id)`,
+			Down: `
+				DROP TABLE group_members;
+				DROP TABLE groups`,
+		},
+		{
+			Version: 8,
+			Name:    "api_keys",
+			Up: `
+				CREATE TABLE api_keys (
+					id CHAR(24) PRIMARY KEY,
+					tenant_id TEXT NOT NULL,
+					user_id CHAR(24) NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+					name TEXT NOT NULL,
+					prefix TEXT NOT NULL,
+					hash TEXT NOT NULL UNIQUE,
+					scopes TEXT NOT NULL DEFAULT '[]',
+					rate_limit INTEGER NOT NULL,
+					expires_at TIMESTAMP NOT NULL,
+					last_used_at TIMESTAMP,
+					revoked_at TIMESTAMP,
+					created_at TIMESTAMP NOT NULL
+				);
+				CREATE INDEX api_keys_tenant_user_idx ON api_keys (tenant_id, user_id, created_at)`,
+			Down: `DROP TABLE api_keys`,
+		},
+		{
+			Version: 9,
+			Name:    "service_accounts",
+			Up: `
+				CREATE TABLE service_accounts (
+					id CHAR(24) PRIMARY KEY,
+					tenant_id TEXT NOT NULL,
+					name TEXT NOT NULL,
+					description TEXT NOT NULL DEFAULT '',
+					client_id TEXT NOT NULL UNIQUE,
+					role TEXT NOT NULL,
+					scopes TEXT NOT NULL DEFAULT '[]',
+					credentials TEXT NOT NULL DEFAULT '{}',
+					created_at TIMESTAMP NOT NULL,
+					updated_at TIMESTAMP NOT NULL
+				);
+				CREATE UNIQUE INDEX service_accounts_tenant_name_key ON service_accounts (tenant_id, lower(name))`,
+			Down: `DROP TABLE service_accounts`,
+		},
+		{
+			Version: 10,
+			Name:    "oauth_clients",
+			Up: `
+				CREATE TABLE oauth_clients (
+					id CHAR(24) PRIMARY KEY,
+					tenant_id TEXT NOT NULL,
+					client_id TEXT NOT NULL UNIQUE,
+					name TEXT NOT NULL,
+					redirect_uris TEXT NOT NULL DEFAULT '[]',
+					scopes TEXT NOT NULL DEFAULT '[]',
+					public BOOLEAN NOT NULL DEFAULT FALSE,
+					trusted BOOLEAN NOT NULL DEFAULT FALSE,
+					secrets TEXT NOT NULL DEFAULT '[]',
+					created_at TIMESTAMP NOT NULL,
+					updated_at TIMESTAMP NOT NULL
+				);
+				CREATE UNIQUE INDEX oauth_clients_tenant_name_key ON oauth_clients (tenant_id, lower(name));
+				CREATE TABLE oauth_consents (
+					tenant_id TEXT NOT NULL,
+					user_id CHAR(24) NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+					client_id TEXT NOT NULL REFERENCES oauth_clients (client_id) ON DELETE CASCADE,
+					scopes TEXT NOT NULL DEFAULT '[]',
+					grant_id TEXT NOT NULL,
+					created_at TIMESTAMP NOT NULL,
+					updated_at TIMESTAMP NOT NULL,
+					PRIMARY KEY (tenant_id, user_id, client_id)
+				);
+				CREATE INDEX oauth_consents_client_idx ON oauth_consents (client_id)`,
+			Down: `DROP TABLE oauth_consents; DROP TABLE oauth_clients`,
+		},
+		{
+			Version: 11,
+			Name:    "identity_providers",
+			Up: `
+				CREATE TABLE identity_providers (
+					id CHAR(24) PRIMARY KEY,
+					tenant_id TEXT NOT NULL,
+					name TEXT NOT NULL,
+					display_name TEXT NOT NULL DEFAULT '',
+					issuer TEXT NOT NULL,
+					client_id TEXT NOT NULL,
+					client_secret TEXT NOT NULL DEFAULT '',
+					redirect_uri TEXT NOT NULL,
+					scopes TEXT NOT NULL DEFAULT '[]',
+					allow_signup BOOLEAN NOT NULL DEFAULT FALSE,
+					allowed_domains TEXT NOT NULL DEFAULT '[]',
+					created_at TIMESTAMP NOT NULL,
+					updated_at TIMESTAMP NOT NULL
+				);
+				CREATE UNIQUE INDEX identity_providers_tenant_name_key ON identity_providers (tenant_id, lower(name));
+				CREATE TABLE linked_identities (
+					tenant_id TEXT NOT NULL,
+					user_id CHAR(24) NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+					provider_id CHAR(24) NOT NULL REFERENCES identity_providers (id) ON DELETE CASCADE,
+					subject TEXT NOT NULL,
+					email TEXT NOT NULL DEFAULT '',
+					created_at TIMESTAMP NOT NULL,
+					last_login_at TIMESTAMP
+				);
+				CREATE UNIQUE INDEX linked_identities_subject_key ON linked_identities (provider_id, subject);
+				CREATE UNIQUE INDEX linked_identities_user_provider_key ON linked_identities (tenant_id, user_id, provider_id)`,
+			Down: `DROP TABLE linked_identities; DROP TABLE identity_providers`,
+		},
+		{
+			Version: 12,
+			Name:    "directories",
+			Up: `
+				CREATE TABLE directories (
+					id CHAR(24) PRIMARY KEY,
+					tenant_id TEXT NOT NULL,
+					name TEXT NOT NULL,
+					url TEXT NOT NULL,
+					start_tls BOOLEAN NOT NULL DEFAULT FALSE,
+					root_ca TEXT NOT NULL DEFAULT '',
+					bind_dn TEXT NOT NULL DEFAULT '',
+					bind_password TEXT NOT NULL DEFAULT '',
+					user_dn_template TEXT NOT NULL DEFAULT '',
+					base_dn TEXT NOT NULL DEFAULT '',
+					user_filter TEXT NOT NULL DEFAULT '',
+					username_attribute TEXT NOT NULL DEFAULT '',
+					display_name_attribute TEXT NOT NULL DEFAULT '',
+					group_filter TEXT NOT NULL DEFAULT '',
+					role_mappings TEXT NOT NULL DEFAULT '[]',
+					default_role TEXT NOT NULL DEFAULT '',
+					domains TEXT NOT NULL DEFAULT '[]',
+					allow_local_fallback BOOLEAN NOT NULL DEFAULT FALSE,
+					pool_size INTEGER NOT NULL DEFAULT 0,
+					created_at TIMESTAMP NOT NULL,
+					updated_at TIMESTAMP NOT NULL
+				);
+				CREATE UNIQUE INDEX directories_tenant_name_key ON directories (tenant_id, lower(name))`,
+			Down: `DROP TABLE directories`,
+		},
+		{
+			Version: 13,
+			Name:    "saml_identity_providers",
+			Up: `
+				ALTER TABLE identity_providers ADD COLUMN protocol TEXT NOT NULL DEFAULT 'oidc';
+				ALTER TABLE identity_providers ADD COLUMN saml TEXT`,
+			Down: `
+				ALTER TABLE identity_providers DROP COLUMN saml;
+				ALTER TABLE identity_providers DROP COLUMN protocol`,
+		},
+		{
+			Version: 14,
+			Name:    "passkeys",
+			Up: `
+				CREATE TABLE passkeys (
+					id CHAR(24) PRIMARY KEY,
+					tenant_id TEXT NOT NULL,
+					user_id CHAR(24) NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+					name TEXT NOT NULL DEFAULT '',
+					credential_id TEXT NOT NULL,
+					public_key BLOB NOT NULL,
+					algorithm INTEGER NOT NULL,
+					sign_count BIGINT NOT NULL DEFAULT 0,
+					aaguid TEXT NOT NULL DEFAULT '',
+					attestation_format TEXT NOT NULL DEFAULT '',
+					transports TEXT NOT NULL DEFAULT '[]',
+					backup_eligible BOOLEAN NOT NULL DEFAULT FALSE,
+					backed_up BOOLEAN NOT NULL DEFAULT FALSE,
+					created_at TIMESTAMP NOT NULL,
+					last_used_at TIMESTAMP
+				);
+				CREATE UNIQUE INDEX passkeys_tenant_credential_key ON passkeys (tenant_id, credential_id);
+				CREATE INDEX passkeys_user_idx ON passkeys (tenant_id, user_id);
+				ALTER TABLE users ADD COLUMN passkey_required BOOLEAN NOT NULL DEFAULT FALSE`,
+			Down: `
+				ALTER TABLE users DROP COLUMN passkey_required;
+				DROP TABLE passkeys`,
+		},
+		{
+			Version: 15,
+			Name:    "webhooks",
+			Up: `
+				CREATE TABLE webhooks (
+					id CHAR(24) PRIMARY KEY,
+					tenant_id TEXT NOT NULL,
+					url TEXT NOT NULL,
+					secret TEXT NOT NULL,
+					events TEXT NOT NULL DEFAULT '[]',
+					description TEXT NOT NULL DEFAULT '',
+					created_at TIMESTAMP NOT NULL
+				);
+				CREATE INDEX webhooks_tenant_idx ON webhooks (tenant_id, created_at);
+				CREATE TABLE webhook_deliveries (
+					id CHAR(24) PRIMARY KEY,
+					tenant_id TEXT NOT NULL,
+					webhook_id CHAR(24) NOT NULL REFERENCES webhooks (id) ON DELETE CASCADE,
+					event_id TEXT NOT NULL,
+					event_type TEXT NOT NULL,
+					payload TEXT NOT NULL,
+					status TEXT NOT NULL,
+					attempts INTEGER NOT NULL DEFAULT 0,
+					next_attempt_at TIMESTAMP NOT NULL,
+					last_error TEXT NOT NULL DEFAULT '',
+					response_status INTEGER NOT NULL DEFAULT 0,
+					created_at TIMESTAMP NOT NULL,
+					delivered_at TIMESTAMP
+				);
+				CREATE INDEX webhook_deliveries_due_idx ON webhook_deliveries (status, next_attempt_at);
+				CREATE INDEX webhook_deliveries_tenant_idx ON webhook_deliveries (tenant_id, id);
+				CREATE INDEX webhook_deliveries_webhook_idx ON webhook_deliveries (webhook_id)`,
+			Down: `
+				DROP TABLE webhook_deliveries;
+				DROP TABLE webhooks`,
+		},
+		{
+			Version: 16,
+			Name:    "outbox",
+			Up: `
+				CREATE TABLE outbox (
+					id CHAR(24) PRIMARY KEY,
+					tenant_id TEXT NOT NULL,
+					type TEXT NOT NULL,
+					aggregate_id TEXT NOT NULL DEFAULT '',
+					payload TEXT NOT NULL,
+					created_at TIMESTAMP NOT NULL,
+					published_at TIMESTAMP,
+					attempts INTEGER NOT NULL DEFAULT 0,
+					next_attempt_at TIMESTAMP NOT NULL,
+					last_error TEXT NOT NULL DEFAULT ''
+				);
+				CREATE INDEX outbox_pending_idx ON outbox (published_at, next_attempt_at)`,
+			Down: `DROP TABLE outbox`,
+		},
+		{
+			Version: 17,
+			Name:    "outbox tenant index",
+			Up:      `CREATE INDEX outbox_tenant_idx ON outbox (tenant_id, id)`,
+			Down:    `DROP INDEX outbox_tenant_idx`,
+		},
+		{
+			Version: 18,
+			Name:    "audit tenant index",
+			Up:      `CREATE INDEX audit_logs_tenant_idx ON audit_logs (tenant_id, id)`,
+			Down:    `DROP INDEX audit_logs_tenant_idx`,
+		},
+	},
+}
@@ -0,0 +1,206 @@
+// Package sqlstore เก็บข้อมูลของ service ในฐานข้อมูล SQL ผ่าน database/sql
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"strings"
+	"time"
+
+	"auth-microservice/internal/migrate"
+	"auth-microservice/internal/store"
+)
+
+// Dialect รวมความแตกต่างของ SQL ระหว่างฐานข้อมูลแต่ละชนิด
+type Dialect struct {
+	Name         string
+	DriverName   string // ชื่อ driver ที่ลงทะเบียนกับ database/sql
+	Numbered     bool   // placeholder แบบ $1, $2 (ถ้า false ใช้ ?)
+	ForUpdate    string // ส่วนท้ายของ SELECT เพื่อล็อกแถวใน transaction ("" ถ้าไม่รองรับ)
+	MaxOpenConns int    // จำนวนการเชื่อมต่อสูงสุด (0 = ไม่จำกัด)
+
+	// ชื่อ unique constraint ที่ทำให้ insert/update ล้มเหลว (ok = false ถ้าไม่ใช่ error ประเภทนี้)
+	UniqueViolation func(err error) (constraint string, ok bool)
+	// migration ของ schema ทั้งหมด เรียงตามเวอร์ชัน
+	Migrations []SchemaMigration
+}
+
+// SchemaMigration หนึ่งเวอร์ชันของ schema เป็นคำสั่ง SQL (คั่นหลายคำสั่งด้วย ;)
+type SchemaMigration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Store เก็บข้อมูลทั้งหมด (tenant, ผู้ใช้, blacklist, session, ข้อมูลชั่วคราว, audit log และการตั้งค่า) ในฐานข้อมูลเดียว
+type Store struct {
+	DB      *sql.DB
+	Dialect *Dialect
+}
+
+// เปิดการเชื่อมต่อฐานข้อมูลและตรวจสอบด้วย ping
+func Open(ctx context.Context, dialect *Dialect, dsn string) (*Store, error) {
+	db, err := sql.Open(dialect.DriverName, dsn)
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(dialect.MaxOpenConns)
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &Store{DB: db, Dialect: dialect}, nil
+}
+
+// รวม store ทั้งหมดเพื่อส่งให้ service
+func (s *Store) Stores() *store.Stores {
+	return &store.Stores{
+		Tenants:         s,
+		Users:           s,
+		Groups:          s,
+		APIKeys:         s,
+		ServiceAccounts: s,
+		OAuthClients:    s,
+		Identities:      s,
+		Webhooks:        s,
+		Outbox:          s,
+		Blacklist:       s,
+		Sessions:        s,
+		Cache:           s,
+		Audit:           s,
+		Settings:        s,
+		Close: func(context.Context) error {
+			return s.DB.Close()
+		},
+	}
+}
+
+// สร้าง Migrator สำหรับ schema ของ dialect นี้
+func (s *Store) Migrator() *migrate.Migrator {
+	migrations := make([]migrate.Migration, 0, len(s.Dialect.Migrations))
+	for _, m := range s.Dialect.Migrations {
+		m := m
+		migrations = append(migrations, migrate.Migration{
+			Version: m.Version,
+			Name:    m.Name,
+			Up:      func(ctx context.Context) error { return s.execScript(ctx, m.Up) },
+			Down:    func(ctx context.Context) error { return s.execScript(ctx, m.Down) },
+		})
+	}
+	return migrate.NewMigrator(s, migrations)
+}
+
+// รันหลายคำสั่ง SQL ใน transaction เดียว
+func (s *Store) execScript(ctx context.Context, script string) error {
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		for _, stmt := range strings.Split(script, ";") {
+			if strings.TrimSpace(stmt) == "" {
+				continue
+			}
+			if _, err := tx.ExecContext(ctx, stmt); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// รัน fn ใน transaction แล้ว commit ถ้าไม่มี error
+func (s *Store) inTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// แปลง placeholder ? เป็นรูปแบบของ dialect (query ในแพ็กเกจนี้ห้ามมี ? ในข้อความอื่น)
+func (s *Store) rebind(query string) string {
+	if !s.Dialect.Numbered {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// แปลง unique violation เป็น DuplicateError ตามชื่อ constraint
+func (s *Store) mapError(err error) error {
+	constraint, ok := s.Dialect.UniqueViolation(err)
+	if !ok {
+		return err
+	}
+	switch {
+	case strings.Contains(constraint, "email"):
+		return &store.DuplicateError{Field: "email"}
+	case strings.Contains(constraint, "username"):
+		return &store.DuplicateError{Field: "username"}
+	}
+	return &store.DuplicateError{}
+}
+
+// คืน ErrNotFound ถ้าคำสั่งไม่มีผลกับแถวใดเลย
+func rowsAffected(result sql.Result, err error) error {
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+// ======== migrate.Journal ========
+
+func (s *Store) Applied(ctx context.Context) (map[int]time.Time, error) {
+	// สร้างตารางบันทึก migration ถ้ายังไม่มี (ตารางนี้ต้องมีก่อน migration แรก)
+	_, err := s.DB.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS `+migrate.CollectionName+` (
+		version INTEGER PRIMARY KEY,
+		name TEXT NOT NULL,
+		applied_at TIMESTAMP NOT NULL
+	)`)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.DB.QueryContext(ctx, `SELECT version, applied_at FROM `+migrate.CollectionName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := map[int]time.Time{}
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = appliedAt
+	}
+	return applied, rows.Err()
+}
+
+func (s *Store) Record(ctx context.Context, version int, name string, appliedAt time.Time) error {
+	_, err := s.DB.ExecContext(ctx, s.rebind(`INSERT INTO `+migrate.CollectionName+` (version, name, applied_at) VALUES (?, ?, ?)`), version, name, appliedAt.UTC())
+	return err
+}
+
+func (s *Store) Remove(ctx context.Context, version int) error {
+	_, err := s.DB.ExecContext(ctx, s.rebind(`DELETE FROM `+migrate.CollectionName+` WHERE version = ?`), version)
+	return err
+}
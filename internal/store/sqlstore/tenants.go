@@ -0,0 +1,90 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/store"
+)
+
+// การตั้งค่าของ tenant ที่เก็บเป็น JSON ในคอลัมน์ settings
+type tenantSettings struct {
+	PasswordPolicy models.PasswordPolicy `json:"passwordPolicy"`
+	AccessTokenTTL time.Duration         `json:"accessTokenTtl"`
+	SessionTTL     time.Duration         `json:"sessionTtl"`
+	SigningKeys    []models.SigningKey   `json:"signingKeys"`
+}
+
+// ======== TenantStore ========
+
+func (s *Store) CreateTenant(ctx context.Context, t *models.Tenant) error {
+	_, err := s.DB.ExecContext(ctx, s.rebind(`INSERT INTO tenants (id, name, status, settings, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)`),
+		t.ID, t.Name, t.Status, marshalTenantSettings(t), t.CreatedAt.UTC(), t.UpdatedAt.UTC())
+	if _, ok := s.Dialect.UniqueViolation(err); ok {
+		return &store.DuplicateError{Field: "id"}
+	}
+	return err
+}
+
+func (s *Store) GetTenant(ctx context.Context, id string) (*models.Tenant, error) {
+	t, err := scanTenant(s.DB.QueryRowContext(ctx, s.rebind(`SELECT id, name, status, settings, created_at, updated_at FROM tenants WHERE id = ?`), id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, store.ErrNotFound
+	}
+	return t, err
+}
+
+func (s *Store) ListTenants(ctx context.Context) ([]models.Tenant, error) {
+	rows, err := s.DB.QueryContext(ctx, `SELECT id, name, status, settings, created_at, updated_at FROM tenants ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tenants []models.Tenant
+	for rows.Next() {
+		t, err := scanTenant(rows)
+		if err != nil {
+			return nil, err
+		}
+		tenants = append(tenants, *t)
+	}
+	return tenants, rows.Err()
+}
+
+func (s *Store) SaveTenant(ctx context.Context, t *models.Tenant) error {
+	_, err := s.DB.ExecContext(ctx, s.rebind(`INSERT INTO tenants (id, name, status, settings, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET name = excluded.name, status = excluded.status, settings = excluded.settings, updated_at = excluded.updated_at`),
+		t.ID, t.Name, t.Status, marshalTenantSettings(t), t.CreatedAt.UTC(), t.UpdatedAt.UTC())
+	return err
+}
+
+func scanTenant(row rowScanner) (*models.Tenant, error) {
+	var t models.Tenant
+	var settingsJSON string
+	if err := row.Scan(&t.ID, &t.Name, &t.Status, &settingsJSON, &t.CreatedAt, &t.UpdatedAt); err != nil {
+		return nil, err
+	}
+	var settings tenantSettings
+	if err := json.Unmarshal([]byte(settingsJSON), &settings); err != nil {
+		return nil, err
+	}
+	t.PasswordPolicy = settings.PasswordPolicy
+	t.AccessTokenTTL = settings.AccessTokenTTL
+	t.SessionTTL = settings.SessionTTL
+	t.SigningKeys = settings.SigningKeys
+	return &t, nil
+}
+
+func marshalTenantSettings(t *models.Tenant) string {
+	return marshalJSON(tenantSettings{
+		PasswordPolicy: t.PasswordPolicy,
+		AccessTokenTTL: t.AccessTokenTTL,
+		SessionTTL:     t.SessionTTL,
+		SigningKeys:    t.SigningKeys,
+	})
+}
@@ -0,0 +1,57 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// ======== BlacklistStore ========
+
+func (s *Store) AddToken(ctx context.Context, token string, expiresAt time.Time) error {
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		return s.addToken(ctx, tx, token, expiresAt)
+	})
+}
+
+// เพิ่ม token ใน blacklist ภายใน transaction ที่ส่งเข้ามา
+func (s *Store) addToken(ctx context.Context, tx *sql.Tx, token string, expiresAt time.Time) error {
+	// SQL ไม่มี TTL index จึงลบ token ที่หมดอายุแล้วทิ้งทุกครั้งที่เพิ่ม token ใหม่
+	if _, err := tx.ExecContext(ctx, s.rebind(`DELETE FROM blacklisted_tokens WHERE expires_at < ?`), time.Now().UTC()); err != nil {
+		return err
+	}
+	_, err := tx.ExecContext(ctx, s.rebind(`INSERT INTO blacklisted_tokens (token, expires_at) VALUES (?, ?) ON CONFLICT (token) DO NOTHING`),
+		token, expiresAt.UTC())
+	return err
+}
+
+func (s *Store) IsBlacklisted(ctx context.Context, token string) (bool, error) {
+	return s.exists(ctx, `SELECT 1 FROM blacklisted_tokens WHERE token = ?`, token)
+}
+
+// ======== SessionStore ========
+
+func (s *Store) GetActiveToken(ctx context.Context, tenantID string, email string) (string, error) {
+	var token string
+	err := s.DB.QueryRowContext(ctx, s.rebind(`SELECT token FROM sessions WHERE tenant_id = ? AND email = ? AND expires_at > ?`), tenantID, email, time.Now().UTC()).Scan(&token)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	return token, err
+}
+
+func (s *Store) SetActiveToken(ctx context.Context, tenantID string, email string, token string, ttl time.Duration) error {
+	if _, err := s.DB.ExecContext(ctx, s.rebind(`DELETE FROM sessions WHERE expires_at < ?`), time.Now().UTC()); err != nil {
+		return err
+	}
+	_, err := s.DB.ExecContext(ctx, s.rebind(`INSERT INTO sessions (tenant_id, email, token, expires_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT (tenant_id, email) DO UPDATE SET token = excluded.token, expires_at = excluded.expires_at`),
+		tenantID, email, token, time.Now().Add(ttl).UTC())
+	return err
+}
+
+func (s *Store) DeleteActiveToken(ctx context.Context, tenantID string, email string) error {
+	_, err := s.DB.ExecContext(ctx, s.rebind(`DELETE FROM sessions WHERE tenant_id = ? AND email = ?`), tenantID, email)
+	return err
+}
@@ -0,0 +1,463 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/search"
+	"auth-microservice/internal/store"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// คอลัมน์ของตาราง users ตามลำดับที่ scanUser อ่าน
+const userColumns = `id, tenant_id, email, username, password, password_history, password_changed_at, role, email_verified,
+	display_name, avatar_url, locale, timezone, phone, metadata, deleted, deleted_at, created_at, updated_at, passkey_required`
+
+// คอลัมน์ที่ใช้เรียงลำดับในแต่ละ field (ข้อความเรียงแบบไม่สนตัวพิมพ์)
+// ค่าที่นำไปเทียบกับ lower(...) จะถูกแปลงเป็นตัวพิมพ์เล็กฝั่ง Go ก่อนส่งเป็น parameter
+var sortColumns = map[string]string{
+	"createdAt": "created_at",
+	"updatedAt": "updated_at",
+	"username":  "lower(username)",
+	"email":     "lower(email)",
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanUser(row rowScanner) (*models.User, error) {
+	var (
+		u                 models.User
+		id                string
+		history, metadata string
+		passwordChangedAt sql.NullTime
+		deletedAt         sql.NullTime
+	)
+	err := row.Scan(&id, &u.TenantID, &u.Email, &u.Username, &u.Password, &history, &passwordChangedAt, &u.Role, &u.EmailVerified,
+		&u.DisplayName, &u.AvatarURL, &u.Locale, &u.Timezone, &u.Phone, &metadata, &u.Deleted, &deletedAt, &u.CreatedAt, &u.UpdatedAt, &u.PasskeyRequired)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, store.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if u.ID, err = primitive.ObjectIDFromHex(id); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(history), &u.PasswordHistory); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(metadata), &u.Metadata); err != nil {
+		return nil, err
+	}
+	if len(u.Metadata) == 0 {
+		u.Metadata = nil
+	}
+	if passwordChangedAt.Valid {
+		u.PasswordChangedAt = &passwordChangedAt.Time
+	}
+	if deletedAt.Valid {
+		u.DeletedAt = &deletedAt.Time
+	}
+	return &u, nil
+}
+
+// ค่า NULL สำหรับเวลาที่ไม่ได้กำหนด
+func nullTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: t.UTC(), Valid: true}
+}
+
+func marshalJSON(v interface{}) string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+func (s *Store) CreateUser(ctx context.Context, u *models.User) error {
+	// ตรวจสอบและ insert ใน transaction เดียว unique index เป็นตัวกันข้อมูลซ้ำขั้นสุดท้าย
+	// เมื่อมี request พร้อมกัน (ได้ DuplicateError จาก unique violation)
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		return s.createUser(ctx, tx, u)
+	})
+}
+
+// สร้างผู้ใช้ภายใน transaction ที่ส่งเข้ามา
+func (s *Store) createUser(ctx context.Context, tx *sql.Tx, u *models.User) error {
+	u.ID = primitive.NewObjectID()
+	if u.PasswordHistory == nil {
+		u.PasswordHistory = []string{}
+	}
+	metadata := u.Metadata
+	if metadata == nil {
+		metadata = map[string]string{}
+	}
+
+	var email, username string
+	err := tx.QueryRowContext(ctx, s.rebind(`SELECT email, username FROM users WHERE tenant_id = ? AND (lower(email) = ? OR lower(username) = ?)`),
+		u.TenantID, strings.ToLower(u.Email), strings.ToLower(u.Username)).Scan(&email, &username)
+	if err == nil {
+		if strings.EqualFold(email, u.Email) {
+			return &store.DuplicateError{Field: "email"}
+		}
+		return &store.DuplicateError{Field: "username"}
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, s.rebind(`INSERT INTO users (`+userColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`),
+		u.ID.Hex(), u.TenantID, u.Email, u.Username, u.Password, marshalJSON(u.PasswordHistory), nullTime(u.PasswordChangedAt), u.Role, u.EmailVerified,
+		u.DisplayName, u.AvatarURL, u.Locale, u.Timezone, u.Phone, marshalJSON(metadata), u.Deleted, nullTime(u.DeletedAt), u.CreatedAt.UTC(), u.UpdatedAt.UTC(), u.PasskeyRequired)
+	return s.mapError(err)
+}
+
+func (s *Store) GetUserByID(ctx context.Context, id string, includeDeleted bool) (*models.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE id = ?`
+	if !includeDeleted {
+		query += ` AND deleted = ?`
+		return scanUser(s.DB.QueryRowContext(ctx, s.rebind(query), id, false))
+	}
+	return scanUser(s.DB.QueryRowContext(ctx, s.rebind(query), id))
+}
+
+func (s *Store) GetUserByEmail(ctx context.Context, tenantID string, email string) (*models.User, error) {
+	return scanUser(s.DB.QueryRowContext(ctx, s.rebind(`SELECT `+userColumns+` FROM users WHERE tenant_id = ? AND email = ? AND deleted = ?`), tenantID, email, false))
+}
+
+func (s *Store) EmailTaken(ctx context.Context, tenantID string, email string) (bool, error) {
+	return s.exists(ctx, `SELECT 1 FROM users WHERE tenant_id = ? AND lower(email) = ?`, tenantID, strings.ToLower(email))
+}
+
+func (s *Store) UsernameTaken(ctx context.Context, tenantID string, username string) (bool, error) {
+	return s.exists(ctx, `SELECT 1 FROM users WHERE tenant_id = ? AND lower(username) = ?`, tenantID, strings.ToLower(username))
+}
+
+func (s *Store) exists(ctx context.Context, query string, args ...interface{}) (bool, error) {
+	var one int
+	err := s.DB.QueryRowContext(ctx, s.rebind(query), args...).Scan(&one)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (s *Store) UpdateProfile(ctx context.Context, id string, patch store.ProfilePatch, updatedAt time.Time) error {
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		return s.updateProfile(ctx, tx, id, patch, updatedAt)
+	})
+}
+
+// แก้ไขโปรไฟล์ภายใน transaction ที่ส่งเข้ามา
+func (s *Store) updateProfile(ctx context.Context, tx *sql.Tx, id string, patch store.ProfilePatch, updatedAt time.Time) error {
+	var metadataJSON string
+	err := tx.QueryRowContext(ctx, s.rebind(`SELECT metadata FROM users WHERE id = ?`+s.Dialect.ForUpdate), id).Scan(&metadataJSON)
+	if errors.Is(err, sql.ErrNoRows) {
+		return store.ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	sets := []string{"updated_at = ?"}
+	args := []interface{}{updatedAt.UTC()}
+	for column, value := range map[string]*string{
+		"username":     patch.Username,
+		"display_name": patch.DisplayName,
+		"avatar_url":   patch.AvatarURL,
+		"locale":       patch.Locale,
+		"timezone":     patch.Timezone,
+		"phone":        patch.Phone,
+		"role":         patch.Role,
+	} {
+		if value != nil {
+			sets = append(sets, column+" = ?")
+			args = append(args, *value)
+		}
+	}
+	if patch.PasskeyRequired != nil {
+		sets = append(sets, "passkey_required = ?")
+		args = append(args, *patch.PasskeyRequired)
+	}
+
+	if patch.ReplaceMetadata || len(patch.Metadata) > 0 {
+		metadata := map[string]string{}
+		if !patch.ReplaceMetadata {
+			// แก้ไขเฉพาะ key ที่ระบุ โดยไม่กระทบ key อื่น
+			if err := json.Unmarshal([]byte(metadataJSON), &metadata); err != nil {
+				return err
+			}
+		}
+		for key, value := range patch.Metadata {
+			if value == "" {
+				delete(metadata, key)
+			} else {
+				metadata[key] = value
+			}
+		}
+		sets = append(sets, "metadata = ?")
+		args = append(args, marshalJSON(metadata))
+	}
+
+	args = append(args, id)
+	_, err = tx.ExecContext(ctx, s.rebind(`UPDATE users SET `+strings.Join(sets, ", ")+` WHERE id = ?`), args...)
+	return s.mapError(err)
+}
+
+func (s *Store) UpdatePassword(ctx context.Context, id string, oldHash string, newHash string, historyLimit int, changedAt time.Time) error {
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		var historyJSON string
+		err := tx.QueryRowContext(ctx, s.rebind(`SELECT password_history FROM users WHERE id = ? AND deleted = ?`+s.Dialect.ForUpdate), id, false).Scan(&historyJSON)
+		if errors.Is(err, sql.ErrNoRows) {
+			return store.ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		// เก็บ hash เดิมไว้ในประวัติ เฉพาะ historyLimit รายการล่าสุด
+		var history []string
+		if err := json.Unmarshal([]byte(historyJSON), &history); err != nil {
+			return err
+		}
+		history = append(history, oldHash)
+		if len(history) > historyLimit {
+			history = history[len(history)-historyLimit:]
+		}
+
+		_, err = tx.ExecContext(ctx, s.rebind(`UPDATE users SET password = ?, password_history = ?, password_changed_at = ?, updated_at = ? WHERE id = ?`),
+			newHash, marshalJSON(history), changedAt.UTC(), changedAt.UTC(), id)
+		return err
+	})
+}
+
+func (s *Store) UpdateEmail(ctx context.Context, tenantID string, from string, to string, updatedAt time.Time) (*models.User, error) {
+	return s.modifyUser(ctx, userModification{`tenant_id = ? AND email = ? AND deleted = ?`, []interface{}{tenantID, from, false},
+		`email = ?, email_verified = ?, updated_at = ?`, []interface{}{to, true, updatedAt.UTC()}})
+}
+
+func (s *Store) SoftDeleteUser(ctx context.Context, id string, deletedAt time.Time) (*models.User, error) {
+	return s.modifyUser(ctx, softDeleteUser(id, deletedAt))
+}
+
+func (s *Store) RestoreUser(ctx context.Context, id string, updatedAt time.Time) (*models.User, error) {
+	return s.modifyUser(ctx, restoreUser(id, updatedAt))
+}
+
+// เงื่อนไขและค่าที่ใช้ใน modifyUser
+type userModification struct {
+	where     string
+	whereArgs []interface{}
+	set       string
+	setArgs   []interface{}
+}
+
+func softDeleteUser(id string, deletedAt time.Time) userModification {
+	return userModification{`id = ? AND deleted = ?`, []interface{}{id, false},
+		`deleted = ?, deleted_at = ?`, []interface{}{true, deletedAt.UTC()}}
+}
+
+func restoreUser(id string, updatedAt time.Time) userModification {
+	return userModification{`id = ? AND deleted = ?`, []interface{}{id, true},
+		`deleted = ?, deleted_at = NULL, updated_at = ?`, []interface{}{false, updatedAt.UTC()}}
+}
+
+// อัปเดตผู้ใช้หนึ่งคนที่ตรงกับ where แล้วคืนข้อมูลก่อนอัปเดต
+func (s *Store) modifyUser(ctx context.Context, m userModification) (*models.User, error) {
+	var user *models.User
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		user, err = s.modifyUserTx(ctx, tx, m)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// modifyUser ภายใน transaction ที่ส่งเข้ามา
+func (s *Store) modifyUserTx(ctx context.Context, tx *sql.Tx, m userModification) (*models.User, error) {
+	user, err := scanUser(tx.QueryRowContext(ctx, s.rebind(`SELECT `+userColumns+` FROM users WHERE `+m.where+s.Dialect.ForUpdate), m.whereArgs...))
+	if err != nil {
+		return nil, err
+	}
+	_, err = tx.ExecContext(ctx, s.rebind(`UPDATE users SET `+m.set+` WHERE id = ?`), append(m.setArgs, user.ID.Hex())...)
+	if err != nil {
+		return nil, s.mapError(err)
+	}
+	return user, nil
+}
+
+func (s *Store) DeleteUser(ctx context.Context, id string) error {
+	result, err := s.DB.ExecContext(ctx, s.rebind(`DELETE FROM users WHERE id = ?`), id)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (s *Store) ListDeletedBefore(ctx context.Context, before time.Time) ([]string, error) {
+	rows, err := s.DB.QueryContext(ctx, s.rebind(`SELECT id FROM users WHERE deleted = ? AND deleted_at < ?`), true, before.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func (s *Store) ListUsers(ctx context.Context, q store.UserListQuery) ([]models.User, error) {
+	where, args, err := userWhere(q.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	column, ok := sortColumns[q.Order.Field]
+	if !ok {
+		column = sortColumns["createdAt"]
+	}
+	dir, op := "ASC", ">"
+	if q.Order.Desc {
+		dir, op = "DESC", "<"
+	}
+
+	if q.After != nil {
+		// cursor-based pagination: ต่อจากรายการสุดท้ายของหน้าก่อน (ใช้ id ตัดสินเมื่อค่าเท่ากัน)
+		cursorValue := q.After.Value
+		switch v := cursorValue.(type) {
+		case time.Time:
+			cursorValue = v.UTC()
+		case string:
+			cursorValue = strings.ToLower(v)
+		}
+		where = append(where, "("+column+" "+op+" ? OR ("+column+" = ? AND id "+op+" ?))")
+		args = append(args, cursorValue, cursorValue, q.After.ID)
+	}
+
+	query := `SELECT ` + userColumns + ` FROM users`
+	if len(where) > 0 {
+		query += ` WHERE ` + strings.Join(where, " AND ")
+	}
+	query += ` ORDER BY ` + column + ` ` + dir + `, id ` + dir + ` LIMIT ?`
+	args = append(args, q.Limit)
+	if q.After == nil && q.Skip > 0 {
+		query += ` OFFSET ?`
+		args = append(args, q.Skip)
+	}
+
+	rows, err := s.DB.QueryContext(ctx, s.rebind(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []models.User
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, *u)
+	}
+	return users, rows.Err()
+}
+
+// นับจำนวนจริงเสมอ (SQL ไม่มีค่าประมาณที่เชื่อถือได้แบบ EstimatedDocumentCount)
+func (s *Store) CountUsers(ctx context.Context, q search.UserQuery, allowEstimate bool) (int64, bool, error) {
+	where, args, err := userWhere(q)
+	if err != nil {
+		return 0, false, err
+	}
+	query := `SELECT COUNT(*) FROM users`
+	if len(where) > 0 {
+		query += ` WHERE ` + strings.Join(where, " AND ")
+	}
+	var total int64
+	err = s.DB.QueryRowContext(ctx, s.rebind(query), args...).Scan(&total)
+	return total, false, err
+}
+
+// สร้างเงื่อนไข WHERE จากเงื่อนไขค้นหา (ค่าจากผู้ใช้ส่งเป็น parameter เสมอ)
+func userWhere(q search.UserQuery) ([]string, []interface{}, error) {
+	if err := q.Validate(); err != nil {
+		return nil, nil, err
+	}
+	var where []string
+	var args []interface{}
+
+	if q.TenantID != "" {
+		where = append(where, "tenant_id = ?")
+		args = append(args, q.TenantID)
+	}
+
+	switch q.Deleted {
+	case search.DeletedInclude:
+	case search.DeletedOnly:
+		where = append(where, "deleted = ?")
+		args = append(args, true)
+	default:
+		where = append(where, "deleted = ?")
+		args = append(args, false)
+	}
+
+	for column, term := range map[string]string{"username": q.Username, "email": q.Email} {
+		if term == "" {
+			continue
+		}
+		switch q.Mode {
+		case search.MatchExact:
+			where = append(where, "lower("+column+") = ?")
+			args = append(args, strings.ToLower(term))
+		case search.MatchContains:
+			where = append(where, "lower("+column+`) LIKE ? ESCAPE '\'`)
+			args = append(args, "%"+escapeLike(strings.ToLower(term))+"%")
+		default:
+			where = append(where, "lower("+column+`) LIKE ? ESCAPE '\'`)
+			args = append(args, escapeLike(strings.ToLower(term))+"%")
+		}
+	}
+
+	if q.Role != "" {
+		where = append(where, "role = ?")
+		args = append(args, q.Role)
+	}
+	if q.CreatedAfter != nil {
+		where = append(where, "created_at >= ?")
+		args = append(args, q.CreatedAfter.UTC())
+	}
+	if q.CreatedBefore != nil {
+		where = append(where, "created_at < ?")
+		args = append(args, q.CreatedBefore.UTC())
+	}
+	if q.Verified != nil {
+		where = append(where, "email_verified = ?")
+		args = append(args, *q.Verified)
+	}
+	return where, args, nil
+}
+
+// escape อักขระพิเศษของ LIKE เพื่อให้คำค้นหาถูกเทียบตามตัวอักษร
+func escapeLike(term string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(term)
+}
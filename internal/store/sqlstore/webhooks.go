@@ -0,0 +1,211 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/store"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ======== WebhookStore ========
+
+const webhookColumns = `id, tenant_id, url, secret, events, description, created_at`
+
+const deliveryColumns = `id, tenant_id, webhook_id, event_id, event_type, payload, status, attempts, next_attempt_at,
+	last_error, response_status, created_at, delivered_at`
+
+func (s *Store) CreateWebhook(ctx context.Context, w *models.Webhook) error {
+	w.ID = primitive.NewObjectID()
+	_, err := s.DB.ExecContext(ctx, s.rebind(`INSERT INTO webhooks (`+webhookColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?)`),
+		w.ID.Hex(), w.TenantID, w.URL, w.Secret, marshalJSON(w.Events), w.Description, w.CreatedAt.UTC())
+	return err
+}
+
+func (s *Store) GetWebhook(ctx context.Context, tenantID string, id string) (*models.Webhook, error) {
+	w, err := scanWebhook(s.DB.QueryRowContext(ctx, s.rebind(`SELECT `+webhookColumns+` FROM webhooks WHERE id = ? AND tenant_id = ?`), id, tenantID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, store.ErrNotFound
+	}
+	return w, err
+}
+
+func (s *Store) ListWebhooks(ctx context.Context, tenantID string) ([]models.Webhook, error) {
+	rows, err := s.DB.QueryContext(ctx, s.rebind(`SELECT `+webhookColumns+` FROM webhooks WHERE tenant_id = ? ORDER BY created_at, id`), tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	webhooks := []models.Webhook{}
+	for rows.Next() {
+		w, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, *w)
+	}
+	return webhooks, rows.Err()
+}
+
+func (s *Store) DeleteWebhook(ctx context.Context, tenantID string, id string) error {
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, s.rebind(`DELETE FROM webhooks WHERE id = ? AND tenant_id = ?`), id, tenantID)
+		if err := rowsAffected(res, err); err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, s.rebind(`DELETE FROM webhook_deliveries WHERE webhook_id = ?`), id)
+		return err
+	})
+}
+
+func (s *Store) EnqueueDeliveries(ctx context.Context, deliveries []*models.WebhookDelivery) error {
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		for _, d := range deliveries {
+			d.ID = primitive.NewObjectID()
+			_, err := tx.ExecContext(ctx, s.rebind(`INSERT INTO webhook_deliveries (`+deliveryColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`),
+				d.ID.Hex(), d.TenantID, d.WebhookID.Hex(), d.EventID, d.EventType, d.Payload, d.Status, d.Attempts,
+				d.NextAttemptAt.UTC(), d.LastError, d.ResponseStatus, d.CreatedAt.UTC(), nullTime(d.DeliveredAt))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *Store) ClaimDueDeliveries(ctx context.Context, now time.Time, leaseUntil time.Time, limit int) ([]models.WebhookDelivery, error) {
+	rows, err := s.DB.QueryContext(ctx, s.rebind(`SELECT id FROM webhook_deliveries WHERE status = ? AND next_attempt_at <= ?
+		ORDER BY next_attempt_at LIMIT ?`), models.DeliveryPending, now.UTC(), limit)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// จองด้วยเงื่อนไขเดิม ถ้า instance อื่นจองไปก่อนแล้วจะไม่มีแถวถูกแก้
+	claimed := []models.WebhookDelivery{}
+	for _, id := range ids {
+		res, err := s.DB.ExecContext(ctx, s.rebind(`UPDATE webhook_deliveries SET next_attempt_at = ? WHERE id = ? AND status = ? AND next_attempt_at <= ?`),
+			leaseUntil.UTC(), id, models.DeliveryPending, now.UTC())
+		if err := rowsAffected(res, err); errors.Is(err, store.ErrNotFound) {
+			continue
+		} else if err != nil {
+			return claimed, err
+		}
+		d, err := s.getDelivery(ctx, `id = ?`, id)
+		if err != nil {
+			return claimed, err
+		}
+		claimed = append(claimed, *d)
+	}
+	return claimed, nil
+}
+
+func (s *Store) SaveDeliveryAttempt(ctx context.Context, d *models.WebhookDelivery) error {
+	res, err := s.DB.ExecContext(ctx, s.rebind(`UPDATE webhook_deliveries SET status = ?, attempts = ?, next_attempt_at = ?, last_error = ?,
+		response_status = ?, delivered_at = ? WHERE id = ?`),
+		d.Status, d.Attempts, d.NextAttemptAt.UTC(), d.LastError, d.ResponseStatus, nullTime(d.DeliveredAt), d.ID.Hex())
+	return rowsAffected(res, err)
+}
+
+func (s *Store) GetDelivery(ctx context.Context, tenantID string, id string) (*models.WebhookDelivery, error) {
+	return s.getDelivery(ctx, `id = ? AND tenant_id = ?`, id, tenantID)
+}
+
+func (s *Store) ListDeliveries(ctx context.Context, q store.DeliveryListQuery) ([]models.WebhookDelivery, error) {
+	query := `SELECT ` + deliveryColumns + ` FROM webhook_deliveries WHERE tenant_id = ?`
+	args := []interface{}{q.TenantID}
+	if q.WebhookID != "" {
+		query += ` AND webhook_id = ?`
+		args = append(args, q.WebhookID)
+	}
+	if q.Status != "" {
+		query += ` AND status = ?`
+		args = append(args, q.Status)
+	}
+	if q.BeforeID != "" {
+		query += ` AND id < ?`
+		args = append(args, q.BeforeID)
+	}
+	rows, err := s.DB.QueryContext(ctx, s.rebind(query+` ORDER BY id DESC LIMIT ?`), append(args, q.Limit)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []models.WebhookDelivery{}
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, *d)
+	}
+	return deliveries, rows.Err()
+}
+
+func (s *Store) getDelivery(ctx context.Context, where string, args ...interface{}) (*models.WebhookDelivery, error) {
+	d, err := scanDelivery(s.DB.QueryRowContext(ctx, s.rebind(`SELECT `+deliveryColumns+` FROM webhook_deliveries WHERE `+where), args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, store.ErrNotFound
+	}
+	return d, err
+}
+
+func scanWebhook(row rowScanner) (*models.Webhook, error) {
+	var (
+		w          models.Webhook
+		id, events string
+	)
+	if err := row.Scan(&id, &w.TenantID, &w.URL, &w.Secret, &events, &w.Description, &w.CreatedAt); err != nil {
+		return nil, err
+	}
+	var err error
+	if w.ID, err = primitive.ObjectIDFromHex(id); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(events), &w.Events); err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+func scanDelivery(row rowScanner) (*models.WebhookDelivery, error) {
+	var (
+		d             models.WebhookDelivery
+		id, webhookID string
+		deliveredAt   sql.NullTime
+	)
+	if err := row.Scan(&id, &d.TenantID, &webhookID, &d.EventID, &d.EventType, &d.Payload, &d.Status, &d.Attempts,
+		&d.NextAttemptAt, &d.LastError, &d.ResponseStatus, &d.CreatedAt, &deliveredAt); err != nil {
+		return nil, err
+	}
+	var err error
+	if d.ID, err = primitive.ObjectIDFromHex(id); err != nil {
+		return nil, err
+	}
+	if d.WebhookID, err = primitive.ObjectIDFromHex(webhookID); err != nil {
+		return nil, err
+	}
+	if deliveredAt.Valid {
+		d.DeliveredAt = &deliveredAt.Time
+	}
+	return &d, nil
+}
@@ -0,0 +1,432 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/search"
+)
+
+// ไม่พบข้อมูลที่ต้องการ
+var ErrNotFound = errors.New("store: not found")
+
+// ErrWatchUnsupported คืนเมื่อฐานข้อมูลไม่รองรับ change stream (เช่น MongoDB แบบ standalone)
+var ErrWatchUnsupported = errors.New("store: change streams are not supported")
+
+// ErrInvalidResumeToken คืนเมื่อ resume token ผิดรูปแบบ หรือฐานข้อมูลไม่มีประวัติย้อนไปถึงแล้ว
+var ErrInvalidResumeToken = errors.New("store: invalid resume token")
+
+// DuplicateError เกิดเมื่อข้อมูลซ้ำกับ unique constraint (เช่น อีเมลหรือ username)
+type DuplicateError struct {
+	Field string // "email", "username", "id", "name" หรือ "" ถ้าไม่ทราบ
+}
+
+func (e *DuplicateError) Error() string {
+	return fmt.Sprintf("store: duplicate %s", e.Field)
+}
+
+// ตรวจสอบว่า err เป็น DuplicateError หรือไม่
+func IsDuplicate(err error) (*DuplicateError, bool) {
+	var dup *DuplicateError
+	ok := errors.As(err, &dup)
+	return dup, ok
+}
+
+// การเรียงลำดับของรายการผู้ใช้ (ใช้ ID เป็นตัวตัดสินเมื่อค่าเท่ากัน เพื่อให้ลำดับคงที่)
+type SortOrder struct {
+	Field string // "createdAt", "updatedAt", "username" หรือ "email"
+	Desc  bool
+}
+
+// ตำแหน่งของรายการสุดท้ายในหน้าก่อนหน้า สำหรับ cursor-based pagination
+type Cursor struct {
+	Value interface{} // ค่าของ field ที่ใช้เรียง (time.Time หรือ string)
+	ID    string
+}
+
+// เงื่อนไขดึงรายการผู้ใช้หนึ่งหน้า
+type UserListQuery struct {
+	Filter search.UserQuery
+	Order  SortOrder
+	After  *Cursor // nil = หน้าแรก
+	Skip   int64   // สำหรับ client เดิมที่ใช้หมายเลขหน้า
+	Limit  int64
+}
+
+// เงื่อนไขดึงรายการการส่ง webhook หนึ่งหน้า (เรียงจากใหม่ไปเก่าตาม ID)
+type DeliveryListQuery struct {
+	TenantID  string
+	WebhookID string // "" = ทุก webhook ใน tenant
+	Status    string // "" = ทุกสถานะ
+	BeforeID  string // ID ของรายการสุดท้ายในหน้าก่อน ("" = หน้าแรก)
+	Limit     int64
+}
+
+// เงื่อนไขดึง event จาก outbox ตามลำดับที่บันทึก (เรียงตาม ID)
+type OutboxListQuery struct {
+	TenantID     string
+	AfterID      string   // ID ของ event สุดท้ายที่อ่านแล้ว ("" = ตั้งแต่ต้น)
+	Types        []string // ว่าง = ทุกประเภท
+	AggregateIDs []string // ว่าง = ทุกรายการ
+	Limit        int
+}
+
+// เงื่อนไขดึง audit log ของ tenant ตามลำดับที่บันทึก (เรียงตาม ID)
+type AuditListQuery struct {
+	TenantID  string
+	AfterID   string // ID ของเหตุการณ์สุดท้ายที่อ่านแล้ว ("" = ตั้งแต่ต้น)
+	Action    string // "" = ทุกเหตุการณ์
+	SubjectID string // "" = ทุกผู้ใช้
+	Latest    bool   // true = เฉพาะ Limit รายการล่าสุด (ยังเรียงจากเก่าไปใหม่)
+	Limit     int
+}
+
+// ประเภทการเปลี่ยนแปลงของผู้ใช้ที่ WatchUsers ส่ง
+const (
+	UserCreated  = "created"
+	UserUpdated  = "updated"
+	UserDeleted  = "deleted" // soft delete
+	UserRestored = "restored"
+	UserPurged   = "purged" // ลบถาวร (User เป็น nil)
+)
+
+// การเปลี่ยนแปลงของผู้ใช้หนึ่งรายการจาก change stream
+type UserChange struct {
+	ResumeToken   string
+	Type          string
+	UserID        string
+	TenantID      string
+	OccurredAt    time.Time
+	UpdatedFields []string     // field ที่เปลี่ยน (เฉพาะ updated)
+	User          *models.User // ข้อมูลล่าสุดของผู้ใช้ ไม่มี hash รหัสผ่าน (nil เมื่อ purged หรือถูกลบไปแล้ว)
+}
+
+// เงื่อนไขติดตามการเปลี่ยนแปลงของผู้ใช้
+type UserWatchQuery struct {
+	TenantID    string
+	UserIDs     []string // ว่าง = ทุกคนใน tenant
+	ResumeToken string   // รับต่อจากการเปลี่ยนแปลงนี้ ("" = เฉพาะที่เกิดหลังจากนี้)
+}
+
+// การแก้ไขโปรไฟล์ผู้ใช้ field ที่เป็น nil จะไม่ถูกแก้ ค่าว่าง "" หมายถึงลบค่า
+type ProfilePatch struct {
+	Username    *string
+	DisplayName *string
+	AvatarURL   *string
+	Locale      *string
+	Timezone    *string
+	Phone       *string
+	Role        *string // role ตามกลุ่มใน directory (ตั้งตอนเข้าสู่ระบบผ่าน LDAP)
+	// บังคับยืนยันด้วย passkey หลังเข้าสู่ระบบด้วยรหัสผ่าน
+	PasskeyRequired *bool
+
+	ReplaceMetadata bool              // true = แทนที่ metadata ทั้งชุดด้วย Metadata
+	Metadata        map[string]string // ค่า metadata ที่จะตั้ง (ค่าว่าง = ลบ key นั้น ถ้าไม่ได้แทนที่ทั้งชุด)
+}
+
+// UserStore จัดเก็บข้อมูลผู้ใช้
+// ID ของผู้ใช้ไม่ซ้ำกันทั้งระบบ ส่วนอีเมลและ username ไม่ซ้ำกันภายใน tenant เดียวกัน
+type UserStore interface {
+	// สร้างผู้ใช้ใหม่ใน u.TenantID (กำหนด ID ให้ u) คืน DuplicateError ถ้าอีเมลหรือ username ซ้ำ
+	CreateUser(ctx context.Context, u *models.User) error
+	// ดึงผู้ใช้ตาม ID (includeDeleted = รวมผู้ใช้ที่ถูก soft delete)
+	GetUserByID(ctx context.Context, id string, includeDeleted bool) (*models.User, error)
+	// ดึงผู้ใช้ที่ยังไม่ถูกลบตามอีเมลใน tenant
+	GetUserByEmail(ctx context.Context, tenantID string, email string) (*models.User, error)
+	// ตรวจสอบว่าอีเมล/username ถูกใช้แล้วใน tenant หรือไม่ (ไม่สนตัวพิมพ์ และรวมผู้ใช้ที่ถูกลบ)
+	EmailTaken(ctx context.Context, tenantID string, email string) (bool, error)
+	UsernameTaken(ctx context.Context, tenantID string, username string) (bool, error)
+
+	// แก้ไขโปรไฟล์ผู้ใช้
+	UpdateProfile(ctx context.Context, id string, patch ProfilePatch, updatedAt time.Time) error
+	// เปลี่ยนรหัสผ่าน และเก็บ hash เดิมไว้ในประวัติไม่เกิน historyLimit รายการ
+	UpdatePassword(ctx context.Context, id string, oldHash string, newHash string, historyLimit int, changedAt time.Time) error
+	// เปลี่ยนอีเมลของผู้ใช้ที่ยังไม่ถูกลบ (อีเมลใหม่ถือว่ายืนยันแล้ว) คืนข้อมูลผู้ใช้ก่อนเปลี่ยน
+	UpdateEmail(ctx context.Context, tenantID string, from string, to string, updatedAt time.Time) (*models.User, error)
+
+	// soft delete ผู้ใช้ที่ยังไม่ถูกลบ คืนข้อมูลผู้ใช้ก่อนลบ
+	SoftDeleteUser(ctx context.Context, id string, deletedAt time.Time) (*models.User, error)
+	// กู้คืนผู้ใช้ที่ถูก soft delete คืนข้อมูลผู้ใช้ก่อนกู้คืน
+	RestoreUser(ctx context.Context, id string, updatedAt time.Time) (*models.User, error)
+	// ลบผู้ใช้ถาวร
+	DeleteUser(ctx context.Context, id string) error
+	// ID ของผู้ใช้ที่ถูก soft delete ก่อนเวลาที่กำหนด (ทุก tenant)
+	ListDeletedBefore(ctx context.Context, before time.Time) ([]string, error)
+
+	// ดึงรายการผู้ใช้หนึ่งหน้า (ไม่สนตัวพิมพ์เล็ก/ใหญ่ในการค้นหาและเรียง)
+	ListUsers(ctx context.Context, q UserListQuery) ([]models.User, error)
+	// นับจำนวนผู้ใช้ตามเงื่อนไข ถ้า allowEstimate อาจคืนค่าประมาณที่เป็นค่าขั้นต่ำของผลที่ตรงเงื่อนไข (bool = เป็นค่าประมาณหรือไม่)
+	CountUsers(ctx context.Context, q search.UserQuery, allowEstimate bool) (int64, bool, error)
+}
+
+// BlacklistStore จัดเก็บ token ที่ถูกยกเลิกจนกว่าจะหมดอายุ
+type BlacklistStore interface {
+	AddToken(ctx context.Context, token string, expiresAt time.Time) error
+	IsBlacklisted(ctx context.Context, token string) (bool, error)
+}
+
+// SessionStore จัดเก็บ active token ของผู้ใช้แต่ละคน (หนึ่ง session ต่อผู้ใช้ใน tenant)
+type SessionStore interface {
+	// คืน "" ถ้าไม่มี session
+	GetActiveToken(ctx context.Context, tenantID string, email string) (string, error)
+	SetActiveToken(ctx context.Context, tenantID string, email string, token string, ttl time.Duration) error
+	DeleteActiveToken(ctx context.Context, tenantID string, email string) error
+}
+
+// KeyValueStore จัดเก็บข้อมูลชั่วคราวที่มีอายุ เช่น ตัวนับ rate limit และ token ยืนยันการเปลี่ยนอีเมล
+type KeyValueStore interface {
+	// เพิ่มตัวนับทีละ 1 แล้วคืนค่าใหม่ ตัวนับหมดอายุหลัง window นับจากครั้งแรก
+	Incr(ctx context.Context, key string, window time.Duration) (int64, error)
+	// คืน ErrNotFound ถ้าไม่มี key หรือหมดอายุแล้ว
+	Get(ctx context.Context, key string) (string, error)
+	Set(ctx context.Context, key string, value string, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+	// ย้ายค่า (และอายุที่เหลือ) ไปยัง key ใหม่ ไม่ทำอะไรถ้าไม่มี key เดิม
+	Rename(ctx context.Context, from string, to string) error
+}
+
+// AuditStore จัดเก็บ audit log
+type AuditStore interface {
+	InsertEvent(ctx context.Context, ev models.AuditEvent) error
+	// เหตุการณ์ที่เกี่ยวกับผู้ใช้ (ตาม ID หรืออีเมลภายใน tenant) เรียงจากเก่าไปใหม่
+	FindBySubject(ctx context.Context, tenantID string, subjectID string, email string) ([]models.AuditEvent, error)
+	// ลบข้อมูลส่วนบุคคลของผู้ใช้ออกจาก audit log
+	RedactSubject(ctx context.Context, tenantID string, subjectID string, email string) error
+	// เหตุการณ์ของ tenant ตามเงื่อนไข เรียงจากเก่าไปใหม่
+	ListEvents(ctx context.Context, q AuditListQuery) ([]models.AuditEvent, error)
+}
+
+// SettingsStore จัดเก็บการตั้งค่าของระบบ
+type SettingsStore interface {
+	// คืน nil ถ้ายังไม่เคยกำหนด
+	GetProfileSchema(ctx context.Context) ([]models.ProfileAttribute, error)
+	SaveProfileSchema(ctx context.Context, schema models.ProfileSchema) error
+	// คืน ErrNotFound ถ้ายังไม่มี signing key ของ OpenID Connect
+	GetOIDCKeys(ctx context.Context) (*models.OIDCKeySet, error)
+	// บันทึกชุด key เฉพาะเมื่อยังไม่มี (instance ที่เริ่มพร้อมกันได้ key ชุดเดียวกัน)
+	InitOIDCKeys(ctx context.Context, keys models.OIDCKeySet) error
+}
+
+// TenantStore จัดเก็บ tenant และการตั้งค่าของแต่ละ tenant
+type TenantStore interface {
+	// คืน DuplicateError (Field "id") ถ้ามี tenant ID นี้แล้ว
+	CreateTenant(ctx context.Context, t *models.Tenant) error
+	// คืน ErrNotFound ถ้าไม่มี tenant
+	GetTenant(ctx context.Context, id string) (*models.Tenant, error)
+	// tenant ทั้งหมดเรียงตาม ID
+	ListTenants(ctx context.Context) ([]models.Tenant, error)
+	// บันทึกทับ tenant ทั้งก้อน (สร้างใหม่ถ้ายังไม่มี)
+	SaveTenant(ctx context.Context, t *models.Tenant) error
+}
+
+// GroupStore จัดเก็บกลุ่มและสมาชิกของกลุ่ม (ทุกคำสั่งจำกัดอยู่ใน tenant ที่ระบุ)
+type GroupStore interface {
+	// สร้างกลุ่มใหม่ (กำหนด ID ให้ g) คืน DuplicateError (Field "name") ถ้าชื่อซ้ำใน tenant
+	CreateGroup(ctx context.Context, g *models.Group) error
+	// คืน ErrNotFound ถ้าไม่มีกลุ่มใน tenant
+	GetGroup(ctx context.Context, tenantID string, id string) (*models.Group, error)
+	// กลุ่มทั้งหมดใน tenant เรียงตามชื่อ
+	ListGroups(ctx context.Context, tenantID string) ([]models.Group, error)
+	// ลบกลุ่มพร้อมสมาชิกทั้งหมด คืน ErrNotFound ถ้าไม่มีกลุ่ม
+	DeleteGroup(ctx context.Context, tenantID string, id string) error
+
+	// เพิ่มผู้ใช้ที่ยังไม่ถูกลบเข้ากลุ่ม หรือเปลี่ยน role ถ้าเป็นสมาชิกอยู่แล้ว คืน ErrNotFound ถ้าไม่มีผู้ใช้ใน tenant
+	AddMember(ctx context.Context, tenantID string, userID string, m models.GroupMembership) error
+	// คืน ErrNotFound ถ้าผู้ใช้ไม่ได้เป็นสมาชิก
+	RemoveMember(ctx context.Context, tenantID string, groupID string, userID string) error
+	// role ของผู้ใช้ในกลุ่ม คืน ErrNotFound ถ้าไม่ได้เป็นสมาชิก
+	GetMembership(ctx context.Context, tenantID string, groupID string, userID string) (*models.GroupMembership, error)
+	// สมาชิกที่ยังไม่ถูกลบของกลุ่ม เรียงตามเวลาที่เข้ากลุ่ม
+	ListMembers(ctx context.Context, tenantID string, groupID string) ([]models.GroupMember, error)
+	// กลุ่มที่ผู้ใช้เป็นสมาชิก เรียงตามชื่อกลุ่ม
+	ListUserGroups(ctx context.Context, tenantID string, userID string) ([]models.UserGroup, error)
+}
+
+// APIKeyStore จัดเก็บ API key (ค้นหา key ที่ใช้ยืนยันตัวตนด้วย hash เท่านั้น)
+type APIKeyStore interface {
+	// สร้าง key ใหม่ (กำหนด ID ให้ k)
+	CreateAPIKey(ctx context.Context, k *models.APIKey) error
+	// คืน ErrNotFound ถ้าไม่มี key ใน tenant
+	GetAPIKey(ctx context.Context, tenantID string, id string) (*models.APIKey, error)
+	// ค้นหา key จาก hash (รวม key ที่ถูกยกเลิกหรือหมดอายุแล้ว) คืน ErrNotFound ถ้าไม่มี
+	GetAPIKeyByHash(ctx context.Context, hash string) (*models.APIKey, error)
+	// key ของผู้ใช้ใน tenant เรียงจากใหม่ไปเก่า (userID ว่าง = ทุกคนใน tenant)
+	ListAPIKeys(ctx context.Context, tenantID string, userID string) ([]models.APIKey, error)
+	// ยกเลิก key คืน ErrNotFound ถ้าไม่มี key หรือถูกยกเลิกไปแล้ว
+	RevokeAPIKey(ctx context.Context, tenantID string, id string, revokedAt time.Time) error
+	// บันทึกเวลาที่ใช้ key ล่าสุด
+	TouchAPIKey(ctx context.Context, id string, usedAt time.Time) error
+}
+
+// ServiceAccountStore จัดเก็บ service account พร้อม client secret และ public key
+type ServiceAccountStore interface {
+	// สร้าง service account ใหม่ (กำหนด ID ให้ a) คืน DuplicateError (Field "name") ถ้าชื่อซ้ำใน tenant
+	CreateServiceAccount(ctx context.Context, a *models.ServiceAccount) error
+	// คืน ErrNotFound ถ้าไม่มี service account ใน tenant
+	GetServiceAccount(ctx context.Context, tenantID string, id string) (*models.ServiceAccount, error)
+	// ค้นหาจาก client ID (ไม่ซ้ำกันทุก tenant) คืน ErrNotFound ถ้าไม่มี
+	GetServiceAccountByClientID(ctx context.Context, clientID string) (*models.ServiceAccount, error)
+	// service account ทั้งหมดใน tenant เรียงตามชื่อ
+	ListServiceAccounts(ctx context.Context, tenantID string) ([]models.ServiceAccount, error)
+	// บันทึกทับทั้งก้อน (เช่น หลัง rotate secret) คืน ErrNotFound ถ้าไม่มี
+	SaveServiceAccount(ctx context.Context, a *models.ServiceAccount) error
+	// คืน ErrNotFound ถ้าไม่มี service account ใน tenant
+	DeleteServiceAccount(ctx context.Context, tenantID string, id string) error
+}
+
+// OAuthClientStore จัดเก็บ client ของ OAuth/OpenID Connect และความยินยอมของผู้ใช้
+type OAuthClientStore interface {
+	// สร้าง client ใหม่ (กำหนด ID ให้ c) คืน DuplicateError (Field "name") ถ้าชื่อซ้ำใน tenant
+	CreateOAuthClient(ctx context.Context, c *models.OAuthClient) error
+	// คืน ErrNotFound ถ้าไม่มี client ใน tenant
+	GetOAuthClient(ctx context.Context, tenantID string, id string) (*models.OAuthClient, error)
+	// ค้นหาจาก client ID (ไม่ซ้ำกันทุก tenant) คืน ErrNotFound ถ้าไม่มี
+	GetOAuthClientByClientID(ctx context.Context, clientID string) (*models.OAuthClient, error)
+	// client ทั้งหมดใน tenant เรียงตามชื่อ
+	ListOAuthClients(ctx context.Context, tenantID string) ([]models.OAuthClient, error)
+	// บันทึกทับทั้งก้อน คืน ErrNotFound ถ้าไม่มี หรือ DuplicateError (Field "name") ถ้าชื่อซ้ำ
+	SaveOAuthClient(ctx context.Context, c *models.OAuthClient) error
+	// ลบ client พร้อมความยินยอมทั้งหมดของ client คืน ErrNotFound ถ้าไม่มี client ใน tenant
+	DeleteOAuthClient(ctx context.Context, tenantID string, id string) error
+
+	// คืน ErrNotFound ถ้าผู้ใช้ยังไม่เคยให้ความยินยอมกับ client
+	GetConsent(ctx context.Context, tenantID string, userID string, clientID string) (*models.OAuthConsent, error)
+	// บันทึกความยินยอม (แทนที่รายการเดิมของผู้ใช้กับ client เดียวกัน)
+	SaveConsent(ctx context.Context, c *models.OAuthConsent) error
+	// ความยินยอมทั้งหมดของผู้ใช้ เรียงจากใหม่ไปเก่า
+	ListConsents(ctx context.Context, tenantID string, userID string) ([]models.OAuthConsent, error)
+	// คืน ErrNotFound ถ้าไม่มีความยินยอม
+	DeleteConsent(ctx context.Context, tenantID string, userID string, clientID string) error
+}
+
+// IdentityStore จัดเก็บ identity provider ภายนอกและ directory (LDAP) ของแต่ละ tenant
+// บัญชีภายนอกที่ผู้ใช้ผูกไว้ และ passkey (WebAuthn credential) ของผู้ใช้
+type IdentityStore interface {
+	// สร้าง provider ใหม่ (กำหนด ID ให้ p) คืน DuplicateError (Field "name") ถ้าชื่อซ้ำใน tenant
+	CreateIdentityProvider(ctx context.Context, p *models.IdentityProvider) error
+	// คืน ErrNotFound ถ้าไม่มี provider ใน tenant
+	GetIdentityProvider(ctx context.Context, tenantID string, id string) (*models.IdentityProvider, error)
+	GetIdentityProviderByName(ctx context.Context, tenantID string, name string) (*models.IdentityProvider, error)
+	// provider ทั้งหมดใน tenant เรียงตามชื่อ
+	ListIdentityProviders(ctx context.Context, tenantID string) ([]models.IdentityProvider, error)
+	// ลบ provider พร้อมบัญชีที่ผูกไว้ทั้งหมด คืน ErrNotFound ถ้าไม่มี provider ใน tenant
+	DeleteIdentityProvider(ctx context.Context, tenantID string, id string) error
+
+	// ผูกบัญชี คืน DuplicateError ที่ Field "subject" ถ้าบัญชีนี้ผูกกับผู้ใช้อื่นแล้ว
+	// หรือ Field "provider" ถ้าผู้ใช้ผูกบัญชีอื่นของ provider เดียวกันไว้แล้ว
+	CreateLinkedIdentity(ctx context.Context, l *models.LinkedIdentity) error
+	// ค้นหาจาก sub ของ provider คืน ErrNotFound ถ้ายังไม่มีผู้ใช้ผูกไว้
+	GetLinkedIdentity(ctx context.Context, providerID string, subject string) (*models.LinkedIdentity, error)
+	// บัญชีทั้งหมดที่ผู้ใช้ผูกไว้ เรียงตามเวลาที่ผูก
+	ListLinkedIdentities(ctx context.Context, tenantID string, userID string) ([]models.LinkedIdentity, error)
+	// บันทึกเวลาที่เข้าสู่ระบบด้วยบัญชีนี้ล่าสุด
+	TouchLinkedIdentity(ctx context.Context, providerID string, subject string, at time.Time) error
+	// คืน ErrNotFound ถ้าผู้ใช้ไม่ได้ผูกบัญชีของ provider นี้
+	DeleteLinkedIdentity(ctx context.Context, tenantID string, userID string, providerID string) error
+	// ลบบัญชีทั้งหมดที่ผู้ใช้ผูกไว้และ passkey ทั้งหมดของผู้ใช้ (ตอนลบผู้ใช้ถาวร)
+	DeleteUserIdentities(ctx context.Context, tenantID string, userID string) error
+
+	// สร้าง directory ใหม่ (กำหนด ID ให้ d) คืน DuplicateError (Field "name") ถ้าชื่อซ้ำใน tenant
+	CreateDirectory(ctx context.Context, d *models.Directory) error
+	// คืน ErrNotFound ถ้าไม่มี directory ใน tenant
+	GetDirectory(ctx context.Context, tenantID string, id string) (*models.Directory, error)
+	// directory ทั้งหมดใน tenant เรียงตามชื่อ
+	ListDirectories(ctx context.Context, tenantID string) ([]models.Directory, error)
+	// คืน ErrNotFound ถ้าไม่มี directory ใน tenant
+	DeleteDirectory(ctx context.Context, tenantID string, id string) error
+
+	// บันทึก passkey (กำหนด ID ให้ p) คืน DuplicateError (Field "credentialId") ถ้า credential นี้ลงทะเบียนแล้วใน tenant
+	CreatePasskey(ctx context.Context, p *models.Passkey) error
+	// ค้นหาจาก credential ID คืน ErrNotFound ถ้าไม่มีใน tenant
+	GetPasskeyByCredentialID(ctx context.Context, tenantID string, credentialID string) (*models.Passkey, error)
+	// passkey ทั้งหมดของผู้ใช้ เรียงตามเวลาที่ลงทะเบียน
+	ListPasskeys(ctx context.Context, tenantID string, userID string) ([]models.Passkey, error)
+	// บันทึก sign counter และเวลาที่ใช้ล่าสุด เฉพาะเมื่อ counter ยังเป็น prevCount
+	// คืน ErrNotFound ถ้า counter ถูกเปลี่ยนไปแล้ว (assertion อื่นถูกใช้พร้อมกัน)
+	UpdatePasskeyUsage(ctx context.Context, id string, prevCount uint32, signCount uint32, backedUp bool, at time.Time) error
+	// คืน ErrNotFound ถ้าผู้ใช้ไม่มี passkey นี้
+	RenamePasskey(ctx context.Context, tenantID string, userID string, id string, name string) (*models.Passkey, error)
+	DeletePasskey(ctx context.Context, tenantID string, userID string, id string) error
+}
+
+// WebhookStore จัดเก็บ webhook ของแต่ละ tenant และคิวการส่งเหตุการณ์ไปยัง webhook
+type WebhookStore interface {
+	// สร้าง webhook ใหม่ (กำหนด ID ให้ w)
+	CreateWebhook(ctx context.Context, w *models.Webhook) error
+	// คืน ErrNotFound ถ้าไม่มี webhook ใน tenant
+	GetWebhook(ctx context.Context, tenantID string, id string) (*models.Webhook, error)
+	// webhook ทั้งหมดใน tenant เรียงตามเวลาที่สร้าง
+	ListWebhooks(ctx context.Context, tenantID string) ([]models.Webhook, error)
+	// ลบ webhook พร้อมประวัติการส่งทั้งหมด คืน ErrNotFound ถ้าไม่มี webhook ใน tenant
+	DeleteWebhook(ctx context.Context, tenantID string, id string) error
+
+	// เพิ่มการส่งเข้าคิว (กำหนด ID ให้แต่ละรายการ)
+	EnqueueDeliveries(ctx context.Context, deliveries []*models.WebhookDelivery) error
+	// จองการส่งที่ถึงเวลาแล้วไม่เกิน limit รายการ (ทุก tenant) โดยเลื่อน NextAttemptAt เป็น leaseUntil
+	// instance อื่นจะไม่ได้รายการเดียวกันจนกว่า lease จะหมด
+	ClaimDueDeliveries(ctx context.Context, now time.Time, leaseUntil time.Time, limit int) ([]models.WebhookDelivery, error)
+	// บันทึกผลการส่ง (status, attempts, เวลาส่งครั้งถัดไป และ error ล่าสุด)
+	SaveDeliveryAttempt(ctx context.Context, d *models.WebhookDelivery) error
+	// คืน ErrNotFound ถ้าไม่มีการส่งใน tenant
+	GetDelivery(ctx context.Context, tenantID string, id string) (*models.WebhookDelivery, error)
+	ListDeliveries(ctx context.Context, q DeliveryListQuery) ([]models.WebhookDelivery, error)
+}
+
+// OutboxStore บันทึก domain event ลง outbox ใน transaction เดียวกับการเปลี่ยนแปลงข้อมูล และให้ relay ดึงไปเผยแพร่
+type OutboxStore interface {
+	// สร้างผู้ใช้แบบเดียวกับ UserStore.CreateUser พร้อมบันทึก ev (ตั้ง AggregateID เป็น ID ของผู้ใช้) ใน transaction เดียวกัน
+	CreateUserWithEvent(ctx context.Context, u *models.User, ev *models.OutboxEvent) error
+	// เพิ่ม token ใน blacklist พร้อมบันทึก ev ใน transaction เดียวกัน
+	AddTokenWithEvent(ctx context.Context, token string, expiresAt time.Time, ev *models.OutboxEvent) error
+	// UserStore.UpdateProfile, SoftDeleteUser, RestoreUser และ DeleteUser พร้อมบันทึก ev ใน transaction เดียวกัน
+	UpdateProfileWithEvent(ctx context.Context, id string, patch ProfilePatch, updatedAt time.Time, ev *models.OutboxEvent) error
+	SoftDeleteUserWithEvent(ctx context.Context, id string, deletedAt time.Time, ev *models.OutboxEvent) (*models.User, error)
+	RestoreUserWithEvent(ctx context.Context, id string, updatedAt time.Time, ev *models.OutboxEvent) (*models.User, error)
+	DeleteUserWithEvent(ctx context.Context, id string, ev *models.OutboxEvent) error
+	// สร้างผู้ใช้หลายคนพร้อม event ของแต่ละคน (events[i] เป็นของ users[i]) คืน error ของแต่ละคน
+	// (nil = สร้างสำเร็จ, DuplicateError = ซ้ำ) ผู้ใช้ที่สร้างไม่สำเร็จไม่กระทบคนอื่น error ตัวที่สองคือ error ของทั้งชุด
+	CreateUsersWithEvents(ctx context.Context, users []*models.User, events []*models.OutboxEvent) ([]error, error)
+
+	// ดึง event ที่บันทึกหลัง q.AfterID (รวมที่ยังไม่เผยแพร่)
+	ListOutboxEvents(ctx context.Context, q OutboxListQuery) ([]models.OutboxEvent, error)
+
+	// จอง event ที่ยังไม่เผยแพร่และถึงเวลาแล้วไม่เกิน limit รายการ (เรียงตามลำดับที่บันทึก) โดยเลื่อน NextAttemptAt เป็น leaseUntil
+	ClaimOutboxEvents(ctx context.Context, now time.Time, leaseUntil time.Time, limit int) ([]models.OutboxEvent, error)
+	MarkOutboxPublished(ctx context.Context, id string, publishedAt time.Time) error
+	// บันทึกการเผยแพร่ที่ไม่สำเร็จ และเวลาที่จะลองใหม่
+	MarkOutboxFailed(ctx context.Context, id string, attempts int, nextAttemptAt time.Time, lastError string) error
+	// ลบ event ที่เผยแพร่แล้วก่อนเวลาที่กำหนด คืนจำนวนที่ลบ
+	PurgePublishedOutbox(ctx context.Context, before time.Time) (int64, error)
+}
+
+// UserWatcher ติดตามการเปลี่ยนแปลงของผู้ใช้จาก change stream ของฐานข้อมูล
+type UserWatcher interface {
+	// ส่งการเปลี่ยนแปลงให้ fn ตามลำดับจนกว่า ctx ถูกยกเลิกหรือ fn คืน error
+	// คืน ErrWatchUnsupported ถ้าฐานข้อมูลไม่รองรับ และ ErrInvalidResumeToken ถ้าใช้ q.ResumeToken ไม่ได้
+	WatchUsers(ctx context.Context, q UserWatchQuery, fn func(UserChange) error) error
+}
+
+// รวม store ทั้งหมดของ backend หนึ่ง ๆ
+type Stores struct {
+	Tenants         TenantStore
+	Users           UserStore
+	Groups          GroupStore
+	APIKeys         APIKeyStore
+	ServiceAccounts ServiceAccountStore
+	OAuthClients    OAuthClientStore
+	Identities      IdentityStore
+	Webhooks        WebhookStore
+	Outbox          OutboxStore
+	UserChanges     UserWatcher // nil = backend ไม่มี change stream (ใช้ event จาก outbox แทน)
+	Blacklist       BlacklistStore
+	Sessions        SessionStore
+	Cache           KeyValueStore
+	Audit           AuditStore
+	Settings        SettingsStore
+
+	// ปิดการเชื่อมต่อของ backend
+	Close func(ctx context.Context) error
+}
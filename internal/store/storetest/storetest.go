@@ -0,0 +1,884 @@
+// Package storetest คือชุดทดสอบที่ทุก backend ของ store ต้องผ่านเหมือนกัน (conformance suite)
+// backend เรียก Run จาก _test.go ของตัวเองพร้อมฟังก์ชันที่เปิดฐานข้อมูลว่างที่รัน migration แล้ว
+package storetest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/search"
+	"auth-microservice/internal/store"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Open เปิด store ชุดใหม่บนฐานข้อมูลว่างที่รัน migration แล้ว (ปิดการเชื่อมต่อด้วย t.Cleanup)
+type Open func(t *testing.T) *store.Stores
+
+// รันชุดทดสอบทั้งหมดกับ backend หนึ่ง แต่ละกรณีได้ฐานข้อมูลใหม่
+func Run(t *testing.T, open Open) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, s *store.Stores)
+	}{
+		{"Users/CreateAndGet", testCreateAndGetUser},
+		{"Users/DuplicateIgnoresCase", testDuplicateUser},
+		{"Users/TenantIsolation", testUserTenantIsolation},
+		{"Users/SoftDeleteAndRestore", testSoftDeleteUser},
+		{"Users/UpdateProfile", testUpdateProfile},
+		{"Users/UpdateEmail", testUpdateEmail},
+		{"Users/ListPages", testListUsers},
+		{"Users/Count", testCountUsers},
+		{"Blacklist", testBlacklist},
+		{"Sessions", testSessions},
+		{"Cache", testCache},
+		{"Expiry", testExpiry},
+		{"Tenants", testTenants},
+		{"Groups", testGroups},
+		{"APIKeys", testAPIKeys},
+		{"Audit", testAudit},
+		{"Outbox", testOutbox},
+		{"Webhooks", testWebhooks},
+		{"LinkedIdentities", testLinkedIdentities},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, open(t))
+		})
+	}
+}
+
+func testContext(t *testing.T) context.Context {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+// เวลาปัดเป็นมิลลิวินาทีเพราะ MongoDB เก็บเวลาละเอียดแค่นั้น
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Millisecond)
+}
+
+func newUser(tenantID string, name string, createdAt time.Time) *models.User {
+	return &models.User{
+		TenantID:  tenantID,
+		Email:     name + "@example.com",
+		Username:  name,
+		Password:  "hash-of-" + name,
+		Role:      "user",
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	}
+}
+
+func createUser(t *testing.T, s *store.Stores, u *models.User) *models.User {
+	t.Helper()
+	if err := s.Users.CreateUser(testContext(t), u); err != nil {
+		t.Fatalf("CreateUser(%s): %v", u.Email, err)
+	}
+	if u.ID.IsZero() {
+		t.Fatalf("CreateUser(%s) did not assign an ID", u.Email)
+	}
+	return u
+}
+
+func wantNotFound(t *testing.T, what string, err error) {
+	t.Helper()
+	if !errors.Is(err, store.ErrNotFound) {
+		t.Fatalf("%s: got error %v, want store.ErrNotFound", what, err)
+	}
+}
+
+func wantDuplicate(t *testing.T, what string, err error, field string) {
+	t.Helper()
+	dup, ok := store.IsDuplicate(err)
+	if !ok {
+		t.Fatalf("%s: got error %v, want DuplicateError", what, err)
+	}
+	if field != "" && dup.Field != field {
+		t.Fatalf("%s: DuplicateError.Field = %q, want %q", what, dup.Field, field)
+	}
+}
+
+func testCreateAndGetUser(t *testing.T, s *store.Stores) {
+	ctx := testContext(t)
+	u := createUser(t, s, newUser(models.DefaultTenantID, "alice", now()))
+
+	got, err := s.Users.GetUserByID(ctx, u.ID.Hex(), false)
+	if err != nil {
+		t.Fatalf("GetUserByID: %v", err)
+	}
+	if got.Email != u.Email || got.Username != u.Username || got.Password != u.Password || got.TenantID != u.TenantID {
+		t.Fatalf("GetUserByID = %+v, want %+v", got, u)
+	}
+
+	got, err = s.Users.GetUserByEmail(ctx, models.DefaultTenantID, "alice@example.com")
+	if err != nil {
+		t.Fatalf("GetUserByEmail: %v", err)
+	}
+	if got.ID != u.ID {
+		t.Fatalf("GetUserByEmail returned user %s, want %s", got.ID.Hex(), u.ID.Hex())
+	}
+
+	_, err = s.Users.GetUserByID(ctx, primitive.NewObjectID().Hex(), true)
+	wantNotFound(t, "GetUserByID of unknown ID", err)
+	_, err = s.Users.GetUserByID(ctx, "not-an-id", true)
+	wantNotFound(t, "GetUserByID of malformed ID", err)
+	_, err = s.Users.GetUserByEmail(ctx, models.DefaultTenantID, "nobody@example.com")
+	wantNotFound(t, "GetUserByEmail of unknown email", err)
+}
+
+func testDuplicateUser(t *testing.T, s *store.Stores) {
+	ctx := testContext(t)
+	createUser(t, s, newUser(models.DefaultTenantID, "bob", now()))
+
+	dup := newUser(models.DefaultTenantID, "other", now())
+	dup.Email = "BOB@Example.com"
+	wantDuplicate(t, "CreateUser with same email in other case", s.Users.CreateUser(ctx, dup), "email")
+
+	dup = newUser(models.DefaultTenantID, "other", now())
+	dup.Username = "Bob"
+	wantDuplicate(t, "CreateUser with same username in other case", s.Users.CreateUser(ctx, dup), "username")
+
+	for _, email := range []string{"bob@example.com", "BOB@EXAMPLE.COM"} {
+		taken, err := s.Users.EmailTaken(ctx, models.DefaultTenantID, email)
+		if err != nil || !taken {
+			t.Fatalf("EmailTaken(%q) = %v, %v, want true", email, taken, err)
+		}
+	}
+	taken, err := s.Users.UsernameTaken(ctx, models.DefaultTenantID, "BOB")
+	if err != nil || !taken {
+		t.Fatalf("UsernameTaken(BOB) = %v, %v, want true", taken, err)
+	}
+	taken, err = s.Users.EmailTaken(ctx, models.DefaultTenantID, "carol@example.com")
+	if err != nil || taken {
+		t.Fatalf("EmailTaken of unused email = %v, %v, want false", taken, err)
+	}
+}
+
+func testUserTenantIsolation(t *testing.T, s *store.Stores) {
+	ctx := testContext(t)
+	a := createUser(t, s, newUser("tenant-a", "dave", now()))
+	b := createUser(t, s, newUser("tenant-b", "dave", now()))
+	if a.ID == b.ID {
+		t.Fatal("users in different tenants share an ID")
+	}
+
+	got, err := s.Users.GetUserByEmail(ctx, "tenant-b", "dave@example.com")
+	if err != nil {
+		t.Fatalf("GetUserByEmail in tenant-b: %v", err)
+	}
+	if got.ID != b.ID {
+		t.Fatalf("GetUserByEmail in tenant-b returned the user of %s", got.TenantID)
+	}
+	_, err = s.Users.GetUserByEmail(ctx, "tenant-c", "dave@example.com")
+	wantNotFound(t, "GetUserByEmail in a tenant without the user", err)
+}
+
+func testSoftDeleteUser(t *testing.T, s *store.Stores) {
+	ctx := testContext(t)
+	u := createUser(t, s, newUser(models.DefaultTenantID, "erin", now()))
+	deletedAt := now()
+
+	before, err := s.Users.SoftDeleteUser(ctx, u.ID.Hex(), deletedAt)
+	if err != nil {
+		t.Fatalf("SoftDeleteUser: %v", err)
+	}
+	if before.Deleted {
+		t.Fatal("SoftDeleteUser should return the user as it was before deletion")
+	}
+	_, err = s.Users.SoftDeleteUser(ctx, u.ID.Hex(), deletedAt)
+	wantNotFound(t, "SoftDeleteUser of a deleted user", err)
+
+	_, err = s.Users.GetUserByID(ctx, u.ID.Hex(), false)
+	wantNotFound(t, "GetUserByID of a deleted user", err)
+	_, err = s.Users.GetUserByEmail(ctx, models.DefaultTenantID, u.Email)
+	wantNotFound(t, "GetUserByEmail of a deleted user", err)
+	got, err := s.Users.GetUserByID(ctx, u.ID.Hex(), true)
+	if err != nil || !got.Deleted || got.DeletedAt == nil {
+		t.Fatalf("GetUserByID(includeDeleted) = %+v, %v, want the deleted user", got, err)
+	}
+
+	// อีเมลของผู้ใช้ที่ถูกลบยังถือว่าถูกใช้ (ตรงกับ unique index)
+	taken, err := s.Users.EmailTaken(ctx, models.DefaultTenantID, u.Email)
+	if err != nil || !taken {
+		t.Fatalf("EmailTaken of a deleted user = %v, %v, want true", taken, err)
+	}
+
+	ids, err := s.Users.ListDeletedBefore(ctx, deletedAt.Add(time.Second))
+	if err != nil || len(ids) != 1 || ids[0] != u.ID.Hex() {
+		t.Fatalf("ListDeletedBefore = %v, %v, want [%s]", ids, err, u.ID.Hex())
+	}
+	ids, err = s.Users.ListDeletedBefore(ctx, deletedAt.Add(-time.Second))
+	if err != nil || len(ids) != 0 {
+		t.Fatalf("ListDeletedBefore before deletion = %v, %v, want none", ids, err)
+	}
+
+	if _, err := s.Users.RestoreUser(ctx, u.ID.Hex(), now()); err != nil {
+		t.Fatalf("RestoreUser: %v", err)
+	}
+	if _, err := s.Users.GetUserByEmail(ctx, models.DefaultTenantID, u.Email); err != nil {
+		t.Fatalf("GetUserByEmail after restore: %v", err)
+	}
+
+	if err := s.Users.DeleteUser(ctx, u.ID.Hex()); err != nil {
+		t.Fatalf("DeleteUser: %v", err)
+	}
+	_, err = s.Users.GetUserByID(ctx, u.ID.Hex(), true)
+	wantNotFound(t, "GetUserByID after DeleteUser", err)
+}
+
+func testUpdateProfile(t *testing.T, s *store.Stores) {
+	ctx := testContext(t)
+	u := newUser(models.DefaultTenantID, "frank", now())
+	u.Metadata = map[string]string{"team": "core", "floor": "3"}
+	createUser(t, s, u)
+
+	displayName, role, required := "Frank", "tenant_admin", true
+	patch := store.ProfilePatch{
+		DisplayName:     &displayName,
+		Role:            &role,
+		PasskeyRequired: &required,
+		Metadata:        map[string]string{"team": "", "desk": "12"},
+	}
+	if err := s.Users.UpdateProfile(ctx, u.ID.Hex(), patch, now()); err != nil {
+		t.Fatalf("UpdateProfile: %v", err)
+	}
+	got, err := s.Users.GetUserByID(ctx, u.ID.Hex(), false)
+	if err != nil {
+		t.Fatalf("GetUserByID: %v", err)
+	}
+	if got.DisplayName != displayName || got.Role != role || !got.PasskeyRequired {
+		t.Fatalf("UpdateProfile did not apply fields: %+v", got)
+	}
+	want := map[string]string{"floor": "3", "desk": "12"}
+	if fmt.Sprint(got.Metadata) != fmt.Sprint(want) {
+		t.Fatalf("metadata = %v, want %v", got.Metadata, want)
+	}
+	if got.Username != u.Username {
+		t.Fatalf("UpdateProfile changed a field that was not in the patch: username = %q", got.Username)
+	}
+
+	other := createUser(t, s, newUser(models.DefaultTenantID, "grace", now()))
+	taken := "FRANK"
+	err = s.Users.UpdateProfile(ctx, other.ID.Hex(), store.ProfilePatch{Username: &taken}, now())
+	wantDuplicate(t, "UpdateProfile to a username in use", err, "username")
+
+	err = s.Users.UpdateProfile(ctx, primitive.NewObjectID().Hex(), patch, now())
+	wantNotFound(t, "UpdateProfile of unknown user", err)
+}
+
+func testUpdateEmail(t *testing.T, s *store.Stores) {
+	ctx := testContext(t)
+	u := createUser(t, s, newUser(models.DefaultTenantID, "heidi", now()))
+	createUser(t, s, newUser(models.DefaultTenantID, "ivan", now()))
+
+	before, err := s.Users.UpdateEmail(ctx, models.DefaultTenantID, u.Email, "heidi@new.example.com", now())
+	if err != nil {
+		t.Fatalf("UpdateEmail: %v", err)
+	}
+	if before.Email != u.Email {
+		t.Fatalf("UpdateEmail returned email %q, want the old email", before.Email)
+	}
+	got, err := s.Users.GetUserByEmail(ctx, models.DefaultTenantID, "heidi@new.example.com")
+	if err != nil || got.ID != u.ID || !got.EmailVerified {
+		t.Fatalf("GetUserByEmail of the new email = %+v, %v, want the verified user", got, err)
+	}
+
+	_, err = s.Users.UpdateEmail(ctx, models.DefaultTenantID, "heidi@new.example.com", "IVAN@example.com", now())
+	wantDuplicate(t, "UpdateEmail to an email in use", err, "email")
+}
+
+func testListUsers(t *testing.T, s *store.Stores) {
+	ctx := testContext(t)
+	start := now().Add(-time.Hour)
+	var ids []string
+	for i := 0; i < 7; i++ {
+		u := createUser(t, s, newUser(models.DefaultTenantID, fmt.Sprintf("list%02d", i), start.Add(time.Duration(i)*time.Minute)))
+		ids = append(ids, u.ID.Hex())
+	}
+	createUser(t, s, newUser("other-tenant", "list99", start))
+	deleted := createUser(t, s, newUser(models.DefaultTenantID, "listdeleted", start))
+	if _, err := s.Users.SoftDeleteUser(ctx, deleted.ID.Hex(), now()); err != nil {
+		t.Fatalf("SoftDeleteUser: %v", err)
+	}
+
+	// เดินทีละหน้าด้วย cursor ต้องได้ครบ ไม่ซ้ำ และเรียงตามเวลาที่สร้าง
+	filter := search.UserQuery{TenantID: models.DefaultTenantID, Deleted: search.DeletedExclude}
+	order := store.SortOrder{Field: "createdAt"}
+	var got []string
+	var after *store.Cursor
+	for page := 0; page < 10; page++ {
+		users, err := s.Users.ListUsers(ctx, store.UserListQuery{Filter: filter, Order: order, After: after, Limit: 3})
+		if err != nil {
+			t.Fatalf("ListUsers page %d: %v", page, err)
+		}
+		for _, u := range users {
+			got = append(got, u.ID.Hex())
+		}
+		if len(users) < 3 {
+			break
+		}
+		last := users[len(users)-1]
+		after = &store.Cursor{Value: last.CreatedAt, ID: last.ID.Hex()}
+	}
+	if fmt.Sprint(got) != fmt.Sprint(ids) {
+		t.Fatalf("ListUsers pages = %v, want %v", got, ids)
+	}
+
+	// ค้นหาด้วยคำขึ้นต้นแบบไม่สนตัวพิมพ์ และเรียงตาม username จากมากไปน้อย
+	users, err := s.Users.ListUsers(ctx, store.UserListQuery{
+		Filter: search.UserQuery{TenantID: models.DefaultTenantID, Username: "LIST0", Mode: search.MatchPrefix, Deleted: search.DeletedExclude},
+		Order:  store.SortOrder{Field: "username", Desc: true},
+		Limit:  2,
+	})
+	if err != nil {
+		t.Fatalf("ListUsers by username: %v", err)
+	}
+	if len(users) != 2 || users[0].Username != "list06" || users[1].Username != "list05" {
+		t.Fatalf("ListUsers by username desc = %v, want list06, list05", usernames(users))
+	}
+
+	users, err = s.Users.ListUsers(ctx, store.UserListQuery{
+		Filter: search.UserQuery{TenantID: models.DefaultTenantID, Deleted: search.DeletedOnly},
+		Order:  order,
+		Limit:  10,
+	})
+	if err != nil || len(users) != 1 || users[0].ID != deleted.ID {
+		t.Fatalf("ListUsers(deleted only) = %v, %v, want the deleted user", usernames(users), err)
+	}
+}
+
+func usernames(users []models.User) []string {
+	names := make([]string, 0, len(users))
+	for _, u := range users {
+		names = append(names, u.Username)
+	}
+	return names
+}
+
+func testCountUsers(t *testing.T, s *store.Stores) {
+	ctx := testContext(t)
+	for i := 0; i < 3; i++ {
+		createUser(t, s, newUser(models.DefaultTenantID, fmt.Sprintf("count%d", i), now()))
+	}
+	createUser(t, s, newUser("other-tenant", "count9", now()))
+	deleted := createUser(t, s, newUser(models.DefaultTenantID, "countdeleted", now()))
+	if _, err := s.Users.SoftDeleteUser(ctx, deleted.ID.Hex(), now()); err != nil {
+		t.Fatalf("SoftDeleteUser: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		query search.UserQuery
+		want  int64
+	}{
+		{"tenant", search.UserQuery{TenantID: models.DefaultTenantID, Deleted: search.DeletedExclude}, 3},
+		{"all tenants", search.UserQuery{Deleted: search.DeletedExclude}, 4},
+		{"include deleted", search.UserQuery{TenantID: models.DefaultTenantID, Deleted: search.DeletedInclude}, 4},
+		{"deleted only", search.UserQuery{Deleted: search.DeletedOnly}, 1},
+	}
+	for _, tt := range tests {
+		total, estimated, err := s.Users.CountUsers(ctx, tt.query, false)
+		if err != nil || total != tt.want || estimated {
+			t.Errorf("CountUsers(%s) = %d, %v, %v, want exact %d", tt.name, total, estimated, err, tt.want)
+		}
+		// ค่าประมาณต้องนับตามเงื่อนไขเดียวกัน (ไม่รวมผู้ใช้ที่ถูกลบหรือ tenant อื่น) และไม่เกินค่าจริง
+		total, estimated, err = s.Users.CountUsers(ctx, tt.query, true)
+		if err != nil || total > tt.want || (!estimated && total != tt.want) {
+			t.Errorf("CountUsers(%s, allowEstimate) = %d, %v, %v, want at most %d", tt.name, total, estimated, err, tt.want)
+		}
+	}
+}
+
+func testBlacklist(t *testing.T, s *store.Stores) {
+	ctx := testContext(t)
+	if err := s.Blacklist.AddToken(ctx, "token-1", now().Add(time.Hour)); err != nil {
+		t.Fatalf("AddToken: %v", err)
+	}
+	for token, want := range map[string]bool{"token-1": true, "token-2": false} {
+		got, err := s.Blacklist.IsBlacklisted(ctx, token)
+		if err != nil || got != want {
+			t.Errorf("IsBlacklisted(%s) = %v, %v, want %v", token, got, err, want)
+		}
+	}
+}
+
+// session และข้อมูลชั่วคราวของ MongoDB อยู่ใน Redis จึงข้ามได้ถ้า backend ไม่ได้ให้มา
+func testSessions(t *testing.T, s *store.Stores) {
+	if s.Sessions == nil {
+		t.Skip("backend has no session store")
+	}
+	ctx := testContext(t)
+	if err := s.Sessions.SetActiveToken(ctx, "t1", "a@example.com", "jwt-1", time.Hour); err != nil {
+		t.Fatalf("SetActiveToken: %v", err)
+	}
+	if got, err := s.Sessions.GetActiveToken(ctx, "t1", "a@example.com"); err != nil || got != "jwt-1" {
+		t.Fatalf("GetActiveToken = %q, %v, want jwt-1", got, err)
+	}
+	if got, err := s.Sessions.GetActiveToken(ctx, "t2", "a@example.com"); err != nil || got != "" {
+		t.Fatalf("GetActiveToken in other tenant = %q, %v, want none", got, err)
+	}
+	if err := s.Sessions.DeleteActiveToken(ctx, "t1", "a@example.com"); err != nil {
+		t.Fatalf("DeleteActiveToken: %v", err)
+	}
+	if got, err := s.Sessions.GetActiveToken(ctx, "t1", "a@example.com"); err != nil || got != "" {
+		t.Fatalf("GetActiveToken after delete = %q, %v, want none", got, err)
+	}
+}
+
+func testCache(t *testing.T, s *store.Stores) {
+	if s.Cache == nil {
+		t.Skip("backend has no key-value store")
+	}
+	ctx := testContext(t)
+	for want := int64(1); want <= 3; want++ {
+		got, err := s.Cache.Incr(ctx, "counter", time.Minute)
+		if err != nil || got != want {
+			t.Fatalf("Incr = %d, %v, want %d", got, err, want)
+		}
+	}
+
+	if err := s.Cache.Set(ctx, "key", "value", time.Minute); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if err := s.Cache.Rename(ctx, "key", "renamed"); err != nil {
+		t.Fatalf("Rename: %v", err)
+	}
+	_, err := s.Cache.Get(ctx, "key")
+	wantNotFound(t, "Get of a renamed key", err)
+	if got, err := s.Cache.Get(ctx, "renamed"); err != nil || got != "value" {
+		t.Fatalf("Get(renamed) = %q, %v, want value", got, err)
+	}
+	if err := s.Cache.Rename(ctx, "missing", "other"); err != nil {
+		t.Fatalf("Rename of a missing key: %v", err)
+	}
+
+	if err := s.Cache.Set(ctx, "short", "value", time.Second); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	time.Sleep(1100 * time.Millisecond)
+	_, err = s.Cache.Get(ctx, "short")
+	wantNotFound(t, "Get of an expired key", err)
+
+	if err := s.Cache.Delete(ctx, "renamed"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	_, err = s.Cache.Get(ctx, "renamed")
+	wantNotFound(t, "Get of a deleted key", err)
+}
+
+// session และตัวนับ rate limit ต้องหมดอายุเองเหมือน Redis (โหมด SQLite ไม่มี Redis ช่วย)
+func testExpiry(t *testing.T, s *store.Stores) {
+	if s.Sessions == nil || s.Cache == nil {
+		t.Skip("backend has no session or key-value store")
+	}
+	ctx := testContext(t)
+	tests := []struct {
+		name  string
+		ttl   time.Duration
+		setup func(ttl time.Duration) error
+		check func() error
+	}{
+		{
+			name: "session",
+			ttl:  time.Second,
+			setup: func(ttl time.Duration) error {
+				return s.Sessions.SetActiveToken(ctx, "t1", "short@example.com", "jwt-short", ttl)
+			},
+			check: func() error {
+				if got, err := s.Sessions.GetActiveToken(ctx, "t1", "short@example.com"); err != nil || got != "" {
+					return fmt.Errorf("GetActiveToken = %q, %v, want none", got, err)
+				}
+				return nil
+			},
+		},
+		{
+			name: "session still valid",
+			ttl:  time.Hour,
+			setup: func(ttl time.Duration) error {
+				return s.Sessions.SetActiveToken(ctx, "t1", "long@example.com", "jwt-long", ttl)
+			},
+			check: func() error {
+				if got, err := s.Sessions.GetActiveToken(ctx, "t1", "long@example.com"); err != nil || got != "jwt-long" {
+					return fmt.Errorf("GetActiveToken = %q, %v, want jwt-long", got, err)
+				}
+				return nil
+			},
+		},
+		{
+			name: "counter window restarts",
+			ttl:  time.Second,
+			setup: func(ttl time.Duration) error {
+				for i := 0; i < 3; i++ {
+					if _, err := s.Cache.Incr(ctx, "short-counter", ttl); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+			check: func() error {
+				if got, err := s.Cache.Incr(ctx, "short-counter", time.Second); err != nil || got != 1 {
+					return fmt.Errorf("Incr = %d, %v, want 1", got, err)
+				}
+				return nil
+			},
+		},
+		{
+			name: "counter window still open",
+			ttl:  time.Hour,
+			setup: func(ttl time.Duration) error {
+				for i := 0; i < 3; i++ {
+					if _, err := s.Cache.Incr(ctx, "long-counter", ttl); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+			check: func() error {
+				if got, err := s.Cache.Incr(ctx, "long-counter", time.Hour); err != nil || got != 4 {
+					return fmt.Errorf("Incr = %d, %v, want 4", got, err)
+				}
+				return nil
+			},
+		},
+	}
+	for _, tt := range tests {
+		if err := tt.setup(tt.ttl); err != nil {
+			t.Fatalf("%s: setup: %v", tt.name, err)
+		}
+	}
+	// รอครั้งเดียวให้ทุกกรณีที่ ttl สั้นหมดอายุ
+	time.Sleep(1100 * time.Millisecond)
+	for _, tt := range tests {
+		if err := tt.check(); err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+		}
+	}
+}
+
+func testTenants(t *testing.T, s *store.Stores) {
+	ctx := testContext(t)
+	tenant := &models.Tenant{
+		ID:             "acme",
+		Name:           "Acme",
+		Status:         models.TenantActive,
+		PasswordPolicy: models.DefaultPasswordPolicy(),
+		AccessTokenTTL: 10 * time.Minute,
+		SessionTTL:     time.Hour,
+		SigningKeys:    []models.SigningKey{{ID: "k1", Secret: []byte("secret"), CreatedAt: now()}},
+		CreatedAt:      now(),
+		UpdatedAt:      now(),
+	}
+	if err := s.Tenants.CreateTenant(ctx, tenant); err != nil {
+		t.Fatalf("CreateTenant: %v", err)
+	}
+	wantDuplicate(t, "CreateTenant with the same ID", s.Tenants.CreateTenant(ctx, tenant), "id")
+
+	got, err := s.Tenants.GetTenant(ctx, "acme")
+	if err != nil {
+		t.Fatalf("GetTenant: %v", err)
+	}
+	if got.Name != tenant.Name || got.AccessTokenTTL != tenant.AccessTokenTTL || len(got.SigningKeys) != 1 ||
+		string(got.SigningKeys[0].Secret) != "secret" || got.PasswordPolicy != tenant.PasswordPolicy {
+		t.Fatalf("GetTenant = %+v, want %+v", got, tenant)
+	}
+
+	got.Name = "Acme Corp"
+	if err := s.Tenants.SaveTenant(ctx, got); err != nil {
+		t.Fatalf("SaveTenant: %v", err)
+	}
+	if err := s.Tenants.SaveTenant(ctx, &models.Tenant{ID: "beta", Name: "Beta", Status: models.TenantActive}); err != nil {
+		t.Fatalf("SaveTenant of a new tenant: %v", err)
+	}
+	tenants, err := s.Tenants.ListTenants(ctx)
+	if err != nil {
+		t.Fatalf("ListTenants: %v", err)
+	}
+	names := map[string]string{}
+	for _, tn := range tenants {
+		names[tn.ID] = tn.Name
+	}
+	if names["acme"] != "Acme Corp" || names["beta"] != "Beta" {
+		t.Fatalf("ListTenants = %v, want the saved tenants", names)
+	}
+
+	_, err = s.Tenants.GetTenant(ctx, "missing")
+	wantNotFound(t, "GetTenant of unknown tenant", err)
+}
+
+func testGroups(t *testing.T, s *store.Stores) {
+	ctx := testContext(t)
+	owner := createUser(t, s, newUser(models.DefaultTenantID, "owner", now()))
+	member := createUser(t, s, newUser(models.DefaultTenantID, "member", now()))
+	outsider := createUser(t, s, newUser("other-tenant", "outsider", now()))
+
+	g := &models.Group{TenantID: models.DefaultTenantID, Name: "Platform", CreatedAt: now(), UpdatedAt: now()}
+	if err := s.Groups.CreateGroup(ctx, g); err != nil {
+		t.Fatalf("CreateGroup: %v", err)
+	}
+	same := &models.Group{TenantID: models.DefaultTenantID, Name: "Platform", CreatedAt: now(), UpdatedAt: now()}
+	wantDuplicate(t, "CreateGroup with the same name", s.Groups.CreateGroup(ctx, same), "name")
+
+	add := func(u *models.User, role string, joinedAt time.Time) error {
+		return s.Groups.AddMember(ctx, u.TenantID, u.ID.Hex(), models.GroupMembership{GroupID: g.ID, Role: role, JoinedAt: joinedAt})
+	}
+	if err := add(owner, models.GroupRoleOwner, now().Add(-time.Minute)); err != nil {
+		t.Fatalf("AddMember(owner): %v", err)
+	}
+	if err := add(member, models.GroupRoleMember, now()); err != nil {
+		t.Fatalf("AddMember(member): %v", err)
+	}
+	wantNotFound(t, "AddMember of a user in another tenant", add(outsider, models.GroupRoleMember, now()))
+
+	// เพิ่มซ้ำ = เปลี่ยน role
+	if err := add(member, models.GroupRoleManager, now()); err != nil {
+		t.Fatalf("AddMember to change role: %v", err)
+	}
+	m, err := s.Groups.GetMembership(ctx, models.DefaultTenantID, g.ID.Hex(), member.ID.Hex())
+	if err != nil || m.Role != models.GroupRoleManager {
+		t.Fatalf("GetMembership = %+v, %v, want manager", m, err)
+	}
+
+	members, err := s.Groups.ListMembers(ctx, models.DefaultTenantID, g.ID.Hex())
+	if err != nil || len(members) != 2 || members[0].UserID != owner.ID || members[1].UserID != member.ID {
+		t.Fatalf("ListMembers = %+v, %v, want owner then member", members, err)
+	}
+	groups, err := s.Groups.ListUserGroups(ctx, models.DefaultTenantID, member.ID.Hex())
+	if err != nil || len(groups) != 1 || groups[0].Group.ID != g.ID || groups[0].Role != models.GroupRoleManager {
+		t.Fatalf("ListUserGroups = %+v, %v, want Platform as manager", groups, err)
+	}
+
+	if err := s.Groups.RemoveMember(ctx, models.DefaultTenantID, g.ID.Hex(), member.ID.Hex()); err != nil {
+		t.Fatalf("RemoveMember: %v", err)
+	}
+	wantNotFound(t, "RemoveMember twice", s.Groups.RemoveMember(ctx, models.DefaultTenantID, g.ID.Hex(), member.ID.Hex()))
+
+	_, err = s.Groups.GetGroup(ctx, "other-tenant", g.ID.Hex())
+	wantNotFound(t, "GetGroup from another tenant", err)
+	if err := s.Groups.DeleteGroup(ctx, models.DefaultTenantID, g.ID.Hex()); err != nil {
+		t.Fatalf("DeleteGroup: %v", err)
+	}
+	_, err = s.Groups.GetMembership(ctx, models.DefaultTenantID, g.ID.Hex(), owner.ID.Hex())
+	wantNotFound(t, "GetMembership after DeleteGroup", err)
+}
+
+func testAPIKeys(t *testing.T, s *store.Stores) {
+	ctx := testContext(t)
+	owner := createUser(t, s, newUser(models.DefaultTenantID, "keyowner", now()))
+	k := &models.APIKey{
+		TenantID:  models.DefaultTenantID,
+		UserID:    owner.ID,
+		Name:      "ci",
+		Prefix:    "ak_abc",
+		Hash:      "hash-1",
+		Scopes:    []string{models.ScopeUsersRead},
+		RateLimit: 60,
+		ExpiresAt: now().Add(time.Hour),
+		CreatedAt: now(),
+	}
+	if err := s.APIKeys.CreateAPIKey(ctx, k); err != nil {
+		t.Fatalf("CreateAPIKey: %v", err)
+	}
+
+	got, err := s.APIKeys.GetAPIKeyByHash(ctx, "hash-1")
+	if err != nil || got.ID != k.ID || len(got.Scopes) != 1 || got.Scopes[0] != models.ScopeUsersRead {
+		t.Fatalf("GetAPIKeyByHash = %+v, %v, want the key", got, err)
+	}
+	_, err = s.APIKeys.GetAPIKeyByHash(ctx, "hash-2")
+	wantNotFound(t, "GetAPIKeyByHash of unknown hash", err)
+	_, err = s.APIKeys.GetAPIKey(ctx, "other-tenant", k.ID.Hex())
+	wantNotFound(t, "GetAPIKey from another tenant", err)
+
+	if err := s.APIKeys.RevokeAPIKey(ctx, models.DefaultTenantID, k.ID.Hex(), now()); err != nil {
+		t.Fatalf("RevokeAPIKey: %v", err)
+	}
+	wantNotFound(t, "RevokeAPIKey twice", s.APIKeys.RevokeAPIKey(ctx, models.DefaultTenantID, k.ID.Hex(), now()))
+	got, err = s.APIKeys.GetAPIKeyByHash(ctx, "hash-1")
+	if err != nil || got.RevokedAt == nil {
+		t.Fatalf("GetAPIKeyByHash of a revoked key = %+v, %v, want RevokedAt set", got, err)
+	}
+}
+
+func testAudit(t *testing.T, s *store.Stores) {
+	ctx := testContext(t)
+	events := []models.AuditEvent{
+		{TenantID: "t1", Action: "user.registered", ActorEmail: "a@example.com", SubjectID: "u1", SubjectEmail: "a@example.com"},
+		{TenantID: "t1", Action: "user.login", ActorEmail: "a@example.com", SubjectID: "u1", SubjectEmail: "a@example.com"},
+		{TenantID: "t1", Action: "user.login", ActorEmail: "b@example.com", SubjectID: "u2", SubjectEmail: "b@example.com"},
+		{TenantID: "t2", Action: "user.login", ActorEmail: "a@example.com", SubjectID: "u9", SubjectEmail: "a@example.com"},
+	}
+	for i, ev := range events {
+		ev.CreatedAt = now().Add(time.Duration(i) * time.Millisecond)
+		ev.Details = map[string]interface{}{"n": "x"}
+		if err := s.Audit.InsertEvent(ctx, ev); err != nil {
+			t.Fatalf("InsertEvent: %v", err)
+		}
+	}
+
+	got, err := s.Audit.FindBySubject(ctx, "t1", "u1", "a@example.com")
+	if err != nil || len(got) != 2 || got[0].Action != "user.registered" || got[1].Action != "user.login" {
+		t.Fatalf("FindBySubject = %+v, %v, want the two events of u1 in order", got, err)
+	}
+
+	list, err := s.Audit.ListEvents(ctx, store.AuditListQuery{TenantID: "t1", Action: "user.login", Limit: 10})
+	if err != nil || len(list) != 2 {
+		t.Fatalf("ListEvents(action) = %+v, %v, want two logins in t1", list, err)
+	}
+	latest, err := s.Audit.ListEvents(ctx, store.AuditListQuery{TenantID: "t1", Latest: true, Limit: 2})
+	if err != nil || len(latest) != 2 || latest[0].SubjectID != "u1" || latest[1].SubjectID != "u2" {
+		t.Fatalf("ListEvents(latest) = %+v, %v, want the last two events oldest first", latest, err)
+	}
+	after, err := s.Audit.ListEvents(ctx, store.AuditListQuery{TenantID: "t1", AfterID: latest[0].ID.Hex(), Limit: 10})
+	if err != nil || len(after) != 1 || after[0].ID != latest[1].ID {
+		t.Fatalf("ListEvents(after) = %+v, %v, want only the last event", after, err)
+	}
+
+	if err := s.Audit.RedactSubject(ctx, "t1", "u1", "a@example.com"); err != nil {
+		t.Fatalf("RedactSubject: %v", err)
+	}
+	got, err = s.Audit.FindBySubject(ctx, "t1", "u1", "a@example.com")
+	if err != nil {
+		t.Fatalf("FindBySubject after redaction: %v", err)
+	}
+	for _, ev := range got {
+		if ev.SubjectEmail == "a@example.com" || ev.ActorEmail == "a@example.com" {
+			t.Fatalf("RedactSubject left personal data: %+v", ev)
+		}
+	}
+	other, err := s.Audit.ListEvents(ctx, store.AuditListQuery{TenantID: "t2", Limit: 10})
+	if err != nil || len(other) != 1 || other[0].SubjectEmail != "a@example.com" {
+		t.Fatalf("RedactSubject changed another tenant: %+v, %v", other, err)
+	}
+}
+
+func testOutbox(t *testing.T, s *store.Stores) {
+	ctx := testContext(t)
+	u := newUser(models.DefaultTenantID, "outbox", now())
+	ev := &models.OutboxEvent{TenantID: models.DefaultTenantID, Type: "user.registered", Payload: `{}`, CreatedAt: now(), NextAttemptAt: now()}
+	if err := s.Outbox.CreateUserWithEvent(ctx, u, ev); err != nil {
+		t.Fatalf("CreateUserWithEvent: %v", err)
+	}
+
+	// ผู้ใช้ที่ซ้ำต้องไม่มี event ค้างอยู่
+	dup := newUser(models.DefaultTenantID, "outbox", now())
+	dupEvent := &models.OutboxEvent{TenantID: models.DefaultTenantID, Type: "user.registered", Payload: `{}`, CreatedAt: now(), NextAttemptAt: now()}
+	wantDuplicate(t, "CreateUserWithEvent of a duplicate", s.Outbox.CreateUserWithEvent(ctx, dup, dupEvent), "")
+
+	events, err := s.Outbox.ListOutboxEvents(ctx, store.OutboxListQuery{TenantID: models.DefaultTenantID, Limit: 10})
+	if err != nil || len(events) != 1 || events[0].AggregateID != u.ID.Hex() {
+		t.Fatalf("ListOutboxEvents = %+v, %v, want one event for the new user", events, err)
+	}
+
+	claimed, err := s.Outbox.ClaimOutboxEvents(ctx, now().Add(time.Second), now().Add(time.Minute), 10)
+	if err != nil || len(claimed) != 1 {
+		t.Fatalf("ClaimOutboxEvents = %+v, %v, want one event", claimed, err)
+	}
+	again, err := s.Outbox.ClaimOutboxEvents(ctx, now().Add(time.Second), now().Add(time.Minute), 10)
+	if err != nil || len(again) != 0 {
+		t.Fatalf("ClaimOutboxEvents during lease = %+v, %v, want none", again, err)
+	}
+	if err := s.Outbox.MarkOutboxPublished(ctx, claimed[0].ID.Hex(), now()); err != nil {
+		t.Fatalf("MarkOutboxPublished: %v", err)
+	}
+	again, err = s.Outbox.ClaimOutboxEvents(ctx, now().Add(time.Hour), now().Add(2*time.Hour), 10)
+	if err != nil || len(again) != 0 {
+		t.Fatalf("ClaimOutboxEvents after publish = %+v, %v, want none", again, err)
+	}
+	purged, err := s.Outbox.PurgePublishedOutbox(ctx, now().Add(time.Minute))
+	if err != nil || purged != 1 {
+		t.Fatalf("PurgePublishedOutbox = %d, %v, want 1", purged, err)
+	}
+
+	// การแก้ไขพร้อม event
+	name := "Outbox User"
+	update := &models.OutboxEvent{TenantID: models.DefaultTenantID, Type: "user.updated", Payload: `{}`, CreatedAt: now(), NextAttemptAt: now()}
+	if err := s.Outbox.UpdateProfileWithEvent(ctx, u.ID.Hex(), store.ProfilePatch{DisplayName: &name}, now(), update); err != nil {
+		t.Fatalf("UpdateProfileWithEvent: %v", err)
+	}
+	events, err = s.Outbox.ListOutboxEvents(ctx, store.OutboxListQuery{TenantID: models.DefaultTenantID, Types: []string{"user.updated"}, Limit: 10})
+	if err != nil || len(events) != 1 {
+		t.Fatalf("ListOutboxEvents(user.updated) = %+v, %v, want one event", events, err)
+	}
+}
+
+func testWebhooks(t *testing.T, s *store.Stores) {
+	ctx := testContext(t)
+	w := &models.Webhook{TenantID: "t1", URL: "https://hooks.example.com/a", Secret: "whsec_x", Events: []string{"user.*"}, CreatedAt: now()}
+	if err := s.Webhooks.CreateWebhook(ctx, w); err != nil {
+		t.Fatalf("CreateWebhook: %v", err)
+	}
+	_, err := s.Webhooks.GetWebhook(ctx, "t2", w.ID.Hex())
+	wantNotFound(t, "GetWebhook from another tenant", err)
+
+	deliveries := []*models.WebhookDelivery{
+		{TenantID: "t1", WebhookID: w.ID, EventID: "e1", EventType: "user.login", Payload: `{}`, Status: models.DeliveryPending, NextAttemptAt: now().Add(-time.Second), CreatedAt: now()},
+		{TenantID: "t1", WebhookID: w.ID, EventID: "e2", EventType: "user.login", Payload: `{}`, Status: models.DeliveryPending, NextAttemptAt: now().Add(time.Hour), CreatedAt: now()},
+	}
+	if err := s.Webhooks.EnqueueDeliveries(ctx, deliveries); err != nil {
+		t.Fatalf("EnqueueDeliveries: %v", err)
+	}
+
+	claimed, err := s.Webhooks.ClaimDueDeliveries(ctx, now(), now().Add(time.Minute), 10)
+	if err != nil || len(claimed) != 1 || claimed[0].EventID != "e1" {
+		t.Fatalf("ClaimDueDeliveries = %+v, %v, want only the due delivery", claimed, err)
+	}
+	again, err := s.Webhooks.ClaimDueDeliveries(ctx, now(), now().Add(time.Minute), 10)
+	if err != nil || len(again) != 0 {
+		t.Fatalf("ClaimDueDeliveries during lease = %+v, %v, want none", again, err)
+	}
+
+	d := claimed[0]
+	d.Status = models.DeliveryDead
+	d.Attempts = 8
+	d.LastError = "HTTP 500"
+	d.ResponseStatus = 500
+	if err := s.Webhooks.SaveDeliveryAttempt(ctx, &d); err != nil {
+		t.Fatalf("SaveDeliveryAttempt: %v", err)
+	}
+	dead, err := s.Webhooks.ListDeliveries(ctx, store.DeliveryListQuery{TenantID: "t1", Status: models.DeliveryDead, Limit: 10})
+	if err != nil || len(dead) != 1 || dead[0].ResponseStatus != 500 || dead[0].Attempts != 8 {
+		t.Fatalf("ListDeliveries(dead) = %+v, %v, want the failed delivery", dead, err)
+	}
+
+	if err := s.Webhooks.DeleteWebhook(ctx, "t1", w.ID.Hex()); err != nil {
+		t.Fatalf("DeleteWebhook: %v", err)
+	}
+	_, err = s.Webhooks.GetDelivery(ctx, "t1", d.ID.Hex())
+	wantNotFound(t, "GetDelivery after DeleteWebhook", err)
+}
+
+func testLinkedIdentities(t *testing.T, s *store.Stores) {
+	ctx := testContext(t)
+	alice := createUser(t, s, newUser(models.DefaultTenantID, "linked1", now()))
+	bob := createUser(t, s, newUser(models.DefaultTenantID, "linked2", now()))
+	p := &models.IdentityProvider{TenantID: models.DefaultTenantID, Name: "corp", Protocol: models.ProtocolOIDC, CreatedAt: now()}
+	if err := s.Identities.CreateIdentityProvider(ctx, p); err != nil {
+		t.Fatalf("CreateIdentityProvider: %v", err)
+	}
+
+	link := func(u *models.User, subject string) error {
+		return s.Identities.CreateLinkedIdentity(ctx, &models.LinkedIdentity{
+			TenantID: models.DefaultTenantID, UserID: u.ID, ProviderID: p.ID, Subject: subject, Email: u.Email, CreatedAt: now(),
+		})
+	}
+	if err := link(alice, "sub-1"); err != nil {
+		t.Fatalf("CreateLinkedIdentity: %v", err)
+	}
+	wantDuplicate(t, "linking an account that belongs to another user", link(bob, "sub-1"), "subject")
+	wantDuplicate(t, "linking a second account of the same provider", link(alice, "sub-2"), "provider")
+
+	got, err := s.Identities.GetLinkedIdentity(ctx, p.ID.Hex(), "sub-1")
+	if err != nil || got.UserID != alice.ID {
+		t.Fatalf("GetLinkedIdentity = %+v, %v, want alice", got, err)
+	}
+	if err := s.Identities.DeleteIdentityProvider(ctx, models.DefaultTenantID, p.ID.Hex()); err != nil {
+		t.Fatalf("DeleteIdentityProvider: %v", err)
+	}
+	_, err = s.Identities.GetLinkedIdentity(ctx, p.ID.Hex(), "sub-1")
+	wantNotFound(t, "GetLinkedIdentity after DeleteIdentityProvider", err)
+}
@@ -0,0 +1,49 @@
+package validation
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	models "auth-microservice/internal/model"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// scope ที่ API key และ service account ขอได้
+var knownScopes = map[string]bool{
+	models.ScopeUsersRead:    true,
+	models.ScopeUsersWrite:   true,
+	models.ScopeGroupsRead:   true,
+	models.ScopeGroupsWrite:  true,
+	models.ScopeTenantsRead:  true,
+	models.ScopeTenantsWrite: true,
+}
+
+func ValidateAPIKeyName(name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > 100 {
+		return status.Error(codes.InvalidArgument, "ชื่อ API key ต้องมีความยาว 1-100 ตัวอักษร")
+	}
+	return nil
+}
+
+// ต้องมีอย่างน้อย 1 scope และทุก scope ต้องเป็น scope ที่รองรับ
+func ValidateScopes(scopes []string) error {
+	if len(scopes) == 0 {
+		return status.Error(codes.InvalidArgument, "ต้องระบุ scope อย่างน้อย 1 scope")
+	}
+	for _, scope := range scopes {
+		if !knownScopes[scope] {
+			return status.Errorf(codes.InvalidArgument, "ไม่รองรับ scope %q", scope)
+		}
+	}
+	return nil
+}
+
+func ValidateAPIKeyRateLimit(limit int) error {
+	if limit < 1 || limit > 1000 {
+		return status.Error(codes.InvalidArgument, "rate limit ของ API key ต้องอยู่ระหว่าง 1-1000 request ต่อนาที")
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package validation
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"net/url"
+	"strings"
+
+	models "auth-microservice/internal/model"
+
+	"github.com/go-ldap/ldap/v3"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const maxDirectoryPoolSize = 50
+
+// ตรวจสอบการตั้งค่า directory (รหัสผ่านของผู้ใช้ถูกส่งไปยัง server จึงต้องเข้ารหัสเสมอ ยกเว้น server บนเครื่องเดียวกัน)
+func ValidateDirectory(d *models.Directory) error {
+	if err := ValidateIdentityProviderName(d.Name); err != nil {
+		return status.Error(codes.InvalidArgument, "ชื่อ directory ต้องเป็นตัวพิมพ์เล็ก ตัวเลข หรือ - ยาว 2-40 ตัวอักษร")
+	}
+
+	u, err := url.Parse(d.URL)
+	if err != nil || u.Host == "" || (u.Path != "" && u.Path != "/") || u.RawQuery != "" {
+		return status.Error(codes.InvalidArgument, "URL ของ directory ต้องอยู่ในรูป ldaps://host:port หรือ ldap://host:port")
+	}
+	switch {
+	case u.Scheme == "ldaps" && d.StartTLS:
+		return status.Error(codes.InvalidArgument, "ldaps:// เข้ารหัสอยู่แล้ว ใช้ startTls ไม่ได้")
+	case u.Scheme == "ldap" && !d.StartTLS && !isLoopbackHost(u.Hostname()):
+		return status.Error(codes.InvalidArgument, "ldap:// ต้องใช้ startTls (แบบไม่เข้ารหัสใช้ได้เฉพาะ localhost)")
+	case u.Scheme != "ldap" && u.Scheme != "ldaps":
+		return status.Error(codes.InvalidArgument, "URL ของ directory ต้องขึ้นต้นด้วย ldaps:// หรือ ldap://")
+	}
+	if d.RootCA != "" {
+		block, _ := pem.Decode([]byte(d.RootCA))
+		if block == nil {
+			return status.Error(codes.InvalidArgument, "rootCa ต้องเป็น certificate แบบ PEM")
+		}
+		if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+			return status.Error(codes.InvalidArgument, "rootCa ต้องเป็น certificate แบบ PEM")
+		}
+	}
+
+	if d.BindDN != "" {
+		if _, err := ldap.ParseDN(d.BindDN); err != nil {
+			return status.Error(codes.InvalidArgument, "bindDn ไม่ถูกต้อง")
+		}
+		if d.BindPassword == "" {
+			return status.Error(codes.InvalidArgument, "ต้องระบุ bindPassword ของ service account")
+		}
+	}
+	if d.BaseDN != "" {
+		if _, err := ldap.ParseDN(d.BaseDN); err != nil {
+			return status.Error(codes.InvalidArgument, "baseDn ไม่ถูกต้อง")
+		}
+	}
+
+	// ต้องเลือกวิธีหา DN ของผู้ใช้อย่างใดอย่างหนึ่ง
+	switch {
+	case (d.UserDNTemplate == "") == (d.UserFilter == ""):
+		return status.Error(codes.InvalidArgument, "ต้องระบุ userDnTemplate หรือ userFilter อย่างใดอย่างหนึ่ง")
+	case d.UserDNTemplate != "" && !strings.Contains(d.UserDNTemplate, "{username}") && !strings.Contains(d.UserDNTemplate, "{email}"):
+		return status.Error(codes.InvalidArgument, "userDnTemplate ต้องมี {username} หรือ {email}")
+	case d.UserFilter != "":
+		if d.BaseDN == "" {
+			return status.Error(codes.InvalidArgument, "ต้องระบุ baseDn เมื่อใช้ userFilter")
+		}
+		if !strings.Contains(d.UserFilter, "{username}") && !strings.Contains(d.UserFilter, "{email}") {
+			return status.Error(codes.InvalidArgument, "userFilter ต้องมี {username} หรือ {email}")
+		}
+		if err := validateLDAPFilter(d.UserFilter, "userFilter"); err != nil {
+			return err
+		}
+	}
+	if d.AllowLocalFallback && d.UserFilter == "" {
+		return status.Error(codes.InvalidArgument, "allowLocalFallback ใช้ได้เฉพาะกับ userFilter")
+	}
+	if d.GroupFilter != "" {
+		if d.BaseDN == "" {
+			return status.Error(codes.InvalidArgument, "ต้องระบุ baseDn เมื่อใช้ groupFilter")
+		}
+		if !strings.Contains(d.GroupFilter, "{dn}") {
+			return status.Error(codes.InvalidArgument, "groupFilter ต้องมี {dn}")
+		}
+		if err := validateLDAPFilter(d.GroupFilter, "groupFilter"); err != nil {
+			return err
+		}
+	}
+
+	for _, mapping := range d.RoleMappings {
+		if _, err := ldap.ParseDN(mapping.Group); err != nil || mapping.Group == "" {
+			return status.Errorf(codes.InvalidArgument, "DN ของกลุ่ม %q ไม่ถูกต้อง", mapping.Group)
+		}
+		if err := ValidateRole(mapping.Role, d.TenantID); err != nil {
+			return err
+		}
+	}
+	if err := ValidateRole(d.DefaultRole, d.TenantID); err != nil {
+		return err
+	}
+	if err := ValidateEmailDomains(d.Domains); err != nil {
+		return err
+	}
+	if d.PoolSize < 0 || d.PoolSize > maxDirectoryPoolSize {
+		return status.Errorf(codes.InvalidArgument, "poolSize ต้องอยู่ระหว่าง 0 ถึง %d", maxDirectoryPoolSize)
+	}
+	return nil
+}
+
+// role ที่ผู้ใช้จาก directory, SAML IdP หรือการนำเข้าได้รับ (admin ของระบบมีได้เฉพาะ tenant default)
+func ValidateRole(role string, tenantID string) error {
+	switch role {
+	case "user", "tenant_admin":
+		return nil
+	case "admin":
+		if tenantID == models.DefaultTenantID {
+			return nil
+		}
+		return status.Error(codes.InvalidArgument, "role admin ใช้ได้เฉพาะ tenant default กรุณาใช้ tenant_admin")
+	}
+	return status.Errorf(codes.InvalidArgument, "role %q ต้องเป็น user, tenant_admin หรือ admin", role)
+}
+
+// ตรวจสอบไวยากรณ์ของ filter (แทนที่ตัวแปรด้วยค่าตัวอย่างก่อน)
+func validateLDAPFilter(filter string, field string) error {
+	sample := strings.NewReplacer("{email}", "user@example.com", "{username}", "user", "{dn}", "cn=user").Replace(filter)
+	if _, err := ldap.CompileFilter(sample); err != nil {
+		return status.Errorf(codes.InvalidArgument, "%s ไม่ถูกต้อง", field)
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package validation
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"auth-microservice/internal/federation"
+	models "auth-microservice/internal/model"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ชื่อของ identity provider ใช้อ้างถึงตอนเข้าสู่ระบบ จึงจำกัดเป็นตัวพิมพ์เล็ก ตัวเลข และ -
+var identityProviderNameRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,39}$`)
+
+func ValidateIdentityProviderName(name string) error {
+	if !identityProviderNameRegexp.MatchString(name) {
+		return status.Error(codes.InvalidArgument, "ชื่อ provider ต้องเป็นตัวพิมพ์เล็ก ตัวเลข หรือ - ยาว 2-40 ตัวอักษร")
+	}
+	return nil
+}
+
+func ValidateIdentityProviderDisplayName(name string) error {
+	if utf8.RuneCountInString(name) > 100 {
+		return status.Error(codes.InvalidArgument, "ชื่อที่แสดงของ provider ต้องมีความยาวไม่เกิน 100 ตัวอักษร")
+	}
+	return nil
+}
+
+// issuer และ redirect URI ต้องเป็น URL เต็มแบบ https issuer ต้องไม่มี query และ fragment และต้องไม่ชี้ไปยัง localhost หรือที่อยู่ภายใน
+// allowLocal (FEDERATION_ALLOW_LOCAL สำหรับเครื่อง dev เท่านั้น) ยอมให้ issuer ใช้ http://localhost และที่อยู่ภายใน
+func ValidateIdentityProviderURLs(issuer string, redirectURI string, allowLocal bool) error {
+	u, err := url.Parse(issuer)
+	if err != nil || u.Host == "" || u.RawQuery != "" || u.Fragment != "" {
+		return status.Error(codes.InvalidArgument, "issuer ต้องเป็น URL แบบ https")
+	}
+	if allowLocal {
+		if u.Scheme != "https" && !(u.Scheme == "http" && isLoopbackHost(u.Hostname())) {
+			return status.Error(codes.InvalidArgument, "issuer ต้องเป็น URL แบบ https (http ใช้ได้เฉพาะ localhost)")
+		}
+	} else if u.Scheme != "https" {
+		return status.Error(codes.InvalidArgument, "issuer ต้องเป็น URL แบบ https")
+	} else if isLocalHost(u.Hostname()) {
+		return status.Error(codes.InvalidArgument, "issuer ต้องชี้ไปยังที่อยู่สาธารณะ (ไม่ใช่ loopback, private หรือ link-local)")
+	}
+	if err := ValidateRedirectURIs([]string{redirectURI}); err != nil {
+		return err
+	}
+	return nil
+}
+
+func ValidateIdentityProviderClient(clientID string, clientSecret string) error {
+	if strings.TrimSpace(clientID) == "" || len(clientID) > 255 {
+		return status.Error(codes.InvalidArgument, "client ID ของ provider ต้องมีความยาว 1-255 ตัวอักษร")
+	}
+	if len(clientSecret) > 512 {
+		return status.Error(codes.InvalidArgument, "client secret ของ provider ต้องมีความยาวไม่เกิน 512 ตัวอักษร")
+	}
+	return nil
+}
+
+// ต้องมี scope openid เพื่อให้ provider ส่ง ID token กลับมา
+func ValidateFederationScopes(scopes []string) error {
+	hasOpenID := false
+	for _, scope := range scopes {
+		if scope == "" || strings.ContainsAny(scope, " \"\\") {
+			return status.Errorf(codes.InvalidArgument, "scope %q ไม่ถูกต้อง", scope)
+		}
+		hasOpenID = hasOpenID || scope == models.ScopeOpenID
+	}
+	if !hasOpenID {
+		return status.Error(codes.InvalidArgument, "scope ต้องมี openid")
+	}
+	return nil
+}
+
+// โดเมนอีเมลที่สร้างผู้ใช้ใหม่ได้ เช่น example.com
+func ValidateEmailDomains(domains []string) error {
+	for _, domain := range domains {
+		if err := ValidateEmailFormat("user@" + domain); err != nil {
+			return status.Errorf(codes.InvalidArgument, "โดเมน %q ไม่ถูกต้อง", domain)
+		}
+	}
+	return nil
+}
+
+// การตั้งค่าของ SAML IdP: SSO URL แบบ https ที่ไม่ชี้ไปยัง localhost หรือที่อยู่ภายใน, certificate แบบ PEM และ role ตามกลุ่ม
+// allowLocal (FEDERATION_ALLOW_LOCAL สำหรับเครื่อง dev เท่านั้น) ยอมให้ SSO URL ใช้ http://localhost และที่อยู่ภายใน
+func ValidateSAMLSettings(s *models.SAMLSettings, tenantID string, allowLocal bool) error {
+	if strings.TrimSpace(s.EntityID) == "" || len(s.EntityID) > 1024 {
+		return status.Error(codes.InvalidArgument, "ต้องระบุ entity ID ของ IdP (ไม่เกิน 1024 ตัวอักษร)")
+	}
+	u, err := url.Parse(s.SSOURL)
+	if err != nil || u.Host == "" || u.Fragment != "" {
+		return status.Error(codes.InvalidArgument, "SSO URL ของ IdP ต้องเป็น URL แบบ https")
+	}
+	if allowLocal {
+		if u.Scheme != "https" && !(u.Scheme == "http" && isLoopbackHost(u.Hostname())) {
+			return status.Error(codes.InvalidArgument, "SSO URL ของ IdP ต้องเป็น URL แบบ https (http ใช้ได้เฉพาะ localhost)")
+		}
+	} else if u.Scheme != "https" {
+		return status.Error(codes.InvalidArgument, "SSO URL ของ IdP ต้องเป็น URL แบบ https")
+	} else if isLocalHost(u.Hostname()) {
+		return status.Error(codes.InvalidArgument, "SSO URL ของ IdP ต้องชี้ไปยังที่อยู่สาธารณะ (ไม่ใช่ loopback, private หรือ link-local)")
+	}
+	if _, err := federation.ParseSAMLCertificates(s.Certificates); err != nil {
+		return status.Error(codes.InvalidArgument, "ต้องระบุ certificate ของ IdP แบบ PEM อย่างน้อยหนึ่งใบ")
+	}
+	for _, attr := range []string{s.EmailAttribute, s.NameAttribute, s.UsernameAttribute, s.GroupsAttribute} {
+		if len(attr) > 255 {
+			return status.Error(codes.InvalidArgument, "ชื่อ attribute ต้องมีความยาวไม่เกิน 255 ตัวอักษร")
+		}
+	}
+	for _, mapping := range s.RoleMappings {
+		if strings.TrimSpace(mapping.Group) == "" {
+			return status.Error(codes.InvalidArgument, "ต้องระบุกลุ่มของ role mapping")
+		}
+		if err := ValidateRole(mapping.Role, tenantID); err != nil {
+			return err
+		}
+	}
+	if len(s.RoleMappings) > 0 {
+		if err := ValidateRole(s.DefaultRole, tenantID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
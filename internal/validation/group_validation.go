@@ -0,0 +1,35 @@
+package validation
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	models "auth-microservice/internal/model"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func ValidateGroupName(name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > 100 {
+		return status.Error(codes.InvalidArgument, "ชื่อกลุ่มต้องมีความยาว 1-100 ตัวอักษร")
+	}
+	return nil
+}
+
+func ValidateGroupDescription(description string) error {
+	if utf8.RuneCountInString(description) > 500 {
+		return status.Error(codes.InvalidArgument, "คำอธิบายกลุ่มต้องมีความยาวไม่เกิน 500 ตัวอักษร")
+	}
+	return nil
+}
+
+// role ของสมาชิกในกลุ่มต้องเป็น owner, manager หรือ member
+func ValidateGroupRole(role string) error {
+	switch role {
+	case models.GroupRoleOwner, models.GroupRoleManager, models.GroupRoleMember:
+		return nil
+	}
+	return status.Error(codes.InvalidArgument, "role ในกลุ่มต้องเป็น owner, manager หรือ member")
+}
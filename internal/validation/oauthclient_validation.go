@@ -0,0 +1,80 @@
+package validation
+
+import (
+	"net"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/netguard"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// scope ของ OpenID Connect ที่ OAuth client ขอได้เพิ่มจาก scope ของ API
+var oidcScopes = map[string]bool{
+	models.ScopeOpenID:        true,
+	models.ScopeProfile:       true,
+	models.ScopeEmail:         true,
+	models.ScopePhone:         true,
+	models.ScopeOfflineAccess: true,
+}
+
+func ValidateOAuthClientName(name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > 100 {
+		return status.Error(codes.InvalidArgument, "ชื่อ client ต้องมีความยาว 1-100 ตัวอักษร")
+	}
+	return nil
+}
+
+// ต้องมีอย่างน้อย 1 scope และทุก scope ต้องเป็น scope ของ OpenID Connect หรือของ API
+func ValidateOAuthScopes(scopes []string) error {
+	if len(scopes) == 0 {
+		return status.Error(codes.InvalidArgument, "ต้องระบุ scope อย่างน้อย 1 scope")
+	}
+	for _, scope := range scopes {
+		if !oidcScopes[scope] && !knownScopes[scope] {
+			return status.Errorf(codes.InvalidArgument, "ไม่รองรับ scope %q", scope)
+		}
+	}
+	return nil
+}
+
+// redirect URI ต้องเป็น URL เต็มแบบ https (หรือ http เฉพาะ localhost สำหรับแอปบนเครื่อง) และไม่มี fragment
+func ValidateRedirectURIs(uris []string) error {
+	if len(uris) == 0 || len(uris) > 10 {
+		return status.Error(codes.InvalidArgument, "ต้องระบุ redirect URI 1-10 รายการ")
+	}
+	for _, raw := range uris {
+		u, err := url.Parse(raw)
+		if err != nil || u.Host == "" || u.Fragment != "" || strings.Contains(raw, "#") {
+			return status.Errorf(codes.InvalidArgument, "redirect URI %q ไม่ถูกต้อง", raw)
+		}
+		if u.Scheme != "https" && !(u.Scheme == "http" && isLoopbackHost(u.Hostname())) {
+			return status.Errorf(codes.InvalidArgument, "redirect URI %q ต้องเป็น https (http ใช้ได้เฉพาะ localhost)", raw)
+		}
+	}
+	return nil
+}
+
+// host ที่ชี้เข้าเครื่องหรือเครือข่ายภายใน: localhost, *.localhost หรือ IP ที่ไม่ใช่ที่อยู่สาธารณะ
+// (ชื่อโดเมนอื่นตรวจด้วย netguard ตอนเชื่อมต่อ)
+func isLocalHost(host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && !netguard.IsPublicIP(ip)
+}
+
+func isLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
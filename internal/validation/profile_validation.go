@@ -0,0 +1,154 @@
+package validation
+
+import (
+	"net/url"
+	"regexp"
+	"strconv"
+	"time"
+
+	models "auth-microservice/internal/model"
+
+	"golang.org/x/text/language"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	phoneRegexp       = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`)
+	metadataKeyRegexp = regexp.MustCompile(`^[a-z][a-z0-9_]{0,63}$`)
+)
+
+// Validate Display Name
+func ValidateDisplayName(name string) error {
+	if len([]rune(name)) > 64 {
+		return status.Error(codes.InvalidArgument, "ชื่อที่แสดงต้องมีความยาวไม่เกิน 64 ตัวอักษร")
+	}
+	return nil
+}
+
+// Validate Avatar URL (ค่าว่าง = ลบรูปโปรไฟล์)
+func ValidateAvatarURL(avatarURL string) error {
+	if avatarURL == "" {
+		return nil
+	}
+	u, err := url.Parse(avatarURL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" || len(avatarURL) > 2048 {
+		return status.Error(codes.InvalidArgument, "URL รูปโปรไฟล์ไม่ถูกต้อง")
+	}
+	return nil
+}
+
+// Validate Locale (BCP 47 เช่น "th-TH")
+func ValidateLocale(locale string) error {
+	if locale == "" {
+		return nil
+	}
+	if _, err := language.Parse(locale); err != nil {
+		return status.Error(codes.InvalidArgument, "รูปแบบ locale ไม่ถูกต้อง")
+	}
+	return nil
+}
+
+// Validate Timezone (ชื่อจาก IANA เช่น "Asia/Bangkok")
+func ValidateTimezone(tz string) error {
+	if tz == "" {
+		return nil
+	}
+	if _, err := time.LoadLocation(tz); err != nil {
+		return status.Error(codes.InvalidArgument, "เขตเวลาไม่ถูกต้อง")
+	}
+	return nil
+}
+
+// Validate Phone (รูปแบบ E.164 เช่น "+66812345678")
+func ValidatePhone(phone string) error {
+	if phone == "" {
+		return nil
+	}
+	if !phoneRegexp.MatchString(phone) {
+		return status.Error(codes.InvalidArgument, "เบอร์โทรศัพท์ต้องอยู่ในรูปแบบ E.164 เช่น +66812345678")
+	}
+	return nil
+}
+
+// Validate Profile Schema ที่ admin กำหนด
+func ValidateProfileSchema(attributes []models.ProfileAttribute) error {
+	seen := map[string]bool{}
+	for _, attr := range attributes {
+		if !metadataKeyRegexp.MatchString(attr.Key) {
+			return status.Errorf(codes.InvalidArgument, "key %q ต้องเป็นตัวพิมพ์เล็ก ตัวเลข หรือ _ และขึ้นต้นด้วยตัวอักษร", attr.Key)
+		}
+		if seen[attr.Key] {
+			return status.Errorf(codes.InvalidArgument, "key %q ซ้ำกัน", attr.Key)
+		}
+		seen[attr.Key] = true
+
+		switch attr.Type {
+		case "string", "number", "boolean":
+		default:
+			return status.Errorf(codes.InvalidArgument, "ชนิดข้อมูลของ %q ต้องเป็น string, number หรือ boolean", attr.Key)
+		}
+		if attr.MaxLength < 0 {
+			return status.Errorf(codes.InvalidArgument, "maxLength ของ %q ต้องไม่ติดลบ", attr.Key)
+		}
+	}
+	return nil
+}
+
+// Validate ค่า metadata หนึ่งตัวตาม schema
+func ValidateMetadataValue(key string, value string, schema []models.ProfileAttribute) error {
+	attr, ok := FindProfileAttribute(key, schema)
+	if !ok {
+		return status.Errorf(codes.InvalidArgument, "ไม่อนุญาตให้ใช้ key %q ใน metadata", key)
+	}
+
+	if attr.MaxLength > 0 && len([]rune(value)) > attr.MaxLength {
+		return status.Errorf(codes.InvalidArgument, "ค่าของ %q ต้องมีความยาวไม่เกิน %d ตัวอักษร", key, attr.MaxLength)
+	}
+
+	switch attr.Type {
+	case "number":
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return status.Errorf(codes.InvalidArgument, "ค่าของ %q ต้องเป็นตัวเลข", key)
+		}
+	case "boolean":
+		if _, err := strconv.ParseBool(value); err != nil {
+			return status.Errorf(codes.InvalidArgument, "ค่าของ %q ต้องเป็น true หรือ false", key)
+		}
+	}
+
+	if len(attr.AllowedValues) > 0 {
+		for _, allowed := range attr.AllowedValues {
+			if value == allowed {
+				return nil
+			}
+		}
+		return status.Errorf(codes.InvalidArgument, "ค่าของ %q ไม่อยู่ในรายการที่อนุญาต", key)
+	}
+	return nil
+}
+
+// Validate metadata ทั้งชุดตาม schema (รวมถึง key ที่ required)
+func ValidateMetadata(metadata map[string]string, schema []models.ProfileAttribute) error {
+	for key, value := range metadata {
+		if err := ValidateMetadataValue(key, value, schema); err != nil {
+			return err
+		}
+	}
+	for _, attr := range schema {
+		if attr.Required && metadata[attr.Key] == "" {
+			return status.Errorf(codes.InvalidArgument, "ต้องระบุ %q ใน metadata", attr.Key)
+		}
+	}
+	return nil
+}
+
+// ค้นหานิยามของ key ใน schema
+func FindProfileAttribute(key string, schema []models.ProfileAttribute) (models.ProfileAttribute, bool) {
+	for _, attr := range schema {
+		if attr.Key == key {
+			return attr, true
+		}
+	}
+	return models.ProfileAttribute{}, false
+}
@@ -0,0 +1,42 @@
+package validation
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"auth-microservice/internal/auth"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func ValidateServiceAccountName(name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > 100 {
+		return status.Error(codes.InvalidArgument, "ชื่อ service account ต้องมีความยาว 1-100 ตัวอักษร")
+	}
+	return nil
+}
+
+func ValidateServiceAccountDescription(description string) error {
+	if utf8.RuneCountInString(description) > 500 {
+		return status.Error(codes.InvalidArgument, "คำอธิบาย service account ต้องมีความยาวไม่เกิน 500 ตัวอักษร")
+	}
+	return nil
+}
+
+// public key ต้องเป็น RSA หรือ EC ในรูปแบบ PEM
+func ValidateClientPublicKey(pem string) error {
+	if _, err := auth.ParsePublicKeyPEM(pem); err != nil {
+		return status.Error(codes.InvalidArgument, "public key ต้องเป็น RSA หรือ EC ในรูปแบบ PEM")
+	}
+	return nil
+}
+
+// kid ของ public key (ถ้าระบุ) ยาวไม่เกิน 64 ตัวอักษรและไม่มีช่องว่าง
+func ValidateClientKeyID(keyID string) error {
+	if len(keyID) > 64 || strings.ContainsAny(keyID, " \t\r\n") {
+		return status.Error(codes.InvalidArgument, "keyId ต้องยาวไม่เกิน 64 ตัวอักษรและไม่มีช่องว่าง")
+	}
+	return nil
+}
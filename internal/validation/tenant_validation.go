@@ -0,0 +1,64 @@
+package validation
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	models "auth-microservice/internal/model"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ID ของ tenant ใช้ใน metadata, token และ key ต่าง ๆ จึงจำกัดเป็นตัวพิมพ์เล็ก ตัวเลข และ -
+var tenantIDRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,62}$`)
+
+// ขอบเขตของการตั้งค่า tenant
+const (
+	minAccessTokenTTL  = time.Minute
+	maxAccessTokenTTL  = 24 * time.Hour
+	maxSessionTTL      = 30 * 24 * time.Hour
+	maxPasswordLength  = 72 // bcrypt ใช้แค่ 72 ไบต์แรก
+	maxPasswordHistory = 24 // จำนวนรหัสผ่านเก่าที่จำได้สูงสุด
+)
+
+func ValidateTenantID(id string) error {
+	if !tenantIDRegexp.MatchString(id) {
+		return status.Error(codes.InvalidArgument, "ID ของ tenant ต้องเป็นตัวพิมพ์เล็ก ตัวเลข หรือ - ยาว 2-63 ตัวอักษร")
+	}
+	return nil
+}
+
+func ValidateTenantName(name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > 100 {
+		return status.Error(codes.InvalidArgument, "ชื่อ tenant ต้องมีความยาว 1-100 ตัวอักษร")
+	}
+	return nil
+}
+
+// ตรวจสอบนโยบายรหัสผ่านที่ admin กำหนด
+func ValidatePasswordPolicy(policy models.PasswordPolicy) error {
+	if policy.MinLength < 6 || policy.MinLength > maxPasswordLength {
+		return status.Errorf(codes.InvalidArgument, "ความยาวรหัสผ่านขั้นต่ำต้องอยู่ระหว่าง 6 ถึง %d", maxPasswordLength)
+	}
+	if policy.HistorySize < 0 || policy.HistorySize > maxPasswordHistory {
+		return status.Errorf(codes.InvalidArgument, "จำนวนรหัสผ่านเก่าที่ห้ามใช้ซ้ำต้องอยู่ระหว่าง 0 ถึง %d", maxPasswordHistory)
+	}
+	if policy.MaxAge < 0 {
+		return status.Error(codes.InvalidArgument, "อายุรหัสผ่านต้องไม่ติดลบ")
+	}
+	return nil
+}
+
+// ตรวจสอบอายุของ token (session ต้องไม่สั้นกว่า token)
+func ValidateTokenTTLs(accessTokenTTL time.Duration, sessionTTL time.Duration) error {
+	if accessTokenTTL < minAccessTokenTTL || accessTokenTTL > maxAccessTokenTTL {
+		return status.Error(codes.InvalidArgument, "อายุของ token ต้องอยู่ระหว่าง 1 นาทีถึง 24 ชั่วโมง")
+	}
+	if sessionTTL < accessTokenTTL || sessionTTL > maxSessionTTL {
+		return status.Error(codes.InvalidArgument, "อายุของ session ต้องไม่น้อยกว่าอายุของ token และไม่เกิน 30 วัน")
+	}
+	return nil
+}
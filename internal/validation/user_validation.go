@@ -5,26 +5,37 @@ import (
 	"regexp"
 	"strings"
 
-	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/mongo"
+	"auth-microservice/internal/auth"
+	models "auth-microservice/internal/model"
+
 	"golang.org/x/crypto/bcrypt"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
-// Validate Email
-func ValidateEmail(email string, ctx context.Context, userCollection *mongo.Collection) error {
-	filterEmail := bson.M{"email": email}
-	count, err := userCollection.CountDocuments(ctx, filterEmail)
-	re := regexp.MustCompile(`(?i)^[a-z0-9._%+\-]+@[a-z0-9.\-]+\.[a-z]{2,}$`)
+// ตรวจสอบว่าอีเมลหรือ username ถูกใช้แล้วใน tenant หรือไม่ (store.UserStore ของทุก backend ใช้ได้)
+type UserLookup interface {
+	EmailTaken(ctx context.Context, tenantID string, email string) (bool, error)
+	UsernameTaken(ctx context.Context, tenantID string, username string) (bool, error)
+}
+
+// Validate Email (ต้องไม่ซ้ำกับผู้ใช้อื่นใน tenant เดียวกัน)
+func ValidateEmail(email string, ctx context.Context, users UserLookup, tenantID string) error {
+	taken, err := users.EmailTaken(ctx, tenantID, email)
 	if err != nil {
 		return err
 	}
 
-	if count > 0 {
+	if taken {
 		return status.Error(codes.AlreadyExists, "อีเมลถูกใช้งานแล้ว")
 	}
 
+	return ValidateEmailFormat(email)
+}
+
+// ตรวจสอบรูปแบบอีเมลอย่างเดียว (เช่น อีเมลที่ถูกเชิญซึ่งอาจเป็นผู้ใช้อยู่แล้ว)
+func ValidateEmailFormat(email string) error {
+	re := regexp.MustCompile(`(?i)^[a-z0-9._%+\-]+@[a-z0-9.\-]+\.[a-z]{2,}$`)
 	if strings.TrimSpace(email) == "" {
 		return status.Error(codes.InvalidArgument, "อีเมลไม่สามารถเว้นว่างได้")
 	}
@@ -40,18 +51,32 @@ func ValidateEmail(email string, ctx context.Context, userCollection *mongo.Coll
 	return nil
 }
 
-// Validate Password
-func ValidatePassword(password string) (string, error) {
-	if len(password) < 6 {
-		return "", status.Error(codes.InvalidArgument, "พาสเวิร์ดต้องมีความยาวอย่างน้อย 6 ตัวอักษร")
+// Validate Password ตามนโยบายรหัสผ่านของ tenant แล้วคืน hash
+func ValidatePassword(password string, policy models.PasswordPolicy) (string, error) {
+	if len(password) < policy.MinLength {
+		return "", status.Errorf(codes.InvalidArgument, "พาสเวิร์ดต้องมีความยาวอย่างน้อย %d ตัวอักษร", policy.MinLength)
+	}
+	if len(password) > maxPasswordLength {
+		return "", status.Errorf(codes.InvalidArgument, "พาสเวิร์ดต้องมีความยาวไม่เกิน %d ตัวอักษร", maxPasswordLength)
 	}
 
-	hasNumber := regexp.MustCompile("[0-9]").MatchString(password)
-	hasUpper := regexp.MustCompile("[A-Z]").MatchString(password)
-	hasLower := regexp.MustCompile("[a-z]").MatchString(password)
-
-	if !hasNumber || !hasUpper || !hasLower {
-		return "", status.Error(codes.InvalidArgument, "พาสเวิร์ดต้องมีตัวเลข ตัวพิมพ์ใหญ่ และตัวพิมพ์เล็กอย่างน้อย 1 ตัว")
+	var missing []string
+	for _, rule := range []struct {
+		required bool
+		pattern  string
+		name     string
+	}{
+		{policy.RequireNumber, "[0-9]", "ตัวเลข"},
+		{policy.RequireUpper, "[A-Z]", "ตัวพิมพ์ใหญ่"},
+		{policy.RequireLower, "[a-z]", "ตัวพิมพ์เล็ก"},
+		{policy.RequireSymbol, "[^A-Za-z0-9]", "อักขระพิเศษ"},
+	} {
+		if rule.required && !regexp.MustCompile(rule.pattern).MatchString(password) {
+			missing = append(missing, rule.name)
+		}
+	}
+	if len(missing) > 0 {
+		return "", status.Errorf(codes.InvalidArgument, "พาสเวิร์ดต้องมี%sอย่างน้อย 1 ตัว", strings.Join(missing, " "))
 	}
 
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
@@ -62,15 +87,22 @@ func ValidatePassword(password string) (string, error) {
 	return string(hashedPassword), nil
 }
 
-// Validate Username
-func ValidateUsername(username string, ctx context.Context, userCollection *mongo.Collection) error {
-	filterUsername := bson.M{"username": username}
-	count, err := userCollection.CountDocuments(ctx, filterUsername)
+// ตรวจสอบ hash รหัสผ่านที่นำเข้าจากระบบเดิม (bcrypt หรือ argon2id / argon2i แบบ PHC)
+func ValidatePasswordHash(hash string) error {
+	if err := auth.CheckPasswordHash(hash); err != nil {
+		return status.Error(codes.InvalidArgument, "passwordHash ต้องเป็น bcrypt หรือ argon2 ในรูปแบบที่รองรับ")
+	}
+	return nil
+}
+
+// Validate Username (ต้องไม่ซ้ำกับผู้ใช้อื่นใน tenant เดียวกัน)
+func ValidateUsername(username string, ctx context.Context, users UserLookup, tenantID string) error {
+	taken, err := users.UsernameTaken(ctx, tenantID, username)
 	if err != nil {
 		return err
 	}
 
-	if count > 0 {
+	if taken {
 		return status.Error(codes.AlreadyExists, "ชื่อผู้ใช้ถูกใช้งานแล้ว")
 	}
 
@@ -0,0 +1,57 @@
+package validation
+
+import (
+	"net/url"
+	"regexp"
+	"unicode/utf8"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ชื่อเหตุการณ์ เช่น "user.deleted" หรือ wildcard "user.*" และ "*"
+var webhookEventPattern = regexp.MustCompile(`^(\*|[a-z_]+(\.([a-z_]+|\*))*)$`)
+
+// URL ของ webhook ต้องเป็น https ไปยัง host สาธารณะ และไม่มีข้อมูลผู้ใช้หรือ fragment
+// allowLocal (สำหรับเครื่อง dev เท่านั้น) ยอมให้ใช้ http://localhost และที่อยู่ภายใน
+// host ที่เป็นชื่อโดเมนต้อง resolve และตรวจด้วย netguard.IsPublicIP อีกครั้ง (ตอนลงทะเบียนและตอนส่ง)
+func ValidateWebhookURL(raw string, allowLocal bool) error {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" || u.User != nil || u.Fragment != "" || len(raw) > 2048 {
+		return status.Error(codes.InvalidArgument, "URL ของ webhook ไม่ถูกต้อง")
+	}
+	host := u.Hostname()
+	if allowLocal {
+		if u.Scheme != "https" && !(u.Scheme == "http" && isLoopbackHost(host)) {
+			return status.Error(codes.InvalidArgument, "URL ของ webhook ต้องเป็น https (http ใช้ได้เฉพาะ localhost)")
+		}
+		return nil
+	}
+	if u.Scheme != "https" {
+		return status.Error(codes.InvalidArgument, "URL ของ webhook ต้องเป็น https")
+	}
+	if isLocalHost(host) {
+		return status.Error(codes.InvalidArgument, "URL ของ webhook ต้องชี้ไปยังที่อยู่สาธารณะ (ไม่ใช่ loopback, private หรือ link-local)")
+	}
+	return nil
+}
+
+// ต้องระบุเหตุการณ์ 1-50 รายการ
+func ValidateWebhookEvents(events []string) error {
+	if len(events) == 0 || len(events) > 50 {
+		return status.Error(codes.InvalidArgument, "ต้องระบุเหตุการณ์ 1-50 รายการ")
+	}
+	for _, event := range events {
+		if len(event) > 100 || !webhookEventPattern.MatchString(event) {
+			return status.Errorf(codes.InvalidArgument, "ชื่อเหตุการณ์ %q ไม่ถูกต้อง", event)
+		}
+	}
+	return nil
+}
+
+func ValidateWebhookDescription(description string) error {
+	if utf8.RuneCountInString(description) > 500 {
+		return status.Error(codes.InvalidArgument, "คำอธิบาย webhook ต้องมีความยาวไม่เกิน 500 ตัวอักษร")
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package webauthn
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/x509"
+	"encoding/asn1"
+	"errors"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// attestation format ที่ไม่รองรับ (รองรับ none, packed และ fido-u2f)
+var ErrUnsupportedAttestation = errors.New("webauthn: unsupported attestation format")
+
+// extension ของ certificate ที่ระบุ AAGUID ของ authenticator (id-fido-gen-ce-aaguid)
+var oidFIDOAAGUID = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 45724, 1, 1, 4}
+
+type attestationStatement struct {
+	Alg int64    `cbor:"alg"`
+	Sig []byte   `cbor:"sig"`
+	X5C [][]byte `cbor:"x5c"`
+}
+
+// ตรวจสอบ attestation statement ตาม format (WebAuthn ข้อ 8)
+// ตรวจลายเซ็นและ certificate ของ authenticator แต่ไม่ตรวจ chain กับ FIDO metadata service
+func verifyAttestation(format string, rawStmt []byte, authData []byte, clientDataHash []byte, data *authenticatorData, key *coseKey) error {
+	signed := append(append([]byte{}, authData...), clientDataHash...)
+	switch format {
+	case "none":
+		var stmt map[string]cbor.RawMessage
+		if err := cbor.Unmarshal(rawStmt, &stmt); err != nil || len(stmt) != 0 {
+			return ErrInvalidResponse
+		}
+		return nil
+
+	case "packed":
+		var stmt attestationStatement
+		if err := cbor.Unmarshal(rawStmt, &stmt); err != nil {
+			return ErrInvalidResponse
+		}
+		if len(stmt.X5C) == 0 {
+			// self attestation: เซ็นด้วย key ของ credential เอง
+			if stmt.Alg != key.alg {
+				return ErrInvalidResponse
+			}
+			return verifySignature(stmt.Alg, key.public, signed, stmt.Sig)
+		}
+		cert, err := attestationCertificate(stmt.X5C[0])
+		if err != nil {
+			return err
+		}
+		if aaguid, ok := certificateAAGUID(cert); ok && !bytes.Equal(aaguid, data.AAGUID) {
+			return ErrInvalidResponse
+		}
+		return verifySignature(stmt.Alg, cert.PublicKey, signed, stmt.Sig)
+
+	case "fido-u2f":
+		var stmt attestationStatement
+		if err := cbor.Unmarshal(rawStmt, &stmt); err != nil || len(stmt.X5C) != 1 {
+			return ErrInvalidResponse
+		}
+		cert, err := attestationCertificate(stmt.X5C[0])
+		if err != nil {
+			return err
+		}
+		certKey, ok1 := cert.PublicKey.(*ecdsa.PublicKey)
+		credKey, ok2 := key.public.(*ecdsa.PublicKey)
+		if !ok1 || !ok2 || certKey.Curve != elliptic.P256() {
+			return ErrInvalidResponse
+		}
+		// verificationData = 0x00 | rpIdHash | clientDataHash | credentialId | public key (uncompressed)
+		var message bytes.Buffer
+		message.WriteByte(0)
+		message.Write(data.RPIDHash)
+		message.Write(clientDataHash)
+		message.Write(data.CredentialID)
+		message.WriteByte(4)
+		message.Write(credKey.X.FillBytes(make([]byte, 32)))
+		message.Write(credKey.Y.FillBytes(make([]byte, 32)))
+		return verifySignature(AlgES256, certKey, message.Bytes(), stmt.Sig)
+	}
+	return ErrUnsupportedAttestation
+}
+
+// certificate ของ authenticator ต้องเป็น X.509 v3 และไม่ใช่ CA
+func attestationCertificate(der []byte) (*x509.Certificate, error) {
+	cert, err := x509.ParseCertificate(der)
+	if err != nil || cert.Version != 3 || (cert.BasicConstraintsValid && cert.IsCA) {
+		return nil, ErrInvalidResponse
+	}
+	return cert, nil
+}
+
+func certificateAAGUID(cert *x509.Certificate) ([]byte, bool) {
+	for _, ext := range cert.Extensions {
+		if ext.Id.Equal(oidFIDOAAGUID) {
+			var aaguid []byte
+			if _, err := asn1.Unmarshal(ext.Value, &aaguid); err != nil {
+				return nil, true
+			}
+			return aaguid, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,98 @@
+package webauthn
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"math/big"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// algorithm ตาม COSE (RFC 9053) ที่รองรับ เรียงตามลำดับที่ขอใน options
+const (
+	AlgES256 int64 = -7
+	AlgEdDSA int64 = -8
+	AlgRS256 int64 = -257
+)
+
+var supportedAlgorithms = []int64{AlgES256, AlgEdDSA, AlgRS256}
+
+// key type และ curve ของ COSE
+const (
+	coseKtyOKP     = 1
+	coseKtyEC2     = 2
+	coseKtyRSA     = 3
+	coseCrvP256    = 1
+	coseCrvEd25519 = 6
+)
+
+type coseKey struct {
+	alg    int64
+	public crypto.PublicKey
+}
+
+// แปลง COSE_Key เป็น public key (ตรวจว่า key type, curve และ algorithm สอดคล้องกัน)
+func parseCOSEKey(raw []byte) (*coseKey, error) {
+	var fields map[int64]cbor.RawMessage
+	if err := cbor.Unmarshal(raw, &fields); err != nil {
+		return nil, ErrInvalidResponse
+	}
+	var kty, alg int64
+	if cbor.Unmarshal(fields[1], &kty) != nil || cbor.Unmarshal(fields[3], &alg) != nil {
+		return nil, ErrInvalidResponse
+	}
+
+	switch {
+	case kty == coseKtyEC2 && alg == AlgES256:
+		var crv int64
+		var x, y []byte
+		if cbor.Unmarshal(fields[-1], &crv) != nil || cbor.Unmarshal(fields[-2], &x) != nil || cbor.Unmarshal(fields[-3], &y) != nil ||
+			crv != coseCrvP256 || len(x) != 32 || len(y) != 32 {
+			return nil, ErrInvalidResponse
+		}
+		pub := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !pub.Curve.IsOnCurve(pub.X, pub.Y) {
+			return nil, ErrInvalidResponse
+		}
+		return &coseKey{alg: alg, public: pub}, nil
+	case kty == coseKtyOKP && alg == AlgEdDSA:
+		var crv int64
+		var x []byte
+		if cbor.Unmarshal(fields[-1], &crv) != nil || cbor.Unmarshal(fields[-2], &x) != nil ||
+			crv != coseCrvEd25519 || len(x) != ed25519.PublicKeySize {
+			return nil, ErrInvalidResponse
+		}
+		return &coseKey{alg: alg, public: ed25519.PublicKey(x)}, nil
+	case kty == coseKtyRSA && alg == AlgRS256:
+		var n, e []byte
+		if cbor.Unmarshal(fields[-1], &n) != nil || cbor.Unmarshal(fields[-2], &e) != nil || len(n) < 256 || len(e) == 0 || len(e) > 4 {
+			return nil, ErrInvalidResponse
+		}
+		return &coseKey{alg: alg, public: &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}}, nil
+	}
+	return nil, ErrInvalidResponse
+}
+
+// ตรวจสอบลายเซ็นตาม algorithm (ES256 ใช้ลายเซ็นแบบ ASN.1 DER ตามที่ WebAuthn กำหนด)
+func verifySignature(alg int64, pub crypto.PublicKey, data []byte, sig []byte) error {
+	digest := sha256.Sum256(data)
+	switch key := pub.(type) {
+	case *ecdsa.PublicKey:
+		if alg == AlgES256 && ecdsa.VerifyASN1(key, digest[:], sig) {
+			return nil
+		}
+	case ed25519.PublicKey:
+		if alg == AlgEdDSA && ed25519.Verify(key, data, sig) {
+			return nil
+		}
+	case *rsa.PublicKey:
+		if alg == AlgRS256 && rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], sig) == nil {
+			return nil
+		}
+	}
+	return ErrInvalidResponse
+}
@@ -0,0 +1,86 @@
+package webauthn
+
+import "encoding/json"
+
+// options ในรูปแบบ JSON ที่ browser แปลงได้ด้วย PublicKeyCredential.parseCreationOptionsFromJSON
+// และ parseRequestOptionsFromJSON (ข้อมูล binary เป็น base64url)
+type creationOptions struct {
+	RP struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"rp"`
+	User struct {
+		ID          string `json:"id"`
+		Name        string `json:"name"`
+		DisplayName string `json:"displayName"`
+	} `json:"user"`
+	Challenge              string                 `json:"challenge"`
+	PubKeyCredParams       []credentialParameter  `json:"pubKeyCredParams"`
+	Timeout                int64                  `json:"timeout"`
+	ExcludeCredentials     []credentialDescriptor `json:"excludeCredentials"`
+	AuthenticatorSelection struct {
+		ResidentKey        string `json:"residentKey"`
+		RequireResidentKey bool   `json:"requireResidentKey"`
+		UserVerification   string `json:"userVerification"`
+	} `json:"authenticatorSelection"`
+	Attestation string `json:"attestation"`
+}
+
+type requestOptions struct {
+	Challenge        string                 `json:"challenge"`
+	Timeout          int64                  `json:"timeout"`
+	RPID             string                 `json:"rpId"`
+	AllowCredentials []credentialDescriptor `json:"allowCredentials"`
+	UserVerification string                 `json:"userVerification"`
+}
+
+type credentialParameter struct {
+	Type string `json:"type"`
+	Alg  int64  `json:"alg"`
+}
+
+type credentialDescriptor struct {
+	Type       string   `json:"type"`
+	ID         string   `json:"id"`
+	Transports []string `json:"transports,omitempty"`
+}
+
+// options สำหรับ navigator.credentials.create (ขอ discoverable credential เพื่อให้เข้าสู่ระบบได้โดยไม่ต้องกรอกอีเมล)
+// exclude คือ passkey ที่ผู้ใช้มีอยู่แล้ว เพื่อไม่ให้ลงทะเบียน authenticator เดิมซ้ำ
+func (rp *RelyingParty) CreationOptions(user User, challenge []byte, exclude []Descriptor) ([]byte, error) {
+	var opts creationOptions
+	opts.RP.ID = rp.Config.RPID
+	opts.RP.Name = rp.Config.RPName
+	opts.User.ID = EncodeBase64URL(user.ID)
+	opts.User.Name = user.Name
+	opts.User.DisplayName = user.DisplayName
+	opts.Challenge = EncodeBase64URL(challenge)
+	for _, alg := range supportedAlgorithms {
+		opts.PubKeyCredParams = append(opts.PubKeyCredParams, credentialParameter{Type: "public-key", Alg: alg})
+	}
+	opts.Timeout = rp.Config.Timeout.Milliseconds()
+	opts.ExcludeCredentials = descriptors(exclude)
+	opts.AuthenticatorSelection.ResidentKey = "preferred"
+	opts.AuthenticatorSelection.UserVerification = VerificationPreferred
+	opts.Attestation = rp.Config.Attestation
+	return json.Marshal(opts)
+}
+
+// options สำหรับ navigator.credentials.get (allow ว่าง = ให้ผู้ใช้เลือก passkey ที่เก็บไว้ใน authenticator)
+func (rp *RelyingParty) RequestOptions(challenge []byte, allow []Descriptor, userVerification string) ([]byte, error) {
+	return json.Marshal(requestOptions{
+		Challenge:        EncodeBase64URL(challenge),
+		Timeout:          rp.Config.Timeout.Milliseconds(),
+		RPID:             rp.Config.RPID,
+		AllowCredentials: descriptors(allow),
+		UserVerification: userVerification,
+	})
+}
+
+func descriptors(list []Descriptor) []credentialDescriptor {
+	out := []credentialDescriptor{}
+	for _, d := range list {
+		out = append(out, credentialDescriptor{Type: "public-key", ID: EncodeBase64URL(d.ID), Transports: d.Transports})
+	}
+	return out
+}
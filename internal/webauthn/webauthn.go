@@ -0,0 +1,340 @@
+// Package webauthn ทำหน้าที่ relying party ของ WebAuthn (passkey): สร้าง options ให้ browser
+// และตรวจสอบ attestation ตอนลงทะเบียนและ assertion ตอนเข้าสู่ระบบ
+package webauthn
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+const (
+	defaultTimeout = 5 * time.Minute
+
+	// flag ใน authenticator data
+	flagUserPresent    = 0x01
+	flagUserVerified   = 0x04
+	flagBackupEligible = 0x08
+	flagBackedUp       = 0x10
+	flagAttestedData   = 0x40
+	flagExtensions     = 0x80
+
+	// ค่าของ userVerification ใน options
+	VerificationRequired  = "required"
+	VerificationPreferred = "preferred"
+)
+
+var (
+	// response ของ authenticator ไม่ผ่านการตรวจสอบ (ลายเซ็น, challenge, origin, RP ID หรือรูปแบบข้อมูล)
+	ErrInvalidResponse = errors.New("webauthn: invalid response")
+	// sign counter ไม่เพิ่มขึ้น (authenticator อาจถูกคัดลอก)
+	ErrSignCount = errors.New("webauthn: sign counter did not increase")
+)
+
+// การตั้งค่าของ relying party
+type Config struct {
+	RPID        string   // โดเมนที่ผูกกับ passkey (เช่น example.com) ต้องเป็นโดเมนเดียวกับหรือโดเมนแม่ของ origin
+	RPName      string   // ชื่อที่ authenticator แสดงให้ผู้ใช้เห็น
+	Origins     []string // origin ของหน้าเว็บที่เรียก WebAuthn ได้ (เช่น https://login.example.com)
+	Attestation string   // attestation ที่ขอตอนลงทะเบียน: none (ค่าว่าง) หรือ direct
+	Timeout     time.Duration
+}
+
+// ผู้ใช้ที่ลงทะเบียน passkey (ID คือ user handle ที่ authenticator ส่งกลับมาตอนเข้าสู่ระบบ)
+type User struct {
+	ID          []byte
+	Name        string
+	DisplayName string
+}
+
+// credential ที่ระบุใน excludeCredentials / allowCredentials
+type Descriptor struct {
+	ID         []byte
+	Transports []string
+}
+
+// passkey ที่ลงทะเบียนสำเร็จ (ข้อมูลที่ต้องเก็บไว้ตรวจสอบ assertion)
+type Credential struct {
+	ID                []byte
+	PublicKey         []byte // COSE_Key
+	Algorithm         int64
+	SignCount         uint32
+	AAGUID            []byte // รุ่นของ authenticator (ศูนย์ทั้งหมด = ไม่ระบุ)
+	AttestationFormat string
+	Transports        []string
+	UserVerified      bool
+	BackupEligible    bool // passkey ที่ sync ข้ามอุปกรณ์ได้
+	BackedUp          bool
+}
+
+// assertion ที่อ่านจาก response แล้วแต่ยังไม่ได้ตรวจสอบ (ใช้ CredentialID และ UserHandle หา passkey ที่เก็บไว้)
+type Assertion struct {
+	CredentialID      []byte
+	UserHandle        []byte
+	clientDataJSON    []byte
+	authenticatorData []byte
+	signature         []byte
+}
+
+// ผลการตรวจสอบ assertion
+type AssertionResult struct {
+	SignCount    uint32
+	UserVerified bool
+	BackedUp     bool
+}
+
+// RelyingParty สร้าง options และตรวจสอบ response ของ authenticator
+type RelyingParty struct {
+	Config Config
+}
+
+// สร้างอินสแตนซ์ของ RelyingParty
+func NewRelyingParty(cfg Config) *RelyingParty {
+	if cfg.Timeout == 0 {
+		cfg.Timeout = defaultTimeout
+	}
+	if cfg.Attestation == "" {
+		cfg.Attestation = "none"
+	}
+	return &RelyingParty{Config: cfg}
+}
+
+// รูปแบบ JSON ของ PublicKeyCredential (ตาม PublicKeyCredential.toJSON() ข้อมูล binary เป็น base64url)
+type credentialJSON struct {
+	ID       string `json:"id"`
+	RawID    string `json:"rawId"`
+	Type     string `json:"type"`
+	Response struct {
+		ClientDataJSON    string   `json:"clientDataJSON"`
+		AttestationObject string   `json:"attestationObject"`
+		Transports        []string `json:"transports"`
+		AuthenticatorData string   `json:"authenticatorData"`
+		Signature         string   `json:"signature"`
+		UserHandle        string   `json:"userHandle"`
+	} `json:"response"`
+}
+
+type clientData struct {
+	Type        string `json:"type"`
+	Challenge   string `json:"challenge"`
+	Origin      string `json:"origin"`
+	CrossOrigin bool   `json:"crossOrigin"`
+}
+
+type attestationObject struct {
+	Format   string          `cbor:"fmt"`
+	AttStmt  cbor.RawMessage `cbor:"attStmt"`
+	AuthData []byte          `cbor:"authData"`
+}
+
+type authenticatorData struct {
+	RPIDHash     []byte
+	Flags        byte
+	SignCount    uint32
+	AAGUID       []byte
+	CredentialID []byte
+	PublicKey    []byte
+}
+
+// ตรวจสอบ response ของ navigator.credentials.create: clientData, RP ID, flag, attestation statement
+// requireUV = ผู้ใช้ต้องยืนยันตัวตนกับ authenticator (PIN หรือ biometric)
+func (rp *RelyingParty) VerifyRegistration(response []byte, challenge []byte, requireUV bool) (*Credential, error) {
+	var cred credentialJSON
+	if err := json.Unmarshal(response, &cred); err != nil || cred.Type != "public-key" {
+		return nil, ErrInvalidResponse
+	}
+	clientDataJSON, err1 := DecodeBase64URL(cred.Response.ClientDataJSON)
+	rawAttestation, err2 := DecodeBase64URL(cred.Response.AttestationObject)
+	if err1 != nil || err2 != nil {
+		return nil, ErrInvalidResponse
+	}
+	if err := rp.verifyClientData(clientDataJSON, "webauthn.create", challenge); err != nil {
+		return nil, err
+	}
+
+	var att attestationObject
+	if err := cbor.Unmarshal(rawAttestation, &att); err != nil {
+		return nil, ErrInvalidResponse
+	}
+	data, err := parseAuthenticatorData(att.AuthData)
+	if err != nil {
+		return nil, err
+	}
+	if err := rp.verifyAuthenticatorData(data, requireUV); err != nil {
+		return nil, err
+	}
+	if data.Flags&flagAttestedData == 0 || len(data.CredentialID) == 0 {
+		return nil, ErrInvalidResponse
+	}
+	if rawID, err := DecodeBase64URL(cred.RawID); err != nil || !bytes.Equal(rawID, data.CredentialID) {
+		return nil, ErrInvalidResponse
+	}
+	key, err := parseCOSEKey(data.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	if err := verifyAttestation(att.Format, att.AttStmt, att.AuthData, clientDataHash[:], data, key); err != nil {
+		return nil, err
+	}
+	return &Credential{
+		ID:                data.CredentialID,
+		PublicKey:         data.PublicKey,
+		Algorithm:         key.alg,
+		SignCount:         data.SignCount,
+		AAGUID:            data.AAGUID,
+		AttestationFormat: att.Format,
+		Transports:        cred.Response.Transports,
+		UserVerified:      data.Flags&flagUserVerified != 0,
+		BackupEligible:    data.Flags&flagBackupEligible != 0,
+		BackedUp:          data.Flags&flagBackedUp != 0,
+	}, nil
+}
+
+// อ่าน response ของ navigator.credentials.get (ยังไม่ตรวจสอบลายเซ็น)
+func ParseAssertion(response []byte) (*Assertion, error) {
+	var cred credentialJSON
+	if err := json.Unmarshal(response, &cred); err != nil || cred.Type != "public-key" {
+		return nil, ErrInvalidResponse
+	}
+	a := &Assertion{}
+	var errs [5]error
+	a.CredentialID, errs[0] = DecodeBase64URL(cred.RawID)
+	a.clientDataJSON, errs[1] = DecodeBase64URL(cred.Response.ClientDataJSON)
+	a.authenticatorData, errs[2] = DecodeBase64URL(cred.Response.AuthenticatorData)
+	a.signature, errs[3] = DecodeBase64URL(cred.Response.Signature)
+	a.UserHandle, errs[4] = DecodeBase64URL(cred.Response.UserHandle)
+	if errors.Join(errs[:]...) != nil || len(a.CredentialID) == 0 {
+		return nil, ErrInvalidResponse
+	}
+	return a, nil
+}
+
+// ตรวจสอบ assertion กับ passkey ที่เก็บไว้: clientData, RP ID, flag, ลายเซ็น และ sign counter
+func (rp *RelyingParty) VerifyAssertion(a *Assertion, challenge []byte, cred *Credential, requireUV bool) (*AssertionResult, error) {
+	if !bytes.Equal(a.CredentialID, cred.ID) {
+		return nil, ErrInvalidResponse
+	}
+	if err := rp.verifyClientData(a.clientDataJSON, "webauthn.get", challenge); err != nil {
+		return nil, err
+	}
+	data, err := parseAuthenticatorData(a.authenticatorData)
+	if err != nil {
+		return nil, err
+	}
+	if err := rp.verifyAuthenticatorData(data, requireUV); err != nil {
+		return nil, err
+	}
+
+	key, err := parseCOSEKey(cred.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	clientDataHash := sha256.Sum256(a.clientDataJSON)
+	signed := append(append([]byte{}, a.authenticatorData...), clientDataHash[:]...)
+	if err := verifySignature(key.alg, key.public, signed, a.signature); err != nil {
+		return nil, err
+	}
+
+	// authenticator ที่ไม่มี counter ส่ง 0 เสมอ ถ้ามี counter ค่าต้องเพิ่มขึ้นทุกครั้ง
+	if (data.SignCount != 0 || cred.SignCount != 0) && data.SignCount <= cred.SignCount {
+		return nil, ErrSignCount
+	}
+	return &AssertionResult{
+		SignCount:    data.SignCount,
+		UserVerified: data.Flags&flagUserVerified != 0,
+		BackedUp:     data.Flags&flagBackedUp != 0,
+	}, nil
+}
+
+func (rp *RelyingParty) verifyClientData(raw []byte, typ string, challenge []byte) error {
+	var cd clientData
+	if err := json.Unmarshal(raw, &cd); err != nil || cd.Type != typ || cd.CrossOrigin {
+		return ErrInvalidResponse
+	}
+	got, err := DecodeBase64URL(cd.Challenge)
+	if err != nil || len(challenge) == 0 || subtle.ConstantTimeCompare(got, challenge) != 1 {
+		return ErrInvalidResponse
+	}
+	for _, origin := range rp.Config.Origins {
+		if cd.Origin == origin {
+			return nil
+		}
+	}
+	return ErrInvalidResponse
+}
+
+func (rp *RelyingParty) verifyAuthenticatorData(data *authenticatorData, requireUV bool) error {
+	rpIDHash := sha256.Sum256([]byte(rp.Config.RPID))
+	if !bytes.Equal(data.RPIDHash, rpIDHash[:]) || data.Flags&flagUserPresent == 0 {
+		return ErrInvalidResponse
+	}
+	if requireUV && data.Flags&flagUserVerified == 0 {
+		return ErrInvalidResponse
+	}
+	// backup state มีได้เฉพาะ credential ที่ backup ได้
+	if data.Flags&flagBackedUp != 0 && data.Flags&flagBackupEligible == 0 {
+		return ErrInvalidResponse
+	}
+	return nil
+}
+
+// authenticator data: rpIdHash (32) | flags (1) | signCount (4) | attested credential data | extensions
+func parseAuthenticatorData(raw []byte) (*authenticatorData, error) {
+	if len(raw) < 37 {
+		return nil, ErrInvalidResponse
+	}
+	data := &authenticatorData{
+		RPIDHash:  raw[:32],
+		Flags:     raw[32],
+		SignCount: binary.BigEndian.Uint32(raw[33:37]),
+	}
+	rest := raw[37:]
+	if data.Flags&flagAttestedData != 0 {
+		if len(rest) < 18 {
+			return nil, ErrInvalidResponse
+		}
+		data.AAGUID = rest[:16]
+		idLen := int(binary.BigEndian.Uint16(rest[16:18]))
+		if idLen > 1023 || len(rest) < 18+idLen {
+			return nil, ErrInvalidResponse
+		}
+		data.CredentialID = rest[18 : 18+idLen]
+		var key cbor.RawMessage
+		var err error
+		if rest, err = cbor.UnmarshalFirst(rest[18+idLen:], &key); err != nil {
+			return nil, ErrInvalidResponse
+		}
+		data.PublicKey = key
+	}
+	if data.Flags&flagExtensions != 0 {
+		var extensions cbor.RawMessage
+		var err error
+		if rest, err = cbor.UnmarshalFirst(rest, &extensions); err != nil {
+			return nil, ErrInvalidResponse
+		}
+	}
+	if len(rest) != 0 {
+		return nil, ErrInvalidResponse
+	}
+	return data, nil
+}
+
+// รับ base64url ทั้งแบบมีและไม่มี padding
+func DecodeBase64URL(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
+
+// เข้ารหัส binary เป็น base64url แบบไม่มี padding ตามที่ WebAuthn ใช้
+func EncodeBase64URL(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -0,0 +1,228 @@
+package webauthn_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"auth-microservice/internal/webauthn"
+	"auth-microservice/internal/webauthn/webauthntest"
+)
+
+const testOrigin = "https://login.example.com"
+
+func newRelyingParty() *webauthn.RelyingParty {
+	return webauthn.NewRelyingParty(webauthn.Config{RPID: "example.com", RPName: "Example", Origins: []string{testOrigin}})
+}
+
+func newChallenge(t *testing.T) []byte {
+	t.Helper()
+	challenge := make([]byte, 32)
+	if _, err := rand.Read(challenge); err != nil {
+		t.Fatal(err)
+	}
+	return challenge
+}
+
+// ลงทะเบียน passkey ของ authenticator กับ rp แล้วคืน credential ทั้งสองฝั่ง
+func register(t *testing.T, rp *webauthn.RelyingParty, a *webauthntest.Authenticator) (*webauthn.Credential, *webauthntest.Credential) {
+	t.Helper()
+	challenge := newChallenge(t)
+	options, err := rp.CreationOptions(webauthn.User{ID: []byte("user-1"), Name: "alice@example.com", DisplayName: "Alice"}, challenge, nil)
+	if err != nil {
+		t.Fatalf("CreationOptions: %v", err)
+	}
+	response, local := a.Create(t, string(options))
+	cred, err := rp.VerifyRegistration([]byte(response), challenge, false)
+	if err != nil {
+		t.Fatalf("VerifyRegistration: %v", err)
+	}
+	return cred, local
+}
+
+// ตอบ assertion ด้วย passkey แล้วตรวจสอบกับ credential ที่ลงทะเบียนไว้
+func assert(t *testing.T, rp *webauthn.RelyingParty, a *webauthntest.Authenticator, cred *webauthn.Credential, local *webauthntest.Credential, requireUV bool) (*webauthn.AssertionResult, error) {
+	t.Helper()
+	challenge := newChallenge(t)
+	options, err := rp.RequestOptions(challenge, []webauthn.Descriptor{{ID: cred.ID}}, webauthn.VerificationRequired)
+	if err != nil {
+		t.Fatalf("RequestOptions: %v", err)
+	}
+	assertion, err := webauthn.ParseAssertion([]byte(a.Get(t, string(options), local)))
+	if err != nil {
+		t.Fatalf("ParseAssertion: %v", err)
+	}
+	return rp.VerifyAssertion(assertion, challenge, cred, requireUV)
+}
+
+func TestRegistrationAndAssertion(t *testing.T) {
+	rp := newRelyingParty()
+	for _, format := range []string{"none", "packed"} {
+		a := webauthntest.New(testOrigin)
+		a.Attestation = format
+		a.BackupEligible = true
+		a.AAGUID = bytes.Repeat([]byte{0xad}, 16)
+
+		cred, local := register(t, rp, a)
+		if !bytes.Equal(cred.ID, local.ID) || cred.Algorithm != webauthn.AlgES256 || cred.AttestationFormat != format ||
+			!bytes.Equal(cred.AAGUID, a.AAGUID) || !cred.UserVerified || !cred.BackupEligible || !cred.BackedUp ||
+			len(cred.Transports) != 1 || cred.Transports[0] != "internal" {
+			t.Fatalf("VerifyRegistration (%s) = %+v", format, cred)
+		}
+
+		result, err := assert(t, rp, a, cred, local, true)
+		if err != nil {
+			t.Fatalf("VerifyAssertion (%s): %v", format, err)
+		}
+		if result.SignCount != 1 || !result.UserVerified || !result.BackedUp {
+			t.Fatalf("VerifyAssertion (%s) = %+v", format, result)
+		}
+	}
+}
+
+func TestVerifyRegistrationRejects(t *testing.T) {
+	tests := []struct {
+		name      string
+		modify    func(a *webauthntest.Authenticator)
+		response  func(response string) string
+		challenge []byte // nil = challenge ใน options
+		requireUV bool
+	}{
+		{name: "other origin", modify: func(a *webauthntest.Authenticator) { a.Origin = "https://evil.example.com" }},
+		{name: "other RP ID", modify: func(a *webauthntest.Authenticator) { a.RPID = "evil.example.com" }},
+		{name: "other challenge", challenge: []byte("another challenge")},
+		{name: "user not verified", modify: func(a *webauthntest.Authenticator) { a.UserVerified = false }, requireUV: true},
+		{name: "assertion instead of attestation", response: func(response string) string {
+			return strings.Replace(response, `"type":"public-key"`, `"type":"password"`, 1)
+		}},
+		{name: "rawId of another credential", response: func(response string) string {
+			var cred map[string]interface{}
+			json.Unmarshal([]byte(response), &cred)
+			cred["rawId"] = webauthn.EncodeBase64URL([]byte("other"))
+			raw, _ := json.Marshal(cred)
+			return string(raw)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rp := newRelyingParty()
+			a := webauthntest.New(testOrigin)
+			if tt.modify != nil {
+				tt.modify(a)
+			}
+			challenge := newChallenge(t)
+			options, err := rp.CreationOptions(webauthn.User{ID: []byte("user-1"), Name: "alice@example.com"}, challenge, nil)
+			if err != nil {
+				t.Fatalf("CreationOptions: %v", err)
+			}
+			response, _ := a.Create(t, string(options))
+			if tt.response != nil {
+				response = tt.response(response)
+			}
+			if tt.challenge != nil {
+				challenge = tt.challenge
+			}
+			if cred, err := rp.VerifyRegistration([]byte(response), challenge, tt.requireUV); !errors.Is(err, webauthn.ErrInvalidResponse) {
+				t.Fatalf("VerifyRegistration = %+v, %v, want ErrInvalidResponse", cred, err)
+			}
+		})
+	}
+}
+
+func TestVerifyAssertionRejects(t *testing.T) {
+	rp := newRelyingParty()
+	a := webauthntest.New(testOrigin)
+	cred, local := register(t, rp, a)
+	if _, err := assert(t, rp, a, cred, local, true); err != nil {
+		t.Fatalf("VerifyAssertion: %v", err)
+	}
+	cred.SignCount = local.SignCount
+
+	// authenticator ที่ถูกคัดลอกมีค่า counter เก่า
+	clone := *local
+	clone.SignCount = 0
+	if _, err := assert(t, rp, a, cred, &clone, false); !errors.Is(err, webauthn.ErrSignCount) {
+		t.Fatalf("VerifyAssertion with a stale sign counter = %v, want ErrSignCount", err)
+	}
+
+	// ลายเซ็นด้วย key อื่นที่อ้าง credential ID เดียวกัน
+	_, otherLocal := register(t, rp, webauthntest.New(testOrigin))
+	otherLocal.ID = cred.ID
+	if _, err := assert(t, rp, a, &webauthn.Credential{ID: cred.ID, PublicKey: cred.PublicKey, SignCount: cred.SignCount}, otherLocal, false); !errors.Is(err, webauthn.ErrInvalidResponse) {
+		t.Fatalf("VerifyAssertion signed by another key = %v, want ErrInvalidResponse", err)
+	}
+
+	for _, tt := range []struct {
+		name      string
+		modify    func(a *webauthntest.Authenticator)
+		requireUV bool
+	}{
+		{name: "other origin", modify: func(a *webauthntest.Authenticator) { a.Origin = "https://evil.example.com" }},
+		{name: "other RP ID", modify: func(a *webauthntest.Authenticator) { a.RPID = "evil.example.com" }},
+		{name: "user not verified", modify: func(a *webauthntest.Authenticator) { a.UserVerified = false }, requireUV: true},
+	} {
+		b := *a
+		tt.modify(&b)
+		if _, err := assert(t, rp, &b, cred, local, tt.requireUV); !errors.Is(err, webauthn.ErrInvalidResponse) {
+			t.Errorf("VerifyAssertion (%s) = %v, want ErrInvalidResponse", tt.name, err)
+		}
+	}
+
+	// ไม่ต้องยืนยันตัวตนเมื่อใช้เป็นปัจจัยที่สอง
+	b := *a
+	b.UserVerified = false
+	result, err := assert(t, rp, &b, cred, local, false)
+	if err != nil || result.UserVerified {
+		t.Fatalf("VerifyAssertion without user verification = %+v, %v", result, err)
+	}
+}
+
+func TestVerifyAssertionRejectsOtherChallenge(t *testing.T) {
+	rp := newRelyingParty()
+	a := webauthntest.New(testOrigin)
+	cred, local := register(t, rp, a)
+
+	options, _ := rp.RequestOptions(newChallenge(t), nil, webauthn.VerificationRequired)
+	assertion, err := webauthn.ParseAssertion([]byte(a.Get(t, string(options), local)))
+	if err != nil {
+		t.Fatalf("ParseAssertion: %v", err)
+	}
+	if _, err := rp.VerifyAssertion(assertion, newChallenge(t), cred, true); !errors.Is(err, webauthn.ErrInvalidResponse) {
+		t.Fatalf("VerifyAssertion with another challenge = %v, want ErrInvalidResponse", err)
+	}
+	if _, err := webauthn.ParseAssertion([]byte(`{"type":"public-key","rawId":""}`)); !errors.Is(err, webauthn.ErrInvalidResponse) {
+		t.Fatalf("ParseAssertion without a credential ID = %v, want ErrInvalidResponse", err)
+	}
+}
+
+func TestCreationOptions(t *testing.T) {
+	rp := newRelyingParty()
+	challenge := newChallenge(t)
+	options, err := rp.CreationOptions(webauthn.User{ID: []byte("user-1"), Name: "alice@example.com", DisplayName: "Alice"}, challenge,
+		[]webauthn.Descriptor{{ID: []byte("existing"), Transports: []string{"usb"}}})
+	if err != nil {
+		t.Fatalf("CreationOptions: %v", err)
+	}
+	var opts struct {
+		RP                 struct{ ID, Name string }
+		User               struct{ ID, Name, DisplayName string }
+		Challenge          string
+		PubKeyCredParams   []struct{ Alg int64 }
+		ExcludeCredentials []struct {
+			ID         string
+			Transports []string
+		}
+		Attestation string
+	}
+	if err := json.Unmarshal(options, &opts); err != nil {
+		t.Fatal(err)
+	}
+	if opts.RP.ID != "example.com" || opts.User.ID != webauthn.EncodeBase64URL([]byte("user-1")) || opts.User.DisplayName != "Alice" ||
+		opts.Challenge != webauthn.EncodeBase64URL(challenge) || len(opts.PubKeyCredParams) != 3 || opts.PubKeyCredParams[0].Alg != webauthn.AlgES256 ||
+		len(opts.ExcludeCredentials) != 1 || opts.ExcludeCredentials[0].ID != webauthn.EncodeBase64URL([]byte("existing")) || opts.Attestation != "none" {
+		t.Fatalf("CreationOptions = %s", options)
+	}
+}
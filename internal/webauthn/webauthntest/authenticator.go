@@ -0,0 +1,232 @@
+// Package webauthntest มี authenticator แบบ software สำหรับทดสอบการลงทะเบียนและเข้าสู่ระบบด้วย passkey
+// โดยไม่ต้องใช้ browser หรืออุปกรณ์จริง
+package webauthntest
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+
+	"auth-microservice/internal/webauthn"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// flag ใน authenticator data
+const (
+	flagUserPresent    = 0x01
+	flagUserVerified   = 0x04
+	flagBackupEligible = 0x08
+	flagBackedUp       = 0x10
+	flagAttestedData   = 0x40
+)
+
+// Authenticator สร้าง passkey แบบ ES256 และตอบ options ของ relying party แบบเดียวกับ browser
+// (response อยู่ในรูปแบบ PublicKeyCredential.toJSON()) แก้ field เพื่อจำลอง response ที่ไม่ถูกต้องได้
+type Authenticator struct {
+	Origin         string // origin ของหน้าเว็บที่เรียก WebAuthn
+	RPID           string // ค่าว่าง = ใช้ RP ID จาก options (ตั้งค่าอื่นเพื่อจำลองเว็บไซต์ปลอม)
+	AAGUID         []byte // รุ่นของ authenticator (nil = ศูนย์ทั้งหมด)
+	Attestation    string // "none" (ค่าว่าง) หรือ "packed" แบบ self attestation
+	UserVerified   bool   // ผู้ใช้ยืนยันตัวตนด้วย PIN หรือ biometric
+	BackupEligible bool   // passkey ที่ sync ข้ามอุปกรณ์ได้ (ตั้งแล้วถือว่า backup แล้ว)
+	Transports     []string
+}
+
+// passkey ที่ authenticator เก็บไว้
+type Credential struct {
+	ID         []byte
+	Key        *ecdsa.PrivateKey
+	RPID       string
+	UserHandle []byte
+	SignCount  uint32
+}
+
+// สร้าง authenticator ที่ผู้ใช้ยืนยันตัวตนได้ สำหรับหน้าเว็บที่ origin
+func New(origin string) *Authenticator {
+	return &Authenticator{Origin: origin, UserVerified: true, Transports: []string{"internal"}}
+}
+
+// ตอบ options ของ navigator.credentials.create ด้วย passkey ใหม่ คืน response และ passkey ที่สร้าง
+func (a *Authenticator) Create(t *testing.T, options string) (string, *Credential) {
+	t.Helper()
+	var opts struct {
+		RP struct {
+			ID string `json:"id"`
+		} `json:"rp"`
+		User struct {
+			ID string `json:"id"`
+		} `json:"user"`
+		Challenge        string `json:"challenge"`
+		PubKeyCredParams []struct {
+			Alg int64 `json:"alg"`
+		} `json:"pubKeyCredParams"`
+	}
+	if err := json.Unmarshal([]byte(options), &opts); err != nil {
+		t.Fatalf("creation options: %v\n%s", err, options)
+	}
+	supported := false
+	for _, p := range opts.PubKeyCredParams {
+		supported = supported || p.Alg == webauthn.AlgES256
+	}
+	if !supported {
+		t.Fatalf("creation options do not allow ES256: %s", options)
+	}
+	userHandle, err := webauthn.DecodeBase64URL(opts.User.ID)
+	if err != nil {
+		t.Fatalf("user.id: %v", err)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate credential key: %v", err)
+	}
+	cred := &Credential{ID: make([]byte, 32), Key: key, RPID: opts.RP.ID, UserHandle: userHandle}
+	if _, err := rand.Read(cred.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	coseKey, err := cbor.Marshal(map[int64]interface{}{
+		1:  2,  // kty: EC2
+		3:  -7, // alg: ES256
+		-1: 1,  // crv: P-256
+		-2: key.X.FillBytes(make([]byte, 32)),
+		-3: key.Y.FillBytes(make([]byte, 32)),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var attested bytes.Buffer
+	aaguid := a.AAGUID
+	if aaguid == nil {
+		aaguid = make([]byte, 16)
+	}
+	attested.Write(aaguid)
+	binary.Write(&attested, binary.BigEndian, uint16(len(cred.ID)))
+	attested.Write(cred.ID)
+	attested.Write(coseKey)
+	authData := a.authenticatorData(cred, flagAttestedData, attested.Bytes())
+	clientDataJSON := a.clientData(t, "webauthn.create", opts.Challenge)
+
+	format := a.Attestation
+	attStmt := map[string]interface{}{}
+	switch format {
+	case "", "none":
+		format = "none"
+	case "packed":
+		attStmt["alg"] = webauthn.AlgES256
+		attStmt["sig"] = sign(t, key, authData, clientDataJSON)
+	default:
+		t.Fatalf("unsupported attestation %q", a.Attestation)
+	}
+	attestationObject, err := cbor.Marshal(map[string]interface{}{"fmt": format, "attStmt": attStmt, "authData": authData})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	response := map[string]interface{}{
+		"clientDataJSON":    webauthn.EncodeBase64URL(clientDataJSON),
+		"attestationObject": webauthn.EncodeBase64URL(attestationObject),
+		"transports":        a.Transports,
+	}
+	return credentialJSON(t, cred.ID, response), cred
+}
+
+// ตอบ options ของ navigator.credentials.get ด้วย passkey ที่ระบุ (sign counter เพิ่มขึ้นทุกครั้ง)
+// ถ้า options ระบุ allowCredentials ไว้ passkey ต้องอยู่ในรายการ
+func (a *Authenticator) Get(t *testing.T, options string, cred *Credential) string {
+	t.Helper()
+	var opts struct {
+		Challenge        string `json:"challenge"`
+		RPID             string `json:"rpId"`
+		AllowCredentials []struct {
+			ID string `json:"id"`
+		} `json:"allowCredentials"`
+	}
+	if err := json.Unmarshal([]byte(options), &opts); err != nil {
+		t.Fatalf("request options: %v\n%s", err, options)
+	}
+	if opts.RPID != cred.RPID {
+		t.Fatalf("request options for RP %q, the passkey belongs to %q", opts.RPID, cred.RPID)
+	}
+	allowed := len(opts.AllowCredentials) == 0
+	for _, c := range opts.AllowCredentials {
+		allowed = allowed || c.ID == webauthn.EncodeBase64URL(cred.ID)
+	}
+	if !allowed {
+		t.Fatalf("request options do not allow the passkey: %s", options)
+	}
+
+	cred.SignCount++
+	authData := a.authenticatorData(cred, 0, nil)
+	clientDataJSON := a.clientData(t, "webauthn.get", opts.Challenge)
+	response := map[string]interface{}{
+		"clientDataJSON":    webauthn.EncodeBase64URL(clientDataJSON),
+		"authenticatorData": webauthn.EncodeBase64URL(authData),
+		"signature":         webauthn.EncodeBase64URL(sign(t, cred.Key, authData, clientDataJSON)),
+		"userHandle":        webauthn.EncodeBase64URL(cred.UserHandle),
+	}
+	return credentialJSON(t, cred.ID, response)
+}
+
+// authenticator data: rpIdHash | flags | signCount | attested credential data
+func (a *Authenticator) authenticatorData(cred *Credential, flags byte, attested []byte) []byte {
+	rpID := a.RPID
+	if rpID == "" {
+		rpID = cred.RPID
+	}
+	rpIDHash := sha256.Sum256([]byte(rpID))
+	flags |= flagUserPresent
+	if a.UserVerified {
+		flags |= flagUserVerified
+	}
+	if a.BackupEligible {
+		flags |= flagBackupEligible | flagBackedUp
+	}
+	var data bytes.Buffer
+	data.Write(rpIDHash[:])
+	data.WriteByte(flags)
+	binary.Write(&data, binary.BigEndian, cred.SignCount)
+	data.Write(attested)
+	return data.Bytes()
+}
+
+func (a *Authenticator) clientData(t *testing.T, typ string, challenge string) []byte {
+	t.Helper()
+	raw, err := json.Marshal(map[string]interface{}{"type": typ, "challenge": challenge, "origin": a.Origin, "crossOrigin": false})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return raw
+}
+
+// ลายเซ็น ES256 (ASN.1 DER) ของ authenticatorData | SHA-256(clientDataJSON)
+func sign(t *testing.T, key *ecdsa.PrivateKey, authData []byte, clientDataJSON []byte) []byte {
+	t.Helper()
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	digest := sha256.Sum256(append(append([]byte{}, authData...), clientDataHash[:]...))
+	sig, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	return sig
+}
+
+func credentialJSON(t *testing.T, id []byte, response map[string]interface{}) string {
+	t.Helper()
+	raw, err := json.Marshal(map[string]interface{}{
+		"id":       webauthn.EncodeBase64URL(id),
+		"rawId":    webauthn.EncodeBase64URL(id),
+		"type":     "public-key",
+		"response": response,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(raw)
+}
@@ -0,0 +1,173 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/store"
+)
+
+// header ที่ส่งไปพร้อม payload
+const (
+	HeaderEventID   = "X-Webhook-Event-Id" // ID ของเหตุการณ์ (ใช้กันการประมวลผลซ้ำ)
+	HeaderEvent     = "X-Webhook-Event"    // ชื่อเหตุการณ์
+	HeaderDelivery  = "X-Webhook-Delivery" // ID ของการส่ง
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// ขนาดสูงสุดของ error ที่เก็บไว้ในประวัติการส่ง
+const maxErrorLength = 500
+
+// ลายเซ็นของ payload ในรูปแบบ "t=<unix>,v1=<hex>" โดย v1 = HMAC-SHA256(secret, "<unix>.<body>")
+// ปลายทางควรตรวจลายเซ็นและปฏิเสธ t ที่เก่าเกินไปเพื่อกัน replay
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	t := strconv.FormatInt(timestamp.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(t + "."))
+	mac.Write(body)
+	return "t=" + t + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// รันการส่งเหตุการณ์ในคิวเป็นรอบ ๆ จนกว่า ctx จะถูกยกเลิก
+func (d *Dispatcher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := d.DeliverDue(ctx); err != nil {
+			log.Printf("Webhook delivery failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ส่งทุกรายการในคิวที่ถึงเวลาแล้ว (ทีละชุด ชุดละไม่เกิน BatchSize รายการพร้อมกัน)
+func (d *Dispatcher) DeliverDue(ctx context.Context) error {
+	for ctx.Err() == nil {
+		// lease ยาวกว่าเวลาส่งหนึ่งชุด ถ้า instance ตายระหว่างส่ง รายการจะถูกส่งใหม่หลัง lease หมด
+		now := time.Now()
+		claimed, err := d.Store.ClaimDueDeliveries(ctx, now, now.Add(2*d.Client.Timeout+time.Minute), d.BatchSize)
+		if err != nil {
+			return err
+		}
+		if len(claimed) == 0 {
+			return nil
+		}
+
+		var wg sync.WaitGroup
+		for i := range claimed {
+			wg.Add(1)
+			go func(delivery *models.WebhookDelivery) {
+				defer wg.Done()
+				d.deliver(ctx, delivery)
+			}(&claimed[i])
+		}
+		wg.Wait()
+
+		if len(claimed) < d.BatchSize {
+			return nil
+		}
+	}
+	return ctx.Err()
+}
+
+// ส่งหนึ่งรายการแล้วบันทึกผล: 2xx = สำเร็จ, อื่น ๆ = retry แบบ exponential backoff หรือย้ายไป dead letter
+func (d *Dispatcher) deliver(ctx context.Context, delivery *models.WebhookDelivery) {
+	now := time.Now()
+	delivery.Attempts++
+
+	w, err := d.Store.GetWebhook(ctx, delivery.TenantID, delivery.WebhookID.Hex())
+	if errors.Is(err, store.ErrNotFound) {
+		// webhook ถูกลบระหว่างรอส่ง ไม่ต้อง retry
+		delivery.Status = models.DeliveryDead
+		delivery.LastError = "webhook not found"
+		d.save(ctx, delivery)
+		return
+	}
+	if err == nil {
+		delivery.ResponseStatus, err = d.post(ctx, w, delivery, now)
+	}
+
+	if err == nil {
+		delivery.Status = models.DeliverySucceeded
+		delivery.LastError = ""
+		delivery.DeliveredAt = &now
+	} else {
+		delivery.LastError = truncate(err.Error(), maxErrorLength)
+		if delivery.Attempts >= d.MaxAttempts {
+			delivery.Status = models.DeliveryDead
+		} else {
+			delivery.NextAttemptAt = now.Add(d.backoff(delivery.Attempts))
+		}
+	}
+	d.save(ctx, delivery)
+}
+
+// POST payload ไปยัง webhook คืน HTTP status ที่ได้รับ (0 ถ้าไม่ได้รับคำตอบ)
+func (d *Dispatcher) post(ctx context.Context, w *models.Webhook, delivery *models.WebhookDelivery, now time.Time) (int, error) {
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "auth-microservice-webhook/1.0")
+	req.Header.Set(HeaderEventID, delivery.EventID)
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderDelivery, delivery.ID.Hex())
+	req.Header.Set(HeaderSignature, Sign(w.Secret, now, body))
+
+	resp, err := d.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+func (d *Dispatcher) save(ctx context.Context, delivery *models.WebhookDelivery) {
+	if err := d.Store.SaveDeliveryAttempt(ctx, delivery); err != nil {
+		log.Printf("Could not save webhook delivery %s: %v", delivery.ID.Hex(), err)
+	}
+}
+
+// ระยะรอก่อนส่งครั้งถัดไปหลังส่งไม่สำเร็จครั้งที่ attempts (base, 2*base, 4*base, ... ไม่เกิน MaxBackoff)
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	wait := d.BaseBackoff
+	for i := 1; i < attempts && wait < d.MaxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > d.MaxBackoff {
+		wait = d.MaxBackoff
+	}
+	return wait
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
@@ -0,0 +1,140 @@
+// Package webhook ส่งเหตุการณ์ของผู้ใช้ (สมัคร เข้าสู่ระบบ แก้ไข ลบ ฯลฯ) ไปยัง webhook ของแต่ละ tenant
+// เหตุการณ์ถูกเก็บเป็นคิวในฐานข้อมูลก่อนส่ง จึงไม่หายเมื่อปลายทางล่มหรือ service restart
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	models "auth-microservice/internal/model"
+	"auth-microservice/internal/netguard"
+	"auth-microservice/internal/store"
+)
+
+// ค่าเริ่มต้นของ Dispatcher
+const (
+	DefaultMaxAttempts = 8                // ส่งไม่สำเร็จครบจำนวนนี้แล้วย้ายไป dead letter
+	DefaultBaseBackoff = 30 * time.Second // ระยะรอก่อน retry ครั้งแรก (เพิ่มเป็นสองเท่าทุกครั้ง)
+	DefaultMaxBackoff  = time.Hour
+	DefaultTimeout     = 10 * time.Second // เวลารอคำตอบจากปลายทางต่อครั้ง
+	DefaultBatchSize   = 20
+)
+
+// Dispatcher นำเหตุการณ์จาก audit log เข้าคิวของ webhook ที่สนใจ และส่งรายการในคิวด้วย HTTP POST
+type Dispatcher struct {
+	Store       store.WebhookStore
+	Client      *http.Client
+	MaxAttempts int
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	BatchSize   int
+}
+
+// ErrForbiddenAddress คืนเมื่อ host ของ webhook resolve ได้เป็นที่อยู่ภายใน (loopback, private, link-local ฯลฯ)
+var ErrForbiddenAddress = netguard.ErrForbiddenAddress
+
+// สร้างอินสแตนซ์ของ Dispatcher ด้วยค่าเริ่มต้น (ไม่ตาม redirect เพื่อไม่ให้ส่งไปยัง URL ที่ไม่ได้ลงทะเบียน)
+// allowLocal = false จะตรวจ IP ทุกครั้งที่เชื่อมต่อ จึงส่งไปยังที่อยู่ภายในไม่ได้แม้ DNS จะเปลี่ยนหลังลงทะเบียน
+func NewDispatcher(webhooks store.WebhookStore, allowLocal bool) *Dispatcher {
+	client := netguard.NewClient(DefaultTimeout, allowLocal)
+	client.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	return &Dispatcher{
+		Store:       webhooks,
+		Client:      client,
+		MaxAttempts: DefaultMaxAttempts,
+		BaseBackoff: DefaultBaseBackoff,
+		MaxBackoff:  DefaultMaxBackoff,
+		BatchSize:   DefaultBatchSize,
+	}
+}
+
+// Event คือ body ที่ส่งไปยัง webhook
+type Event struct {
+	ID        string    `json:"id"`   // ID ของเหตุการณ์ (เหมือนเดิมทุกครั้งที่ส่งซ้ำ ใช้กันการประมวลผลซ้ำ)
+	Type      string    `json:"type"` // เช่น "user.registered"
+	TenantID  string    `json:"tenantId"`
+	CreatedAt time.Time `json:"createdAt"`
+	Data      EventData `json:"data"`
+}
+
+// ข้อมูลของเหตุการณ์ (ไม่มีรหัสผ่านหรือ token)
+type EventData struct {
+	UserID     string                 `json:"userId,omitempty"`
+	Email      string                 `json:"email,omitempty"`
+	ActorEmail string                 `json:"actorEmail,omitempty"`
+	Details    map[string]interface{} `json:"details,omitempty"`
+}
+
+// Publish นำเหตุการณ์เข้าคิวของทุก webhook ใน tenant ที่สนใจเหตุการณ์นี้ (ใช้เป็น audit.Sink)
+// ถ้านำเข้าคิวไม่สำเร็จจะแค่ log ไว้ ไม่ทำให้ request ล้มเหลว
+func (d *Dispatcher) Publish(ctx context.Context, ev models.AuditEvent) {
+	webhooks, err := d.Store.ListWebhooks(ctx, ev.TenantID)
+	if err != nil {
+		log.Printf("Could not load webhooks of tenant %s: %v", ev.TenantID, err)
+		return
+	}
+	var matched []models.Webhook
+	for _, w := range webhooks {
+		if MatchEvent(w.Events, ev.Action) {
+			matched = append(matched, w)
+		}
+	}
+	if len(matched) == 0 {
+		return
+	}
+
+	payload, err := json.Marshal(Event{
+		ID:        ev.ID.Hex(),
+		Type:      ev.Action,
+		TenantID:  ev.TenantID,
+		CreatedAt: ev.CreatedAt.UTC(),
+		Data: EventData{
+			UserID:     ev.SubjectID,
+			Email:      ev.SubjectEmail,
+			ActorEmail: ev.ActorEmail,
+			Details:    ev.Details,
+		},
+	})
+	if err != nil {
+		log.Printf("Could not encode webhook event %s: %v", ev.Action, err)
+		return
+	}
+
+	now := time.Now()
+	deliveries := make([]*models.WebhookDelivery, 0, len(matched))
+	for _, w := range matched {
+		deliveries = append(deliveries, &models.WebhookDelivery{
+			TenantID:      ev.TenantID,
+			WebhookID:     w.ID,
+			EventID:       ev.ID.Hex(),
+			EventType:     ev.Action,
+			Payload:       string(payload),
+			Status:        models.DeliveryPending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+		})
+	}
+	if err := d.Store.EnqueueDeliveries(ctx, deliveries); err != nil {
+		log.Printf("Could not enqueue webhook event %s: %v", ev.Action, err)
+	}
+}
+
+// ตรวจสอบว่าเหตุการณ์ตรงกับรายการที่ webhook สนใจหรือไม่
+// "*" = ทุกเหตุการณ์, "user.*" = ทุกเหตุการณ์ที่ขึ้นต้นด้วย "user."
+func MatchEvent(patterns []string, eventType string) bool {
+	for _, p := range patterns {
+		switch {
+		case p == "*" || p == eventType:
+			return true
+		case strings.HasSuffix(p, ".*") && strings.HasPrefix(eventType, strings.TrimSuffix(p, "*")):
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+	"time"
+)
+
+func TestMatchEvent(t *testing.T) {
+	tests := []struct {
+		patterns []string
+		event    string
+		want     bool
+	}{
+		{[]string{"*"}, "user.login", true},
+		{[]string{"user.login"}, "user.login", true},
+		{[]string{"user.*"}, "user.email_verified", true},
+		{[]string{"user.*"}, "webhook.created", false},
+		{[]string{"user"}, "user.login", false},
+		{[]string{"user.login", "user.deleted"}, "user.registered", false},
+		{nil, "user.login", false},
+	}
+	for _, tt := range tests {
+		if got := MatchEvent(tt.patterns, tt.event); got != tt.want {
+			t.Errorf("MatchEvent(%v, %q) = %v, want %v", tt.patterns, tt.event, got, tt.want)
+		}
+	}
+}
+
+func TestSign(t *testing.T) {
+	at := time.Unix(1700000000, 0)
+	body := []byte(`{"id":"1"}`)
+	mac := hmac.New(sha256.New, []byte("whsec_test"))
+	mac.Write([]byte("1700000000." + string(body)))
+	if got, want := Sign("whsec_test", at, body), "t=1700000000,v1="+hex.EncodeToString(mac.Sum(nil)); got != want {
+		t.Fatalf("Sign = %s, want %s", got, want)
+	}
+	if Sign("whsec_other", at, body) == Sign("whsec_test", at, body) {
+		t.Fatal("signatures with different secrets are equal")
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	d := &Dispatcher{BaseBackoff: 30 * time.Second, MaxBackoff: 5 * time.Minute}
+	want := []time.Duration{30 * time.Second, time.Minute, 2 * time.Minute, 4 * time.Minute, 5 * time.Minute, 5 * time.Minute}
+	for i, w := range want {
+		if got := d.backoff(i + 1); got != w {
+			t.Errorf("backoff(%d) = %v, want %v", i+1, got, w)
+		}
+	}
+}
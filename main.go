@@ -2,40 +2,31 @@ package main
 
 import (
 	"log"
+	"os"
 
 	"auth-microservice/internal/server"
 )
 
 func main() {
+	// ===== คำสั่ง migration: go run main.go migrate <up|down [steps]|status> =====
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := server.RunMigrations(os.Args[2:]); err != nil {
+			log.Fatalf("Migration failed: %v", err)
+		}
+		return
+	}
+
+	// ===== สร้าง admin ของระบบคนแรก: go run main.go create-admin -email e -username u -password p =====
+	if len(os.Args) > 1 && os.Args[1] == "create-admin" {
+		if err := server.RunCreateAdmin(os.Args[2:]); err != nil {
+			log.Fatalf("Create admin failed: %v", err)
+		}
+		return
+	}
+
 	// =================เริ่มการทำงาน=================
 	log.Println("Starting Auth Microservice gRPC server...")
 	if err := server.RunGRPCServer(); err != nil {
 		log.Fatalf("Failed to start gRPC server: %v", err)
 	}
 }
-
-// func main() {
-// 	log.Println("Starting Auth Microservice gRPC server...")
-
-// 	authService, err := server.RunGRPCServer()
-// 	if err != nil {
-// 		log.Fatalf("Failed to start gRPC server: %v", err)
-// 	}
-
-// 	// รอ server ตั้งต้นเสร็จ
-// 	time.Sleep(2 * time.Second)
-
-// 	// เรียก Bulk Register 100,000 users
-// 	go func() {
-// 		log.Println("Starting bulk registration...")
-// 		err := authService.RegisterBulkUsers(context.Background(), 100000)
-// 		if err != nil {
-// 			log.Printf("Bulk registration failed: %v", err)
-// 		} else {
-// 			log.Println("Bulk registration completed successfully.")
-// 		}
-// 	}()
-
-// 	// กัน main ออกจากโปรแกรม
-// 	select {}
-// }